@@ -123,6 +123,7 @@ For Terminology refer to the [Scenario Description](https://www.project-piper.io
 			handler := func() {
 				commonPipelineEnvironment.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
 				stepTelemetryData.PiperCommitHash = GitCommit
@@ -148,7 +149,12 @@ For Terminology refer to the [Scenario Description](https://www.project-piper.io
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
-			abapAddonAssemblyKitCheckPV(stepConfig, &stepTelemetryData, &commonPipelineEnvironment)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+				commonPipelineEnvironment = abapAddonAssemblyKitCheckPVCommonPipelineEnvironment{}
+			}, func() {
+				abapAddonAssemblyKitCheckPV(stepConfig, &stepTelemetryData, &commonPipelineEnvironment)
+			})
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
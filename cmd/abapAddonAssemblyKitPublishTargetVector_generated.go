@@ -93,6 +93,7 @@ For Terminology refer to the [Scenario Description](https://www.project-piper.io
 			stepTelemetryData.ErrorCode = "1"
 			handler := func() {
 				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
 				stepTelemetryData.PiperCommitHash = GitCommit
@@ -118,7 +119,11 @@ For Terminology refer to the [Scenario Description](https://www.project-piper.io
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
-			abapAddonAssemblyKitPublishTargetVector(stepConfig, &stepTelemetryData)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+			}, func() {
+				abapAddonAssemblyKitPublishTargetVector(stepConfig, &stepTelemetryData)
+			})
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
@@ -15,6 +15,7 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/abaputils"
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
@@ -239,6 +240,12 @@ func logAndPersistAndEvaluateATCResults(utils piperutils.FileUtils, body []byte,
 				}
 			}
 		}
+		if sarifPath, err := writeATCSarif(utils, body, atcResultFileName); err != nil {
+			log.Entry().WithError(err).Warning("failed to write ATC results as SARIF")
+		} else {
+			log.Entry().Infof("Writing %s file was successful", sarifPath)
+			reports = append(reports, piperutils.Path{Target: sarifPath, Name: "ATC Results SARIF file"})
+		}
 		if generateHTML {
 			htmlString := generateHTMLDocument(parsedXML)
 			htmlStringByte := []byte(htmlString)
@@ -256,6 +263,17 @@ func logAndPersistAndEvaluateATCResults(utils piperutils.FileUtils, body []byte,
 	}
 	return nil, failStep
 }
+
+// writeATCSarif converts the ATC results in checkstyle format into a SARIF report next to the results file
+func writeATCSarif(utils piperutils.FileUtils, body []byte, atcResultFileName string) (string, error) {
+	sarif, err := format.CheckstyleToSarif(body, "ABAP ATC")
+	if err != nil {
+		return "", err
+	}
+	sarifPath := format.SarifReportPath(atcResultFileName)
+	return sarifPath, format.WriteSarifFile(sarif, sarifPath, utils)
+}
+
 func checkStepFailing(severity string, failOnSeverityLevel string) bool {
 	switch failOnSeverityLevel {
 	case "error":
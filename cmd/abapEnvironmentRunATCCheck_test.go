@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/SAP/jenkins-library/pkg/abaputils"
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/stretchr/testify/assert"
 )
@@ -244,9 +245,15 @@ func TestParseATCResult(t *testing.T) {
 			</file>
 		</checkstyle>`
 		body := []byte(bodyString)
-		err, failStep := logAndPersistAndEvaluateATCResults(&mock.FilesMock{}, body, "ATCResults.xml", false, "")
+		utils := &mock.FilesMock{}
+		err, failStep := logAndPersistAndEvaluateATCResults(utils, body, "ATCResults.xml", false, "")
 		assert.Equal(t, false, failStep)
 		assert.Equal(t, nil, err)
+		sarif, err := format.ReadSarif("ATCResults.sarif", utils)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "ABAP ATC", sarif.Runs[0].Tool.Driver.Name)
+			assert.Equal(t, 3, len(sarif.Runs[0].Results))
+		}
 	})
 	t.Run("succes case: test parsing example XML result - Fail on Severity error", func(t *testing.T) {
 		dir := t.TempDir()
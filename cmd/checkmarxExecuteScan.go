@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/SAP/jenkins-library/pkg/checkmarx"
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperGithub "github.com/SAP/jenkins-library/pkg/github"
 	piperHttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -377,6 +378,7 @@ func verifyCxProjectCompliance(ctx context.Context, config checkmarxExecuteScanO
 	reports = append(reports, piperutils.Path{Target: xmlReportName})
 
 	// generate sarif report
+	var baselineDiff *format.SarifDiff
 	if config.ConvertToSarif {
 		log.Entry().Info("Calling conversion to SARIF function.")
 		sarif, err := checkmarx.ConvertCxxmlToSarif(sys, xmlReportName, scanID)
@@ -388,6 +390,12 @@ func verifyCxProjectCompliance(ctx context.Context, config checkmarxExecuteScanO
 			return fmt.Errorf("failed to write sarif")
 		}
 		reports = append(reports, paths...)
+
+		baselineDiff, paths, err = newSarifBaseline("checkmarxExecuteScan", config.SarifBaselineLocation).process(filepath.Join(checkmarx.ReportsDirectory, "result.sarif"), "Checkmarx", checkmarx.ReportsDirectory)
+		if err != nil {
+			log.Entry().WithError(err).Warning("failed to process SARIF baseline")
+		}
+		reports = append(reports, paths...)
 	}
 
 	// create toolrecord
@@ -415,7 +423,12 @@ func verifyCxProjectCompliance(ctx context.Context, config checkmarxExecuteScanO
 	var neutralResults []string
 
 	if config.VulnerabilityThresholdEnabled {
-		insecure, insecureResults, neutralResults = enforceThresholds(config, results)
+		thresholdResults := results
+		if baselineDiff != nil && config.EnforceThresholdsOnNewFindings {
+			log.Entry().Infof("Enforcing thresholds only on the %v findings not contained in the baseline", len(baselineDiff.New))
+			thresholdResults = resultsForNewFindings(results, baselineDiff.New)
+		}
+		insecure, insecureResults, neutralResults = enforceThresholds(config, thresholdResults)
 		scanReport := checkmarx.CreateCustomReport(results, insecureResults, neutralResults)
 
 		if insecure && config.CreateResultIssue && len(config.GithubToken) > 0 && len(config.GithubAPIURL) > 0 && len(config.Owner) > 0 && len(config.Repository) > 0 {
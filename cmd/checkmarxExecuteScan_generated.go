@@ -55,6 +55,8 @@ type checkmarxExecuteScanOptions struct {
 	IsOptimizedAndScheduled              bool     `json:"isOptimizedAndScheduled,omitempty"`
 	CreateResultIssue                    bool     `json:"createResultIssue,omitempty"`
 	ConvertToSarif                       bool     `json:"convertToSarif,omitempty"`
+	SarifBaselineLocation                string   `json:"sarifBaselineLocation,omitempty"`
+	EnforceThresholdsOnNewFindings       bool     `json:"enforceThresholdsOnNewFindings,omitempty"`
 }
 
 type checkmarxExecuteScanInflux struct {
@@ -198,6 +200,7 @@ func (p *checkmarxExecuteScanReports) persist(stepConfig checkmarxExecuteScanOpt
 		{FilePattern: "**/CxSASTResults_*.xml", ParamRef: "", StepResultType: "checkmarx"},
 		{FilePattern: "**/ScanReport.*", ParamRef: "", StepResultType: "checkmarx"},
 		{FilePattern: "**/toolrun_checkmarx_*.json", ParamRef: "", StepResultType: "checkmarx"},
+		{FilePattern: "**/piper_differential_report.html", ParamRef: "", StepResultType: "checkmarx"},
 	}
 	envVars := []gcs.EnvVar{
 		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
@@ -303,6 +306,7 @@ thresholds instead of ` + "`" + `percentage` + "`" + ` whereas we strongly recom
 				influx.persist(GeneralConfig.EnvRootPath, "influx")
 				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
 				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
 				stepTelemetryData.PiperCommitHash = GitCommit
@@ -328,7 +332,12 @@ thresholds instead of ` + "`" + `percentage` + "`" + ` whereas we strongly recom
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
-			checkmarxExecuteScan(stepConfig, &stepTelemetryData, &influx)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+				influx = checkmarxExecuteScanInflux{}
+			}, func() {
+				checkmarxExecuteScan(stepConfig, &stepTelemetryData, &influx)
+			})
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
@@ -372,6 +381,8 @@ func addCheckmarxExecuteScanFlags(cmd *cobra.Command, stepConfig *checkmarxExecu
 	cmd.Flags().BoolVar(&stepConfig.IsOptimizedAndScheduled, "isOptimizedAndScheduled", false, "Whether the pipeline runs in optimized mode and the current execution is a scheduled one")
 	cmd.Flags().BoolVar(&stepConfig.CreateResultIssue, "createResultIssue", false, "Activate creation of a result issue in GitHub.")
 	cmd.Flags().BoolVar(&stepConfig.ConvertToSarif, "convertToSarif", true, "Convert the Checkmarx XML scan results to the open SARIF standard.")
+	cmd.Flags().StringVar(&stepConfig.SarifBaselineLocation, "sarifBaselineLocation", os.Getenv("PIPER_sarifBaselineLocation"), "Location of the SARIF baselines, either a directory or a Google Cloud Storage folder (`gs://<bucket>/<folder>`). On pull requests, the SARIF results are compared with the baseline of the target branch, otherwise they are stored as baseline of the current branch.")
+	cmd.Flags().BoolVar(&stepConfig.EnforceThresholdsOnNewFindings, "enforceThresholdsOnNewFindings", false, "On pull requests, enforce the vulnerability thresholds only on findings which are not contained in the SARIF baseline of the target branch. Requires `sarifBaselineLocation`.")
 
 	cmd.MarkFlagRequired("password")
 	cmd.MarkFlagRequired("projectName")
@@ -749,6 +760,24 @@ func checkmarxExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     true,
 					},
+					{
+						Name:        "sarifBaselineLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_sarifBaselineLocation"),
+					},
+					{
+						Name:        "enforceThresholdsOnNewFindings",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
 				},
 			},
 			Outputs: config.StepOutputs{
@@ -769,6 +798,7 @@ func checkmarxExecuteScanMetadata() config.StepData {
 							{"filePattern": "**/CxSASTResults_*.xml", "type": "checkmarx"},
 							{"filePattern": "**/ScanReport.*", "type": "checkmarx"},
 							{"filePattern": "**/toolrun_checkmarx_*.json", "type": "checkmarx"},
+							{"filePattern": "**/piper_differential_report.html", "type": "checkmarx"},
 						},
 					},
 				},
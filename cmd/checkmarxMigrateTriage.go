@@ -0,0 +1,354 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/checkmarx"
+	checkmarxOne "github.com/SAP/jenkins-library/pkg/checkmarxone"
+	piperHttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+const (
+	triageStatusMigrated  = "migrated"
+	triageStatusToMigrate = "to be migrated"
+	triageStatusUpToDate  = "already up to date"
+	triageStatusUnmatched = "unmatched"
+)
+
+// sastResultStates maps the numeric result states of Checkmarx SAST to the result states of Checkmarx One
+var sastResultStates = map[int]string{
+	0: "TO_VERIFY",
+	1: "NOT_EXPLOITABLE",
+	2: "CONFIRMED",
+	3: "URGENT",
+	4: "PROPOSED_NOT_EXPLOITABLE",
+}
+
+// triagedResult is a result of a Checkmarx SAST scan which has been audited
+type triagedResult struct {
+	Query        string
+	FileName     string
+	Line         int
+	SimilarityID string
+	State        string
+	Remark       string
+}
+
+// triageMatch is the outcome of matching a triaged Checkmarx SAST result against the Checkmarx One results
+type triageMatch struct {
+	Result            triagedResult
+	CheckmarxOneState string
+	Status            string
+}
+
+func checkmarxMigrateTriage(config checkmarxMigrateTriageOptions, _ *telemetry.CustomData) {
+	sastSys, err := checkmarx.NewSystemInstance(&piperHttp.Client{}, config.ServerURL, config.Username, config.Password)
+	if err != nil {
+		log.Entry().WithError(err).Fatalf("Failed to create Checkmarx client talking to URL %v", config.ServerURL)
+	}
+	cx1Sys, err := checkmarxOne.NewSystemInstance(&piperHttp.Client{}, config.CheckmarxOneServerURL, config.IamURL, config.Tenant, config.APIKey, config.ClientID, config.ClientSecret)
+	if err != nil {
+		log.Entry().WithError(err).Fatalf("Failed to create Checkmarx One client talking to URLs %v and %v with tenant %v", config.CheckmarxOneServerURL, config.IamURL, config.Tenant)
+	}
+
+	if err := runCheckmarxMigrateTriage(&config, sastSys, cx1Sys, &piperutils.Files{}); err != nil {
+		log.Entry().WithError(err).Fatal("Failed to migrate the Checkmarx triage")
+	}
+}
+
+func runCheckmarxMigrateTriage(config *checkmarxMigrateTriageOptions, sastSys checkmarx.System, cx1Sys checkmarxOne.System, utils piperutils.FileUtils) error {
+	triaged, err := loadSastTriage(sastSys, config.ProjectName, config.TeamName)
+	if err != nil {
+		return err
+	}
+	log.Entry().Infof("Found %v audited results in Checkmarx project '%v'", len(triaged), config.ProjectName)
+
+	cx1ProjectName := config.CheckmarxOneProjectName
+	if len(cx1ProjectName) == 0 {
+		cx1ProjectName = config.ProjectName
+	}
+	project, cx1Results, err := loadCheckmarxOneResults(cx1Sys, cx1ProjectName)
+	if err != nil {
+		return err
+	}
+
+	matches, predicates := matchTriage(triaged, cx1Results, project.ProjectID, config.ProjectName)
+	if config.DryRun {
+		log.Entry().Infof("Dry run: %v results would be updated in Checkmarx One project '%v'", len(predicates), cx1ProjectName)
+	} else {
+		if err := cx1Sys.AddResultsPredicates(predicates); err != nil {
+			return errors.Wrapf(err, "failed to update the results of Checkmarx One project '%v'", cx1ProjectName)
+		}
+		for i := range matches {
+			if matches[i].Status == triageStatusToMigrate {
+				matches[i].Status = triageStatusMigrated
+			}
+		}
+		log.Entry().Infof("Updated %v results in Checkmarx One project '%v'", len(predicates), cx1ProjectName)
+	}
+
+	scanReport := createTriageMigrationReport(config.ProjectName, cx1ProjectName, config.DryRun, matches)
+	paths, err := writeTriageMigrationReports(scanReport, utils)
+	if err != nil {
+		return err
+	}
+	piperutils.PersistReportsAndLinks("checkmarxMigrateTriage", "", utils, paths, nil)
+	return nil
+}
+
+// loadSastTriage returns the audited results of the latest finished scan of a Checkmarx SAST project
+func loadSastTriage(sys checkmarx.System, projectName, teamName string) ([]triagedResult, error) {
+	teamID := ""
+	if len(teamName) > 0 {
+		team, err := loadTeam(sys, teamName)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrap(err, "failed to load team")
+		}
+		teamID = strings.Trim(string(team.ID), `"`)
+	}
+	projects, err := sys.GetProjectsByNameAndTeam(projectName, teamID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load Checkmarx project '%v'", projectName)
+	}
+	project := sys.FilterProjectByName(projects, projectName)
+	if project.ID == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("Checkmarx project '%v' not found", projectName)
+	}
+
+	scans, err := sys.GetScans(project.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load scans of Checkmarx project '%v'", projectName)
+	}
+	scanID := 0
+	for _, scan := range scans {
+		if scan.Status.Name == "Finished" {
+			scanID = scan.ID
+			break
+		}
+	}
+	if scanID == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("no finished scan found for Checkmarx project '%v'", projectName)
+	}
+
+	data, err := generateAndDownloadReport(sys, scanID, "XML")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to download xml report")
+	}
+	var xmlResults checkmarx.CxXMLResults
+	if err := xml.Unmarshal(data, &xmlResults); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal XML report for scan %v", scanID)
+	}
+
+	triaged := []triagedResult{}
+	for _, query := range xmlResults.Query {
+		for _, result := range query.Result {
+			state, ok := sastResultStates[result.State]
+			if !ok || result.State == 0 {
+				continue
+			}
+			triaged = append(triaged, triagedResult{
+				Query:        query.Name,
+				FileName:     result.FileName,
+				Line:         result.Line,
+				SimilarityID: result.Path.SimilarityID,
+				State:        state,
+				Remark:       result.Remark,
+			})
+		}
+	}
+	return triaged, nil
+}
+
+// loadCheckmarxOneResults returns the project and the results of its latest completed scan in Checkmarx One
+func loadCheckmarxOneResults(sys checkmarxOne.System, projectName string) (checkmarxOne.Project, []checkmarxOne.ScanResult, error) {
+	projects, err := sys.GetProjectsByName(projectName)
+	if err != nil {
+		return checkmarxOne.Project{}, nil, errors.Wrapf(err, "failed to load Checkmarx One project '%v'", projectName)
+	}
+	project := checkmarxOne.Project{}
+	for _, p := range projects {
+		if p.Name == projectName {
+			project = p
+			break
+		}
+	}
+	if len(project.ProjectID) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return project, nil, fmt.Errorf("Checkmarx One project '%v' not found", projectName)
+	}
+
+	scans, err := sys.GetLastScansByStatus(project.ProjectID, 1, []string{"Completed"})
+	if err != nil {
+		return project, nil, errors.Wrapf(err, "failed to load scans of Checkmarx One project '%v'", projectName)
+	}
+	if len(scans) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return project, nil, fmt.Errorf("no completed scan found for Checkmarx One project '%v'", projectName)
+	}
+
+	totalResultCount := uint64(0)
+	scanSummary, err := sys.GetScanSummary(scans[0].ScanID)
+	if err != nil {
+		log.Entry().WithError(err).Warnf("Failed to fetch scan summary for scan %v", scans[0].ScanID)
+	} else {
+		totalResultCount = scanSummary.TotalCount()
+	}
+	results, err := sys.GetScanResults(scans[0].ScanID, totalResultCount)
+	if err != nil {
+		return project, nil, errors.Wrapf(err, "failed to load results of scan %v", scans[0].ScanID)
+	}
+	return project, results, nil
+}
+
+// triageKey identifies a result across Checkmarx SAST and Checkmarx One by query, file and similarity ID
+func triageKey(query, fileName, similarityID string) string {
+	fileName = strings.TrimPrefix(strings.ReplaceAll(fileName, `\`, "/"), "/")
+	return fmt.Sprintf("%v|%v|%v", query, fileName, similarityID)
+}
+
+// matchTriage matches the triaged Checkmarx SAST results against the Checkmarx One results and returns
+// the predicates required to transfer the states of the matched results which differ in Checkmarx One
+func matchTriage(triaged []triagedResult, cx1Results []checkmarxOne.ScanResult, projectID, sastProjectName string) ([]triageMatch, []checkmarxOne.ResultsPredicates) {
+	cx1ResultsByKey := map[string]checkmarxOne.ScanResult{}
+	for _, result := range cx1Results {
+		if len(result.Data.Nodes) == 0 {
+			continue
+		}
+		key := triageKey(result.Data.QueryName, result.Data.Nodes[0].FileName, strconv.FormatInt(result.SimilarityID, 10))
+		if _, ok := cx1ResultsByKey[key]; !ok {
+			cx1ResultsByKey[key] = result
+		}
+	}
+
+	matches := []triageMatch{}
+	predicates := []checkmarxOne.ResultsPredicates{}
+	migrated := map[string]bool{}
+	for _, result := range triaged {
+		key := triageKey(result.Query, result.FileName, result.SimilarityID)
+		cx1Result, ok := cx1ResultsByKey[key]
+		if !ok {
+			matches = append(matches, triageMatch{Result: result, Status: triageStatusUnmatched})
+			continue
+		}
+		match := triageMatch{Result: result, CheckmarxOneState: cx1Result.State, Status: triageStatusToMigrate}
+		if cx1Result.State == result.State || migrated[key] {
+			match.Status = triageStatusUpToDate
+		} else {
+			migrated[key] = true
+			comment := fmt.Sprintf("Migrated from Checkmarx project '%v'", sastProjectName)
+			if remark := strings.TrimSpace(result.Remark); len(remark) > 0 {
+				comment = fmt.Sprintf("%v: %v", comment, remark)
+			}
+			predicates = append(predicates, checkmarxOne.ResultsPredicates{
+				SimilarityID: cx1Result.SimilarityID,
+				ProjectID:    projectID,
+				State:        result.State,
+				Severity:     cx1Result.Severity,
+				Comment:      comment,
+			})
+		}
+		matches = append(matches, match)
+	}
+	return matches, predicates
+}
+
+func createTriageMigrationReport(sastProjectName, cx1ProjectName string, dryRun bool, matches []triageMatch) reporting.ScanReport {
+	mode := "apply"
+	if dryRun {
+		mode = "dry run"
+	}
+	scanReport := reporting.ScanReport{
+		ReportTitle: "Checkmarx Triage Migration Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Checkmarx project", Details: sastProjectName},
+			{Description: "Checkmarx One project", Details: cx1ProjectName},
+			{Description: "Mode", Details: mode},
+		},
+		SuccessfulScan: true,
+		ReportTime:     time.Now(),
+	}
+
+	counts := map[string]int{}
+	for _, match := range matches {
+		counts[match.Status]++
+	}
+	scanReport.Overview = append(scanReport.Overview, reporting.OverviewRow{Description: "Audited results", Details: fmt.Sprint(len(matches))})
+	for _, status := range []string{triageStatusToMigrate, triageStatusMigrated, triageStatusUpToDate, triageStatusUnmatched} {
+		if status == triageStatusToMigrate && !dryRun || status == triageStatusMigrated && dryRun {
+			continue
+		}
+		row := reporting.OverviewRow{Description: fmt.Sprintf("Results %v", status), Details: fmt.Sprint(counts[status])}
+		if status == triageStatusUnmatched && counts[status] > 0 {
+			row.Style = reporting.Yellow
+		}
+		scanReport.Overview = append(scanReport.Overview, row)
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No audited results found",
+		Headers:       []string{"Query", "Location", "Similarity ID", "Checkmarx state", "Checkmarx One state", "Status"},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+	for _, match := range matches {
+		row := reporting.ScanRow{}
+		row.AddColumn(match.Result.Query, 0)
+		row.AddColumn(fmt.Sprintf("%v:%v", match.Result.FileName, match.Result.Line), 0)
+		row.AddColumn(match.Result.SimilarityID, 0)
+		row.AddColumn(match.Result.State, 0)
+		row.AddColumn(match.CheckmarxOneState, 0)
+		style := reporting.ColumnStyle(0)
+		if match.Status == triageStatusUnmatched {
+			style = reporting.Yellow
+		}
+		row.AddColumn(match.Status, style)
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+	scanReport.DetailTable = detailTable
+
+	return scanReport
+}
+
+func writeTriageMigrationReports(scanReport reporting.ScanReport, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+
+	// ignore templating errors since template is in our hands and issues will be detected with the automated tests
+	htmlReport, _ := scanReport.ToHTML()
+	htmlReportPath := filepath.Join(checkmarx.ReportsDirectory, "piper_checkmarx_triage_migration.html")
+	if err := utils.MkdirAll(checkmarx.ReportsDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to create report directory")
+	}
+	if err := utils.FileWrite(htmlReportPath, htmlReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrap(err, "failed to write html report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Checkmarx Triage Migration Report", Target: htmlReportPath})
+
+	// JSON reports are used by step pipelineCreateSummary in order to e.g. prepare an issue creation in GitHub
+	// ignore JSON errors since structure is in our hands
+	jsonReport, _ := scanReport.ToJSON()
+	if exists, _ := utils.DirExists(reporting.StepReportDirectory); !exists {
+		if err := utils.MkdirAll(reporting.StepReportDirectory, 0777); err != nil {
+			return reportPaths, errors.Wrap(err, "failed to create step reporting directory")
+		}
+	}
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, "checkmarxMigrateTriage_triage.json"), jsonReport, 0666); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to write json report")
+	}
+
+	return reportPaths, nil
+}
@@ -0,0 +1,425 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/cobra"
+)
+
+type checkmarxMigrateTriageOptions struct {
+	ServerURL               string `json:"serverUrl,omitempty"`
+	Username                string `json:"username,omitempty"`
+	Password                string `json:"password,omitempty"`
+	ProjectName             string `json:"projectName,omitempty"`
+	TeamName                string `json:"teamName,omitempty"`
+	CheckmarxOneServerURL   string `json:"checkmarxOneServerUrl,omitempty"`
+	IamURL                  string `json:"iamUrl,omitempty"`
+	Tenant                  string `json:"tenant,omitempty"`
+	ClientID                string `json:"clientId,omitempty"`
+	ClientSecret            string `json:"clientSecret,omitempty"`
+	APIKey                  string `json:"APIKey,omitempty"`
+	CheckmarxOneProjectName string `json:"checkmarxOneProjectName,omitempty"`
+	DryRun                  bool   `json:"dryRun,omitempty"`
+}
+
+type checkmarxMigrateTriageReports struct {
+}
+
+func (p *checkmarxMigrateTriageReports) persist(stepConfig checkmarxMigrateTriageOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "**/piper_checkmarx_triage_migration.html", ParamRef: "", StepResultType: "checkmarx"},
+	}
+	envVars := []gcs.EnvVar{
+		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
+	}
+	gcsClient, err := gcs.NewClient(gcs.WithEnvVars(envVars))
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// CheckmarxMigrateTriageCommand Migrates the audit results of a Checkmarx SAST project to the corresponding Checkmarx One project.
+func CheckmarxMigrateTriageCommand() *cobra.Command {
+	const STEP_NAME = "checkmarxMigrateTriage"
+
+	metadata := checkmarxMigrateTriageMetadata()
+	var stepConfig checkmarxMigrateTriageOptions
+	var startTime time.Time
+	var reports checkmarxMigrateTriageReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createCheckmarxMigrateTriageCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Migrates the audit results of a Checkmarx SAST project to the corresponding Checkmarx One project.",
+		Long: `When moving from ` + "`" + `checkmarxExecuteScan` + "`" + ` to ` + "`" + `checkmarxOneExecuteScan` + "`" + ` the audit work done on the Checkmarx SAST server, i.e. the states like
+'Not Exploitable' or 'Confirmed' and the comments of the results, is not available in Checkmarx One.
+
+This step reads the results of the latest finished scan of a Checkmarx SAST project and applies their states to the results of the latest completed scan
+of the corresponding Checkmarx One project. Results are matched via their query name, file name and similarity ID.
+The remark of a Checkmarx SAST result is carried over as comment.
+
+By default the step runs in ` + "`" + `dryRun` + "`" + ` mode and only creates a report listing the matched and unmatched results.
+Once the report looks good, set ` + "`" + `dryRun: false` + "`" + ` in order to write the states to Checkmarx One.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.Username)
+			log.RegisterSecret(stepConfig.Password)
+			log.RegisterSecret(stepConfig.ClientID)
+			log.RegisterSecret(stepConfig.ClientSecret)
+			log.RegisterSecret(stepConfig.APIKey)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.Send()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+			}, func() {
+				checkmarxMigrateTriage(stepConfig, &stepTelemetryData)
+			})
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addCheckmarxMigrateTriageFlags(createCheckmarxMigrateTriageCmd, &stepConfig)
+	return createCheckmarxMigrateTriageCmd
+}
+
+func addCheckmarxMigrateTriageFlags(cmd *cobra.Command, stepConfig *checkmarxMigrateTriageOptions) {
+	cmd.Flags().StringVar(&stepConfig.ServerURL, "serverUrl", os.Getenv("PIPER_serverUrl"), "The URL pointing to the root of the Checkmarx SAST server to read the audit results from")
+	cmd.Flags().StringVar(&stepConfig.Username, "username", os.Getenv("PIPER_username"), "The username to authenticate with Checkmarx SAST")
+	cmd.Flags().StringVar(&stepConfig.Password, "password", os.Getenv("PIPER_password"), "The password to authenticate with Checkmarx SAST")
+	cmd.Flags().StringVar(&stepConfig.ProjectName, "projectName", os.Getenv("PIPER_projectName"), "The name of the Checkmarx SAST project to read the audit results from")
+	cmd.Flags().StringVar(&stepConfig.TeamName, "teamName", os.Getenv("PIPER_teamName"), "The full name of the Checkmarx SAST team the project belongs to")
+	cmd.Flags().StringVar(&stepConfig.CheckmarxOneServerURL, "checkmarxOneServerUrl", os.Getenv("PIPER_checkmarxOneServerUrl"), "The URL pointing to the root of the Checkmarx One server to apply the audit results to")
+	cmd.Flags().StringVar(&stepConfig.IamURL, "iamUrl", os.Getenv("PIPER_iamUrl"), "The URL pointing to the access control root of the Checkmarx One IAM server to be used")
+	cmd.Flags().StringVar(&stepConfig.Tenant, "tenant", os.Getenv("PIPER_tenant"), "The name of the Checkmarx One tenant to be used")
+	cmd.Flags().StringVar(&stepConfig.ClientID, "clientId", os.Getenv("PIPER_clientId"), "The clientId to authenticate with Checkmarx One using a service account")
+	cmd.Flags().StringVar(&stepConfig.ClientSecret, "clientSecret", os.Getenv("PIPER_clientSecret"), "The clientSecret to authenticate with Checkmarx One using a service account")
+	cmd.Flags().StringVar(&stepConfig.APIKey, "APIKey", os.Getenv("PIPER_APIKey"), "The APIKey to authenticate with Checkmarx One, preferred to `clientId` and `clientSecret`")
+	cmd.Flags().StringVar(&stepConfig.CheckmarxOneProjectName, "checkmarxOneProjectName", os.Getenv("PIPER_checkmarxOneProjectName"), "The name of the Checkmarx One project to apply the audit results to. If not set, `projectName` is used.")
+	cmd.Flags().BoolVar(&stepConfig.DryRun, "dryRun", true, "Whether the step shall only report the matched and unmatched results without changing their state in Checkmarx One")
+
+	cmd.MarkFlagRequired("serverUrl")
+	cmd.MarkFlagRequired("username")
+	cmd.MarkFlagRequired("password")
+	cmd.MarkFlagRequired("projectName")
+	cmd.MarkFlagRequired("checkmarxOneServerUrl")
+	cmd.MarkFlagRequired("iamUrl")
+	cmd.MarkFlagRequired("tenant")
+}
+
+// retrieve step metadata
+func checkmarxMigrateTriageMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "checkmarxMigrateTriage",
+			Aliases:     []config.Alias{},
+			Description: "Migrates the audit results of a Checkmarx SAST project to the corresponding Checkmarx One project.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "checkmarxCredentialsId", Description: "Jenkins 'Username with password' credentials ID containing username and password to communicate with the Checkmarx SAST backend.", Type: "jenkins"},
+					{Name: "checkmarxOneCredentialsId", Description: "Jenkins 'Username with password' credentials ID containing ClientID and ClientSecret to communicate with the Checkmarx One backend.", Type: "jenkins"},
+					{Name: "checkmarxOneAPIKey", Description: "Jenkins 'Secret Text' containing the APIKey to communicate with the Checkmarx One backend.", Type: "jenkins"},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name:        "serverUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{{Name: "checkmarxServerUrl"}},
+						Default:     os.Getenv("PIPER_serverUrl"),
+					},
+					{
+						Name: "username",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "checkmarxCredentialsId",
+								Param: "username",
+								Type:  "secret",
+							},
+
+							{
+								Name:    "checkmarxVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "checkmarx",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_username"),
+					},
+					{
+						Name: "password",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "checkmarxCredentialsId",
+								Param: "password",
+								Type:  "secret",
+							},
+
+							{
+								Name:    "checkmarxVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "checkmarx",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_password"),
+					},
+					{
+						Name:        "projectName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_projectName"),
+					},
+					{
+						Name:        "teamName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_teamName"),
+					},
+					{
+						Name:        "checkmarxOneServerUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_checkmarxOneServerUrl"),
+					},
+					{
+						Name:        "iamUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_iamUrl"),
+					},
+					{
+						Name:        "tenant",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_tenant"),
+					},
+					{
+						Name: "clientId",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "checkmarxOneCredentialsId",
+								Param: "clientId",
+								Type:  "secret",
+							},
+
+							{
+								Name:    "checkmarxOneVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "checkmarxOne",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_clientId"),
+					},
+					{
+						Name: "clientSecret",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "checkmarxOneCredentialsId",
+								Param: "clientSecret",
+								Type:  "secret",
+							},
+
+							{
+								Name:    "checkmarxOneVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "checkmarxOne",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_clientSecret"),
+					},
+					{
+						Name: "APIKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "checkmarxOneAPIKey",
+								Param: "APIKey",
+								Type:  "secret",
+							},
+
+							{
+								Name:    "checkmarxOneVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "checkmarxOne",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_APIKey"),
+					},
+					{
+						Name:        "checkmarxOneProjectName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_checkmarxOneProjectName"),
+					},
+					{
+						Name:        "dryRun",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "**/piper_checkmarx_triage_migration.html", "type": "checkmarx"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,206 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/checkmarx"
+	checkmarxOne "github.com/SAP/jenkins-library/pkg/checkmarxone"
+	piperHttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+const triageSastReport = `<?xml version="1.0" encoding="utf-8"?>
+<CxXMLResults ProjectName="Test" ScanId="1001">
+  <Query name="SQL_Injection" Severity="High">
+    <Result FileName="src/db.go" Line="10" state="1" Remark="sanitized by the ORM"><Path SimilarityId="-111"/></Result>
+    <Result FileName="src/api.go" Line="20" state="2"><Path SimilarityId="222"/></Result>
+    <Result FileName="src/api.go" Line="30" state="0"><Path SimilarityId="333"/></Result>
+    <Result FileName="src/old.go" Line="5" state="1"><Path SimilarityId="444"/></Result>
+  </Query>
+  <Query name="Reflected_XSS" Severity="Medium">
+    <Result FileName="web/index.js" Line="3" state="4"><Path SimilarityId="555"/></Result>
+  </Query>
+</CxXMLResults>`
+
+const triageCheckmarxOneResults = `{"totalCount": 4, "results": [
+  {"similarityId": "-111", "state": "TO_VERIFY", "severity": "HIGH", "data": {"queryName": "SQL_Injection", "nodes": [{"fileName": "/src/db.go", "line": 10}]}},
+  {"similarityId": "222", "state": "CONFIRMED", "severity": "HIGH", "data": {"queryName": "SQL_Injection", "nodes": [{"fileName": "/src/api.go", "line": 20}]}},
+  {"similarityId": "333", "state": "TO_VERIFY", "severity": "HIGH", "data": {"queryName": "SQL_Injection", "nodes": [{"fileName": "/src/api.go", "line": 30}]}},
+  {"similarityId": "556", "state": "TO_VERIFY", "severity": "MEDIUM", "data": {"queryName": "Reflected_XSS", "nodes": [{"fileName": "/web/index.js", "line": 3}]}}
+]}`
+
+// triageMigrationBackends provides stand-ins for the Checkmarx SAST and the Checkmarx One API
+type triageMigrationBackends struct {
+	sast       *httptest.Server
+	cx1        *httptest.Server
+	mutex      sync.Mutex
+	predicates []string
+}
+
+func newTriageMigrationBackends(t *testing.T) *triageMigrationBackends {
+	backends := &triageMigrationBackends{}
+
+	backends.sast = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/cxrestapi/auth/identity/connect/token":
+			rw.Write([]byte(`{"token_type": "Bearer", "access_token": "sast-token"}`))
+		case "/cxrestapi/projects":
+			rw.Write([]byte(`[{"id": 2, "name": "Other"}, {"id": 1, "name": "Test"}]`))
+		case "/cxrestapi/sast/scans":
+			assert.Equal(t, "1", req.URL.Query().Get("projectId"))
+			rw.Write([]byte(`[{"id": 1002, "status": {"id": 9, "name": "Failed"}}, {"id": 1001, "status": {"id": 7, "name": "Finished"}}]`))
+		case "/cxrestapi/reports/sastScan":
+			body, _ := io.ReadAll(req.Body)
+			assert.Contains(t, string(body), `"scanId":1001`)
+			rw.Write([]byte(`{"reportId": 5}`))
+		case "/cxrestapi/reports/sastScan/5/status":
+			rw.Write([]byte(`{"status": {"id": 2, "value": "Created"}}`))
+		case "/cxrestapi/reports/sastScan/5":
+			rw.Write([]byte(triageSastReport))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(backends.sast.Close)
+
+	backends.cx1 = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/auth/realms/tenant/protocol/openid-connect/token":
+			rw.Write([]byte(`{"token_type": "Bearer", "access_token": "cx1-token"}`))
+		case "/api/projects/":
+			rw.Write([]byte(`{"totalCount": 2, "projects": [{"id": "cx1-project-1", "name": "Test-1"}, {"id": "cx1-project", "name": "Test"}]}`))
+		case "/api/scans/":
+			assert.Equal(t, "cx1-project", req.URL.Query().Get("project-id"))
+			rw.Write([]byte(`{"totalCount": 1, "scans": [{"id": "cx1-scan", "status": "Completed"}]}`))
+		case "/api/scan-summary/":
+			rw.Write([]byte(`{"totalCount": 1, "scansSummaries": [{"sastCounters": {"stateCounters": [{"state": "TO_VERIFY", "counter": 3}, {"state": "CONFIRMED", "counter": 1}]}}]}`))
+		case "/api/results/":
+			assert.Equal(t, "cx1-scan", req.URL.Query().Get("scan-id"))
+			assert.Equal(t, "4", req.URL.Query().Get("limit"))
+			rw.Write([]byte(triageCheckmarxOneResults))
+		case "/api/sast-results-predicates":
+			body, _ := io.ReadAll(req.Body)
+			backends.mutex.Lock()
+			backends.predicates = append(backends.predicates, string(body))
+			backends.mutex.Unlock()
+			rw.WriteHeader(http.StatusCreated)
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(backends.cx1.Close)
+
+	return backends
+}
+
+func (b *triageMigrationBackends) systems(t *testing.T) (checkmarx.System, checkmarxOne.System) {
+	sastSys, err := checkmarx.NewSystemInstance(&piperHttp.Client{}, b.sast.URL, "user", "password")
+	require.NoError(t, err)
+	cx1Sys, err := checkmarxOne.NewSystemInstance(&piperHttp.Client{}, b.cx1.URL, b.cx1.URL, "tenant", "", "client", "secret")
+	require.NoError(t, err)
+	return sastSys, cx1Sys
+}
+
+func TestRunCheckmarxMigrateTriage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("dry run", func(t *testing.T) {
+		t.Parallel()
+		backends := newTriageMigrationBackends(t)
+		sastSys, cx1Sys := backends.systems(t)
+		utils := &mock.FilesMock{}
+		config := checkmarxMigrateTriageOptions{ProjectName: "Test", DryRun: true}
+
+		err := runCheckmarxMigrateTriage(&config, sastSys, cx1Sys, utils)
+
+		assert.NoError(t, err)
+		assert.Empty(t, backends.predicates)
+		assert.True(t, utils.HasWrittenFile(filepath.Join("checkmarx", "piper_checkmarx_triage_migration.html")))
+		jsonReport, err := utils.FileRead(filepath.Join(reporting.StepReportDirectory, "checkmarxMigrateTriage_triage.json"))
+		if assert.NoError(t, err) {
+			assert.Contains(t, string(jsonReport), `"description":"Results to be migrated","details":"1"`)
+			assert.Contains(t, string(jsonReport), `"description":"Results already up to date","details":"1"`)
+			assert.Contains(t, string(jsonReport), `"description":"Results unmatched","details":"2"`)
+		}
+	})
+
+	t.Run("apply triage", func(t *testing.T) {
+		t.Parallel()
+		backends := newTriageMigrationBackends(t)
+		sastSys, cx1Sys := backends.systems(t)
+		utils := &mock.FilesMock{}
+		config := checkmarxMigrateTriageOptions{ProjectName: "Test"}
+
+		err := runCheckmarxMigrateTriage(&config, sastSys, cx1Sys, utils)
+
+		assert.NoError(t, err)
+		if assert.Len(t, backends.predicates, 1) {
+			assert.Equal(t, `[{"similarityId":"-111","projectId":"cx1-project","severity":"HIGH","state":"NOT_EXPLOITABLE","comment":"Migrated from Checkmarx project 'Test': sanitized by the ORM"}]`, backends.predicates[0])
+		}
+		jsonReport, err := utils.FileRead(filepath.Join(reporting.StepReportDirectory, "checkmarxMigrateTriage_triage.json"))
+		if assert.NoError(t, err) {
+			assert.Contains(t, string(jsonReport), `"description":"Results migrated","details":"1"`)
+		}
+	})
+
+	t.Run("Checkmarx project not found", func(t *testing.T) {
+		t.Parallel()
+		backends := newTriageMigrationBackends(t)
+		sastSys, cx1Sys := backends.systems(t)
+		config := checkmarxMigrateTriageOptions{ProjectName: "Unknown", CheckmarxOneProjectName: "Test"}
+
+		err := runCheckmarxMigrateTriage(&config, sastSys, cx1Sys, &mock.FilesMock{})
+
+		assert.EqualError(t, err, "Checkmarx project 'Unknown' not found")
+	})
+
+	t.Run("Checkmarx One project not found", func(t *testing.T) {
+		t.Parallel()
+		backends := newTriageMigrationBackends(t)
+		sastSys, cx1Sys := backends.systems(t)
+		config := checkmarxMigrateTriageOptions{ProjectName: "Test", CheckmarxOneProjectName: "Test-2"}
+
+		err := runCheckmarxMigrateTriage(&config, sastSys, cx1Sys, &mock.FilesMock{})
+
+		assert.EqualError(t, err, "Checkmarx One project 'Test-2' not found")
+		assert.Empty(t, backends.predicates)
+	})
+}
+
+func TestMatchTriage(t *testing.T) {
+	t.Parallel()
+
+	triaged := []triagedResult{
+		{Query: "Path_Traversal", FileName: `src\files.go`, SimilarityID: "42", State: "CONFIRMED"},
+		{Query: "Path_Traversal", FileName: "src/files.go", SimilarityID: "42", State: "NOT_EXPLOITABLE"},
+		{Query: "Path_Traversal", FileName: "src/other.go", SimilarityID: "42", State: "CONFIRMED"},
+	}
+	cx1Results := []checkmarxOne.ScanResult{
+		{SimilarityID: 42, State: "TO_VERIFY", Severity: "MEDIUM", Data: checkmarxOne.ScanResultData{QueryName: "Path_Traversal", Nodes: []checkmarxOne.ScanResultNodes{{FileName: "/src/files.go"}}}},
+		{SimilarityID: 43, State: "TO_VERIFY", Severity: "MEDIUM", Data: checkmarxOne.ScanResultData{QueryName: "Path_Traversal"}},
+	}
+
+	matches, predicates := matchTriage(triaged, cx1Results, "project", "Test")
+
+	if assert.Len(t, predicates, 1) {
+		assert.Equal(t, checkmarxOne.ResultsPredicates{SimilarityID: 42, ProjectID: "project", State: "CONFIRMED", Severity: "MEDIUM", Comment: "Migrated from Checkmarx project 'Test'"}, predicates[0])
+	}
+	statuses := []string{}
+	for _, match := range matches {
+		statuses = append(statuses, match.Status)
+	}
+	assert.Equal(t, []string{triageStatusToMigrate, triageStatusUpToDate, triageStatusUnmatched}, statuses)
+	assert.Equal(t, "Path_Traversal|a/b.go|1", triageKey("Path_Traversal", `\a\b.go`, "1"))
+}
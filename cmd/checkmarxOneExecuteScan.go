@@ -15,6 +15,7 @@ import (
 	"time"
 
 	checkmarxOne "github.com/SAP/jenkins-library/pkg/checkmarxone"
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperGithub "github.com/SAP/jenkins-library/pkg/github"
 	piperHttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -49,6 +50,8 @@ type checkmarxOneExecuteScanHelper struct {
 	Group   *checkmarxOne.Group
 	App     *checkmarxOne.Application
 	reports []piperutils.Path
+	// baselineDiff contains the comparison with the SARIF baseline on pull requests
+	baselineDiff *format.SarifDiff
 }
 
 type checkmarxOneExecuteScanUtilsBundle struct {
@@ -193,7 +196,7 @@ func Authenticate(config checkmarxOneExecuteScanOptions, influx *checkmarxOneExe
 
 	utils := newcheckmarxOneExecuteScanUtilsBundle("./", ghClient)
 
-	return checkmarxOneExecuteScanHelper{ctx, config, sys, influx, utils, nil, nil, nil, []piperutils.Path{}}, nil
+	return checkmarxOneExecuteScanHelper{ctx, config, sys, influx, utils, nil, nil, nil, []piperutils.Path{}, nil}, nil
 }
 
 func (c *checkmarxOneExecuteScanHelper) GetProjectByName() (*checkmarxOne.Project, error) {
@@ -468,7 +471,13 @@ func (c *checkmarxOneExecuteScanHelper) CheckCompliance(scan *checkmarxOne.Scan,
 	var neutralResults []string
 
 	if c.config.VulnerabilityThresholdEnabled {
-		insecure, insecureResults, neutralResults = c.enforceThresholds(detailedResults)
+		thresholdResults := detailedResults
+		if c.baselineDiff != nil && c.config.EnforceThresholdsOnNewFindings {
+			log.Entry().Infof("Enforcing thresholds only on the %v findings not contained in the baseline", len(c.baselineDiff.New))
+			newResults := resultsForNewFindings(*detailedResults, c.baselineDiff.New)
+			thresholdResults = &newResults
+		}
+		insecure, insecureResults, neutralResults = c.enforceThresholds(thresholdResults)
 		scanReport := checkmarxOne.CreateCustomReport(detailedResults, insecureResults, neutralResults)
 
 		if insecure && c.config.CreateResultIssue && len(c.config.GithubToken) > 0 && len(c.config.GithubAPIURL) > 0 && len(c.config.Owner) > 0 && len(c.config.Repository) > 0 {
@@ -538,6 +547,12 @@ func (c *checkmarxOneExecuteScanHelper) GetReportSARIF(scan *checkmarxOne.Scan,
 			return fmt.Errorf("Failed to write SARIF: %s", err)
 		}
 		c.reports = append(c.reports, paths...)
+
+		c.baselineDiff, paths, err = newSarifBaseline("checkmarxOneExecuteScan", c.config.SarifBaselineLocation).process(filepath.Join(checkmarxOne.ReportsDirectory, "result.sarif"), "Checkmarx One", checkmarxOne.ReportsDirectory)
+		if err != nil {
+			log.Entry().WithError(err).Warning("Failed to process SARIF baseline")
+		}
+		c.reports = append(c.reports, paths...)
 	}
 	return nil
 }
@@ -60,6 +60,8 @@ type checkmarxOneExecuteScanOptions struct {
 	IsOptimizedAndScheduled              bool     `json:"isOptimizedAndScheduled,omitempty"`
 	CreateResultIssue                    bool     `json:"createResultIssue,omitempty"`
 	ConvertToSarif                       bool     `json:"convertToSarif,omitempty"`
+	SarifBaselineLocation                string   `json:"sarifBaselineLocation,omitempty"`
+	EnforceThresholdsOnNewFindings       bool     `json:"enforceThresholdsOnNewFindings,omitempty"`
 }
 
 type checkmarxOneExecuteScanInflux struct {
@@ -204,6 +206,7 @@ func (p *checkmarxOneExecuteScanReports) persist(stepConfig checkmarxOneExecuteS
 		{FilePattern: "**/ScanReport.*", ParamRef: "", StepResultType: "checkmarxone"},
 		{FilePattern: "**/toolrun_checkmarxone_*.json", ParamRef: "", StepResultType: "checkmarxone"},
 		{FilePattern: "**/piper_checkmarxone_report.json", ParamRef: "", StepResultType: "checkmarxone"},
+		{FilePattern: "**/piper_differential_report.html", ParamRef: "", StepResultType: "checkmarxone"},
 	}
 	envVars := []gcs.EnvVar{
 		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
@@ -310,6 +313,7 @@ thresholds instead of ` + "`" + `percentage` + "`" + ` whereas we strongly recom
 				influx.persist(GeneralConfig.EnvRootPath, "influx")
 				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
 				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
 				stepTelemetryData.PiperCommitHash = GitCommit
@@ -335,7 +339,12 @@ thresholds instead of ` + "`" + `percentage` + "`" + ` whereas we strongly recom
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
-			checkmarxOneExecuteScan(stepConfig, &stepTelemetryData, &influx)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+				influx = checkmarxOneExecuteScanInflux{}
+			}, func() {
+				checkmarxOneExecuteScan(stepConfig, &stepTelemetryData, &influx)
+			})
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
@@ -384,6 +393,8 @@ func addCheckmarxOneExecuteScanFlags(cmd *cobra.Command, stepConfig *checkmarxOn
 	cmd.Flags().BoolVar(&stepConfig.IsOptimizedAndScheduled, "isOptimizedAndScheduled", false, "Whether the pipeline runs in optimized mode and the current execution is a scheduled one")
 	cmd.Flags().BoolVar(&stepConfig.CreateResultIssue, "createResultIssue", false, "Activate creation of a result issue in GitHub.")
 	cmd.Flags().BoolVar(&stepConfig.ConvertToSarif, "convertToSarif", true, "Convert the checkmarxOne XML scan results to the open SARIF standard.")
+	cmd.Flags().StringVar(&stepConfig.SarifBaselineLocation, "sarifBaselineLocation", os.Getenv("PIPER_sarifBaselineLocation"), "Location of the SARIF baselines, either a directory or a Google Cloud Storage folder (`gs://<bucket>/<folder>`). On pull requests, the SARIF results are compared with the baseline of the target branch, otherwise they are stored as baseline of the current branch.")
+	cmd.Flags().BoolVar(&stepConfig.EnforceThresholdsOnNewFindings, "enforceThresholdsOnNewFindings", false, "On pull requests, enforce the vulnerability thresholds only on findings which are not contained in the SARIF baseline of the target branch. Requires `sarifBaselineLocation`.")
 
 	cmd.MarkFlagRequired("clientSecret")
 	cmd.MarkFlagRequired("APIKey")
@@ -824,6 +835,24 @@ func checkmarxOneExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     true,
 					},
+					{
+						Name:        "sarifBaselineLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_sarifBaselineLocation"),
+					},
+					{
+						Name:        "enforceThresholdsOnNewFindings",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
 				},
 			},
 			Outputs: config.StepOutputs{
@@ -845,6 +874,7 @@ func checkmarxOneExecuteScanMetadata() config.StepData {
 							{"filePattern": "**/ScanReport.*", "type": "checkmarxone"},
 							{"filePattern": "**/toolrun_checkmarxone_*.json", "type": "checkmarxone"},
 							{"filePattern": "**/piper_checkmarxone_report.json", "type": "checkmarxone"},
+							{"filePattern": "**/piper_differential_report.html", "type": "checkmarxone"},
 						},
 					},
 				},
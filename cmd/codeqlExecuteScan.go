@@ -6,23 +6,30 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/SAP/jenkins-library/pkg/codeql"
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/orchestrator"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/toolrecord"
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 )
 
 type codeqlExecuteScanUtils interface {
 	command.ExecRunner
 
 	piperutils.FileUtils
+
+	// Clone returns utils with an own command runner for concurrent executions
+	Clone() codeqlExecuteScanUtils
 }
 
 type RepoInfo struct {
@@ -52,6 +59,14 @@ func newCodeqlExecuteScanUtils() codeqlExecuteScanUtils {
 	return &utils
 }
 
+func (c *codeqlExecuteScanUtilsBundle) Clone() codeqlExecuteScanUtils {
+	clone := *c
+	clone.Command = &command.Command{}
+	clone.Stdout(log.Writer())
+	clone.Stderr(log.Writer())
+	return &clone
+}
+
 func codeqlExecuteScan(config codeqlExecuteScanOptions, telemetryData *telemetry.CustomData) {
 
 	utils := newCodeqlExecuteScanUtils()
@@ -244,33 +259,25 @@ func runCodeqlExecuteScan(config *codeqlExecuteScanOptions, telemetryData *telem
 	}
 
 	var reports []piperutils.Path
-	cmd := []string{"database", "create", config.Database, "--overwrite", "--source-root", config.ModulePath}
-
-	language := getLangFromBuildTool(config.BuildTool)
 
-	if len(language) == 0 && len(config.Language) == 0 {
-		if config.BuildTool == "custom" {
-			return reports, fmt.Errorf("as the buildTool is custom. please specify the language parameter")
-		} else {
-			return reports, fmt.Errorf("the step could not recognize the specified buildTool %s. please specify valid buildtool", config.BuildTool)
-		}
-	}
-	if len(language) > 0 {
-		cmd = append(cmd, "--language="+language)
-	} else {
-		cmd = append(cmd, "--language="+config.Language)
+	languages, err := getLanguages(config)
+	if err != nil {
+		return reports, err
 	}
 
-	cmd = append(cmd, getRamAndThreadsFromConfig(config)...)
+	repoInfo := initGitInfo(config)
 
-	//codeql has an autobuilder which tries to build the project based on specified programming language
-	if len(config.BuildCommand) > 0 {
-		cmd = append(cmd, "--command="+config.BuildCommand)
+	cache := codeql.DatabaseCache{Directory: config.CacheDirectory, CommitID: repoInfo.commitId}
+	if cache.Enabled() {
+		if err := cache.Prune(utils); err != nil {
+			log.Entry().WithError(err).Warning("failed to remove outdated CodeQL databases from the cache")
+		}
+	} else if len(config.CacheDirectory) > 0 {
+		log.Entry().Warn("CodeQL databases are not cached since the analyzed commit is unknown.")
 	}
 
-	err = execute(utils, cmd, GeneralConfig.Verbose)
-	if err != nil {
-		log.Entry().Error("failed running command codeql database create")
+	databases := getDatabases(config, languages, cache, utils)
+	if err := createDatabases(config, databases, utils); err != nil {
 		return reports, err
 	}
 
@@ -279,31 +286,22 @@ func runCodeqlExecuteScan(config *codeqlExecuteScanOptions, telemetryData *telem
 		return reports, fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	cmd = nil
-	cmd = append(cmd, "database", "analyze", "--format=sarif-latest", fmt.Sprintf("--output=%v", filepath.Join(config.ModulePath, "target", "codeqlReport.sarif")), config.Database)
-	cmd = append(cmd, getRamAndThreadsFromConfig(config)...)
-	cmd = codeqlQuery(cmd, config.QuerySuite)
-	err = execute(utils, cmd, GeneralConfig.Verbose)
-	if err != nil {
-		log.Entry().Error("failed running command codeql database analyze for sarif generation")
+	if err := analyzeDatabases(config, databases, cache, utils); err != nil {
 		return reports, err
 	}
 
 	reports = append(reports, piperutils.Path{Target: filepath.Join(config.ModulePath, "target", "codeqlReport.sarif")})
 
-	cmd = nil
-	cmd = append(cmd, "database", "analyze", "--format=csv", fmt.Sprintf("--output=%v", filepath.Join(config.ModulePath, "target", "codeqlReport.csv")), config.Database)
-	cmd = append(cmd, getRamAndThreadsFromConfig(config)...)
-	cmd = codeqlQuery(cmd, config.QuerySuite)
-	err = execute(utils, cmd, GeneralConfig.Verbose)
+	baseline := newSarifBaseline("codeqlExecuteScan", config.SarifBaselineLocation)
+	baseline.fileUtils = utils
+	baselineDiff, paths, err := baseline.process(filepath.Join(config.ModulePath, "target", "codeqlReport.sarif"), "CodeQL", filepath.Join(config.ModulePath, "target"))
 	if err != nil {
-		log.Entry().Error("failed running command codeql database analyze for csv generation")
-		return reports, err
+		log.Entry().WithError(err).Warning("failed to process SARIF baseline")
 	}
+	reports = append(reports, paths...)
 
 	reports = append(reports, piperutils.Path{Target: filepath.Join(config.ModulePath, "target", "codeqlReport.csv")})
 
-	repoInfo := initGitInfo(config)
 	repoUrl := fmt.Sprintf("%s/%s/%s", repoInfo.serverUrl, repoInfo.owner, repoInfo.repo)
 	repoReference, err := buildRepoReference(repoUrl, repoInfo.ref)
 	repoCodeqlScanUrl := fmt.Sprintf("%s/security/code-scanning?query=is:open+ref:%s", repoUrl, repoInfo.ref)
@@ -339,7 +337,13 @@ func runCodeqlExecuteScan(config *codeqlExecuteScanOptions, telemetryData *telem
 		}
 		reports = append(reports, paths...)
 
-		if config.CheckForCompliance {
+		if config.CheckForCompliance && baselineDiff != nil && config.EnforceThresholdsOnNewFindings {
+			unaudited := len(format.UnauditedResults(baselineDiff.New))
+			if unaudited > config.VulnerabilityThresholdTotal {
+				msg := fmt.Sprintf("Your repository %v with ref %v is not compliant. New unaudited issues compared to %v are %v which is greater than the VulnerabilityThresholdTotal count %v", repoUrl, repoInfo.ref, baselineDiff.BaselineName, unaudited, config.VulnerabilityThresholdTotal)
+				return reports, errors.Errorf(msg)
+			}
+		} else if config.CheckForCompliance {
 			for _, scanResult := range scanResults {
 				unaudited := scanResult.Total - scanResult.Audited
 				if unaudited > config.VulnerabilityThresholdTotal {
@@ -441,13 +445,243 @@ func persistToolRecord(toolRecord *toolrecord.Toolrecord) (string, error) {
 	return toolRecord.GetFileName(), nil
 }
 
-func getRamAndThreadsFromConfig(config *codeqlExecuteScanOptions) []string {
+// getRamAndThreadsFromConfig returns the ram and threads options of a codeql command.
+// Since the configured ram and threads are available for the whole step, they are shared among the given number of concurrent codeql processes.
+func getRamAndThreadsFromConfig(config *codeqlExecuteScanOptions, concurrent int) []string {
+	threads, ram := config.Threads, config.Ram
+	if concurrent > 1 {
+		threads, ram = shareThreads(threads, concurrent), shareRam(ram, concurrent)
+	}
 	params := make([]string, 0, 2)
-	if len(config.Threads) > 0 {
-		params = append(params, "--threads="+config.Threads)
+	if len(threads) > 0 {
+		params = append(params, "--threads="+threads)
 	}
-	if len(config.Ram) > 0 {
-		params = append(params, "--ram="+config.Ram)
+	if len(ram) > 0 {
+		params = append(params, "--ram="+ram)
 	}
 	return params
 }
+
+// shareThreads returns the threads of one of the concurrent processes, 0 means one thread per core and negative values leave cores unused
+func shareThreads(threads string, concurrent int) string {
+	number, err := strconv.Atoi(threads)
+	if err != nil {
+		return threads
+	}
+	if number <= 0 {
+		number += runtime.NumCPU()
+	}
+	if number = number / concurrent; number < 1 {
+		number = 1
+	}
+	return strconv.Itoa(number)
+}
+
+// shareRam returns the ram in MB of one of the concurrent processes
+func shareRam(ram string, concurrent int) string {
+	number, err := strconv.Atoi(ram)
+	if err != nil {
+		return ram
+	}
+	return strconv.Itoa(number / concurrent)
+}
+
+// codeqlDatabase is the database of a single language to be created and analyzed
+type codeqlDatabase struct {
+	language string
+	path     string
+	// cached is true if the database has been created by a previous run for the same commit
+	cached bool
+}
+
+// getLanguages returns the languages to analyze, either the comma separated list of the language parameter or the language of the build tool
+func getLanguages(config *codeqlExecuteScanOptions) ([]string, error) {
+	languages := []string{}
+	for _, language := range strings.Split(config.Language, ",") {
+		if language = strings.TrimSpace(language); len(language) > 0 && !piperutils.ContainsString(languages, language) {
+			languages = append(languages, language)
+		}
+	}
+	if len(languages) > 0 {
+		return languages, nil
+	}
+
+	if language := getLangFromBuildTool(config.BuildTool); len(language) > 0 {
+		return []string{language}, nil
+	}
+	if config.BuildTool == "custom" {
+		return languages, fmt.Errorf("as the buildTool is custom. please specify the language parameter")
+	}
+	return languages, fmt.Errorf("the step could not recognize the specified buildTool %s. please specify valid buildtool", config.BuildTool)
+}
+
+// getDatabases returns the database locations, either within the cache or the database directory which contains one folder per language in case of several languages
+func getDatabases(config *codeqlExecuteScanOptions, languages []string, cache codeql.DatabaseCache, utils codeqlExecuteScanUtils) []codeqlDatabase {
+	databases := []codeqlDatabase{}
+	for _, language := range languages {
+		database := codeqlDatabase{language: language, path: config.Database}
+		if cache.Enabled() {
+			database.path = cache.DatabasePath(language)
+			database.cached = codeql.IsFinalized(database.path, utils)
+		} else if len(languages) > 1 {
+			database.path = filepath.Join(config.Database, language)
+		}
+		databases = append(databases, database)
+	}
+	return databases
+}
+
+// requiresBuild returns true for the compiled languages whose database is created by tracing the build
+func requiresBuild(language string) bool {
+	switch language {
+	case "javascript", "javascript-typescript", "typescript", "python", "ruby":
+		return false
+	}
+	return true
+}
+
+// createDatabases creates the databases which are not cached. Since builds of the compiled languages would interfere with each other,
+// their databases are created one after the other while the databases of the other languages are created concurrently.
+func createDatabases(config *codeqlExecuteScanOptions, databases []codeqlDatabase, utils codeqlExecuteScanUtils) error {
+	if len(databases) == 1 {
+		if databases[0].cached {
+			log.Entry().Infof("Using cached CodeQL database %v", databases[0].path)
+			return nil
+		}
+		return createDatabase(config, databases[0], len(config.BuildCommand) > 0, 1, utils)
+	}
+
+	compiled, others := []codeqlDatabase{}, []codeqlDatabase{}
+	for _, database := range databases {
+		if database.cached {
+			log.Entry().Infof("Using cached CodeQL database %v for language %v", database.path, database.language)
+		} else if requiresBuild(database.language) {
+			compiled = append(compiled, database)
+		} else {
+			others = append(others, database)
+		}
+	}
+	// each of the other languages runs in a process of its own while the compiled languages share one process at a time
+	concurrent := len(others)
+	if len(compiled) > 0 {
+		concurrent++
+	}
+
+	group := errgroup.Group{}
+	for _, database := range others {
+		database, databaseUtils := database, utils.Clone() // https://golang.org/doc/faq#closures_and_goroutines
+		group.Go(func() error {
+			return createDatabase(config, database, false, concurrent, databaseUtils)
+		})
+	}
+	if len(compiled) > 0 {
+		compiledUtils := utils.Clone()
+		group.Go(func() error {
+			for _, database := range compiled {
+				if err := createDatabase(config, database, len(config.BuildCommand) > 0, concurrent, compiledUtils); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	return group.Wait()
+}
+
+func createDatabase(config *codeqlExecuteScanOptions, database codeqlDatabase, withBuildCommand bool, concurrent int, utils codeqlExecuteScanUtils) error {
+	cmd := []string{"database", "create", database.path, "--overwrite", "--source-root", config.ModulePath, "--language=" + database.language}
+	cmd = append(cmd, getRamAndThreadsFromConfig(config, concurrent)...)
+
+	//codeql has an autobuilder which tries to build the project based on specified programming language
+	if withBuildCommand {
+		cmd = append(cmd, "--command="+config.BuildCommand)
+	}
+
+	if err := execute(utils, cmd, GeneralConfig.Verbose); err != nil {
+		log.Entry().Errorf("failed running command codeql database create for language %v", database.language)
+		return err
+	}
+	return nil
+}
+
+// analyzeDatabases analyzes the databases concurrently and merges the results of several languages into one SARIF and CSV report
+func analyzeDatabases(config *codeqlExecuteScanOptions, databases []codeqlDatabase, cache codeql.DatabaseCache, utils codeqlExecuteScanUtils) error {
+	sarifReport := filepath.Join(config.ModulePath, "target", "codeqlReport.sarif")
+	csvReport := filepath.Join(config.ModulePath, "target", "codeqlReport.csv")
+	if len(databases) == 1 {
+		return analyzeDatabase(config, databases[0], sarifReport, csvReport, "", 1, cache, utils)
+	}
+
+	group := errgroup.Group{}
+	for _, database := range databases {
+		database, databaseUtils := database, utils.Clone() // https://golang.org/doc/faq#closures_and_goroutines
+		group.Go(func() error {
+			return analyzeDatabase(config, database, languageReport(sarifReport, database.language), languageReport(csvReport, database.language), "/language:"+database.language, len(databases), cache, databaseUtils)
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return err
+	}
+
+	sarifs, csvs := [][]byte{}, [][]byte{}
+	for _, database := range databases {
+		sarif, err := utils.FileRead(languageReport(sarifReport, database.language))
+		if err != nil {
+			return errors.Wrapf(err, "failed to read SARIF report of language %v", database.language)
+		}
+		sarifs = append(sarifs, sarif)
+		csv, err := utils.FileRead(languageReport(csvReport, database.language))
+		if err != nil {
+			return errors.Wrapf(err, "failed to read CSV report of language %v", database.language)
+		}
+		csvs = append(csvs, csv)
+	}
+	merged, err := codeql.MergeSarif(sarifs)
+	if err != nil {
+		return errors.Wrap(err, "failed to merge SARIF reports")
+	}
+	if err := utils.FileWrite(sarifReport, merged, 0666); err != nil {
+		return errors.Wrap(err, "failed to write SARIF report")
+	}
+	if err := utils.FileWrite(csvReport, codeql.MergeCsv(csvs), 0666); err != nil {
+		return errors.Wrap(err, "failed to write CSV report")
+	}
+	return nil
+}
+
+func analyzeDatabase(config *codeqlExecuteScanOptions, database codeqlDatabase, sarifReport, csvReport, category string, concurrent int, cache codeql.DatabaseCache, utils codeqlExecuteScanUtils) error {
+	options := []string{}
+	if len(category) > 0 {
+		options = append(options, "--sarif-category="+category)
+	}
+	if cache.Enabled() {
+		options = append(options, "--common-caches="+cache.CommonCachesPath())
+	}
+
+	cmd := []string{"database", "analyze", "--format=sarif-latest", fmt.Sprintf("--output=%v", sarifReport)}
+	cmd = append(cmd, options...)
+	cmd = append(cmd, database.path)
+	cmd = append(cmd, getRamAndThreadsFromConfig(config, concurrent)...)
+	cmd = codeqlQuery(cmd, config.QuerySuite)
+	if err := execute(utils, cmd, GeneralConfig.Verbose); err != nil {
+		log.Entry().Error("failed running command codeql database analyze for sarif generation")
+		return err
+	}
+
+	cmd = []string{"database", "analyze", "--format=csv", fmt.Sprintf("--output=%v", csvReport)}
+	cmd = append(cmd, options...)
+	cmd = append(cmd, database.path)
+	cmd = append(cmd, getRamAndThreadsFromConfig(config, concurrent)...)
+	cmd = codeqlQuery(cmd, config.QuerySuite)
+	if err := execute(utils, cmd, GeneralConfig.Verbose); err != nil {
+		log.Entry().Error("failed running command codeql database analyze for csv generation")
+		return err
+	}
+	return nil
+}
+
+// languageReport returns the path of the report of a single language, e.g. target/codeqlReport-java.sarif
+func languageReport(report, language string) string {
+	extension := filepath.Ext(report)
+	return strings.TrimSuffix(report, extension) + "-" + language + extension
+}
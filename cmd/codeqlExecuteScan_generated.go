@@ -20,23 +20,26 @@ import (
 )
 
 type codeqlExecuteScanOptions struct {
-	GithubToken                 string `json:"githubToken,omitempty"`
-	BuildTool                   string `json:"buildTool,omitempty" validate:"possible-values=custom maven golang npm pip yarn"`
-	BuildCommand                string `json:"buildCommand,omitempty"`
-	Language                    string `json:"language,omitempty"`
-	ModulePath                  string `json:"modulePath,omitempty"`
-	Database                    string `json:"database,omitempty"`
-	QuerySuite                  string `json:"querySuite,omitempty"`
-	UploadResults               bool   `json:"uploadResults,omitempty"`
-	SarifCheckMaxRetries        int    `json:"sarifCheckMaxRetries,omitempty"`
-	SarifCheckRetryInterval     int    `json:"sarifCheckRetryInterval,omitempty"`
-	Threads                     string `json:"threads,omitempty"`
-	Ram                         string `json:"ram,omitempty"`
-	AnalyzedRef                 string `json:"analyzedRef,omitempty"`
-	Repository                  string `json:"repository,omitempty"`
-	CommitID                    string `json:"commitId,omitempty"`
-	VulnerabilityThresholdTotal int    `json:"vulnerabilityThresholdTotal,omitempty"`
-	CheckForCompliance          bool   `json:"checkForCompliance,omitempty"`
+	GithubToken                    string `json:"githubToken,omitempty"`
+	BuildTool                      string `json:"buildTool,omitempty" validate:"possible-values=custom maven golang npm pip yarn"`
+	BuildCommand                   string `json:"buildCommand,omitempty"`
+	Language                       string `json:"language,omitempty"`
+	ModulePath                     string `json:"modulePath,omitempty"`
+	Database                       string `json:"database,omitempty"`
+	CacheDirectory                 string `json:"cacheDirectory,omitempty"`
+	QuerySuite                     string `json:"querySuite,omitempty"`
+	UploadResults                  bool   `json:"uploadResults,omitempty"`
+	SarifCheckMaxRetries           int    `json:"sarifCheckMaxRetries,omitempty"`
+	SarifCheckRetryInterval        int    `json:"sarifCheckRetryInterval,omitempty"`
+	Threads                        string `json:"threads,omitempty"`
+	Ram                            string `json:"ram,omitempty"`
+	AnalyzedRef                    string `json:"analyzedRef,omitempty"`
+	Repository                     string `json:"repository,omitempty"`
+	CommitID                       string `json:"commitId,omitempty"`
+	VulnerabilityThresholdTotal    int    `json:"vulnerabilityThresholdTotal,omitempty"`
+	CheckForCompliance             bool   `json:"checkForCompliance,omitempty"`
+	SarifBaselineLocation          string `json:"sarifBaselineLocation,omitempty"`
+	EnforceThresholdsOnNewFindings bool   `json:"enforceThresholdsOnNewFindings,omitempty"`
 }
 
 type codeqlExecuteScanReports struct {
@@ -53,6 +56,7 @@ func (p *codeqlExecuteScanReports) persist(stepConfig codeqlExecuteScanOptions,
 		{FilePattern: "**/*.sarif", ParamRef: "", StepResultType: "codeql"},
 		{FilePattern: "**/toolrun_codeql_*.json", ParamRef: "", StepResultType: "codeql"},
 		{FilePattern: "**/piper_codeql_report.json", ParamRef: "", StepResultType: "codeql"},
+		{FilePattern: "**/piper_differential_report.html", ParamRef: "", StepResultType: "codeql"},
 	}
 	envVars := []gcs.EnvVar{
 		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
@@ -147,6 +151,7 @@ and Java plus Maven.`,
 			handler := func() {
 				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
 				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
 				stepTelemetryData.PiperCommitHash = GitCommit
@@ -172,7 +177,11 @@ and Java plus Maven.`,
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
-			codeqlExecuteScan(stepConfig, &stepTelemetryData)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+			}, func() {
+				codeqlExecuteScan(stepConfig, &stepTelemetryData)
+			})
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
@@ -186,20 +195,23 @@ func addCodeqlExecuteScanFlags(cmd *cobra.Command, stepConfig *codeqlExecuteScan
 	cmd.Flags().StringVar(&stepConfig.GithubToken, "githubToken", os.Getenv("PIPER_githubToken"), "GitHub personal access token in plain text. NEVER set this parameter in a file commited to a source code repository. This parameter is intended to be used from the command line or set securely via the environment variable listed below. In most pipeline use-cases, you should instead either store the token in Vault (where it can be automatically retrieved by the step from one of the paths listed below) or store it as a Jenkins secret and configure the secret's id via the `githubTokenCredentialsId` parameter.")
 	cmd.Flags().StringVar(&stepConfig.BuildTool, "buildTool", `maven`, "Defines the build tool which is used for building the project.")
 	cmd.Flags().StringVar(&stepConfig.BuildCommand, "buildCommand", os.Getenv("PIPER_buildCommand"), "Command to build the project")
-	cmd.Flags().StringVar(&stepConfig.Language, "language", os.Getenv("PIPER_language"), "The programming language used to analyze.")
+	cmd.Flags().StringVar(&stepConfig.Language, "language", os.Getenv("PIPER_language"), "The programming language used to analyze. Several languages can be provided as comma separated list, e.g. `java,javascript`.")
 	cmd.Flags().StringVar(&stepConfig.ModulePath, "modulePath", `./`, "Allows providing the path for the module to scan")
 	cmd.Flags().StringVar(&stepConfig.Database, "database", `codeqlDB`, "Path to the CodeQL database to create. This directory will be created, and must not already exist.")
+	cmd.Flags().StringVar(&stepConfig.CacheDirectory, "cacheDirectory", os.Getenv("PIPER_cacheDirectory"), "Directory for caching the CodeQL databases and query packs, e.g. `.pipeline/cache/codeql`.")
 	cmd.Flags().StringVar(&stepConfig.QuerySuite, "querySuite", os.Getenv("PIPER_querySuite"), "The name of a CodeQL query suite. If omitted, the default query suite for the language of the database being analyzed will be used.")
 	cmd.Flags().BoolVar(&stepConfig.UploadResults, "uploadResults", false, "Allows you to upload codeql SARIF results to your github project. You will need to set githubToken for this.")
 	cmd.Flags().IntVar(&stepConfig.SarifCheckMaxRetries, "sarifCheckMaxRetries", 10, "Maximum number of retries when waiting for the server to finish processing the SARIF upload.")
 	cmd.Flags().IntVar(&stepConfig.SarifCheckRetryInterval, "sarifCheckRetryInterval", 30, "Interval in seconds between retries when waiting for the server to finish processing the SARIF upload.")
-	cmd.Flags().StringVar(&stepConfig.Threads, "threads", `0`, "Use this many threads for the codeql operations.")
-	cmd.Flags().StringVar(&stepConfig.Ram, "ram", os.Getenv("PIPER_ram"), "Use this much ram (MB) for the codeql operations.")
+	cmd.Flags().StringVar(&stepConfig.Threads, "threads", `0`, "Use this many threads for the codeql operations. 0 uses one thread per core. In case of several languages, the threads are shared among the codeql processes running concurrently.")
+	cmd.Flags().StringVar(&stepConfig.Ram, "ram", os.Getenv("PIPER_ram"), "Use this much ram (MB) for the codeql operations. In case of several languages, the ram is shared among the codeql processes running concurrently.")
 	cmd.Flags().StringVar(&stepConfig.AnalyzedRef, "analyzedRef", os.Getenv("PIPER_analyzedRef"), "Name of the ref that was analyzed.")
 	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "URL of the GitHub instance")
 	cmd.Flags().StringVar(&stepConfig.CommitID, "commitId", os.Getenv("PIPER_commitId"), "SHA of commit that was analyzed.")
 	cmd.Flags().IntVar(&stepConfig.VulnerabilityThresholdTotal, "vulnerabilityThresholdTotal", 0, "Threashold for maximum number of allowed vulnerabilities.")
 	cmd.Flags().BoolVar(&stepConfig.CheckForCompliance, "checkForCompliance", false, "If set to true, the piper step checks for compliance based on vulnerability threadholds. Example - If total vulnerabilites are 10 and vulnerabilityThresholdTotal is set as 0, then the steps throws an compliance error.")
+	cmd.Flags().StringVar(&stepConfig.SarifBaselineLocation, "sarifBaselineLocation", os.Getenv("PIPER_sarifBaselineLocation"), "Location of the SARIF baselines, either a directory or a Google Cloud Storage folder (`gs://<bucket>/<folder>`). On pull requests, the SARIF results are compared with the baseline of the target branch, otherwise they are stored as baseline of the current branch.")
+	cmd.Flags().BoolVar(&stepConfig.EnforceThresholdsOnNewFindings, "enforceThresholdsOnNewFindings", false, "On pull requests, enforce the vulnerability thresholds only on findings which are not contained in the SARIF baseline of the target branch. Requires `sarifBaselineLocation`.")
 
 	cmd.MarkFlagRequired("buildTool")
 }
@@ -288,6 +300,15 @@ func codeqlExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     `codeqlDB`,
 					},
+					{
+						Name:        "cacheDirectory",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_cacheDirectory"),
+					},
 					{
 						Name:        "querySuite",
 						ResourceRef: []config.ResourceReference{},
@@ -396,6 +417,24 @@ func codeqlExecuteScanMetadata() config.StepData {
 					{
 						Name:        "checkForCompliance",
 						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "sarifBaselineLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_sarifBaselineLocation"),
+					},
+					{
+						Name:        "enforceThresholdsOnNewFindings",
+						ResourceRef: []config.ResourceReference{},
 						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
 						Type:        "bool",
 						Mandatory:   false,
@@ -417,6 +456,7 @@ func codeqlExecuteScanMetadata() config.StepData {
 							{"filePattern": "**/*.sarif", "type": "codeql"},
 							{"filePattern": "**/toolrun_codeql_*.json", "type": "codeql"},
 							{"filePattern": "**/piper_codeql_report.json", "type": "codeql"},
+							{"filePattern": "**/piper_differential_report.html", "type": "codeql"},
 						},
 					},
 				},
@@ -4,7 +4,10 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
@@ -18,16 +21,36 @@ import (
 type codeqlExecuteScanMockUtils struct {
 	*mock.ExecMockRunner
 	*mock.FilesMock
+
+	// clones records the command runners of the clones in order to verify their calls
+	clones *[]*mock.ExecMockRunner
 }
 
 func newCodeqlExecuteScanTestsUtils() codeqlExecuteScanMockUtils {
 	utils := codeqlExecuteScanMockUtils{
 		ExecMockRunner: &mock.ExecMockRunner{},
 		FilesMock:      &mock.FilesMock{},
+		clones:         &[]*mock.ExecMockRunner{},
 	}
 	return utils
 }
 
+func (c codeqlExecuteScanMockUtils) Clone() codeqlExecuteScanUtils {
+	clone := c
+	clone.ExecMockRunner = &mock.ExecMockRunner{ShouldFailOnCommand: c.ShouldFailOnCommand}
+	*c.clones = append(*c.clones, clone.ExecMockRunner)
+	return clone
+}
+
+// allCalls returns the calls of the utils and all their clones
+func (c codeqlExecuteScanMockUtils) allCalls() []mock.ExecCall {
+	calls := append([]mock.ExecCall{}, c.Calls...)
+	for _, clone := range *c.clones {
+		calls = append(calls, clone.Calls...)
+	}
+	return calls
+}
+
 func TestRunCodeqlExecuteScan(t *testing.T) {
 
 	t.Run("Valid CodeqlExecuteScan", func(t *testing.T) {
@@ -71,6 +94,128 @@ func TestRunCodeqlExecuteScan(t *testing.T) {
 		_, err := runCodeqlExecuteScan(&config, nil, newCodeqlExecuteScanTestsUtils())
 		assert.NoError(t, err)
 	})
+
+	t.Run("Several languages", func(t *testing.T) {
+		config := codeqlExecuteScanOptions{BuildTool: "maven", Language: "java, javascript,python", BuildCommand: "mvn install", ModulePath: "./", Database: "codeqlDB"}
+		utils := newCodeqlExecuteScanTestsUtils()
+		for _, language := range []string{"java", "javascript", "python"} {
+			utils.AddFile(filepath.Join("target", "codeqlReport-"+language+".sarif"), []byte(`{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "CodeQL"}}, "properties": {"language": "`+language+`"}}]}`))
+			utils.AddFile(filepath.Join("target", "codeqlReport-"+language+".csv"), []byte(`"Finding","`+language+`"`))
+		}
+
+		_, err := runCodeqlExecuteScan(&config, nil, utils)
+
+		if assert.NoError(t, err) {
+			calls := utils.allCalls()
+			assert.Contains(t, calls, mock.ExecCall{Exec: "codeql", Params: []string{"database", "create", filepath.Join("codeqlDB", "java"), "--overwrite", "--source-root", "./", "--language=java", "--command=mvn install"}})
+			assert.Contains(t, calls, mock.ExecCall{Exec: "codeql", Params: []string{"database", "create", filepath.Join("codeqlDB", "javascript"), "--overwrite", "--source-root", "./", "--language=javascript"}})
+			assert.Contains(t, calls, mock.ExecCall{Exec: "codeql", Params: []string{"database", "create", filepath.Join("codeqlDB", "python"), "--overwrite", "--source-root", "./", "--language=python"}})
+			assert.Contains(t, calls, mock.ExecCall{Exec: "codeql", Params: []string{"database", "analyze", "--format=sarif-latest", "--output=" + filepath.Join("target", "codeqlReport-java.sarif"), "--sarif-category=/language:java", filepath.Join("codeqlDB", "java")}})
+			assert.Len(t, calls, 9)
+
+			sarif, err := utils.FileRead(filepath.Join("target", "codeqlReport.sarif"))
+			assert.NoError(t, err)
+			assert.Len(t, sarifRuns(t, sarif), 3)
+			csv, err := utils.FileRead(filepath.Join("target", "codeqlReport.csv"))
+			assert.NoError(t, err)
+			assert.Equal(t, "\"Finding\",\"java\"\n\"Finding\",\"javascript\"\n\"Finding\",\"python\"\n", string(csv))
+		}
+	})
+
+	t.Run("Several languages - ram and threads are shared", func(t *testing.T) {
+		config := codeqlExecuteScanOptions{BuildTool: "custom", Language: "java,javascript,python", ModulePath: "./", Database: "codeqlDB", Threads: "6", Ram: "6000"}
+		utils := newCodeqlExecuteScanTestsUtils()
+		for _, language := range []string{"java", "javascript", "python"} {
+			utils.AddFile(filepath.Join("target", "codeqlReport-"+language+".sarif"), []byte(`{"version": "2.1.0", "runs": []}`))
+			utils.AddFile(filepath.Join("target", "codeqlReport-"+language+".csv"), []byte(""))
+		}
+
+		_, err := runCodeqlExecuteScan(&config, nil, utils)
+
+		if assert.NoError(t, err) {
+			calls := utils.allCalls()
+			assert.Contains(t, calls, mock.ExecCall{Exec: "codeql", Params: []string{"database", "create", filepath.Join("codeqlDB", "java"), "--overwrite", "--source-root", "./", "--language=java", "--threads=2", "--ram=2000"}})
+			assert.Contains(t, calls, mock.ExecCall{Exec: "codeql", Params: []string{"database", "analyze", "--format=csv", "--output=" + filepath.Join("target", "codeqlReport-python.csv"), "--sarif-category=/language:python", filepath.Join("codeqlDB", "python"), "--threads=2", "--ram=2000"}})
+		}
+	})
+
+	t.Run("Several languages - database creation fails", func(t *testing.T) {
+		config := codeqlExecuteScanOptions{BuildTool: "custom", Language: "go,ruby", ModulePath: "./", Database: "codeqlDB"}
+		utils := newCodeqlExecuteScanTestsUtils()
+		utils.ShouldFailOnCommand = map[string]error{"codeql database create " + filepath.Join("codeqlDB", "go"): fmt.Errorf("build failed")}
+
+		_, err := runCodeqlExecuteScan(&config, nil, utils)
+
+		assert.EqualError(t, err, "build failed")
+	})
+
+	t.Run("Cached database", func(t *testing.T) {
+		config := codeqlExecuteScanOptions{BuildTool: "maven", ModulePath: "./", Database: "codeqlDB", CacheDirectory: "cache", CommitID: "abc123"}
+		utils := newCodeqlExecuteScanTestsUtils()
+		utils.AddFile(filepath.Join("cache", "abc123", "java", "codeql-database.yml"), []byte("primaryLanguage: java\nfinalised: true\n"))
+		utils.AddDir(filepath.Join("cache", "0ld"))
+		utils.AddDir(filepath.Join("cache", "abc123"))
+
+		_, err := runCodeqlExecuteScan(&config, nil, utils)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []mock.ExecCall{
+				{Exec: "codeql", Params: []string{"database", "analyze", "--format=sarif-latest", "--output=" + filepath.Join("target", "codeqlReport.sarif"), "--common-caches=" + filepath.Join("cache", "common"), filepath.Join("cache", "abc123", "java")}},
+				{Exec: "codeql", Params: []string{"database", "analyze", "--format=csv", "--output=" + filepath.Join("target", "codeqlReport.csv"), "--common-caches=" + filepath.Join("cache", "common"), filepath.Join("cache", "abc123", "java")}},
+			}, utils.Calls)
+			exists, _ := utils.DirExists(filepath.Join("cache", "0ld"))
+			assert.False(t, exists, "databases of other commits are expected to be removed")
+		}
+	})
+
+	t.Run("Database not finalized in cache", func(t *testing.T) {
+		config := codeqlExecuteScanOptions{BuildTool: "maven", ModulePath: "./", Database: "codeqlDB", CacheDirectory: "cache", CommitID: "abc123"}
+		utils := newCodeqlExecuteScanTestsUtils()
+		utils.AddFile(filepath.Join("cache", "abc123", "java", "codeql-database.yml"), []byte("primaryLanguage: java\n"))
+
+		_, err := runCodeqlExecuteScan(&config, nil, utils)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, mock.ExecCall{Exec: "codeql", Params: []string{"database", "create", filepath.Join("cache", "abc123", "java"), "--overwrite", "--source-root", "./", "--language=java"}}, utils.Calls[0])
+		}
+	})
+}
+
+func TestGetRamAndThreadsFromConfig(t *testing.T) {
+	t.Run("Single process", func(t *testing.T) {
+		config := codeqlExecuteScanOptions{Threads: "0", Ram: "4000"}
+		assert.Equal(t, []string{"--threads=0", "--ram=4000"}, getRamAndThreadsFromConfig(&config, 1))
+	})
+
+	t.Run("Concurrent processes", func(t *testing.T) {
+		config := codeqlExecuteScanOptions{Threads: "5", Ram: "4000"}
+		assert.Equal(t, []string{"--threads=2", "--ram=2000"}, getRamAndThreadsFromConfig(&config, 2))
+	})
+
+	t.Run("Concurrent processes with one thread per core", func(t *testing.T) {
+		config := codeqlExecuteScanOptions{Threads: "0"}
+		expected := runtime.NumCPU() / 2
+		if expected < 1 {
+			expected = 1
+		}
+		assert.Equal(t, []string{fmt.Sprintf("--threads=%v", expected)}, getRamAndThreadsFromConfig(&config, 2))
+	})
+
+	t.Run("More concurrent processes than threads", func(t *testing.T) {
+		config := codeqlExecuteScanOptions{Threads: "2"}
+		assert.Equal(t, []string{"--threads=1"}, getRamAndThreadsFromConfig(&config, 3))
+	})
+
+	t.Run("Not configured", func(t *testing.T) {
+		assert.Empty(t, getRamAndThreadsFromConfig(&codeqlExecuteScanOptions{}, 3))
+	})
+}
+
+func sarifRuns(t *testing.T, sarif []byte) []interface{} {
+	document := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(sarif, &document))
+	runs, _ := document["runs"].([]interface{})
+	return runs
 }
 
 func TestGetGitRepoInfo(t *testing.T) {
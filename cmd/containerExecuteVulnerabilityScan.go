@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/imagescan"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/osv"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/sbom"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+// containerScanReportsDirectory defines the subfolder for the reports of the container vulnerability scan
+const containerScanReportsDirectory = "containerScan"
+
+type containerExecuteVulnerabilityScanUtils interface {
+	piperutils.FileUtils
+
+	ScanImage(path string) (*imagescan.Inventory, error)
+}
+
+type containerExecuteVulnerabilityScanUtilsBundle struct {
+	*piperutils.Files
+}
+
+func (c *containerExecuteVulnerabilityScanUtilsBundle) ScanImage(path string) (*imagescan.Inventory, error) {
+	return imagescan.Scan(path)
+}
+
+func newContainerExecuteVulnerabilityScanUtils() containerExecuteVulnerabilityScanUtils {
+	utils := containerExecuteVulnerabilityScanUtilsBundle{
+		Files: &piperutils.Files{},
+	}
+	return &utils
+}
+
+func containerExecuteVulnerabilityScan(config containerExecuteVulnerabilityScanOptions, _ *telemetry.CustomData) {
+	utils := newContainerExecuteVulnerabilityScanUtils()
+
+	err := runContainerExecuteVulnerabilityScan(&config, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runContainerExecuteVulnerabilityScan(config *containerExecuteVulnerabilityScanOptions, utils containerExecuteVulnerabilityScanUtils) error {
+	cvssSeverityLimit, err := strconv.ParseFloat(config.CvssSeverityLimit, 64)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrapf(err, "failed to parse parameter cvssSeverityLimit (%s) as floating point number", config.CvssSeverityLimit)
+	}
+
+	inventory, err := utils.ScanImage(config.ScanPath)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrapf(err, "failed to analyze '%v'", config.ScanPath)
+	}
+	if inventory.Distribution != nil {
+		log.Entry().Infof("Detected distribution %v %v", inventory.Distribution.ID, inventory.Distribution.VersionID)
+	}
+	log.Entry().Infof("%v packages detected in %v", len(inventory.Packages), config.ScanPath)
+
+	db, err := osv.LoadDatabase(config.VulnerabilityDatabasePath, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "failed to load vulnerability database")
+	}
+
+	findings := []osv.Finding{}
+	for _, pkg := range inventory.Packages {
+		purl := pkg.Purl()
+		for _, match := range db.Query(purl) {
+			findings = append(findings, osv.Finding{
+				Vulnerability: match.Vulnerability,
+				Purl:          purl,
+				FixedVersions: match.FixedVersions,
+				BOMFile:       pkg.Location,
+			})
+		}
+	}
+
+	assessments := readAssessmentsFromFile(config.AssessmentFile, utils)
+	findings, assessedFindings := filterAssessedFindings(findings, assessments)
+
+	reportPaths, err := writeContainerVulnerabilityReports(config, inventory, findings, assessedFindings, cvssSeverityLimit, utils)
+	piperutils.PersistReportsAndLinks("containerExecuteVulnerabilityScan", "", utils, reportPaths, nil)
+	if err != nil {
+		return err
+	}
+
+	severeVulnerabilities, nonSevereVulnerabilities := osv.CountSecurityVulnerabilities(findings, cvssSeverityLimit)
+	if nonSevereVulnerabilities > 0 {
+		log.Entry().Warnf("WARNING: %v Open Source Software Security vulnerabilities with CVSS score below threshold %.1f detected.", nonSevereVulnerabilities, cvssSeverityLimit)
+	} else if len(findings) == 0 {
+		log.Entry().Info("No Open Source Software Security vulnerabilities detected")
+	}
+	if severeVulnerabilities > 0 {
+		if config.FailOnSevereVulnerabilities {
+			log.SetErrorCategory(log.ErrorCompliance)
+			return fmt.Errorf("%v Open Source Software Security vulnerabilities with CVSS score greater or equal to %.1f detected", severeVulnerabilities, cvssSeverityLimit)
+		}
+		log.Entry().Infof("%v Open Source Software Security vulnerabilities with CVSS score greater or equal to %.1f detected", severeVulnerabilities, cvssSeverityLimit)
+		log.Entry().Info("Step will only create data but not fail due to setting failOnSevereVulnerabilities: false")
+	}
+	return nil
+}
+
+func writeContainerVulnerabilityReports(config *containerExecuteVulnerabilityScanOptions, inventory *imagescan.Inventory, findings, assessedFindings []osv.Finding, cvssSeverityLimit float64, utils containerExecuteVulnerabilityScanUtils) ([]piperutils.Path, error) {
+	imageName, imageVersion := containerScanImageName(config)
+	reportPaths := []piperutils.Path{}
+
+	if err := utils.MkdirAll(containerScanReportsDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to create report directory")
+	}
+	bomPath := filepath.Join(containerScanReportsDirectory, "bom-container.json")
+	if err := sbom.WriteBOM(inventory.ToBOM(imageName, imageVersion), bomPath, utils); err != nil {
+		return reportPaths, err
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Container SBOM", Target: bomPath})
+
+	scanReport := osv.CreateCustomVulnerabilityReport(config.VulnerabilityDatabasePath, []string{config.ScanPath}, findings, assessedFindings, cvssSeverityLimit)
+	scanReport.ReportTitle = "Container Image Security Vulnerability Report"
+	scanReport.Subheaders = []reporting.Subheader{
+		{Description: "Vulnerability database", Details: config.VulnerabilityDatabasePath},
+		{Description: "Scanned image", Details: config.ScanPath},
+		{Description: "Number of packages", Details: fmt.Sprint(len(inventory.Packages))},
+	}
+	if inventory.Distribution != nil {
+		scanReport.Subheaders = append(scanReport.Subheaders, reporting.Subheader{Description: "Distribution", Details: strings.TrimSpace(fmt.Sprintf("%v %v", inventory.Distribution.ID, inventory.Distribution.VersionID))})
+	}
+	// findings refer to the file the package was detected in instead of an SBOM
+	for i, header := range scanReport.DetailTable.Headers {
+		if header == "SBOM" {
+			scanReport.DetailTable.Headers[i] = "Location"
+		}
+	}
+	paths, err := writeContainerScanReport(scanReport, config.ScanPath, utils)
+	reportPaths = append(reportPaths, paths...)
+	if err != nil {
+		return reportPaths, errors.Wrap(err, "failed to write vulnerability report")
+	}
+
+	// assessed findings are part of the SARIF file including their audit state
+	sarif := osv.CreateSarifResultFile(append(append([]osv.Finding{}, findings...), assessedFindings...))
+	sarif.Runs[0].Tool.Driver.Name = "Piper container vulnerability scan"
+	sarif.Runs[0].Conversion.Tool.Driver.Name = "Piper container scan to SARIF converter"
+	paths, err = writeContainerScanSarif(sarif, utils)
+	reportPaths = append(reportPaths, paths...)
+	if err != nil {
+		return reportPaths, errors.Wrap(err, "failed to write SARIF file")
+	}
+
+	vexPaths, err := format.WriteVEX(containerScanReportsDirectory, "container vulnerability scan", osv.CreateVEXStatements(assessedFindings), utils)
+	reportPaths = append(reportPaths, vexPaths...)
+	if err != nil {
+		return reportPaths, err
+	}
+	return reportPaths, nil
+}
+
+// containerScanImageName returns name and version of the scanned image, falling back to the name of the scanned file or directory
+func containerScanImageName(config *containerExecuteVulnerabilityScanOptions) (string, string) {
+	if len(config.ContainerImageNameTag) > 0 {
+		if i := strings.LastIndex(config.ContainerImageNameTag, ":"); i > strings.LastIndex(config.ContainerImageNameTag, "/") {
+			return config.ContainerImageNameTag[:i], config.ContainerImageNameTag[i+1:]
+		}
+		return config.ContainerImageNameTag, ""
+	}
+	return strings.TrimSuffix(filepath.Base(config.ScanPath), ".tar"), ""
+}
+
+func writeContainerScanReport(scanReport reporting.ScanReport, scanPath string, utils containerExecuteVulnerabilityScanUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+
+	// ignore templating errors since template is in our hands and issues will be detected with the automated tests
+	htmlReport, _ := scanReport.ToHTML()
+	htmlReportPath := filepath.Join(containerScanReportsDirectory, "piper_container_vulnerability_report.html")
+	if err := utils.FileWrite(htmlReportPath, htmlReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrap(err, "failed to write html report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Container Vulnerability Report", Target: htmlReportPath})
+
+	// JSON reports are used by step pipelineCreateSummary in order to e.g. prepare an issue creation in GitHub
+	// ignore JSON errors since structure is in our hands
+	jsonReport, _ := scanReport.ToJSON()
+	if exists, _ := utils.DirExists(reporting.StepReportDirectory); !exists {
+		if err := utils.MkdirAll(reporting.StepReportDirectory, 0777); err != nil {
+			return reportPaths, errors.Wrap(err, "failed to create step reporting directory")
+		}
+	}
+	reportSha := fmt.Sprintf("%x", sha1.Sum([]byte(scanPath)))
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, fmt.Sprintf("containerExecuteVulnerabilityScan_oss_%v.json", reportSha)), jsonReport, 0666); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to write json report")
+	}
+	return reportPaths, nil
+}
+
+func writeContainerScanSarif(sarif *format.SARIF, utils containerExecuteVulnerabilityScanUtils) ([]piperutils.Path, error) {
+	// ignore JSON errors since structure is in our hands
+	sarifReport, _ := json.Marshal(sarif)
+	sarifReportPath := filepath.Join(containerScanReportsDirectory, "piper_container_vulnerability.sarif")
+	if err := utils.FileWrite(sarifReportPath, sarifReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrap(err, "failed to write SARIF file")
+	}
+	return []piperutils.Path{{Name: "Container Vulnerability SARIF file", Target: sarifReportPath}}, nil
+}
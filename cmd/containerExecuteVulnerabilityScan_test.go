@@ -0,0 +1,159 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/imagescan"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type containerExecuteVulnerabilityScanMockUtils struct {
+	*mock.FilesMock
+	inventory *imagescan.Inventory
+}
+
+func (c *containerExecuteVulnerabilityScanMockUtils) ScanImage(path string) (*imagescan.Inventory, error) {
+	if path != "app.tar" {
+		return nil, fmt.Errorf("failed to access '%v'", path)
+	}
+	return c.inventory, nil
+}
+
+func newContainerExecuteVulnerabilityScanTestsUtils(t *testing.T) *containerExecuteVulnerabilityScanMockUtils {
+	utils := &containerExecuteVulnerabilityScanMockUtils{
+		FilesMock: &mock.FilesMock{},
+		inventory: &imagescan.Inventory{
+			Distribution: &imagescan.Distribution{ID: "debian", VersionID: "12"},
+			Packages: []imagescan.Package{
+				{Type: "npm", Name: "lodash", Version: "4.17.20", Location: "app/node_modules/lodash/package.json"},
+				{Type: "deb", Namespace: "debian", Name: "libssl3", Version: "3.0.11-1~deb12u2", Qualifiers: map[string]string{"distro": "debian-12", "upstream": "openssl"}, Location: "var/lib/dpkg/status"},
+				{Type: "deb", Namespace: "debian", Name: "libc6", Version: "2.36-9+deb12u1", Qualifiers: map[string]string{"distro": "debian-12", "upstream": "glibc"}, Location: "var/lib/dpkg/status"},
+			},
+		},
+	}
+	record, err := os.ReadFile(filepath.Join("..", "pkg", "osv", "testdata", "GHSA-test-lodash.json"))
+	require.NoError(t, err)
+	utils.AddFile("osv/npm/GHSA-35jh-r3h4-6jhm.json", record)
+	utils.AddFile("osv/debian/DSA-1.json", []byte(`{
+  "id": "DSA-1",
+  "aliases": ["CVE-2023-0001"],
+  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:L/AC:H/PR:L/UI:N/S:U/C:L/I:N/A:N"}],
+  "affected": [
+    {"package": {"ecosystem": "Debian:12", "name": "openssl"}, "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "3.0.11-1~deb12u2"}]}]},
+    {"package": {"ecosystem": "Debian:12", "name": "glibc"}, "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "2.36-9+deb12u3"}]}]}
+  ]
+}`))
+	return utils
+}
+
+func TestRunContainerExecuteVulnerabilityScan(t *testing.T) {
+	t.Parallel()
+
+	config := containerExecuteVulnerabilityScanOptions{
+		ScanPath:                    "app.tar",
+		ContainerImageNameTag:       "my.registry.com/app:1.0",
+		VulnerabilityDatabasePath:   "osv",
+		AssessmentFile:              "hs-assessments.yaml",
+		CvssSeverityLimit:           "7.0",
+		FailOnSevereVulnerabilities: true,
+	}
+
+	t.Run("error case - severe vulnerability", func(t *testing.T) {
+		t.Parallel()
+		utils := newContainerExecuteVulnerabilityScanTestsUtils(t)
+
+		err := runContainerExecuteVulnerabilityScan(&config, utils)
+
+		assert.EqualError(t, err, "1 Open Source Software Security vulnerabilities with CVSS score greater or equal to 7.0 detected")
+		sarif, err := utils.FileRead(filepath.Join("containerScan", "piper_container_vulnerability.sarif"))
+		require.NoError(t, err)
+		assert.Contains(t, string(sarif), `"name":"Piper container vulnerability scan"`)
+		assert.Contains(t, string(sarif), `"uri":"var/lib/dpkg/status"`)
+		// libssl3 is already fixed, only the source package glibc of libc6 is affected
+		assert.Contains(t, string(sarif), "DSA-1 affects pkg:deb/debian/libc6@2.36-9+deb12u1?distro=debian-12")
+		assert.NotContains(t, string(sarif), "affects pkg:deb/debian/libssl3")
+		assert.True(t, utils.HasWrittenFile(filepath.Join("containerScan", "piper_container_vulnerability_report.html")))
+
+		bom, err := utils.FileRead(filepath.Join("containerScan", "bom-container.json"))
+		require.NoError(t, err)
+		assert.Contains(t, string(bom), `"name": "my.registry.com/app"`)
+		assert.Contains(t, string(bom), `"purl": "pkg:npm/lodash@4.17.20"`)
+	})
+
+	t.Run("success case - vulnerability assessed", func(t *testing.T) {
+		t.Parallel()
+		utils := newContainerExecuteVulnerabilityScanTestsUtils(t)
+		utils.AddFile("hs-assessments.yaml", []byte(`ignore:
+  - vulnerability: CVE-2021-23337
+    status: notRelevant
+    analysis: notUsed
+    purls:
+      - purl: pkg:npm/lodash@4.17.20
+`))
+
+		err := runContainerExecuteVulnerabilityScan(&config, utils)
+
+		assert.NoError(t, err)
+		sarif, err := utils.FileRead(filepath.Join("containerScan", "piper_container_vulnerability.sarif"))
+		require.NoError(t, err)
+		assert.Contains(t, string(sarif), `"unifiedAuditState":"notRelevant"`)
+		assert.True(t, utils.HasWrittenFile(filepath.Join("containerScan", "piper_vex.json")))
+	})
+
+	t.Run("success case - do not fail on severe vulnerabilities", func(t *testing.T) {
+		t.Parallel()
+		utils := newContainerExecuteVulnerabilityScanTestsUtils(t)
+		noFailConfig := config
+		noFailConfig.FailOnSevereVulnerabilities = false
+
+		err := runContainerExecuteVulnerabilityScan(&noFailConfig, utils)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("error case - scan failed", func(t *testing.T) {
+		t.Parallel()
+		utils := newContainerExecuteVulnerabilityScanTestsUtils(t)
+		missingConfig := config
+		missingConfig.ScanPath = "missing.tar"
+
+		err := runContainerExecuteVulnerabilityScan(&missingConfig, utils)
+
+		assert.EqualError(t, err, "failed to analyze 'missing.tar': failed to access 'missing.tar'")
+	})
+
+	t.Run("error case - invalid limit", func(t *testing.T) {
+		t.Parallel()
+		utils := newContainerExecuteVulnerabilityScanTestsUtils(t)
+		invalidConfig := config
+		invalidConfig.CvssSeverityLimit = "high"
+
+		err := runContainerExecuteVulnerabilityScan(&invalidConfig, utils)
+
+		assert.Contains(t, err.Error(), "failed to parse parameter cvssSeverityLimit (high)")
+	})
+}
+
+func TestContainerScanImageName(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		nameTag, scanPath, name, version string
+	}{
+		{nameTag: "my.registry.com:5000/app:1.0", name: "my.registry.com:5000/app", version: "1.0"},
+		{nameTag: "my.registry.com:5000/app", name: "my.registry.com:5000/app"},
+		{scanPath: "images/app.tar", name: "app"},
+	}
+	for _, test := range tt {
+		name, version := containerScanImageName(&containerExecuteVulnerabilityScanOptions{ContainerImageNameTag: test.nameTag, ScanPath: test.scanPath})
+		assert.Equal(t, test.name, name)
+		assert.Equal(t, test.version, version)
+	}
+}
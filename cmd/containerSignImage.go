@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/cosign"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/provenance"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+type containerSignImageUtils interface {
+	piperutils.FileUtils
+
+	SignImage(image string, signer *provenance.Signer) error
+}
+
+type containerSignImageUtilsBundle struct {
+	*piperutils.Files
+}
+
+func (c *containerSignImageUtilsBundle) SignImage(image string, signer *provenance.Signer) error {
+	return cosign.SignImage(image, signer, nil, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+}
+
+func newContainerSignImageUtils() containerSignImageUtils {
+	utils := containerSignImageUtilsBundle{
+		Files: &piperutils.Files{},
+	}
+	return &utils
+}
+
+func containerSignImage(config containerSignImageOptions, _ *telemetry.CustomData) {
+	utils := newContainerSignImageUtils()
+
+	if len(config.DockerConfigJSON) > 0 {
+		if err := setDockerConfigEnv(config.DockerConfigJSON, utils); err != nil {
+			log.Entry().WithError(err).Fatal("step execution failed")
+		}
+	}
+
+	err := runContainerSignImage(&config, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runContainerSignImage(config *containerSignImageOptions, utils containerSignImageUtils) error {
+	keyPEM, err := utils.FileRead(config.SigningKey)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "failed to read signing key")
+	}
+	signer, err := cosign.NewSigner(keyPEM, []byte(config.SigningKeyPassword))
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "invalid signing key")
+	}
+
+	images, err := containerImageDigestReferences(config.ContainerRegistryURL, config.ContainerImageNameTags, config.ContainerImageDigests)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	if len(images) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("no container images found to sign")
+	}
+
+	for _, image := range images {
+		if err := utils.SignImage(image, signer); err != nil {
+			return errors.Wrapf(err, "failed to sign image '%v'", image)
+		}
+		log.Entry().Infof("Image '%v' signed", image)
+	}
+	return nil
+}
+
+// containerImageDigestReferences combines the images and digests of the common pipeline environment to references by digest, e.g. my.registry.com/app@sha256:<hash>
+func containerImageDigestReferences(registryURL string, imageNameTags, imageDigests []string) ([]string, error) {
+	if len(imageNameTags) != len(imageDigests) {
+		return nil, fmt.Errorf("number of container images (%v) does not match the number of image digests (%v)", len(imageNameTags), len(imageDigests))
+	}
+	if len(imageNameTags) == 0 {
+		return []string{}, nil
+	}
+	_, registry, err := splitRegistryURL(registryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	references := []string{}
+	for i, imageNameTag := range imageNameTags {
+		reference, err := name.ParseReference(fmt.Sprintf("%v/%v", registry, imageNameTag))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid container image '%v'", imageNameTag)
+		}
+		references = append(references, fmt.Sprintf("%v@%v", reference.Context().Name(), imageDigests[i]))
+	}
+	return references, nil
+}
@@ -0,0 +1,121 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/provenance"
+)
+
+type containerSignImageMockUtils struct {
+	*mock.FilesMock
+	signed []string
+}
+
+func (c *containerSignImageMockUtils) SignImage(image string, signer *provenance.Signer) error {
+	if image == "my.registry.com/broken@sha256:abc" {
+		return fmt.Errorf("unauthorized")
+	}
+	c.signed = append(c.signed, image)
+	return nil
+}
+
+func newContainerSignImageTestsUtils(t *testing.T) *containerSignImageMockUtils {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	utils := containerSignImageMockUtils{
+		FilesMock: &mock.FilesMock{},
+	}
+	utils.AddFile("cosign.key", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	return &utils
+}
+
+func TestRunContainerSignImage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sign images", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := containerSignImageOptions{
+			ContainerRegistryURL:   "https://my.registry.com",
+			ContainerImageNameTags: []string{"shop:1.0.0", "org/backend:1.0.0"},
+			ContainerImageDigests:  []string{"sha256:aaa", "sha256:bbb"},
+			SigningKey:             "cosign.key",
+		}
+		utils := newContainerSignImageTestsUtils(t)
+		// test
+		err := runContainerSignImage(&config, utils)
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"my.registry.com/shop@sha256:aaa", "my.registry.com/org/backend@sha256:bbb"}, utils.signed)
+	})
+
+	t.Run("signing fails", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := containerSignImageOptions{
+			ContainerRegistryURL:   "https://my.registry.com",
+			ContainerImageNameTags: []string{"broken:1.0.0"},
+			ContainerImageDigests:  []string{"sha256:abc"},
+			SigningKey:             "cosign.key",
+		}
+		utils := newContainerSignImageTestsUtils(t)
+		// test
+		err := runContainerSignImage(&config, utils)
+		// assert
+		assert.EqualError(t, err, "failed to sign image 'my.registry.com/broken@sha256:abc': unauthorized")
+	})
+
+	t.Run("no images", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := containerSignImageOptions{SigningKey: "cosign.key"}
+		utils := newContainerSignImageTestsUtils(t)
+		// test
+		err := runContainerSignImage(&config, utils)
+		// assert
+		assert.EqualError(t, err, "no container images found to sign")
+	})
+
+	t.Run("digests missing", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := containerSignImageOptions{
+			ContainerRegistryURL:   "https://my.registry.com",
+			ContainerImageNameTags: []string{"shop:1.0.0"},
+			SigningKey:             "cosign.key",
+		}
+		utils := newContainerSignImageTestsUtils(t)
+		// test
+		err := runContainerSignImage(&config, utils)
+		// assert
+		assert.EqualError(t, err, "number of container images (1) does not match the number of image digests (0)")
+	})
+
+	t.Run("missing password", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := containerSignImageOptions{SigningKey: "cosign.key"}
+		utils := newContainerSignImageTestsUtils(t)
+		utils.AddFile("cosign.key", pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED SIGSTORE PRIVATE KEY", Bytes: []byte("{}")}))
+		// test
+		err := runContainerSignImage(&config, utils)
+		// assert
+		assert.EqualError(t, err, "invalid signing key: unsupported private key encryption '' with ''")
+	})
+}
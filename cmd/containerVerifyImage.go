@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"crypto"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/cosign"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/provenance"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+type containerVerifyImageUtils interface {
+	piperutils.FileUtils
+
+	ResolveDigest(image string) (string, error)
+	VerifyImage(image string, publicKey crypto.PublicKey) ([]cosign.Payload, error)
+	Referrers(image string) ([]provenance.Envelope, error)
+}
+
+type containerVerifyImageUtilsBundle struct {
+	*piperutils.Files
+}
+
+func (c *containerVerifyImageUtilsBundle) ResolveDigest(image string) (string, error) {
+	return cosign.ResolveDigest(image, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+}
+
+func (c *containerVerifyImageUtilsBundle) VerifyImage(image string, publicKey crypto.PublicKey) ([]cosign.Payload, error) {
+	return cosign.VerifyImage(image, publicKey, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+}
+
+func (c *containerVerifyImageUtilsBundle) Referrers(image string) ([]provenance.Envelope, error) {
+	return provenance.Referrers(image, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+}
+
+func newContainerVerifyImageUtils() containerVerifyImageUtils {
+	utils := containerVerifyImageUtilsBundle{
+		Files: &piperutils.Files{},
+	}
+	return &utils
+}
+
+func containerVerifyImage(config containerVerifyImageOptions, _ *telemetry.CustomData) {
+	utils := newContainerVerifyImageUtils()
+
+	if len(config.DockerConfigJSON) > 0 {
+		if err := setDockerConfigEnv(config.DockerConfigJSON, utils); err != nil {
+			log.Entry().WithError(err).Fatal("step execution failed")
+		}
+	}
+
+	err := runContainerVerifyImage(&config, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runContainerVerifyImage(config *containerVerifyImageOptions, utils containerVerifyImageUtils) error {
+	keyPEM, err := utils.FileRead(config.PublicKey)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "failed to read public key")
+	}
+	publicKey, err := provenance.ParsePublicKey(keyPEM)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "invalid public key")
+	}
+
+	images, err := containerImageDigestReferences(config.ContainerRegistryURL, config.ContainerImageNameTags, config.ContainerImageDigests)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	for _, containerImage := range config.ContainerImages {
+		image, err := utils.ResolveDigest(containerImage)
+		if err != nil {
+			return err
+		}
+		if !piperutils.ContainsString(images, image) {
+			images = append(images, image)
+		}
+	}
+	if len(images) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("no container images found to verify")
+	}
+
+	failed := 0
+	for _, image := range images {
+		if err := verifyContainerImage(image, publicKey, config.VerifyProvenance, utils); err != nil {
+			log.Entry().WithError(err).Errorf("Verification of image '%v' failed", image)
+			failed++
+			continue
+		}
+		log.Entry().Infof("Image '%v' verified", image)
+	}
+	if failed > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("verification failed for %v of %v container image(s)", failed, len(images))
+	}
+	return nil
+}
+
+func verifyContainerImage(image string, publicKey crypto.PublicKey, verifyProvenance bool, utils containerVerifyImageUtils) error {
+	if _, err := utils.VerifyImage(image, publicKey); err != nil {
+		if errors.Is(err, cosign.ErrNotSigned) {
+			return errors.New("image is not signed")
+		}
+		return err
+	}
+	if !verifyProvenance {
+		return nil
+	}
+
+	digest := image[strings.LastIndex(image, "@")+1:]
+	envelopes, err := utils.Referrers(image)
+	if err != nil {
+		return err
+	}
+	for _, envelope := range envelopes {
+		if envelope.Verify(publicKey) != nil {
+			continue
+		}
+		statement, err := envelope.Statement()
+		if err != nil || statement.PredicateType != provenance.PredicateTypeSLSAProvenance {
+			continue
+		}
+		for _, subject := range statement.Subject {
+			if fmt.Sprintf("sha256:%v", subject.Digest["sha256"]) == digest {
+				return nil
+			}
+		}
+	}
+	return errors.New("no provenance signed with the public key found")
+}
@@ -0,0 +1,275 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/spf13/cobra"
+)
+
+type containerVerifyImageOptions struct {
+	ContainerRegistryURL   string   `json:"containerRegistryUrl,omitempty"`
+	ContainerImageNameTags []string `json:"containerImageNameTags,omitempty"`
+	ContainerImageDigests  []string `json:"containerImageDigests,omitempty"`
+	ContainerImages        []string `json:"containerImages,omitempty"`
+	PublicKey              string   `json:"publicKey,omitempty"`
+	VerifyProvenance       bool     `json:"verifyProvenance,omitempty"`
+	DockerConfigJSON       string   `json:"dockerConfigJSON,omitempty"`
+}
+
+// ContainerVerifyImageCommand Verifies that container images are signed with a trusted key before they are deployed.
+func ContainerVerifyImageCommand() *cobra.Command {
+	const STEP_NAME = "containerVerifyImage"
+
+	metadata := containerVerifyImageMetadata()
+	var stepConfig containerVerifyImageOptions
+	var startTime time.Time
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createContainerVerifyImageCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Verifies that container images are signed with a trusted key before they are deployed.",
+		Long: `This step verifies the [cosign](https://github.com/sigstore/cosign) signatures of container images, e.g. before deploying them with ` + "`" + `kubernetesDeploy` + "`" + ` or ` + "`" + `helmExecute` + "`" + `.
+The step fails if an image is not signed or if none of its signatures was created with the private key matching the configured public key.
+
+The images listed in the common pipeline environment (` + "`" + `container/imageNameTags` + "`" + ` together with ` + "`" + `container/imageDigests` + "`" + `) are verified by default.
+Additional images (e.g. images of other teams) can be configured with ` + "`" + `containerImages` + "`" + `; images referenced by tag are resolved to their current digest.
+
+Optionally, the step also requires SLSA provenance attached to the images by ` + "`" + `provenanceCreate` + "`" + `, signed with the same key.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.DockerConfigJSON)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.Send()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+			}, func() {
+				containerVerifyImage(stepConfig, &stepTelemetryData)
+			})
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addContainerVerifyImageFlags(createContainerVerifyImageCmd, &stepConfig)
+	return createContainerVerifyImageCmd
+}
+
+func addContainerVerifyImageFlags(cmd *cobra.Command, stepConfig *containerVerifyImageOptions) {
+	cmd.Flags().StringVar(&stepConfig.ContainerRegistryURL, "containerRegistryUrl", os.Getenv("PIPER_containerRegistryUrl"), "Url of the container registry of the images listed in `containerImageNameTags` - typically provided by the CI/CD environment.")
+	cmd.Flags().StringSliceVar(&stepConfig.ContainerImageNameTags, "containerImageNameTags", []string{}, "List of images (name and tag without registry) to verify.")
+	cmd.Flags().StringSliceVar(&stepConfig.ContainerImageDigests, "containerImageDigests", []string{}, "List of digests of the images in the format `sha256:<hash>`, in the same order as `containerImageNameTags`.")
+	cmd.Flags().StringSliceVar(&stepConfig.ContainerImages, "containerImages", []string{}, "List of additional images including registry to verify, e.g. `my.registry.com/app:1.0.0` or `my.registry.com/app@sha256:<hash>`.")
+	cmd.Flags().StringVar(&stepConfig.PublicKey, "publicKey", os.Getenv("PIPER_publicKey"), "Path to the public key in PEM format, e.g. `cosign.pub` created with `cosign generate-key-pair`.")
+	cmd.Flags().BoolVar(&stepConfig.VerifyProvenance, "verifyProvenance", false, "Requires SLSA provenance attached to the images as OCI referrer, signed with the private key matching the public key.")
+	cmd.Flags().StringVar(&stepConfig.DockerConfigJSON, "dockerConfigJSON", os.Getenv("PIPER_dockerConfigJSON"), "Path to the file `.docker/config.json` - this is typically provided by your CI/CD system. The credentials need to allow pulling from the repositories of the images.")
+
+	cmd.MarkFlagRequired("publicKey")
+}
+
+// retrieve step metadata
+func containerVerifyImageMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "containerVerifyImage",
+			Aliases:     []config.Alias{},
+			Description: "Verifies that container images are signed with a trusted key before they are deployed.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "publicKeyCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing the public key used to verify the images.", Type: "jenkins"},
+					{Name: "dockerConfigJsonCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing Docker config.json (with registry credential(s)). You can find more details about the Docker credentials in the [Docker documentation](https://docs.docker.com/engine/reference/commandline/login/).", Type: "jenkins"},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name: "containerRegistryUrl",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "container/registryUrl",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_containerRegistryUrl"),
+					},
+					{
+						Name: "containerImageNameTags",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "container/imageNameTags",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "[]string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   []string{},
+					},
+					{
+						Name: "containerImageDigests",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "container/imageDigests",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "[]string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   []string{},
+					},
+					{
+						Name:        "containerImages",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name: "publicKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "publicKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "cosignVaultSecretName",
+								Type:    "vaultSecretFile",
+								Default: "cosign",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_publicKey"),
+					},
+					{
+						Name:        "verifyProvenance",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name: "dockerConfigJSON",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/dockerConfigJSON",
+							},
+
+							{
+								Name: "dockerConfigJsonCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "dockerConfigFileVaultSecretName",
+								Type:    "vaultSecretFile",
+								Default: "docker-config",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_dockerConfigJSON"),
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,20 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainerVerifyImageCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := ContainerVerifyImageCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "containerVerifyImage", testCmd.Use, "command name incorrect")
+
+}
@@ -0,0 +1,162 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/cosign"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/provenance"
+)
+
+type containerVerifyImageMockUtils struct {
+	*mock.FilesMock
+	signed     map[string]bool
+	provenance map[string][]provenance.Envelope
+}
+
+func (c *containerVerifyImageMockUtils) ResolveDigest(image string) (string, error) {
+	if image == "my.registry.com/base:latest" {
+		return "my.registry.com/base@sha256:ccc", nil
+	}
+	return "", fmt.Errorf("image '%v' not found", image)
+}
+
+func (c *containerVerifyImageMockUtils) VerifyImage(image string, publicKey crypto.PublicKey) ([]cosign.Payload, error) {
+	signed, ok := c.signed[image]
+	if !ok {
+		return nil, cosign.ErrNotSigned
+	}
+	if !signed {
+		return nil, fmt.Errorf("no valid signature found")
+	}
+	return []cosign.Payload{{}}, nil
+}
+
+func (c *containerVerifyImageMockUtils) Referrers(image string) ([]provenance.Envelope, error) {
+	return c.provenance[image], nil
+}
+
+func newContainerVerifyImageTestsUtils(t *testing.T) (*containerVerifyImageMockUtils, *provenance.Signer) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	privateDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	signer, err := provenance.NewSigner(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privateDER}), "")
+	require.NoError(t, err)
+
+	utils := containerVerifyImageMockUtils{
+		FilesMock: &mock.FilesMock{},
+		signed: map[string]bool{
+			"my.registry.com/shop@sha256:aaa": true,
+			"my.registry.com/base@sha256:ccc": true,
+			"my.registry.com/evil@sha256:eee": false,
+		},
+		provenance: map[string][]provenance.Envelope{},
+	}
+	utils.AddFile("cosign.pub", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	return &utils, signer
+}
+
+func TestRunContainerVerifyImage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("signed images", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := containerVerifyImageOptions{
+			ContainerRegistryURL:   "https://my.registry.com",
+			ContainerImageNameTags: []string{"shop:1.0.0"},
+			ContainerImageDigests:  []string{"sha256:aaa"},
+			ContainerImages:        []string{"my.registry.com/base:latest"},
+			PublicKey:              "cosign.pub",
+		}
+		utils, _ := newContainerVerifyImageTestsUtils(t)
+		// test
+		err := runContainerVerifyImage(&config, utils)
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("unsigned and wrongly signed images", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := containerVerifyImageOptions{
+			ContainerRegistryURL:   "https://my.registry.com",
+			ContainerImageNameTags: []string{"shop:1.0.0", "unsigned:1.0.0", "evil:1.0.0"},
+			ContainerImageDigests:  []string{"sha256:aaa", "sha256:ddd", "sha256:eee"},
+			PublicKey:              "cosign.pub",
+		}
+		utils, _ := newContainerVerifyImageTestsUtils(t)
+		// test
+		err := runContainerVerifyImage(&config, utils)
+		// assert
+		assert.EqualError(t, err, "verification failed for 2 of 3 container image(s)")
+	})
+
+	t.Run("provenance", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := containerVerifyImageOptions{
+			ContainerRegistryURL:   "https://my.registry.com",
+			ContainerImageNameTags: []string{"shop:1.0.0"},
+			ContainerImageDigests:  []string{"sha256:aaa"},
+			PublicKey:              "cosign.pub",
+			VerifyProvenance:       true,
+		}
+		utils, signer := newContainerVerifyImageTestsUtils(t)
+		// test
+		err := runContainerVerifyImage(&config, utils)
+		// assert
+		assert.EqualError(t, err, "verification failed for 1 of 1 container image(s)")
+
+		// init
+		otherImage, _ := signer.SignStatement(provenance.NewStatement([]provenance.Subject{{Name: "my.registry.com/shop", Digest: map[string]string{"sha256": "bbb"}}}, provenance.BuildInfo{}))
+		envelope, _ := signer.SignStatement(provenance.NewStatement([]provenance.Subject{{Name: "my.registry.com/shop", Digest: map[string]string{"sha256": "aaa"}}}, provenance.BuildInfo{}))
+		utils.provenance["my.registry.com/shop@sha256:aaa"] = []provenance.Envelope{*otherImage, *envelope}
+		// test
+		err = runContainerVerifyImage(&config, utils)
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown image", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := containerVerifyImageOptions{
+			ContainerImages: []string{"my.registry.com/unknown:latest"},
+			PublicKey:       "cosign.pub",
+		}
+		utils, _ := newContainerVerifyImageTestsUtils(t)
+		// test
+		err := runContainerVerifyImage(&config, utils)
+		// assert
+		assert.EqualError(t, err, "image 'my.registry.com/unknown:latest' not found")
+	})
+
+	t.Run("invalid public key", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := containerVerifyImageOptions{PublicKey: "cosign.pub"}
+		utils, _ := newContainerVerifyImageTestsUtils(t)
+		utils.AddFile("cosign.pub", []byte("no key"))
+		// test
+		err := runContainerVerifyImage(&config, utils)
+		// assert
+		assert.EqualError(t, err, "invalid public key: no PEM encoded public key found")
+	})
+}
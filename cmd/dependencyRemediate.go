@@ -0,0 +1,357 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	bd "github.com/SAP/jenkins-library/pkg/blackduck"
+	gitUtil "github.com/SAP/jenkins-library/pkg/git"
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/remediation"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/versioning"
+	ws "github.com/SAP/jenkins-library/pkg/whitesource"
+	"github.com/go-git/go-git/v5"
+	gitConfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/google/go-github/v45/github"
+	"github.com/pkg/errors"
+)
+
+// remediationDescriptorPatterns lists the build descriptors the upgrades are applied to
+var remediationDescriptorPatterns = []string{"**/package.json", "**/pom.xml", "**/go.mod"}
+
+type dependencyRemediateUtils interface {
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	Glob(pattern string) (matches []string, err error)
+}
+
+type dependencyRemediateGitUtils interface {
+	CurrentBranch() (string, error)
+	CreateBranch(branchName string) error
+	CommitFiles(filePaths []string, commitMessage, author string) (plumbing.Hash, error)
+	PushBranch(branchName, username, password string) error
+}
+
+// dependencyRemediatePRService allows to update the pull request of a previous run instead of creating another one
+type dependencyRemediatePRService interface {
+	githubPRService
+	List(ctx context.Context, owner string, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
+	Edit(ctx context.Context, owner string, repo string, number int, pull *github.PullRequest) (*github.PullRequest, *github.Response, error)
+}
+
+// dependencyRemediationSource provides the upgrades proposed by a scanner
+type dependencyRemediationSource interface {
+	Upgrades() ([]remediation.Upgrade, error)
+}
+
+type blackDuckRemediationClient interface {
+	GetComponents(projectName, versionName string) (*bd.Components, error)
+	GetVulnerabilities(projectName, versionName string) (*bd.Vulnerabilities, error)
+	GetRemediatingVersions(componentVersion string) (*bd.RemediatingVersions, error)
+}
+
+type whiteSourceRemediationClient interface {
+	GetProductByName(productName string) (ws.Product, error)
+	GetProjectToken(productToken, projectName string) (string, error)
+	GetProjectAlertsByType(projectToken, alertType string) ([]ws.Alert, error)
+}
+
+type blackDuckRemediationSource struct {
+	client      blackDuckRemediationClient
+	projectName string
+	versionName string
+}
+
+type whiteSourceRemediationSource struct {
+	client      whiteSourceRemediationClient
+	productName string
+	projectName string
+}
+
+type dependencyRemediateGitUtilsBundle struct {
+	repository *git.Repository
+	worktree   *git.Worktree
+}
+
+func (g *dependencyRemediateGitUtilsBundle) CurrentBranch() (string, error) {
+	head, err := g.repository.Head()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to retrieve HEAD")
+	}
+	if !head.Name().IsBranch() {
+		return "", errors.New("HEAD does not point to a branch")
+	}
+	return head.Name().Short(), nil
+}
+
+// CreateBranch creates the branch at the current HEAD and checks it out, an existing branch is reset
+func (g *dependencyRemediateGitUtilsBundle) CreateBranch(branchName string) error {
+	head, err := g.repository.Head()
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve HEAD")
+	}
+	branch := plumbing.NewBranchReferenceName(branchName)
+	if err := g.repository.Storer.SetReference(plumbing.NewHashReference(branch, head.Hash())); err != nil {
+		return errors.Wrapf(err, "failed to create branch '%v'", branchName)
+	}
+	if err := g.worktree.Checkout(&git.CheckoutOptions{Branch: branch, Keep: true}); err != nil {
+		return errors.Wrapf(err, "failed to checkout branch '%v'", branchName)
+	}
+	return nil
+}
+
+func (g *dependencyRemediateGitUtilsBundle) CommitFiles(filePaths []string, commitMessage, author string) (plumbing.Hash, error) {
+	for _, path := range filePaths {
+		if _, err := g.worktree.Add(path); err != nil {
+			return [20]byte{}, errors.Wrap(err, "failed to add file to git")
+		}
+	}
+	commit, err := g.worktree.Commit(commitMessage, &git.CommitOptions{
+		Author: &object.Signature{Name: author, When: time.Now()},
+	})
+	if err != nil {
+		return [20]byte{}, errors.Wrap(err, "failed to commit files")
+	}
+	return commit, nil
+}
+
+// PushBranch force pushes only the given branch in order not to touch other branches of the remote repository
+func (g *dependencyRemediateGitUtilsBundle) PushBranch(branchName, username, password string) error {
+	refSpec := gitConfig.RefSpec(fmt.Sprintf("+refs/heads/%[1]v:refs/heads/%[1]v", branchName))
+	err := g.repository.Push(&git.PushOptions{
+		RefSpecs: []gitConfig.RefSpec{refSpec},
+		Auth:     &http.BasicAuth{Username: username, Password: password},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to push branch '%v'", branchName)
+	}
+	return nil
+}
+
+func (s *blackDuckRemediationSource) Upgrades() ([]remediation.Upgrade, error) {
+	components, err := s.client.GetComponents(s.projectName, s.versionName)
+	if err != nil {
+		return nil, err
+	}
+	componentLookup := map[string]*bd.Component{}
+	for i := range components.Items {
+		componentLookup[fmt.Sprintf("%v/%v", components.Items[i].Name, components.Items[i].Version)] = &components.Items[i]
+	}
+
+	vulnerabilities, err := s.client.GetVulnerabilities(s.projectName, s.versionName)
+	if err != nil {
+		return nil, err
+	}
+
+	active := []bd.Vulnerability{}
+	remediatingVersions := map[string]*bd.RemediatingVersions{}
+	for _, vulnerability := range vulnerabilities.Items {
+		if !isActiveVulnerability(vulnerability) || len(vulnerability.ComponentVersion) == 0 {
+			continue
+		}
+		vulnerability.Component = componentLookup[fmt.Sprintf("%v/%v", vulnerability.Name, vulnerability.Version)]
+		active = append(active, vulnerability)
+		if _, ok := remediatingVersions[vulnerability.ComponentVersion]; ok {
+			continue
+		}
+		versions, err := s.client.GetRemediatingVersions(vulnerability.ComponentVersion)
+		if err != nil {
+			return nil, err
+		}
+		remediatingVersions[vulnerability.ComponentVersion] = versions
+	}
+	return remediation.FromBlackDuck(active, remediatingVersions), nil
+}
+
+func (s *whiteSourceRemediationSource) Upgrades() ([]remediation.Upgrade, error) {
+	product, err := s.client.GetProductByName(s.productName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve product '%v'", s.productName)
+	}
+	projectToken, err := s.client.GetProjectToken(product.Token, s.projectName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve project '%v'", s.projectName)
+	}
+	if len(projectToken) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("project '%v' not found in product '%v'", s.projectName, s.productName)
+	}
+	alerts, err := s.client.GetProjectAlertsByType(projectToken, "SECURITY_VULNERABILITY")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve alerts of project '%v'", s.projectName)
+	}
+	return remediation.FromWhiteSource(alerts), nil
+}
+
+func newDependencyRemediationSource(config *dependencyRemediateOptions) dependencyRemediationSource {
+	version := versioning.ApplyVersioningModel(config.VersioningModel, config.Version)
+	if config.Scanner == "whitesource" {
+		return &whiteSourceRemediationSource{
+			client:      ws.NewSystem(config.ServiceURL, config.OrgToken, config.UserToken, 5*time.Minute),
+			productName: config.ProductName,
+			projectName: fmt.Sprintf("%s - %s", config.ProjectName, version),
+		}
+	}
+	client := bd.NewClient(config.DetectToken, config.ServerURL, &piperhttp.Client{})
+	return &blackDuckRemediationSource{client: &client, projectName: config.ProjectName, versionName: version}
+}
+
+func dependencyRemediate(config dependencyRemediateOptions, _ *telemetry.CustomData) {
+	ctx, client, err := piperGithub.NewClientBuilder(config.Token, config.APIURL).Build()
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Failed to get GitHub client")
+	}
+
+	gitUtils := &dependencyRemediateGitUtilsBundle{}
+	gitUtils.repository, err = gitUtil.PlainOpen(".")
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Failed to open git repository")
+	}
+	gitUtils.worktree, err = gitUtils.repository.Worktree()
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Failed to retrieve git worktree")
+	}
+
+	err = runDependencyRemediate(ctx, &config, newDependencyRemediationSource(&config), &piperutils.Files{}, gitUtils, client.PullRequests, client.Issues)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Failed to remediate vulnerable dependencies")
+	}
+}
+
+func runDependencyRemediate(ctx context.Context, config *dependencyRemediateOptions, source dependencyRemediationSource, utils dependencyRemediateUtils, gitUtils dependencyRemediateGitUtils, ghPRService dependencyRemediatePRService, ghIssueService githubIssueService) error {
+	upgrades, err := source.Upgrades()
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve remediation information")
+	}
+	upgrades = remediation.Merge(upgrades)
+	if len(upgrades) == 0 {
+		log.Entry().Info("No upgrades for vulnerable dependencies available")
+		return nil
+	}
+	log.Entry().Infof("%v upgrades for vulnerable dependencies available", len(upgrades))
+
+	descriptors, err := readRemediationDescriptors(utils)
+	if err != nil {
+		return err
+	}
+
+	result, err := remediation.Apply(descriptors, upgrades, remediation.Options{AllowMajorUpgrades: config.AllowMajorUpgrades})
+	if err != nil {
+		return errors.Wrap(err, "failed to apply upgrades")
+	}
+	for _, skipped := range result.Skipped {
+		log.Entry().Infof("Skipping upgrade of %v from %v to %v: %v", skipped.Upgrade.Name, skipped.Upgrade.Version, skipped.Upgrade.FixedVersion, skipped.Reason)
+	}
+	if len(result.Changes) == 0 {
+		log.Entry().Info("No build descriptor needs to be updated")
+		return nil
+	}
+
+	base := config.Base
+	if len(base) == 0 {
+		if base, err = gitUtils.CurrentBranch(); err != nil {
+			return errors.Wrap(err, "failed to determine base branch")
+		}
+	}
+
+	if err := gitUtils.CreateBranch(config.BranchName); err != nil {
+		return err
+	}
+	files := []string{}
+	for _, change := range result.Changes {
+		if piperutils.ContainsString(files, change.File) {
+			continue
+		}
+		files = append(files, change.File)
+		if err := utils.FileWrite(change.File, result.Files[change.File], 0644); err != nil {
+			return errors.Wrapf(err, "failed to write '%v'", change.File)
+		}
+		log.Entry().Infof("Upgraded %v from %v to %v in '%v'", change.Upgrade.Name, change.FromVersion, change.ToVersion, change.File)
+	}
+	if _, err := gitUtils.CommitFiles(files, config.CommitMessage, config.Username); err != nil {
+		return errors.Wrap(err, "committing changes failed")
+	}
+	if err := gitUtils.PushBranch(config.BranchName, config.Username, config.Token); err != nil {
+		return errors.Wrap(err, "pushing changes failed")
+	}
+
+	body := result.PullRequestBody()
+	existing, err := openRemediationPullRequest(ctx, config, base, ghPRService)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return updateRemediationPullRequest(ctx, config, existing.GetNumber(), body, ghPRService, ghIssueService)
+	}
+
+	prConfig := githubCreatePullRequestOptions{
+		Title:      config.CommitMessage,
+		Body:       body,
+		Head:       config.BranchName,
+		Base:       base,
+		Owner:      config.Owner,
+		Repository: config.Repository,
+		Labels:     config.Labels,
+		Assignees:  config.Assignees,
+	}
+	return runGithubCreatePullRequest(ctx, &prConfig, ghPRService, ghIssueService)
+}
+
+// openRemediationPullRequest returns the open pull request of the remediation branch, e.g. created by a previous run
+func openRemediationPullRequest(ctx context.Context, config *dependencyRemediateOptions, base string, ghPRService dependencyRemediatePRService) (*github.PullRequest, error) {
+	options := github.PullRequestListOptions{State: "open", Head: fmt.Sprintf("%v:%v", config.Owner, config.BranchName), Base: base}
+	pullRequests, _, err := ghPRService.List(ctx, config.Owner, config.Repository, &options)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pull requests")
+	}
+	if len(pullRequests) == 0 {
+		return nil, nil
+	}
+	return pullRequests[0], nil
+}
+
+// updateRemediationPullRequest updates title and description of an existing pull request according to the upgrades of the current run
+func updateRemediationPullRequest(ctx context.Context, config *dependencyRemediateOptions, number int, body string, ghPRService dependencyRemediatePRService, ghIssueService githubIssueService) error {
+	update := github.PullRequest{Title: &config.CommitMessage, Body: &body}
+	if _, _, err := ghPRService.Edit(ctx, config.Owner, config.Repository, number, &update); err != nil {
+		return errors.Wrapf(err, "failed to update pull request #%v", number)
+	}
+	issueRequest := github.IssueRequest{Labels: &config.Labels, Assignees: &config.Assignees}
+	if _, _, err := ghIssueService.Edit(ctx, config.Owner, config.Repository, number, &issueRequest); err != nil {
+		return errors.Wrapf(err, "failed to update labels and assignees of pull request #%v", number)
+	}
+	log.Entry().Infof("Updated existing pull request #%v", number)
+	return nil
+}
+
+func readRemediationDescriptors(utils dependencyRemediateUtils) (map[string][]byte, error) {
+	descriptors := map[string][]byte{}
+	for _, pattern := range remediationDescriptorPatterns {
+		paths, err := utils.Glob(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to search for '%v'", pattern)
+		}
+		for _, path := range paths {
+			// dependencies of dependencies are not in scope of the remediation
+			if strings.Contains(path, "node_modules/") || strings.HasPrefix(path, "vendor/") || strings.Contains(path, "/vendor/") {
+				continue
+			}
+			content, err := utils.FileRead(path)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read '%v'", path)
+			}
+			descriptors[path] = content
+		}
+	}
+	return descriptors, nil
+}
@@ -0,0 +1,465 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/spf13/cobra"
+)
+
+type dependencyRemediateOptions struct {
+	Scanner            string   `json:"scanner,omitempty" validate:"possible-values=blackduck whitesource"`
+	ServerURL          string   `json:"serverUrl,omitempty" validate:"required_if=Scanner blackduck"`
+	DetectToken        string   `json:"detectToken,omitempty" validate:"required_if=Scanner blackduck"`
+	ServiceURL         string   `json:"serviceUrl,omitempty"`
+	OrgToken           string   `json:"orgToken,omitempty" validate:"required_if=Scanner whitesource"`
+	UserToken          string   `json:"userToken,omitempty" validate:"required_if=Scanner whitesource"`
+	ProductName        string   `json:"productName,omitempty" validate:"required_if=Scanner whitesource"`
+	ProjectName        string   `json:"projectName,omitempty"`
+	Version            string   `json:"version,omitempty"`
+	VersioningModel    string   `json:"versioningModel,omitempty" validate:"possible-values=major major-minor semantic full"`
+	AllowMajorUpgrades bool     `json:"allowMajorUpgrades,omitempty"`
+	BranchName         string   `json:"branchName,omitempty"`
+	Base               string   `json:"base,omitempty"`
+	CommitMessage      string   `json:"commitMessage,omitempty"`
+	Username           string   `json:"username,omitempty"`
+	APIURL             string   `json:"apiUrl,omitempty"`
+	Owner              string   `json:"owner,omitempty"`
+	Repository         string   `json:"repository,omitempty"`
+	Token              string   `json:"token,omitempty"`
+	Labels             []string `json:"labels,omitempty"`
+	Assignees          []string `json:"assignees,omitempty"`
+}
+
+// DependencyRemediateCommand Upgrades vulnerable dependencies to fixed versions and opens a GitHub pull request
+func DependencyRemediateCommand() *cobra.Command {
+	const STEP_NAME = "dependencyRemediate"
+
+	metadata := dependencyRemediateMetadata()
+	var stepConfig dependencyRemediateOptions
+	var startTime time.Time
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createDependencyRemediateCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Upgrades vulnerable dependencies to fixed versions and opens a GitHub pull request",
+		Long: `BlackDuck and Mend (formerly known as WhiteSource) know which version of a library fixes a vulnerability.
+This step retrieves this remediation information for the scanned project and applies the upgrades to the direct dependencies
+declared in the build descriptors of the repository:
+
+* npm: ` + "`" + `package.json` + "`" + ` (` + "`" + `dependencies` + "`" + `, ` + "`" + `devDependencies` + "`" + `, ` + "`" + `optionalDependencies` + "`" + `), a range operator like ` + "`" + `^` + "`" + ` or ` + "`" + `~` + "`" + ` is kept
+* Maven: ` + "`" + `pom.xml` + "`" + ` (dependencies and dependency management of the project), versions defined via properties are updated in the property unless the property is used by other declarations as well
+* Go: ` + "`" + `go.mod` + "`" + ` (direct requirements only)
+
+The changes are committed to the branch [` + "`" + `branchName` + "`" + `](#branchname) which is pushed to the repository and a pull request is opened against [` + "`" + `base` + "`" + `](#base).
+If a pull request of the branch is still open from a previous run, it is updated instead.
+The description of the pull request lists the upgrades together with the vulnerabilities they address as well as the upgrades which have been skipped.
+Upgrades to a different major version are skipped unless [` + "`" + `allowMajorUpgrades` + "`" + `](#allowmajorupgrades) is set.
+Major upgrades of Go modules are always skipped since they require a new module path (e.g. ` + "`" + `/v2` + "`" + `) and adapted imports.
+
+!!! note "Lock files"
+    Lock files like ` + "`" + `package-lock.json` + "`" + ` or ` + "`" + `go.sum` + "`" + ` are not updated by the step. They need to be refreshed as part of the pull request, e.g. via ` + "`" + `npm install` + "`" + ` or ` + "`" + `go mod tidy` + "`" + `.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.DetectToken)
+			log.RegisterSecret(stepConfig.OrgToken)
+			log.RegisterSecret(stepConfig.UserToken)
+			log.RegisterSecret(stepConfig.Token)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.Send()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+			}, func() {
+				dependencyRemediate(stepConfig, &stepTelemetryData)
+			})
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addDependencyRemediateFlags(createDependencyRemediateCmd, &stepConfig)
+	return createDependencyRemediateCmd
+}
+
+func addDependencyRemediateFlags(cmd *cobra.Command, stepConfig *dependencyRemediateOptions) {
+	cmd.Flags().StringVar(&stepConfig.Scanner, "scanner", os.Getenv("PIPER_scanner"), "The scanner which provides the remediation information.")
+	cmd.Flags().StringVar(&stepConfig.ServerURL, "serverUrl", os.Getenv("PIPER_serverUrl"), "Server URL of the BlackDuck server.")
+	cmd.Flags().StringVar(&stepConfig.DetectToken, "detectToken", os.Getenv("PIPER_detectToken"), "API token used to authenticate with the BlackDuck server.")
+	cmd.Flags().StringVar(&stepConfig.ServiceURL, "serviceUrl", `https://saas.whitesourcesoftware.com/api`, "URL to the Mend API endpoint.")
+	cmd.Flags().StringVar(&stepConfig.OrgToken, "orgToken", os.Getenv("PIPER_orgToken"), "Mend token identifying your organization.")
+	cmd.Flags().StringVar(&stepConfig.UserToken, "userToken", os.Getenv("PIPER_userToken"), "User token to access Mend.")
+	cmd.Flags().StringVar(&stepConfig.ProductName, "productName", os.Getenv("PIPER_productName"), "Name of the Mend product containing the project.")
+	cmd.Flags().StringVar(&stepConfig.ProjectName, "projectName", os.Getenv("PIPER_projectName"), "Name of the BlackDuck project respectively the Mend project without version suffix.")
+	cmd.Flags().StringVar(&stepConfig.Version, "version", os.Getenv("PIPER_version"), "Version of the artifact being built in the pipeline. The project version in BlackDuck and Mend is calculated using the [`versioningModel`](#versioningmodel).")
+	cmd.Flags().StringVar(&stepConfig.VersioningModel, "versioningModel", `major`, "The versioning model used for the project version in BlackDuck and Mend. Example 1.2.3 using `major` will result in version 1")
+	cmd.Flags().BoolVar(&stepConfig.AllowMajorUpgrades, "allowMajorUpgrades", false, "Whether upgrades to a fixed version with a different major version are applied.")
+	cmd.Flags().StringVar(&stepConfig.BranchName, "branchName", `piper/dependency-remediation`, "Name of the branch the upgrades are committed to. An existing branch with this name is overwritten.")
+	cmd.Flags().StringVar(&stepConfig.Base, "base", os.Getenv("PIPER_base"), "The name of the branch you want the changes pulled into. If not set, the branch currently checked out is used.")
+	cmd.Flags().StringVar(&stepConfig.CommitMessage, "commitMessage", `Upgrade vulnerable dependencies`, "The commit message as well as the title of the pull request.")
+	cmd.Flags().StringVar(&stepConfig.Username, "username", `piper`, "The user name used as author of the commit and for authentication with GitHub in combination with the token.")
+	cmd.Flags().StringVar(&stepConfig.APIURL, "apiUrl", `https://api.github.com`, "Set the GitHub API url.")
+	cmd.Flags().StringVar(&stepConfig.Owner, "owner", os.Getenv("PIPER_owner"), "Name of the GitHub organization.")
+	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "Name of the GitHub repository.")
+	cmd.Flags().StringVar(&stepConfig.Token, "token", os.Getenv("PIPER_token"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line")
+	cmd.Flags().StringSliceVar(&stepConfig.Labels, "labels", []string{}, "Labels to be added to the pull request.")
+	cmd.Flags().StringSliceVar(&stepConfig.Assignees, "assignees", []string{}, "Login names of users to which the pull request should be assigned to.")
+
+	cmd.MarkFlagRequired("scanner")
+	cmd.MarkFlagRequired("projectName")
+	cmd.MarkFlagRequired("apiUrl")
+	cmd.MarkFlagRequired("owner")
+	cmd.MarkFlagRequired("repository")
+	cmd.MarkFlagRequired("token")
+}
+
+// retrieve step metadata
+func dependencyRemediateMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "dependencyRemediate",
+			Aliases:     []config.Alias{},
+			Description: "Upgrades vulnerable dependencies to fixed versions and opens a GitHub pull request",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "githubTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing token to authenticate to GitHub.", Type: "jenkins"},
+					{Name: "detectTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the API token used to authenticate with the BlackDuck server.", Type: "jenkins"},
+					{Name: "userTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the Mend user token.", Type: "jenkins"},
+					{Name: "orgAdminUserTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the Mend org admin token.", Type: "jenkins"},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name:        "scanner",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_scanner"),
+					},
+					{
+						Name:        "serverUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "detect/serverUrl"}},
+						Default:     os.Getenv("PIPER_serverUrl"),
+					},
+					{
+						Name: "detectToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "detectTokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "detectVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "detect",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "blackduckToken"}},
+						Default:   os.Getenv("PIPER_detectToken"),
+					},
+					{
+						Name:        "serviceUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "whitesourceServiceUrl"}},
+						Default:     `https://saas.whitesourcesoftware.com/api`,
+					},
+					{
+						Name: "orgToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "orgAdminUserTokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "whitesourceVaultSecret",
+								Type:    "vaultSecret",
+								Default: "whitesource",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "whitesourceOrgToken"}},
+						Default:   os.Getenv("PIPER_orgToken"),
+					},
+					{
+						Name: "userToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "userTokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "whitesourceVaultSecret",
+								Type:    "vaultSecret",
+								Default: "whitesource",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_userToken"),
+					},
+					{
+						Name:        "productName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "whitesourceProductName"}},
+						Default:     os.Getenv("PIPER_productName"),
+					},
+					{
+						Name:        "projectName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{{Name: "detect/projectName"}},
+						Default:     os.Getenv("PIPER_projectName"),
+					},
+					{
+						Name: "version",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "artifactVersion",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "projectVersion"}},
+						Default:   os.Getenv("PIPER_version"),
+					},
+					{
+						Name:        "versioningModel",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `major`,
+					},
+					{
+						Name:        "allowMajorUpgrades",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "branchName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `piper/dependency-remediation`,
+					},
+					{
+						Name:        "base",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_base"),
+					},
+					{
+						Name:        "commitMessage",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `Upgrade vulnerable dependencies`,
+					},
+					{
+						Name:        "username",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `piper`,
+					},
+					{
+						Name:        "apiUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{{Name: "githubApiUrl"}},
+						Default:     `https://api.github.com`,
+					},
+					{
+						Name: "owner",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/owner",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{{Name: "githubOrg"}},
+						Default:   os.Getenv("PIPER_owner"),
+					},
+					{
+						Name: "repository",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/repository",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{{Name: "githubRepo"}},
+						Default:   os.Getenv("PIPER_repository"),
+					},
+					{
+						Name: "token",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubTokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "githubVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "github",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{{Name: "githubToken"}, {Name: "access_token"}},
+						Default:   os.Getenv("PIPER_token"),
+					},
+					{
+						Name:        "labels",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "assignees",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,266 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	bd "github.com/SAP/jenkins-library/pkg/blackduck"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/remediation"
+	ws "github.com/SAP/jenkins-library/pkg/whitesource"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/google/go-github/v45/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type remediationSourceMock struct {
+	upgrades []remediation.Upgrade
+	err      error
+}
+
+func (s *remediationSourceMock) Upgrades() ([]remediation.Upgrade, error) {
+	return s.upgrades, s.err
+}
+
+type dependencyRemediateGitMock struct {
+	branch        string
+	createdBranch string
+	committed     []string
+	author        string
+	pushedBranch  string
+	pushError     error
+}
+
+func (g *dependencyRemediateGitMock) CurrentBranch() (string, error) {
+	return g.branch, nil
+}
+
+func (g *dependencyRemediateGitMock) CreateBranch(branchName string) error {
+	g.createdBranch = branchName
+	return nil
+}
+
+func (g *dependencyRemediateGitMock) CommitFiles(filePaths []string, commitMessage, author string) (plumbing.Hash, error) {
+	g.committed = filePaths
+	g.author = author
+	return plumbing.Hash{}, nil
+}
+
+func (g *dependencyRemediateGitMock) PushBranch(branchName, username, password string) error {
+	g.pushedBranch = branchName
+	return g.pushError
+}
+
+type dependencyRemediatePRMock struct {
+	*ghPRMock
+	open        []*github.PullRequest
+	listOptions *github.PullRequestListOptions
+	edited      *github.PullRequest
+	number      int
+}
+
+func (g *dependencyRemediatePRMock) List(ctx context.Context, owner string, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
+	g.listOptions = opts
+	return g.open, nil, nil
+}
+
+func (g *dependencyRemediatePRMock) Edit(ctx context.Context, owner string, repo string, number int, pull *github.PullRequest) (*github.PullRequest, *github.Response, error) {
+	g.number = number
+	g.edited = pull
+	return pull, nil, nil
+}
+
+func newDependencyRemediatePRMock(open ...*github.PullRequest) *dependencyRemediatePRMock {
+	return &dependencyRemediatePRMock{ghPRMock: &ghPRMock{}, open: open}
+}
+
+type blackDuckRemediationClientMock struct {
+	remediatingRequests []string
+}
+
+func (c *blackDuckRemediationClientMock) GetComponents(projectName, versionName string) (*bd.Components, error) {
+	return &bd.Components{Items: []bd.Component{
+		{Name: "lodash", Version: "4.17.11", Origins: []bd.ComponentOrigin{{ExternalNamespace: "npmjs", ExternalID: "lodash/4.17.11"}}},
+	}}, nil
+}
+
+func (c *blackDuckRemediationClientMock) GetVulnerabilities(projectName, versionName string) (*bd.Vulnerabilities, error) {
+	return &bd.Vulnerabilities{Items: []bd.Vulnerability{
+		{Name: "lodash", Version: "4.17.11", ComponentVersion: "https://bd/api/components/1/versions/1", VulnerabilityWithRemediation: bd.VulnerabilityWithRemediation{VulnerabilityName: "CVE-2019-10744", RemediationStatus: "NEW"}},
+		{Name: "lodash", Version: "4.17.11", ComponentVersion: "https://bd/api/components/1/versions/1", VulnerabilityWithRemediation: bd.VulnerabilityWithRemediation{VulnerabilityName: "CVE-2020-8203", RemediationStatus: "NEW"}},
+		{Name: "lodash", Version: "4.17.11", ComponentVersion: "https://bd/api/components/1/versions/1", VulnerabilityWithRemediation: bd.VulnerabilityWithRemediation{VulnerabilityName: "CVE-2000-0001", RemediationStatus: "IGNORED"}},
+	}}, nil
+}
+
+func (c *blackDuckRemediationClientMock) GetRemediatingVersions(componentVersion string) (*bd.RemediatingVersions, error) {
+	c.remediatingRequests = append(c.remediatingRequests, componentVersion)
+	return &bd.RemediatingVersions{FixesPreviousVulnerabilities: &bd.RemediatingVersion{Name: "4.17.21"}}, nil
+}
+
+type whiteSourceRemediationClientMock struct {
+	projectToken string
+}
+
+func (c *whiteSourceRemediationClientMock) GetProductByName(productName string) (ws.Product, error) {
+	return ws.Product{Name: productName, Token: "product-token"}, nil
+}
+
+func (c *whiteSourceRemediationClientMock) GetProjectToken(productToken, projectName string) (string, error) {
+	return c.projectToken, nil
+}
+
+func (c *whiteSourceRemediationClientMock) GetProjectAlertsByType(projectToken, alertType string) ([]ws.Alert, error) {
+	return []ws.Alert{{
+		Type:          alertType,
+		Library:       ws.Library{GroupID: "org.apache.logging.log4j", ArtifactID: "log4j-core", Version: "2.14.1", LibType: "Java"},
+		Vulnerability: ws.Vulnerability{Name: "CVE-2021-44228", TopFix: ws.Fix{Type: "UPGRADE_VERSION", FixResolution: "Upgrade to version 2.17.1"}},
+	}}, nil
+}
+
+func TestRunDependencyRemediate(t *testing.T) {
+	t.Parallel()
+
+	upgrades := []remediation.Upgrade{
+		{PackageType: "npm", Name: "lodash", Version: "4.17.11", FixedVersion: "4.17.21", Vulnerabilities: []string{"CVE-2019-10744"}},
+		{PackageType: "npm", Name: "express", Version: "3.0.0", FixedVersion: "4.0.0", Vulnerabilities: []string{"CVE-2000-0001"}},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		config := dependencyRemediateOptions{BranchName: "remediation", CommitMessage: "Upgrade vulnerable dependencies", Username: "piper", Token: "token", Owner: "org", Repository: "repo", Labels: []string{"security"}}
+		utils := &mock.FilesMock{}
+		utils.AddFile("package.json", []byte(`{"dependencies": {"lodash": "^4.17.11", "express": "3.0.0"}}`))
+		utils.AddFile("node_modules/lib/package.json", []byte(`{"dependencies": {"lodash": "4.17.11"}}`))
+		gitUtils := &dependencyRemediateGitMock{branch: "main"}
+		prService := newDependencyRemediatePRMock()
+		issueService := &ghIssueMock{}
+
+		err := runDependencyRemediate(context.Background(), &config, &remediationSourceMock{upgrades: upgrades}, utils, gitUtils, prService, issueService)
+
+		require.NoError(t, err)
+		content, _ := utils.FileRead("package.json")
+		assert.Equal(t, `{"dependencies": {"lodash": "^4.17.21", "express": "3.0.0"}}`, string(content))
+		content, _ = utils.FileRead("node_modules/lib/package.json")
+		assert.Equal(t, `{"dependencies": {"lodash": "4.17.11"}}`, string(content))
+
+		assert.Equal(t, "remediation", gitUtils.createdBranch)
+		assert.Equal(t, []string{"package.json"}, gitUtils.committed)
+		assert.Equal(t, "piper", gitUtils.author)
+		assert.Equal(t, "remediation", gitUtils.pushedBranch)
+
+		assert.Equal(t, "org", prService.owner)
+		assert.Equal(t, "main", prService.pullrequest.GetBase())
+		assert.Equal(t, "remediation", prService.pullrequest.GetHead())
+		assert.Equal(t, "Upgrade vulnerable dependencies", prService.pullrequest.GetTitle())
+		assert.Contains(t, prService.pullrequest.GetBody(), "| lodash | package.json | ^4.17.11 | 4.17.21 | CVE-2019-10744 |")
+		assert.Contains(t, prService.pullrequest.GetBody(), "| express | 3.0.0 | 4.0.0 | major version upgrade | CVE-2000-0001 |")
+		assert.Equal(t, []string{"security"}, *issueService.issueRequest.Labels)
+		assert.Equal(t, "org:remediation", prService.listOptions.Head)
+		assert.Equal(t, "main", prService.listOptions.Base)
+		assert.Nil(t, prService.edited)
+	})
+
+	t.Run("success - update existing pull request", func(t *testing.T) {
+		t.Parallel()
+		config := dependencyRemediateOptions{BranchName: "remediation", Base: "main", CommitMessage: "Upgrade vulnerable dependencies", Owner: "org", Repository: "repo", Labels: []string{"security"}}
+		utils := &mock.FilesMock{}
+		utils.AddFile("package.json", []byte(`{"dependencies": {"lodash": "^4.17.11"}}`))
+		gitUtils := &dependencyRemediateGitMock{}
+		number := 42
+		prService := newDependencyRemediatePRMock(&github.PullRequest{Number: &number})
+		issueService := &ghIssueMock{}
+
+		err := runDependencyRemediate(context.Background(), &config, &remediationSourceMock{upgrades: upgrades}, utils, gitUtils, prService, issueService)
+
+		require.NoError(t, err)
+		assert.Equal(t, "remediation", gitUtils.pushedBranch)
+		assert.Nil(t, prService.pullrequest, "no additional pull request must be created")
+		assert.Equal(t, 42, prService.number)
+		assert.Equal(t, "Upgrade vulnerable dependencies", prService.edited.GetTitle())
+		assert.Contains(t, prService.edited.GetBody(), "| lodash | package.json | ^4.17.11 | 4.17.21 | CVE-2019-10744 |")
+		assert.Equal(t, 42, issueService.number)
+		assert.Equal(t, []string{"security"}, *issueService.issueRequest.Labels)
+	})
+
+	t.Run("success - no upgrades applicable", func(t *testing.T) {
+		t.Parallel()
+		config := dependencyRemediateOptions{BranchName: "remediation", Base: "main"}
+		utils := &mock.FilesMock{}
+		utils.AddFile("package.json", []byte(`{"dependencies": {"express": "3.0.0"}}`))
+		gitUtils := &dependencyRemediateGitMock{}
+		prService := newDependencyRemediatePRMock()
+
+		err := runDependencyRemediate(context.Background(), &config, &remediationSourceMock{upgrades: upgrades}, utils, gitUtils, prService, &ghIssueMock{})
+
+		assert.NoError(t, err)
+		assert.Empty(t, gitUtils.createdBranch)
+		assert.Nil(t, prService.pullrequest)
+	})
+
+	t.Run("error - remediation information", func(t *testing.T) {
+		t.Parallel()
+		config := dependencyRemediateOptions{}
+
+		err := runDependencyRemediate(context.Background(), &config, &remediationSourceMock{err: fmt.Errorf("unauthorized")}, &mock.FilesMock{}, &dependencyRemediateGitMock{}, newDependencyRemediatePRMock(), &ghIssueMock{})
+
+		assert.EqualError(t, err, "failed to retrieve remediation information: unauthorized")
+	})
+
+	t.Run("error - push", func(t *testing.T) {
+		t.Parallel()
+		config := dependencyRemediateOptions{BranchName: "remediation", Base: "main"}
+		utils := &mock.FilesMock{}
+		utils.AddFile("package.json", []byte(`{"dependencies": {"lodash": "4.17.11"}}`))
+		gitUtils := &dependencyRemediateGitMock{pushError: fmt.Errorf("failed to push branch 'remediation'")}
+		prService := newDependencyRemediatePRMock()
+
+		err := runDependencyRemediate(context.Background(), &config, &remediationSourceMock{upgrades: upgrades}, utils, gitUtils, prService, &ghIssueMock{})
+
+		assert.EqualError(t, err, "pushing changes failed: failed to push branch 'remediation'")
+		assert.Nil(t, prService.pullrequest)
+	})
+}
+
+func TestDependencyRemediationSources(t *testing.T) {
+	t.Parallel()
+
+	t.Run("BlackDuck", func(t *testing.T) {
+		t.Parallel()
+		client := &blackDuckRemediationClientMock{}
+		source := blackDuckRemediationSource{client: client, projectName: "project", versionName: "1"}
+
+		upgrades, err := source.Upgrades()
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://bd/api/components/1/versions/1"}, client.remediatingRequests)
+		assert.Equal(t, []remediation.Upgrade{
+			{PackageType: "npm", Name: "lodash", Version: "4.17.11", FixedVersion: "4.17.21", Vulnerabilities: []string{"CVE-2019-10744", "CVE-2020-8203"}},
+		}, remediation.Merge(upgrades))
+	})
+
+	t.Run("WhiteSource", func(t *testing.T) {
+		t.Parallel()
+		source := whiteSourceRemediationSource{client: &whiteSourceRemediationClientMock{projectToken: "project-token"}, productName: "product", projectName: "project - 1"}
+
+		upgrades, err := source.Upgrades()
+
+		require.NoError(t, err)
+		assert.Equal(t, []remediation.Upgrade{
+			{PackageType: "maven", Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", FixedVersion: "2.17.1", Vulnerabilities: []string{"CVE-2021-44228"}},
+		}, upgrades)
+	})
+
+	t.Run("WhiteSource - project not found", func(t *testing.T) {
+		t.Parallel()
+		source := whiteSourceRemediationSource{client: &whiteSourceRemediationClientMock{}, productName: "product", projectName: "project - 1"}
+
+		_, err := source.Upgrades()
+
+		assert.EqualError(t, err, "project 'project - 1' not found in product 'product'")
+	})
+}
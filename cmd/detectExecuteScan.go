@@ -14,6 +14,7 @@ import (
 
 	bd "github.com/SAP/jenkins-library/pkg/blackduck"
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperGithub "github.com/SAP/jenkins-library/pkg/github"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -552,7 +553,7 @@ func createVulnerabilityReport(config detectExecuteScanOptions, vulns *bd.Vulner
 }
 
 func isActiveVulnerability(v bd.Vulnerability) bool {
-	if v.Ignored {
+	if v.Ignored || v.Assessment != nil {
 		return false
 	}
 	switch v.VulnerabilityWithRemediation.RemediationStatus {
@@ -568,7 +569,7 @@ func isActiveVulnerability(v bd.Vulnerability) bool {
 }
 
 func isMajorVulnerability(v bd.Vulnerability) bool {
-	if v.Ignored {
+	if v.Ignored || v.Assessment != nil {
 		return false
 	}
 	switch v.VulnerabilityWithRemediation.Severity {
@@ -610,7 +611,12 @@ func postScanChecksAndReporting(ctx context.Context, config detectExecuteScanOpt
 	}
 
 	errorsOccured := []string{}
-	vulns, err := getVulnerabilitiesWithComponents(config, influx, sys)
+	assessments := readAssessmentsFromFile(config.AssessmentFile, utils)
+	components, err := sys.Client.GetComponents(config.ProjectName, getVersionName(config))
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch components")
+	}
+	vulns, err := getVulnerabilitiesWithComponents(config, influx, sys, components, assessments)
 	if err != nil {
 		return errors.Wrap(err, "failed to fetch vulnerabilities")
 	}
@@ -638,12 +644,30 @@ func postScanChecksAndReporting(ctx context.Context, config detectExecuteScanOpt
 		projectLink = projectVersion.Href
 	}
 
+	manifestLocator, err := bd.NewManifestLocator(utils)
+	if err != nil {
+		log.Entry().WithError(err).Warning("failed to read build descriptors, SARIF results are not located in the build descriptors")
+	}
+
 	sarif := bd.CreateSarifResultFile(vulns, config.ProjectName, config.Version, projectLink)
+	if manifestLocator != nil {
+		manifestLocator.AddLocations(sarif)
+	}
 	paths, err := bd.WriteSarifFile(sarif, utils)
 	if err != nil {
 		errorsOccured = append(errorsOccured, fmt.Sprint(err))
 	}
 
+	policySarif := bd.CreatePolicyViolationSarifResultFile(components, config.ProjectName, config.Version, projectLink)
+	if manifestLocator != nil {
+		manifestLocator.AddLocations(policySarif)
+	}
+	policySarifPaths, err := bd.WritePolicyViolationSarifFile(policySarif, utils)
+	if err != nil {
+		log.Entry().WithError(err).Warning("failed to write SARIF file of the policy violations")
+	}
+	paths = append(paths, policySarifPaths...)
+
 	scanReport := createVulnerabilityReport(config, vulns, influx, sys)
 	vulnerabilityReportPaths, err := bd.WriteVulnerabilityReports(scanReport, utils)
 	if err != nil {
@@ -651,6 +675,12 @@ func postScanChecksAndReporting(ctx context.Context, config detectExecuteScanOpt
 	}
 	paths = append(paths, vulnerabilityReportPaths...)
 
+	vexPaths, err := format.WriteVEX(bd.ReportsDirectory, "BlackDuck", bd.CreateVEXStatements(vulns), utils)
+	if err != nil {
+		errorsOccured = append(errorsOccured, fmt.Sprint(err))
+	}
+	paths = append(paths, vexPaths...)
+
 	policyStatus, err := getPolicyStatus(config, influx, sys)
 	if err != nil {
 		errorsOccured = append(errorsOccured, fmt.Sprint(err))
@@ -684,12 +714,8 @@ func postScanChecksAndReporting(ctx context.Context, config detectExecuteScanOpt
 	return nil
 }
 
-func getVulnerabilitiesWithComponents(config detectExecuteScanOptions, influx *detectExecuteScanInflux, sys *blackduckSystem) (*bd.Vulnerabilities, error) {
+func getVulnerabilitiesWithComponents(config detectExecuteScanOptions, influx *detectExecuteScanInflux, sys *blackduckSystem, components *bd.Components, assessments *[]format.Assessment) (*bd.Vulnerabilities, error) {
 	detectVersionName := getVersionName(config)
-	components, err := sys.Client.GetComponents(config.ProjectName, detectVersionName)
-	if err != nil {
-		return nil, err
-	}
 	// create component lookup map to interconnect vulnerability and component
 	keyFormat := "%v/%v"
 	componentLookup := map[string]*bd.Component{}
@@ -705,18 +731,22 @@ func getVulnerabilitiesWithComponents(config detectExecuteScanOptions, influx *d
 	majorVulns := 0
 	activeVulns := 0
 	for index, vuln := range vulns.Items {
-		if isActiveVulnerability(vuln) {
-			activeVulns++
-			if isMajorVulnerability(vuln) {
-				majorVulns++
-			}
-		}
 		component := componentLookup[fmt.Sprintf(keyFormat, vuln.Name, vuln.Version)]
 		if component != nil && len(component.Name) > 0 {
 			vulns.Items[index].Component = component
 		} else {
 			vulns.Items[index].Component = &bd.Component{Name: vuln.Name, Version: vuln.Version}
 		}
+		// assessed vulnerabilities are not considered for the evaluation of thresholds
+		if assessments != nil && len(*assessments) > 0 {
+			vulns.Items[index].ContainedIn(assessments)
+		}
+		if isActiveVulnerability(vulns.Items[index]) {
+			activeVulns++
+			if isMajorVulnerability(vulns.Items[index]) {
+				majorVulns++
+			}
+		}
 	}
 	influx.detect_data.fields.vulnerabilities = activeVulns
 	influx.detect_data.fields.major_vulnerabilities = majorVulns
@@ -33,6 +33,7 @@ type detectExecuteScanOptions struct {
 	ServerURL                   string   `json:"serverUrl,omitempty"`
 	Groups                      []string `json:"groups,omitempty"`
 	FailOn                      []string `json:"failOn,omitempty" validate:"possible-values=ALL BLOCKER CRITICAL MAJOR MINOR NONE"`
+	AssessmentFile              string   `json:"assessmentFile,omitempty"`
 	VersioningModel             string   `json:"versioningModel,omitempty" validate:"possible-values=major major-minor semantic full"`
 	Version                     string   `json:"version,omitempty"`
 	CustomScanVersion           string   `json:"customScanVersion,omitempty"`
@@ -132,7 +133,9 @@ func (p *detectExecuteScanReports) persist(stepConfig detectExecuteScanOptions,
 		{FilePattern: "**/piper_detect_vulnerability_report.html", ParamRef: "", StepResultType: "blackduck-security"},
 		{FilePattern: "**/toolrun_detectExecute_*.json", ParamRef: "", StepResultType: "blackduck-security"},
 		{FilePattern: "**/piper_detect_vulnerability.sarif", ParamRef: "", StepResultType: "blackduck-security"},
+		{FilePattern: "**/piper_detect_policy_violation.sarif", ParamRef: "", StepResultType: "blackduck-security"},
 		{FilePattern: "**/piper_hub_detect_sbom.xml", ParamRef: "", StepResultType: "blackduck-security"},
+		{FilePattern: "**/blackduck/piper_vex.json", ParamRef: "", StepResultType: "blackduck-security"},
 	}
 	envVars := []gcs.EnvVar{
 		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
@@ -229,6 +232,7 @@ Please configure your BlackDuck server Url using the serverUrl parameter and the
 				influx.persist(GeneralConfig.EnvRootPath, "influx")
 				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
 				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
 				stepTelemetryData.PiperCommitHash = GitCommit
@@ -254,7 +258,12 @@ Please configure your BlackDuck server Url using the serverUrl parameter and the
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
-			detectExecuteScan(stepConfig, &stepTelemetryData, &influx)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+				influx = detectExecuteScanInflux{}
+			}, func() {
+				detectExecuteScan(stepConfig, &stepTelemetryData, &influx)
+			})
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
@@ -276,6 +285,7 @@ func addDetectExecuteScanFlags(cmd *cobra.Command, stepConfig *detectExecuteScan
 	cmd.Flags().StringVar(&stepConfig.ServerURL, "serverUrl", os.Getenv("PIPER_serverUrl"), "Server URL to the Synopsis Detect (formerly BlackDuck) Server.")
 	cmd.Flags().StringSliceVar(&stepConfig.Groups, "groups", []string{}, "Users groups to be assigned for the Project")
 	cmd.Flags().StringSliceVar(&stepConfig.FailOn, "failOn", []string{`BLOCKER`}, "Mark the current build as fail based on the policy categories applied.")
+	cmd.Flags().StringVar(&stepConfig.AssessmentFile, "assessmentFile", `hs-assessments.yaml`, "Explicit path to the assessment YAML file. Assessed vulnerabilities are not considered for the vulnerability thresholds and are written as CycloneDX VEX.")
 	cmd.Flags().StringVar(&stepConfig.VersioningModel, "versioningModel", `major`, "The versioning model used for result reporting (based on the artifact version). Example 1.2.3 using `major` will result in version 1")
 	cmd.Flags().StringVar(&stepConfig.Version, "version", os.Getenv("PIPER_version"), "Defines the version number of the artifact being build in the pipeline. It is used as source for the Detect version.")
 	cmd.Flags().StringVar(&stepConfig.CustomScanVersion, "customScanVersion", os.Getenv("PIPER_customScanVersion"), "A custom version used along with the uploaded scan results.")
@@ -440,6 +450,15 @@ func detectExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{{Name: "detect/failOn"}},
 						Default:     []string{`BLOCKER`},
 					},
+					{
+						Name:        "assessmentFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `hs-assessments.yaml`,
+					},
 					{
 						Name:        "versioningModel",
 						ResourceRef: []config.ResourceReference{},
@@ -765,7 +784,9 @@ func detectExecuteScanMetadata() config.StepData {
 							{"filePattern": "**/piper_detect_vulnerability_report.html", "type": "blackduck-security"},
 							{"filePattern": "**/toolrun_detectExecute_*.json", "type": "blackduck-security"},
 							{"filePattern": "**/piper_detect_vulnerability.sarif", "type": "blackduck-security"},
+							{"filePattern": "**/piper_detect_policy_violation.sarif", "type": "blackduck-security"},
 							{"filePattern": "**/piper_hub_detect_sbom.xml", "type": "blackduck-security"},
+							{"filePattern": "**/blackduck/piper_vex.json", "type": "blackduck-security"},
 						},
 					},
 				},
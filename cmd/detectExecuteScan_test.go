@@ -883,6 +883,23 @@ func TestPostScanChecksAndReporting(t *testing.T) {
 		content, err := utils.FileRead("blackduck-ip.json")
 		assert.NoError(t, err)
 		assert.Contains(t, string(content), `"policyViolations":2`)
+		exists, err := utils.FileExists(filepath.Join(bd.ReportsDirectory, "piper_detect_policy_violation.sarif"))
+		assert.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("Policy violation SARIF not written", func(t *testing.T) {
+		ctx := context.Background()
+		config := detectExecuteScanOptions{Token: "token", ServerURL: "https://my.blackduck.system", ProjectName: "SHC-PiperTest", Version: "", CustomScanVersion: "1.0"}
+		utils := newDetectTestUtilsBundle(false)
+		utils.FileWriteErrors = map[string]error{filepath.Join(bd.ReportsDirectory, "piper_detect_policy_violation.sarif"): fmt.Errorf("write error")}
+		sys := newBlackduckMockSystem(config)
+		err := postScanChecksAndReporting(ctx, config, &detectExecuteScanInflux{}, utils, &sys)
+
+		assert.EqualError(t, err, "License Policy Violations found")
+		exists, err := utils.FileExists(filepath.Join(bd.ReportsDirectory, "piper_detect_vulnerability.sarif"))
+		assert.NoError(t, err)
+		assert.True(t, exists)
 	})
 }
 
@@ -982,7 +999,9 @@ func TestGetVulnerabilitiesWithComponents(t *testing.T) {
 		config := detectExecuteScanOptions{Token: "token", ServerURL: "https://my.blackduck.system", ProjectName: "SHC-PiperTest", Version: "", CustomScanVersion: "1.0"}
 		sys := newBlackduckMockSystem(config)
 
-		vulns, err := getVulnerabilitiesWithComponents(config, &detectExecuteScanInflux{}, &sys)
+		components, err := sys.Client.GetComponents("SHC-PiperTest", "1.0")
+		assert.NoError(t, err)
+		vulns, err := getVulnerabilitiesWithComponents(config, &detectExecuteScanInflux{}, &sys, components, nil)
 		assert.NoError(t, err)
 		vulnerabilitySpring := bd.Vulnerability{}
 		vulnerabilityLog4j1 := bd.Vulnerability{}
@@ -25,6 +25,7 @@ import (
 	"github.com/piper-validation/fortify-client-go/models"
 
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/fortify"
 	"github.com/SAP/jenkins-library/pkg/gradle"
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -150,8 +151,13 @@ func determineArtifact(config fortifyExecuteScanOptions, utils fortifyUtils) (ve
 
 func runFortifyScan(ctx context.Context, config fortifyExecuteScanOptions, sys fortify.System, utils fortifyUtils, telemetryData *telemetry.CustomData, influx *fortifyExecuteScanInflux, auditStatus map[string]string) ([]piperutils.Path, error) {
 	var reports []piperutils.Path
-	log.Entry().Debugf("Running Fortify scan against SSC at %v", config.ServerURL)
 	executableList := []string{"fortifyupdate", "sourceanalyzer"}
+	if config.LocalOnly {
+		log.Entry().Debug("Running Fortify scan locally without SSC")
+		executableList = []string{"sourceanalyzer"}
+	} else {
+		log.Entry().Debugf("Running Fortify scan against SSC at %v", config.ServerURL)
+	}
 	for _, exec := range executableList {
 		_, err := execInPath(exec)
 		if err != nil {
@@ -188,6 +194,9 @@ func runFortifyScan(ctx context.Context, config fortifyExecuteScanOptions, sys f
 	}
 
 	fortifyProjectName, fortifyProjectVersion := versioning.DetermineProjectCoordinatesWithCustomVersion(config.ProjectName, config.VersioningModel, config.CustomScanVersion, coordinates)
+	if config.LocalOnly {
+		return runFortifyLocalScan(ctx, config, utils, fortifyProjectName, fortifyProjectVersion, influx)
+	}
 	project, err := sys.GetProjectByName(fortifyProjectName, config.AutoCreate, fortifyProjectVersion)
 	if err != nil {
 		classifyErrorOnLookup(err)
@@ -242,7 +251,7 @@ func runFortifyScan(ctx context.Context, config fortifyExecuteScanOptions, sys f
 
 	if config.VerifyOnly {
 		log.Entry().Infof("Starting audit status check on project %v with version %v and project version ID %v", fortifyProjectName, fortifyProjectVersion, projectVersion.ID)
-		paths, err := verifyFFProjectCompliance(ctx, config, utils, sys, project, projectVersion, filterSet, influx, auditStatus)
+		paths, err := verifyFFProjectCompliance(ctx, config, utils, sys, project, projectVersion, filterSet, influx, auditStatus, nil)
 		reports = append(reports, paths...)
 		return reports, err
 	}
@@ -311,6 +320,7 @@ func runFortifyScan(ctx context.Context, config fortifyExecuteScanOptions, sys f
 	}
 
 	// SARIF conversion done after latest FPR is processed, but before the compliance is checked
+	var baselineDiff *format.SarifDiff
 	if config.ConvertToSarif {
 		resultFilePath := fmt.Sprintf("%vtarget/result.fpr", config.ModulePath)
 		log.Entry().Info("Calling conversion to SARIF function.")
@@ -331,21 +341,171 @@ func runFortifyScan(ctx context.Context, config fortifyExecuteScanOptions, sys f
 			return reports, fmt.Errorf("failed to write gzip sarif")
 		}
 		reports = append(reports, paths...)
+
+		baselineDiff, paths, err = newSarifBaseline("fortifyExecuteScan", config.SarifBaselineLocation).process(filepath.Join(fortify.ReportsDirectory, "result.sarif"), "Fortify", fortify.ReportsDirectory)
+		if err != nil {
+			log.Entry().WithError(err).Warning("failed to process SARIF baseline")
+		}
+		reports = append(reports, paths...)
 	}
 
 	log.Entry().Infof("Starting audit status check on project %v with version %v and project version ID %v", fortifyProjectName, fortifyProjectVersion, projectVersion.ID)
-	paths, err := verifyFFProjectCompliance(ctx, config, utils, sys, project, projectVersion, filterSet, influx, auditStatus)
+	paths, err := verifyFFProjectCompliance(ctx, config, utils, sys, project, projectVersion, filterSet, influx, auditStatus, baselineDiff)
 	reports = append(reports, paths...)
 	return reports, err
 }
 
+// runFortifyLocalScan scans the project and checks the compliance based on the FPR file only, without any call to Fortify SSC
+func runFortifyLocalScan(ctx context.Context, config fortifyExecuteScanOptions, utils fortifyUtils, fortifyProjectName, fortifyProjectVersion string, influx *fortifyExecuteScanInflux) ([]piperutils.Path, error) {
+	reports := []piperutils.Path{}
+	log.Entry().Infof("Scanning project %v with version %v locally", fortifyProjectName, fortifyProjectVersion)
+	buildLabel := fmt.Sprintf("%v/repos/%v/%v/commits/%v", config.GithubAPIURL, config.Owner, config.Repository, config.CommitID)
+
+	buildID := uuid.New().String()
+	utils.SetDir(config.ModulePath)
+	if err := os.MkdirAll(fmt.Sprintf("%v/%v", config.ModulePath, "target"), os.ModePerm); err != nil {
+		log.Entry().WithError(err).Error("failed to create directory")
+	}
+
+	err := triggerFortifyScan(config, utils, buildID, buildLabel, fortifyProjectName)
+	reports = append(reports, piperutils.Path{Target: fmt.Sprintf("%vtarget/fortify-scan.*", config.ModulePath)})
+	reports = append(reports, piperutils.Path{Target: fmt.Sprintf("%vtarget/*.fpr", config.ModulePath)})
+	if err != nil {
+		return reports, errors.Wrapf(err, "failed to scan project")
+	}
+
+	var audit *fortify.LocalAudit
+	if len(config.LocalAuditFile) > 0 {
+		auditData, err := utils.FileRead(config.LocalAuditFile)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return reports, errors.Wrapf(err, "failed to read audit file %v", config.LocalAuditFile)
+		}
+		if audit, err = fortify.ParseLocalAudit(auditData); err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return reports, errors.Wrapf(err, "failed to load audit file %v", config.LocalAuditFile)
+		}
+	}
+
+	resultFilePath := fmt.Sprintf("%vtarget/result.fpr", config.ModulePath)
+	log.Entry().Info("Calling conversion to SARIF function.")
+	result, err := fortify.ConvertLocalFprToSarif(resultFilePath, audit)
+	if err != nil {
+		return reports, errors.Wrap(err, "failed to generate SARIF")
+	}
+	log.Entry().Debug("Writing simplified sarif file in plain text to disk.")
+	paths, err := fortify.WriteSarif(result.SarifSimplified, "result.sarif")
+	if err != nil {
+		return reports, fmt.Errorf("failed to write simplified sarif")
+	}
+	reports = append(reports, paths...)
+
+	log.Entry().Debug("Writing full sarif file to disk and gzip it.")
+	paths, err = fortify.WriteGzipSarif(result.Sarif, "result.sarif.gz")
+	if err != nil {
+		return reports, fmt.Errorf("failed to write gzip sarif")
+	}
+	reports = append(reports, paths...)
+
+	baselineDiff, paths, err := newSarifBaseline("fortifyExecuteScan", config.SarifBaselineLocation).process(filepath.Join(fortify.ReportsDirectory, "result.sarif"), "Fortify", fortify.ReportsDirectory)
+	if err != nil {
+		log.Entry().WithError(err).Warning("failed to process SARIF baseline")
+	}
+	reports = append(reports, paths...)
+
+	violations := result.Violations(config.LocalFailOnPriorities, config.LocalFailOnCategories)
+	log.Entry().Infof("Counted %v violations with priority %v or category %v", len(violations), config.LocalFailOnPriorities, config.LocalFailOnCategories)
+	if baselineDiff != nil && config.EnforceThresholdsOnNewFindings {
+		violations = newLocalViolations(violations, baselineDiff)
+		log.Entry().Infof("Counted %v violations on findings not contained in the baseline", len(violations))
+	}
+
+	reportData := fortify.CreateLocalReportData(fortifyProjectName, fortifyProjectVersion, result, len(violations))
+	influx.fortify_data.fields.projectName = reportData.ProjectName
+	influx.fortify_data.fields.projectVersion = reportData.ProjectVersion
+	influx.fortify_data.fields.violations = reportData.Violations
+	influx.fortify_data.fields.exploitable = reportData.Exploitable
+	influx.fortify_data.fields.suppressed = reportData.Suppressed
+	influx.fortify_data.fields.suspicious = reportData.Suspicious
+
+	scanReport := fortify.CreateLocalCustomReport(reportData, result)
+	paths, err = fortify.WriteCustomReports(scanReport)
+	if err != nil {
+		return reports, errors.Wrap(err, "failed to write custom reports")
+	}
+	reports = append(reports, paths...)
+
+	if config.CreateResultIssue && len(violations) > 0 && len(config.GithubToken) > 0 && len(config.GithubAPIURL) > 0 && len(config.Owner) > 0 && len(config.Repository) > 0 {
+		log.Entry().Debug("Creating/updating GitHub issue with scan results")
+		gh := reporting.GitHub{
+			Owner:         &config.Owner,
+			Repository:    &config.Repository,
+			Assignees:     &config.Assignees,
+			IssueService:  utils.GetIssueService(),
+			SearchService: utils.GetSearchService(),
+		}
+		if err := gh.UploadSingleReport(ctx, scanReport); err != nil {
+			return reports, fmt.Errorf("failed to upload scan results into GitHub: %w", err)
+		}
+	}
+
+	paths, err = fortify.WriteJSONReport(reportData)
+	if err != nil {
+		return reports, errors.Wrap(err, "failed to write json report")
+	}
+	reports = append(reports, paths...)
+
+	if len(violations) > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return reports, errors.New("fortify scan failed, the project is not compliant. For details check the archived report")
+	}
+	return reports, nil
+}
+
+// newLocalViolations reduces the violations to the ones of findings which are not contained in the SARIF baseline
+func newLocalViolations(violations []fortify.LocalIssue, baselineDiff *format.SarifDiff) []fortify.LocalIssue {
+	newInstanceIDs := map[string]bool{}
+	for _, result := range baselineDiff.New {
+		newInstanceIDs[result.PartialFingerprints.FortifyInstanceID] = true
+	}
+	newViolations := []fortify.LocalIssue{}
+	for _, violation := range violations {
+		if newInstanceIDs[violation.InstanceID] {
+			newViolations = append(newViolations, violation)
+		}
+	}
+	return newViolations
+}
+
+// newSSCViolations counts the new findings which violate the audit compliance: unaudited findings in the issue groups which must be audited
+// as well as findings audited as exploitable or suspicious.
+// Missing spot checks relate to a whole category and thus cannot be attributed to a single new finding.
+func newSSCViolations(config fortifyExecuteScanOptions, newFindings []format.Results) int {
+	violations := 0
+	for _, finding := range newFindings {
+		properties := finding.Properties
+		if properties == nil {
+			continue
+		}
+		switch {
+		case !properties.Audited && len(properties.FortifyCategory) > 0 && strings.Contains(config.MustAuditIssueGroups, properties.FortifyCategory):
+			violations++
+		case properties.Audited && properties.ToolState == "Exploitable":
+			violations++
+		case properties.Audited && properties.ToolState == "Suspicious" && config.ConsiderSuspicious:
+			violations++
+		}
+	}
+	return violations
+}
+
 func classifyErrorOnLookup(err error) {
 	if strings.Contains(err.Error(), "connect: connection refused") || strings.Contains(err.Error(), "net/http: TLS handshake timeout") {
 		log.SetErrorCategory(log.ErrorService)
 	}
 }
 
-func verifyFFProjectCompliance(ctx context.Context, config fortifyExecuteScanOptions, utils fortifyUtils, sys fortify.System, project *models.Project, projectVersion *models.ProjectVersion, filterSet *models.FilterSet, influx *fortifyExecuteScanInflux, auditStatus map[string]string) ([]piperutils.Path, error) {
+func verifyFFProjectCompliance(ctx context.Context, config fortifyExecuteScanOptions, utils fortifyUtils, sys fortify.System, project *models.Project, projectVersion *models.ProjectVersion, filterSet *models.FilterSet, influx *fortifyExecuteScanInflux, auditStatus map[string]string, baselineDiff *format.SarifDiff) ([]piperutils.Path, error) {
 	reports := []piperutils.Path{}
 	// Generate report
 	if config.Reporting {
@@ -380,6 +540,10 @@ func verifyFFProjectCompliance(ctx context.Context, config fortifyExecuteScanOpt
 	issueGroups = append(issueGroups, issueGroupsSuspiciousExploitable...)
 
 	log.Entry().Infof("Counted %v violations, details: %v", numberOfViolations, auditStatus)
+	if baselineDiff != nil && config.EnforceThresholdsOnNewFindings {
+		numberOfViolations = newSSCViolations(config, baselineDiff.New)
+		log.Entry().Infof("Counted %v violations on findings not contained in the baseline", numberOfViolations)
+	}
 
 	influx.fortify_data.fields.projectID = project.ID
 	influx.fortify_data.fields.projectName = *project.Name
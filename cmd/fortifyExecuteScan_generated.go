@@ -25,7 +25,7 @@ type fortifyExecuteScanOptions struct {
 	AdditionalScanParameters        []string `json:"additionalScanParameters,omitempty"`
 	AdditionalMvnParameters         []string `json:"additionalMvnParameters,omitempty"`
 	Assignees                       []string `json:"assignees,omitempty"`
-	AuthToken                       string   `json:"authToken,omitempty"`
+	AuthToken                       string   `json:"authToken,omitempty" validate:"required_if=LocalOnly false"`
 	BuildDescriptorExcludeList      []string `json:"buildDescriptorExcludeList,omitempty"`
 	CustomScanVersion               string   `json:"customScanVersion,omitempty"`
 	GithubToken                     string   `json:"githubToken,omitempty"`
@@ -59,10 +59,16 @@ type fortifyExecuteScanOptions struct {
 	ArtifactURL                     string   `json:"artifactUrl,omitempty"`
 	ConsiderSuspicious              bool     `json:"considerSuspicious,omitempty"`
 	ConvertToSarif                  bool     `json:"convertToSarif,omitempty"`
+	SarifBaselineLocation           string   `json:"sarifBaselineLocation,omitempty"`
+	EnforceThresholdsOnNewFindings  bool     `json:"enforceThresholdsOnNewFindings,omitempty"`
+	LocalOnly                       bool     `json:"localOnly,omitempty"`
+	LocalAuditFile                  string   `json:"localAuditFile,omitempty"`
+	LocalFailOnPriorities           []string `json:"localFailOnPriorities,omitempty" validate:"possible-values=Critical High Medium Low"`
+	LocalFailOnCategories           []string `json:"localFailOnCategories,omitempty"`
 	FprUploadEndpoint               string   `json:"fprUploadEndpoint,omitempty"`
 	ProjectName                     string   `json:"projectName,omitempty"`
 	Reporting                       bool     `json:"reporting,omitempty"`
-	ServerURL                       string   `json:"serverUrl,omitempty"`
+	ServerURL                       string   `json:"serverUrl,omitempty" validate:"required_if=LocalOnly false"`
 	PullRequestMessageRegexGroup    int      `json:"pullRequestMessageRegexGroup,omitempty"`
 	DeltaMinutes                    int      `json:"deltaMinutes,omitempty"`
 	SpotCheckMinimum                int      `json:"spotCheckMinimum,omitempty"`
@@ -170,6 +176,7 @@ func (p *fortifyExecuteScanReports) persist(stepConfig fortifyExecuteScanOptions
 		{FilePattern: "**/toolrun_fortify_*.json", ParamRef: "", StepResultType: "fortify"},
 		{FilePattern: "**/piper_fortify_report.json", ParamRef: "", StepResultType: "fortify"},
 		{FilePattern: "**/piper_fortify_report.html", ParamRef: "", StepResultType: "fortify"},
+		{FilePattern: "**/piper_differential_report.html", ParamRef: "", StepResultType: "fortify"},
 	}
 	envVars := []gcs.EnvVar{
 		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
@@ -222,7 +229,12 @@ Besides triggering a scan the step verifies the results after they have been upl
 * All issues must be audited from the Corporate Security Requirements folder.
 * All issues must be audited from the Audit All folder.
 * At least one issue per category must be audited from the Spot Checks of Each Category folder.
-* Nothing needs to be audited from the Optional folder.`,
+* Nothing needs to be audited from the Optional folder.
+
+!!! hint "Scanning without Fortify SSC"
+    With ` + "`" + `localOnly: true` + "`" + ` the step does not interact with Fortify SSC at all, so ` + "`" + `serverUrl` + "`" + ` and ` + "`" + `authToken` + "`" + ` are not required. After translate and scan, the resulting FPR file is converted to SARIF and a scan report directly.
+    The priority of each issue (Critical, High, Medium, Low) is calculated from the rule metadata contained in the FPR. Audit decisions are taken from the FPR and from the optional ` + "`" + `localAuditFile` + "`" + `, e.g. an ` + "`" + `audit.xml` + "`" + ` exported from Audit Workbench.
+    The step fails if issues which are neither suppressed nor audited as harmless match ` + "`" + `localFailOnPriorities` + "`" + ` or ` + "`" + `localFailOnCategories` + "`" + `.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -275,6 +287,7 @@ Besides triggering a scan the step verifies the results after they have been upl
 				influx.persist(GeneralConfig.EnvRootPath, "influx")
 				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
 				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
 				stepTelemetryData.PiperCommitHash = GitCommit
@@ -300,7 +313,12 @@ Besides triggering a scan the step verifies the results after they have been upl
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
-			fortifyExecuteScan(stepConfig, &stepTelemetryData, &influx)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+				influx = fortifyExecuteScanInflux{}
+			}, func() {
+				fortifyExecuteScan(stepConfig, &stepTelemetryData, &influx)
+			})
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
@@ -348,6 +366,12 @@ func addFortifyExecuteScanFlags(cmd *cobra.Command, stepConfig *fortifyExecuteSc
 	cmd.Flags().StringVar(&stepConfig.ArtifactURL, "artifactUrl", os.Getenv("PIPER_artifactUrl"), "Path/URL pointing to an additional artifact repository for resolution of additional artifacts during the build")
 	cmd.Flags().BoolVar(&stepConfig.ConsiderSuspicious, "considerSuspicious", true, "Whether suspicious issues should trigger the check to fail or not")
 	cmd.Flags().BoolVar(&stepConfig.ConvertToSarif, "convertToSarif", true, "Convert the proprietary format of Fortify scan results to the open SARIF standard.")
+	cmd.Flags().StringVar(&stepConfig.SarifBaselineLocation, "sarifBaselineLocation", os.Getenv("PIPER_sarifBaselineLocation"), "Location of the SARIF baselines, either a directory or a Google Cloud Storage folder (`gs://<bucket>/<folder>`). On pull requests, the SARIF results are compared with the baseline of the target branch, otherwise they are stored as baseline of the current branch.")
+	cmd.Flags().BoolVar(&stepConfig.EnforceThresholdsOnNewFindings, "enforceThresholdsOnNewFindings", false, "On pull requests, enforce the vulnerability thresholds only on findings which are not contained in the SARIF baseline of the target branch. Requires `sarifBaselineLocation`.")
+	cmd.Flags().BoolVar(&stepConfig.LocalOnly, "localOnly", false, "Scan the project with `sourceanalyzer` and assess the results of the local FPR file only, without any interaction with Fortify SSC. The priority of the issues is calculated from the rule metadata contained in the FPR.")
+	cmd.Flags().StringVar(&stepConfig.LocalAuditFile, "localAuditFile", os.Getenv("PIPER_localAuditFile"), "Path to an audit XML file, e.g. exported from Audit Workbench, whose audit decisions are merged into the results of a `localOnly` scan. Its decisions take precedence over the ones contained in the FPR.")
+	cmd.Flags().StringSliceVar(&stepConfig.LocalFailOnPriorities, "localFailOnPriorities", []string{`Critical`, `High`}, "In `localOnly` mode, the priorities (`Critical`, `High`, `Medium`, `Low`) of unaudited, suspicious or exploitable issues which cause the step to fail.")
+	cmd.Flags().StringSliceVar(&stepConfig.LocalFailOnCategories, "localFailOnCategories", []string{``}, "In `localOnly` mode, the categories, e.g. `SQL Injection`, of unaudited, suspicious or exploitable issues which cause the step to fail regardless of their priority.")
 	cmd.Flags().StringVar(&stepConfig.FprUploadEndpoint, "fprUploadEndpoint", `/upload/resultFileUpload.html`, "Fortify SSC endpoint for FPR uploads")
 	cmd.Flags().StringVar(&stepConfig.ProjectName, "projectName", `{{list .GroupID .ArtifactID | join "-" | trimAll "-"}}`, "The project used for reporting results in SSC")
 	cmd.Flags().BoolVar(&stepConfig.Reporting, "reporting", false, "Influences whether a report is generated or not")
@@ -373,9 +397,7 @@ func addFortifyExecuteScanFlags(cmd *cobra.Command, stepConfig *fortifyExecuteSc
 	cmd.Flags().BoolVar(&stepConfig.InstallArtifacts, "installArtifacts", false, "If enabled, it will install all artifacts to the local maven repository to make them available before running Fortify. This is required if any maven module has dependencies to other modules in the repository and they were not installed before.")
 	cmd.Flags().BoolVar(&stepConfig.CreateResultIssue, "createResultIssue", false, "Activate creation of a result issue in GitHub.")
 
-	cmd.MarkFlagRequired("authToken")
 	cmd.Flags().MarkDeprecated("pythonAdditionalPath", "this is deprecated")
-	cmd.MarkFlagRequired("serverUrl")
 }
 
 // retrieve step metadata
@@ -443,7 +465,7 @@ func fortifyExecuteScanMetadata() config.StepData {
 						},
 						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
 						Type:      "string",
-						Mandatory: true,
+						Mandatory: false,
 						Aliases:   []config.Alias{},
 						Default:   os.Getenv("PIPER_authToken"),
 					},
@@ -802,6 +824,60 @@ func fortifyExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     true,
 					},
+					{
+						Name:        "sarifBaselineLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_sarifBaselineLocation"),
+					},
+					{
+						Name:        "enforceThresholdsOnNewFindings",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "localOnly",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "localAuditFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_localAuditFile"),
+					},
+					{
+						Name:        "localFailOnPriorities",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`Critical`, `High`},
+					},
+					{
+						Name:        "localFailOnCategories",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{``},
+					},
 					{
 						Name:        "fprUploadEndpoint",
 						ResourceRef: []config.ResourceReference{},
@@ -834,7 +910,7 @@ func fortifyExecuteScanMetadata() config.StepData {
 						ResourceRef: []config.ResourceReference{},
 						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
 						Type:        "string",
-						Mandatory:   true,
+						Mandatory:   false,
 						Aliases:     []config.Alias{{Name: "fortifyServerUrl"}, {Name: "sscUrl", Deprecated: true}},
 						Default:     os.Getenv("PIPER_serverUrl"),
 					},
@@ -1048,6 +1124,7 @@ func fortifyExecuteScanMetadata() config.StepData {
 							{"filePattern": "**/toolrun_fortify_*.json", "type": "fortify"},
 							{"filePattern": "**/piper_fortify_report.json", "type": "fortify"},
 							{"filePattern": "**/piper_fortify_report.html", "type": "fortify"},
+							{"filePattern": "**/piper_differential_report.html", "type": "fortify"},
 						},
 					},
 				},
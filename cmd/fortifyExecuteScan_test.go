@@ -4,6 +4,7 @@
 package cmd
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
 	"errors"
@@ -19,6 +20,7 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/mock"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/fortify"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
@@ -540,6 +542,135 @@ func TestExecutions(t *testing.T) {
 	}
 }
 
+const localScanFvdl = `<?xml version="1.0" encoding="UTF-8"?>
+<FVDL xmlns="xmlns://www.fortifysoftware.com/schema/fvdl" version="1.12">
+<Vulnerabilities>
+<Vulnerability>
+  <ClassInfo><ClassID>RULE-SQL</ClassID><Type>SQL Injection</Type></ClassInfo>
+  <InstanceInfo><InstanceID>INSTANCE-1</InstanceID><Confidence>5.0</Confidence></InstanceInfo>
+  <AnalysisInfo><Unified><Trace><Primary><Entry>
+    <Node isDefault="true"><SourceLocation path="main.go" line="10" colStart="0" colEnd="0"/></Node>
+  </Entry></Primary></Trace></Unified></AnalysisInfo>
+</Vulnerability>
+<Vulnerability>
+  <ClassInfo><ClassID>RULE-LOG</ClassID><Type>Log Forging</Type></ClassInfo>
+  <InstanceInfo><InstanceID>INSTANCE-2</InstanceID><Confidence>2.0</Confidence></InstanceInfo>
+  <AnalysisInfo><Unified><Trace><Primary><Entry>
+    <Node isDefault="true"><SourceLocation path="log.go" line="3" colStart="0" colEnd="0"/></Node>
+  </Entry></Primary></Trace></Unified></AnalysisInfo>
+</Vulnerability>
+</Vulnerabilities>
+<EngineData><RuleInfo>
+  <Rule id="RULE-SQL"><MetaInfo><Group name="Accuracy">5</Group><Group name="Impact">5</Group><Group name="Probability">4</Group></MetaInfo></Rule>
+  <Rule id="RULE-LOG"><MetaInfo><Group name="Accuracy">4</Group><Group name="Impact">1</Group><Group name="Probability">2</Group></MetaInfo></Rule>
+</RuleInfo></EngineData>
+</FVDL>`
+
+func TestRunFortifyLocalScan(t *testing.T) {
+	writeFpr := func(t *testing.T) {
+		if err := os.MkdirAll("target", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		fpr, err := os.Create(filepath.Join("target", "result.fpr"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		archive := zip.NewWriter(fpr)
+		writer, _ := archive.Create("audit.fvdl")
+		_, _ = writer.Write([]byte(localScanFvdl))
+		_ = archive.Close()
+		_ = fpr.Close()
+	}
+
+	t.Run("not compliant", func(t *testing.T) {
+		dir := t.TempDir()
+		oldCWD, _ := os.Getwd()
+		_ = os.Chdir(dir)
+		// clean up tmp dir
+		defer func() {
+			_ = os.Chdir(oldCWD)
+		}()
+		writeFpr(t)
+
+		utils := newFortifyTestUtilsBundle()
+		influx := fortifyExecuteScanInflux{}
+		// fortifyupdate is not required and no SSC system is available
+		execInPath = failMockExecinPathfortifyupdate
+		config := fortifyExecuteScanOptions{BuildTool: "maven", BuildDescriptorFile: "pom.xml", ProjectName: "my-project", VersioningModel: "major", LocalOnly: true, LocalFailOnPriorities: []string{"Critical", "High"}}
+
+		reports, err := runFortifyScan(context.Background(), config, nil, &utils, nil, &influx, map[string]string{})
+
+		assert.EqualError(t, err, "fortify scan failed, the project is not compliant. For details check the archived report")
+		assert.Equal(t, 1, influx.fortify_data.fields.violations)
+		assert.Equal(t, "my-project", influx.fortify_data.fields.projectName)
+		assert.Equal(t, "sourceanalyzer", utils.executions[0].executable)
+		assert.FileExists(t, filepath.Join(fortify.ReportsDirectory, "result.sarif"))
+		assert.FileExists(t, filepath.Join(fortify.ReportsDirectory, "piper_fortify_report.html"))
+		assert.Contains(t, reports, piperutils.Path{Target: "target/*.fpr"})
+	})
+
+	t.Run("compliant with local audit", func(t *testing.T) {
+		dir := t.TempDir()
+		oldCWD, _ := os.Getwd()
+		_ = os.Chdir(dir)
+		// clean up tmp dir
+		defer func() {
+			_ = os.Chdir(oldCWD)
+		}()
+		writeFpr(t)
+
+		utils := newFortifyTestUtilsBundle()
+		utils.AddFile("audit.xml", []byte(`<Audit><IssueList><Issue instanceId="INSTANCE-1"><Tag id="87f2364f-dcd4-49e6-861d-f8d3f351686b"><Value>Not an Issue</Value></Tag></Issue></IssueList></Audit>`))
+		influx := fortifyExecuteScanInflux{}
+		execInPath = mockExecinPath
+		config := fortifyExecuteScanOptions{BuildTool: "maven", BuildDescriptorFile: "pom.xml", ProjectName: "my-project", VersioningModel: "major", LocalOnly: true, LocalAuditFile: "audit.xml", LocalFailOnPriorities: []string{"Critical", "High"}}
+
+		_, err := runFortifyScan(context.Background(), config, nil, &utils, nil, &influx, map[string]string{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, influx.fortify_data.fields.violations)
+	})
+
+	t.Run("failing category", func(t *testing.T) {
+		dir := t.TempDir()
+		oldCWD, _ := os.Getwd()
+		_ = os.Chdir(dir)
+		// clean up tmp dir
+		defer func() {
+			_ = os.Chdir(oldCWD)
+		}()
+		writeFpr(t)
+
+		utils := newFortifyTestUtilsBundle()
+		influx := fortifyExecuteScanInflux{}
+		execInPath = mockExecinPath
+		config := fortifyExecuteScanOptions{BuildTool: "maven", BuildDescriptorFile: "pom.xml", ProjectName: "my-project", VersioningModel: "major", LocalOnly: true, LocalFailOnCategories: []string{"Log Forging"}}
+
+		_, err := runFortifyScan(context.Background(), config, nil, &utils, nil, &influx, map[string]string{})
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, influx.fortify_data.fields.violations)
+	})
+
+	t.Run("audit file missing", func(t *testing.T) {
+		dir := t.TempDir()
+		oldCWD, _ := os.Getwd()
+		_ = os.Chdir(dir)
+		// clean up tmp dir
+		defer func() {
+			_ = os.Chdir(oldCWD)
+		}()
+
+		utils := newFortifyTestUtilsBundle()
+		execInPath = mockExecinPath
+		config := fortifyExecuteScanOptions{BuildTool: "maven", BuildDescriptorFile: "pom.xml", ProjectName: "my-project", VersioningModel: "major", LocalOnly: true, LocalAuditFile: "audit.xml"}
+
+		_, err := runFortifyScan(context.Background(), config, nil, &utils, nil, &fortifyExecuteScanInflux{}, map[string]string{})
+
+		assert.EqualError(t, err, "failed to read audit file audit.xml: could not read 'audit.xml'")
+	})
+}
+
 func TestAnalyseSuspiciousExploitable(t *testing.T) {
 	config := fortifyExecuteScanOptions{SpotCheckMinimum: 4, MustAuditIssueGroups: "Audit All, Corporate Security Requirements", SpotAuditIssueGroups: "Spot Checks of Each Category"}
 	ff := fortifyMock{}
@@ -643,6 +774,23 @@ func TestAnalyseUnauditedIssuesWithWrongConfig(t *testing.T) {
 	assert.Equal(t, "Invalid spotCheckMinimumUnit. Please set it as 'percentage' or 'number'.", err.Error())
 }
 
+func TestNewSSCViolations(t *testing.T) {
+	config := fortifyExecuteScanOptions{MustAuditIssueGroups: "Audit All, Corporate Security Requirements", ConsiderSuspicious: true}
+	newFindings := []format.Results{
+		{RuleID: "unaudited must audit", Properties: &format.SarifProperties{FortifyCategory: "Audit All", ToolState: "Unreviewed"}},
+		{RuleID: "unaudited spot check", Properties: &format.SarifProperties{FortifyCategory: "Spot Checks of Each Category", ToolState: "Unreviewed"}},
+		{RuleID: "not an issue", Properties: &format.SarifProperties{FortifyCategory: "Audit All", Audited: true, ToolState: "Not an Issue"}},
+		{RuleID: "exploitable", Properties: &format.SarifProperties{FortifyCategory: "Optional", Audited: true, ToolState: "Exploitable"}},
+		{RuleID: "suspicious", Properties: &format.SarifProperties{FortifyCategory: "Optional", Audited: true, ToolState: "Suspicious"}},
+		{RuleID: "without properties"},
+	}
+
+	assert.Equal(t, 3, newSSCViolations(config, newFindings))
+
+	config.ConsiderSuspicious = false
+	assert.Equal(t, 2, newSSCViolations(config, newFindings))
+}
+
 func TestTriggerFortifyScan(t *testing.T) {
 	t.Run("maven", func(t *testing.T) {
 		dir := t.TempDir()
@@ -16,6 +16,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/command"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/pkg/errors"
 )
@@ -33,7 +34,7 @@ func gctsExecuteABAPQualityChecks(config gctsExecuteABAPQualityChecksOptions, te
 	httpClient := &piperhttp.Client{}
 
 	// error situations should stop execution through log.Entry().Fatal() call which leads to an os.Exit(1) in the end
-	err := rungctsExecuteABAPQualityChecks(&config, httpClient)
+	err := rungctsExecuteABAPQualityChecks(&config, httpClient, &piperutils.Files{})
 	if err != nil {
 		log.Entry().WithError(err).Fatal("step execution failed")
 	}
@@ -46,7 +47,7 @@ func gctsExecuteABAPQualityChecks(config gctsExecuteABAPQualityChecksOptions, te
 
 }
 
-func rungctsExecuteABAPQualityChecks(config *gctsExecuteABAPQualityChecksOptions, httpClient piperhttp.Sender) error {
+func rungctsExecuteABAPQualityChecks(config *gctsExecuteABAPQualityChecksOptions, httpClient piperhttp.Sender, utils piperutils.FileUtils) error {
 
 	const localChangedObjects = "localchangedobjects"
 	const remoteChangedObjects = "remotechangedobjects"
@@ -114,7 +115,7 @@ func rungctsExecuteABAPQualityChecks(config *gctsExecuteABAPQualityChecksOptions
 	if config.AUnitTest {
 
 		// wrapper for execution of AUnit Test
-		err := executeAUnitTest(config, httpClient, objects)
+		err := executeAUnitTest(config, httpClient, objects, utils)
 
 		if err != nil {
 			log.Entry().WithError(err)
@@ -135,7 +136,7 @@ func rungctsExecuteABAPQualityChecks(config *gctsExecuteABAPQualityChecksOptions
 	if config.AtcCheck {
 
 		// wrapper for execution of ATCChecks
-		err = executeATCCheck(config, httpClient, objects)
+		err = executeATCCheck(config, httpClient, objects, utils)
 
 		if err != nil {
 			log.Entry().WithError(err).Fatal("execute ATC Check failed")
@@ -507,7 +508,7 @@ func discoverServer(config *gctsExecuteABAPQualityChecksOptions, client piperhtt
 	return &disc.Header, nil
 }
 
-func executeAUnitTest(config *gctsExecuteABAPQualityChecksOptions, client piperhttp.Sender, objects []repoObject) error {
+func executeAUnitTest(config *gctsExecuteABAPQualityChecksOptions, client piperhttp.Sender, objects []repoObject, utils piperutils.FileUtils) error {
 
 	log.Entry().Info("execute ABAP Unit Test started")
 
@@ -558,7 +559,7 @@ func executeAUnitTest(config *gctsExecuteABAPQualityChecksOptions, client piperh
 		return nil
 	}
 
-	parsedRes, err := parseUnitResult(config, client, &result)
+	parsedRes, err := parseUnitResult(config, client, &result, utils)
 
 	if err != nil {
 		log.Entry().Warning(err)
@@ -611,7 +612,7 @@ func runAUnitTest(config *gctsExecuteABAPQualityChecksOptions, client piperhttp.
 	return response, nil
 }
 
-func parseUnitResult(config *gctsExecuteABAPQualityChecksOptions, client piperhttp.Sender, aUnitRunResult *runResult) (parsedResult checkstyle, err error) {
+func parseUnitResult(config *gctsExecuteABAPQualityChecksOptions, client piperhttp.Sender, aUnitRunResult *runResult, utils piperutils.FileUtils) (parsedResult checkstyle, err error) {
 
 	log.Entry().Info("parse ABAP Unit Result started")
 
@@ -733,7 +734,7 @@ func parseUnitResult(config *gctsExecuteABAPQualityChecksOptions, client piperht
 
 	body, _ := xml.Marshal(parsedResult)
 
-	writeErr := os.WriteFile(config.AUnitResultsFileName, body, 0644)
+	writeErr := utils.FileWrite(config.AUnitResultsFileName, body, 0644)
 
 	if writeErr != nil {
 		log.Entry().Error("file AUnitResults.xml could not be created")
@@ -745,7 +746,7 @@ func parseUnitResult(config *gctsExecuteABAPQualityChecksOptions, client piperht
 
 }
 
-func executeATCCheck(config *gctsExecuteABAPQualityChecksOptions, client piperhttp.Sender, objects []repoObject) (error error) {
+func executeATCCheck(config *gctsExecuteABAPQualityChecksOptions, client piperhttp.Sender, objects []repoObject, utils piperutils.FileUtils) (error error) {
 
 	log.Entry().Info("execute ATC Check started")
 
@@ -815,7 +816,7 @@ func executeATCCheck(config *gctsExecuteABAPQualityChecksOptions, client piperht
 		return nil
 	}
 
-	atcRes, err := parseATCCheckResult(config, client, &result)
+	atcRes, err := parseATCCheckResult(config, client, &result, utils)
 
 	if err != nil {
 		log.Entry().Error(err)
@@ -949,7 +950,7 @@ func getWorklist(config *gctsExecuteABAPQualityChecksOptions, client piperhttp.S
 	return worklistID, nil
 }
 
-func parseATCCheckResult(config *gctsExecuteABAPQualityChecksOptions, client piperhttp.Sender, response *worklist) (atcResults checkstyle, error error) {
+func parseATCCheckResult(config *gctsExecuteABAPQualityChecksOptions, client piperhttp.Sender, response *worklist, utils piperutils.FileUtils) (atcResults checkstyle, error error) {
 
 	log.Entry().Info("parse ATC Check Result started")
 
@@ -1051,12 +1052,17 @@ func parseATCCheckResult(config *gctsExecuteABAPQualityChecksOptions, client pip
 
 	atcBody, _ := xml.Marshal(atcResults)
 
-	writeErr := os.WriteFile(config.AtcResultsFileName, atcBody, 0644)
+	writeErr := utils.FileWrite(config.AtcResultsFileName, atcBody, 0644)
 
 	if writeErr != nil {
 		log.Entry().Error("ATCResults.xml could not be created")
 		return atcResults, fmt.Errorf("handling atc results failed: %w", writeErr)
 	}
+	if sarifPath, err := writeATCSarif(utils, atcBody, config.AtcResultsFileName); err != nil {
+		log.Entry().WithError(err).Warning("failed to write ATC results as SARIF")
+	} else {
+		log.Entry().Info("ATC check results have been written as SARIF to " + sarifPath)
+	}
 	log.Entry().Info("parsing ATC check results to CheckStyle has finished.")
 	return atcResults, writeErr
 }
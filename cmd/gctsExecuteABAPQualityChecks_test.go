@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
@@ -877,7 +878,7 @@ func TestExecuteAUnitTestSuccess(t *testing.T) {
 		var repoObjects []repoObject
 		repoObjects = append(repoObjects, object)
 
-		err := executeAUnitTest(&config, &httpClient, repoObjects)
+		err := executeAUnitTest(&config, &httpClient, repoObjects, &mock.FilesMock{})
 
 		if assert.NoError(t, err) {
 
@@ -913,7 +914,7 @@ func TestExecuteAUnitTestSuccess(t *testing.T) {
 
 		var repoObjects []repoObject
 		repoObjects = append(repoObjects, object)
-		err := executeAUnitTest(&config, &httpClient, repoObjects)
+		err := executeAUnitTest(&config, &httpClient, repoObjects, &mock.FilesMock{})
 
 		if assert.NoError(t, err) {
 
@@ -957,7 +958,7 @@ func TestExecuteAUnitTestFailure(t *testing.T) {
 		header.Add("x-csrf-token", "ZegUEgfa50R7ZfGGxOtx2A==")
 		header.Add("saml2", "disabled")
 
-		err := executeAUnitTest(&config, &httpClient, repoObjects)
+		err := executeAUnitTest(&config, &httpClient, repoObjects, &mock.FilesMock{})
 
 		assert.EqualError(t, err, "execute of Aunit test has failed: run of unit tests failed: discovery of the ABAP server failed: a http error occurred")
 
@@ -998,7 +999,7 @@ func TestExecuteATCCheckSuccess(t *testing.T) {
 		var repoObjects []repoObject
 		repoObjects = append(repoObjects, object)
 
-		err := executeATCCheck(&config, &httpClient, repoObjects)
+		err := executeATCCheck(&config, &httpClient, repoObjects, &mock.FilesMock{})
 
 		if assert.NoError(t, err) {
 
@@ -1039,7 +1040,7 @@ func TestExecuteATCCheckSuccess(t *testing.T) {
 
 		var repoObjects []repoObject
 		repoObjects = append(repoObjects, object)
-		err := executeATCCheck(&config, &httpClient, repoObjects)
+		err := executeATCCheck(&config, &httpClient, repoObjects, &mock.FilesMock{})
 
 		if assert.NoError(t, err) {
 
@@ -1090,7 +1091,7 @@ func TestExecuteATCCheckFailure(t *testing.T) {
 		header.Add("x-csrf-token", "ZegUEgfa50R7ZfGGxOtx2A==")
 		header.Add("saml2", "disabled")
 
-		err := executeATCCheck(&config, &httpClient, repoObjects)
+		err := executeATCCheck(&config, &httpClient, repoObjects, &mock.FilesMock{})
 
 		assert.EqualError(t, err, "execution of ATC Checks failed: get worklist failed: discovery of the ABAP server failed: a http error occurred")
 
@@ -1133,7 +1134,7 @@ func TestParseAUnitResultSuccess(t *testing.T) {
 		var resp *runResult
 		xml.Unmarshal(xmlBody, &resp)
 
-		parsedRes, err := parseUnitResult(&config, &httpClient, resp)
+		parsedRes, err := parseUnitResult(&config, &httpClient, resp, &mock.FilesMock{})
 
 		if assert.NoError(t, err) {
 
@@ -1189,7 +1190,7 @@ func TestParseAUnitResultSuccess(t *testing.T) {
 		var resp *runResult
 		xml.Unmarshal(xmlBody, &resp)
 
-		parsedRes, err := parseUnitResult(&config, &httpClient, resp)
+		parsedRes, err := parseUnitResult(&config, &httpClient, resp, &mock.FilesMock{})
 
 		if assert.NoError(t, err) {
 
@@ -1278,7 +1279,7 @@ func TestParseAUnitResultFailure(t *testing.T) {
 		var resp *runResult
 		xml.Unmarshal(xmlBody, &resp)
 
-		parsedRes, err := parseUnitResult(&config, &httpClient, resp)
+		parsedRes, err := parseUnitResult(&config, &httpClient, resp, &mock.FilesMock{})
 
 		if assert.Error(t, err) {
 
@@ -1334,7 +1335,8 @@ func TestParseATCCheckResultSuccess(t *testing.T) {
 
 		xml.Unmarshal(xmlBody, &resp)
 
-		parsedRes, err := parseATCCheckResult(&config, &httpClient, resp)
+		files := &mock.FilesMock{}
+		parsedRes, err := parseATCCheckResult(&config, &httpClient, resp, files)
 
 		if assert.NoError(t, err) {
 
@@ -1342,6 +1344,10 @@ func TestParseATCCheckResultSuccess(t *testing.T) {
 				assert.Equal(t, "/var/jenkins_home/workspace/myFirstPipeline//objects/CLAS/ZCL_GCTS/CPRI ZCL_GCTS.abap", parsedRes.File[0].Name)
 			})
 
+			t.Run("check SARIF file", func(t *testing.T) {
+				assert.True(t, files.HasWrittenFile("ATCResults.sarif"))
+			})
+
 			t.Run("check line number", func(t *testing.T) {
 				assert.Equal(t, "20", parsedRes.File[0].Error[0].Line)
 			})
@@ -1390,7 +1396,7 @@ func TestParseATCCheckResultSuccess(t *testing.T) {
 		</atcworklist:worklist>`)
 		var resp *worklist
 		xml.Unmarshal(xmlBody, &resp)
-		parsedRes, err := parseATCCheckResult(&config, &httpClient, resp)
+		parsedRes, err := parseATCCheckResult(&config, &httpClient, resp, &mock.FilesMock{})
 
 		if assert.NoError(t, err) {
 
@@ -1440,7 +1446,7 @@ func TestParseATCCheckResultFailure(t *testing.T) {
 
 		var resp *worklist
 		xml.Unmarshal(xmlBody, &resp)
-		parsedRes, err := parseATCCheckResult(&config, &httpClient, resp)
+		parsedRes, err := parseATCCheckResult(&config, &httpClient, resp, &mock.FilesMock{})
 
 		assert.EqualError(t, err, "conversion of ATC check results to CheckStyle has failed: get file name has failed: could not check readable source format: could not get repository layout: a http error occurred")
 		assert.NotEmpty(t, parsedRes)
@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+type gitHubChecksService interface {
+	CreateCheckRun(ctx context.Context, owner, repo string, opts github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error)
+	UpdateCheckRun(ctx context.Context, owner, repo string, checkRunID int64, opts github.UpdateCheckRunOptions) (*github.CheckRun, *github.Response, error)
+}
+
+// sarifLevels contains the SARIF result levels ordered by severity
+var sarifLevels = []string{"none", "note", "warning", "error"}
+
+func githubPublishCheckRun(config githubPublishCheckRunOptions, _ *telemetry.CustomData) {
+	ctx, client, err := piperGithub.NewClientBuilder(config.Token, config.APIURL).Build()
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Failed to get GitHub client")
+	}
+
+	err = runGithubPublishCheckRun(ctx, &config, &piperutils.Files{}, client.Checks)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Publishing GitHub check run failed")
+	}
+}
+
+func runGithubPublishCheckRun(ctx context.Context, config *githubPublishCheckRunOptions, utils piperutils.FileUtils, checksService gitHubChecksService) error {
+	sarif, err := format.ReadSarif(config.SarifFile, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	workingDir, err := utils.Getwd()
+	if err != nil {
+		return errors.Wrap(err, "failed to determine working directory")
+	}
+
+	checkName := config.CheckName
+	counts := map[string]int{}
+	annotations := []*github.CheckRunAnnotation{}
+	unlocated := 0
+	audited := 0
+	for _, run := range sarif.Runs {
+		if len(checkName) == 0 {
+			checkName = run.Tool.Driver.Name
+		}
+		ruleLevels := map[string]string{}
+		for _, rule := range run.Tool.Driver.Rules {
+			if rule.DefaultConfiguration != nil {
+				ruleLevels[rule.ID] = rule.DefaultConfiguration.Level
+			}
+		}
+		relevant := format.UnauditedResults(run.Results)
+		audited += len(run.Results) - len(relevant)
+		for _, result := range relevant {
+			level := resultLevel(result, ruleLevels)
+			counts[level]++
+			annotation := sarifAnnotation(result, level, workingDir)
+			if annotation == nil {
+				unlocated++
+				continue
+			}
+			annotations = append(annotations, annotation)
+		}
+	}
+	if len(checkName) == 0 {
+		checkName = "SARIF results"
+	}
+
+	conclusion := checkConclusion(counts, config.FailureLevel, config.NeutralLevel)
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+
+	options := piperGithub.CheckRunOptions{
+		Owner:       config.Owner,
+		Repository:  config.Repository,
+		Name:        checkName,
+		HeadSHA:     config.CommitID,
+		DetailsURL:  config.DetailsURL,
+		Title:       fmt.Sprintf("%v findings", total),
+		Summary:     checkRunSummary(counts, audited, unlocated, conclusion),
+		Conclusion:  conclusion,
+		Annotations: annotations,
+	}
+	log.Entry().Infof("Publishing check run '%v' with %v annotations and conclusion '%v' for commit %v", checkName, len(annotations), conclusion, config.CommitID)
+	checkRun, err := piperGithub.PublishCheckRun(ctx, checksService, &options)
+	if err != nil {
+		if strings.Contains(fmt.Sprint(err), "No commit found for SHA") {
+			log.SetErrorCategory(log.ErrorCustom)
+		}
+		return err
+	}
+	log.Entry().Infof("Check run published: %v", checkRun.GetHTMLURL())
+	return nil
+}
+
+// resultLevel returns the level of a result, falling back to the default level of its rule.
+// As per SARIF specification the level defaults to "warning".
+func resultLevel(result format.Results, ruleLevels map[string]string) string {
+	level := result.Level
+	if len(level) == 0 {
+		level = ruleLevels[result.RuleID]
+	}
+	if levelIndex(level) < 0 {
+		level = "warning"
+	}
+	return level
+}
+
+func levelIndex(level string) int {
+	for i, l := range sarifLevels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+func checkConclusion(counts map[string]int, failureLevel, neutralLevel string) string {
+	exceeds := func(threshold string) bool {
+		if threshold == "none" || levelIndex(threshold) < 0 {
+			return false
+		}
+		for level, count := range counts {
+			if count > 0 && levelIndex(level) >= levelIndex(threshold) {
+				return true
+			}
+		}
+		return false
+	}
+	if exceeds(failureLevel) {
+		return "failure"
+	}
+	if exceeds(neutralLevel) {
+		return "neutral"
+	}
+	return "success"
+}
+
+func sarifAnnotation(result format.Results, level, workingDir string) *github.CheckRunAnnotation {
+	if len(result.Locations) == 0 || len(result.Locations[0].PhysicalLocation.ArtifactLocation.URI) == 0 {
+		return nil
+	}
+	location := result.Locations[0].PhysicalLocation
+	path := annotationPath(location.ArtifactLocation.URI, workingDir)
+
+	startLine := location.Region.StartLine
+	if startLine == 0 {
+		startLine = 1
+	}
+	endLine := location.Region.EndLine
+	if endLine < startLine {
+		endLine = startLine
+	}
+
+	annotationLevel := "notice"
+	switch level {
+	case "error":
+		annotationLevel = "failure"
+	case "warning":
+		annotationLevel = "warning"
+	}
+
+	message := result.RuleID
+	if result.Message != nil && len(result.Message.Text) > 0 {
+		message = result.Message.Text
+	}
+
+	annotation := github.CheckRunAnnotation{
+		Path:            &path,
+		StartLine:       &startLine,
+		EndLine:         &endLine,
+		AnnotationLevel: &annotationLevel,
+		Message:         &message,
+		Title:           &result.RuleID,
+	}
+	// columns are only supported by GitHub for annotations on a single line
+	if startLine == endLine && location.Region.StartColumn > 0 {
+		startColumn := location.Region.StartColumn
+		endColumn := location.Region.EndColumn
+		if endColumn < startColumn {
+			endColumn = startColumn
+		}
+		annotation.StartColumn = &startColumn
+		annotation.EndColumn = &endColumn
+	}
+	return &annotation
+}
+
+// annotationPath converts the artifact location of a result into a path relative to the repository root
+func annotationPath(uri, workingDir string) string {
+	path := strings.TrimPrefix(uri, "file://")
+	if filepath.IsAbs(path) && len(workingDir) > 0 {
+		if relativePath, err := filepath.Rel(workingDir, path); err == nil && !strings.HasPrefix(relativePath, "..") {
+			path = relativePath
+		}
+	}
+	return strings.TrimPrefix(filepath.ToSlash(path), "./")
+}
+
+func checkRunSummary(counts map[string]int, audited, unlocated int, conclusion string) string {
+	summary := fmt.Sprintf("Conclusion: **%v**\n\n| Level | Findings |\n| --- | --- |\n", conclusion)
+	for i := len(sarifLevels) - 1; i >= 0; i-- {
+		summary += fmt.Sprintf("| %v | %v |\n", sarifLevels[i], counts[sarifLevels[i]])
+	}
+	if audited > 0 {
+		summary += fmt.Sprintf("\n%v findings have been audited as not relevant and are not considered.\n", audited)
+	}
+	if unlocated > 0 {
+		summary += fmt.Sprintf("\n%v findings have no source location and are therefore not shown as annotation.\n", unlocated)
+	}
+	return summary
+}
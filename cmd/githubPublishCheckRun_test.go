@@ -0,0 +1,155 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+type ghChecksServiceMock struct {
+	createOptions []github.CreateCheckRunOptions
+	updateOptions []github.UpdateCheckRunOptions
+	owner         string
+	repo          string
+	serviceError  error
+}
+
+func (g *ghChecksServiceMock) CreateCheckRun(ctx context.Context, owner, repo string, opts github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error) {
+	g.owner = owner
+	g.repo = repo
+	g.createOptions = append(g.createOptions, opts)
+	id := int64(1)
+	return &github.CheckRun{ID: &id}, nil, g.serviceError
+}
+
+func (g *ghChecksServiceMock) UpdateCheckRun(ctx context.Context, owner, repo string, checkRunID int64, opts github.UpdateCheckRunOptions) (*github.CheckRun, *github.Response, error) {
+	g.updateOptions = append(g.updateOptions, opts)
+	return &github.CheckRun{ID: &checkRunID}, nil, g.serviceError
+}
+
+const checkRunSarif = `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "hadolint", "rules": [{"id": "DL3008", "defaultConfiguration": {"level": "note"}}]}},
+    "results": [
+      {"ruleId": "DL3007", "level": "error", "message": {"text": "Using latest is prone to errors"}, "locations": [{"physicalLocation": {"artifactLocation": {"uri": "Dockerfile"}, "region": {"startLine": 1, "startColumn": 1, "endColumn": 20}}}]},
+      {"ruleId": "DL3008", "message": {"text": "Pin versions in apt get install"}, "locations": [{"physicalLocation": {"artifactLocation": {"uri": "file:///work/docker/Dockerfile"}, "region": {"startLine": 5, "endLine": 7}}}]},
+      {"ruleId": "DL3009", "level": "warning", "message": {"text": "Delete the apt-get lists"}},
+      {"ruleId": "DL3010", "level": "error", "properties": {"audited": true}, "locations": [{"physicalLocation": {"artifactLocation": {"uri": "Dockerfile"}, "region": {"startLine": 9}}}]}
+    ]
+  }]
+}`
+
+func TestRunGithubPublishCheckRun(t *testing.T) {
+	t.Parallel()
+
+	config := githubPublishCheckRunOptions{
+		Owner:        "TEST",
+		Repository:   "test",
+		CommitID:     "abcd1234",
+		SarifFile:    "hadolint.sarif",
+		FailureLevel: "error",
+		NeutralLevel: "warning",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		utils := &mock.FilesMock{CurrentDir: "/work"}
+		utils.AddFile("hadolint.sarif", []byte(checkRunSarif))
+		checksService := ghChecksServiceMock{}
+
+		err := runGithubPublishCheckRun(context.Background(), &config, utils, &checksService)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "TEST", checksService.owner)
+		assert.Equal(t, "test", checksService.repo)
+		if assert.Len(t, checksService.createOptions, 1) {
+			opts := checksService.createOptions[0]
+			assert.Equal(t, "hadolint", opts.Name)
+			assert.Equal(t, "abcd1234", opts.HeadSHA)
+			assert.Equal(t, "failure", *opts.Conclusion)
+			assert.Equal(t, "3 findings", *opts.Output.Title)
+			assert.Contains(t, *opts.Output.Summary, "| error | 1 |")
+			assert.Contains(t, *opts.Output.Summary, "| note | 1 |")
+			assert.Contains(t, *opts.Output.Summary, "1 findings have been audited as not relevant")
+			assert.Contains(t, *opts.Output.Summary, "1 findings have no source location")
+			if assert.Len(t, opts.Output.Annotations, 2) {
+				first := opts.Output.Annotations[0]
+				assert.Equal(t, "Dockerfile", *first.Path)
+				assert.Equal(t, "failure", *first.AnnotationLevel)
+				assert.Equal(t, 1, *first.StartColumn)
+				assert.Equal(t, 20, *first.EndColumn)
+				second := opts.Output.Annotations[1]
+				assert.Equal(t, "docker/Dockerfile", *second.Path)
+				assert.Equal(t, "notice", *second.AnnotationLevel)
+				assert.Equal(t, 5, *second.StartLine)
+				assert.Equal(t, 7, *second.EndLine)
+				assert.Nil(t, second.StartColumn)
+			}
+		}
+	})
+
+	t.Run("custom name and thresholds", func(t *testing.T) {
+		t.Parallel()
+		utils := &mock.FilesMock{}
+		utils.AddFile("hadolint.sarif", []byte(checkRunSarif))
+		checksService := ghChecksServiceMock{}
+		customConfig := config
+		customConfig.CheckName = "Dockerfile lint"
+		customConfig.FailureLevel = "none"
+
+		err := runGithubPublishCheckRun(context.Background(), &customConfig, utils, &checksService)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Dockerfile lint", checksService.createOptions[0].Name)
+		assert.Equal(t, "neutral", *checksService.createOptions[0].Conclusion)
+	})
+
+	t.Run("missing SARIF file", func(t *testing.T) {
+		t.Parallel()
+		utils := &mock.FilesMock{}
+
+		err := runGithubPublishCheckRun(context.Background(), &config, utils, &ghChecksServiceMock{})
+
+		assert.Contains(t, fmt.Sprint(err), "failed to read SARIF file 'hadolint.sarif'")
+	})
+
+	t.Run("error creating check run", func(t *testing.T) {
+		t.Parallel()
+		utils := &mock.FilesMock{}
+		utils.AddFile("hadolint.sarif", []byte(checkRunSarif))
+		checksService := ghChecksServiceMock{serviceError: fmt.Errorf("create error")}
+
+		err := runGithubPublishCheckRun(context.Background(), &config, utils, &checksService)
+
+		assert.EqualError(t, err, "failed to create check run 'hadolint' for commit 'abcd1234': create error")
+	})
+}
+
+func TestCheckConclusion(t *testing.T) {
+	t.Parallel()
+	counts := map[string]int{"warning": 2, "note": 1}
+
+	assert.Equal(t, "failure", checkConclusion(counts, "warning", "note"))
+	assert.Equal(t, "neutral", checkConclusion(counts, "error", "warning"))
+	assert.Equal(t, "success", checkConclusion(counts, "error", "none"))
+	assert.Equal(t, "success", checkConclusion(map[string]int{}, "note", "note"))
+}
+
+func TestResultLevel(t *testing.T) {
+	t.Parallel()
+	ruleLevels := map[string]string{"rule1": "error"}
+
+	assert.Equal(t, "note", resultLevel(format.Results{RuleID: "rule1", Level: "note"}, ruleLevels))
+	assert.Equal(t, "error", resultLevel(format.Results{RuleID: "rule1"}, ruleLevels))
+	assert.Equal(t, "warning", resultLevel(format.Results{RuleID: "rule2"}, ruleLevels))
+}
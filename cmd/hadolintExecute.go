@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
@@ -104,21 +105,37 @@ func runHadolint(config hadolintExecuteOptions, utils hadolintUtils) error {
 	//TODO: related to https://github.com/hadolint/hadolint/issues/391
 	// hadolint exists with 1 if there are processing issues but also if there are findings
 	// thus check stdout first if a report was created
+	reports := []piperutils.Path{{Target: config.ReportFile}}
 	if output := outputBuffer.String(); len(output) > 0 {
 		log.Entry().WithField("report", output).Debug("Report created")
 		if err := utils.FileWrite(config.ReportFile, []byte(output), 0666); err != nil {
 			log.Entry().WithError(err).Warningf("failed to write report %v", config.ReportFile)
 		}
+		if sarifPath, err := writeHadolintSarif([]byte(output), config.ReportFile, utils); err != nil {
+			log.Entry().WithError(err).Warning("failed to write SARIF report")
+		} else {
+			reports = append(reports, piperutils.Path{Target: sarifPath})
+		}
 	} else if err != nil {
 		// if stdout is empty a processing issue occured
 		return errors.Wrap(err, errorBuffer.String())
 	}
 	//TODO: mock away in tests
 	// persist report information
-	piperutils.PersistReportsAndLinks("hadolintExecute", "./", utils, []piperutils.Path{{Target: config.ReportFile}}, []piperutils.Path{})
+	piperutils.PersistReportsAndLinks("hadolintExecute", "./", utils, reports, []piperutils.Path{})
 	return nil
 }
 
+// writeHadolintSarif converts the checkstyle report into a SARIF report next to it
+func writeHadolintSarif(report []byte, reportFile string, utils hadolintUtils) (string, error) {
+	sarif, err := format.CheckstyleToSarif(report, "hadolint")
+	if err != nil {
+		return "", err
+	}
+	sarifPath := format.SarifReportPath(reportFile)
+	return sarifPath, format.WriteSarifFile(sarif, sarifPath, utils)
+}
+
 // loadConfigurationFile loads a file from the provided url
 func loadConfigurationFile(url, file string, utils hadolintUtils) error {
 	log.Entry().WithField("url", url).Debug("Loading configuration file from URL")
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v45/github"
+
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	"github.com/SAP/jenkins-library/pkg/licensing"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/sbom"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+type licenseComplianceCheckUtils interface {
+	piperutils.FileUtils
+
+	GetIssueService() *github.IssuesService
+	GetSearchService() *github.SearchService
+}
+
+type licenseComplianceCheckUtilsBundle struct {
+	*piperutils.Files
+	issues *github.IssuesService
+	search *github.SearchService
+}
+
+func (l *licenseComplianceCheckUtilsBundle) GetIssueService() *github.IssuesService {
+	return l.issues
+}
+
+func (l *licenseComplianceCheckUtilsBundle) GetSearchService() *github.SearchService {
+	return l.search
+}
+
+func newLicenseComplianceCheckUtils(client *github.Client) licenseComplianceCheckUtils {
+	utils := licenseComplianceCheckUtilsBundle{
+		Files: &piperutils.Files{},
+	}
+	if client != nil {
+		utils.issues = client.Issues
+		utils.search = client.Search
+	}
+	return &utils
+}
+
+func licenseComplianceCheck(config licenseComplianceCheckOptions, _ *telemetry.CustomData) {
+	ctx, client, err := piperGithub.NewClientBuilder(config.GithubToken, config.GithubAPIURL).Build()
+	if err != nil {
+		log.Entry().WithError(err).Warning("Failed to get GitHub client")
+	}
+	utils := newLicenseComplianceCheckUtils(client)
+
+	err = runLicenseComplianceCheck(ctx, &config, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runLicenseComplianceCheck(ctx context.Context, config *licenseComplianceCheckOptions, utils licenseComplianceCheckUtils) error {
+	policy, err := licensing.LoadPolicy(config.PolicyFile, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	bomFiles, err := findLicenseComplianceSBOMs(config, utils)
+	if err != nil {
+		return err
+	}
+
+	results := []licensing.Result{}
+	evaluated := map[string]bool{}
+	for _, bomFile := range bomFiles {
+		bom, err := sbom.ReadBOM(bomFile, utils)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return err
+		}
+		for _, component := range sbom.ContainedComponents(bom) {
+			key := sbom.ComponentKey(component)
+			if evaluated[key] {
+				continue
+			}
+			evaluated[key] = true
+			results = append(results, policy.Evaluate(component))
+		}
+	}
+
+	counts := licensing.CountDecisions(results)
+	log.Entry().Infof("Licenses of %v components evaluated: %v allowed, %v to be reviewed, %v denied", len(results), counts[licensing.Allowed], counts[licensing.Review], counts[licensing.Denied])
+	violations := licensing.Violations(results, config.FailOnReview)
+	for _, violation := range violations {
+		log.Entry().Warnf("%v: %v", violation.Title(), violation.Reason)
+	}
+
+	reportPaths, err := writeLicenseComplianceReports(config, bomFiles, results, violations, utils)
+	piperutils.PersistReportsAndLinks("licenseComplianceCheck", "", utils, reportPaths, nil)
+	if err != nil {
+		return err
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	if config.CreateResultIssue && len(config.GithubToken) > 0 && len(config.GithubAPIURL) > 0 && len(config.Owner) > 0 && len(config.Repository) > 0 {
+		log.Entry().Debugf("Creating result issues for %v policy violation(s)", len(violations))
+		issueDetails := make([]reporting.IssueDetail, len(violations))
+		piperutils.CopyAtoB(violations, issueDetails)
+		gh := reporting.GitHub{
+			Owner:         &config.Owner,
+			Repository:    &config.Repository,
+			Assignees:     &config.Assignees,
+			IssueService:  utils.GetIssueService(),
+			SearchService: utils.GetSearchService(),
+		}
+		if err := gh.UploadMultipleReports(ctx, &issueDetails); err != nil {
+			return fmt.Errorf("failed to upload reports to GitHub for %v policy violations: %w", len(violations), err)
+		}
+	}
+
+	if (counts[licensing.Denied] > 0 && config.FailOnDenied) || (counts[licensing.Review] > 0 && config.FailOnReview) {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("%v license policy violation(s) found", len(violations))
+	}
+	log.Entry().Infof("%v license policy violation(s) found", len(violations))
+	log.Entry().Info("Step will only create data but not fail due to setting failOnDenied: false")
+	return nil
+}
+
+func findLicenseComplianceSBOMs(config *licenseComplianceCheckOptions, utils licenseComplianceCheckUtils) ([]string, error) {
+	bomFiles := []string{}
+	for _, pattern := range config.BomFilePattern {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, fmt.Errorf("failed to find SBOMs for pattern '%v': %w", pattern, err)
+		}
+		for _, match := range matches {
+			if !piperutils.ContainsString(bomFiles, match) {
+				bomFiles = append(bomFiles, match)
+			}
+		}
+	}
+	if len(bomFiles) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("no SBOM found for patterns %v", strings.Join(config.BomFilePattern, ", "))
+	}
+	return bomFiles, nil
+}
+
+func writeLicenseComplianceReports(config *licenseComplianceCheckOptions, bomFiles []string, results []licensing.Result, violations []licensing.Violation, utils licenseComplianceCheckUtils) ([]piperutils.Path, error) {
+	if len(config.AttributionFile) > 0 {
+		if err := licensing.WriteAttribution(config.AttributionFile, config.ApplicationName, results, utils); err != nil {
+			return []piperutils.Path{}, err
+		}
+		log.Entry().Infof("Attribution file written to %v", config.AttributionFile)
+	}
+
+	scanReport := licensing.CreateScanReport(config.PolicyFile, bomFiles, results, config.FailOnReview)
+	reportPaths, err := licensing.WriteCustomReports(scanReport, violations, utils)
+	if len(config.AttributionFile) > 0 {
+		reportPaths = append(reportPaths, piperutils.Path{Name: "Third-Party Software Notices", Target: config.AttributionFile})
+	}
+	return reportPaths, err
+}
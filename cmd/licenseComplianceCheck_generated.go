@@ -0,0 +1,393 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/cobra"
+)
+
+type licenseComplianceCheckOptions struct {
+	BomFilePattern    []string `json:"bomFilePattern,omitempty"`
+	PolicyFile        string   `json:"policyFile,omitempty"`
+	FailOnDenied      bool     `json:"failOnDenied,omitempty"`
+	FailOnReview      bool     `json:"failOnReview,omitempty"`
+	AttributionFile   string   `json:"attributionFile,omitempty"`
+	ApplicationName   string   `json:"applicationName,omitempty"`
+	CreateResultIssue bool     `json:"createResultIssue,omitempty"`
+	GithubToken       string   `json:"githubToken,omitempty"`
+	GithubAPIURL      string   `json:"githubApiUrl,omitempty"`
+	Owner             string   `json:"owner,omitempty"`
+	Repository        string   `json:"repository,omitempty"`
+	Assignees         []string `json:"assignees,omitempty"`
+}
+
+type licenseComplianceCheckReports struct {
+}
+
+func (p *licenseComplianceCheckReports) persist(stepConfig licenseComplianceCheckOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "**/piper_license_compliance_report.html", ParamRef: "", StepResultType: "license-compliance"},
+		{FilePattern: "**/piper_license_policy_violations.md", ParamRef: "", StepResultType: "license-compliance"},
+		{FilePattern: "**/licenseCompliance/NOTICE.md", ParamRef: "", StepResultType: "license-compliance"},
+		{FilePattern: "**/licenseComplianceCheck_licenses.json", ParamRef: "", StepResultType: "license-compliance"},
+	}
+	envVars := []gcs.EnvVar{
+		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
+	}
+	gcsClient, err := gcs.NewClient(gcs.WithEnvVars(envVars))
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// LicenseComplianceCheckCommand Checks the licenses of the components contained in the CycloneDX SBOMs against a license policy.
+func LicenseComplianceCheckCommand() *cobra.Command {
+	const STEP_NAME = "licenseComplianceCheck"
+
+	metadata := licenseComplianceCheckMetadata()
+	var stepConfig licenseComplianceCheckOptions
+	var startTime time.Time
+	var reports licenseComplianceCheckReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createLicenseComplianceCheckCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Checks the licenses of the components contained in the CycloneDX SBOMs against a license policy.",
+		Long: `Evaluates the licenses of all components contained in the CycloneDX SBOMs created during the build (e.g. by ` + "`" + `mavenBuild` + "`" + `, ` + "`" + `npmExecuteScripts` + "`" + `, ` + "`" + `golangBuild` + "`" + `, ` + "`" + `pythonBuild` + "`" + ` or ` + "`" + `sbomProcess` + "`" + `) against a license policy.
+This allows license gating for projects which are not onboarded to a commercial software composition analysis tool.
+
+License information is interpreted as [SPDX license expression](https://spdx.github.io/spdx-spec/v2.3/SPDX-license-expressions/) supporting the operators ` + "`" + `AND` + "`" + `, ` + "`" + `OR` + "`" + ` and ` + "`" + `WITH` + "`" + `.
+For a disjunction (` + "`" + `OR` + "`" + `) the most permissive license is chosen, for a conjunction (` + "`" + `AND` + "`" + `) all licenses need to be permitted.
+
+The policy file lists allowed, denied and licenses which need to be reviewed and allows exceptions per component:
+
+` + "`" + `` + "`" + `` + "`" + `yaml
+allow:
+  - MIT
+  - Apache-2.0
+  - BSD-*
+review:
+  - LGPL-*
+deny:
+  - GPL-*
+  - AGPL-*
+# decision for licenses not covered by the policy and for components without license information: allow, review or deny
+unknown: review
+exceptions:
+  - component: "pkg:maven/org.openjdk/**"
+    licenses:
+      - GPL-2.0-only WITH Classpath-exception-2.0
+    reason: approved by legal department
+` + "`" + `` + "`" + `` + "`" + `
+
+The step creates an HTML and a JSON report, a markdown report per policy violation and a markdown attribution (NOTICE) file listing all components grouped by license.
+Optionally GitHub issues are created for the policy violations.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.GithubToken)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.Send()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+			}, func() {
+				licenseComplianceCheck(stepConfig, &stepTelemetryData)
+			})
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addLicenseComplianceCheckFlags(createLicenseComplianceCheckCmd, &stepConfig)
+	return createLicenseComplianceCheckCmd
+}
+
+func addLicenseComplianceCheckFlags(cmd *cobra.Command, stepConfig *licenseComplianceCheckOptions) {
+	cmd.Flags().StringSliceVar(&stepConfig.BomFilePattern, "bomFilePattern", []string{`**/bom-*.xml`, `**/bom-*.json`}, "List of file patterns of the CycloneDX SBOMs to evaluate. XML and JSON format are supported.")
+	cmd.Flags().StringVar(&stepConfig.PolicyFile, "policyFile", `license-policy.yaml`, "Path to the license policy file.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnDenied, "failOnDenied", true, "Whether to fail the step if components with denied licenses are detected.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnReview, "failOnReview", false, "Whether to fail the step if components with licenses which need to be reviewed are detected.")
+	cmd.Flags().StringVar(&stepConfig.AttributionFile, "attributionFile", `licenseCompliance/NOTICE.md`, "Path of the markdown attribution (NOTICE) file. No file is created if the parameter is empty.")
+	cmd.Flags().StringVar(&stepConfig.ApplicationName, "applicationName", os.Getenv("PIPER_applicationName"), "Name of the application used in the attribution file.")
+	cmd.Flags().BoolVar(&stepConfig.CreateResultIssue, "createResultIssue", false, "Activate creation of a result issue per policy violation in GitHub.")
+	cmd.Flags().StringVar(&stepConfig.GithubToken, "githubToken", os.Getenv("PIPER_githubToken"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line")
+	cmd.Flags().StringVar(&stepConfig.GithubAPIURL, "githubApiUrl", `https://api.github.com`, "Set the GitHub API URL.")
+	cmd.Flags().StringVar(&stepConfig.Owner, "owner", os.Getenv("PIPER_owner"), "Set the GitHub organization.")
+	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "Set the GitHub repository.")
+	cmd.Flags().StringSliceVar(&stepConfig.Assignees, "assignees", []string{``}, "Defines the assignees for the Github Issue created/updated with the results of the scan as a list of login names.")
+
+}
+
+// retrieve step metadata
+func licenseComplianceCheckMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "licenseComplianceCheck",
+			Aliases:     []config.Alias{},
+			Description: "Checks the licenses of the components contained in the CycloneDX SBOMs against a license policy.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "githubTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing token to authenticate to GitHub.", Type: "jenkins"},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name:        "bomFilePattern",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`**/bom-*.xml`, `**/bom-*.json`},
+					},
+					{
+						Name:        "policyFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `license-policy.yaml`,
+					},
+					{
+						Name:        "failOnDenied",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "failOnReview",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "attributionFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `licenseCompliance/NOTICE.md`,
+					},
+					{
+						Name:        "applicationName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_applicationName"),
+					},
+					{
+						Name: "createResultIssue",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/isOptimizedAndScheduled",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "bool",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   false,
+					},
+					{
+						Name: "githubToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubTokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "githubVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "github",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "access_token"}},
+						Default:   os.Getenv("PIPER_githubToken"),
+					},
+					{
+						Name:        "githubApiUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `https://api.github.com`,
+					},
+					{
+						Name: "owner",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/owner",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "githubOrg"}},
+						Default:   os.Getenv("PIPER_owner"),
+					},
+					{
+						Name: "repository",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/repository",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "githubRepo"}},
+						Default:   os.Getenv("PIPER_repository"),
+					},
+					{
+						Name:        "assignees",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{``},
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "**/piper_license_compliance_report.html", "type": "license-compliance"},
+							{"filePattern": "**/piper_license_policy_violations.md", "type": "license-compliance"},
+							{"filePattern": "**/licenseCompliance/NOTICE.md", "type": "license-compliance"},
+							{"filePattern": "**/licenseComplianceCheck_licenses.json", "type": "license-compliance"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,164 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SAP/jenkins-library/pkg/licensing"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+type licenseComplianceCheckMockUtils struct {
+	*mock.FilesMock
+}
+
+func (l *licenseComplianceCheckMockUtils) GetIssueService() *github.IssuesService {
+	return nil
+}
+
+func (l *licenseComplianceCheckMockUtils) GetSearchService() *github.SearchService {
+	return nil
+}
+
+const licenseComplianceCheckTestBOM = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "metadata": {"component": {"type": "application", "name": "shop", "version": "1.0.0", "licenses": [{"license": {"id": "GPL-3.0-only"}}]}},
+  "components": [
+    {"type": "library", "name": "lodash", "version": "4.17.21", "purl": "pkg:npm/lodash@4.17.21", "licenses": [{"license": {"id": "MIT"}}]},
+    {"type": "library", "name": "sharp", "version": "0.32.0", "purl": "pkg:npm/sharp@0.32.0", "licenses": [{"expression": "Apache-2.0 AND LGPL-3.0-or-later"}]}
+  ]
+}`
+
+const licenseComplianceCheckTestGPLBOM = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "components": [
+    {"type": "library", "name": "lodash", "version": "4.17.21", "purl": "pkg:npm/lodash@4.17.21", "licenses": [{"license": {"id": "MIT"}}]},
+    {"type": "library", "name": "readline", "version": "1.0.0", "purl": "pkg:npm/readline@1.0.0", "licenses": [{"license": {"id": "GPL-3.0-only"}}]}
+  ]
+}`
+
+const licenseComplianceCheckTestPolicy = `allow: [MIT, Apache-2.0]
+review: [LGPL-*]
+deny: [GPL-*]
+`
+
+func newLicenseComplianceCheckTestsUtils() *licenseComplianceCheckMockUtils {
+	utils := licenseComplianceCheckMockUtils{
+		FilesMock: &mock.FilesMock{},
+	}
+	utils.AddFile("license-policy.yaml", []byte(licenseComplianceCheckTestPolicy))
+	utils.AddFile("bom-npm.json", []byte(licenseComplianceCheckTestBOM))
+	return &utils
+}
+
+func licenseComplianceCheckTestConfig() licenseComplianceCheckOptions {
+	return licenseComplianceCheckOptions{
+		BomFilePattern:  []string{"**/bom-*.json"},
+		PolicyFile:      "license-policy.yaml",
+		FailOnDenied:    true,
+		AttributionFile: "licenseCompliance/NOTICE.md",
+		ApplicationName: "shop",
+	}
+}
+
+func TestRunLicenseComplianceCheck(t *testing.T) {
+	t.Parallel()
+
+	t.Run("compliant", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := licenseComplianceCheckTestConfig()
+		utils := newLicenseComplianceCheckTestsUtils()
+		// test
+		err := runLicenseComplianceCheck(context.Background(), &config, utils)
+		// assert
+		assert.NoError(t, err)
+		assert.True(t, utils.HasWrittenFile(filepath.Join(licensing.ReportsDirectory, "piper_license_compliance_report.html")))
+		assert.True(t, utils.HasWrittenFile(filepath.Join(reporting.StepReportDirectory, "licenseComplianceCheck_licenses.json")))
+		assert.False(t, utils.HasWrittenFile(filepath.Join(licensing.ReportsDirectory, "piper_license_policy_violations.md")))
+		notice, _ := utils.FileRead("licenseCompliance/NOTICE.md")
+		assert.Contains(t, string(notice), "shop includes the following third-party software components.")
+		assert.Contains(t, string(notice), "## Apache-2.0 AND LGPL-3.0-or-later\n\n* sharp 0.32.0")
+		assert.NotContains(t, string(notice), "GPL-3.0-only", "the application itself is not evaluated")
+	})
+
+	t.Run("review fails", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := licenseComplianceCheckTestConfig()
+		config.FailOnReview = true
+		utils := newLicenseComplianceCheckTestsUtils()
+		// test
+		err := runLicenseComplianceCheck(context.Background(), &config, utils)
+		// assert
+		assert.EqualError(t, err, "1 license policy violation(s) found")
+		violations, _ := utils.FileRead(filepath.Join(licensing.ReportsDirectory, "piper_license_policy_violations.md"))
+		assert.Contains(t, string(violations), "# Policy Violation - pkg:npm/sharp@0.32.0")
+	})
+
+	t.Run("denied license", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := licenseComplianceCheckTestConfig()
+		utils := newLicenseComplianceCheckTestsUtils()
+		utils.AddFile("backend/bom-npm.json", []byte(licenseComplianceCheckTestGPLBOM))
+		// test
+		err := runLicenseComplianceCheck(context.Background(), &config, utils)
+		// assert
+		assert.EqualError(t, err, "1 license policy violation(s) found")
+		violations, _ := utils.FileRead(filepath.Join(licensing.ReportsDirectory, "piper_license_policy_violations.md"))
+		assert.Contains(t, string(violations), "# Policy Violation - pkg:npm/readline@1.0.0")
+		assert.NotContains(t, string(violations), "sharp")
+	})
+
+	t.Run("denied license without failing", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := licenseComplianceCheckTestConfig()
+		config.FailOnDenied = false
+		config.AttributionFile = ""
+		utils := newLicenseComplianceCheckTestsUtils()
+		utils.AddFile("backend/bom-npm.json", []byte(licenseComplianceCheckTestGPLBOM))
+		// test
+		err := runLicenseComplianceCheck(context.Background(), &config, utils)
+		// assert
+		assert.NoError(t, err)
+		assert.False(t, utils.HasWrittenFile("licenseCompliance/NOTICE.md"))
+	})
+
+	t.Run("missing policy", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := licenseComplianceCheckTestConfig()
+		config.PolicyFile = "unknown.yaml"
+		utils := newLicenseComplianceCheckTestsUtils()
+		// test
+		err := runLicenseComplianceCheck(context.Background(), &config, utils)
+		// assert
+		assert.Contains(t, err.Error(), "failed to read license policy 'unknown.yaml'")
+	})
+
+	t.Run("no SBOM found", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := licenseComplianceCheckTestConfig()
+		config.BomFilePattern = []string{"**/bom-*.xml"}
+		utils := newLicenseComplianceCheckTestsUtils()
+		// test
+		err := runLicenseComplianceCheck(context.Background(), &config, utils)
+		// assert
+		assert.EqualError(t, err, "no SBOM found for patterns **/bom-*.xml")
+	})
+}
@@ -49,6 +49,18 @@ func newMalwareScanUtilsBundle(config malwareExecuteScanOptions) *malwareScanUti
 		log.Entry().Warnf("Unable to parse timeout for malwareScan: '%v'. Falling back to %ds", err, timeout)
 	}
 
+	if config.Backend == "clamav" {
+		client, err := malwarescan.NewClamdClient(config.ClamdAddress, timeout)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			log.Entry().WithError(err).Fatal("invalid configuration of the ClamAV daemon")
+		}
+		return &malwareScanUtilsBundle{
+			Client: client,
+			Files:  &piperutils.Files{},
+		}
+	}
+
 	httpClientOptions := piperhttp.ClientOptions{
 		Username:           config.Username,
 		Password:           config.Password,
@@ -83,7 +95,7 @@ func runMalwareScan(config *malwareExecuteScanOptions, telemetryData *telemetry.
 		return err
 	}
 
-	log.Entry().Infof("Scanning file \"%s\" for malware using service \"%s\"", file, config.Host)
+	log.Entry().Infof("Scanning file \"%s\" for malware using service \"%s\"", file, malwareScanEndpoint(config))
 
 	candidate, err := utils.OpenFile(file, os.O_RDONLY, 0666)
 	if err != nil {
@@ -92,6 +104,9 @@ func runMalwareScan(config *malwareExecuteScanOptions, telemetryData *telemetry.
 	defer candidate.Close()
 
 	scannerInfo, err := utils.Info()
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve information about the malware scanner")
+	}
 
 	log.Entry().Infof("***************************************")
 	log.Entry().Infof("* Engine:     %s", scannerInfo.EngineVersion)
@@ -102,6 +117,17 @@ func runMalwareScan(config *malwareExecuteScanOptions, telemetryData *telemetry.
 		return err
 	}
 
+	if config.ScanArchiveContents {
+		isArchive, err := isMalwareScanArchive(file, utils)
+		if err != nil {
+			return err
+		}
+		if isArchive {
+			return scanMalwareArchiveContents(config, file, candidate, utils)
+		}
+		log.Entry().Infof("File '%s' is no archive, scanning it as a whole", file)
+	}
+
 	scanResponse, err := utils.Scan(candidate)
 
 	if err != nil {
@@ -188,7 +214,7 @@ func validateHash(remoteHash, fileName string, utils malwareScanUtils) error {
 
 // create toolrecord file for malwarescan
 func createToolRecordMalwareScan(utils malwareScanUtils, workspace string, config *malwareExecuteScanOptions, scanner *malwarescan.Info) (string, error) {
-	record := toolrecord.New(utils, workspace, "malwarescan", config.Host)
+	record := toolrecord.New(utils, workspace, "malwarescan", malwareScanEndpoint(config))
 	record.SetOverallDisplayData("Malware Scanner", "")
 
 	if err := record.AddKeyData("engineVersion", scanner.EngineVersion, "Engine Version", ""); err != nil {
@@ -206,7 +232,7 @@ func createToolRecordMalwareScan(utils malwareScanUtils, workspace string, confi
 	return record.GetFileName(), nil
 }
 
-func createMalwareScanReport(config *malwareExecuteScanOptions, scanResult *malwarescan.ScanResult, utils malwareScanUtils) error {
+func createMalwareScanReport(config *malwareExecuteScanOptions, scanResult interface{}, utils malwareScanUtils) error {
 	scanResultJSON, err := json.Marshal(scanResult)
 
 	if err != nil {
@@ -215,3 +241,65 @@ func createMalwareScanReport(config *malwareExecuteScanOptions, scanResult *malw
 
 	return utils.FileWrite(config.ReportFileName, scanResultJSON, 0666)
 }
+
+// malwareScanArchiveReport extends the result of the scan by the results of the files contained in the scanned archive
+type malwareScanArchiveReport struct {
+	malwarescan.ScanResult
+	Entries []malwarescan.ArchiveEntryResult `json:"entries"`
+}
+
+func malwareScanEndpoint(config *malwareExecuteScanOptions) string {
+	if config.Backend == "clamav" {
+		return config.ClamdAddress
+	}
+	return config.Host
+}
+
+func isMalwareScanArchive(file string, utils malwareScanUtils) (bool, error) {
+	candidate, err := utils.OpenFile(file, os.O_RDONLY, 0666)
+	if err != nil {
+		return false, err
+	}
+	defer candidate.Close()
+	return malwarescan.IsArchive(candidate), nil
+}
+
+// scanMalwareArchiveContents scans every file contained in the archive and summarizes the results
+func scanMalwareArchiveContents(config *malwareExecuteScanOptions, file string, candidate io.Reader, utils malwareScanUtils) error {
+	entries, err := malwarescan.ScanArchive(utils, file, candidate)
+	if err != nil {
+		return err
+	}
+
+	hash, err := utils.SHA256(file)
+	if err != nil {
+		return err
+	}
+	report := malwareScanArchiveReport{ScanResult: malwarescan.ScanResult{SHA256: hash}, Entries: entries}
+	findings := []string{}
+	for _, entry := range entries {
+		report.ScanSize += entry.ScanSize
+		report.MalwareDetected = report.MalwareDetected || entry.MalwareDetected
+		report.EncryptedContentDetected = report.EncryptedContentDetected || entry.EncryptedContentDetected
+		if entry.MalwareDetected || entry.EncryptedContentDetected {
+			log.Entry().Warnf("File '%s' in '%s' has been flagged. Malware detected: %t, encrypted content detected: %t, finding: %v",
+				entry.Path, file, entry.MalwareDetected, entry.EncryptedContentDetected, entry.Finding)
+			findings = append(findings, fmt.Sprintf("%v (%v)", entry.Finding, entry.Path))
+		}
+	}
+	report.Finding = strings.Join(findings, ", ")
+
+	if err = createMalwareScanReport(config, report, utils); err != nil {
+		return err
+	}
+
+	if report.MalwareDetected || report.EncryptedContentDetected {
+		return fmt.Errorf("Malware scan failed for file '%s'. Malware detected: %t, encrypted content detected: %t, finding: %v",
+			file, report.MalwareDetected, report.EncryptedContentDetected, report.Finding)
+	}
+
+	log.Entry().Infof("Malware scan succeeded for %d files in archive '%s'. Malware detected: %t, encrypted content detected: %t",
+		len(entries), file, report.MalwareDetected, report.EncryptedContentDetected)
+
+	return nil
+}
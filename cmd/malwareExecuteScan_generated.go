@@ -24,12 +24,15 @@ type malwareExecuteScanOptions struct {
 	DockerConfigJSON          string `json:"dockerConfigJSON,omitempty"`
 	ContainerRegistryPassword string `json:"containerRegistryPassword,omitempty"`
 	ContainerRegistryUser     string `json:"containerRegistryUser,omitempty"`
-	Host                      string `json:"host,omitempty"`
-	Username                  string `json:"username,omitempty"`
-	Password                  string `json:"password,omitempty"`
+	Backend                   string `json:"backend,omitempty" validate:"possible-values=malwareScanningService clamav"`
+	Host                      string `json:"host,omitempty" validate:"required_if=Backend malwareScanningService"`
+	ClamdAddress              string `json:"clamdAddress,omitempty"`
+	Username                  string `json:"username,omitempty" validate:"required_if=Backend malwareScanningService"`
+	Password                  string `json:"password,omitempty" validate:"required_if=Backend malwareScanningService"`
 	ScanImage                 string `json:"scanImage,omitempty"`
 	ScanImageRegistryURL      string `json:"scanImageRegistryUrl,omitempty"`
 	ScanFile                  string `json:"scanFile,omitempty"`
+	ScanArchiveContents       bool   `json:"scanArchiveContents,omitempty"`
 	Timeout                   string `json:"timeout,omitempty"`
 	ReportFileName            string `json:"reportFileName,omitempty"`
 }
@@ -86,7 +89,13 @@ func MalwareExecuteScanCommand() *cobra.Command {
 	var createMalwareExecuteScanCmd = &cobra.Command{
 		Use:   STEP_NAME,
 		Short: "Performs a malware scan using the [SAP Malware Scanning Service](https://help.sap.com/viewer/b416237f818c4e2e827f6118640079f8/LATEST/en-US/b7c9b86fe724458086a502df3160f380.html).",
-		Long:  `Performs a malware scan using the [SAP Malware Scanning Service](https://help.sap.com/viewer/b416237f818c4e2e827f6118640079f8/LATEST/en-US/b7c9b86fe724458086a502df3160f380.html).`,
+		Long: `Performs a malware scan using the [SAP Malware Scanning Service](https://help.sap.com/viewer/b416237f818c4e2e827f6118640079f8/LATEST/en-US/b7c9b86fe724458086a502df3160f380.html).
+
+Alternatively, the scan can be performed by a [ClamAV](https://www.clamav.net/) daemon (` + "`" + `clamd` + "`" + `) reachable via TCP or a Unix socket, e.g. running as sidecar, by setting ` + "`" + `backend: clamav` + "`" + `.
+The content is streamed to ` + "`" + `clamd` + "`" + ` using the ` + "`" + `INSTREAM` + "`" + ` command, hence the ` + "`" + `StreamMaxLength` + "`" + ` of ` + "`" + `clamd` + "`" + ` needs to be large enough for the files to be scanned.
+
+With ` + "`" + `scanArchiveContents: true` + "`" + ` every file contained in a tar (e.g. an image tarball including its layers) or zip archive is scanned separately
+and the report lists the result per file.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -141,6 +150,7 @@ func MalwareExecuteScanCommand() *cobra.Command {
 			handler := func() {
 				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
 				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
 				stepTelemetryData.PiperCommitHash = GitCommit
@@ -166,7 +176,11 @@ func MalwareExecuteScanCommand() *cobra.Command {
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
-			malwareExecuteScan(stepConfig, &stepTelemetryData)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+			}, func() {
+				malwareExecuteScan(stepConfig, &stepTelemetryData)
+			})
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
@@ -181,19 +195,19 @@ func addMalwareExecuteScanFlags(cmd *cobra.Command, stepConfig *malwareExecuteSc
 	cmd.Flags().StringVar(&stepConfig.DockerConfigJSON, "dockerConfigJSON", os.Getenv("PIPER_dockerConfigJSON"), "Path to the file `.docker/config.json` - this is typically provided by your CI/CD system. You can find more details about the Docker credentials in the [Docker documentation](https://docs.docker.com/engine/reference/commandline/login/).")
 	cmd.Flags().StringVar(&stepConfig.ContainerRegistryPassword, "containerRegistryPassword", os.Getenv("PIPER_containerRegistryPassword"), "For `buildTool: docker`: Password for container registry access - typically provided by the CI/CD environment.")
 	cmd.Flags().StringVar(&stepConfig.ContainerRegistryUser, "containerRegistryUser", os.Getenv("PIPER_containerRegistryUser"), "For `buildTool: docker`: Username for container registry access - typically provided by the CI/CD environment.")
+	cmd.Flags().StringVar(&stepConfig.Backend, "backend", `malwareScanningService`, "The malware scanning backend to be used: the SAP Malware Scanning Service or a ClamAV daemon.")
 	cmd.Flags().StringVar(&stepConfig.Host, "host", os.Getenv("PIPER_host"), "malware scanning host.")
+	cmd.Flags().StringVar(&stepConfig.ClamdAddress, "clamdAddress", `tcp://localhost:3310`, "For `backend: clamav`: Address of the ClamAV daemon, either `tcp://<host>:<port>` or `unix://<socket path>`.")
 	cmd.Flags().StringVar(&stepConfig.Username, "username", os.Getenv("PIPER_username"), "User")
 	cmd.Flags().StringVar(&stepConfig.Password, "password", os.Getenv("PIPER_password"), "Password")
 	cmd.Flags().StringVar(&stepConfig.ScanImage, "scanImage", os.Getenv("PIPER_scanImage"), "For `buildTool: docker`: Defines the docker image which should be scanned.")
 	cmd.Flags().StringVar(&stepConfig.ScanImageRegistryURL, "scanImageRegistryUrl", os.Getenv("PIPER_scanImageRegistryUrl"), "For `buildTool: docker`: Defines the registry where the scanImage is located.")
 	cmd.Flags().StringVar(&stepConfig.ScanFile, "scanFile", os.Getenv("PIPER_scanFile"), "The file which is scanned for malware")
-	cmd.Flags().StringVar(&stepConfig.Timeout, "timeout", `600`, "timeout for http layer in seconds")
+	cmd.Flags().BoolVar(&stepConfig.ScanArchiveContents, "scanArchiveContents", false, "Whether every file contained in a tar, gzip compressed tar or zip archive is scanned separately, e.g. the files of all layers of an image tarball.")
+	cmd.Flags().StringVar(&stepConfig.Timeout, "timeout", `600`, "timeout for http layer respectively the connection to the ClamAV daemon in seconds")
 	cmd.Flags().StringVar(&stepConfig.ReportFileName, "reportFileName", `malwarescan_report.json`, "The file name of the report to be created")
 
 	cmd.MarkFlagRequired("buildTool")
-	cmd.MarkFlagRequired("host")
-	cmd.MarkFlagRequired("username")
-	cmd.MarkFlagRequired("password")
 }
 
 // retrieve step metadata
@@ -287,15 +301,33 @@ func malwareExecuteScanMetadata() config.StepData {
 						Aliases:   []config.Alias{},
 						Default:   os.Getenv("PIPER_containerRegistryUser"),
 					},
+					{
+						Name:        "backend",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `malwareScanningService`,
+					},
 					{
 						Name:        "host",
 						ResourceRef: []config.ResourceReference{},
 						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
 						Type:        "string",
-						Mandatory:   true,
+						Mandatory:   false,
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_host"),
 					},
+					{
+						Name:        "clamdAddress",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `tcp://localhost:3310`,
+					},
 					{
 						Name: "username",
 						ResourceRef: []config.ResourceReference{
@@ -313,7 +345,7 @@ func malwareExecuteScanMetadata() config.StepData {
 						},
 						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
 						Type:      "string",
-						Mandatory: true,
+						Mandatory: false,
 						Aliases:   []config.Alias{},
 						Default:   os.Getenv("PIPER_username"),
 					},
@@ -334,7 +366,7 @@ func malwareExecuteScanMetadata() config.StepData {
 						},
 						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
 						Type:      "string",
-						Mandatory: true,
+						Mandatory: false,
 						Aliases:   []config.Alias{},
 						Default:   os.Getenv("PIPER_password"),
 					},
@@ -375,6 +407,15 @@ func malwareExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{{Name: "file", Deprecated: true}},
 						Default:     os.Getenv("PIPER_scanFile"),
 					},
+					{
+						Name:        "scanArchiveContents",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
 					{
 						Name:        "timeout",
 						ResourceRef: []config.ResourceReference{},
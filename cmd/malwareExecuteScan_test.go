@@ -4,6 +4,8 @@
 package cmd
 
 import (
+	"archive/tar"
+	"bytes"
 	"io"
 	"os"
 	"testing"
@@ -227,6 +229,70 @@ func TestMalwareScanWithOtherBuildtoolTests(t *testing.T) {
 	})
 }
 
+func TestMalwareScanArchiveContents(t *testing.T) {
+	content := &bytes.Buffer{}
+	writer := tar.NewWriter(content)
+	for _, name := range []string{"bin/tool", "etc/config"} {
+		assert.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: 5}))
+		_, err := writer.Write([]byte("HELLO"))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+
+	files := &mock.FilesMock{}
+	files.AddFile("target/image.tar", content.Bytes())
+	files.AddFile("target/myFile", []byte(`HELLO`))
+
+	utils := malwareScanUtilsMockBundle{
+		FilesMock:    files,
+		returnSHA256: "96ca802fbd54d31903f1115a1d95590c685160637d9262bd340ab30d0f817e85",
+	}
+
+	config := malwareExecuteScanOptions{
+		Host:                "https://example.org/malwarescanner",
+		ScanFile:            "target/image.tar",
+		ScanArchiveContents: true,
+		Timeout:             "60",
+		ReportFileName:      "malwarescan_report.json",
+	}
+
+	t.Run("No malware detected in archive", func(t *testing.T) {
+		utils.returnScanResult = &malwarescan.ScanResult{ScanSize: 5, MimeType: "text/plain"}
+
+		err := runMalwareScan(&config, nil, &utils)
+
+		if assert.NoError(t, err) {
+			report, err := utils.FileRead("malwarescan_report.json")
+			assert.NoError(t, err)
+			assert.Contains(t, string(report), `"scanSize":10`)
+			assert.Contains(t, string(report), `"path":"bin/tool"`)
+			assert.Contains(t, string(report), `"path":"etc/config"`)
+		}
+	})
+
+	t.Run("Malware detected in archive", func(t *testing.T) {
+		utils.returnScanResult = &malwarescan.ScanResult{ScanSize: 5, MalwareDetected: true, Finding: "Win.Test.EICAR_HDB-1"}
+
+		err := runMalwareScan(&config, nil, &utils)
+
+		assert.EqualError(t, err, "Malware scan failed for file 'target/image.tar'. Malware detected: true, encrypted content detected: false, finding: Win.Test.EICAR_HDB-1 (bin/tool), Win.Test.EICAR_HDB-1 (etc/config)")
+	})
+
+	t.Run("No archive is scanned as a whole", func(t *testing.T) {
+		utils.returnScanResult = &malwarescan.ScanResult{ScanSize: 5, SHA256: utils.returnSHA256}
+		config := config
+		config.ScanFile = "target/myFile"
+
+		err := runMalwareScan(&config, nil, &utils)
+
+		if assert.NoError(t, err) {
+			report, err := utils.FileRead("malwarescan_report.json")
+			assert.NoError(t, err)
+			assert.NotContains(t, string(report), `"entries"`)
+		}
+	})
+}
+
 type dockerClientMock struct {
 	imageName   string
 	registryURL string
@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"os"
+	"strconv"
+
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/maven"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
-	"strconv"
 )
 
 func mavenExecuteStaticCodeChecks(config mavenExecuteStaticCodeChecksOptions, telemetryData *telemetry.CustomData) {
@@ -63,9 +66,37 @@ func runMavenStaticCodeChecks(config *mavenExecuteStaticCodeChecksOptions, telem
 		LogSuccessfulMavenTransfers: config.LogSuccessfulMavenTransfers,
 	}
 	_, err := maven.Execute(&finalMavenOptions, utils)
+	// the check goals fail the build in case of findings, thus the reports are converted regardless of the result
+	if config.SpotBugs {
+		writeStaticCodeChecksSarif("**/target/spotbugsXml.xml", format.SpotBugsToSarif, utils)
+	}
+	if config.Pmd {
+		writeStaticCodeChecksSarif("**/target/pmd.xml", format.PmdToSarif, utils)
+	}
 	return err
 }
 
+// writeStaticCodeChecksSarif converts the reports of all modules into SARIF reports next to them
+func writeStaticCodeChecksSarif(reportPattern string, converter format.SarifConverter, utils maven.Utils) {
+	reports, err := utils.Glob(reportPattern)
+	if err != nil {
+		log.Entry().WithError(err).Warnf("failed to search for reports '%v'", reportPattern)
+		return
+	}
+	workingDir, err := os.Getwd()
+	if err != nil {
+		log.Entry().WithError(err).Warning("failed to determine working directory")
+	}
+	for _, report := range reports {
+		sarifPath, err := format.ConvertReportToSarif(report, workingDir, converter, utils)
+		if err != nil {
+			log.Entry().WithError(err).Warnf("failed to write SARIF report for %v", report)
+			continue
+		}
+		log.Entry().Infof("Static code check results written as SARIF to %v", sarifPath)
+	}
+}
+
 func getSpotBugsMavenParameters(config *mavenExecuteStaticCodeChecksOptions) *maven.ExecuteOptions {
 	var defines []string
 	if config.SpotBugsIncludeFilterFile != "" {
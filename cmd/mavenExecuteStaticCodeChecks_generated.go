@@ -52,7 +52,8 @@ PMD is a source code analyzer. It finds common programming flaws like unused var
 For more information please visit https://pmd.github.io/.
 The plugins should be configured in the respective pom.xml.
 For SpotBugs include- and exclude filters as well as maximum allowed violations are conifgurable via .pipeline/config.yml.
-For PMD the failure priority and the max allowed violations are configurable via .pipeline/config.yml.`,
+For PMD the failure priority and the max allowed violations are configurable via .pipeline/config.yml.
+The SpotBugs and PMD reports of the modules, ` + "`" + `target/spotbugsXml.xml` + "`" + ` and ` + "`" + `target/pmd.xml` + "`" + `, are also written as SARIF next to them, e.g. for the upload to GitHub code scanning.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -101,6 +102,7 @@ For PMD the failure priority and the max allowed violations are configurable via
 			stepTelemetryData.ErrorCode = "1"
 			handler := func() {
 				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
 				stepTelemetryData.PiperCommitHash = GitCommit
@@ -126,7 +128,11 @@ For PMD the failure priority and the max allowed violations are configurable via
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
-			mavenExecuteStaticCodeChecks(stepConfig, &stepTelemetryData)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+			}, func() {
+				mavenExecuteStaticCodeChecks(stepConfig, &stepTelemetryData)
+			})
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
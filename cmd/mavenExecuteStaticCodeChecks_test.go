@@ -57,6 +57,21 @@ func TestRunMavenStaticCodeChecks(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, expected, utils.Calls[0])
 	})
+	t.Run("should write SARIF reports also if the checks fail", func(t *testing.T) {
+		utils := newMavenStaticCodeChecksTestUtilsBundle()
+		utils.ShouldFailOnCommand = map[string]error{"^mvn": errors.New("PMD Failure")}
+		utils.AddFile("app/target/pmd.xml", []byte(`<pmd version="6.29.0"><file name="app/src/main/java/App.java"><violation beginline="3" endline="3" begincolumn="1" endcolumn="20" rule="UnusedImports" ruleset="Best Practices" priority="4">Unused import</violation></file></pmd>`))
+
+		err := runMavenStaticCodeChecks(&mavenExecuteStaticCodeChecksOptions{Pmd: true}, nil, utils)
+
+		assert.EqualError(t, err, "failed to run executable, command: '[mvn -Dorg.slf4j.simpleLogger.log.org.apache.maven.cli.transfer.Slf4jMavenTransferListener=warn --batch-mode org.apache.maven.plugins:maven-pmd-plugin:3.14.0:check]', error: PMD Failure")
+		content, err := utils.FileRead("app/target/pmd.sarif")
+		if assert.NoError(t, err) {
+			assert.Contains(t, string(content), `"ruleId": "UnusedImports"`)
+			assert.Contains(t, string(content), `"uri": "app/src/main/java/App.java"`)
+		}
+	})
+
 	t.Run("should warn and skip execution if all tools are turned off", func(t *testing.T) {
 		utils := newMavenStaticCodeChecksTestUtilsBundle()
 		config := mavenExecuteStaticCodeChecksOptions{
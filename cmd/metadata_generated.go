@@ -40,6 +40,7 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"azureBlobUpload":                           azureBlobUploadMetadata(),
 		"batsExecuteTests":                          batsExecuteTestsMetadata(),
 		"checkmarxExecuteScan":                      checkmarxExecuteScanMetadata(),
+		"checkmarxMigrateTriage":                    checkmarxMigrateTriageMetadata(),
 		"checkmarxOneExecuteScan":                   checkmarxOneExecuteScanMetadata(),
 		"cloudFoundryCreateService":                 cloudFoundryCreateServiceMetadata(),
 		"cloudFoundryCreateServiceKey":              cloudFoundryCreateServiceKeyMetadata(),
@@ -50,8 +51,12 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"cnbBuild":                                  cnbBuildMetadata(),
 		"codeqlExecuteScan":                         codeqlExecuteScanMetadata(),
 		"containerExecuteStructureTests":            containerExecuteStructureTestsMetadata(),
+		"containerExecuteVulnerabilityScan":         containerExecuteVulnerabilityScanMetadata(),
 		"containerSaveImage":                        containerSaveImageMetadata(),
+		"containerSignImage":                        containerSignImageMetadata(),
+		"containerVerifyImage":                      containerVerifyImageMetadata(),
 		"credentialdiggerScan":                      credentialdiggerScanMetadata(),
+		"dependencyRemediate":                       dependencyRemediateMetadata(),
 		"detectExecuteScan":                         detectExecuteScanMetadata(),
 		"fortifyExecuteScan":                        fortifyExecuteScanMetadata(),
 		"gaugeExecuteTests":                         gaugeExecuteTestsMetadata(),
@@ -65,6 +70,7 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"githubCommentIssue":                        githubCommentIssueMetadata(),
 		"githubCreateIssue":                         githubCreateIssueMetadata(),
 		"githubCreatePullRequest":                   githubCreatePullRequestMetadata(),
+		"githubPublishCheckRun":                     githubPublishCheckRunMetadata(),
 		"githubPublishRelease":                      githubPublishReleaseMetadata(),
 		"githubSetCommitStatus":                     githubSetCommitStatusMetadata(),
 		"gitopsUpdateDeployment":                    gitopsUpdateDeploymentMetadata(),
@@ -88,6 +94,7 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"kanikoExecute":                             kanikoExecuteMetadata(),
 		"karmaExecuteTests":                         karmaExecuteTestsMetadata(),
 		"kubernetesDeploy":                          kubernetesDeployMetadata(),
+		"licenseComplianceCheck":                    licenseComplianceCheckMetadata(),
 		"malwareExecuteScan":                        malwareExecuteScanMetadata(),
 		"mavenBuild":                                mavenBuildMetadata(),
 		"mavenExecute":                              mavenExecuteMetadata(),
@@ -100,7 +107,11 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"npmExecuteScripts":                         npmExecuteScriptsMetadata(),
 		"pipelineCreateScanSummary":                 pipelineCreateScanSummaryMetadata(),
 		"protecodeExecuteScan":                      protecodeExecuteScanMetadata(),
+		"provenanceCreate":                          provenanceCreateMetadata(),
 		"pythonBuild":                               pythonBuildMetadata(),
+		"sbomExecuteVulnerabilityScan":              sbomExecuteVulnerabilityScanMetadata(),
+		"sbomProcess":                               sbomProcessMetadata(),
+		"secretExecuteScan":                         secretExecuteScanMetadata(),
 		"shellExecute":                              shellExecuteMetadata(),
 		"sonarExecuteScan":                          sonarExecuteScanMetadata(),
 		"terraformExecute":                          terraformExecuteMetadata(),
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/npm"
@@ -19,6 +20,9 @@ import (
 
 type lintUtils interface {
 	Glob(pattern string) (matches []string, err error)
+	FileExists(filename string) (bool, error)
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
 
 	getExecRunner() command.ExecRunner
 	getGeneralPurposeConfig(configURL string)
@@ -156,6 +160,7 @@ func runDefaultLint(npmExecutor npm.Executor, utils lintUtils, failOnError bool,
 			}, fmt.Sprintf("./%s_%%s", strconv.Itoa(i)), outputFileName)
 
 			err = execRunner.RunExecutable("npx", args...)
+			writeLintSarif(utils, outputFormat, fmt.Sprintf("./%s_%s", strconv.Itoa(i), outputFileName))
 			if err != nil {
 				if failOnError {
 					return fmt.Errorf("Lint execution failed. This might be the result of severe linting findings, problems with the provided ESLint configuration (%s), or another issue. Please examine the linting results in the UI or in %s, if available, or the log above. ", config, strconv.Itoa(i)+"_defaultlint.xml")
@@ -182,10 +187,38 @@ func runDefaultLint(npmExecutor npm.Executor, utils lintUtils, failOnError bool,
 		}, "./%s", outputFileName)
 
 		_ = execRunner.RunExecutable("npx", args...)
+		writeLintSarif(utils, outputFormat, "./"+outputFileName)
 	}
 	return nil
 }
 
+// writeLintSarif converts the ESLint output file into a SARIF report next to it, if the output format can be converted
+func writeLintSarif(utils lintUtils, outputFormat, outputFile string) {
+	var converter format.SarifConverter
+	switch outputFormat {
+	case "checkstyle":
+		converter = func(content []byte) (*format.SARIF, error) { return format.CheckstyleToSarif(content, "ESLint") }
+	case "json":
+		converter = format.EslintToSarif
+	default:
+		return
+	}
+	// no output file is written if the results are printed to the console
+	if exists, _ := utils.FileExists(outputFile); !exists {
+		return
+	}
+	workingDir, err := os.Getwd()
+	if err != nil {
+		log.Entry().WithError(err).Warning("failed to determine working directory")
+	}
+	sarifPath, err := format.ConvertReportToSarif(outputFile, workingDir, converter, utils)
+	if err != nil {
+		log.Entry().WithError(err).Warnf("failed to write SARIF report for %v", outputFile)
+		return
+	}
+	log.Entry().Infof("Lint results written as SARIF to %v", sarifPath)
+}
+
 func findEslintConfigs(utils lintUtils) []string {
 	unfilteredListOfEslintConfigs, err := utils.Glob("**/.eslintrc*")
 	if err != nil {
@@ -78,6 +78,28 @@ func TestNpmExecuteLint(t *testing.T) {
 		}
 	})
 
+	t.Run("Call default with ESLint config from user - SARIF report", func(t *testing.T) {
+		lintUtils := newLintMockUtilsBundle()
+		lintUtils.AddFile("package.json", []byte("{\"name\": \"Test\" }"))
+		lintUtils.AddFile(".eslintrc.json", []byte("{\"name\": \"Test\" }"))
+		// simulates the output file written by ESLint
+		lintUtils.AddFile("0_defaultlint.xml", []byte(`<checkstyle version="4.3"><file name="index.js"><error line="1" column="7" severity="error" message="'a' is unused." source="eslint.rules.no-unused-vars" /></file></checkstyle>`))
+
+		npmUtils := newNpmMockUtilsBundle()
+		npmUtils.execRunner = lintUtils.execRunner
+		npmExecutor := npm.Execute{Utils: &npmUtils, Options: npm.ExecutorOptions{}}
+
+		err := runNpmExecuteLint(&npmExecutor, &lintUtils, &defaultConfig)
+
+		if assert.NoError(t, err) {
+			content, err := lintUtils.FileRead("0_defaultlint.sarif")
+			if assert.NoError(t, err) {
+				assert.Contains(t, string(content), `"ruleId": "eslint.rules.no-unused-vars"`)
+				assert.Contains(t, string(content), `"uri": "index.js"`)
+			}
+		}
+	})
+
 	t.Run("Call default with ESLint config from user - no redirect to file, stylish format", func(t *testing.T) {
 		lintUtils := newLintMockUtilsBundle()
 		lintUtils.AddFile("package.json", []byte("{\"name\": \"Test\" }"))
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type pipelineGraphCommandOptions struct {
+	openFile        func(s string, t map[string]string) (io.ReadCloser, error)
+	stageConfigFile string
+	format          string
+	outputFile      string
+	failOnIssues    bool
+}
+
+var pipelineGraphOptions pipelineGraphCommandOptions
+
+// PipelineGraphCommand is the entry command for exporting the step dependency graph of a pipeline
+func PipelineGraphCommand() *cobra.Command {
+	pipelineGraphOptions.openFile = config.OpenPiperFile
+	var pipelineGraphCmd = &cobra.Command{
+		Use:   "pipelineGraph",
+		Short: "Exports the dependencies between the steps of a pipeline and checks their order.",
+		Long: `Builds a dependency graph based on the commonPipelineEnvironment inputs and outputs declared in the step metadata.
+If a stage configuration (CRD-style, as used with --useV1 of checkIfStepActive) is provided, the order of the steps is checked:
+steps consuming values which are not produced by any other step or which are only produced by later steps are reported.
+Values provided by the orchestrator integration, e.g. the GitHub repository or the repository credentials, are not reported as missing.`,
+		PreRun: func(cmd *cobra.Command, _ []string) {
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+			initRemoteFileOptions()
+			log.SetVerbose(GeneralConfig.Verbose)
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+		},
+		Run: func(cmd *cobra.Command, _ []string) {
+			utils := &piperutils.Files{}
+			if GeneralConfig.MetaDataResolver == nil {
+				GeneralConfig.MetaDataResolver = GetAllStepMetadata
+			}
+			err := pipelineGraph(GeneralConfig.MetaDataResolver(), utils)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				log.Entry().WithError(err).Fatal("Creating the pipeline graph failed")
+			}
+		},
+	}
+	addPipelineGraphFlags(pipelineGraphCmd)
+	return pipelineGraphCmd
+}
+
+func pipelineGraph(metadata map[string]config.StepData, utils piperutils.FileUtils) error {
+	var pipeline *config.PipelineDefinitionV1
+	if len(pipelineGraphOptions.stageConfigFile) > 0 {
+		stageConfigFile, err := pipelineGraphOptions.openFile(pipelineGraphOptions.stageConfigFile, GeneralConfig.GitHubAccessTokens)
+		if err != nil {
+			return errors.Wrapf(err, "config: open stage configuration file '%v' failed", pipelineGraphOptions.stageConfigFile)
+		}
+		runConfigV1 := &config.RunConfigV1{RunConfig: config.RunConfig{StageConfigFile: stageConfigFile}}
+		if err := runConfigV1.LoadConditionsV1(); err != nil {
+			return errors.Wrap(err, "failed to load stage configuration")
+		}
+		pipeline = &runConfigV1.PipelineConfig
+	}
+
+	graph := config.NewDependencyGraph(metadata, pipeline)
+
+	var output []byte
+	switch pipelineGraphOptions.format {
+	case "dot":
+		output = []byte(graph.DOT())
+	case "mermaid":
+		output = []byte(graph.Mermaid())
+	case "json":
+		result, err := json.MarshalIndent(graph, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshalling json: %w", err)
+		}
+		output = result
+	default:
+		return fmt.Errorf("unsupported format '%v', use one of dot, mermaid, json", pipelineGraphOptions.format)
+	}
+
+	if len(pipelineGraphOptions.outputFile) > 0 {
+		log.Entry().Infof("Writing pipeline graph to %v", pipelineGraphOptions.outputFile)
+		if err := utils.FileWrite(pipelineGraphOptions.outputFile, output, 0666); err != nil {
+			return fmt.Errorf("error writing file '%v': %w", pipelineGraphOptions.outputFile, err)
+		}
+	} else {
+		fmt.Print(string(output))
+	}
+
+	for _, issue := range graph.Issues {
+		log.Entry().Warning(issue.Message)
+	}
+	if pipelineGraphOptions.failOnIssues && len(graph.Issues) > 0 {
+		return errors.Errorf("%v dependency issues found in the pipeline", len(graph.Issues))
+	}
+	return nil
+}
+
+func addPipelineGraphFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&pipelineGraphOptions.stageConfigFile, "stageConfig", "", "CRD-style stage configuration defining the order of stages and steps. If not set, all steps are considered without order")
+	cmd.Flags().StringVar(&pipelineGraphOptions.format, "format", "dot", "Output format: dot, mermaid or json")
+	cmd.Flags().StringVar(&pipelineGraphOptions.outputFile, "outputFile", "", "Defines a file path. If set, the graph will be written to the defined file instead of stdout")
+	cmd.Flags().BoolVar(&pipelineGraphOptions.failOnIssues, "failOnIssues", false, "Fail if steps consume values which are not produced by other steps or which are produced too late")
+}
@@ -0,0 +1,119 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func pipelineGraphOpenFileMock(name string, tokens map[string]string) (io.ReadCloser, error) {
+	var fileContent string
+	switch name {
+	case "stage-config.yml":
+		fileContent = `
+spec:
+  stages:
+    - name: Build
+      steps:
+        - name: consumerStep
+        - name: producerStep`
+	case "piper-stage-config.yml":
+		fileContent = `
+spec:
+  stages:
+    - name: Build
+      steps:
+        - name: artifactPrepareVersion
+        - name: mavenBuild
+    - name: Security
+      steps:
+        - name: detectExecuteScan
+    - name: Release
+      steps:
+        - name: githubPublishRelease`
+	default:
+		fileContent = ""
+	}
+	return io.NopCloser(strings.NewReader(fileContent)), nil
+}
+
+func TestPipelineGraph(t *testing.T) {
+	metadata := map[string]config.StepData{
+		"producerStep": {Spec: config.StepSpec{Outputs: config.StepOutputs{Resources: []config.StepResources{
+			{Name: "commonPipelineEnvironment", Type: "piperEnvironment", Parameters: []map[string]interface{}{{"name": "artifactVersion"}}},
+		}}}},
+		"consumerStep": {Spec: config.StepSpec{Inputs: config.StepInputs{Parameters: []config.StepParameters{
+			{Name: "version", ResourceRef: []config.ResourceReference{{Name: "commonPipelineEnvironment", Param: "artifactVersion"}}},
+		}}}},
+	}
+
+	t.Run("success case - mermaid without stage configuration", func(t *testing.T) {
+		pipelineGraphOptions = pipelineGraphCommandOptions{openFile: pipelineGraphOpenFileMock, format: "mermaid", outputFile: "graph.mmd", failOnIssues: true}
+		utils := &mock.FilesMock{}
+
+		err := pipelineGraph(metadata, utils)
+
+		assert.NoError(t, err)
+		content, err := utils.FileRead("graph.mmd")
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "producerStep -->|\"artifactVersion\"| consumerStep")
+	})
+
+	t.Run("success case - issues are only reported", func(t *testing.T) {
+		pipelineGraphOptions = pipelineGraphCommandOptions{openFile: pipelineGraphOpenFileMock, stageConfigFile: "stage-config.yml", format: "json", outputFile: "graph.json"}
+		utils := &mock.FilesMock{}
+
+		err := pipelineGraph(metadata, utils)
+
+		assert.NoError(t, err)
+		content, err := utils.FileRead("graph.json")
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), `"type": "wrongOrder"`)
+	})
+
+	t.Run("error case - fail on issues", func(t *testing.T) {
+		pipelineGraphOptions = pipelineGraphCommandOptions{openFile: pipelineGraphOpenFileMock, stageConfigFile: "stage-config.yml", format: "dot", outputFile: "graph.dot", failOnIssues: true}
+		utils := &mock.FilesMock{}
+
+		err := pipelineGraph(metadata, utils)
+
+		assert.EqualError(t, err, "1 dependency issues found in the pipeline")
+	})
+
+	t.Run("success case - step metadata without stage configuration", func(t *testing.T) {
+		pipelineGraphOptions = pipelineGraphCommandOptions{openFile: pipelineGraphOpenFileMock, format: "json", outputFile: "graph.json", failOnIssues: true}
+		utils := &mock.FilesMock{}
+
+		err := pipelineGraph(GetAllStepMetadata(), utils)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("success case - step metadata with stage configuration", func(t *testing.T) {
+		pipelineGraphOptions = pipelineGraphCommandOptions{openFile: pipelineGraphOpenFileMock, stageConfigFile: "piper-stage-config.yml", format: "mermaid", outputFile: "graph.mmd", failOnIssues: true}
+		utils := &mock.FilesMock{}
+
+		err := pipelineGraph(GetAllStepMetadata(), utils)
+
+		assert.NoError(t, err)
+		content, err := utils.FileRead("graph.mmd")
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "Build_artifactPrepareVersion -->|\"artifactVersion\"| Release_githubPublishRelease")
+	})
+
+	t.Run("error case - unsupported format", func(t *testing.T) {
+		pipelineGraphOptions = pipelineGraphCommandOptions{openFile: pipelineGraphOpenFileMock, format: "svg"}
+		utils := &mock.FilesMock{}
+
+		err := pipelineGraph(metadata, utils)
+
+		assert.EqualError(t, err, "unsupported format 'svg', use one of dot, mermaid, json")
+	})
+}
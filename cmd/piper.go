@@ -42,11 +42,16 @@ type GeneralConfigOptions struct {
 	VaultNamespace       string
 	VaultPath            string
 	HookConfig           HookConfiguration
+	StepHookConfig       StepHookConfiguration
+	StepRetryConfig      StepRetryConfiguration
 	MetaDataResolver     func() map[string]config.StepData
 	GCPJsonKeyFilePath   string
 	GCSFolderPath        string
 	GCSBucketId          string
 	GCSSubFolder         string
+	ConfigCacheDir       string
+	ConfigOffline        bool
+	ConfigPublicKeyFile  string
 }
 
 // HookConfiguration contains the configuration for supported hooks, so far Sentry and Splunk are supported.
@@ -106,6 +111,7 @@ func Execute() {
 	rootCmd.AddCommand(GithubCreateIssueCommand())
 	rootCmd.AddCommand(GithubCreatePullRequestCommand())
 	rootCmd.AddCommand(GithubPublishReleaseCommand())
+	rootCmd.AddCommand(GithubPublishCheckRunCommand())
 	rootCmd.AddCommand(GithubSetCommitStatusCommand())
 	rootCmd.AddCommand(GitopsUpdateDeploymentCommand())
 	rootCmd.AddCommand(CloudFoundryDeleteServiceCommand())
@@ -116,6 +122,7 @@ func Execute() {
 	rootCmd.AddCommand(AbapEnvironmentCreateSystemCommand())
 	rootCmd.AddCommand(CheckmarxExecuteScanCommand())
 	rootCmd.AddCommand(CheckmarxOneExecuteScanCommand())
+	rootCmd.AddCommand(CheckmarxMigrateTriageCommand())
 	rootCmd.AddCommand(FortifyExecuteScanCommand())
 	rootCmd.AddCommand(CodeqlExecuteScanCommand())
 	rootCmd.AddCommand(CredentialdiggerScanCommand())
@@ -183,6 +190,7 @@ func Execute() {
 	rootCmd.AddCommand(InfluxWriteDataCommand())
 	rootCmd.AddCommand(AbapEnvironmentRunAUnitTestCommand())
 	rootCmd.AddCommand(CheckStepActiveCommand())
+	rootCmd.AddCommand(PipelineGraphCommand())
 	rootCmd.AddCommand(GolangBuildCommand())
 	rootCmd.AddCommand(ShellExecuteCommand())
 	rootCmd.AddCommand(ApiProxyDownloadCommand())
@@ -201,6 +209,15 @@ func Execute() {
 	rootCmd.AddCommand(TmsExportCommand())
 	rootCmd.AddCommand(IntegrationArtifactTransportCommand())
 	rootCmd.AddCommand(AscAppUploadCommand())
+	rootCmd.AddCommand(SbomExecuteVulnerabilityScanCommand())
+	rootCmd.AddCommand(SecretExecuteScanCommand())
+	rootCmd.AddCommand(SbomProcessCommand())
+	rootCmd.AddCommand(LicenseComplianceCheckCommand())
+	rootCmd.AddCommand(ProvenanceCreateCommand())
+	rootCmd.AddCommand(ContainerSignImageCommand())
+	rootCmd.AddCommand(ContainerVerifyImageCommand())
+	rootCmd.AddCommand(ContainerExecuteVulnerabilityScanCommand())
+	rootCmd.AddCommand(DependencyRemediateCommand())
 
 	addRootFlags(rootCmd)
 
@@ -239,6 +256,9 @@ func addRootFlags(rootCmd *cobra.Command) {
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.GCSFolderPath, "gcsFolderPath", "", "GCS folder path. One of the components of GCS target folder")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.GCSBucketId, "gcsBucketId", "", "Bucket name for Google Cloud Storage")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.GCSSubFolder, "gcsSubFolder", "", "Used to logically separate results of the same step result type")
+	rootCmd.PersistentFlags().StringVar(&GeneralConfig.ConfigCacheDir, "configCacheDir", os.Getenv("PIPER_configCacheDir"), "Directory used to cache configuration / defaults downloaded via http(s)")
+	rootCmd.PersistentFlags().BoolVar(&GeneralConfig.ConfigOffline, "configOffline", os.Getenv("PIPER_configOffline") == "true", "Only use cached configuration / defaults instead of downloading them via http(s)")
+	rootCmd.PersistentFlags().StringVar(&GeneralConfig.ConfigPublicKeyFile, "configPublicKeyFile", os.Getenv("PIPER_configPublicKeyFile"), "Path to a PEM encoded public key used to verify the signature (<url>.sig) of configuration / defaults downloaded via http(s)")
 
 }
 
@@ -271,6 +291,15 @@ func AccessTokensFromEnvJSON(env string) []string {
 	return accessTokens
 }
 
+// initRemoteFileOptions passes the options for configuration / defaults downloaded via http(s) to the config package
+func initRemoteFileOptions() {
+	config.SetRemoteFileOptions(config.RemoteFileOptions{
+		CacheDir:      GeneralConfig.ConfigCacheDir,
+		Offline:       GeneralConfig.ConfigOffline,
+		PublicKeyFile: GeneralConfig.ConfigPublicKeyFile,
+	})
+}
+
 // initStageName initializes GeneralConfig.StageName from either GeneralConfig.ParametersJSON
 // or the environment variable (orchestrator specific), unless it has been provided as command line option.
 // Log output needs to be suppressed via outputToLog by the getConfig step.
@@ -327,6 +356,7 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 	log.SetFormatter(GeneralConfig.LogFormat)
 
 	initStageName(true)
+	initRemoteFileOptions()
 
 	filters := metadata.GetParameterFilters()
 
@@ -335,6 +365,17 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 	filters.General = append(filters.General, "collectTelemetryData")
 	filters.Parameters = append(filters.Parameters, "collectTelemetryData")
 
+	// add user-defined step hooks "hooks" to ALL, STEPS and STAGES filters
+	filters.All = append(filters.All, "hooks")
+	filters.Steps = append(filters.Steps, "hooks")
+	filters.Stages = append(filters.Stages, "hooks")
+
+	// add step retry policy "retry" to ALL, GENERAL, STEPS and STAGES filters
+	filters.All = append(filters.All, "retry")
+	filters.General = append(filters.General, "retry")
+	filters.Steps = append(filters.Steps, "retry")
+	filters.Stages = append(filters.Stages, "retry")
+
 	envParams := metadata.GetResourceParameters(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 	reportingEnvParams := config.ReportingParameters.GetResourceParameters(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 	resourceParams := mergeResourceParameters(envParams, reportingEnvParams)
@@ -418,6 +459,8 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 	config.MarkFlagsWithValue(cmd, stepConfig)
 
 	retrieveHookConfig(stepConfig.HookConfig, &GeneralConfig.HookConfig)
+	retrieveStepHookConfig(stepConfig.Config["hooks"], &GeneralConfig.StepHookConfig)
+	retrieveStepRetryConfig(stepConfig.Config["retry"], &GeneralConfig.StepRetryConfig)
 
 	if GeneralConfig.GCPJsonKeyFilePath == "" {
 		GeneralConfig.GCPJsonKeyFilePath, _ = stepConfig.Config["gcpJsonKeyFilePath"].(string)
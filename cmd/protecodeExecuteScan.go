@@ -12,14 +12,17 @@ import (
 	"strings"
 	"time"
 
+	cdx "github.com/CycloneDX/cyclonedx-go"
 	"github.com/pkg/errors"
 
 	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/docker"
 	piperDocker "github.com/SAP/jenkins-library/pkg/docker"
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/protecode"
+	"github.com/SAP/jenkins-library/pkg/sbom"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/toolrecord"
 	"github.com/SAP/jenkins-library/pkg/versioning"
@@ -222,6 +225,11 @@ func executeProtecodeScan(influx *protecodeExecuteScanInflux, client protecode.P
 	log.Entry().Debugf("Delete scan %v for %v", config.CleanupMode, productID)
 	client.DeleteScan(config.CleanupMode, productID)
 
+	// assessed vulnerabilities are handled like triaged ones
+	assessments := readAssessmentsFromFile(config.AssessmentFile, utils)
+	vexStatements := protecode.ApplyAssessments(&result.Result, assessments)
+	vexStatements = append(vexStatements, protecode.TriageVEXStatements(result.Result)...)
+
 	//count vulnerabilities
 	log.Entry().Debug("Parse scan result")
 	parsedResult, vulns := client.ParseResultForInflux(result.Result, config.ExcludeCVEs)
@@ -265,6 +273,19 @@ func executeProtecodeScan(influx *protecodeExecuteScanInflux, client protecode.P
 		reports = append(reports, paths...)
 	}
 
+	vexPaths, err := format.WriteVEX(protecode.ReportsDirectory, "Protecode", vexStatements, utils)
+	if err != nil {
+		log.Entry().Warning("failed to create VEX file ...", err)
+	} else {
+		reports = append(reports, vexPaths...)
+	}
+
+	if err := writeProtecodeSBOM(config, fileName, result.Result, utils); err != nil {
+		log.Entry().Warning("failed to create SBOM file ...", err)
+	} else {
+		reports = append(reports, piperutils.Path{Target: protecode.SBOMFileName})
+	}
+
 	// create toolrecord file
 	toolRecordFileName, err := createToolRecordProtecode(utils, "./", config, productID, webuiURL)
 	if err != nil {
@@ -400,6 +421,15 @@ func correctDockerConfigEnvVar(config *protecodeExecuteScanOptions, utils protec
 	return nil
 }
 
+// writeProtecodeSBOM writes the components detected by Protecode as CycloneDX SBOM, a scanned image is described as container
+func writeProtecodeSBOM(config *protecodeExecuteScanOptions, fileName string, result protecode.Result, utils protecodeUtils) error {
+	name, componentType := fileName, cdx.ComponentTypeApplication
+	if len(config.ScanImage) > 0 {
+		name, componentType = config.ScanImage, cdx.ComponentTypeContainer
+	}
+	return sbom.WriteBOM(result.ToBOM(name, config.Version, componentType), protecode.SBOMFileName, utils)
+}
+
 // Calculate version based on versioning model and artifact version or return custom scan version provided by user
 func getProcessedVersion(config *protecodeExecuteScanOptions) string {
 	processedVersion := config.CustomScanVersion
@@ -23,6 +23,7 @@ import (
 
 type protecodeExecuteScanOptions struct {
 	ExcludeCVEs                 string `json:"excludeCVEs,omitempty"`
+	AssessmentFile              string `json:"assessmentFile,omitempty"`
 	FailOnSevereVulnerabilities bool   `json:"failOnSevereVulnerabilities,omitempty"`
 	ScanImage                   string `json:"scanImage,omitempty"`
 	DockerRegistryURL           string `json:"dockerRegistryUrl,omitempty"`
@@ -113,6 +114,8 @@ func (p *protecodeExecuteScanReports) persist(stepConfig protecodeExecuteScanOpt
 		{FilePattern: "", ParamRef: "reportFileName", StepResultType: "protecode"},
 		{FilePattern: "**/protecodeExecuteScan.json", ParamRef: "", StepResultType: "protecode"},
 		{FilePattern: "**/protecodescan_vulns.json", ParamRef: "", StepResultType: "protecode"},
+		{FilePattern: "**/protecode/piper_vex.json", ParamRef: "", StepResultType: "protecode"},
+		{FilePattern: "**/bom-protecode.xml", ParamRef: "", StepResultType: "sbom"},
 	}
 	envVars := []gcs.EnvVar{
 		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
@@ -157,6 +160,14 @@ func ProtecodeExecuteScanCommand() *cobra.Command {
 		Long: `Black Duck Binary Analysis (previously known as Protecode) is an Open Source Vulnerability Scan tool which provides the composition of Open Source components in a product along with Security information (no license info is provided).
 BDBA (Protecode) uses a combination of static binary analysis techniques to X-ray the provided software package to identify third-party software components and their exact versions with a high level of confidence. Methods range from simple string matching to proprietary patent-pending techniques.
 
+The detected components are written as CycloneDX SBOM ` + "`" + `bom-protecode.xml` + "`" + ` and triaged as well as assessed vulnerabilities as CycloneDX VEX ` + "`" + `protecode/piper_vex.json` + "`" + `.
+Since Protecode triages are free text without a structured reason, triaged vulnerabilities are reported as ` + "`" + `in_triage` + "`" + ` with the triage descriptions as detail. Only vulnerabilities assessed in the assessment file are reported as ` + "`" + `not_affected` + "`" + `.
+
+!!! note "Package URLs"
+    Protecode does not provide the package type of a component, thus components are identified by generic package URLs ` + "`" + `pkg:generic/<lib>@<version>` + "`" + `.
+    These do not match the typed package URLs (e.g. ` + "`" + `pkg:maven/...` + "`" + ` or ` + "`" + `pkg:npm/...` + "`" + `) of the SBOMs created by build steps like ` + "`" + `cnbBuild` + "`" + ` or ` + "`" + `kanikoExecute` + "`" + `,
+    hence the VEX statements cannot be correlated with those SBOMs automatically.
+
 !!! hint "Auditing findings (Triaging)"
     Triaging is now supported by the BDBA (Protecode) backend and also Piper does consider this information during the analysis of the scan results though product versions are not supported by BDBA (Protecode). Therefore please make sure that the ` + "`" + `fileName` + "`" + ` you are providing does either contain a stable version or that it does not contain one at all. By ensuring that you are able to triage CVEs globally on the upload file's name without affecting any other artifacts scanned in the same BDBA (Protecode) group and as such triaged vulnerabilities will be considered during the next scan and will not fail the build anymore.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
@@ -215,6 +226,7 @@ BDBA (Protecode) uses a combination of static binary analysis techniques to X-ra
 				influx.persist(GeneralConfig.EnvRootPath, "influx")
 				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
 				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
 				stepTelemetryData.PiperCommitHash = GitCommit
@@ -240,7 +252,12 @@ BDBA (Protecode) uses a combination of static binary analysis techniques to X-ra
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
-			protecodeExecuteScan(stepConfig, &stepTelemetryData, &influx)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+				influx = protecodeExecuteScanInflux{}
+			}, func() {
+				protecodeExecuteScan(stepConfig, &stepTelemetryData, &influx)
+			})
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
@@ -252,6 +269,7 @@ BDBA (Protecode) uses a combination of static binary analysis techniques to X-ra
 
 func addProtecodeExecuteScanFlags(cmd *cobra.Command, stepConfig *protecodeExecuteScanOptions) {
 	cmd.Flags().StringVar(&stepConfig.ExcludeCVEs, "excludeCVEs", ``, "DEPRECATED: Do use triaging within the Protecode UI instead")
+	cmd.Flags().StringVar(&stepConfig.AssessmentFile, "assessmentFile", `hs-assessments.yaml`, "Explicit path to the assessment YAML file. Assessed vulnerabilities are handled like vulnerabilities triaged within Protecode and are written as CycloneDX VEX together with the triaged ones. Components are identified by the package URL `pkg:generic/<lib>@<version>`.")
 	cmd.Flags().BoolVar(&stepConfig.FailOnSevereVulnerabilities, "failOnSevereVulnerabilities", true, "Whether to fail the step on severe vulnerabilties or not")
 	cmd.Flags().StringVar(&stepConfig.ScanImage, "scanImage", os.Getenv("PIPER_scanImage"), "The reference to the docker image to scan with Protecode. Note: If possible please also check [fetchUrl](https://www.project-piper.io/steps/protecodeExecuteScan/#fetchurl) parameter, which might help you to optimize upload time.")
 	cmd.Flags().StringVar(&stepConfig.DockerRegistryURL, "dockerRegistryUrl", os.Getenv("PIPER_dockerRegistryUrl"), "The reference to the docker registry to scan with Protecode")
@@ -307,6 +325,15 @@ func protecodeExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{{Name: "protecodeExcludeCVEs"}},
 						Default:     ``,
 					},
+					{
+						Name:        "assessmentFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `hs-assessments.yaml`,
+					},
 					{
 						Name:        "failOnSevereVulnerabilities",
 						ResourceRef: []config.ResourceReference{},
@@ -621,6 +648,8 @@ func protecodeExecuteScanMetadata() config.StepData {
 							{"type": "protecode"},
 							{"filePattern": "**/protecodeExecuteScan.json", "type": "protecode"},
 							{"filePattern": "**/protecodescan_vulns.json", "type": "protecode"},
+							{"filePattern": "**/protecode/piper_vex.json", "type": "protecode"},
+							{"filePattern": "**/bom-protecode.xml", "type": "sbom"},
 						},
 					},
 				},
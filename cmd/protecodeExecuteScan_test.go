@@ -150,6 +150,10 @@ func TestRunProtecodeScan(t *testing.T) {
 				if userSpecifiedReportExists, err := files.FileExists(test.opts.ReportFileName); assert.NoError(t, err) {
 					assert.True(t, userSpecifiedReportExists, "%s must exist", test.opts.ReportFileName)
 				}
+
+				if sbomExists, err := files.FileExists("bom-protecode.xml"); assert.NoError(t, err) {
+					assert.True(t, sbomExists, "bom-protecode.xml expected")
+				}
 			}
 
 			if cacheExists, err := files.DirExists(cacheDir); assert.NoError(t, err) {
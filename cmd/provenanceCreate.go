@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/provenance"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+const (
+	provenanceCreateDirectory        = "provenance"
+	provenanceCreateFileSuffix       = ".intoto.jsonl"
+	provenanceCreateDefaultBuilderID = "https://github.com/SAP/jenkins-library"
+)
+
+type provenanceCreateUtils interface {
+	piperutils.FileUtils
+
+	NewOrchestratorSpecificConfigProvider() (orchestrator.OrchestratorSpecificConfigProviding, error)
+	AttachToImage(image string, envelope *provenance.Envelope) (string, error)
+}
+
+type provenanceCreateUtilsBundle struct {
+	*piperutils.Files
+}
+
+func (p *provenanceCreateUtilsBundle) NewOrchestratorSpecificConfigProvider() (orchestrator.OrchestratorSpecificConfigProviding, error) {
+	return orchestrator.NewOrchestratorSpecificConfigProvider()
+}
+
+func (p *provenanceCreateUtilsBundle) AttachToImage(image string, envelope *provenance.Envelope) (string, error) {
+	return provenance.AttachToImage(image, envelope, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+}
+
+func newProvenanceCreateUtils() provenanceCreateUtils {
+	utils := provenanceCreateUtilsBundle{
+		Files: &piperutils.Files{},
+	}
+	return &utils
+}
+
+// provenanceSubject is an artifact or image together with the location of its provenance
+type provenanceSubject struct {
+	subject provenance.Subject
+	file    string
+	image   string
+}
+
+func provenanceCreate(config provenanceCreateOptions, _ *telemetry.CustomData) {
+	utils := newProvenanceCreateUtils()
+
+	if config.AttachToImage && len(config.DockerConfigJSON) > 0 {
+		if err := setDockerConfigEnv(config.DockerConfigJSON, utils); err != nil {
+			log.Entry().WithError(err).Fatal("step execution failed")
+		}
+	}
+
+	err := runProvenanceCreate(&config, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runProvenanceCreate(config *provenanceCreateOptions, utils provenanceCreateUtils) error {
+	keyPEM, err := utils.FileRead(config.SigningKey)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "failed to read signing key")
+	}
+	signer, err := provenance.NewSigner(keyPEM, config.SigningKeyID)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "invalid signing key")
+	}
+
+	subjects, err := provenanceCreateArtifactSubjects(config, utils)
+	if err != nil {
+		return err
+	}
+	imageSubjects, err := provenanceCreateImageSubjects(config)
+	if err != nil {
+		return err
+	}
+	subjects = append(subjects, imageSubjects...)
+	if len(subjects) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("no artifacts or container images found to create provenance for")
+	}
+
+	buildInfo := provenanceCreateBuildInfo(config, utils)
+	reportPaths := []piperutils.Path{}
+	for _, subject := range subjects {
+		envelope, err := signer.SignStatement(provenance.NewStatement([]provenance.Subject{subject.subject}, buildInfo))
+		if err != nil {
+			return errors.Wrapf(err, "failed to sign provenance of '%v'", subject.subject.Name)
+		}
+		if err := writeProvenanceEnvelope(subject.file, envelope, utils); err != nil {
+			return err
+		}
+		log.Entry().Infof("Provenance of '%v' written to %v", subject.subject.Name, subject.file)
+		reportPaths = append(reportPaths, piperutils.Path{Target: subject.file})
+
+		if config.AttachToImage && len(subject.image) > 0 {
+			referrer, err := utils.AttachToImage(subject.image, envelope)
+			if err != nil {
+				return errors.Wrapf(err, "failed to attach provenance to image '%v'", subject.image)
+			}
+			log.Entry().Infof("Provenance attached to image '%v' as %v", subject.image, referrer)
+		}
+	}
+
+	piperutils.PersistReportsAndLinks("provenanceCreate", "", utils, reportPaths, nil)
+	return nil
+}
+
+func provenanceCreateArtifactSubjects(config *provenanceCreateOptions, utils provenanceCreateUtils) ([]provenanceSubject, error) {
+	subjects := []provenanceSubject{}
+	artifacts := []string{}
+	for _, pattern := range config.ArtifactPatterns {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "failed to find artifacts for pattern '%v'", pattern)
+		}
+		for _, match := range matches {
+			if strings.HasSuffix(match, provenanceCreateFileSuffix) || piperutils.ContainsString(artifacts, match) {
+				continue
+			}
+			if isDir, _ := utils.DirExists(match); isDir {
+				continue
+			}
+			artifacts = append(artifacts, match)
+		}
+	}
+
+	for _, artifact := range artifacts {
+		subject, err := provenance.FileSubject(filepath.Base(artifact), artifact, utils)
+		if err != nil {
+			return nil, err
+		}
+		subjects = append(subjects, provenanceSubject{subject: subject, file: artifact + provenanceCreateFileSuffix})
+	}
+	return subjects, nil
+}
+
+func provenanceCreateImageSubjects(config *provenanceCreateOptions) ([]provenanceSubject, error) {
+	subjects := []provenanceSubject{}
+	if len(config.ContainerImageNameTags) != len(config.ContainerImageDigests) {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("number of container images (%v) does not match the number of image digests (%v)", len(config.ContainerImageNameTags), len(config.ContainerImageDigests))
+	}
+
+	registry := ""
+	if len(config.ContainerRegistryURL) > 0 {
+		var err error
+		if _, registry, err = splitRegistryURL(config.ContainerRegistryURL); err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, err
+		}
+	}
+
+	for i, imageNameTag := range config.ContainerImageNameTags {
+		imageName := imageNameTag
+		if len(registry) > 0 {
+			imageName = fmt.Sprintf("%v/%v", registry, imageNameTag)
+		}
+		reference, err := name.ParseReference(imageName)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "invalid container image '%v'", imageName)
+		}
+		repository := reference.Context().Name()
+		if len(registry) == 0 {
+			// keep the image name as provided instead of defaulting to Docker Hub
+			repository = strings.TrimSuffix(imageNameTag, ":"+reference.Identifier())
+		}
+
+		subject, err := provenance.ImageSubject(repository, config.ContainerImageDigests[i])
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, err
+		}
+		fileName := strings.NewReplacer("/", "_", ":", "_").Replace(imageNameTag) + provenanceCreateFileSuffix
+		imageSubject := provenanceSubject{subject: subject, file: filepath.Join(provenanceCreateDirectory, fileName)}
+		if config.AttachToImage {
+			if len(registry) == 0 {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return nil, fmt.Errorf("containerRegistryUrl is required to attach the provenance to image '%v'", imageNameTag)
+			}
+			imageSubject.image = fmt.Sprintf("%v@%v", repository, config.ContainerImageDigests[i])
+		}
+		subjects = append(subjects, imageSubject)
+	}
+	return subjects, nil
+}
+
+func provenanceCreateBuildInfo(config *provenanceCreateOptions, utils provenanceCreateUtils) provenance.BuildInfo {
+	provider, err := utils.NewOrchestratorSpecificConfigProvider()
+	if err != nil {
+		log.Entry().WithError(err).Warning("Failed to detect orchestrator, provenance does not contain build details")
+	}
+
+	externalParameters := map[string]interface{}{}
+	if len(config.ArtifactVersion) > 0 {
+		externalParameters["artifactVersion"] = config.ArtifactVersion
+	}
+	if len(config.BuildSettingsInfo) > 0 {
+		var buildSettings interface{}
+		if err := json.Unmarshal([]byte(config.BuildSettingsInfo), &buildSettings); err != nil {
+			log.Entry().WithError(err).Warning("Failed to parse build settings info, it is recorded as text")
+			buildSettings = config.BuildSettingsInfo
+		}
+		externalParameters["buildSettings"] = buildSettings
+	}
+
+	builderVersion := map[string]string{}
+	if len(GitCommit) > 0 {
+		builderVersion["piper"] = GitCommit
+	}
+	buildInfo := provenance.BuildInfo{
+		BuilderID:          provenanceCreateDefaultBuilderID,
+		BuilderVersion:     builderVersion,
+		FinishedOn:         time.Now(),
+		ExternalParameters: externalParameters,
+	}
+	if provider == nil {
+		return buildInfo
+	}
+
+	if jobURL := orchestratorValue(provider.GetJobURL()); len(jobURL) > 0 {
+		buildInfo.BuilderID = jobURL
+	}
+	if version := orchestratorValue(provider.OrchestratorVersion()); len(version) > 0 {
+		builderVersion[provider.OrchestratorType()] = version
+	}
+	buildInfo.InvocationID = orchestratorValue(provider.GetBuildURL())
+	buildInfo.RepositoryURL = orchestratorValue(provider.GetRepoURL())
+	buildInfo.Commit = orchestratorValue(provider.GetCommit())
+	buildInfo.Branch = orchestratorValue(provider.GetBranch())
+	buildInfo.StartedOn = provider.GetPipelineStartTime()
+	buildInfo.InternalParameters = map[string]interface{}{
+		"orchestrator": provider.OrchestratorType(),
+		"jobName":      orchestratorValue(provider.GetJobName()),
+		"buildId":      orchestratorValue(provider.GetBuildID()),
+	}
+	return buildInfo
+}
+
+// orchestratorValue removes the placeholder returned by orchestrator providers for unavailable values
+func orchestratorValue(value string) string {
+	if value == "n/a" {
+		return ""
+	}
+	return value
+}
+
+func writeProvenanceEnvelope(path string, envelope *provenance.Envelope, utils provenanceCreateUtils) error {
+	// ignore JSON errors since structure is in our hands
+	envelopeJSON, _ := json.Marshal(envelope)
+	if err := utils.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return errors.Wrapf(err, "failed to create directory for provenance '%v'", path)
+	}
+	if err := utils.FileWrite(path, append(envelopeJSON, '\n'), 0666); err != nil {
+		return errors.Wrapf(err, "failed to write provenance '%v'", path)
+	}
+	return nil
+}
+
+// setDockerConfigEnv makes the registry credentials available to the default keychain of go-containerregistry
+func setDockerConfigEnv(dockerConfigJSON string, utils piperutils.FileUtils) error {
+	dockerConfigDir, err := utils.TempDir("", "docker")
+	if err != nil {
+		return errors.Wrap(err, "unable to create docker config directory")
+	}
+	if _, err := utils.Copy(dockerConfigJSON, filepath.Join(dockerConfigDir, "config.json")); err != nil {
+		return errors.Wrap(err, "unable to copy docker config")
+	}
+	return os.Setenv("DOCKER_CONFIG", dockerConfigDir)
+}
@@ -0,0 +1,227 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/provenance"
+)
+
+type provenanceCreateTestProvider struct {
+	orchestrator.UnknownOrchestratorConfigProvider
+}
+
+func (p *provenanceCreateTestProvider) OrchestratorType() string    { return "Jenkins" }
+func (p *provenanceCreateTestProvider) OrchestratorVersion() string { return "2.401" }
+func (p *provenanceCreateTestProvider) GetJobURL() string {
+	return "https://jenkins.example.com/job/shop/"
+}
+func (p *provenanceCreateTestProvider) GetBuildURL() string {
+	return "https://jenkins.example.com/job/shop/42/"
+}
+func (p *provenanceCreateTestProvider) GetRepoURL() string { return "https://github.com/org/shop" }
+func (p *provenanceCreateTestProvider) GetCommit() string  { return "0123456789abcdef" }
+func (p *provenanceCreateTestProvider) GetBranch() string  { return "main" }
+func (p *provenanceCreateTestProvider) GetPipelineStartTime() time.Time {
+	return time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+}
+
+type provenanceCreateMockUtils struct {
+	*mock.FilesMock
+	attached map[string]*provenance.Envelope
+}
+
+func (p *provenanceCreateMockUtils) NewOrchestratorSpecificConfigProvider() (orchestrator.OrchestratorSpecificConfigProviding, error) {
+	return &provenanceCreateTestProvider{}, nil
+}
+
+func (p *provenanceCreateMockUtils) AttachToImage(image string, envelope *provenance.Envelope) (string, error) {
+	if image == "registry.example.com/broken@sha256:abc" {
+		return "", fmt.Errorf("unauthorized")
+	}
+	p.attached[image] = envelope
+	return "sha256:referrer", nil
+}
+
+func newProvenanceCreateTestsUtils(t *testing.T) (*provenanceCreateMockUtils, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	utils := provenanceCreateMockUtils{
+		FilesMock: &mock.FilesMock{},
+		attached:  map[string]*provenance.Envelope{},
+	}
+	utils.AddFile("signing-key.pem", pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+	utils.AddFile("target/shop.jar", []byte("jar"))
+	return &utils, key
+}
+
+func readProvenanceEnvelope(t *testing.T, path string, utils *provenanceCreateMockUtils) provenance.Envelope {
+	content, err := utils.FileRead(path)
+	require.NoError(t, err)
+	envelope := provenance.Envelope{}
+	require.NoError(t, json.Unmarshal(content, &envelope))
+	return envelope
+}
+
+func TestRunProvenanceCreate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("artifacts", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := provenanceCreateOptions{
+			ArtifactPatterns:  []string{"target/*.jar", "target/*"},
+			ArtifactVersion:   "1.0.0",
+			BuildSettingsInfo: `{"mavenBuild":[{"profiles":["release"]}]}`,
+			SigningKey:        "signing-key.pem",
+			SigningKeyID:      "release-key",
+		}
+		utils, key := newProvenanceCreateTestsUtils(t)
+		utils.AddFile("target/shop.jar.intoto.jsonl", []byte("previous provenance"))
+		// test
+		err := runProvenanceCreate(&config, utils)
+		// assert
+		require.NoError(t, err)
+		envelope := readProvenanceEnvelope(t, "target/shop.jar.intoto.jsonl", utils)
+		assert.NoError(t, envelope.Verify(&key.PublicKey))
+		assert.Equal(t, "release-key", envelope.Signatures[0].KeyID)
+		statement, err := envelope.Statement()
+		require.NoError(t, err)
+		digest := sha256.Sum256([]byte("jar"))
+		assert.Equal(t, []provenance.Subject{{Name: "shop.jar", Digest: map[string]string{"sha256": hex.EncodeToString(digest[:])}}}, statement.Subject)
+		assert.False(t, utils.HasWrittenFile("target/shop.jar.intoto.jsonl.intoto.jsonl"), "provenance files are no artifacts")
+		assert.Equal(t, "https://jenkins.example.com/job/shop/", statement.Predicate.RunDetails.Builder.ID)
+		assert.Equal(t, "2.401", statement.Predicate.RunDetails.Builder.Version["Jenkins"])
+		assert.Equal(t, "https://jenkins.example.com/job/shop/42/", statement.Predicate.RunDetails.Metadata.InvocationID)
+		assert.Equal(t, []provenance.ResourceDescriptor{{URI: "git+https://github.com/org/shop@refs/heads/main", Digest: map[string]string{"gitCommit": "0123456789abcdef"}}}, statement.Predicate.BuildDefinition.ResolvedDependencies)
+		assert.Equal(t, "1.0.0", statement.Predicate.BuildDefinition.ExternalParameters["artifactVersion"])
+		assert.Equal(t, map[string]interface{}{"mavenBuild": []interface{}{map[string]interface{}{"profiles": []interface{}{"release"}}}}, statement.Predicate.BuildDefinition.ExternalParameters["buildSettings"])
+	})
+
+	t.Run("images", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := provenanceCreateOptions{
+			ContainerRegistryURL:   "https://registry.example.com",
+			ContainerImageNameTags: []string{"shop:1.0.0", "org/backend:1.0.0"},
+			ContainerImageDigests:  []string{"sha256:aaa", "sha256:bbb"},
+			SigningKey:             "signing-key.pem",
+			AttachToImage:          true,
+		}
+		utils, key := newProvenanceCreateTestsUtils(t)
+		// test
+		err := runProvenanceCreate(&config, utils)
+		// assert
+		require.NoError(t, err)
+		envelope := readProvenanceEnvelope(t, "provenance/org_backend_1.0.0.intoto.jsonl", utils)
+		assert.NoError(t, envelope.Verify(&key.PublicKey))
+		statement, err := envelope.Statement()
+		require.NoError(t, err)
+		assert.Equal(t, []provenance.Subject{{Name: "registry.example.com/org/backend", Digest: map[string]string{"sha256": "bbb"}}}, statement.Subject)
+		assert.True(t, utils.HasWrittenFile("provenance/shop_1.0.0.intoto.jsonl"))
+		assert.Len(t, utils.attached, 2)
+		assert.Equal(t, envelope, *utils.attached["registry.example.com/org/backend@sha256:bbb"])
+	})
+
+	t.Run("images without registry", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := provenanceCreateOptions{
+			ContainerImageNameTags: []string{"shop:1.0.0"},
+			ContainerImageDigests:  []string{"sha256:aaa"},
+			SigningKey:             "signing-key.pem",
+		}
+		utils, _ := newProvenanceCreateTestsUtils(t)
+		// test
+		err := runProvenanceCreate(&config, utils)
+		// assert
+		require.NoError(t, err)
+		envelope := readProvenanceEnvelope(t, "provenance/shop_1.0.0.intoto.jsonl", utils)
+		statement, _ := envelope.Statement()
+		assert.Equal(t, "shop", statement.Subject[0].Name)
+		assert.Empty(t, utils.attached)
+
+		config.AttachToImage = true
+		err = runProvenanceCreate(&config, utils)
+		assert.EqualError(t, err, "containerRegistryUrl is required to attach the provenance to image 'shop:1.0.0'")
+	})
+
+	t.Run("attach fails", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := provenanceCreateOptions{
+			ContainerRegistryURL:   "https://registry.example.com",
+			ContainerImageNameTags: []string{"broken:1.0.0"},
+			ContainerImageDigests:  []string{"sha256:abc"},
+			SigningKey:             "signing-key.pem",
+			AttachToImage:          true,
+		}
+		utils, _ := newProvenanceCreateTestsUtils(t)
+		// test
+		err := runProvenanceCreate(&config, utils)
+		// assert
+		assert.EqualError(t, err, "failed to attach provenance to image 'registry.example.com/broken@sha256:abc': unauthorized")
+	})
+
+	t.Run("digests missing", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := provenanceCreateOptions{
+			ContainerImageNameTags: []string{"shop:1.0.0"},
+			SigningKey:             "signing-key.pem",
+		}
+		utils, _ := newProvenanceCreateTestsUtils(t)
+		// test
+		err := runProvenanceCreate(&config, utils)
+		// assert
+		assert.EqualError(t, err, "number of container images (1) does not match the number of image digests (0)")
+	})
+
+	t.Run("nothing to attest", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := provenanceCreateOptions{
+			ArtifactPatterns: []string{"dist/*.tgz"},
+			SigningKey:       "signing-key.pem",
+		}
+		utils, _ := newProvenanceCreateTestsUtils(t)
+		// test
+		err := runProvenanceCreate(&config, utils)
+		// assert
+		assert.EqualError(t, err, "no artifacts or container images found to create provenance for")
+	})
+
+	t.Run("invalid signing key", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := provenanceCreateOptions{
+			ArtifactPatterns: []string{"target/*.jar"},
+			SigningKey:       "target/shop.jar",
+		}
+		utils, _ := newProvenanceCreateTestsUtils(t)
+		// test
+		err := runProvenanceCreate(&config, utils)
+		// assert
+		assert.EqualError(t, err, "invalid signing key: no PEM encoded private key found")
+	})
+}
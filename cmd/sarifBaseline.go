@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// sarifBaseline compares the SARIF results of a step on pull requests with the SARIF results of the base branch.
+// On other builds, the SARIF results are stored as baseline of the current branch.
+// The baseline is located at <location>/<branch>/<stepName>.sarif, the location is either a directory or a GCS folder (gs://<bucket>/<folder>).
+type sarifBaseline struct {
+	stepName  string
+	location  string
+	provider  orchestrator.OrchestratorSpecificConfigProviding
+	fileUtils piperutils.FileUtils
+	gcsClient func() (gcs.Client, error)
+}
+
+func newSarifBaseline(stepName, location string) *sarifBaseline {
+	provider, err := orchestrator.NewOrchestratorSpecificConfigProvider()
+	if err != nil {
+		log.Entry().WithError(err).Debug("failed to detect orchestrator, SARIF results are stored as baseline")
+	}
+	return &sarifBaseline{
+		stepName:  stepName,
+		location:  location,
+		provider:  provider,
+		fileUtils: &piperutils.Files{},
+		gcsClient: func() (gcs.Client, error) {
+			envVars := []gcs.EnvVar{
+				{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: GeneralConfig.GCPJsonKeyFilePath, Modified: false},
+			}
+			return gcs.NewClient(gcs.WithEnvVars(envVars))
+		},
+	}
+}
+
+// process compares the SARIF file with the baseline on pull requests and stores it as new baseline otherwise.
+// The comparison is returned together with the paths of the differential reports. If no comparison took place, nil is returned.
+func (b *sarifBaseline) process(sarifPath, toolName, reportsDirectory string) (*format.SarifDiff, []piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+	if len(b.location) == 0 {
+		return nil, reportPaths, nil
+	}
+
+	current, err := format.ReadSarif(sarifPath, b.fileUtils)
+	if err != nil {
+		return nil, reportPaths, err
+	}
+
+	if !b.provider.IsPullRequest() {
+		branch := strings.TrimPrefix(b.provider.GetBranch(), "refs/heads/")
+		log.Entry().Infof("Storing SARIF results as baseline of branch %v", branch)
+		return nil, reportPaths, b.store(sarifPath, branch)
+	}
+
+	base := strings.TrimPrefix(b.provider.GetPullRequestConfig().Base, "refs/heads/")
+	baseline, err := b.load(base)
+	if err != nil {
+		log.Entry().WithError(err).Warnf("No SARIF baseline available for branch %v, all findings are considered", base)
+		return nil, reportPaths, nil
+	}
+
+	diff := format.CompareSarif(baseline, current)
+	diff.BaselineName = fmt.Sprintf("branch %v", base)
+	log.Entry().Infof("Compared to branch %v: %v new, %v fixed and %v unchanged findings", base, len(diff.New), len(diff.Fixed), len(diff.Unchanged))
+
+	reportPaths, err = b.writeReports(diff.CreateScanReport(toolName), reportsDirectory)
+	return &diff, reportPaths, err
+}
+
+func (b *sarifBaseline) baselinePath(branch string) string {
+	return path.Join(branch, b.stepName+".sarif")
+}
+
+func (b *sarifBaseline) load(branch string) (*format.SARIF, error) {
+	if bucketID, folder, isGCS := parseGCSLocation(b.location); isGCS {
+		client, err := b.gcsClient()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create GCS client")
+		}
+		defer client.Close()
+		localPath := filepath.Join(".pipeline", "baseline", b.stepName+".sarif")
+		if err := b.fileUtils.MkdirAll(filepath.Dir(localPath), 0777); err != nil {
+			return nil, err
+		}
+		if err := client.DownloadFile(bucketID, path.Join(folder, b.baselinePath(branch)), localPath); err != nil {
+			return nil, errors.Wrap(err, "failed to download SARIF baseline")
+		}
+		return format.ReadSarif(localPath, b.fileUtils)
+	}
+	return format.ReadSarif(filepath.Join(b.location, filepath.FromSlash(b.baselinePath(branch))), b.fileUtils)
+}
+
+func (b *sarifBaseline) store(sarifPath, branch string) error {
+	if bucketID, folder, isGCS := parseGCSLocation(b.location); isGCS {
+		client, err := b.gcsClient()
+		if err != nil {
+			return errors.Wrap(err, "failed to create GCS client")
+		}
+		defer client.Close()
+		if err := client.UploadFile(bucketID, sarifPath, path.Join(folder, b.baselinePath(branch))); err != nil {
+			return errors.Wrap(err, "failed to upload SARIF baseline")
+		}
+		return nil
+	}
+	target := filepath.Join(b.location, filepath.FromSlash(b.baselinePath(branch)))
+	if err := b.fileUtils.MkdirAll(filepath.Dir(target), 0777); err != nil {
+		return errors.Wrap(err, "failed to create baseline directory")
+	}
+	if _, err := b.fileUtils.Copy(sarifPath, target); err != nil {
+		return errors.Wrap(err, "failed to store SARIF baseline")
+	}
+	return nil
+}
+
+func (b *sarifBaseline) writeReports(scanReport reporting.ScanReport, reportsDirectory string) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+
+	// ignore templating errors since template is in our hands and issues will be detected with the automated tests
+	htmlReport, _ := scanReport.ToHTML()
+	if err := b.fileUtils.MkdirAll(reportsDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to create report directory")
+	}
+	htmlReportPath := filepath.Join(reportsDirectory, "piper_differential_report.html")
+	if err := b.fileUtils.FileWrite(htmlReportPath, htmlReport, 0666); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to write differential html report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Differential Scan Report", Target: htmlReportPath})
+
+	// JSON reports are used by step pipelineCreateSummary in order to e.g. prepare an issue creation in GitHub
+	// ignore JSON errors since structure is in our hands
+	jsonReport, _ := scanReport.ToJSON()
+	if err := b.fileUtils.MkdirAll(reporting.StepReportDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to create step reporting directory")
+	}
+	if err := b.fileUtils.FileWrite(filepath.Join(reporting.StepReportDirectory, b.stepName+"_differential.json"), jsonReport, 0666); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to write differential json report")
+	}
+	return reportPaths, nil
+}
+
+// parseGCSLocation splits a location gs://<bucket>/<folder> into bucket and folder
+func parseGCSLocation(location string) (string, string, bool) {
+	if !strings.HasPrefix(location, "gs://") {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(location, "gs://"), "/", 2)
+	if len(parts) == 1 {
+		return parts[0], "", true
+	}
+	return parts[0], strings.Trim(parts[1], "/"), true
+}
+
+// resultsForNewFindings creates the Checkmarx result counts of the new findings in order to enforce the thresholds only on them
+func resultsForNewFindings(results map[string]interface{}, newFindings []format.Results) map[string]interface{} {
+	newResults := map[string]interface{}{}
+	for key, value := range results {
+		newResults[key] = value
+	}
+	for _, severity := range []string{"High", "Medium", "Low", "Information"} {
+		newResults[severity] = map[string]int{}
+	}
+	lowPerQuery := map[string]map[string]int{}
+	for _, finding := range newFindings {
+		if finding.Properties == nil {
+			continue
+		}
+		severity := checkmarxResultKey(finding.Properties.ToolSeverity)
+		state := checkmarxResultKey(finding.Properties.ToolState)
+		counts, ok := newResults[severity].(map[string]int)
+		if !ok {
+			counts = map[string]int{}
+			newResults[severity] = counts
+		}
+		countFinding(counts, state)
+		if severity == "Low" {
+			if lowPerQuery[finding.RuleID] == nil {
+				lowPerQuery[finding.RuleID] = map[string]int{}
+			}
+			countFinding(lowPerQuery[finding.RuleID], state)
+		}
+	}
+	if _, ok := results["LowPerQuery"]; ok {
+		newResults["LowPerQuery"] = lowPerQuery
+	}
+	return newResults
+}
+
+// checkmarxResultKey converts the upper case values of Checkmarx One (e.g. TO_VERIFY) into the keys used by Checkmarx (e.g. ToVerify)
+func checkmarxResultKey(value string) string {
+	if strings.ToUpper(value) != value {
+		return value
+	}
+	return strings.ReplaceAll(piperutils.Title(strings.ToLower(strings.ReplaceAll(value, "_", " "))), " ", "")
+}
+
+func countFinding(counts map[string]int, state string) {
+	counts["Issues"]++
+	counts[state]++
+	if state != "NotExploitable" {
+		counts["NotFalsePositive"]++
+	}
+}
@@ -0,0 +1,157 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	gcsMocks "github.com/SAP/jenkins-library/pkg/gcs/mocks"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+type sarifBaselineOrchestratorMock struct {
+	orchestrator.UnknownOrchestratorConfigProvider
+	pullRequest bool
+}
+
+func (o *sarifBaselineOrchestratorMock) IsPullRequest() bool {
+	return o.pullRequest
+}
+
+func (o *sarifBaselineOrchestratorMock) GetBranch() string {
+	return "feature"
+}
+
+func (o *sarifBaselineOrchestratorMock) GetPullRequestConfig() orchestrator.PullRequestConfig {
+	return orchestrator.PullRequestConfig{Branch: "feature", Base: "main", Key: "42"}
+}
+
+func sarifBaselineTestFile(results ...format.Results) []byte {
+	sarif := format.SARIF{Version: "2.1.0", Runs: []format.Runs{{Results: results}}}
+	content, _ := json.Marshal(sarif)
+	return content
+}
+
+func TestSarifBaselineProcess(t *testing.T) {
+	t.Parallel()
+
+	unchanged := format.Results{RuleID: "rule1", PartialFingerprints: format.PartialFingerprints{CheckmarxSimilarityID: "1"}}
+	fixed := format.Results{RuleID: "rule1", PartialFingerprints: format.PartialFingerprints{CheckmarxSimilarityID: "2"}}
+	newFinding := format.Results{RuleID: "rule2", Locations: []format.Location{{PhysicalLocation: format.PhysicalLocation{ArtifactLocation: format.ArtifactLocation{URI: "src/main.go"}, Region: format.Region{StartLine: 12}}}}}
+
+	t.Run("pull request compared with baseline", func(t *testing.T) {
+		t.Parallel()
+		// init
+		utils := mock.FilesMock{}
+		utils.AddFile("result.sarif", sarifBaselineTestFile(unchanged, newFinding))
+		utils.AddFile(filepath.Join("baselines", "main", "checkmarxExecuteScan.sarif"), sarifBaselineTestFile(unchanged, fixed))
+		baseline := sarifBaseline{stepName: "checkmarxExecuteScan", location: "baselines", provider: &sarifBaselineOrchestratorMock{pullRequest: true}, fileUtils: &utils}
+		// test
+		diff, paths, err := baseline.process("result.sarif", "Checkmarx", "checkmarx")
+		// assert
+		assert.NoError(t, err)
+		if assert.NotNil(t, diff) {
+			assert.Equal(t, []format.Results{newFinding}, diff.New)
+			assert.Equal(t, []format.Results{fixed}, diff.Fixed)
+			assert.Equal(t, []format.Results{unchanged}, diff.Unchanged)
+			assert.Equal(t, "branch main", diff.BaselineName)
+		}
+		assert.Len(t, paths, 1)
+		assert.True(t, utils.HasWrittenFile(filepath.Join("checkmarx", "piper_differential_report.html")))
+		assert.True(t, utils.HasWrittenFile(filepath.Join(reporting.StepReportDirectory, "checkmarxExecuteScan_differential.json")))
+	})
+
+	t.Run("pull request without baseline", func(t *testing.T) {
+		t.Parallel()
+		// init
+		utils := mock.FilesMock{}
+		utils.AddFile("result.sarif", sarifBaselineTestFile(unchanged))
+		baseline := sarifBaseline{stepName: "checkmarxExecuteScan", location: "baselines", provider: &sarifBaselineOrchestratorMock{pullRequest: true}, fileUtils: &utils}
+		// test
+		diff, paths, err := baseline.process("result.sarif", "Checkmarx", "checkmarx")
+		// assert
+		assert.NoError(t, err)
+		assert.Nil(t, diff)
+		assert.Empty(t, paths)
+	})
+
+	t.Run("branch build stores baseline", func(t *testing.T) {
+		t.Parallel()
+		// init
+		utils := mock.FilesMock{}
+		utils.AddFile("result.sarif", sarifBaselineTestFile(unchanged))
+		baseline := sarifBaseline{stepName: "checkmarxExecuteScan", location: "baselines", provider: &sarifBaselineOrchestratorMock{}, fileUtils: &utils}
+		// test
+		diff, _, err := baseline.process("result.sarif", "Checkmarx", "checkmarx")
+		// assert
+		assert.NoError(t, err)
+		assert.Nil(t, diff)
+		assert.True(t, utils.HasFile(filepath.Join("baselines", "feature", "checkmarxExecuteScan.sarif")))
+	})
+
+	t.Run("branch build stores baseline in GCS", func(t *testing.T) {
+		t.Parallel()
+		// init
+		utils := mock.FilesMock{}
+		utils.AddFile("result.sarif", sarifBaselineTestFile(unchanged))
+		gcsClient := &gcsMocks.Client{}
+		gcsClient.On("UploadFile", "my-bucket", "result.sarif", "baselines/feature/checkmarxExecuteScan.sarif").Return(nil)
+		gcsClient.On("Close").Return(nil)
+		baseline := sarifBaseline{
+			stepName:  "checkmarxExecuteScan",
+			location:  "gs://my-bucket/baselines/",
+			provider:  &sarifBaselineOrchestratorMock{},
+			fileUtils: &utils,
+			gcsClient: func() (gcs.Client, error) { return gcsClient, nil },
+		}
+		// test
+		_, _, err := baseline.process("result.sarif", "Checkmarx", "checkmarx")
+		// assert
+		assert.NoError(t, err)
+		gcsClient.AssertExpectations(t)
+	})
+
+	t.Run("no baseline location", func(t *testing.T) {
+		t.Parallel()
+		// init
+		baseline := sarifBaseline{stepName: "checkmarxExecuteScan", provider: &sarifBaselineOrchestratorMock{pullRequest: true}, fileUtils: &mock.FilesMock{}}
+		// test
+		diff, paths, err := baseline.process("result.sarif", "Checkmarx", "checkmarx")
+		// assert
+		assert.NoError(t, err)
+		assert.Nil(t, diff)
+		assert.Empty(t, paths)
+	})
+}
+
+func TestResultsForNewFindings(t *testing.T) {
+	t.Parallel()
+	results := map[string]interface{}{
+		"DeepLink":    "https://cx.server/deeplink",
+		"High":        map[string]int{"Issues": 10, "NotFalsePositive": 10},
+		"LowPerQuery": map[string]map[string]int{},
+	}
+	newFindings := []format.Results{
+		{RuleID: "query1", Properties: &format.SarifProperties{ToolSeverity: "High", ToolState: "ToVerify"}},
+		{RuleID: "query2", Properties: &format.SarifProperties{ToolSeverity: "LOW", ToolState: "NOT_EXPLOITABLE"}},
+	}
+
+	newResults := resultsForNewFindings(results, newFindings)
+
+	assert.Equal(t, "https://cx.server/deeplink", newResults["DeepLink"])
+	assert.Equal(t, map[string]int{"Issues": 1, "ToVerify": 1, "NotFalsePositive": 1}, newResults["High"])
+	assert.Equal(t, map[string]int{}, newResults["Medium"])
+	assert.Equal(t, map[string]int{"Issues": 1, "NotExploitable": 1}, newResults["Low"])
+	assert.Equal(t, map[string]map[string]int{"query2": {"Issues": 1, "NotExploitable": 1}}, newResults["LowPerQuery"])
+	// original results are not modified
+	assert.Equal(t, map[string]int{"Issues": 10, "NotFalsePositive": 10}, results["High"])
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/osv"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/sbom"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+type sbomExecuteVulnerabilityScanUtils interface {
+	piperutils.FileUtils
+}
+
+type sbomExecuteVulnerabilityScanUtilsBundle struct {
+	*piperutils.Files
+}
+
+func newSbomExecuteVulnerabilityScanUtils() sbomExecuteVulnerabilityScanUtils {
+	utils := sbomExecuteVulnerabilityScanUtilsBundle{
+		Files: &piperutils.Files{},
+	}
+	return &utils
+}
+
+func sbomExecuteVulnerabilityScan(config sbomExecuteVulnerabilityScanOptions, _ *telemetry.CustomData) {
+	utils := newSbomExecuteVulnerabilityScanUtils()
+
+	err := runSbomExecuteVulnerabilityScan(&config, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runSbomExecuteVulnerabilityScan(config *sbomExecuteVulnerabilityScanOptions, utils sbomExecuteVulnerabilityScanUtils) error {
+	cvssSeverityLimit, err := strconv.ParseFloat(config.CvssSeverityLimit, 64)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrapf(err, "failed to parse parameter cvssSeverityLimit (%s) as floating point number", config.CvssSeverityLimit)
+	}
+
+	bomFiles, err := findSBOMFiles(config.BomFilePattern, utils)
+	if err != nil {
+		return err
+	}
+	if len(bomFiles) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("no SBOM found for patterns %v", config.BomFilePattern)
+	}
+
+	db, err := osv.LoadDatabase(config.VulnerabilityDatabasePath, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "failed to load vulnerability database")
+	}
+
+	findings := []osv.Finding{}
+	for _, bomFile := range bomFiles {
+		bom, err := sbom.ReadBOM(bomFile, utils)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return err
+		}
+		bomFindings := db.ScanBOM(bom, bomFile)
+		log.Entry().Infof("%v vulnerabilities detected in SBOM %v", len(bomFindings), bomFile)
+		findings = append(findings, bomFindings...)
+	}
+
+	assessments := readAssessmentsFromFile(config.AssessmentFile, utils)
+	findings, assessedFindings := filterAssessedFindings(findings, assessments)
+
+	reportPaths, err := writeSbomVulnerabilityReports(config, bomFiles, findings, assessedFindings, cvssSeverityLimit, utils)
+	piperutils.PersistReportsAndLinks("sbomExecuteVulnerabilityScan", "", utils, reportPaths, nil)
+	if err != nil {
+		return err
+	}
+
+	severeVulnerabilities, nonSevereVulnerabilities := osv.CountSecurityVulnerabilities(findings, cvssSeverityLimit)
+	if nonSevereVulnerabilities > 0 {
+		log.Entry().Warnf("WARNING: %v Open Source Software Security vulnerabilities with CVSS score below threshold %.1f detected.", nonSevereVulnerabilities, cvssSeverityLimit)
+	} else if len(findings) == 0 {
+		log.Entry().Info("No Open Source Software Security vulnerabilities detected")
+	}
+	if severeVulnerabilities > 0 {
+		if config.FailOnSevereVulnerabilities {
+			log.SetErrorCategory(log.ErrorCompliance)
+			return fmt.Errorf("%v Open Source Software Security vulnerabilities with CVSS score greater or equal to %.1f detected", severeVulnerabilities, cvssSeverityLimit)
+		}
+		log.Entry().Infof("%v Open Source Software Security vulnerabilities with CVSS score greater or equal to %.1f detected", severeVulnerabilities, cvssSeverityLimit)
+		log.Entry().Info("Step will only create data but not fail due to setting failOnSevereVulnerabilities: false")
+	}
+	return nil
+}
+
+func findSBOMFiles(patterns []string, utils sbomExecuteVulnerabilityScanUtils) ([]string, error) {
+	bomFiles := []string{}
+	for _, pattern := range patterns {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "failed to find SBOMs for pattern '%v'", pattern)
+		}
+		for _, match := range matches {
+			if !piperutils.ContainsString(bomFiles, match) {
+				bomFiles = append(bomFiles, match)
+			}
+		}
+	}
+	return bomFiles, nil
+}
+
+// filterAssessedFindings separates the findings covered by an assessment from the ones which still need to be considered
+func filterAssessedFindings(findings []osv.Finding, assessments *[]format.Assessment) ([]osv.Finding, []osv.Finding) {
+	if assessments == nil || len(*assessments) == 0 {
+		return findings, []osv.Finding{}
+	}
+	filteredFindings := []osv.Finding{}
+	assessedFindings := []osv.Finding{}
+	for _, finding := range findings {
+		if contained, err := finding.ContainedIn(assessments); err == nil && contained {
+			log.Entry().Debugf("Matched assessment with status %v and analysis %v to vulnerability %v affecting package %v", finding.Assessment.Status, finding.Assessment.Analysis, finding.Vulnerability.ID, finding.Purl.ToString())
+			assessedFindings = append(assessedFindings, finding)
+		} else {
+			filteredFindings = append(filteredFindings, finding)
+		}
+	}
+	return filteredFindings, assessedFindings
+}
+
+func writeSbomVulnerabilityReports(config *sbomExecuteVulnerabilityScanOptions, bomFiles []string, findings, assessedFindings []osv.Finding, cvssSeverityLimit float64, utils sbomExecuteVulnerabilityScanUtils) ([]piperutils.Path, error) {
+	scanReport := osv.CreateCustomVulnerabilityReport(config.VulnerabilityDatabasePath, bomFiles, findings, assessedFindings, cvssSeverityLimit)
+	reportPaths, err := osv.WriteCustomVulnerabilityReports(scanReport, bomFiles, utils)
+	if err != nil {
+		return reportPaths, errors.Wrap(err, "failed to write vulnerability report")
+	}
+
+	// assessed findings are part of the SARIF file including their audit state
+	sarif := osv.CreateSarifResultFile(append(append([]osv.Finding{}, findings...), assessedFindings...))
+	sarifPaths, err := osv.WriteSarifFile(sarif, utils)
+	reportPaths = append(reportPaths, sarifPaths...)
+	if err != nil {
+		return reportPaths, errors.Wrap(err, "failed to write SARIF file")
+	}
+
+	vexPaths, err := format.WriteVEX(osv.ReportsDirectory, "SBOM vulnerability scan", osv.CreateVEXStatements(assessedFindings), utils)
+	reportPaths = append(reportPaths, vexPaths...)
+	if err != nil {
+		return reportPaths, err
+	}
+	return reportPaths, nil
+}
@@ -0,0 +1,266 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/cobra"
+)
+
+type sbomExecuteVulnerabilityScanOptions struct {
+	BomFilePattern              []string `json:"bomFilePattern,omitempty"`
+	VulnerabilityDatabasePath   string   `json:"vulnerabilityDatabasePath,omitempty"`
+	AssessmentFile              string   `json:"assessmentFile,omitempty"`
+	CvssSeverityLimit           string   `json:"cvssSeverityLimit,omitempty"`
+	FailOnSevereVulnerabilities bool     `json:"failOnSevereVulnerabilities,omitempty"`
+}
+
+type sbomExecuteVulnerabilityScanReports struct {
+}
+
+func (p *sbomExecuteVulnerabilityScanReports) persist(stepConfig sbomExecuteVulnerabilityScanOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "**/piper_sbom_vulnerability_report.html", ParamRef: "", StepResultType: "sbom-vulnerability"},
+		{FilePattern: "**/piper_sbom_vulnerability.sarif", ParamRef: "", StepResultType: "sbom-vulnerability"},
+		{FilePattern: "**/sbomExecuteVulnerabilityScan_oss_*.json", ParamRef: "", StepResultType: "sbom-vulnerability"},
+		{FilePattern: "**/sbomScan/piper_vex.json", ParamRef: "", StepResultType: "sbom-vulnerability"},
+	}
+	envVars := []gcs.EnvVar{
+		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
+	}
+	gcsClient, err := gcs.NewClient(gcs.WithEnvVars(envVars))
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// SbomExecuteVulnerabilityScanCommand Scans CycloneDX SBOMs for known vulnerabilities using a local OSV database export.
+func SbomExecuteVulnerabilityScanCommand() *cobra.Command {
+	const STEP_NAME = "sbomExecuteVulnerabilityScan"
+
+	metadata := sbomExecuteVulnerabilityScanMetadata()
+	var stepConfig sbomExecuteVulnerabilityScanOptions
+	var startTime time.Time
+	var reports sbomExecuteVulnerabilityScanReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createSbomExecuteVulnerabilityScanCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Scans CycloneDX SBOMs for known vulnerabilities using a local OSV database export.",
+		Long: `Scans the CycloneDX SBOMs created during the build (e.g. by ` + "`" + `mavenBuild` + "`" + `, ` + "`" + `npmExecuteScripts` + "`" + `, ` + "`" + `golangBuild` + "`" + `, ` + "`" + `pythonBuild` + "`" + ` or ` + "`" + `kanikoExecute` + "`" + `) for publicly known vulnerabilities.
+
+The components of the SBOMs are matched via their package URL (purl) against a locally mirrored database in [OSV format](https://ossf.github.io/osv-schema/),
+for example an export of [osv.dev](https://osv.dev) (` + "`" + `gs://osv-vulnerabilities/<ecosystem>/all.zip` + "`" + `) or of the [GitHub Advisory Database](https://github.com/github/advisory-database).
+No connection to a vulnerability server is required, which allows scanning in air-gapped environments.
+
+Vulnerabilities can be triaged via an assessment file, using the same format as ` + "`" + `whitesourceExecuteScan` + "`" + `, ` + "`" + `detectExecuteScan` + "`" + ` and ` + "`" + `protecodeExecuteScan` + "`" + `. Assessed vulnerabilities are written as CycloneDX VEX.
+The step creates a SARIF file as well as an HTML and a JSON report and fails if unassessed vulnerabilities with a CVSS v3 score greater or equal to ` + "`" + `cvssSeverityLimit` + "`" + ` are detected.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.Send()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+			}, func() {
+				sbomExecuteVulnerabilityScan(stepConfig, &stepTelemetryData)
+			})
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addSbomExecuteVulnerabilityScanFlags(createSbomExecuteVulnerabilityScanCmd, &stepConfig)
+	return createSbomExecuteVulnerabilityScanCmd
+}
+
+func addSbomExecuteVulnerabilityScanFlags(cmd *cobra.Command, stepConfig *sbomExecuteVulnerabilityScanOptions) {
+	cmd.Flags().StringSliceVar(&stepConfig.BomFilePattern, "bomFilePattern", []string{`**/bom-*.xml`, `**/bom-*.json`}, "List of file patterns of the CycloneDX SBOMs to scan. XML and JSON format are supported.")
+	cmd.Flags().StringVar(&stepConfig.VulnerabilityDatabasePath, "vulnerabilityDatabasePath", os.Getenv("PIPER_vulnerabilityDatabasePath"), "Path to the local OSV database export, either a directory containing the JSON records or a zip archive.")
+	cmd.Flags().StringVar(&stepConfig.AssessmentFile, "assessmentFile", `hs-assessments.yaml`, "Explicit path to the assessment YAML file.")
+	cmd.Flags().StringVar(&stepConfig.CvssSeverityLimit, "cvssSeverityLimit", `7.0`, "Limit of tolerable CVSS v3 score upon assessment and in consequence fails the build. A negative value disables the limit.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnSevereVulnerabilities, "failOnSevereVulnerabilities", true, "Whether to fail the step on severe vulnerabilties or not")
+
+	cmd.MarkFlagRequired("vulnerabilityDatabasePath")
+}
+
+// retrieve step metadata
+func sbomExecuteVulnerabilityScanMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "sbomExecuteVulnerabilityScan",
+			Aliases:     []config.Alias{},
+			Description: "Scans CycloneDX SBOMs for known vulnerabilities using a local OSV database export.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "bomFilePattern",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`**/bom-*.xml`, `**/bom-*.json`},
+					},
+					{
+						Name:        "vulnerabilityDatabasePath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_vulnerabilityDatabasePath"),
+					},
+					{
+						Name:        "assessmentFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `hs-assessments.yaml`,
+					},
+					{
+						Name:        "cvssSeverityLimit",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `7.0`,
+					},
+					{
+						Name:        "failOnSevereVulnerabilities",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "**/piper_sbom_vulnerability_report.html", "type": "sbom-vulnerability"},
+							{"filePattern": "**/piper_sbom_vulnerability.sarif", "type": "sbom-vulnerability"},
+							{"filePattern": "**/sbomExecuteVulnerabilityScan_oss_*.json", "type": "sbom-vulnerability"},
+							{"filePattern": "**/sbomScan/piper_vex.json", "type": "sbom-vulnerability"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,127 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/osv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sbomExecuteVulnerabilityScanMockUtils struct {
+	*mock.FilesMock
+}
+
+func newSbomExecuteVulnerabilityScanTestsUtils(t *testing.T) sbomExecuteVulnerabilityScanMockUtils {
+	utils := sbomExecuteVulnerabilityScanMockUtils{
+		FilesMock: &mock.FilesMock{},
+	}
+	record, err := os.ReadFile(filepath.Join("..", "pkg", "osv", "testdata", "GHSA-test-lodash.json"))
+	require.NoError(t, err)
+	bom, err := os.ReadFile(filepath.Join("..", "pkg", "osv", "testdata", "bom-npm.json"))
+	require.NoError(t, err)
+	utils.AddFile("osv/npm/GHSA-35jh-r3h4-6jhm.json", record)
+	utils.AddFile("bom-npm.json", bom)
+	return utils
+}
+
+func TestRunSbomExecuteVulnerabilityScan(t *testing.T) {
+	t.Parallel()
+
+	config := sbomExecuteVulnerabilityScanOptions{
+		BomFilePattern:              []string{"**/bom-*.xml", "**/bom-*.json"},
+		VulnerabilityDatabasePath:   "osv",
+		AssessmentFile:              "hs-assessments.yaml",
+		CvssSeverityLimit:           "7.0",
+		FailOnSevereVulnerabilities: true,
+	}
+
+	t.Run("error case - severe vulnerability", func(t *testing.T) {
+		t.Parallel()
+		utils := newSbomExecuteVulnerabilityScanTestsUtils(t)
+
+		err := runSbomExecuteVulnerabilityScan(&config, utils)
+
+		assert.EqualError(t, err, "1 Open Source Software Security vulnerabilities with CVSS score greater or equal to 7.0 detected")
+		assert.True(t, utils.HasWrittenFile(filepath.Join(osv.ReportsDirectory, "piper_sbom_vulnerability.sarif")))
+		assert.True(t, utils.HasWrittenFile(filepath.Join(osv.ReportsDirectory, "piper_sbom_vulnerability_report.html")))
+	})
+
+	t.Run("success case - vulnerability assessed", func(t *testing.T) {
+		t.Parallel()
+		utils := newSbomExecuteVulnerabilityScanTestsUtils(t)
+		utils.AddFile("hs-assessments.yaml", []byte(`ignore:
+  - vulnerability: CVE-2021-23337
+    status: notRelevant
+    analysis: notUsed
+    purls:
+      - purl: pkg:npm/lodash@4.17.20
+`))
+
+		err := runSbomExecuteVulnerabilityScan(&config, utils)
+
+		assert.NoError(t, err)
+		sarif, err := utils.FileRead(filepath.Join(osv.ReportsDirectory, "piper_sbom_vulnerability.sarif"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(sarif), `"unifiedAuditState":"notRelevant"`)
+	})
+
+	t.Run("success case - below limit", func(t *testing.T) {
+		t.Parallel()
+		utils := newSbomExecuteVulnerabilityScanTestsUtils(t)
+		belowLimitConfig := config
+		belowLimitConfig.CvssSeverityLimit = "9.0"
+
+		err := runSbomExecuteVulnerabilityScan(&belowLimitConfig, utils)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("success case - do not fail on severe vulnerabilities", func(t *testing.T) {
+		t.Parallel()
+		utils := newSbomExecuteVulnerabilityScanTestsUtils(t)
+		noFailConfig := config
+		noFailConfig.FailOnSevereVulnerabilities = false
+
+		err := runSbomExecuteVulnerabilityScan(&noFailConfig, utils)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("error case - no SBOM", func(t *testing.T) {
+		t.Parallel()
+		utils := sbomExecuteVulnerabilityScanMockUtils{FilesMock: &mock.FilesMock{}}
+
+		err := runSbomExecuteVulnerabilityScan(&config, utils)
+
+		assert.EqualError(t, err, "no SBOM found for patterns [**/bom-*.xml **/bom-*.json]")
+	})
+
+	t.Run("error case - invalid limit", func(t *testing.T) {
+		t.Parallel()
+		utils := newSbomExecuteVulnerabilityScanTestsUtils(t)
+		invalidConfig := config
+		invalidConfig.CvssSeverityLimit = "high"
+
+		err := runSbomExecuteVulnerabilityScan(&invalidConfig, utils)
+
+		assert.Contains(t, err.Error(), "failed to parse parameter cvssSeverityLimit (high)")
+	})
+
+	t.Run("error case - missing database", func(t *testing.T) {
+		t.Parallel()
+		utils := newSbomExecuteVulnerabilityScanTestsUtils(t)
+		missingConfig := config
+		missingConfig.VulnerabilityDatabasePath = "missing.zip"
+
+		err := runSbomExecuteVulnerabilityScan(&missingConfig, utils)
+
+		assert.Contains(t, err.Error(), "failed to load vulnerability database")
+	})
+}
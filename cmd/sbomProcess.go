@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/osv"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/sbom"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+type sbomProcessUtils interface {
+	piperutils.FileUtils
+}
+
+type sbomProcessUtilsBundle struct {
+	*piperutils.Files
+}
+
+func newSbomProcessUtils() sbomProcessUtils {
+	utils := sbomProcessUtilsBundle{
+		Files: &piperutils.Files{},
+	}
+	return &utils
+}
+
+func sbomProcess(config sbomProcessOptions, _ *telemetry.CustomData) {
+	utils := newSbomProcessUtils()
+
+	err := runSbomProcess(&config, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runSbomProcess(config *sbomProcessOptions, utils sbomProcessUtils) error {
+	boms, err := readSbomProcessInputs(config, utils)
+	if err != nil {
+		return err
+	}
+
+	var application *cdx.Component
+	if len(config.ApplicationName) > 0 {
+		application = &cdx.Component{
+			Type:    cdx.ComponentTypeApplication,
+			Name:    config.ApplicationName,
+			Version: config.ApplicationVersion,
+		}
+	}
+	merged := sbom.Merge(boms, application)
+	log.Entry().Infof("%v SBOMs merged into %v containing %v components", len(boms), config.BomFile, len(*merged.Components))
+
+	reportPaths, err := writeSbomProcessResults(config, merged, utils)
+	piperutils.PersistReportsAndLinks("sbomProcess", "", utils, reportPaths, nil)
+	return err
+}
+
+func writeSbomProcessResults(config *sbomProcessOptions, merged *cdx.BOM, utils sbomProcessUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+	if err := sbom.WriteBOM(merged, config.BomFile, utils); err != nil {
+		return reportPaths, err
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Target: config.BomFile})
+
+	if len(config.SpdxFile) > 0 {
+		if err := sbom.WriteSPDX(sbom.ToSPDX(merged, "Project Piper"), config.SpdxFile, utils); err != nil {
+			return reportPaths, err
+		}
+		log.Entry().Infof("SPDX document written to %v", config.SpdxFile)
+		reportPaths = append(reportPaths, piperutils.Path{Target: config.SpdxFile})
+	}
+
+	if len(config.PreviousBomFile) > 0 {
+		previous, err := readSbomProcessInput(config.PreviousBomFile, utils)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return reportPaths, err
+		}
+		diff := sbom.Diff(previous, merged, osv.CompareVersions)
+		log.Entry().Infof("Compared to %v: %v components added, %v removed, %v upgraded, %v downgraded, %v with changed licenses",
+			config.PreviousBomFile, len(diff.Added), len(diff.Removed), len(diff.Upgraded), len(diff.Downgraded), len(diff.LicenseChanges))
+		diffPaths, err := sbom.WriteDiffReports(sbom.CreateDiffReport(config.PreviousBomFile, config.BomFile, diff), diff, utils)
+		reportPaths = append(reportPaths, diffPaths...)
+		if err != nil {
+			return reportPaths, err
+		}
+	}
+	return reportPaths, nil
+}
+
+// readSbomProcessInputs reads the CycloneDX SBOMs and SPDX documents to merge, the output files of the step are not considered
+func readSbomProcessInputs(config *sbomProcessOptions, utils sbomProcessUtils) ([]*cdx.BOM, error) {
+	patterns := append(append([]string{}, config.BomFilePattern...), config.SpdxFilePattern...)
+	files := []string{}
+	for _, pattern := range patterns {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "failed to find SBOMs for pattern '%v'", pattern)
+		}
+		for _, match := range matches {
+			if piperutils.ContainsString(files, match) || isSbomProcessOutput(config, match) {
+				continue
+			}
+			files = append(files, match)
+		}
+	}
+	if len(files) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("no SBOM found for patterns %v", patterns)
+	}
+
+	boms := []*cdx.BOM{}
+	for _, file := range files {
+		log.Entry().Infof("Reading SBOM %v", file)
+		bom, err := readSbomProcessInput(file, utils)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, err
+		}
+		boms = append(boms, bom)
+	}
+	return boms, nil
+}
+
+func readSbomProcessInput(path string, utils sbomProcessUtils) (*cdx.BOM, error) {
+	if sbom.IsSPDXFile(path) {
+		document, err := sbom.ReadSPDX(path, utils)
+		if err != nil {
+			return nil, err
+		}
+		return sbom.FromSPDX(document), nil
+	}
+	return sbom.ReadBOM(path, utils)
+}
+
+func isSbomProcessOutput(config *sbomProcessOptions, path string) bool {
+	path = filepath.Clean(path)
+	return path == filepath.Clean(config.BomFile) || (len(config.SpdxFile) > 0 && path == filepath.Clean(config.SpdxFile))
+}
@@ -0,0 +1,292 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/cobra"
+)
+
+type sbomProcessOptions struct {
+	BomFilePattern     []string `json:"bomFilePattern,omitempty"`
+	SpdxFilePattern    []string `json:"spdxFilePattern,omitempty"`
+	ApplicationName    string   `json:"applicationName,omitempty"`
+	ApplicationVersion string   `json:"applicationVersion,omitempty"`
+	BomFile            string   `json:"bomFile,omitempty"`
+	SpdxFile           string   `json:"spdxFile,omitempty"`
+	PreviousBomFile    string   `json:"previousBomFile,omitempty"`
+}
+
+type sbomProcessReports struct {
+}
+
+func (p *sbomProcessReports) persist(stepConfig sbomProcessOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "sbom/application.*", ParamRef: "", StepResultType: "sbom"},
+		{FilePattern: "**/piper_sbom_diff_report.html", ParamRef: "", StepResultType: "sbom"},
+		{FilePattern: "**/sbom_diff.json", ParamRef: "", StepResultType: "sbom"},
+	}
+	envVars := []gcs.EnvVar{
+		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
+	}
+	gcsClient, err := gcs.NewClient(gcs.WithEnvVars(envVars))
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// SbomProcessCommand Merges the CycloneDX SBOMs of the build into one application SBOM, converts it to SPDX and compares it with a previous version.
+func SbomProcessCommand() *cobra.Command {
+	const STEP_NAME = "sbomProcess"
+
+	metadata := sbomProcessMetadata()
+	var stepConfig sbomProcessOptions
+	var startTime time.Time
+	var reports sbomProcessReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createSbomProcessCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Merges the CycloneDX SBOMs of the build into one application SBOM, converts it to SPDX and compares it with a previous version.",
+		Long: `Build steps like ` + "`" + `mavenBuild` + "`" + `, ` + "`" + `npmExecuteScripts` + "`" + `, ` + "`" + `golangBuild` + "`" + `, ` + "`" + `pythonBuild` + "`" + ` or ` + "`" + `kanikoExecute` + "`" + ` create one CycloneDX SBOM per build tool or module.
+This step combines these SBOMs into one SBOM describing the complete application:
+
+* Components contained in multiple SBOMs are de-duplicated by their package URL (purl), dependencies of the duplicates are combined.
+* The components described by the single SBOMs (e.g. the modules) become dependencies of the application.
+
+The merged SBOM is written in CycloneDX format and optionally converted into an [SPDX 2.3](https://spdx.github.io/spdx-spec/v2.3/) document in JSON format.
+SPDX documents can also be used as input, they are converted into CycloneDX before merging.
+
+If the SBOM of a previous version (e.g. of the last release) is provided, the step creates a report listing added, removed, upgraded and downgraded components as well as license changes.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.Send()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+			}, func() {
+				sbomProcess(stepConfig, &stepTelemetryData)
+			})
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addSbomProcessFlags(createSbomProcessCmd, &stepConfig)
+	return createSbomProcessCmd
+}
+
+func addSbomProcessFlags(cmd *cobra.Command, stepConfig *sbomProcessOptions) {
+	cmd.Flags().StringSliceVar(&stepConfig.BomFilePattern, "bomFilePattern", []string{`**/bom-*.xml`, `**/bom-*.json`}, "List of file patterns of the CycloneDX SBOMs to merge. XML and JSON format are supported.")
+	cmd.Flags().StringSliceVar(&stepConfig.SpdxFilePattern, "spdxFilePattern", []string{}, "List of file patterns of SPDX documents in JSON format to merge.")
+	cmd.Flags().StringVar(&stepConfig.ApplicationName, "applicationName", os.Getenv("PIPER_applicationName"), "Name of the application described by the merged SBOM.")
+	cmd.Flags().StringVar(&stepConfig.ApplicationVersion, "applicationVersion", os.Getenv("PIPER_applicationVersion"), "Version of the application described by the merged SBOM.")
+	cmd.Flags().StringVar(&stepConfig.BomFile, "bomFile", `sbom/application.cdx.json`, "Path of the merged CycloneDX SBOM. The format (XML or JSON) is determined by the file extension.")
+	cmd.Flags().StringVar(&stepConfig.SpdxFile, "spdxFile", `sbom/application.spdx.json`, "Path of the SPDX document created from the merged SBOM. No SPDX document is created if the parameter is empty.")
+	cmd.Flags().StringVar(&stepConfig.PreviousBomFile, "previousBomFile", os.Getenv("PIPER_previousBomFile"), "Path to the SBOM of a previous version of the application in CycloneDX or SPDX (`*.spdx.json`) format. If set, the differences to the merged SBOM are reported.")
+
+}
+
+// retrieve step metadata
+func sbomProcessMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "sbomProcess",
+			Aliases:     []config.Alias{},
+			Description: "Merges the CycloneDX SBOMs of the build into one application SBOM, converts it to SPDX and compares it with a previous version.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "bomFilePattern",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`**/bom-*.xml`, `**/bom-*.json`},
+					},
+					{
+						Name:        "spdxFilePattern",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "applicationName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_applicationName"),
+					},
+					{
+						Name: "applicationVersion",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "artifactVersion",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_applicationVersion"),
+					},
+					{
+						Name:        "bomFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `sbom/application.cdx.json`,
+					},
+					{
+						Name:        "spdxFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `sbom/application.spdx.json`,
+					},
+					{
+						Name:        "previousBomFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_previousBomFile"),
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "sbom/application.*", "type": "sbom"},
+							{"filePattern": "**/piper_sbom_diff_report.html", "type": "sbom"},
+							{"filePattern": "**/sbom_diff.json", "type": "sbom"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,158 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/sbom"
+)
+
+type sbomProcessMockUtils struct {
+	*mock.FilesMock
+}
+
+const sbomProcessTestFrontendBOM = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "metadata": {"component": {"bom-ref": "frontend", "type": "application", "name": "frontend", "version": "1.0.0"}},
+  "components": [
+    {"bom-ref": "pkg:npm/lodash@4.17.21", "type": "library", "name": "lodash", "version": "4.17.21", "purl": "pkg:npm/lodash@4.17.21", "licenses": [{"license": {"id": "MIT"}}]}
+  ],
+  "dependencies": [{"ref": "frontend", "dependsOn": ["pkg:npm/lodash@4.17.21"]}]
+}`
+
+const sbomProcessTestBackendBOM = `<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.4" version="1">
+  <metadata><component type="application" bom-ref="backend"><name>backend</name><version>1.0.0</version></component></metadata>
+  <components>
+    <component type="library" bom-ref="pkg:npm/lodash@4.17.21"><name>lodash</name><version>4.17.21</version><purl>pkg:npm/lodash@4.17.21</purl></component>
+    <component type="library" bom-ref="pkg:golang/github.com/pkg/errors@v0.9.1"><group>github.com/pkg</group><name>errors</name><version>v0.9.1</version><purl>pkg:golang/github.com/pkg/errors@v0.9.1</purl></component>
+  </components>
+</bom>`
+
+const sbomProcessTestPreviousSPDX = `{
+  "spdxVersion": "SPDX-2.3",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "name": "shop-1.0.0",
+  "packages": [
+    {"SPDXID": "SPDXRef-Package-shop", "name": "shop", "versionInfo": "1.0.0", "licenseConcluded": "NOASSERTION", "licenseDeclared": "NOASSERTION"},
+    {"SPDXID": "SPDXRef-Package-lodash", "name": "lodash", "versionInfo": "4.17.20", "licenseConcluded": "NOASSERTION", "licenseDeclared": "MIT",
+      "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:npm/lodash@4.17.20"}]},
+    {"SPDXID": "SPDXRef-Package-left-pad", "name": "left-pad", "versionInfo": "1.3.0", "licenseConcluded": "NOASSERTION", "licenseDeclared": "WTFPL",
+      "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:npm/left-pad@1.3.0"}]},
+    {"SPDXID": "SPDXRef-Package-errors", "name": "github.com/pkg/errors", "versionInfo": "v0.10.0", "licenseConcluded": "NOASSERTION", "licenseDeclared": "BSD-2-Clause",
+      "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:golang/github.com/pkg/errors@v0.10.0"}]}
+  ],
+  "relationships": [{"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": "SPDXRef-Package-shop"}]
+}`
+
+func newSbomProcessTestsUtils() sbomProcessMockUtils {
+	utils := sbomProcessMockUtils{
+		FilesMock: &mock.FilesMock{},
+	}
+	utils.AddFile("frontend/bom-npm.json", []byte(sbomProcessTestFrontendBOM))
+	utils.AddFile("backend/bom-golang.xml", []byte(sbomProcessTestBackendBOM))
+	return utils
+}
+
+func sbomProcessTestConfig() sbomProcessOptions {
+	return sbomProcessOptions{
+		BomFilePattern:     []string{"**/bom-*.xml", "**/bom-*.json"},
+		ApplicationName:    "shop",
+		ApplicationVersion: "1.1.0",
+		BomFile:            "sbom/application.cdx.json",
+		SpdxFile:           "sbom/application.spdx.json",
+	}
+}
+
+func TestRunSbomProcess(t *testing.T) {
+	t.Parallel()
+
+	t.Run("merge and convert", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := sbomProcessTestConfig()
+		utils := newSbomProcessTestsUtils()
+		// test
+		err := runSbomProcess(&config, utils)
+		// assert
+		assert.NoError(t, err)
+		merged, err := sbom.ReadBOM("sbom/application.cdx.json", utils)
+		require.NoError(t, err)
+		assert.Equal(t, "shop", merged.Metadata.Component.Name)
+		assert.Equal(t, "1.1.0", merged.Metadata.Component.Version)
+		assert.Len(t, *merged.Components, 4)
+		document, err := sbom.ReadSPDX("sbom/application.spdx.json", utils)
+		require.NoError(t, err)
+		assert.Len(t, document.Packages, 5)
+		assert.False(t, utils.HasWrittenFile(filepath.Join(sbom.ReportsDirectory, "piper_sbom_diff_report.html")))
+
+		// outputs of previous runs are not merged again
+		utils.AddFile("sbom/bom-application.json", []byte(sbomProcessTestFrontendBOM))
+		config.BomFile = "sbom/bom-application.json"
+		assert.NoError(t, runSbomProcess(&config, utils))
+		merged, err = sbom.ReadBOM("sbom/bom-application.json", utils)
+		require.NoError(t, err)
+		assert.Len(t, *merged.Components, 4)
+	})
+
+	t.Run("diff to previous version", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := sbomProcessTestConfig()
+		config.SpdxFile = ""
+		config.PreviousBomFile = "release/1.0.0.spdx.json"
+		utils := newSbomProcessTestsUtils()
+		utils.AddFile("release/1.0.0.spdx.json", []byte(sbomProcessTestPreviousSPDX))
+		// test
+		err := runSbomProcess(&config, utils)
+		// assert
+		assert.NoError(t, err)
+		assert.False(t, utils.HasWrittenFile("sbom/application.spdx.json"))
+		assert.True(t, utils.HasWrittenFile(filepath.Join(sbom.ReportsDirectory, "piper_sbom_diff_report.html")))
+		diff, err := utils.FileRead(filepath.Join(sbom.ReportsDirectory, "sbom_diff.json"))
+		require.NoError(t, err)
+		assert.Contains(t, string(diff), `"previousVersion": "4.17.20"`)
+		assert.Contains(t, string(diff), `"name": "left-pad"`)
+		assert.Contains(t, string(diff), `"name": "github.com/pkg/errors"`)
+		var bomDiff sbom.BOMDiff
+		require.NoError(t, json.Unmarshal(diff, &bomDiff))
+		if assert.Len(t, bomDiff.Downgraded, 1) {
+			assert.Equal(t, "v0.10.0", bomDiff.Downgraded[0].PreviousVersion)
+			assert.Equal(t, "v0.9.1", bomDiff.Downgraded[0].Version)
+		}
+	})
+
+	t.Run("no SBOM found", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := sbomProcessTestConfig()
+		config.BomFilePattern = []string{"**/bom.xml"}
+		utils := newSbomProcessTestsUtils()
+		// test
+		err := runSbomProcess(&config, utils)
+		// assert
+		assert.EqualError(t, err, "no SBOM found for patterns [**/bom.xml]")
+	})
+
+	t.Run("invalid SBOM", func(t *testing.T) {
+		t.Parallel()
+		// init
+		config := sbomProcessTestConfig()
+		utils := newSbomProcessTestsUtils()
+		utils.AddFile("bom-broken.json", []byte("{"))
+		// test
+		err := runSbomProcess(&config, utils)
+		// assert
+		assert.Contains(t, err.Error(), "failed to parse SBOM 'bom-broken.json'")
+	})
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bmatcuk/doublestar"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/git"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/secretscan"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+type secretExecuteScanUtils interface {
+	piperutils.FileUtils
+
+	LogRange(from, to string) (object.CommitIter, error)
+}
+
+type secretExecuteScanUtilsBundle struct {
+	*piperutils.Files
+}
+
+func (s *secretExecuteScanUtilsBundle) LogRange(from, to string) (object.CommitIter, error) {
+	repository, err := git.PlainOpen(".")
+	if err != nil {
+		return nil, err
+	}
+	return git.LogRange(repository, from, to)
+}
+
+func newSecretExecuteScanUtils() secretExecuteScanUtils {
+	utils := secretExecuteScanUtilsBundle{
+		Files: &piperutils.Files{},
+	}
+	return &utils
+}
+
+func secretExecuteScan(config secretExecuteScanOptions, _ *telemetry.CustomData) {
+	utils := newSecretExecuteScanUtils()
+
+	err := runSecretExecuteScan(&config, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runSecretExecuteScan(config *secretExecuteScanOptions, utils secretExecuteScanUtils) error {
+	rules := secretscan.DefaultRules()
+	if len(config.RulesFile) > 0 {
+		customRules, err := secretscan.LoadRules(config.RulesFile, utils)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return err
+		}
+		rules = append(rules, customRules...)
+	}
+
+	baseline, err := secretscan.LoadBaseline(config.BaselineFile, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	// excluded files are handled like allowed paths, without becoming part of the created baseline
+	allowlist := *baseline
+	allowlist.Allowlist.Paths = append(append([]string{}, baseline.Allowlist.Paths...), config.ExcludePatterns...)
+
+	scanner, err := secretscan.NewScanner(rules, &allowlist)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "failed to initialize secret scanner")
+	}
+
+	var findings []secretscan.Finding
+	var scanTarget string
+	if len(config.HistoryFrom) > 0 {
+		scanTarget = fmt.Sprintf("git history %v..%v", config.HistoryFrom, config.HistoryTo)
+		findings, err = scanGitHistory(config, scanner, utils)
+	} else {
+		scanTarget = "working tree"
+		findings, err = scanWorkingTree(config, scanner, utils)
+	}
+	if err != nil {
+		return err
+	}
+	findings = baseline.Apply(findings)
+	newFindings := secretscan.NewFindings(findings)
+
+	for _, finding := range findings {
+		status := "new"
+		if finding.InBaseline {
+			status = "known"
+		}
+		log.Entry().Infof("%v (%v) detected in %v:%v: %v [%v]", finding.Description, status, finding.File, finding.Line, finding.Redacted(), finding.Fingerprint)
+	}
+
+	reportPaths, err := writeSecretScanReports(scanTarget, findings, rules, baseline, utils)
+	piperutils.PersistReportsAndLinks("secretExecuteScan", "", utils, reportPaths, nil)
+	if err != nil {
+		return err
+	}
+
+	if len(newFindings) > 0 {
+		if config.FailOnNewSecrets {
+			log.SetErrorCategory(log.ErrorCompliance)
+			return fmt.Errorf("%v new secrets detected which are not contained in the baseline %v", len(newFindings), config.BaselineFile)
+		}
+		log.Entry().Warnf("%v new secrets detected which are not contained in the baseline %v", len(newFindings), config.BaselineFile)
+		log.Entry().Info("Step will only create data but not fail due to setting failOnNewSecrets: false")
+	} else {
+		log.Entry().Infof("No new secrets detected (%v secrets contained in the baseline)", len(findings))
+	}
+	return nil
+}
+
+func scanWorkingTree(config *secretExecuteScanOptions, scanner *secretscan.Scanner, utils secretExecuteScanUtils) ([]secretscan.Finding, error) {
+	findings := []secretscan.Finding{}
+	scanned := map[string]bool{}
+	for _, pattern := range config.FilePatterns {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "failed to find files for pattern '%v'", pattern)
+		}
+		for _, match := range matches {
+			if scanned[match] || matchesSecretScanPattern(match, config.ExcludePatterns) {
+				continue
+			}
+			scanned[match] = true
+			if isDir, _ := utils.DirExists(match); isDir {
+				continue
+			}
+			content, err := utils.FileRead(match)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read file '%v'", match)
+			}
+			findings = append(findings, scanner.ScanContent(match, content)...)
+		}
+	}
+	log.Entry().Infof("%v files scanned", len(scanned))
+	return findings, nil
+}
+
+func scanGitHistory(config *secretExecuteScanOptions, scanner *secretscan.Scanner, utils secretExecuteScanUtils) ([]secretscan.Finding, error) {
+	commits, err := utils.LogRange(config.HistoryFrom, config.HistoryTo)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrap(err, "failed to read git history")
+	}
+	defer commits.Close()
+	findings, err := scanner.ScanCommits(commits)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to scan git history")
+	}
+	return findings, nil
+}
+
+func matchesSecretScanPattern(path string, excludePatterns []string) bool {
+	for _, pattern := range excludePatterns {
+		if matched, _ := doublestar.PathMatch(pattern, path); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func writeSecretScanReports(scanTarget string, findings []secretscan.Finding, rules []secretscan.Rule, baseline *secretscan.Baseline, utils secretExecuteScanUtils) ([]piperutils.Path, error) {
+	scanReport := secretscan.CreateCustomReport(scanTarget, findings)
+	reportPaths, err := secretscan.WriteCustomReports(scanReport, utils)
+	if err != nil {
+		return reportPaths, errors.Wrap(err, "failed to write secret scan report")
+	}
+
+	sarifPaths, err := secretscan.WriteSarifFile(secretscan.CreateSarifResultFile(findings, rules), utils)
+	reportPaths = append(reportPaths, sarifPaths...)
+	if err != nil {
+		return reportPaths, err
+	}
+
+	baselinePaths, err := secretscan.WriteBaseline(baseline, findings, utils)
+	reportPaths = append(reportPaths, baselinePaths...)
+	return reportPaths, err
+}
@@ -0,0 +1,287 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/cobra"
+)
+
+type secretExecuteScanOptions struct {
+	FilePatterns     []string `json:"filePatterns,omitempty"`
+	ExcludePatterns  []string `json:"excludePatterns,omitempty"`
+	HistoryFrom      string   `json:"historyFrom,omitempty"`
+	HistoryTo        string   `json:"historyTo,omitempty"`
+	RulesFile        string   `json:"rulesFile,omitempty"`
+	BaselineFile     string   `json:"baselineFile,omitempty"`
+	FailOnNewSecrets bool     `json:"failOnNewSecrets,omitempty"`
+}
+
+type secretExecuteScanReports struct {
+}
+
+func (p *secretExecuteScanReports) persist(stepConfig secretExecuteScanOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "**/piper_secret_scan_report.html", ParamRef: "", StepResultType: "secret-scan"},
+		{FilePattern: "**/piper_secret_scan.sarif", ParamRef: "", StepResultType: "secret-scan"},
+		{FilePattern: "**/piper_secret_baseline.yaml", ParamRef: "", StepResultType: "secret-scan"},
+		{FilePattern: "**/secretExecuteScan_secrets.json", ParamRef: "", StepResultType: "secret-scan"},
+	}
+	envVars := []gcs.EnvVar{
+		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
+	}
+	gcsClient, err := gcs.NewClient(gcs.WithEnvVars(envVars))
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// SecretExecuteScanCommand Scans the sources or the git history for hard-coded secrets.
+func SecretExecuteScanCommand() *cobra.Command {
+	const STEP_NAME = "secretExecuteScan"
+
+	metadata := secretExecuteScanMetadata()
+	var stepConfig secretExecuteScanOptions
+	var startTime time.Time
+	var reports secretExecuteScanReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createSecretExecuteScanCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Scans the sources or the git history for hard-coded secrets.",
+		Long: `Scans the files of the working tree or the commits of a git history range for hard-coded secrets like access keys, tokens and private keys.
+
+Secrets are detected by a regex and entropy based rule engine. The built-in rules detect AWS access keys, GitHub and Slack tokens, Slack webhooks, private keys and generic secret assignments.
+Additional rules can be provided via ` + "`" + `rulesFile` + "`" + `.
+
+Known findings are recorded in a baseline file via their fingerprint, the baseline file also allows to exclude paths and example values.
+The step fails only on secrets which are not contained in the baseline. A baseline containing all current findings is created as report, so that findings can be accepted easily.
+The step creates a SARIF file as well as an HTML and a JSON report. Detected secrets are redacted in all reports and in the log.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.Send()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+			}, func() {
+				secretExecuteScan(stepConfig, &stepTelemetryData)
+			})
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addSecretExecuteScanFlags(createSecretExecuteScanCmd, &stepConfig)
+	return createSecretExecuteScanCmd
+}
+
+func addSecretExecuteScanFlags(cmd *cobra.Command, stepConfig *secretExecuteScanOptions) {
+	cmd.Flags().StringSliceVar(&stepConfig.FilePatterns, "filePatterns", []string{`**/*`}, "List of file patterns of the working tree to scan. Not considered when scanning a git history range.")
+	cmd.Flags().StringSliceVar(&stepConfig.ExcludePatterns, "excludePatterns", []string{`**/.git/**`, `**/node_modules/**`, `**/.pipeline/**`}, "List of file patterns which are not scanned.")
+	cmd.Flags().StringVar(&stepConfig.HistoryFrom, "historyFrom", os.Getenv("PIPER_historyFrom"), "If set, the commits of the git history range from this commit or reference (exclusive) to `historyTo` are scanned instead of the working tree.")
+	cmd.Flags().StringVar(&stepConfig.HistoryTo, "historyTo", `HEAD`, "Commit or reference up to which the git history is scanned.")
+	cmd.Flags().StringVar(&stepConfig.RulesFile, "rulesFile", os.Getenv("PIPER_rulesFile"), "Path to a YAML file containing additional rules (`rules: [{id, description, regex, entropy}]`).")
+	cmd.Flags().StringVar(&stepConfig.BaselineFile, "baselineFile", `.secrets-baseline.yaml`, "Path to the baseline file containing the fingerprints of known findings as well as an allowlist of paths and secret patterns.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnNewSecrets, "failOnNewSecrets", true, "Whether to fail the step if secrets are detected which are not contained in the baseline.")
+
+}
+
+// retrieve step metadata
+func secretExecuteScanMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "secretExecuteScan",
+			Aliases:     []config.Alias{},
+			Description: "Scans the sources or the git history for hard-coded secrets.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "filePatterns",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`**/*`},
+					},
+					{
+						Name:        "excludePatterns",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`**/.git/**`, `**/node_modules/**`, `**/.pipeline/**`},
+					},
+					{
+						Name:        "historyFrom",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_historyFrom"),
+					},
+					{
+						Name:        "historyTo",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `HEAD`,
+					},
+					{
+						Name:        "rulesFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_rulesFile"),
+					},
+					{
+						Name:        "baselineFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `.secrets-baseline.yaml`,
+					},
+					{
+						Name:        "failOnNewSecrets",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "**/piper_secret_scan_report.html", "type": "secret-scan"},
+							{"filePattern": "**/piper_secret_scan.sarif", "type": "secret-scan"},
+							{"filePattern": "**/piper_secret_baseline.yaml", "type": "secret-scan"},
+							{"filePattern": "**/secretExecuteScan_secrets.json", "type": "secret-scan"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/bmatcuk/doublestar"
+	sonargo "github.com/magicsong/sonargo/sonar"
 	"github.com/pkg/errors"
 
 	"github.com/SAP/jenkins-library/pkg/command"
@@ -303,6 +304,31 @@ func runSonar(config sonarExecuteScanOptions, client piperhttp.Downloader, runne
 		reportData.LinesOfCode = loc
 	}
 
+	qualityGateService := SonarUtils.NewQualityGateService(serverUrl, config.Token, taskReport.ProjectKey, config.BranchName, config.ChangeID, apiClient)
+	qualityGateService.AnalysisID = taskService.AnalysisID
+	qualityGate, err := qualityGateService.GetQualityGate()
+	if err != nil {
+		if config.FailOnQualityGateError {
+			return err
+		}
+		log.Entry().Warnf("failed to retrieve sonar quality gate status: %v", err)
+	} else {
+		reportData.QualityGate = qualityGate
+		log.Entry().Infof("Quality gate status: %v", qualityGate.Status)
+		for _, condition := range qualityGate.FailedConditions() {
+			log.Entry().Warnf("Quality gate condition on %v failed: %v (threshold %v %v)", condition.Metric, condition.ActualValue, condition.Comparator, condition.ErrorThreshold)
+		}
+	}
+
+	if config.ExportIssues {
+		paths, err := exportSonarIssues(serverUrl, taskReport.ProjectKey, config, apiClient, utils, qualityGate)
+		reports = append(reports, paths...)
+		piperutils.PersistReportsAndLinks("sonarExecuteScan", sonar.workingDir, utils, reports, links)
+		if err != nil {
+			return err
+		}
+	}
+
 	log.Entry().Debugf("Influx values: %v", influx.sonarqube_data.fields)
 
 	err = SonarUtils.WriteReport(reportData, sonar.workingDir, os.WriteFile)
@@ -310,9 +336,50 @@ func runSonar(config sonarExecuteScanOptions, client piperhttp.Downloader, runne
 	if err != nil {
 		return err
 	}
+
+	if config.FailOnQualityGateError && qualityGate != nil && qualityGate.Status == SonarUtils.QualityGateStatusError {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("quality gate of project '%v' failed", taskReport.ProjectKey)
+	}
 	return nil
 }
 
+// exportSonarIssues writes all open issues and security hotspots to review as SARIF file and as HTML and JSON report
+func exportSonarIssues(serverURL, projectKey string, config sonarExecuteScanOptions, apiClient SonarUtils.Sender, utils piperutils.FileUtils, qualityGate *SonarUtils.QualityGate) ([]piperutils.Path, error) {
+	findings := SonarUtils.Findings{}
+	var err error
+	var components []*sonargo.Component
+
+	issueService := SonarUtils.NewIssuesService(serverURL, config.Token, projectKey, config.Organization, config.BranchName, config.ChangeID, apiClient)
+	findings.Issues, findings.Components, err = issueService.GetAllIssues()
+	if err != nil {
+		return nil, err
+	}
+	hotspotService := SonarUtils.NewHotspotService(serverURL, config.Token, projectKey, config.BranchName, config.ChangeID, apiClient)
+	findings.Hotspots, components, err = hotspotService.GetHotspotsToReview()
+	if err != nil {
+		return nil, err
+	}
+	findings.Components = append(findings.Components, components...)
+	log.Entry().Infof("Exporting %v open issues and %v security hotspots to review", len(findings.Issues), len(findings.Hotspots))
+
+	reports, err := SonarUtils.WriteSarifFile(SonarUtils.CreateSarifResultFile(&findings), sonar.workingDir, utils)
+	if err != nil {
+		return reports, err
+	}
+	if len(config.SarifBaselineLocation) > 0 {
+		baseline := newSarifBaseline("sonarExecuteScan", config.SarifBaselineLocation)
+		baseline.fileUtils = utils
+		_, paths, err := baseline.process(reports[0].Target, "SonarQube", filepath.Join(sonar.workingDir, SonarUtils.ReportsDirectory))
+		if err != nil {
+			log.Entry().WithError(err).Warning("failed to process SARIF baseline")
+		}
+		reports = append(reports, paths...)
+	}
+	paths, err := SonarUtils.WriteCustomReports(SonarUtils.CreateCustomReport(projectKey, &findings, qualityGate), sonar.workingDir, utils)
+	return append(reports, paths...), err
+}
+
 // isInOptions returns true, if the given property is already provided in config.Options.
 func isInOptions(config sonarExecuteScanOptions, property string) bool {
 	property = strings.TrimSuffix(property, "=")
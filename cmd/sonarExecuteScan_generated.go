@@ -38,6 +38,9 @@ type sonarExecuteScanOptions struct {
 	InferJavaLibraries        bool     `json:"inferJavaLibraries,omitempty"`
 	Options                   []string `json:"options,omitempty"`
 	WaitForQualityGate        bool     `json:"waitForQualityGate,omitempty"`
+	FailOnQualityGateError    bool     `json:"failOnQualityGateError,omitempty"`
+	ExportIssues              bool     `json:"exportIssues,omitempty"`
+	SarifBaselineLocation     string   `json:"sarifBaselineLocation,omitempty"`
 	BranchName                string   `json:"branchName,omitempty"`
 	InferBranchName           bool     `json:"inferBranchName,omitempty"`
 	ChangeID                  string   `json:"changeId,omitempty"`
@@ -65,6 +68,8 @@ func (p *sonarExecuteScanReports) persist(stepConfig sonarExecuteScanOptions, gc
 	content := []gcs.ReportOutputParam{
 		{FilePattern: "**/sonarscan.json", ParamRef: "", StepResultType: "sonarqube"},
 		{FilePattern: "**/sonarscan-result.json", ParamRef: "", StepResultType: "sonarqube"},
+		{FilePattern: "**/piper_sonar_report.html", ParamRef: "", StepResultType: "sonarqube"},
+		{FilePattern: "**/piper_sonar_issues.sarif", ParamRef: "", StepResultType: "sonarqube"},
 	}
 	envVars := []gcs.EnvVar{
 		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
@@ -208,6 +213,7 @@ func SonarExecuteScanCommand() *cobra.Command {
 				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
 				influx.persist(GeneralConfig.EnvRootPath, "influx")
 				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
 				stepTelemetryData.PiperCommitHash = GitCommit
@@ -233,7 +239,12 @@ func SonarExecuteScanCommand() *cobra.Command {
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
-			sonarExecuteScan(stepConfig, &stepTelemetryData, &influx)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+				influx = sonarExecuteScanInflux{}
+			}, func() {
+				sonarExecuteScan(stepConfig, &stepTelemetryData, &influx)
+			})
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
@@ -260,6 +271,9 @@ func addSonarExecuteScanFlags(cmd *cobra.Command, stepConfig *sonarExecuteScanOp
 	cmd.Flags().BoolVar(&stepConfig.InferJavaLibraries, "inferJavaLibraries", false, "If the parameter `m2Path` is configured for the step `mavenExecute` in the general section of the configuration, pass it as option `sonar.java.libraries` to the sonar tool.")
 	cmd.Flags().StringSliceVar(&stepConfig.Options, "options", []string{}, "A list of options which are passed to the sonar-scanner.")
 	cmd.Flags().BoolVar(&stepConfig.WaitForQualityGate, "waitForQualityGate", false, "Whether the scan should wait for and consider the result of the quality gate.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnQualityGateError, "failOnQualityGateError", false, "Whether the step should fail if the status of the quality gate of the project is `ERROR`. In contrast to `waitForQualityGate`, the status is retrieved via the SonarQube API after the analysis has been processed, so that the reports are still created.")
+	cmd.Flags().BoolVar(&stepConfig.ExportIssues, "exportIssues", false, "Whether all open issues and security hotspots to review should be exported as SARIF file and as HTML and JSON report.")
+	cmd.Flags().StringVar(&stepConfig.SarifBaselineLocation, "sarifBaselineLocation", os.Getenv("PIPER_sarifBaselineLocation"), "Location of the SARIF baselines, either a directory or a Google Cloud Storage folder (`gs://<bucket>/<folder>`). On pull requests, the exported SARIF results are compared with the baseline of the target branch, otherwise they are stored as baseline of the current branch. Requires `exportIssues`.")
 	cmd.Flags().StringVar(&stepConfig.BranchName, "branchName", os.Getenv("PIPER_branchName"), "Non-Pull-Request only: Name of the SonarQube branch that should be used to report findings to. Automatically inferred from environment variables on supported orchestrators if `inferBranchName` is set to true.")
 	cmd.Flags().BoolVar(&stepConfig.InferBranchName, "inferBranchName", false, "Whether to infer the `branchName` parameter automatically based on the orchestrator-specific environment variable in runs of the pipeline.")
 	cmd.Flags().StringVar(&stepConfig.ChangeID, "changeId", os.Getenv("PIPER_changeId"), "Pull-Request only: The id of the pull-request. Automatically inferred from environment variables on supported orchestrators.")
@@ -451,6 +465,33 @@ func sonarExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     false,
 					},
+					{
+						Name:        "failOnQualityGateError",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "exportIssues",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "sarifBaselineLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_sarifBaselineLocation"),
+					},
 					{
 						Name:        "branchName",
 						ResourceRef: []config.ResourceReference{},
@@ -602,6 +643,8 @@ func sonarExecuteScanMetadata() config.StepData {
 						Parameters: []map[string]interface{}{
 							{"filePattern": "**/sonarscan.json", "type": "sonarqube"},
 							{"filePattern": "**/sonarscan-result.json", "type": "sonarqube"},
+							{"filePattern": "**/piper_sonar_report.html", "type": "sonarqube"},
+							{"filePattern": "**/piper_sonar_issues.sarif", "type": "sonarqube"},
 						},
 					},
 					{
@@ -327,6 +327,83 @@ func TestRunSonar(t *testing.T) {
 	})
 }
 
+func TestRunSonarQualityGate(t *testing.T) {
+	mockRunner := mock.ExecMockRunner{}
+	mockDownloadClient := mockDownloader{shouldFail: false}
+	apiClient := &piperHttp.Client{}
+	apiClient.SetOptions(piperHttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+	// mock SonarQube API calls
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	// add response handler
+	httpmock.RegisterResponder(http.MethodGet, sonarServerURL+"/api/"+SonarUtils.EndpointCeTask+"", httpmock.NewStringResponder(http.StatusOK, `{ "task": { "componentId": "AXERR2JBbm9IiM5TEST", "analysisId": "AXanalysis", "status": "SUCCESS" }}`))
+	httpmock.RegisterResponder(http.MethodGet, sonarServerURL+"/api/"+SonarUtils.EndpointIssuesSearch+"", httpmock.NewStringResponder(http.StatusOK, `{ "total": 1, "paging": { "pageIndex": 1, "pageSize": 500, "total": 1 }, "issues": [{ "key": "AXissue", "rule": "go:S1192", "severity": "MAJOR", "type": "CODE_SMELL", "component": "piper-test:main.go", "line": 3, "message": "Define a constant" }] }`))
+	httpmock.RegisterResponder(http.MethodGet, sonarServerURL+"/api/"+SonarUtils.EndpointHotspotsSearch+"", httpmock.NewStringResponder(http.StatusOK, `{ "paging": { "pageIndex": 1, "pageSize": 500, "total": 0 }, "hotspots": [] }`))
+	httpmock.RegisterResponder(http.MethodGet, sonarServerURL+"/api/"+SonarUtils.EndpointMeasuresComponent+"", httpmock.NewStringResponder(http.StatusOK, measuresComponentResponse))
+	// the quality gate of the analysis created by the task is expected to be evaluated
+	httpmock.RegisterResponder(http.MethodGet, sonarServerURL+"/api/"+SonarUtils.EndpointQualityGatesProjectStatus+"", httpmock.NewStringResponder(http.StatusOK, `{ "projectStatus": { "status": "OK" } }`))
+	httpmock.RegisterResponderWithQuery(http.MethodGet, sonarServerURL+"/api/"+SonarUtils.EndpointQualityGatesProjectStatus+"", "analysisId=AXanalysis", httpmock.NewStringResponder(http.StatusOK, `{ "projectStatus": { "status": "ERROR", "conditions": [{ "status": "ERROR", "metricKey": "new_coverage", "comparator": "LT", "errorThreshold": "80", "actualValue": "52.3" }] } }`))
+
+	runWithOptions := func(t *testing.T, options sonarExecuteScanOptions, utils *mock.FilesMock) (string, error) {
+		tmpFolder := t.TempDir()
+		createTaskReportFile(t, tmpFolder)
+		sonar = sonarSettings{
+			workingDir:  tmpFolder,
+			binary:      "sonar-scanner",
+			environment: []string{},
+			options:     []string{},
+		}
+		options.Token = "secret-ABC"
+		options.ServerURL = sonarServerURL
+		options.PullRequestProvider = "GitHub"
+		fileUtilsExists = mockFileUtilsExists(true)
+		return tmpFolder, runSonar(options, &mockDownloadClient, &mockRunner, apiClient, utils, &sonarExecuteScanInflux{})
+	}
+
+	t.Run("quality gate reported", func(t *testing.T) {
+		tmpFolder, err := runWithOptions(t, sonarExecuteScanOptions{}, &mock.FilesMock{})
+
+		assert.NoError(t, err)
+		report, err := os.ReadFile(filepath.Join(tmpFolder, "sonarscan.json"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(report), `"qualityGate":{"status":"ERROR","conditions":[{"metric":"new_coverage","status":"ERROR","comparator":"LT","errorThreshold":"80","actualValue":"52.3"}]}`)
+	})
+
+	t.Run("fail on quality gate error", func(t *testing.T) {
+		_, err := runWithOptions(t, sonarExecuteScanOptions{FailOnQualityGateError: true}, &mock.FilesMock{})
+
+		assert.EqualError(t, err, "quality gate of project 'piper-test' failed")
+	})
+
+	t.Run("export issues", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		tmpFolder, err := runWithOptions(t, sonarExecuteScanOptions{ExportIssues: true}, utils)
+
+		assert.NoError(t, err)
+		sarif, err := utils.FileRead(filepath.Join(tmpFolder, "sonar", "piper_sonar_issues.sarif"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(sarif), `"ruleId":"go:S1192"`)
+		assert.Contains(t, string(sarif), `"uri":"main.go"`)
+		assert.True(t, utils.HasWrittenFile(filepath.Join(tmpFolder, "sonar", "piper_sonar_report.html")))
+		reports, err := utils.FileRead(filepath.Join(tmpFolder, "sonarExecuteScan_reports.json"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(reports), "sonarscan.json")
+		assert.Contains(t, string(reports), "piper_sonar_report.html")
+	})
+
+	t.Run("export issues as SARIF baseline", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		baselineDir := "baseline"
+		_, err := runWithOptions(t, sonarExecuteScanOptions{ExportIssues: true, SarifBaselineLocation: baselineDir}, utils)
+
+		assert.NoError(t, err)
+		// the branch of the unknown orchestrator is n/a
+		exists, err := utils.FileExists(filepath.Join(baselineDir, "n", "a", "sonarExecuteScan.sarif"))
+		assert.NoError(t, err)
+		assert.True(t, exists)
+	})
+}
+
 func TestSonarHandlePullRequest(t *testing.T) {
 	t.Run("default", func(t *testing.T) {
 		// init
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+// StepHookConfiguration contains user-defined hooks which are executed before (pre) and after (post) the step body.
+// It is configured via the parameter 'hooks' in the steps and stages section of the configuration.
+type StepHookConfiguration struct {
+	Pre  []StepHook `json:"pre,omitempty"`
+	Post []StepHook `json:"post,omitempty"`
+}
+
+// StepHook defines a single hook, either a shell command or another piper step
+type StepHook struct {
+	Name            string   `json:"name,omitempty"`
+	Command         string   `json:"command,omitempty"`
+	Shell           string   `json:"shell,omitempty"`
+	Step            string   `json:"step,omitempty"`
+	Args            []string `json:"args,omitempty"`
+	ContinueOnError bool     `json:"continueOnError,omitempty"`
+}
+
+type stepHookUtils interface {
+	command.ExecRunner
+	command.ShellRunner
+}
+
+func newStepHookUtils() stepHookUtils {
+	utils := command.Command{}
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+// postHooksExecuted prevents running the post hooks again, when a failing post hook exits the step via the exit handlers
+var postHooksExecuted bool
+
+// RunStepPreHooks executes the configured pre hooks of a step and fails the step in case a hook fails
+func RunStepPreHooks(stepName string, stepConfig interface{}, metadata config.StepData) {
+	hooks := GeneralConfig.StepHookConfig.Pre
+	if len(hooks) == 0 {
+		return
+	}
+	if err := runStepHooks(stepName, "pre", "", hooks, hookStepConfig(stepConfig, metadata), newStepHookUtils()); err != nil {
+		log.Entry().WithError(err).Fatal("pre step hook failed")
+	}
+}
+
+// RunStepPostHooks executes the configured post hooks of a step once the step outputs have been persisted.
+// Post hooks run for successful as well as for failed steps, the result of the step is passed via PIPER_HOOK_STEP_RESULT.
+// A failing post hook fails a successful step, for a failed step it is only reported as warning.
+func RunStepPostHooks(stepName string, stepConfig interface{}, metadata config.StepData, stepTelemetryData *telemetry.CustomData) {
+	hooks := GeneralConfig.StepHookConfig.Post
+	if len(hooks) == 0 || postHooksExecuted {
+		return
+	}
+	postHooksExecuted = true
+	result := "success"
+	if stepTelemetryData.ErrorCode != "0" {
+		result = "failure"
+	}
+	err := runStepHooks(stepName, "post", result, hooks, hookStepConfig(stepConfig, metadata), newStepHookUtils())
+	if err == nil {
+		return
+	}
+	if result == "failure" {
+		log.Entry().WithError(err).Warning("post step hook failed")
+		return
+	}
+	stepTelemetryData.ErrorCode = "1"
+	log.Entry().WithError(err).Fatal("post step hook failed")
+}
+
+// hookStepConfig returns the step configuration without the parameters which are marked as secret in the step metadata,
+// since the configuration is passed to arbitrary commands
+func hookStepConfig(stepConfig interface{}, metadata config.StepData) map[string]interface{} {
+	hookConfig := map[string]interface{}{}
+	content, err := json.Marshal(stepConfig)
+	if err != nil {
+		log.Entry().WithError(err).Warning("failed to marshal step configuration for hooks")
+		return hookConfig
+	}
+	if err := json.Unmarshal(content, &hookConfig); err != nil {
+		log.Entry().WithError(err).Warning("failed to unmarshal step configuration for hooks")
+		return hookConfig
+	}
+	for _, param := range metadata.Spec.Inputs.Parameters {
+		if param.Secret {
+			delete(hookConfig, param.Name)
+		}
+	}
+	return hookConfig
+}
+
+func runStepHooks(stepName, phase, result string, hooks []StepHook, stepConfig map[string]interface{}, utils stepHookUtils) error {
+	stepConfigJSON, err := json.Marshal(stepConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal step configuration")
+	}
+	cpePath, _ := filepath.Abs(filepath.Join(GeneralConfig.EnvRootPath, "commonPipelineEnvironment"))
+	env := []string{
+		fmt.Sprintf("PIPER_HOOK_PHASE=%v", phase),
+		fmt.Sprintf("PIPER_HOOK_STEP_NAME=%v", stepName),
+		fmt.Sprintf("PIPER_HOOK_STAGE_NAME=%v", GeneralConfig.StageName),
+		fmt.Sprintf("PIPER_HOOK_STEP_CONFIG=%v", string(stepConfigJSON)),
+		fmt.Sprintf("PIPER_HOOK_CPE_PATH=%v", cpePath),
+	}
+	if len(result) > 0 {
+		env = append(env, fmt.Sprintf("PIPER_HOOK_STEP_RESULT=%v", result))
+	}
+	utils.AppendEnv(env)
+
+	for i, hook := range hooks {
+		name := hook.Name
+		if len(name) == 0 {
+			name = fmt.Sprintf("%v hook %v", phase, i+1)
+		}
+		log.Entry().Infof("Running %v (%v/%v)", name, log.LibraryName, stepName)
+
+		var err error
+		switch {
+		case len(hook.Command) > 0:
+			shell := hook.Shell
+			if len(shell) == 0 {
+				shell = "/bin/sh"
+			}
+			err = utils.RunShell(shell, hook.Command)
+		case len(hook.Step) > 0:
+			err = runStepHookStep(hook, utils)
+		default:
+			err = fmt.Errorf("neither 'command' nor 'step' is defined")
+		}
+
+		if err != nil {
+			if hook.ContinueOnError {
+				log.Entry().WithError(err).Warningf("%v failed, continuing", name)
+				continue
+			}
+			return errors.Wrapf(err, "%v failed", name)
+		}
+	}
+	return nil
+}
+
+func runStepHookStep(hook StepHook, utils stepHookUtils) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return errors.Wrap(err, "failed to determine piper executable")
+	}
+	args := []string{hook.Step}
+	if len(GeneralConfig.StageName) > 0 {
+		args = append(args, "--stageName", GeneralConfig.StageName)
+	}
+	args = append(args, hook.Args...)
+	return utils.RunExecutable(executable, args...)
+}
+
+func retrieveStepHookConfig(source interface{}, target *StepHookConfiguration) {
+	if source != nil {
+		log.Entry().Debug("Retrieving step hook configuration")
+		b, err := json.Marshal(source)
+		if err != nil {
+			log.Entry().Warningf("Failed to marshal step hook configuration: %v", err)
+		}
+		err = json.Unmarshal(b, target)
+		if err != nil {
+			log.Entry().Warningf("Failed to retrieve step hook configuration: %v", err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type stepHookMockUtils struct {
+	*mock.ExecMockRunner
+	shellRunner *mock.ShellMockRunner
+}
+
+func (u *stepHookMockUtils) RunShell(shell, script string) error {
+	return u.shellRunner.RunShell(shell, script)
+}
+
+func newStepHookMockUtils() *stepHookMockUtils {
+	return &stepHookMockUtils{
+		ExecMockRunner: &mock.ExecMockRunner{},
+		shellRunner:    &mock.ShellMockRunner{},
+	}
+}
+
+func TestRunStepHooks(t *testing.T) {
+	stepConfig := map[string]interface{}{"param": "value"}
+
+	t.Run("success case", func(t *testing.T) {
+		defer func(stageName string) { GeneralConfig.StageName = stageName }(GeneralConfig.StageName)
+		GeneralConfig.StageName = "Build"
+		utils := newStepHookMockUtils()
+		hooks := []StepHook{
+			{Name: "prepare", Command: "echo prepare"},
+			{Step: "shellExecute", Args: []string{"--sources", "./cleanup.sh"}},
+		}
+
+		err := runStepHooks("testStep", "pre", "", hooks, stepConfig, utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"echo prepare"}, utils.shellRunner.Calls)
+		if assert.Len(t, utils.Calls, 1) {
+			assert.Equal(t, []string{"shellExecute", "--stageName", "Build", "--sources", "./cleanup.sh"}, utils.Calls[0].Params)
+		}
+		assert.Contains(t, utils.Env, "PIPER_HOOK_PHASE=pre")
+		assert.Contains(t, utils.Env, "PIPER_HOOK_STEP_NAME=testStep")
+		assert.Contains(t, utils.Env, "PIPER_HOOK_STAGE_NAME=Build")
+		assert.Contains(t, utils.Env, `PIPER_HOOK_STEP_CONFIG={"param":"value"}`)
+		assert.NotContains(t, utils.Env, "PIPER_HOOK_STEP_RESULT=")
+	})
+
+	t.Run("success case - post hook of failed step", func(t *testing.T) {
+		utils := newStepHookMockUtils()
+
+		err := runStepHooks("testStep", "post", "failure", []StepHook{{Command: "echo cleanup"}}, stepConfig, utils)
+
+		assert.NoError(t, err)
+		assert.Contains(t, utils.Env, "PIPER_HOOK_PHASE=post")
+		assert.Contains(t, utils.Env, "PIPER_HOOK_STEP_RESULT=failure")
+	})
+
+	t.Run("error case - failing hook", func(t *testing.T) {
+		utils := newStepHookMockUtils()
+		utils.shellRunner.ShouldFailOnCommand = map[string]error{"exit 1": fmt.Errorf("exit status 1")}
+		hooks := []StepHook{{Command: "exit 1"}, {Command: "echo not executed"}}
+
+		err := runStepHooks("testStep", "post", "success", hooks, stepConfig, utils)
+
+		assert.EqualError(t, err, "post hook 1 failed: exit status 1")
+		assert.Equal(t, []string{"exit 1"}, utils.shellRunner.Calls)
+	})
+
+	t.Run("success case - continue on error", func(t *testing.T) {
+		utils := newStepHookMockUtils()
+		utils.shellRunner.ShouldFailOnCommand = map[string]error{"exit 1": fmt.Errorf("exit status 1")}
+		hooks := []StepHook{{Command: "exit 1", ContinueOnError: true}, {Command: "echo executed"}}
+
+		err := runStepHooks("testStep", "post", "success", hooks, stepConfig, utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"exit 1", "echo executed"}, utils.shellRunner.Calls)
+	})
+
+	t.Run("error case - invalid hook", func(t *testing.T) {
+		utils := newStepHookMockUtils()
+
+		err := runStepHooks("testStep", "pre", "", []StepHook{{Name: "empty"}}, stepConfig, utils)
+
+		assert.EqualError(t, err, "empty failed: neither 'command' nor 'step' is defined")
+	})
+}
+
+func TestHookStepConfig(t *testing.T) {
+	stepConfig := struct {
+		Param    string `json:"param,omitempty"`
+		Token    string `json:"token,omitempty"`
+		Password string `json:"password,omitempty"`
+	}{Param: "value", Token: "secretToken", Password: "secretPassword"}
+	metadata := config.StepData{Spec: config.StepSpec{Inputs: config.StepInputs{Parameters: []config.StepParameters{
+		{Name: "param"},
+		{Name: "token", Secret: true},
+		{Name: "password", Secret: true},
+	}}}}
+
+	assert.Equal(t, map[string]interface{}{"param": "value"}, hookStepConfig(stepConfig, metadata))
+}
+
+func TestRetrieveStepHookConfig(t *testing.T) {
+	source := map[string]interface{}{
+		"pre":  []interface{}{map[string]interface{}{"command": "echo pre"}},
+		"post": []interface{}{map[string]interface{}{"step": "shellExecute", "continueOnError": true}},
+	}
+	target := StepHookConfiguration{}
+
+	retrieveStepHookConfig(source, &target)
+
+	assert.Equal(t, StepHookConfiguration{
+		Pre:  []StepHook{{Command: "echo pre"}},
+		Post: []StepHook{{Step: "shellExecute", ContinueOnError: true}},
+	}, target)
+}
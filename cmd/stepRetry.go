@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+// StepRetryConfiguration defines whether and how often a failing step is executed again.
+// It is configured via the parameter 'retry' in the general, steps and stages section of the configuration.
+type StepRetryConfiguration struct {
+	Attempts        int      `json:"attempts,omitempty"`
+	Backoff         string   `json:"backoff,omitempty"`
+	ErrorCategories []string `json:"errorCategories,omitempty"`
+}
+
+// defaultRetryErrorCategories are the error categories a step is retried for if not configured otherwise
+var defaultRetryErrorCategories = []string{log.ErrorInfrastructure.String(), log.ErrorService.String()}
+
+const defaultRetryBackoff = 10 * time.Second
+
+// RunStepWithRetry executes the step function and retries it according to the retry configuration.
+// The reset function is called before each retry in order to reset the step outputs.
+func RunStepWithRetry(stepName string, telemetryData *telemetry.CustomData, reset func(), run func()) {
+	runStepWithRetry(stepName, GeneralConfig.StepRetryConfig, telemetryData, reset, run, time.Sleep)
+}
+
+func runStepWithRetry(stepName string, retryConfig StepRetryConfiguration, telemetryData *telemetry.CustomData, reset func(), run func(), sleep func(time.Duration)) {
+	attempts := retryConfig.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := defaultRetryBackoff
+	if len(retryConfig.Backoff) > 0 {
+		if parsed, err := time.ParseDuration(retryConfig.Backoff); err == nil {
+			backoff = parsed
+		} else {
+			log.Entry().Warningf("invalid value for retry backoff '%v', using %v", retryConfig.Backoff, defaultRetryBackoff)
+		}
+	}
+	errorCategories := retryConfig.ErrorCategories
+	if len(errorCategories) == 0 {
+		errorCategories = defaultRetryErrorCategories
+	}
+
+	for attempt := 1; ; attempt++ {
+		telemetryData.Attempts = fmt.Sprint(attempt)
+		if attempt == attempts {
+			// last attempt: a fatal error exits the step as usual
+			run()
+			return
+		}
+
+		err := log.RunRecoverable(run)
+		if err == nil {
+			return
+		}
+		category := log.GetErrorCategory().String()
+		if !isRetryErrorCategory(category, errorCategories) {
+			log.Entry().Infof("Not retrying step since error category '%v' is not configured for retry (%v/%v)", category, log.LibraryName, stepName)
+			log.ExitWithRecoveredFatal(err)
+		}
+
+		log.Entry().Warningf("Attempt %v of %v failed with error category '%v', retrying in %v (%v/%v)", attempt, attempts, category, backoff, log.LibraryName, stepName)
+		sleep(backoff)
+		backoff *= 2
+		log.SetErrorCategory(log.ErrorUndefined)
+		reset()
+	}
+}
+
+func isRetryErrorCategory(category string, errorCategories []string) bool {
+	for _, c := range errorCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+func retrieveStepRetryConfig(source interface{}, target *StepRetryConfiguration) {
+	if source != nil {
+		log.Entry().Debug("Retrieving step retry configuration")
+		b, err := json.Marshal(source)
+		if err != nil {
+			log.Entry().Warningf("Failed to marshal step retry configuration: %v", err)
+		}
+		err = json.Unmarshal(b, target)
+		if err != nil {
+			log.Entry().Warningf("Failed to retrieve step retry configuration: %v", err)
+		}
+	}
+}
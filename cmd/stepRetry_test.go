@@ -0,0 +1,90 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunStepWithRetry(t *testing.T) {
+	defer log.SetErrorCategory(log.ErrorUndefined)
+
+	t.Run("success case - no retry configured", func(t *testing.T) {
+		telemetryData := telemetry.CustomData{}
+		runs := 0
+
+		runStepWithRetry("testStep", StepRetryConfiguration{}, &telemetryData, func() {}, func() { runs++ }, func(time.Duration) {})
+
+		assert.Equal(t, 1, runs)
+		assert.Equal(t, "1", telemetryData.Attempts)
+	})
+
+	t.Run("success case - retry on infrastructure error", func(t *testing.T) {
+		telemetryData := telemetry.CustomData{}
+		runs := 0
+		resets := 0
+		sleeps := []time.Duration{}
+		output := ""
+
+		runStepWithRetry("testStep", StepRetryConfiguration{Attempts: 3, Backoff: "1s"}, &telemetryData,
+			func() {
+				resets++
+				output = ""
+			},
+			func() {
+				runs++
+				output = "partial"
+				if runs < 3 {
+					log.SetErrorCategory(log.ErrorInfrastructure)
+					log.Entry().Fatal("temporary failure")
+				}
+				output = "complete"
+			},
+			func(d time.Duration) { sleeps = append(sleeps, d) })
+
+		assert.Equal(t, 3, runs)
+		assert.Equal(t, 2, resets)
+		assert.Equal(t, "complete", output)
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, sleeps)
+		assert.Equal(t, "3", telemetryData.Attempts)
+		assert.Equal(t, log.ErrorUndefined, log.GetErrorCategory())
+	})
+
+	t.Run("success case - custom error categories", func(t *testing.T) {
+		telemetryData := telemetry.CustomData{}
+		runs := 0
+
+		runStepWithRetry("testStep", StepRetryConfiguration{Attempts: 2, ErrorCategories: []string{"test"}}, &telemetryData, func() {},
+			func() {
+				runs++
+				if runs == 1 {
+					log.SetErrorCategory(log.ErrorTest)
+					log.Entry().Fatal("flaky test")
+				}
+			},
+			func(time.Duration) {})
+
+		assert.Equal(t, 2, runs)
+		assert.Equal(t, "2", telemetryData.Attempts)
+	})
+}
+
+func TestIsRetryErrorCategory(t *testing.T) {
+	assert.True(t, isRetryErrorCategory("service", defaultRetryErrorCategories))
+	assert.True(t, isRetryErrorCategory("infrastructure", defaultRetryErrorCategories))
+	assert.False(t, isRetryErrorCategory("config", defaultRetryErrorCategories))
+}
+
+func TestRetrieveStepRetryConfig(t *testing.T) {
+	target := StepRetryConfiguration{}
+
+	retrieveStepRetryConfig(map[string]interface{}{"attempts": 3, "backoff": "30s", "errorCategories": []interface{}{"service"}}, &target)
+
+	assert.Equal(t, StepRetryConfiguration{Attempts: 3, Backoff: "30s", ErrorCategories: []string{"service"}}, target)
+}
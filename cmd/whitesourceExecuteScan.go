@@ -101,6 +101,14 @@ func (w *whitesourceUtilsBundle) SetOptions(o piperhttp.ClientOptions) {
 	w.Client.SetOptions(o)
 }
 
+func (w *whitesourceUtilsBundle) Clone() ws.Utils {
+	clone := *w
+	clone.Command = &command.Command{}
+	clone.Stdout(log.Writer())
+	clone.Stderr(log.Writer())
+	return &clone
+}
+
 func (w *whitesourceUtilsBundle) Now() time.Time {
 	return time.Now()
 }
@@ -136,6 +144,7 @@ func newWhitesourceScan(config *ScanOptions) *ws.Scan {
 		AggregateProjectName: config.ProjectName,
 		ProductVersion:       config.Version,
 		BuildTool:            config.BuildTool,
+		RequestConcurrency:   config.RequestConcurrency,
 	}
 }
 
@@ -483,6 +492,7 @@ func wsScanOptions(config *ScanOptions) *ws.ScanOptions {
 		ServiceURL:                 config.ServiceURL,
 		ScanPath:                   config.ScanPath,
 		InstallCommand:             config.InstallCommand,
+		ScanConcurrency:            config.ScanConcurrency,
 		Verbose:                    GeneralConfig.Verbose,
 	}
 }
@@ -778,11 +788,18 @@ func reportGitHubIssuesAndCreateReports(
 
 	reportPaths = append(reportPaths, paths...)
 
+	paths, err = format.WriteVEX(ws.ReportsDirectory, "WhiteSource", ws.CreateVEXStatements(allAssessedAlerts), utils)
+	if err != nil {
+		errorsOccured = append(errorsOccured, fmt.Sprint(err))
+	}
+
+	reportPaths = append(reportPaths, paths...)
+
 	return reportPaths, errorsOccured
 }
 
 // read assessments from file and expose them to match alerts and filter them before processing
-func readAssessmentsFromFile(assessmentFilePath string, utils whitesourceUtils) *[]format.Assessment {
+func readAssessmentsFromFile(assessmentFilePath string, utils piperutils.FileUtils) *[]format.Assessment {
 	exists, err := utils.FileExists(assessmentFilePath)
 	if err != nil {
 		log.SetErrorCategory(log.ErrorConfiguration)
@@ -52,7 +52,9 @@ type whitesourceExecuteScanOptions struct {
 	Version                              string   `json:"version,omitempty"`
 	ProjectName                          string   `json:"projectName,omitempty"`
 	ProjectToken                         string   `json:"projectToken,omitempty"`
+	RequestConcurrency                   int      `json:"requestConcurrency,omitempty"`
 	Reporting                            bool     `json:"reporting,omitempty"`
+	ScanConcurrency                      int      `json:"scanConcurrency,omitempty"`
 	ScanImage                            string   `json:"scanImage,omitempty"`
 	ScanImageRegistryURL                 string   `json:"scanImageRegistryUrl,omitempty"`
 	SecurityVulnerabilities              bool     `json:"securityVulnerabilities,omitempty"`
@@ -169,6 +171,7 @@ func (p *whitesourceExecuteScanReports) persist(stepConfig whitesourceExecuteSca
 		{FilePattern: "**/toolrun_whitesource_*.json", ParamRef: "", StepResultType: "whitesource-security"},
 		{FilePattern: "**/piper_whitesource_vulnerability.sarif", ParamRef: "", StepResultType: "whitesource-security"},
 		{FilePattern: "**/piper_whitesource_sbom.xml", ParamRef: "", StepResultType: "whitesource-security"},
+		{FilePattern: "**/whitesource/piper_vex.json", ParamRef: "", StepResultType: "whitesource-security"},
 	}
 	envVars := []gcs.EnvVar{
 		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
@@ -278,6 +281,7 @@ The step uses the so-called Mend Unified Agent. For details please refer to the
 				influx.persist(GeneralConfig.EnvRootPath, "influx")
 				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
 				config.RemoveVaultSecretFiles()
+				RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
 				stepTelemetryData.PiperCommitHash = GitCommit
@@ -303,7 +307,13 @@ The step uses the so-called Mend Unified Agent. For details please refer to the
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
-			whitesourceExecuteScan(stepConfig, &stepTelemetryData, &commonPipelineEnvironment, &influx)
+			RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+				commonPipelineEnvironment = whitesourceExecuteScanCommonPipelineEnvironment{}
+				influx = whitesourceExecuteScanInflux{}
+			}, func() {
+				whitesourceExecuteScan(stepConfig, &stepTelemetryData, &commonPipelineEnvironment, &influx)
+			})
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
@@ -344,7 +354,9 @@ func addWhitesourceExecuteScanFlags(cmd *cobra.Command, stepConfig *whitesourceE
 	cmd.Flags().StringVar(&stepConfig.Version, "version", os.Getenv("PIPER_version"), "Version of the WhiteSource product to be created and used for results aggregation.")
 	cmd.Flags().StringVar(&stepConfig.ProjectName, "projectName", os.Getenv("PIPER_projectName"), "The project name used for reporting results in WhiteSource. When provided, all source modules will be scanned into one aggregated WhiteSource project. For scan types `maven`, `mta`, `npm`, the default is to generate one WhiteSource project per module, whereas the project name is derived from the module's build descriptor. For NPM modules, project aggregation is not supported, the last scanned NPM module will override all previously aggregated scan results!")
 	cmd.Flags().StringVar(&stepConfig.ProjectToken, "projectToken", os.Getenv("PIPER_projectToken"), "Project token to execute scan on. Ignored for scan types `maven`, `mta` and `npm`. Used for project aggregation when scanning with the Unified Agent and can be provided as an alternative to `projectName`.")
+	cmd.Flags().IntVar(&stepConfig.RequestConcurrency, "requestConcurrency", 5, "Number of projects which are polled for their status and whose reports are downloaded at the same time. Requests which are rejected by the backend due to rate limiting are retried after a back-off which applies to all concurrent requests.")
 	cmd.Flags().BoolVar(&stepConfig.Reporting, "reporting", true, "Whether assessment is being done at all, defaults to `true`")
+	cmd.Flags().IntVar(&stepConfig.ScanConcurrency, "scanConcurrency", 1, "For `buildTool: mta`: Number of modules (the Maven modules as a whole and each npm module) which are scanned with the Unified Agent at the same time. Each execution starts its own Java process, the available memory needs to be considered.")
 	cmd.Flags().StringVar(&stepConfig.ScanImage, "scanImage", os.Getenv("PIPER_scanImage"), "For `buildTool: docker`: Defines the docker image which should be scanned.")
 	cmd.Flags().StringVar(&stepConfig.ScanImageRegistryURL, "scanImageRegistryUrl", os.Getenv("PIPER_scanImageRegistryUrl"), "For `buildTool: docker`: Defines the registry where the scanImage is located.")
 	cmd.Flags().BoolVar(&stepConfig.SecurityVulnerabilities, "securityVulnerabilities", true, "Whether security compliance is considered and reported as part of the assessment.")
@@ -722,6 +734,15 @@ func whitesourceExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_projectToken"),
 					},
+					{
+						Name:        "requestConcurrency",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     5,
+					},
 					{
 						Name:        "reporting",
 						ResourceRef: []config.ResourceReference{},
@@ -731,6 +752,15 @@ func whitesourceExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     true,
 					},
+					{
+						Name:        "scanConcurrency",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     1,
+					},
 					{
 						Name: "scanImage",
 						ResourceRef: []config.ResourceReference{
@@ -1009,6 +1039,7 @@ func whitesourceExecuteScanMetadata() config.StepData {
 							{"filePattern": "**/toolrun_whitesource_*.json", "type": "whitesource-security"},
 							{"filePattern": "**/piper_whitesource_vulnerability.sarif", "type": "whitesource-security"},
 							{"filePattern": "**/piper_whitesource_sbom.xml", "type": "whitesource-security"},
+							{"filePattern": "**/whitesource/piper_vex.json", "type": "whitesource-security"},
 						},
 					},
 				},
@@ -10,7 +10,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/package-url/packageurl-go"
 	"github.com/pkg/errors"
@@ -123,9 +125,11 @@ type Vulnerability struct {
 	Version                      string `json:"componentVersionName,omitempty"`
 	ComponentVersionOriginID     string `json:"componentVersionOriginId,omitempty"`
 	ComponentVersionOriginName   string `json:"componentVersionOriginName,omitempty"`
+	ComponentVersion             string `json:"componentVersion,omitempty"`
 	Ignored                      bool   `json:"ignored,omitempty"`
 	VulnerabilityWithRemediation `json:"vulnerabilityWithRemediation,omitempty"`
 	Component                    *Component
+	Assessment                   *format.Assessment `json:"-"`
 	projectName                  string
 	projectVersion               string
 	projectVersionLink           string
@@ -145,6 +149,45 @@ type VulnerabilityWithRemediation struct {
 	RelatedVulnerability   string  `json:"relatedVulnerability,omitempty"`
 }
 
+// RemediatingVersions defines the versions of a component which remediate the vulnerabilities of a given component version
+type RemediatingVersions struct {
+	FixesPreviousVulnerabilities *RemediatingVersion `json:"fixesPreviousVulnerabilities,omitempty"`
+	LatestAfterCurrent           *RemediatingVersion `json:"latestAfterCurrent,omitempty"`
+	NoVulnerabilities            *RemediatingVersion `json:"noVulnerabilities,omitempty"`
+}
+
+// RemediatingVersion defines a component version proposed for remediation
+type RemediatingVersion struct {
+	Name               string `json:"name,omitempty"`
+	ComponentVersion   string `json:"componentVersion,omitempty"`
+	VulnerabilityCount int    `json:"vulnerabilityCount,omitempty"`
+}
+
+// ContainedIn checks whether the vulnerability is covered by one of the assessments and attaches the matching assessment.
+// Besides the BlackDuck vulnerability name (e.g. BDSA-2020-1234) the related vulnerability (e.g. the CVE) is considered.
+func (v *Vulnerability) ContainedIn(assessments *[]format.Assessment) (bool, error) {
+	if v.Component == nil {
+		return false, nil
+	}
+	ids := []string{v.VulnerabilityWithRemediation.VulnerabilityName}
+	if len(v.VulnerabilityWithRemediation.RelatedVulnerability) > 0 {
+		// the related vulnerability is provided as link, e.g. https://my.blackduck.system/api/vulnerabilities/CVE-2021-44228
+		ids = append(ids, path.Base(v.VulnerabilityWithRemediation.RelatedVulnerability))
+	}
+	assessment, err := format.FindAssessment(assessments, v.Component.ToPackageUrl(), ids...)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		log.Entry().WithError(err).Error("assessment from file ignored")
+		return false, err
+	}
+	if assessment != nil {
+		log.Entry().Debugf("matching assessment %v on package %v detected for vulnerability %v", assessment.Vulnerability, v.Component.ToPackageUrl().ToString(), v.VulnerabilityWithRemediation.VulnerabilityName)
+		v.Assessment = assessment
+		return true, nil
+	}
+	return false, nil
+}
+
 // Title returns the issue title representation of the contents
 func (v Vulnerability) Title() string {
 	return v.VulnerabilityWithRemediation.VulnerabilityName
@@ -447,6 +490,31 @@ func (b *Client) GetVulnerabilities(projectName, versionName string) (*Vulnerabi
 	return &vulnerabilities, nil
 }
 
+// GetRemediatingVersions returns the versions of a component which remediate the vulnerabilities of the given component version.
+// The component version is expected as link as provided by the vulnerable components of a project version.
+func (b *Client) GetRemediatingVersions(componentVersion string) (*RemediatingVersions, error) {
+	if !b.authenticationValid(time.Now()) {
+		if err := b.authenticate(); err != nil {
+			return nil, err
+		}
+	}
+	headers := http.Header{}
+	headers.Add("Accept", HEADER_BOM_V6)
+
+	respBody, err := b.sendRequest("GET", path.Join(urlPath(componentVersion), "remediating"), map[string]string{}, nil, headers)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get remediating versions for '%v'", componentVersion)
+	}
+
+	remediatingVersions := RemediatingVersions{}
+	err = json.Unmarshal(respBody, &remediatingVersions)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve remediating versions for '%v'", componentVersion)
+	}
+
+	return &remediatingVersions, nil
+}
+
 func (b *Client) GetPolicyStatus(projectName, versionName string) (*PolicyStatus, error) {
 	projectVersion, err := b.GetProjectVersion(projectName, versionName)
 	if err != nil {
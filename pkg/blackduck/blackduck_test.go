@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/stretchr/testify/assert"
 )
@@ -481,6 +482,40 @@ func TestGetPolicyStatus(t *testing.T) {
 	})
 }
 
+func TestGetRemediatingVersions(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		myTestClient := httpMockClient{
+			responseBodyForURL: map[string]string{
+				"https://my.blackduck.system/api/tokens/authenticate": authContent,
+				"https://my.blackduck.system/api/components/c1/versions/v1/remediating": `{
+					"fixesPreviousVulnerabilities": {"name": "5.3.18", "componentVersion": "https://my.blackduck.system/api/components/c1/versions/v2", "vulnerabilityCount": 1},
+					"noVulnerabilities": {"name": "5.3.20", "componentVersion": "https://my.blackduck.system/api/components/c1/versions/v3"}
+				}`,
+			},
+			header: map[string]http.Header{},
+		}
+		bdClient := NewClient("token", "https://my.blackduck.system", &myTestClient)
+		versions, err := bdClient.GetRemediatingVersions("https://my.blackduck.system/api/components/c1/versions/v1")
+		assert.NoError(t, err)
+		assert.Equal(t, "5.3.18", versions.FixesPreviousVulnerabilities.Name)
+		assert.Equal(t, "5.3.20", versions.NoVulnerabilities.Name)
+		assert.Nil(t, versions.LatestAfterCurrent)
+	})
+
+	t.Run("failure - unmarshalling", func(t *testing.T) {
+		myTestClient := httpMockClient{
+			responseBodyForURL: map[string]string{
+				"https://my.blackduck.system/api/tokens/authenticate":                   authContent,
+				"https://my.blackduck.system/api/components/c1/versions/v1/remediating": "",
+			},
+			header: map[string]http.Header{},
+		}
+		bdClient := NewClient("token", "https://my.blackduck.system", &myTestClient)
+		_, err := bdClient.GetRemediatingVersions("https://my.blackduck.system/api/components/c1/versions/v1")
+		assert.Contains(t, fmt.Sprint(err), "failed to retrieve remediating versions")
+	})
+}
+
 func TestGetProjectVersionLink(t *testing.T) {
 	t.Run("Success Case", func(t *testing.T) {
 		myTestClient := httpMockClient{
@@ -750,3 +785,50 @@ func TestComponentToPackageUrl(t *testing.T) {
 		})
 	}
 }
+
+func TestVulnerabilityContainedIn(t *testing.T) {
+	assessments := []format.Assessment{
+		{Vulnerability: "CVE-2021-44228", Status: format.NotRelevant, Analysis: format.NotUsed, Purls: []format.Purl{{Purl: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.0"}}},
+	}
+	component := &Component{
+		Name:    "log4j-core",
+		Version: "2.14.0",
+		Origins: []ComponentOrigin{{ExternalNamespace: "maven", ExternalID: "org.apache.logging.log4j:log4j-core:2.14.0"}},
+	}
+
+	t.Run("match via related vulnerability", func(t *testing.T) {
+		vuln := Vulnerability{
+			Component: component,
+			VulnerabilityWithRemediation: VulnerabilityWithRemediation{
+				VulnerabilityName:    "BDSA-2021-3646",
+				RelatedVulnerability: "https://my.blackduck.system/api/vulnerabilities/CVE-2021-44228",
+			},
+		}
+		contained, err := vuln.ContainedIn(&assessments)
+		assert.NoError(t, err)
+		assert.True(t, contained)
+		assert.Equal(t, &assessments[0], vuln.Assessment)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		vuln := Vulnerability{
+			Component:                    component,
+			VulnerabilityWithRemediation: VulnerabilityWithRemediation{VulnerabilityName: "CVE-2021-45046"},
+		}
+		contained, err := vuln.ContainedIn(&assessments)
+		assert.NoError(t, err)
+		assert.False(t, contained)
+		assert.Nil(t, vuln.Assessment)
+	})
+
+	t.Run("invalid assessment", func(t *testing.T) {
+		invalid := []format.Assessment{{Vulnerability: "CVE-2021-44228", Purls: []format.Purl{{Purl: "invalid"}}}}
+		vuln := Vulnerability{
+			Component:                    component,
+			VulnerabilityWithRemediation: VulnerabilityWithRemediation{VulnerabilityName: "CVE-2021-44228"},
+		}
+		contained, err := vuln.ContainedIn(&invalid)
+		assert.Error(t, err)
+		assert.False(t, contained)
+	})
+}
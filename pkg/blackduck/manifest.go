@@ -0,0 +1,186 @@
+package blackduck
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/pkg/errors"
+	"golang.org/x/mod/modfile"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// manifestPatterns defines the build descriptors declaring the dependencies per package type
+var manifestPatterns = map[string]string{
+	packageurl.TypeNPM:    "**/package.json",
+	packageurl.TypeMaven:  "**/pom.xml",
+	packageurl.TypeGolang: "**/go.mod",
+}
+
+// excludedManifestDirectories contain build descriptors of dependencies or build results rather than of the scanned sources
+var excludedManifestDirectories = []string{"node_modules", "target", "vendor", ".git", ".pipeline"}
+
+type manifest struct {
+	path  string
+	lines []string
+}
+
+// ManifestLocator finds the declaration of dependencies in the build descriptors (package.json, pom.xml, go.mod) of the scanned sources
+type ManifestLocator struct {
+	manifests map[string][]manifest
+}
+
+// NewManifestLocator reads the build descriptors within the current directory
+func NewManifestLocator(utils piperutils.FileUtils) (*ManifestLocator, error) {
+	locator := ManifestLocator{manifests: map[string][]manifest{}}
+	for purlType, pattern := range manifestPatterns {
+		paths, err := utils.Glob(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to search for '%v'", pattern)
+		}
+		for _, manifestPath := range paths {
+			manifestPath = filepath.ToSlash(manifestPath)
+			if isExcludedManifest(manifestPath) {
+				continue
+			}
+			content, err := utils.FileRead(manifestPath)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read '%v'", manifestPath)
+			}
+			locator.manifests[purlType] = append(locator.manifests[purlType], manifest{path: manifestPath, lines: strings.Split(string(content), "\n")})
+		}
+		// the topmost build descriptor comes first since it is used for dependencies which are not declared directly
+		sort.SliceStable(locator.manifests[purlType], func(i, j int) bool {
+			return strings.Count(locator.manifests[purlType][i].path, "/") < strings.Count(locator.manifests[purlType][j].path, "/")
+		})
+	}
+	return &locator, nil
+}
+
+func isExcludedManifest(manifestPath string) bool {
+	for _, segment := range strings.Split(path.Dir(manifestPath), "/") {
+		if piperutils.ContainsString(excludedManifestDirectories, segment) {
+			return true
+		}
+	}
+	return false
+}
+
+// Locate returns the location of the declaration of the package identified by the package URL.
+// For packages which are not declared directly, e.g. transitive dependencies, the topmost build descriptor of the package type is returned without region.
+func (l *ManifestLocator) Locate(purl string) (format.Location, bool) {
+	packageURL, err := packageurl.FromString(purl)
+	if err != nil {
+		return format.Location{}, false
+	}
+	manifests := l.manifests[packageURL.Type]
+	for _, manifest := range manifests {
+		if line := manifest.declaration(packageURL); line > 0 {
+			return manifestLocation(manifest.path, line, purl), true
+		}
+	}
+	if len(manifests) > 0 {
+		return manifestLocation(manifests[0].path, 0, purl), true
+	}
+	return format.Location{}, false
+}
+
+// AddLocations sets the location of the results to the declaration of the package the result has been reported for, which is the analysis target.
+// Results of packages of other types than npm, Maven and Go keep their location.
+func (l *ManifestLocator) AddLocations(sarif *format.SARIF) {
+	for i := range sarif.Runs {
+		for j := range sarif.Runs[i].Results {
+			result := &sarif.Runs[i].Results[j]
+			if result.AnalysisTarget == nil {
+				continue
+			}
+			if location, found := l.Locate(result.AnalysisTarget.URI); found {
+				result.Locations = []format.Location{location}
+			} else {
+				log.Entry().Debugf("no build descriptor found for package %v", result.AnalysisTarget.URI)
+			}
+		}
+	}
+}
+
+func manifestLocation(manifestPath string, line int, purl string) format.Location {
+	return format.Location{PhysicalLocation: format.PhysicalLocation{
+		ArtifactLocation: format.ArtifactLocation{URI: manifestPath},
+		Region:           format.Region{StartLine: line},
+		LogicalLocations: []format.LogicalLocation{{FullyQualifiedName: purl}},
+	}}
+}
+
+// declaration returns the line declaring the package or 0 if the package is not declared in the build descriptor
+func (m manifest) declaration(packageURL packageurl.PackageURL) int {
+	switch packageURL.Type {
+	case packageurl.TypeNPM:
+		name := packageURL.Name
+		if len(packageURL.Namespace) > 0 {
+			name = packageURL.Namespace + "/" + name
+		}
+		return m.npmDeclaration(name)
+	case packageurl.TypeMaven:
+		return m.mavenDeclaration(packageURL.Namespace, packageURL.Name)
+	case packageurl.TypeGolang:
+		return m.goDeclaration(strings.Trim(packageURL.Namespace+"/"+packageURL.Name, "/"))
+	}
+	return 0
+}
+
+func (m manifest) npmDeclaration(name string) int {
+	declaration := regexp.MustCompile(`^\s*"` + regexp.QuoteMeta(name) + `"\s*:`)
+	for i, line := range m.lines {
+		if declaration.MatchString(line) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// mavenDeclaration returns the line of the artifactId within the dependency declaring groupId and artifactId
+func (m manifest) mavenDeclaration(groupID, artifactID string) int {
+	groupElement := "<groupId>" + groupID + "</groupId>"
+	artifactElement := "<artifactId>" + artifactID + "</artifactId>"
+	inDependency, hasGroup, artifactLine := false, false, 0
+	for i, line := range m.lines {
+		switch {
+		case strings.Contains(line, "<dependency>"):
+			inDependency, hasGroup, artifactLine = true, false, 0
+		case strings.Contains(line, "</dependency>"):
+			inDependency = false
+		}
+		if !inDependency {
+			continue
+		}
+		if strings.Contains(line, groupElement) {
+			hasGroup = true
+		}
+		if strings.Contains(line, artifactElement) {
+			artifactLine = i + 1
+		}
+		if hasGroup && artifactLine > 0 {
+			return artifactLine
+		}
+	}
+	return 0
+}
+
+func (m manifest) goDeclaration(modulePath string) int {
+	goMod, err := modfile.ParseLax(m.path, []byte(strings.Join(m.lines, "\n")), nil)
+	if err != nil {
+		return 0
+	}
+	for _, require := range goMod.Require {
+		if require.Mod.Path == modulePath && require.Syntax != nil {
+			return require.Syntax.Start.Line
+		}
+	}
+	return 0
+}
@@ -0,0 +1,92 @@
+//go:build unit
+// +build unit
+
+package blackduck
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifestLocator(t *testing.T) {
+	utils := &mock.FilesMock{}
+	utils.AddFile("package.json", []byte(`{
+  "name": "app",
+  "dependencies": {
+    "@babel/core": "^7.0.0",
+    "lodash": "4.17.11"
+  }
+}`))
+	utils.AddFile("node_modules/lodash/package.json", []byte(`{
+  "name": "lodash",
+  "dependencies": {
+    "lodash": "4.17.11"
+  }
+}`))
+	utils.AddFile("backend/pom.xml", []byte(`<project>
+  <groupId>com.sap</groupId>
+  <artifactId>backend</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>org.other</groupId>
+      <artifactId>log4j-core</artifactId>
+    </dependency>
+    <dependency>
+      <artifactId>log4j-core</artifactId>
+      <groupId>org.apache.logging.log4j</groupId>
+    </dependency>
+  </dependencies>
+</project>`))
+	utils.AddFile("tool/go.mod", []byte(`module github.com/SAP/tool
+
+go 1.19
+
+require (
+	github.com/pkg/errors v0.9.1
+	golang.org/x/mod v0.12.0
+)`))
+
+	locator, err := NewManifestLocator(utils)
+	require.NoError(t, err)
+
+	tt := []struct {
+		purl          string
+		expectedFound bool
+		expectedURI   string
+		expectedLine  int
+	}{
+		{purl: "pkg:npm/lodash@4.17.11", expectedFound: true, expectedURI: "package.json", expectedLine: 5},
+		{purl: "pkg:npm/%40babel/core@7.0.0", expectedFound: true, expectedURI: "package.json", expectedLine: 4},
+		{purl: "pkg:npm/express@4.0.0", expectedFound: true, expectedURI: "package.json"},
+		{purl: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", expectedFound: true, expectedURI: "backend/pom.xml", expectedLine: 10},
+		{purl: "pkg:golang/golang.org/x/mod@v0.12.0", expectedFound: true, expectedURI: "tool/go.mod", expectedLine: 7},
+		{purl: "pkg:pypi/requests@2.0.0", expectedFound: false},
+		{purl: "invalid", expectedFound: false},
+	}
+	for _, test := range tt {
+		location, found := locator.Locate(test.purl)
+		assert.Equal(t, test.expectedFound, found, test.purl)
+		if test.expectedFound {
+			assert.Equal(t, test.expectedURI, location.PhysicalLocation.ArtifactLocation.URI, test.purl)
+			assert.Equal(t, test.expectedLine, location.PhysicalLocation.Region.StartLine, test.purl)
+			assert.Equal(t, test.purl, location.PhysicalLocation.LogicalLocations[0].FullyQualifiedName, test.purl)
+		}
+	}
+
+	t.Run("add locations", func(t *testing.T) {
+		sarif := format.SARIF{Runs: []format.Runs{{Results: []format.Results{
+			{RuleID: "CVE-1", AnalysisTarget: &format.ArtifactLocation{URI: "pkg:npm/lodash@4.17.11"}, Locations: []format.Location{{PhysicalLocation: format.PhysicalLocation{ArtifactLocation: format.ArtifactLocation{URI: "lodash"}}}}},
+			{RuleID: "CVE-2", AnalysisTarget: &format.ArtifactLocation{URI: "pkg:generic/zlib@1.2.11"}, Locations: []format.Location{{PhysicalLocation: format.PhysicalLocation{ArtifactLocation: format.ArtifactLocation{URI: "zlib"}}}}},
+		}}}}
+
+		locator.AddLocations(&sarif)
+
+		assert.Equal(t, "package.json", sarif.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Equal(t, 5, sarif.Runs[0].Results[0].Locations[0].PhysicalLocation.Region.StartLine)
+		assert.Equal(t, "zlib", sarif.Runs[0].Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	})
+}
@@ -16,6 +16,26 @@ import (
 
 var severityIndex = map[string]int{"LOW": 1, "MEDIUM": 2, "HIGH": 3, "CRITICAL": 4}
 
+const policyViolationRuleID = "BLACKDUCK_POLICY_VIOLATION"
+
+// CreateVEXStatements returns the VEX statements for the assessed vulnerabilities
+func CreateVEXStatements(vulns *Vulnerabilities) []format.VEXStatement {
+	statements := []format.VEXStatement{}
+	if vulns == nil {
+		return statements
+	}
+	for _, v := range vulns.Items {
+		if v.Assessment != nil && v.Component != nil {
+			statements = append(statements, format.VEXStatement{
+				Vulnerability: v.VulnerabilityWithRemediation.VulnerabilityName,
+				Purl:          v.Component.ToPackageUrl().ToString(),
+				Assessment:    *v.Assessment,
+			})
+		}
+	}
+	return statements
+}
+
 // CreateSarifResultFile creates a SARIF result from the Vulnerabilities that were brought up by the scan
 func CreateSarifResultFile(vulns *Vulnerabilities, projectName, projectVersion, projectLink string) *format.SARIF {
 	log.Entry().Debug("Creating SARIF file for data transfer")
@@ -56,6 +76,13 @@ func CreateSarifResultFile(vulns *Vulnerabilities, projectName, projectVersion,
 				unifiedStatusValue = "relevant"
 			}
 
+			auditMessage := v.VulnerabilityWithRemediation.RemediationComment
+			if v.Assessment != nil {
+				unifiedStatusValue = string(v.Assessment.Status)
+				auditMessage = string(v.Assessment.Analysis)
+				isAudited = v.Assessment.Status == format.Relevant || v.Assessment.Status == format.NotRelevant
+			}
+
 			log.Entry().Debugf("Transforming alert %v on Package %v Version %v into SARIF format", v.VulnerabilityWithRemediation.VulnerabilityName, v.Component.Name, v.Component.Version)
 			result := format.Results{
 				RuleID:  v.VulnerabilityWithRemediation.VulnerabilityName,
@@ -73,7 +100,7 @@ func CreateSarifResultFile(vulns *Vulnerabilities, projectName, projectVersion,
 					Audited:           isAudited,
 					ToolSeverity:      v.Severity,
 					ToolSeverityIndex: severityIndex[v.Severity],
-					ToolAuditMessage:  v.VulnerabilityWithRemediation.RemediationComment,
+					ToolAuditMessage:  auditMessage,
 					ToolState:         v.RemediationStatus,
 					UnifiedAuditState: unifiedStatusValue,
 				},
@@ -185,6 +212,74 @@ func CreateSarifResultFile(vulns *Vulnerabilities, projectName, projectVersion,
 	return &sarif
 }
 
+// CreatePolicyViolationSarifResultFile creates a SARIF result from the components violating policies of the BlackDuck project
+func CreatePolicyViolationSarifResultFile(components *Components, projectName, projectVersion, projectLink string) *format.SARIF {
+	log.Entry().Debug("Creating SARIF file for policy violations")
+
+	results := []format.Results{}
+	if components != nil {
+		for _, c := range components.Items {
+			if c.PolicyStatus != "IN_VIOLATION" && c.PolicyStatus != "IN_VIOLATION_OVERRIDDEN" {
+				continue
+			}
+			overridden := c.PolicyStatus == "IN_VIOLATION_OVERRIDDEN"
+			level, unifiedStatusValue := "error", "new"
+			if overridden {
+				level, unifiedStatusValue = "note", "notRelevant"
+			}
+			purl := c.ToPackageUrl().ToString()
+
+			log.Entry().Debugf("Transforming policy violation of Package %v Version %v into SARIF format", c.Name, c.Version)
+			results = append(results, format.Results{
+				RuleID:  policyViolationRuleID,
+				Level:   level,
+				Message: &format.Message{Text: fmt.Sprintf("Package %v %v violates policies of BlackDuck project %v version %v", c.Name, c.Version, projectName, projectVersion)},
+				AnalysisTarget: &format.ArtifactLocation{
+					URI: purl,
+				},
+				Locations: []format.Location{{PhysicalLocation: format.PhysicalLocation{ArtifactLocation: format.ArtifactLocation{URI: c.Name}}}},
+				PartialFingerprints: format.PartialFingerprints{
+					PackageURLPlusCVEHash: base64.URLEncoding.EncodeToString([]byte(fmt.Sprintf("%v+%v", purl, policyViolationRuleID))),
+				},
+				Properties: &format.SarifProperties{
+					Audited:           overridden,
+					ToolState:         c.PolicyStatus,
+					UnifiedAuditState: unifiedStatusValue,
+				},
+			})
+		}
+	}
+
+	rule := format.SarifRule{
+		ID:                   policyViolationRuleID,
+		ShortDescription:     &format.Message{Text: "Package violates BlackDuck policies"},
+		FullDescription:      &format.Message{Text: "The package violates policies, e.g. license or operational policies, defined for the BlackDuck project."},
+		DefaultConfiguration: &format.DefaultConfiguration{Level: "error"},
+		HelpURI:              projectLink,
+		Help:                 &format.Help{Text: fmt.Sprintf("Please check the policy violations of the package in BlackDuck project %v version %v: %v", projectName, projectVersion, projectLink)},
+		Properties:           &format.SarifRuleProperties{Tags: []string{"POLICY_VIOLATION"}, Precision: "very-high"},
+	}
+
+	return &format.SARIF{
+		Schema:  "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []format.Runs{
+			{
+				Results: results,
+				Tool: format.Tool{
+					Driver: format.Driver{
+						Name:           "Black Duck",
+						Version:        "unknown",
+						InformationUri: "https://community.synopsys.com/s/document-item?bundleId=integrations-detect&topicId=introduction.html&_LANG=enus",
+						Rules:          []format.SarifRule{rule},
+					},
+				},
+				ThreadFlowLocations: []format.Locations{},
+			},
+		},
+	}
+}
+
 func transformToLevel(severity string) string {
 	switch severity {
 	case "LOW":
@@ -227,6 +322,15 @@ func WriteVulnerabilityReports(scanReport reporting.ScanReport, utils piperutils
 
 // WriteSarifFile write a JSON sarif format file for upload into e.g. GCP
 func WriteSarifFile(sarif *format.SARIF, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	return writeSarifFile(sarif, "piper_detect_vulnerability.sarif", "Blackduck Detect Vulnerability SARIF file", utils)
+}
+
+// WritePolicyViolationSarifFile writes the SARIF file of the policy violations
+func WritePolicyViolationSarifFile(sarif *format.SARIF, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	return writeSarifFile(sarif, "piper_detect_policy_violation.sarif", "Blackduck Detect Policy Violation SARIF file", utils)
+}
+
+func writeSarifFile(sarif *format.SARIF, fileName, name string, utils piperutils.FileUtils) ([]piperutils.Path, error) {
 	reportPaths := []piperutils.Path{}
 
 	// ignore templating errors since template is in our hands and issues will be detected with the automated tests
@@ -238,12 +342,12 @@ func WriteSarifFile(sarif *format.SARIF, utils piperutils.FileUtils) ([]piperuti
 		return reportPaths, errors.Wrapf(err, "failed to create report directory")
 	}
 
-	sarifReportPath := filepath.Join(ReportsDirectory, "piper_detect_vulnerability.sarif")
+	sarifReportPath := filepath.Join(ReportsDirectory, fileName)
 	if err := utils.FileWrite(sarifReportPath, sarifReport, 0666); err != nil {
 		log.SetErrorCategory(log.ErrorConfiguration)
 		return reportPaths, errors.Wrapf(err, "failed to write SARIF file")
 	}
-	reportPaths = append(reportPaths, piperutils.Path{Name: "Blackduck Detect Vulnerability SARIF file", Target: sarifReportPath})
+	reportPaths = append(reportPaths, piperutils.Path{Name: name, Target: sarifReportPath})
 
 	return reportPaths, nil
 }
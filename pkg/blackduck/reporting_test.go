@@ -138,6 +138,32 @@ func TestCreateSarifResultFile(t *testing.T) {
 	assert.Equal(t, vulnerabilities, collectedRules)
 }
 
+func TestCreatePolicyViolationSarifResultFile(t *testing.T) {
+	components := Components{Items: []Component{
+		{Name: "lodash", Version: "4.17.11", PolicyStatus: "IN_VIOLATION", Origins: []ComponentOrigin{{ExternalNamespace: "npmjs", ExternalID: "lodash/4.17.11"}}},
+		{Name: "log4j", Version: "2.14.1", PolicyStatus: "IN_VIOLATION_OVERRIDDEN"},
+		{Name: "express", Version: "4.0.0", PolicyStatus: "NOT_IN_VIOLATION"},
+	}}
+
+	sarif := CreatePolicyViolationSarifResultFile(&components, "theProject", "1.0", "https://my.blackduck.system/api/projects/1/versions/1")
+
+	results := sarif.Runs[0].Results
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "BLACKDUCK_POLICY_VIOLATION", results[0].RuleID)
+		assert.Equal(t, "error", results[0].Level)
+		assert.Equal(t, "pkg:npm/lodash@4.17.11", results[0].AnalysisTarget.URI)
+		assert.Equal(t, "Package lodash 4.17.11 violates policies of BlackDuck project theProject version 1.0", results[0].Message.Text)
+		assert.False(t, results[0].Properties.Audited)
+		assert.Equal(t, "new", results[0].Properties.UnifiedAuditState)
+
+		assert.Equal(t, "note", results[1].Level)
+		assert.True(t, results[1].Properties.Audited)
+		assert.Equal(t, "notRelevant", results[1].Properties.UnifiedAuditState)
+	}
+	assert.Len(t, sarif.Runs[0].Tool.Driver.Rules, 1)
+	assert.Equal(t, "https://my.blackduck.system/api/projects/1/versions/1", sarif.Runs[0].Tool.Driver.Rules[0].HelpURI)
+}
+
 func TestWriteCustomVulnerabilityReports(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
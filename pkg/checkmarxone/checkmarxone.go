@@ -299,6 +299,7 @@ type System interface {
 	GetScanResults(scanID string, limit uint64) ([]ScanResult, error)
 	GetScanSummary(scanID string) (ScanSummary, error)
 	GetResultsPredicates(SimilarityID int64, ProjectID string) ([]ResultsPredicates, error)
+	AddResultsPredicates(predicates []ResultsPredicates) error
 	GetScanWorkflow(scanID string) ([]WorkflowLog, error)
 	GetLastScans(projectID string, limit int) ([]Scan, error)
 	GetLastScansByStatus(projectID string, limit int, status []string) ([]Scan, error)
@@ -1273,6 +1274,49 @@ func (sys *SystemInstance) GetResultsPredicates(SimilarityID int64, ProjectID st
 	return Predicates.PredicateHistoryPerProject[0].Predicates, err
 }
 
+// AddResultsPredicates sets state, severity and comment of the results identified by similarity ID and project
+func (sys *SystemInstance) AddResultsPredicates(predicates []ResultsPredicates) error {
+	if len(predicates) == 0 {
+		return nil
+	}
+	sys.logger.Debugf("Adding %d results predicates", len(predicates))
+
+	type predicateRequest struct {
+		SimilarityID int64  `json:"similarityId,string"`
+		ProjectID    string `json:"projectId"`
+		Severity     string `json:"severity"`
+		State        string `json:"state"`
+		Comment      string `json:"comment"`
+	}
+
+	requestBody := []predicateRequest{}
+	for _, predicate := range predicates {
+		requestBody = append(requestBody, predicateRequest{
+			SimilarityID: predicate.SimilarityID,
+			ProjectID:    predicate.ProjectID,
+			Severity:     predicate.Severity,
+			State:        predicate.State,
+			Comment:      predicate.Comment,
+		})
+	}
+
+	jsonValue, err := json.Marshal(requestBody)
+	if err != nil {
+		sys.logger.Errorf("Failed to marshal results predicates.")
+		return err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	_, err = sendRequest(sys, http.MethodPost, "/sast-results-predicates", bytes.NewReader(jsonValue), header, []int{})
+	if err != nil {
+		sys.logger.Errorf("Failed to add results predicates: %s", err)
+		return errors.Wrap(err, "failed to add results predicates")
+	}
+
+	return nil
+}
+
 // RequestNewReport triggers the generation of a  report for a specific scan addressed by scanID
 func (sys *SystemInstance) RequestNewReport(scanID, projectID, branch, reportType string) (string, error) {
 	jsonData := map[string]interface{}{
@@ -294,3 +294,38 @@ func TestGetApplicationByName(t *testing.T) {
 		assert.Contains(t, fmt.Sprint(err), "Provoked technical error")
 	})
 }
+
+func TestAddResultsPredicates(t *testing.T) {
+	logger := log.Entry().WithField("package", "SAP/jenkins-library/pkg/checkmarxOne_test")
+	opts := piperHttp.ClientOptions{}
+	t.Run("test success", func(t *testing.T) {
+		myTestClient := senderMock{httpStatusCode: 201}
+		sys := SystemInstance{serverURL: "https://cx1.server.com", iamURL: "https://cx1iam.server.com", tenant: "tenant", client: &myTestClient, logger: logger}
+		myTestClient.SetOptions(opts)
+
+		err := sys.AddResultsPredicates([]ResultsPredicates{{SimilarityID: -1234, ProjectID: "eac4dc3b-4bbf-4d04-87e5-3b3cedae38fb", State: "NOT_EXPLOITABLE", Severity: "HIGH", Comment: "sanitized by framework"}})
+		assert.NoError(t, err, "Error occurred but none expected")
+		assert.Equal(t, "https://cx1.server.com/api/sast-results-predicates", myTestClient.urlCalled, "Called url incorrect")
+		assert.Equal(t, http.MethodPost, myTestClient.httpMethod, "HTTP method incorrect")
+		assert.Equal(t, `[{"similarityId":"-1234","projectId":"eac4dc3b-4bbf-4d04-87e5-3b3cedae38fb","severity":"HIGH","state":"NOT_EXPLOITABLE","comment":"sanitized by framework"}]`, myTestClient.requestBody, "Request body incorrect")
+	})
+
+	t.Run("test no predicates", func(t *testing.T) {
+		myTestClient := senderMock{httpStatusCode: 201}
+		sys := SystemInstance{serverURL: "https://cx1.server.com", iamURL: "https://cx1iam.server.com", tenant: "tenant", client: &myTestClient, logger: logger}
+
+		err := sys.AddResultsPredicates([]ResultsPredicates{})
+		assert.NoError(t, err, "Error occurred but none expected")
+		assert.Empty(t, myTestClient.urlCalled, "No request expected")
+	})
+
+	t.Run("test technical error", func(t *testing.T) {
+		myTestClient := senderMock{httpStatusCode: 200}
+		sys := SystemInstance{serverURL: "https://cx1.server.com", iamURL: "https://cx1iam.server.com", tenant: "tenant", client: &myTestClient, logger: logger}
+		myTestClient.SetOptions(opts)
+		myTestClient.errorExp = true
+
+		err := sys.AddResultsPredicates([]ResultsPredicates{{SimilarityID: 1, ProjectID: "1"}})
+		assert.Contains(t, fmt.Sprint(err), "failed to add results predicates: Provoked technical error")
+	})
+}
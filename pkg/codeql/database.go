@@ -0,0 +1,106 @@
+package codeql
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+)
+
+// commonCachesDirectory is the folder within the cache directory holding the query packs and compiled queries
+const commonCachesDirectory = "common"
+
+// DatabaseCache stores finalized CodeQL databases per commit and language as well as the downloaded query packs.
+// Databases of other commits are removed, thus re-runs for the same commit reuse the databases while the cache does not grow endlessly.
+type DatabaseCache struct {
+	Directory string
+	CommitID  string
+}
+
+// Enabled returns true if a cache directory is configured and the databases can be identified by the commit
+func (c DatabaseCache) Enabled() bool {
+	return len(c.Directory) > 0 && len(c.CommitID) > 0 && c.CommitID != "NA"
+}
+
+// DatabasePath returns the location of the database of the language within the cache
+func (c DatabaseCache) DatabasePath(language string) string {
+	return filepath.Join(c.Directory, c.CommitID, language)
+}
+
+// CommonCachesPath returns the location for the data which is shared by all runs, e.g. the query packs
+func (c DatabaseCache) CommonCachesPath() string {
+	return filepath.Join(c.Directory, commonCachesDirectory)
+}
+
+// Prune removes the databases of all other commits from the cache
+func (c DatabaseCache) Prune(utils piperutils.FileUtils) error {
+	entries, err := utils.Glob(filepath.Join(c.Directory, "*"))
+	if err != nil {
+		return errors.Wrapf(err, "failed to list cache directory '%v'", c.Directory)
+	}
+	for _, entry := range entries {
+		name := filepath.Base(entry)
+		if name == c.CommitID || name == commonCachesDirectory {
+			continue
+		}
+		if isDir, _ := utils.DirExists(entry); !isDir {
+			continue
+		}
+		log.Entry().Debugf("removing cached CodeQL databases of commit %v", name)
+		if err := utils.RemoveAll(entry); err != nil {
+			return errors.Wrapf(err, "failed to remove cached databases '%v'", entry)
+		}
+	}
+	return nil
+}
+
+// IsFinalized returns true if the database at the given path has been created completely and can be analyzed
+func IsFinalized(databasePath string, utils piperutils.FileUtils) bool {
+	content, err := utils.FileRead(filepath.Join(databasePath, "codeql-database.yml"))
+	if err != nil {
+		return false
+	}
+	return bytes.Contains(content, []byte("finalised: true"))
+}
+
+// MergeSarif combines the runs of the given SARIF documents into the first document.
+// The documents are processed generically in order to keep all properties written by CodeQL.
+func MergeSarif(documents [][]byte) ([]byte, error) {
+	if len(documents) == 0 {
+		return nil, errors.New("no SARIF documents to merge")
+	}
+	merged := map[string]interface{}{}
+	if err := json.Unmarshal(documents[0], &merged); err != nil {
+		return nil, errors.Wrap(err, "failed to parse SARIF document")
+	}
+	runs, _ := merged["runs"].([]interface{})
+	for _, document := range documents[1:] {
+		sarif := struct {
+			Runs []interface{} `json:"runs"`
+		}{}
+		if err := json.Unmarshal(document, &sarif); err != nil {
+			return nil, errors.Wrap(err, "failed to parse SARIF document")
+		}
+		runs = append(runs, sarif.Runs...)
+	}
+	merged["runs"] = runs
+	return json.MarshalIndent(merged, "", "  ")
+}
+
+// MergeCsv concatenates the rows of the given CSV reports, CodeQL writes them without header
+func MergeCsv(documents [][]byte) []byte {
+	merged := []byte{}
+	for _, document := range documents {
+		if len(document) == 0 {
+			continue
+		}
+		merged = append(merged, document...)
+		if !bytes.HasSuffix(document, []byte("\n")) {
+			merged = append(merged, '\n')
+		}
+	}
+	return merged
+}
@@ -0,0 +1,50 @@
+//go:build unit
+// +build unit
+
+package codeql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabaseCache(t *testing.T) {
+	t.Run("enabled", func(t *testing.T) {
+		assert.True(t, DatabaseCache{Directory: "cache", CommitID: "abc123"}.Enabled())
+		assert.False(t, DatabaseCache{Directory: "cache", CommitID: "NA"}.Enabled())
+		assert.False(t, DatabaseCache{Directory: "cache"}.Enabled())
+		assert.False(t, DatabaseCache{CommitID: "abc123"}.Enabled())
+	})
+
+	t.Run("paths", func(t *testing.T) {
+		cache := DatabaseCache{Directory: "cache", CommitID: "abc123"}
+		assert.Equal(t, "cache/abc123/java", cache.DatabasePath("java"))
+		assert.Equal(t, "cache/common", cache.CommonCachesPath())
+	})
+}
+
+func TestMergeSarif(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		merged, err := MergeSarif([][]byte{
+			[]byte(`{"$schema": "https://json.schemastore.org/sarif-2.1.0.json", "version": "2.1.0", "runs": [{"tool": {"driver": {"name": "CodeQL"}}, "automationDetails": {"id": "/language:java/"}}]}`),
+			[]byte(`{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "CodeQL"}}, "automationDetails": {"id": "/language:python/"}}]}`),
+		})
+
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{"$schema": "https://json.schemastore.org/sarif-2.1.0.json", "version": "2.1.0", "runs": [
+				{"tool": {"driver": {"name": "CodeQL"}}, "automationDetails": {"id": "/language:java/"}},
+				{"tool": {"driver": {"name": "CodeQL"}}, "automationDetails": {"id": "/language:python/"}}
+			]}`, string(merged))
+		}
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		_, err := MergeSarif([][]byte{[]byte(`{"runs": []}`), []byte(`runs`)})
+		assert.Contains(t, err.Error(), "failed to parse SARIF document")
+	})
+}
+
+func TestMergeCsv(t *testing.T) {
+	assert.Equal(t, "a,b\nc,d\ne,f\n", string(MergeCsv([][]byte{[]byte("a,b\n"), []byte(""), []byte("c,d"), []byte("e,f\n")})))
+}
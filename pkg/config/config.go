@@ -4,14 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
-	"net/url"
 	"os"
 	"reflect"
 	"regexp"
 	"strings"
 
-	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 
 	"github.com/ghodss/yaml"
@@ -26,6 +23,7 @@ type Config struct {
 	Stages           map[string]map[string]interface{} `json:"stages"`
 	Steps            map[string]map[string]interface{} `json:"steps"`
 	Hooks            map[string]interface{}            `json:"hooks,omitempty"`
+	Locked           *LockedParameters                 `json:"locked,omitempty"`
 	defaults         PipelineDefaults
 	initialized      bool
 	accessTokens     map[string]string
@@ -207,6 +205,10 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 		stepConfig.mixInHookConfig(def.Hooks)
 	}
 
+	// remember values of parameters locked by the defaults, they are enforced once the project configuration has been merged
+	var locked lockedValues
+	locked.collect(c.defaults.Defaults, stepConfig, stageName, stepName)
+
 	// read config & merge - general -> steps -> stages
 	stepConfig.mixIn(c.General, filters.General)
 	stepConfig.mixIn(c.Steps[stepName], filters.Steps)
@@ -254,6 +256,10 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 	reportingConfig.ApplyAliasConfig(ReportingParameters.Parameters, []StepSecrets{}, ReportingParameters.getStepFilters(), stageName, stepName, []Alias{})
 	stepConfig.mixinReportingConfig(reportingConfig.General, reportingConfig.Steps[stepName], reportingConfig.Stages[stageName])
 
+	if err := locked.enforce(&stepConfig, stepName); err != nil {
+		return StepConfig{}, err
+	}
+
 	// check whether vault should be skipped
 	if skip, ok := stepConfig.Config["skipVault"].(bool); !ok || !skip {
 		// fetch secrets from vault
@@ -369,29 +375,6 @@ func OpenPiperFile(name string, accessTokens map[string]string) (io.ReadCloser,
 	return httpReadFile(name, accessTokens)
 }
 
-func httpReadFile(name string, accessTokens map[string]string) (io.ReadCloser, error) {
-
-	u, err := url.Parse(name)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read url: %w", err)
-	}
-
-	// support http(s) urls next to file path
-	client := piperhttp.Client{}
-
-	var header http.Header
-	if len(accessTokens[u.Host]) > 0 {
-		client.SetOptions(piperhttp.ClientOptions{Token: fmt.Sprintf("token %v", accessTokens[u.Host])})
-		header = map[string][]string{"Accept": {"application/vnd.github.v3.raw"}}
-	}
-
-	response, err := client.SendRequest("GET", name, nil, header, nil)
-	if err != nil {
-		return nil, err
-	}
-	return response.Body, nil
-}
-
 func envValues(filter []string) map[string]interface{} {
 	vals := map[string]interface{}{}
 	for _, param := range filter {
@@ -0,0 +1,277 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+const (
+	// GraphIssueMissingProducer marks a consumed commonPipelineEnvironment value which is not produced by any other step
+	GraphIssueMissingProducer = "missingProducer"
+	// GraphIssueWrongOrder marks a consumed commonPipelineEnvironment value which is only produced by later steps
+	GraphIssueWrongOrder = "wrongOrder"
+)
+
+// externalResources are commonPipelineEnvironment values which are not produced by steps but provided by the orchestrator integration,
+// e.g. the repository coordinates and credentials set up by the Jenkins library before the first step runs
+var externalResources = map[string]bool{
+	"buildTool":                      true,
+	"git/httpsUrl":                   true,
+	"git/ref":                        true,
+	"git/remoteCommitId":             true,
+	"github/owner":                   true,
+	"github/repository":              true,
+	"container/repositoryUsername":   true,
+	"container/repositoryPassword":   true,
+	"custom/dockerConfigJSON":        true,
+	"custom/isOptimizedAndScheduled": true,
+	"custom/repositoryId":            true,
+	"custom/repositoryFormat":        true,
+	"custom/repositoryUrl":           true,
+	"custom/repositoryUsername":      true,
+	"custom/repositoryPassword":      true,
+	"custom/helmRepositoryURL":       true,
+	"custom/helmRepositoryUsername":  true,
+	"custom/helmRepositoryPassword":  true,
+	"custom/mavenGlobalSettingsFile": true,
+	"custom/mavenRepositoryURL":      true,
+	"custom/mavenRepositoryUsername": true,
+	"custom/mavenRepositoryPassword": true,
+	"custom/npmRepositoryURL":        true,
+	"custom/npmRepositoryUsername":   true,
+	"custom/npmRepositoryPassword":   true,
+	"custom/rawRepositoryURL":        true,
+	"custom/rawRepositoryUsername":   true,
+	"custom/rawRepositoryPassword":   true,
+}
+
+// GraphNode defines a step within a pipeline, the stage is empty if no pipeline definition is available
+type GraphNode struct {
+	Stage string `json:"stage,omitempty"`
+	Step  string `json:"step"`
+	index int
+}
+
+// GraphEdge defines a dependency between a producing and a consuming step via a commonPipelineEnvironment value
+type GraphEdge struct {
+	From     GraphNode `json:"from"`
+	To       GraphNode `json:"to"`
+	Resource string    `json:"resource"`
+}
+
+// GraphIssue defines a problem detected in the order of steps
+type GraphIssue struct {
+	Type     string    `json:"type"`
+	Node     GraphNode `json:"node"`
+	Resource string    `json:"resource"`
+	Message  string    `json:"message"`
+}
+
+// DependencyGraph defines the data flow between steps derived from the step metadata inputs and outputs
+type DependencyGraph struct {
+	Nodes  []GraphNode  `json:"nodes"`
+	Edges  []GraphEdge  `json:"edges"`
+	Issues []GraphIssue `json:"issues,omitempty"`
+}
+
+// NewDependencyGraph builds the dependency graph for the steps of a pipeline definition.
+// If no pipeline definition is provided, all steps of the metadata are considered without a defined order and no issues are reported,
+// since many of the consumed values are only provided by the steps of the actual pipeline or by the orchestrator integration.
+func NewDependencyGraph(metadata map[string]StepData, pipeline *PipelineDefinitionV1) DependencyGraph {
+	graph := DependencyGraph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+
+	if pipeline != nil {
+		for _, stage := range pipeline.Spec.Stages {
+			for _, step := range stage.Steps {
+				graph.Nodes = append(graph.Nodes, GraphNode{Stage: stage.Name, Step: step.Name, index: len(graph.Nodes)})
+			}
+		}
+	} else {
+		stepNames := make([]string, 0, len(metadata))
+		for stepName := range metadata {
+			stepNames = append(stepNames, stepName)
+		}
+		sort.Strings(stepNames)
+		for _, stepName := range stepNames {
+			graph.Nodes = append(graph.Nodes, GraphNode{Step: stepName, index: -1})
+		}
+	}
+
+	producers := map[string][]GraphNode{}
+	for _, node := range graph.Nodes {
+		stepData := metadata[node.Step]
+		for _, resource := range stepData.ProducedResources() {
+			producers[resource] = append(producers[resource], node)
+		}
+	}
+
+	for _, consumer := range graph.Nodes {
+		stepData := metadata[consumer.Step]
+		for _, resource := range stepData.ConsumedResources() {
+			resourceProducers := []GraphNode{}
+			for _, producer := range producers[resource] {
+				// a step consuming its own output does not depend on itself
+				if producer.index != consumer.index || producer.Step != consumer.Step {
+					resourceProducers = append(resourceProducers, producer)
+				}
+			}
+			if len(resourceProducers) == 0 {
+				// a step consuming its own output extends the value, e.g. the build settings, and does not require another producer
+				if pipeline == nil || externalResources[resource] || piperutils.ContainsString(stepData.ProducedResources(), resource) {
+					continue
+				}
+				graph.Issues = append(graph.Issues, GraphIssue{
+					Type:     GraphIssueMissingProducer,
+					Node:     consumer,
+					Resource: resource,
+					Message:  fmt.Sprintf("step '%v' consumes '%v' which is not produced by any other step", consumer.Step, resource),
+				})
+				continue
+			}
+
+			producedBefore := false
+			for _, producer := range resourceProducers {
+				graph.Edges = append(graph.Edges, GraphEdge{From: producer, To: consumer, Resource: resource})
+				if producer.index < consumer.index {
+					producedBefore = true
+				}
+			}
+			if pipeline != nil && !producedBefore {
+				graph.Issues = append(graph.Issues, GraphIssue{
+					Type:     GraphIssueWrongOrder,
+					Node:     consumer,
+					Resource: resource,
+					Message:  fmt.Sprintf("step '%v' in stage '%v' consumes '%v' which is only produced by later steps (%v)", consumer.Step, consumer.Stage, resource, nodeNames(resourceProducers)),
+				})
+			}
+		}
+	}
+	return graph
+}
+
+// ProducedResources returns the commonPipelineEnvironment values written by a step
+func (m *StepData) ProducedResources() []string {
+	resources := []string{}
+	for _, output := range m.Spec.Outputs.Resources {
+		if output.Type != "piperEnvironment" {
+			continue
+		}
+		for _, param := range output.Parameters {
+			if name, ok := param["name"].(string); ok && len(name) > 0 {
+				resources = appendUnique(resources, name)
+			}
+		}
+	}
+	return resources
+}
+
+// ConsumedResources returns the commonPipelineEnvironment values read by a step
+func (m *StepData) ConsumedResources() []string {
+	resources := []string{}
+	for _, param := range m.Spec.Inputs.Parameters {
+		for _, ref := range param.ResourceRef {
+			if ref.Name == "commonPipelineEnvironment" && len(ref.Param) > 0 {
+				resources = appendUnique(resources, ref.Param)
+			}
+		}
+	}
+	return resources
+}
+
+// DOT returns the graph in Graphviz DOT format
+func (g *DependencyGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph pipeline {\n")
+	b.WriteString("  rankdir=LR;\n")
+	stages := g.stages()
+	for i, stage := range stages {
+		indent := "  "
+		if len(stage) > 0 {
+			fmt.Fprintf(&b, "  subgraph cluster_%v {\n    label=%q;\n", i, stage)
+			indent = "    "
+		}
+		for _, node := range g.Nodes {
+			if node.Stage == stage {
+				fmt.Fprintf(&b, "%v%q [label=%q];\n", indent, node.id(), node.Step)
+			}
+		}
+		if len(stage) > 0 {
+			b.WriteString("  }\n")
+		}
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", edge.From.id(), edge.To.id(), edge.Resource)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid returns the graph as Mermaid flowchart
+func (g *DependencyGraph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	stages := g.stages()
+	for _, stage := range stages {
+		indent := "  "
+		if len(stage) > 0 {
+			fmt.Fprintf(&b, "  subgraph %v [\"%v\"]\n", mermaidID(stage), stage)
+			indent = "    "
+		}
+		for _, node := range g.Nodes {
+			if node.Stage == stage {
+				fmt.Fprintf(&b, "%v%v[\"%v\"]\n", indent, mermaidID(node.id()), node.Step)
+			}
+		}
+		if len(stage) > 0 {
+			b.WriteString("  end\n")
+		}
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %v -->|\"%v\"| %v\n", mermaidID(edge.From.id()), edge.Resource, mermaidID(edge.To.id()))
+	}
+	return b.String()
+}
+
+func (g *DependencyGraph) stages() []string {
+	stages := []string{}
+	for _, node := range g.Nodes {
+		stages = appendUnique(stages, node.Stage)
+	}
+	return stages
+}
+
+func (n GraphNode) id() string {
+	if len(n.Stage) == 0 {
+		return n.Step
+	}
+	return n.Stage + "/" + n.Step
+}
+
+func mermaidID(id string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, id)
+}
+
+func nodeNames(nodes []GraphNode) string {
+	names := []string{}
+	for _, node := range nodes {
+		names = append(names, node.id())
+	}
+	return strings.Join(names, ", ")
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, elem := range list {
+		if elem == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,144 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func graphTestMetadata() map[string]StepData {
+	return map[string]StepData{
+		"producerStep": {
+			Spec: StepSpec{Outputs: StepOutputs{Resources: []StepResources{
+				{Name: "commonPipelineEnvironment", Type: "piperEnvironment", Parameters: []map[string]interface{}{{"name": "artifactVersion"}, {"name": "custom/buildSettingsInfo"}}},
+				{Name: "influx", Type: "influx", Parameters: []map[string]interface{}{{"name": "step_data"}}},
+			}}},
+		},
+		"consumerStep": {
+			Spec: StepSpec{Inputs: StepInputs{Parameters: []StepParameters{
+				{Name: "version", ResourceRef: []ResourceReference{{Name: "commonPipelineEnvironment", Param: "artifactVersion"}}},
+				{Name: "commitId", ResourceRef: []ResourceReference{{Name: "commonPipelineEnvironment", Param: "git/headCommitId"}}},
+				{Name: "token", ResourceRef: []ResourceReference{{Name: "githubTokenCredentialsId", Type: "secret"}}},
+			}}},
+		},
+	}
+}
+
+func TestStepDataResources(t *testing.T) {
+	metadata := graphTestMetadata()
+	producer := metadata["producerStep"]
+	consumer := metadata["consumerStep"]
+
+	assert.Equal(t, []string{"artifactVersion", "custom/buildSettingsInfo"}, producer.ProducedResources())
+	assert.Equal(t, []string{}, producer.ConsumedResources())
+	assert.Equal(t, []string{"artifactVersion", "git/headCommitId"}, consumer.ConsumedResources())
+}
+
+func TestNewDependencyGraph(t *testing.T) {
+	t.Run("without pipeline definition", func(t *testing.T) {
+		graph := NewDependencyGraph(graphTestMetadata(), nil)
+
+		assert.Equal(t, []GraphNode{{Step: "consumerStep", index: -1}, {Step: "producerStep", index: -1}}, graph.Nodes)
+		assert.Equal(t, []GraphEdge{{From: GraphNode{Step: "producerStep", index: -1}, To: GraphNode{Step: "consumerStep", index: -1}, Resource: "artifactVersion"}}, graph.Edges)
+		// missing producers are only reported for a pipeline definition
+		assert.Empty(t, graph.Issues)
+	})
+
+	t.Run("correct order", func(t *testing.T) {
+		pipeline := &PipelineDefinitionV1{Spec: Spec{Stages: []Stage{
+			{Name: "Build", Steps: []Step{{Name: "producerStep"}}},
+			{Name: "Release", Steps: []Step{{Name: "consumerStep"}}},
+		}}}
+
+		graph := NewDependencyGraph(graphTestMetadata(), pipeline)
+
+		assert.Len(t, graph.Nodes, 2)
+		assert.Len(t, graph.Edges, 1)
+		if assert.Len(t, graph.Issues, 1) {
+			assert.Equal(t, GraphIssueMissingProducer, graph.Issues[0].Type)
+			assert.Equal(t, "git/headCommitId", graph.Issues[0].Resource)
+		}
+	})
+
+	t.Run("values provided by the orchestrator integration", func(t *testing.T) {
+		metadata := map[string]StepData{"consumerStep": {Spec: StepSpec{Inputs: StepInputs{Parameters: []StepParameters{
+			{Name: "owner", ResourceRef: []ResourceReference{{Name: "commonPipelineEnvironment", Param: "github/owner"}}},
+			{Name: "repositoryUrl", ResourceRef: []ResourceReference{{Name: "commonPipelineEnvironment", Param: "custom/repositoryUrl"}}},
+		}}}}}
+		pipeline := &PipelineDefinitionV1{Spec: Spec{Stages: []Stage{{Name: "Build", Steps: []Step{{Name: "consumerStep"}}}}}}
+
+		graph := NewDependencyGraph(metadata, pipeline)
+
+		assert.Empty(t, graph.Issues)
+	})
+
+	t.Run("wrong order", func(t *testing.T) {
+		pipeline := &PipelineDefinitionV1{Spec: Spec{Stages: []Stage{
+			{Name: "Build", Steps: []Step{{Name: "consumerStep"}, {Name: "producerStep"}}},
+		}}}
+
+		graph := NewDependencyGraph(graphTestMetadata(), pipeline)
+
+		if assert.Len(t, graph.Issues, 2) {
+			assert.Equal(t, GraphIssueWrongOrder, graph.Issues[0].Type)
+			assert.Equal(t, "artifactVersion", graph.Issues[0].Resource)
+			assert.Equal(t, "step 'consumerStep' in stage 'Build' consumes 'artifactVersion' which is only produced by later steps (Build/producerStep)", graph.Issues[0].Message)
+			assert.Equal(t, GraphIssueMissingProducer, graph.Issues[1].Type)
+		}
+	})
+
+	t.Run("step consuming its own output", func(t *testing.T) {
+		metadata := map[string]StepData{"selfStep": {Spec: StepSpec{
+			Inputs:  StepInputs{Parameters: []StepParameters{{Name: "version", ResourceRef: []ResourceReference{{Name: "commonPipelineEnvironment", Param: "artifactVersion"}}}}},
+			Outputs: StepOutputs{Resources: []StepResources{{Name: "commonPipelineEnvironment", Type: "piperEnvironment", Parameters: []map[string]interface{}{{"name": "artifactVersion"}}}}},
+		}}}
+
+		pipeline := &PipelineDefinitionV1{Spec: Spec{Stages: []Stage{{Name: "Build", Steps: []Step{{Name: "selfStep"}}}}}}
+
+		graph := NewDependencyGraph(metadata, pipeline)
+
+		assert.Empty(t, graph.Edges)
+		assert.Empty(t, graph.Issues)
+	})
+}
+
+func TestDependencyGraphExport(t *testing.T) {
+	pipeline := &PipelineDefinitionV1{Spec: Spec{Stages: []Stage{
+		{Name: "Build", Steps: []Step{{Name: "producerStep"}}},
+		{Name: "Release", Steps: []Step{{Name: "consumerStep"}}},
+	}}}
+	graph := NewDependencyGraph(graphTestMetadata(), pipeline)
+
+	t.Run("DOT", func(t *testing.T) {
+		expected := `digraph pipeline {
+  rankdir=LR;
+  subgraph cluster_0 {
+    label="Build";
+    "Build/producerStep" [label="producerStep"];
+  }
+  subgraph cluster_1 {
+    label="Release";
+    "Release/consumerStep" [label="consumerStep"];
+  }
+  "Build/producerStep" -> "Release/consumerStep" [label="artifactVersion"];
+}
+`
+		assert.Equal(t, expected, graph.DOT())
+	})
+
+	t.Run("Mermaid", func(t *testing.T) {
+		expected := `flowchart LR
+  subgraph Build ["Build"]
+    Build_producerStep["producerStep"]
+  end
+  subgraph Release ["Release"]
+    Release_consumerStep["consumerStep"]
+  end
+  Build_producerStep -->|"artifactVersion"| Release_consumerStep
+`
+		assert.Equal(t, expected, graph.Mermaid())
+	})
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// LockedParameters defines configuration parameters of a defaults file which must not be overridden by the project configuration
+type LockedParameters struct {
+	General []string            `json:"general,omitempty"`
+	Stages  map[string][]string `json:"stages,omitempty"`
+	Steps   map[string][]string `json:"steps,omitempty"`
+	Strict  bool                `json:"strict,omitempty"`
+}
+
+// lockedValues keeps the values of locked parameters as resolved from the defaults
+type lockedValues struct {
+	values map[string]interface{}
+	strict bool
+}
+
+// keys returns the locked parameter names which are relevant for the given stage and step
+func (l *LockedParameters) keys(stageName, stepName string) []string {
+	if l == nil {
+		return nil
+	}
+	keys := append([]string{}, l.General...)
+	keys = append(keys, l.Stages[stageName]...)
+	keys = append(keys, l.Steps[stepName]...)
+	return keys
+}
+
+// collect remembers the current value of all parameters locked by the defaults for the given stage and step.
+// It needs to be called once all defaults have been merged into the step configuration.
+func (l *lockedValues) collect(defaults []Config, stepConfig StepConfig, stageName, stepName string) {
+	for _, def := range defaults {
+		if def.Locked == nil {
+			continue
+		}
+		if def.Locked.Strict {
+			l.strict = true
+		}
+		for _, key := range def.Locked.keys(stageName, stepName) {
+			if l.values == nil {
+				l.values = map[string]interface{}{}
+			}
+			l.values[key] = stepConfig.Config[key]
+		}
+	}
+}
+
+// enforce resets all locked parameters which have been overridden after the defaults have been applied.
+// In strict mode an error is returned instead.
+func (l *lockedValues) enforce(stepConfig *StepConfig, stepName string) error {
+	if len(l.values) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(l.values))
+	for key := range l.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	violations := []string{}
+	for _, key := range keys {
+		lockedValue := l.values[key]
+		currentValue, isSet := stepConfig.Config[key]
+		if reflect.DeepEqual(lockedValue, currentValue) || (lockedValue == nil && !isSet) {
+			continue
+		}
+		violations = append(violations, key)
+		if l.strict {
+			continue
+		}
+		log.Entry().Warningf("The parameter '%v' is locked by the defaults and cannot be overridden by the project configuration, ignoring configured value. (%v/%v)", key, log.LibraryName, stepName)
+		if lockedValue == nil {
+			delete(stepConfig.Config, key)
+		} else {
+			stepConfig.Config[key] = lockedValue
+		}
+	}
+
+	if len(violations) > 0 && l.strict {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("the parameters '%v' are locked by the defaults and must not be overridden by the project configuration", strings.Join(violations, ", "))
+	}
+	if len(keys) > 0 {
+		log.Entry().Debugf("Enforced locked parameters: %v", strings.Join(keys, ", "))
+	}
+	return nil
+}
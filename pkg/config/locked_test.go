@@ -0,0 +1,134 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStepConfigLockedParameters(t *testing.T) {
+	filters := StepFilters{
+		General:    []string{"p0", "p1", "p2", "p3"},
+		Steps:      []string{"p0", "p1", "p2", "p3"},
+		Stages:     []string{"p0", "p1", "p2", "p3"},
+		Parameters: []string{"p0", "p1", "p2", "p3"},
+	}
+	stepMeta := StepData{}
+
+	t.Run("locked parameters are enforced", func(t *testing.T) {
+		defaults := `general:
+  p0: p0_default
+  p1: p1_default
+steps:
+  step1:
+    p2: p2_default
+locked:
+  general:
+  - p0
+  steps:
+    step1:
+    - p2
+  stages:
+    stage1:
+    - p3
+`
+		projectConfig := `general:
+  p0: p0_project
+  p1: p1_project
+steps:
+  step1:
+    p2: p2_project
+stages:
+  stage1:
+    p3: p3_project
+`
+		var c Config
+		stepConfig, err := c.GetStepConfig(map[string]interface{}{"p0": "p0_flag"}, "", io.NopCloser(strings.NewReader(projectConfig)), []io.ReadCloser{io.NopCloser(strings.NewReader(defaults))}, false, filters, stepMeta, nil, "stage1", "step1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "p0_default", stepConfig.Config["p0"])
+		assert.Equal(t, "p1_project", stepConfig.Config["p1"])
+		assert.Equal(t, "p2_default", stepConfig.Config["p2"])
+		assert.NotContains(t, stepConfig.Config, "p3")
+	})
+
+	t.Run("locked parameters of other steps and stages are not enforced", func(t *testing.T) {
+		defaults := `steps:
+  step2:
+    p2: p2_default
+locked:
+  steps:
+    step2:
+    - p2
+  stages:
+    stage2:
+    - p3
+`
+		projectConfig := `steps:
+  step1:
+    p2: p2_project
+stages:
+  stage1:
+    p3: p3_project
+`
+		var c Config
+		stepConfig, err := c.GetStepConfig(nil, "", io.NopCloser(strings.NewReader(projectConfig)), []io.ReadCloser{io.NopCloser(strings.NewReader(defaults))}, false, filters, stepMeta, nil, "stage1", "step1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "p2_project", stepConfig.Config["p2"])
+		assert.Equal(t, "p3_project", stepConfig.Config["p3"])
+	})
+
+	t.Run("locked parameters in project configuration are ignored", func(t *testing.T) {
+		projectConfig := `general:
+  p0: p0_project
+locked:
+  general:
+  - p0
+`
+		var c Config
+		stepConfig, err := c.GetStepConfig(map[string]interface{}{"p0": "p0_flag"}, "", io.NopCloser(strings.NewReader(projectConfig)), nil, false, filters, stepMeta, nil, "stage1", "step1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "p0_flag", stepConfig.Config["p0"])
+	})
+
+	t.Run("strict mode", func(t *testing.T) {
+		defaults := `general:
+  p0: p0_default
+  p1: p1_default
+locked:
+  strict: true
+  general:
+  - p0
+  - p1
+`
+		t.Run("failure on override", func(t *testing.T) {
+			projectConfig := `general:
+  p0: p0_project
+  p1: p1_project
+`
+			var c Config
+			_, err := c.GetStepConfig(nil, "", io.NopCloser(strings.NewReader(projectConfig)), []io.ReadCloser{io.NopCloser(strings.NewReader(defaults))}, false, filters, stepMeta, nil, "stage1", "step1")
+
+			assert.EqualError(t, err, "the parameters 'p0, p1' are locked by the defaults and must not be overridden by the project configuration")
+		})
+
+		t.Run("success with identical value", func(t *testing.T) {
+			projectConfig := `general:
+  p0: p0_default
+`
+			var c Config
+			stepConfig, err := c.GetStepConfig(nil, "", io.NopCloser(strings.NewReader(projectConfig)), []io.ReadCloser{io.NopCloser(strings.NewReader(defaults))}, false, filters, stepMeta, nil, "stage1", "step1")
+
+			assert.NoError(t, err)
+			assert.Equal(t, "p0_default", stepConfig.Config["p0"])
+			assert.Equal(t, "p1_default", stepConfig.Config["p1"])
+		})
+	})
+}
@@ -0,0 +1,237 @@
+package config
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// RemoteFileOptions defines how configuration files referenced via http(s) are retrieved
+type RemoteFileOptions struct {
+	// CacheDir is the directory used to cache downloaded files, caching is disabled if empty
+	CacheDir string
+	// Offline only uses the cache and does not perform any http request
+	Offline bool
+	// PublicKeyFile is the path to a PEM encoded public key used to verify detached signatures (<url>.sig)
+	PublicKeyFile string
+}
+
+// remoteFileCacheEntry contains the metadata of a cached file used for revalidation
+type remoteFileCacheEntry struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+const checksumFragmentPrefix = "sha256="
+
+var remoteFileOptions RemoteFileOptions
+
+// SetRemoteFileOptions sets the options used by OpenPiperFile for files referenced via http(s)
+func SetRemoteFileOptions(options RemoteFileOptions) {
+	remoteFileOptions = options
+}
+
+func httpReadFile(name string, accessTokens map[string]string) (io.ReadCloser, error) {
+	location, checksum := splitChecksum(name)
+
+	content, err := readRemoteFile(location, accessTokens, remoteFileOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(checksum) > 0 {
+		if err := verifyChecksum(content, checksum); err != nil {
+			return nil, errors.Wrapf(err, "failed to verify '%v'", location)
+		}
+	}
+
+	if len(remoteFileOptions.PublicKeyFile) > 0 {
+		publicKey, err := os.ReadFile(remoteFileOptions.PublicKeyFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read public key '%v'", remoteFileOptions.PublicKeyFile)
+		}
+		signature, err := readRemoteFile(location+".sig", accessTokens, remoteFileOptions)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to retrieve signature of '%v'", location)
+		}
+		if err := verifySignature(content, signature, publicKey); err != nil {
+			return nil, errors.Wrapf(err, "failed to verify signature of '%v'", location)
+		}
+	}
+
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+// splitChecksum separates an optional checksum pinned via the url fragment, e.g. https://my.server/defaults.yml#sha256=<hex>
+func splitChecksum(name string) (string, string) {
+	parts := strings.SplitN(name, "#", 2)
+	if len(parts) == 2 && strings.HasPrefix(parts[1], checksumFragmentPrefix) {
+		return parts[0], strings.ToLower(strings.TrimPrefix(parts[1], checksumFragmentPrefix))
+	}
+	return name, ""
+}
+
+func verifyChecksum(content []byte, checksum string) error {
+	sum := sha256.Sum256(content)
+	if actual := hex.EncodeToString(sum[:]); actual != checksum {
+		return fmt.Errorf("sha256 checksum mismatch: expected '%v' but got '%v'", checksum, actual)
+	}
+	return nil
+}
+
+// verifySignature verifies a detached signature of the content, the signature may be base64 encoded.
+// ECDSA (ASN.1) and RSA (PKCS #1 v1.5) signatures are expected over the SHA-256 digest of the content, e.g. created via 'openssl dgst -sha256 -sign'.
+// Ed25519 signatures are expected over the raw content as defined by Ed25519, e.g. created via 'openssl pkeyutl -sign -rawin'.
+func verifySignature(content, signature, publicKeyPEM []byte) error {
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil {
+		return fmt.Errorf("public key is not PEM encoded")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse public key")
+	}
+
+	if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature))); err == nil {
+		signature = decoded
+	}
+
+	digest := sha256.Sum256(content)
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return fmt.Errorf("invalid signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return errors.Wrap(err, "invalid signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, content, signature) {
+			return fmt.Errorf("invalid signature")
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+	return nil
+}
+
+// readRemoteFile downloads a file, using and maintaining the on-disk cache if configured.
+// Cached files are revalidated via ETag / Last-Modified and used as fallback if the server is not reachable or responds with a server error.
+func readRemoteFile(location string, accessTokens map[string]string, options RemoteFileOptions) ([]byte, error) {
+	cacheFile := ""
+	var cached *remoteFileCacheEntry
+	if len(options.CacheDir) > 0 {
+		sum := sha256.Sum256([]byte(location))
+		cacheFile = filepath.Join(options.CacheDir, hex.EncodeToString(sum[:]))
+		cached = readCacheEntry(cacheFile)
+	}
+
+	if options.Offline {
+		if cached == nil {
+			return nil, fmt.Errorf("'%v' is not available in cache '%v' (offline mode)", location, options.CacheDir)
+		}
+		log.Entry().Debugf("Using cached '%v' (offline mode)", location)
+		return os.ReadFile(cacheFile)
+	}
+
+	client := piperhttp.Client{}
+	header := http.Header{}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read url: %w", err)
+	}
+	if len(accessTokens[u.Host]) > 0 {
+		client.SetOptions(piperhttp.ClientOptions{Token: fmt.Sprintf("token %v", accessTokens[u.Host])})
+		header.Set("Accept", "application/vnd.github.v3.raw")
+	}
+	if cached != nil {
+		if len(cached.ETag) > 0 {
+			header.Set("If-None-Match", cached.ETag)
+		}
+		if len(cached.LastModified) > 0 {
+			header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	response, err := client.SendRequest(http.MethodGet, location, nil, header, nil)
+	if response != nil && response.Body != nil {
+		defer response.Body.Close()
+	}
+	if cached != nil && response != nil && response.StatusCode == http.StatusNotModified {
+		log.Entry().Debugf("Using cached '%v' (not modified)", location)
+		return os.ReadFile(cacheFile)
+	}
+	if err != nil {
+		// the cache only bridges unavailable servers, client errors like a missing file or missing permissions are reported
+		if cached != nil && (response == nil || response.StatusCode >= http.StatusInternalServerError) {
+			log.Entry().WithError(err).Warningf("Failed to download '%v', using cached version", location)
+			return os.ReadFile(cacheFile)
+		}
+		return nil, err
+	}
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read '%v'", location)
+	}
+
+	if len(cacheFile) > 0 {
+		entry := remoteFileCacheEntry{URL: location, ETag: response.Header.Get("ETag"), LastModified: response.Header.Get("Last-Modified")}
+		if err := writeCacheEntry(cacheFile, entry, content); err != nil {
+			log.Entry().WithError(err).Warningf("Failed to cache '%v'", location)
+		}
+	}
+	return content, nil
+}
+
+func readCacheEntry(cacheFile string) *remoteFileCacheEntry {
+	if _, err := os.Stat(cacheFile); err != nil {
+		return nil
+	}
+	metadata, err := os.ReadFile(cacheFile + ".json")
+	if err != nil {
+		return nil
+	}
+	var entry remoteFileCacheEntry
+	if err := json.Unmarshal(metadata, &entry); err != nil {
+		log.Entry().Debugf("Ignoring invalid cache entry '%v': %v", cacheFile, err)
+		return nil
+	}
+	return &entry
+}
+
+func writeCacheEntry(cacheFile string, entry remoteFileCacheEntry, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0o755); err != nil {
+		return err
+	}
+	metadata, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(cacheFile, content, 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(cacheFile+".json", metadata, 0o644)
+}
@@ -0,0 +1,187 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const remoteDefaults = "general:\n  p0: p0_remote_default\n"
+
+func TestHttpReadFile(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(remoteDefaults))
+	}))
+	defer server.Close()
+	defer SetRemoteFileOptions(RemoteFileOptions{})
+
+	sum := sha256.Sum256([]byte(remoteDefaults))
+	checksum := hex.EncodeToString(sum[:])
+
+	t.Run("without cache", func(t *testing.T) {
+		SetRemoteFileOptions(RemoteFileOptions{})
+		requests = 0
+
+		content := readAll(t, server.URL+"/defaults.yml")
+
+		assert.Equal(t, remoteDefaults, content)
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("with cache and revalidation", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		SetRemoteFileOptions(RemoteFileOptions{CacheDir: cacheDir})
+		requests = 0
+
+		assert.Equal(t, remoteDefaults, readAll(t, server.URL+"/defaults.yml"))
+		assert.Equal(t, remoteDefaults, readAll(t, server.URL+"/defaults.yml"))
+		assert.Equal(t, 2, requests)
+
+		entries, err := os.ReadDir(cacheDir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 2)
+	})
+
+	t.Run("cache fallback", func(t *testing.T) {
+		status := http.StatusOK
+		flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if status != http.StatusOK {
+				w.WriteHeader(status)
+				return
+			}
+			w.Write([]byte(remoteDefaults))
+		}))
+		defer flaky.Close()
+		SetRemoteFileOptions(RemoteFileOptions{CacheDir: t.TempDir()})
+		readAll(t, flaky.URL+"/defaults.yml")
+
+		status = http.StatusServiceUnavailable
+		assert.Equal(t, remoteDefaults, readAll(t, flaky.URL+"/defaults.yml"))
+
+		status = http.StatusNotFound
+		_, err := httpReadFile(flaky.URL+"/defaults.yml", nil)
+		assert.Contains(t, err.Error(), "404 Not Found")
+
+		flaky.Close()
+		assert.Equal(t, remoteDefaults, readAll(t, flaky.URL+"/defaults.yml"))
+	})
+
+	t.Run("offline mode", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		SetRemoteFileOptions(RemoteFileOptions{CacheDir: cacheDir})
+		readAll(t, server.URL+"/defaults.yml")
+
+		SetRemoteFileOptions(RemoteFileOptions{CacheDir: cacheDir, Offline: true})
+		requests = 0
+
+		assert.Equal(t, remoteDefaults, readAll(t, server.URL+"/defaults.yml"))
+		assert.Equal(t, 0, requests)
+
+		_, err := httpReadFile(server.URL+"/other.yml", nil)
+		assert.Contains(t, err.Error(), "is not available in cache")
+	})
+
+	t.Run("checksum pinning", func(t *testing.T) {
+		SetRemoteFileOptions(RemoteFileOptions{})
+
+		assert.Equal(t, remoteDefaults, readAll(t, server.URL+"/defaults.yml#sha256="+checksum))
+
+		_, err := httpReadFile(server.URL+"/defaults.yml#sha256=0000", nil)
+		assert.Contains(t, err.Error(), "sha256 checksum mismatch")
+	})
+
+	t.Run("signature verification", func(t *testing.T) {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		signature, err := ecdsa.SignASN1(rand.Reader, privateKey, sum[:])
+		require.NoError(t, err)
+		publicKey, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+		require.NoError(t, err)
+		publicKeyFile := filepath.Join(t.TempDir(), "key.pub")
+		require.NoError(t, os.WriteFile(publicKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}), 0o644))
+
+		signed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/defaults.yml":
+				w.Write([]byte(remoteDefaults))
+			case "/defaults.yml.sig":
+				w.Write([]byte(base64.StdEncoding.EncodeToString(signature)))
+			case "/tampered.yml":
+				w.Write([]byte(remoteDefaults + "  p1: p1_remote_default\n"))
+			case "/tampered.yml.sig":
+				w.Write(signature)
+			}
+		}))
+		defer signed.Close()
+
+		SetRemoteFileOptions(RemoteFileOptions{PublicKeyFile: publicKeyFile})
+
+		assert.Equal(t, remoteDefaults, readAll(t, signed.URL+"/defaults.yml"))
+
+		_, err = httpReadFile(signed.URL+"/tampered.yml", nil)
+		assert.EqualError(t, err, "failed to verify signature of '"+signed.URL+"/tampered.yml': invalid signature")
+	})
+}
+
+func TestVerifySignature(t *testing.T) {
+	content := []byte(remoteDefaults)
+	digest := sha256.Sum256(content)
+	encodePublicKey := func(t *testing.T, key interface{}) []byte {
+		publicKey, err := x509.MarshalPKIXPublicKey(key)
+		require.NoError(t, err)
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey})
+	}
+
+	t.Run("RSA signature of the digest", func(t *testing.T) {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+
+		assert.NoError(t, verifySignature(content, signature, encodePublicKey(t, &privateKey.PublicKey)))
+	})
+
+	t.Run("Ed25519 signature of the content", func(t *testing.T) {
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		assert.NoError(t, verifySignature(content, ed25519.Sign(privateKey, content), encodePublicKey(t, publicKey)))
+		assert.EqualError(t, verifySignature(content, ed25519.Sign(privateKey, digest[:]), encodePublicKey(t, publicKey)), "invalid signature")
+	})
+}
+
+func readAll(t *testing.T, location string) string {
+	t.Helper()
+	file, err := httpReadFile(location, nil)
+	require.NoError(t, err)
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+	return string(content)
+}
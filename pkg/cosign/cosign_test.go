@@ -0,0 +1,156 @@
+//go:build unit
+// +build unit
+
+package cosign
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// encryptTestKey encrypts the key the same way as "cosign generate-key-pair"
+func encryptTestKey(t *testing.T, key *ecdsa.PrivateKey, password string) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	encrypted := encryptedKey{}
+	encrypted.KDF.Name = "scrypt"
+	encrypted.KDF.Params.N = 32768
+	encrypted.KDF.Params.R = 8
+	encrypted.KDF.Params.P = 1
+	encrypted.KDF.Salt = []byte("0123456789abcdef0123456789abcdef")
+	encrypted.Cipher.Name = "nacl/secretbox"
+	encrypted.Cipher.Nonce = []byte("0123456789abcdef01234567")
+
+	secret, err := scrypt.Key([]byte(password), encrypted.KDF.Salt, 32768, 8, 1, 32)
+	require.NoError(t, err)
+	var nonce [24]byte
+	var secretKey [32]byte
+	copy(nonce[:], encrypted.Cipher.Nonce)
+	copy(secretKey[:], secret)
+	encrypted.Ciphertext = secretbox.Seal(nil, der, &nonce, &secretKey)
+
+	content, err := json.Marshal(encrypted)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: encryptedSigstoreKeyType, Bytes: content})
+}
+
+func TestNewSigner(t *testing.T) {
+	t.Parallel()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	t.Run("encrypted key", func(t *testing.T) {
+		signer, err := NewSigner(encryptTestKey(t, key, "secret"), []byte("secret"))
+		require.NoError(t, err)
+		assert.Equal(t, &key.PublicKey, signer.PublicKey())
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, err := NewSigner(encryptTestKey(t, key, "secret"), []byte("wrong"))
+		assert.EqualError(t, err, "failed to decrypt private key, the password is wrong")
+	})
+
+	t.Run("unencrypted key", func(t *testing.T) {
+		der, _ := x509.MarshalECPrivateKey(key)
+		signer, err := NewSigner(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil)
+		require.NoError(t, err)
+		assert.Equal(t, &key.PublicKey, signer.PublicKey())
+	})
+}
+
+func TestSignAndVerifyImage(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer server.Close()
+	repository, err := name.NewRepository(strings.TrimPrefix(server.URL, "http://") + "/app")
+	require.NoError(t, err)
+
+	pushImage := func(tag string) string {
+		image, err := random.Image(512, 1)
+		require.NoError(t, err)
+		require.NoError(t, remote.Write(repository.Tag(tag), image))
+		digest, _ := image.Digest()
+		return fmt.Sprintf("%v@%v", repository, digest)
+	}
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	signer, err := NewSigner(encryptTestKey(t, key, "secret"), []byte("secret"))
+	require.NoError(t, err)
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherSigner, err := NewSigner(encryptTestKey(t, otherKey, "secret"), []byte("secret"))
+	require.NoError(t, err)
+
+	t.Run("signed image", func(t *testing.T) {
+		image := pushImage("signed")
+		require.NoError(t, SignImage(image, signer, map[string]interface{}{"commit": "abc"}))
+		// signing again does not add another signature
+		require.NoError(t, SignImage(image, signer, map[string]interface{}{"commit": "abc"}))
+		require.NoError(t, SignImage(image, otherSigner, nil))
+
+		payloads, err := VerifyImage(image, &key.PublicKey)
+		require.NoError(t, err)
+		require.Len(t, payloads, 1)
+		assert.Equal(t, repository.Name(), payloads[0].Critical.Identity.DockerReference)
+		assert.Equal(t, strings.Split(image, "@")[1], payloads[0].Critical.Image.DockerManifestDigest)
+		assert.Equal(t, "abc", payloads[0].Optional["commit"])
+
+		digest, _ := name.NewDigest(image)
+		signatures, err := remote.Image(signatureTag(digest))
+		require.NoError(t, err)
+		layers, _ := signatures.Layers()
+		assert.Len(t, layers, 2)
+
+		_, err = VerifyImage(image, &otherKey.PublicKey)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unsigned image", func(t *testing.T) {
+		image := pushImage("unsigned")
+		_, err := VerifyImage(image, &key.PublicKey)
+		assert.True(t, errors.Is(err, ErrNotSigned))
+	})
+
+	t.Run("signed with other key", func(t *testing.T) {
+		image := pushImage("other")
+		require.NoError(t, SignImage(image, otherSigner, nil))
+		_, err := VerifyImage(image, &key.PublicKey)
+		assert.EqualError(t, err, "no valid signature found")
+	})
+
+	t.Run("resolve digest", func(t *testing.T) {
+		image := pushImage("latest")
+		digest, err := ResolveDigest(repository.Tag("latest").String())
+		assert.NoError(t, err)
+		assert.Equal(t, image, digest)
+
+		digest, err = ResolveDigest(image)
+		assert.NoError(t, err)
+		assert.Equal(t, image, digest)
+	})
+
+	t.Run("reference by tag", func(t *testing.T) {
+		err := SignImage(repository.Tag("latest").String(), signer, nil)
+		assert.Contains(t, fmt.Sprint(err), "a reference by digest is required")
+	})
+}
@@ -0,0 +1,82 @@
+package cosign
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/SAP/jenkins-library/pkg/provenance"
+)
+
+// PEM block types of private keys created with "cosign generate-key-pair"
+const (
+	encryptedSigstoreKeyType = "ENCRYPTED SIGSTORE PRIVATE KEY"
+	encryptedCosignKeyType   = "ENCRYPTED COSIGN PRIVATE KEY"
+)
+
+// encryptedKey is the content of an encrypted cosign private key
+type encryptedKey struct {
+	KDF struct {
+		Name   string `json:"name"`
+		Params struct {
+			N int `json:"N"`
+			R int `json:"r"`
+			P int `json:"p"`
+		} `json:"params"`
+		Salt []byte `json:"salt"`
+	} `json:"kdf"`
+	Cipher struct {
+		Name  string `json:"name"`
+		Nonce []byte `json:"nonce"`
+	} `json:"cipher"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// NewSigner creates a signer from a private key in PEM format.
+// Besides unencrypted keys, the encrypted keys created by cosign are supported and decrypted with the password.
+func NewSigner(keyPEM, password []byte) (*provenance.Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("no PEM encoded private key found")
+	}
+	if block.Type != encryptedSigstoreKeyType && block.Type != encryptedCosignKeyType {
+		return provenance.NewSigner(keyPEM, "")
+	}
+
+	der, err := decryptKey(block.Bytes, password)
+	if err != nil {
+		return nil, err
+	}
+	return provenance.NewSigner(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), "")
+}
+
+func decryptKey(content, password []byte) ([]byte, error) {
+	key := encryptedKey{}
+	if err := json.Unmarshal(content, &key); err != nil {
+		return nil, errors.Wrap(err, "failed to parse encrypted private key")
+	}
+	if key.KDF.Name != "scrypt" || key.Cipher.Name != "nacl/secretbox" {
+		return nil, fmt.Errorf("unsupported private key encryption '%v' with '%v'", key.KDF.Name, key.Cipher.Name)
+	}
+	if len(key.Cipher.Nonce) != 24 {
+		return nil, errors.New("invalid nonce of encrypted private key")
+	}
+
+	secret, err := scrypt.Key(password, key.KDF.Salt, key.KDF.Params.N, key.KDF.Params.R, key.KDF.Params.P, 32)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to derive key from password")
+	}
+	var nonce [24]byte
+	var secretKey [32]byte
+	copy(nonce[:], key.Cipher.Nonce)
+	copy(secretKey[:], secret)
+	der, ok := secretbox.Open(nil, key.Ciphertext, &nonce, &secretKey)
+	if !ok {
+		return nil, errors.New("failed to decrypt private key, the password is wrong")
+	}
+	return der, nil
+}
@@ -0,0 +1,234 @@
+package cosign
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/provenance"
+)
+
+const (
+	// SignatureMediaType is the media type of the layers containing the signed payload
+	SignatureMediaType types.MediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// SignatureAnnotation is the layer annotation containing the base64 encoded signature
+	SignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// SignatureType is the type of the signed payload
+	SignatureType = "cosign container image signature"
+)
+
+// ErrNotSigned is returned if no signature is available for an image
+var ErrNotSigned = errors.New("no signatures found")
+
+// Payload is the signed payload in "simple signing" format
+type Payload struct {
+	Critical Critical               `json:"critical"`
+	Optional map[string]interface{} `json:"optional"`
+}
+
+// Critical contains the image identity and digest
+type Critical struct {
+	Identity Identity `json:"identity"`
+	Image    Image    `json:"image"`
+	Type     string   `json:"type"`
+}
+
+// Identity identifies the repository of the image
+type Identity struct {
+	DockerReference string `json:"docker-reference"`
+}
+
+// Image contains the digest of the signed image
+type Image struct {
+	DockerManifestDigest string `json:"docker-manifest-digest"`
+}
+
+// SignImage signs the image with the signer and pushes the signature to the tag sha256-<hash>.sig as done by cosign.
+// The image has to be referenced by digest. Existing signatures are kept, if the image is already signed by the key nothing is changed.
+func SignImage(image string, signer *provenance.Signer, annotations map[string]interface{}, options ...remote.Option) error {
+	digest, err := name.NewDigest(image)
+	if err != nil {
+		return errors.Wrapf(err, "invalid image reference '%v', a reference by digest is required", image)
+	}
+
+	// ignore JSON errors since structure is in our hands
+	payload, _ := json.Marshal(Payload{
+		Critical: Critical{
+			Identity: Identity{DockerReference: digest.Repository.Name()},
+			Image:    Image{DockerManifestDigest: digest.DigestStr()},
+			Type:     SignatureType,
+		},
+		Optional: annotations,
+	})
+
+	signatures, err := signatureImage(digest, options...)
+	if err != nil {
+		return err
+	}
+	verified, err := verifySignatures(signatures, signer.PublicKey())
+	if err != nil {
+		return err
+	}
+	for _, signed := range verified {
+		if bytes.Equal(signed, payload) {
+			log.Entry().Infof("Image '%v' is already signed", image)
+			return nil
+		}
+	}
+
+	sig, err := signer.SignBytes(payload)
+	if err != nil {
+		return errors.Wrapf(err, "failed to sign image '%v'", image)
+	}
+	signatures, err = mutate.Append(signatures, mutate.Addendum{
+		Layer:       static.NewLayer(payload, SignatureMediaType),
+		Annotations: map[string]string{SignatureAnnotation: base64.StdEncoding.EncodeToString(sig)},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to add signature")
+	}
+	if err := remote.Write(signatureTag(digest), signatures, options...); err != nil {
+		return errors.Wrapf(err, "failed to push signature of image '%v'", image)
+	}
+	return nil
+}
+
+// VerifyImage checks that the image is signed with the private key matching the public key.
+// The image has to be referenced by digest, the payloads of all valid signatures are returned.
+func VerifyImage(image string, publicKey crypto.PublicKey, options ...remote.Option) ([]Payload, error) {
+	digest, err := name.NewDigest(image)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid image reference '%v', a reference by digest is required", image)
+	}
+
+	signatures, err := signatureImage(digest, options...)
+	if err != nil {
+		return nil, err
+	}
+	layers, err := signatures.Layers()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read signatures")
+	}
+	if len(layers) == 0 {
+		return nil, ErrNotSigned
+	}
+
+	verified, err := verifySignatures(signatures, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	payloads := []Payload{}
+	for _, signed := range verified {
+		payload := Payload{}
+		if err := json.Unmarshal(signed, &payload); err != nil {
+			log.Entry().WithError(err).Debug("ignoring signature with invalid payload")
+			continue
+		}
+		if payload.Critical.Image.DockerManifestDigest != digest.DigestStr() {
+			log.Entry().Debugf("ignoring signature of digest %v", payload.Critical.Image.DockerManifestDigest)
+			continue
+		}
+		if payload.Critical.Identity.DockerReference != digest.Repository.Name() {
+			log.Entry().Debugf("ignoring signature of repository %v", payload.Critical.Identity.DockerReference)
+			continue
+		}
+		payloads = append(payloads, payload)
+	}
+	if len(payloads) == 0 {
+		return nil, errors.New("no valid signature found")
+	}
+	return payloads, nil
+}
+
+// ResolveDigest returns the reference by digest of an image referenced by tag or digest
+func ResolveDigest(image string, options ...remote.Option) (string, error) {
+	reference, err := name.ParseReference(image)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid image reference '%v'", image)
+	}
+	if digest, ok := reference.(name.Digest); ok {
+		return digest.String(), nil
+	}
+	descriptor, err := remote.Head(reference, options...)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get digest of image '%v'", image)
+	}
+	return fmt.Sprintf("%v@%v", reference.Context().Name(), descriptor.Digest), nil
+}
+
+func signatureTag(digest name.Digest) name.Tag {
+	return digest.Repository.Tag(strings.Replace(digest.DigestStr(), ":", "-", 1) + ".sig")
+}
+
+// signatureImage returns the image containing the signatures of the image, an empty image is returned if it is not signed yet
+func signatureImage(digest name.Digest, options ...remote.Option) (v1.Image, error) {
+	signatures, err := remote.Image(signatureTag(digest), options...)
+	if err != nil {
+		var transportErr *transport.Error
+		if errors.As(err, &transportErr) && transportErr.StatusCode == http.StatusNotFound {
+			return mutate.ConfigMediaType(mutate.MediaType(empty.Image, types.OCIManifestSchema1), types.OCIConfigJSON), nil
+		}
+		return nil, errors.Wrapf(err, "failed to get signatures of image '%v'", digest)
+	}
+	return signatures, nil
+}
+
+// verifySignatures returns the payloads with valid signatures created with the private key matching the public key
+func verifySignatures(signatures v1.Image, publicKey crypto.PublicKey) ([][]byte, error) {
+	manifest, err := signatures.Manifest()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read signatures")
+	}
+
+	verified := [][]byte{}
+	for _, descriptor := range manifest.Layers {
+		if descriptor.MediaType != SignatureMediaType {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(descriptor.Annotations[SignatureAnnotation])
+		if err != nil {
+			continue
+		}
+		layer, err := signatures.LayerByDigest(descriptor.Digest)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get signature %v", descriptor.Digest)
+		}
+		payload, err := readLayer(layer)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read signature %v", descriptor.Digest)
+		}
+		valid, err := provenance.VerifyBytes(publicKey, payload, sig)
+		if err != nil {
+			return nil, err
+		}
+		if valid {
+			verified = append(verified, payload)
+		}
+	}
+	return verified, nil
+}
+
+func readLayer(layer v1.Layer) ([]byte, error) {
+	content, err := layer.Compressed()
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+	return io.ReadAll(content)
+}
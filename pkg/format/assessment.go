@@ -47,6 +47,43 @@ func (p Purl) ToPackageUrl() (packageurl.PackageURL, error) {
 	return packageurl.FromString(p.Purl)
 }
 
+// FindAssessment returns the assessment matching the package URL and one of the identifiers of a vulnerability (e.g. the vendor specific ID and the CVE).
+// Qualifiers and subpath of the package URLs are not considered. If no assessment matches, nil is returned.
+func FindAssessment(assessments *[]Assessment, purl *packageurl.PackageURL, vulnerabilityIDs ...string) (*Assessment, error) {
+	if assessments == nil || purl == nil {
+		return nil, nil
+	}
+	localPurl := purlWithoutQualifiers(*purl)
+	for i, assessment := range *assessments {
+		if !containsID(vulnerabilityIDs, assessment.Vulnerability) {
+			continue
+		}
+		for _, p := range assessment.Purls {
+			assessmentPurl, err := p.ToPackageUrl()
+			if err != nil {
+				return nil, NewParseError(fmt.Sprintf("assessment for vulnerability %v has an invalid packageUrl '%v': %v", assessment.Vulnerability, p.Purl, err))
+			}
+			if purlWithoutQualifiers(assessmentPurl) == localPurl {
+				return &(*assessments)[i], nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// ToVulnerabilityAnalysis returns the CycloneDX analysis of the assessment
+// Without analysis of the assessment only the state is provided.
+func (a Assessment) ToVulnerabilityAnalysis() *cdx.VulnerabilityAnalysis {
+	if len(a.Analysis) == 0 {
+		return &cdx.VulnerabilityAnalysis{State: a.ToImpactAnalysisState()}
+	}
+	return &cdx.VulnerabilityAnalysis{
+		State:         a.ToImpactAnalysisState(),
+		Justification: a.ToImpactJustification(),
+		Response:      a.ToImpactAnalysisResponse(),
+	}
+}
+
 func (a Assessment) ToImpactAnalysisState() cdx.ImpactAnalysisState {
 	switch a.Status {
 	case Relevant:
@@ -123,3 +160,16 @@ func ReadAssessments(assessmentFile io.ReadCloser) (*[]Assessment, error) {
 	}
 	return &ignore.Assessments, nil
 }
+
+func purlWithoutQualifiers(purl packageurl.PackageURL) string {
+	return packageurl.NewPackageURL(purl.Type, purl.Namespace, purl.Name, purl.Version, nil, "").ToString()
+}
+
+func containsID(ids []string, id string) bool {
+	for _, elem := range ids {
+		if len(elem) > 0 && elem == id {
+			return true
+		}
+	}
+	return false
+}
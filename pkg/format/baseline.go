@@ -0,0 +1,158 @@
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// SarifDiff contains the results of a scan compared to the results of a baseline scan, e.g. of the base branch of a pull request
+type SarifDiff struct {
+	BaselineName string
+	New          []Results
+	Fixed        []Results
+	Unchanged    []Results
+}
+
+// ReadSarif reads a SARIF file
+func ReadSarif(path string, utils piperutils.FileUtils) (*SARIF, error) {
+	content, err := utils.FileRead(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read SARIF file '%v'", path)
+	}
+	sarif := SARIF{}
+	if err := json.Unmarshal(content, &sarif); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse SARIF file '%v'", path)
+	}
+	return &sarif, nil
+}
+
+// Fingerprint returns the identity of a result used to compare results of different scans.
+// The partial fingerprints provided by the tool are used if available, otherwise the rule and the location of the result.
+func (r Results) Fingerprint() string {
+	fingerprints := []string{
+		r.PartialFingerprints.FortifyInstanceID,
+		r.PartialFingerprints.CheckmarxSimilarityID,
+		r.PartialFingerprints.PrimaryLocationLineHash,
+		r.PartialFingerprints.PackageURLPlusCVEHash,
+	}
+	if len(strings.Join(fingerprints, "")) > 0 {
+		return fmt.Sprintf("%v|%v", r.RuleID, strings.Join(fingerprints, "|"))
+	}
+	return fmt.Sprintf("%v|%v", r.RuleID, r.location())
+}
+
+func (r Results) location() string {
+	if len(r.Locations) == 0 {
+		return ""
+	}
+	location := r.Locations[0].PhysicalLocation
+	return fmt.Sprintf("%v:%v:%v", location.ArtifactLocation.URI, location.Region.StartLine, location.Region.StartColumn)
+}
+
+// CompareSarif compares the results of the current scan with the results of the baseline scan.
+// Results are matched via their fingerprint, multiple results with the same fingerprint are matched one by one.
+func CompareSarif(baseline, current *SARIF) SarifDiff {
+	diff := SarifDiff{New: []Results{}, Fixed: []Results{}, Unchanged: []Results{}}
+
+	baselineResults := map[string][]Results{}
+	for _, result := range allResults(baseline) {
+		fingerprint := result.Fingerprint()
+		baselineResults[fingerprint] = append(baselineResults[fingerprint], result)
+	}
+
+	for _, result := range allResults(current) {
+		fingerprint := result.Fingerprint()
+		if len(baselineResults[fingerprint]) > 0 {
+			baselineResults[fingerprint] = baselineResults[fingerprint][1:]
+			diff.Unchanged = append(diff.Unchanged, result)
+		} else {
+			diff.New = append(diff.New, result)
+		}
+	}
+
+	// keep the order of the fixed results stable
+	fingerprints := []string{}
+	for fingerprint := range baselineResults {
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	sort.Strings(fingerprints)
+	for _, fingerprint := range fingerprints {
+		diff.Fixed = append(diff.Fixed, baselineResults[fingerprint]...)
+	}
+	return diff
+}
+
+func allResults(sarif *SARIF) []Results {
+	results := []Results{}
+	if sarif == nil {
+		return results
+	}
+	for _, run := range sarif.Runs {
+		results = append(results, run.Results...)
+	}
+	return results
+}
+
+// UnauditedResults returns the results which have not been audited as not relevant
+func UnauditedResults(results []Results) []Results {
+	unaudited := []Results{}
+	for _, result := range results {
+		if result.Properties != nil && (result.Properties.Audited || result.Properties.UnifiedAuditState == string(NotRelevant)) {
+			continue
+		}
+		unaudited = append(unaudited, result)
+	}
+	return unaudited
+}
+
+// CreateScanReport creates a ScanReport listing the new and the fixed findings compared to the baseline
+func (d SarifDiff) CreateScanReport(toolName string) reporting.ScanReport {
+	scanReport := reporting.ScanReport{
+		ReportTitle: fmt.Sprintf("%v Differential Scan Report", toolName),
+		Subheaders: []reporting.Subheader{
+			{Description: "Baseline", Details: d.BaselineName},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "New findings", Details: fmt.Sprint(len(d.New))},
+			{Description: "Fixed findings", Details: fmt.Sprint(len(d.Fixed))},
+			{Description: "Unchanged findings", Details: fmt.Sprint(len(d.Unchanged))},
+		},
+		SuccessfulScan: len(UnauditedResults(d.New)) == 0,
+		ReportTime:     time.Now(),
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No new or fixed findings",
+		Headers:       []string{"Change", "Rule", "Level", "Location", "Message"},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+	addRows := func(change string, style reporting.ColumnStyle, results []Results) {
+		for _, result := range results {
+			row := reporting.ScanRow{}
+			row.AddColumn(change, style)
+			row.AddColumn(result.RuleID, 0)
+			row.AddColumn(result.Level, 0)
+			row.AddColumn(result.location(), 0)
+			message := ""
+			if result.Message != nil {
+				message = result.Message.Text
+			}
+			row.AddColumn(message, 0)
+			detailTable.Rows = append(detailTable.Rows, row)
+		}
+	}
+	addRows("new", reporting.Red, d.New)
+	addRows("fixed", reporting.Green, d.Fixed)
+	scanReport.DetailTable = detailTable
+
+	return scanReport
+}
@@ -0,0 +1,75 @@
+package format
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// checkstyleReport is the checkstyle XML format, which is written by various linters like hadolint, ESLint or ABAP ATC
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     string `xml:"line,attr"`
+	Column   string `xml:"column,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// CheckstyleToSarif converts a checkstyle XML report into SARIF, toolName names the tool which has written the report.
+// The source of an error is used as rule, errors without source are reported for a rule named like the tool.
+func CheckstyleToSarif(content []byte, toolName string) (*SARIF, error) {
+	report := checkstyleReport{}
+	if err := xml.Unmarshal(content, &report); err != nil {
+		return nil, errors.Wrap(err, "failed to parse checkstyle report")
+	}
+
+	builder := newSarifBuilder(toolName, report.Version, "")
+	for _, file := range report.Files {
+		for _, checkstyleError := range file.Errors {
+			ruleID := checkstyleError.Source
+			if len(ruleID) == 0 {
+				ruleID = toolName
+			}
+			level := checkstyleLevel(checkstyleError.Severity)
+			rule := SarifRule{ID: ruleID, Name: ruleID, DefaultConfiguration: &DefaultConfiguration{Level: level}}
+			region := Region{StartLine: atoi(checkstyleError.Line), StartColumn: atoi(checkstyleError.Column)}
+			builder.addResult(rule, level, checkstyleError.Message, file.Name, region)
+		}
+	}
+	return builder.sarif(), nil
+}
+
+func checkstyleLevel(severity string) string {
+	switch strings.ToLower(severity) {
+	case "error":
+		return "error"
+	case "info":
+		return "note"
+	case "ignore":
+		return "none"
+	default:
+		return "warning"
+	}
+}
+
+// atoi returns 0 for values which are not a number, since the position within a file is optional in most formats
+func atoi(value string) int {
+	number, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0
+	}
+	return number
+}
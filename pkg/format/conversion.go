@@ -0,0 +1,143 @@
+package format
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SarifFileWriter is the file system access needed to write SARIF reports
+type SarifFileWriter interface {
+	FileWrite(path string, content []byte, perm os.FileMode) error
+}
+
+// SarifConversionUtils is the file system access needed to convert native reports of a tool into SARIF reports
+type SarifConversionUtils interface {
+	SarifFileWriter
+	FileRead(path string) ([]byte, error)
+}
+
+// SarifConverter converts the native report of a tool into SARIF
+type SarifConverter func(content []byte) (*SARIF, error)
+
+// SarifReportPath returns the path of the SARIF report written next to the native report of a tool, e.g. target/pmd.sarif for target/pmd.xml
+func SarifReportPath(reportPath string) string {
+	return strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + ".sarif"
+}
+
+// ConvertReportToSarif converts the native report of a tool into a SARIF report next to it and returns the path of the SARIF report.
+// Absolute locations within baseDir are made relative to it in order to match the structure of the repository.
+func ConvertReportToSarif(reportPath, baseDir string, convert SarifConverter, utils SarifConversionUtils) (string, error) {
+	content, err := utils.FileRead(reportPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read report '%v'", reportPath)
+	}
+	sarif, err := convert(content)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to convert report '%v'", reportPath)
+	}
+	sarif.RelativizeLocations(baseDir)
+	sarifPath := SarifReportPath(reportPath)
+	if err := WriteSarifFile(sarif, sarifPath, utils); err != nil {
+		return "", err
+	}
+	return sarifPath, nil
+}
+
+// WriteSarifFile writes the SARIF report as JSON file
+func WriteSarifFile(sarif *SARIF, sarifPath string, utils SarifFileWriter) error {
+	content, err := json.MarshalIndent(sarif, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal SARIF report")
+	}
+	if err := utils.FileWrite(sarifPath, content, 0666); err != nil {
+		return errors.Wrapf(err, "failed to write SARIF report '%v'", sarifPath)
+	}
+	return nil
+}
+
+// RelativizeLocations makes the absolute locations of the results within baseDir relative to it.
+// Absolute locations outside of baseDir are written as file URI, relative locations are kept.
+func (s *SARIF) RelativizeLocations(baseDir string) {
+	for i := range s.Runs {
+		for j := range s.Runs[i].Results {
+			for k := range s.Runs[i].Results[j].Locations {
+				location := &s.Runs[i].Results[j].Locations[k].PhysicalLocation.ArtifactLocation
+				location.URI = relativeURI(location.URI, baseDir)
+			}
+		}
+	}
+}
+
+func relativeURI(uri, baseDir string) string {
+	if !filepath.IsAbs(uri) {
+		return filepath.ToSlash(uri)
+	}
+	if len(baseDir) > 0 {
+		if relative, err := filepath.Rel(baseDir, uri); err == nil && !strings.HasPrefix(relative, "..") {
+			return filepath.ToSlash(relative)
+		}
+	}
+	return "file://" + path.Clean("/"+filepath.ToSlash(uri))
+}
+
+// sarifBuilder collects the rules and results of a single tool run
+type sarifBuilder struct {
+	driver    Driver
+	results   []Results
+	ruleIndex map[string]int
+}
+
+func newSarifBuilder(name, version, informationURI string) *sarifBuilder {
+	return &sarifBuilder{
+		driver:    Driver{Name: name, Version: version, InformationUri: informationURI, Rules: []SarifRule{}},
+		results:   []Results{},
+		ruleIndex: map[string]int{},
+	}
+}
+
+// addRule adds the rule unless a rule with the same ID exists already and returns the index of the rule
+func (b *sarifBuilder) addRule(rule SarifRule) int {
+	if index, exists := b.ruleIndex[rule.ID]; exists {
+		return index
+	}
+	b.driver.Rules = append(b.driver.Rules, rule)
+	b.ruleIndex[rule.ID] = len(b.driver.Rules) - 1
+	return b.ruleIndex[rule.ID]
+}
+
+func (b *sarifBuilder) addResult(rule SarifRule, level, message, uri string, region Region) {
+	b.results = append(b.results, Results{
+		RuleID:    rule.ID,
+		RuleIndex: b.addRule(rule),
+		Level:     level,
+		Message:   &Message{Text: message},
+		Locations: []Location{{PhysicalLocation: PhysicalLocation{
+			ArtifactLocation: ArtifactLocation{URI: uri},
+			Region:           region,
+		}}},
+	})
+}
+
+func (b *sarifBuilder) sarif() *SARIF {
+	conversion := Conversion{}
+	conversion.Tool.Driver.Name = "Piper " + b.driver.Name + " to SARIF converter"
+	conversion.Tool.Driver.InformationUri = "https://github.com/SAP/jenkins-library"
+	conversion.Invocation.ExecutionSuccessful = true
+	conversion.Invocation.Properties = &InvocationProperties{Platform: runtime.GOOS}
+
+	return &SARIF{
+		Schema:  "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []Runs{{
+			Results:    b.results,
+			Tool:       Tool{Driver: b.driver},
+			Conversion: &conversion,
+		}},
+	}
+}
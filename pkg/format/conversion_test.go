@@ -0,0 +1,216 @@
+//go:build unit
+// +build unit
+
+package format
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckstyleToSarif(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		report := `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3">
+  <file name="Dockerfile">
+    <error line="1" column="1" severity="error" message="Always tag the version of an image explicitly" source="DL3006" />
+    <error line="4" column="1" severity="info" message="Delete the apt-get lists" source="DL3009" />
+    <error line="7" severity="warning" message="Pin versions" source="DL3006" />
+  </file>
+  <file name="build/Dockerfile">
+    <error line="" severity="unknown" message="Parse error" />
+  </file>
+</checkstyle>`
+
+		sarif, err := CheckstyleToSarif([]byte(report), "hadolint")
+
+		require.NoError(t, err)
+		assert.Equal(t, "2.1.0", sarif.Version)
+		require.Len(t, sarif.Runs, 1)
+		run := sarif.Runs[0]
+		assert.Equal(t, "hadolint", run.Tool.Driver.Name)
+		assert.Equal(t, "4.3", run.Tool.Driver.Version)
+		assert.Equal(t, []string{"DL3006", "DL3009", "hadolint"}, ruleIDs(run))
+		require.Len(t, run.Results, 4)
+		assert.Equal(t, Results{
+			RuleID:  "DL3006",
+			Level:   "error",
+			Message: &Message{Text: "Always tag the version of an image explicitly"},
+			Locations: []Location{{PhysicalLocation: PhysicalLocation{
+				ArtifactLocation: ArtifactLocation{URI: "Dockerfile"},
+				Region:           Region{StartLine: 1, StartColumn: 1},
+			}}},
+		}, run.Results[0])
+		assert.Equal(t, "note", run.Results[1].Level)
+		assert.Equal(t, 1, run.Results[1].RuleIndex)
+		assert.Equal(t, "warning", run.Results[2].Level)
+		assert.Equal(t, 0, run.Results[2].RuleIndex)
+		assert.Equal(t, "hadolint", run.Results[3].RuleID)
+		assert.Equal(t, "warning", run.Results[3].Level)
+		assert.Equal(t, Region{}, run.Results[3].Locations[0].PhysicalLocation.Region)
+	})
+
+	t.Run("invalid report", func(t *testing.T) {
+		_, err := CheckstyleToSarif([]byte("no xml"), "hadolint")
+
+		assert.EqualError(t, err, "failed to parse checkstyle report: EOF")
+	})
+}
+
+func TestPmdToSarif(t *testing.T) {
+	report := `<?xml version="1.0" encoding="UTF-8"?>
+<pmd xmlns="http://pmd.sourceforge.net/report/2.0.0" version="6.29.0" timestamp="2023-01-01T00:00:00.000">
+  <file name="/workspace/app/src/main/java/App.java">
+    <violation beginline="3" endline="3" begincolumn="1" endcolumn="25" rule="UnusedImports" ruleset="Best Practices" package="app" class="App" externalInfoUrl="https://pmd.github.io/pmd-6.29.0/pmd_rules_java_bestpractices.html#unusedimports" priority="4">
+Avoid unused imports such as 'java.util.List'
+    </violation>
+    <violation beginline="10" endline="12" begincolumn="5" endcolumn="6" rule="EmptyCatchBlock" ruleset="Error Prone" priority="2">
+Avoid empty catch blocks
+    </violation>
+  </file>
+</pmd>`
+
+	sarif, err := PmdToSarif([]byte(report))
+
+	require.NoError(t, err)
+	run := sarif.Runs[0]
+	assert.Equal(t, "PMD", run.Tool.Driver.Name)
+	assert.Equal(t, "6.29.0", run.Tool.Driver.Version)
+	assert.Equal(t, []string{"UnusedImports", "EmptyCatchBlock"}, ruleIDs(run))
+	assert.Equal(t, "https://pmd.github.io/pmd-6.29.0/pmd_rules_java_bestpractices.html#unusedimports", run.Tool.Driver.Rules[0].HelpURI)
+	assert.Equal(t, []string{"Best Practices"}, run.Tool.Driver.Rules[0].Properties.Tags)
+	require.Len(t, run.Results, 2)
+	assert.Equal(t, "note", run.Results[0].Level)
+	assert.Equal(t, "Avoid unused imports such as 'java.util.List'", run.Results[0].Message.Text)
+	assert.Equal(t, PhysicalLocation{
+		ArtifactLocation: ArtifactLocation{URI: "/workspace/app/src/main/java/App.java"},
+		Region:           Region{StartLine: 3, StartColumn: 1, EndLine: 3, EndColumn: 25},
+	}, run.Results[0].Locations[0].PhysicalLocation)
+	assert.Equal(t, "error", run.Results[1].Level)
+	assert.Equal(t, 1, run.Results[1].RuleIndex)
+}
+
+func TestSpotBugsToSarif(t *testing.T) {
+	report := `<?xml version="1.0" encoding="UTF-8"?>
+<BugCollection version="4.1.4" sequence="0" timestamp="1672531200000" analysisTimestamp="1672531200000" release="">
+  <Project projectName="app">
+    <Jar>/workspace/app/target/classes</Jar>
+    <SrcDir>/workspace/app/src/main/java</SrcDir>
+    <SrcDir>/workspace/app/target/generated-sources/annotations</SrcDir>
+  </Project>
+  <BugInstance type="NP_NULL_ON_SOME_PATH" priority="1" rank="6" abbrev="NP" category="CORRECTNESS">
+    <ShortMessage>Possible null pointer dereference</ShortMessage>
+    <LongMessage>Possible null pointer dereference of value in app.App.run()</LongMessage>
+    <Class classname="app.App" primary="true">
+      <SourceLine classname="app.App" start="1" end="30" sourcefile="App.java" sourcepath="app/App.java" />
+    </Class>
+    <Method classname="app.App" name="run" signature="()V" isStatic="false" primary="true">
+      <SourceLine classname="app.App" start="10" end="20" sourcefile="App.java" sourcepath="app/App.java" />
+    </Method>
+    <SourceLine classname="app.App" start="12" end="12" sourcefile="App.java" sourcepath="app/App.java" />
+    <SourceLine classname="app.App" primary="true" start="15" end="15" sourcefile="App.java" sourcepath="app/App.java" />
+  </BugInstance>
+  <BugInstance type="SE_BAD_FIELD" priority="3" rank="19" abbrev="Se" category="BAD_PRACTICE">
+    <Class classname="app.Model" primary="true">
+      <SourceLine classname="app.Model" start="3" end="9" sourcefile="Model.java" sourcepath="app/Model.java" />
+    </Class>
+  </BugInstance>
+  <BugPattern type="NP_NULL_ON_SOME_PATH" abbrev="NP" category="CORRECTNESS">
+    <ShortDescription>Possible null pointer dereference</ShortDescription>
+    <Details>There is a branch of statement that, if executed, guarantees that a null value will be dereferenced.</Details>
+  </BugPattern>
+</BugCollection>`
+
+	sarif, err := SpotBugsToSarif([]byte(report))
+
+	require.NoError(t, err)
+	run := sarif.Runs[0]
+	assert.Equal(t, "SpotBugs", run.Tool.Driver.Name)
+	assert.Equal(t, "4.1.4", run.Tool.Driver.Version)
+	assert.Equal(t, []string{"NP_NULL_ON_SOME_PATH", "SE_BAD_FIELD"}, ruleIDs(run))
+	assert.Equal(t, "Possible null pointer dereference", run.Tool.Driver.Rules[0].ShortDescription.Text)
+	assert.Equal(t, []string{"CORRECTNESS"}, run.Tool.Driver.Rules[0].Properties.Tags)
+	require.Len(t, run.Results, 2)
+	assert.Equal(t, "error", run.Results[0].Level)
+	assert.Equal(t, "Possible null pointer dereference of value in app.App.run()", run.Results[0].Message.Text)
+	assert.Equal(t, PhysicalLocation{
+		ArtifactLocation: ArtifactLocation{URI: "/workspace/app/src/main/java/app/App.java"},
+		Region:           Region{StartLine: 15, EndLine: 15},
+	}, run.Results[0].Locations[0].PhysicalLocation)
+	assert.Equal(t, "note", run.Results[1].Level)
+	assert.Equal(t, "SE_BAD_FIELD in app.Model", run.Results[1].Message.Text)
+	assert.Equal(t, PhysicalLocation{
+		ArtifactLocation: ArtifactLocation{URI: "/workspace/app/src/main/java/app/Model.java"},
+		Region:           Region{StartLine: 3, EndLine: 9},
+	}, run.Results[1].Locations[0].PhysicalLocation)
+}
+
+func TestEslintToSarif(t *testing.T) {
+	report := `[
+  {"filePath": "/workspace/src/index.js", "messages": [
+    {"ruleId": "no-unused-vars", "severity": 2, "message": "'a' is assigned a value but never used.", "line": 1, "column": 7, "endLine": 1, "endColumn": 8},
+    {"ruleId": "@typescript-eslint/no-explicit-any", "severity": 1, "message": "Unexpected any.", "line": 3, "column": 10}
+  ]},
+  {"filePath": "/workspace/src/broken.js", "messages": [
+    {"ruleId": null, "fatal": true, "severity": 2, "message": "Parsing error: Unexpected token", "line": 2, "column": 1}
+  ]},
+  {"filePath": "/workspace/src/clean.js", "messages": []}
+]`
+
+	sarif, err := EslintToSarif([]byte(report))
+
+	require.NoError(t, err)
+	run := sarif.Runs[0]
+	assert.Equal(t, "ESLint", run.Tool.Driver.Name)
+	assert.Equal(t, []string{"no-unused-vars", "@typescript-eslint/no-explicit-any", "eslint"}, ruleIDs(run))
+	assert.Equal(t, "https://eslint.org/docs/rules/no-unused-vars", run.Tool.Driver.Rules[0].HelpURI)
+	assert.Empty(t, run.Tool.Driver.Rules[1].HelpURI)
+	require.Len(t, run.Results, 3)
+	assert.Equal(t, "error", run.Results[0].Level)
+	assert.Equal(t, Region{StartLine: 1, StartColumn: 7, EndLine: 1, EndColumn: 8}, run.Results[0].Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, "warning", run.Results[1].Level)
+	assert.Equal(t, "error", run.Results[2].Level)
+	assert.Equal(t, "/workspace/src/broken.js", run.Results[2].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+
+	_, err = EslintToSarif([]byte("<checkstyle/>"))
+	assert.Contains(t, err.Error(), "failed to parse ESLint report")
+}
+
+func TestConvertReportToSarif(t *testing.T) {
+	utils := &mock.FilesMock{}
+	utils.AddFile("app/target/pmd.xml", []byte(`<pmd version="6.29.0">
+  <file name="/workspace/app/src/main/java/App.java"><violation beginline="3" rule="UnusedImports" priority="4">Unused import</violation></file>
+  <file name="/other/Lib.java"><violation beginline="1" rule="UnusedImports" priority="4">Unused import</violation></file>
+  <file name="app/src/main/java/Relative.java"><violation beginline="1" rule="UnusedImports" priority="4">Unused import</violation></file>
+</pmd>`))
+
+	sarifPath, err := ConvertReportToSarif("app/target/pmd.xml", "/workspace", PmdToSarif, utils)
+
+	require.NoError(t, err)
+	assert.Equal(t, "app/target/pmd.sarif", sarifPath)
+	sarif, err := ReadSarif(sarifPath, utils)
+	require.NoError(t, err)
+	uris := []string{}
+	for _, result := range sarif.Runs[0].Results {
+		uris = append(uris, result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	}
+	assert.Equal(t, []string{"app/src/main/java/App.java", "file:///other/Lib.java", "app/src/main/java/Relative.java"}, uris)
+	assert.Equal(t, "Piper PMD to SARIF converter", sarif.Runs[0].Conversion.Tool.Driver.Name)
+
+	t.Run("missing report", func(t *testing.T) {
+		_, err := ConvertReportToSarif("target/spotbugsXml.xml", "/workspace", SpotBugsToSarif, utils)
+
+		assert.Contains(t, err.Error(), "failed to read report 'target/spotbugsXml.xml'")
+	})
+}
+
+func ruleIDs(run Runs) []string {
+	ids := []string{}
+	for _, rule := range run.Tool.Driver.Rules {
+		ids = append(ids, rule.ID)
+	}
+	return ids
+}
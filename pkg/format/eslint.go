@@ -0,0 +1,60 @@
+package format
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// eslintFileResult is the result of a file within the report written by the ESLint json formatter
+type eslintFileResult struct {
+	FilePath string          `json:"filePath"`
+	Messages []eslintMessage `json:"messages"`
+}
+
+type eslintMessage struct {
+	RuleID    string `json:"ruleId"`
+	Severity  int    `json:"severity"`
+	Message   string `json:"message"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"endLine"`
+	EndColumn int    `json:"endColumn"`
+	Fatal     bool   `json:"fatal"`
+}
+
+// EslintToSarif converts a report of the ESLint json formatter into SARIF.
+// Messages without rule, e.g. parsing errors, are reported for the rule "eslint".
+func EslintToSarif(content []byte) (*SARIF, error) {
+	report := []eslintFileResult{}
+	if err := json.Unmarshal(content, &report); err != nil {
+		return nil, errors.Wrap(err, "failed to parse ESLint report")
+	}
+
+	builder := newSarifBuilder("ESLint", "", "https://eslint.org")
+	for _, file := range report {
+		for _, message := range file.Messages {
+			level := "warning"
+			if message.Severity == 2 || message.Fatal {
+				level = "error"
+			}
+			rule := SarifRule{ID: "eslint", Name: "eslint"}
+			if len(message.RuleID) > 0 {
+				rule = SarifRule{ID: message.RuleID, Name: message.RuleID}
+				// rules of plugins are prefixed with the plugin name, only core rules are documented on eslint.org
+				if !strings.Contains(message.RuleID, "/") {
+					rule.HelpURI = "https://eslint.org/docs/rules/" + message.RuleID
+				}
+			}
+			region := Region{
+				StartLine:   message.Line,
+				StartColumn: message.Column,
+				EndLine:     message.EndLine,
+				EndColumn:   message.EndColumn,
+			}
+			builder.addResult(rule, level, message.Message, file.FilePath, region)
+		}
+	}
+	return builder.sarif(), nil
+}
@@ -0,0 +1,76 @@
+package format
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// pmdReport is the XML report written by PMD, e.g. target/pmd.xml of the maven-pmd-plugin
+type pmdReport struct {
+	XMLName xml.Name  `xml:"pmd"`
+	Version string    `xml:"version,attr"`
+	Files   []pmdFile `xml:"file"`
+}
+
+type pmdFile struct {
+	Name       string         `xml:"name,attr"`
+	Violations []pmdViolation `xml:"violation"`
+}
+
+type pmdViolation struct {
+	BeginLine       int    `xml:"beginline,attr"`
+	EndLine         int    `xml:"endline,attr"`
+	BeginColumn     int    `xml:"begincolumn,attr"`
+	EndColumn       int    `xml:"endcolumn,attr"`
+	Rule            string `xml:"rule,attr"`
+	RuleSet         string `xml:"ruleset,attr"`
+	ExternalInfoURL string `xml:"externalInfoUrl,attr"`
+	Priority        int    `xml:"priority,attr"`
+	Message         string `xml:",chardata"`
+}
+
+// PmdToSarif converts a PMD XML report into SARIF.
+// PMD priorities 1 and 2 are reported as error, priority 3 as warning and priorities 4 and 5 as note.
+func PmdToSarif(content []byte) (*SARIF, error) {
+	report := pmdReport{}
+	if err := xml.Unmarshal(content, &report); err != nil {
+		return nil, errors.Wrap(err, "failed to parse PMD report")
+	}
+
+	builder := newSarifBuilder("PMD", report.Version, "https://pmd.github.io")
+	for _, file := range report.Files {
+		for _, violation := range file.Violations {
+			level := pmdLevel(violation.Priority)
+			rule := SarifRule{
+				ID:                   violation.Rule,
+				Name:                 violation.Rule,
+				DefaultConfiguration: &DefaultConfiguration{Level: level},
+				HelpURI:              violation.ExternalInfoURL,
+			}
+			if len(violation.RuleSet) > 0 {
+				rule.Properties = &SarifRuleProperties{Tags: []string{violation.RuleSet}}
+			}
+			region := Region{
+				StartLine:   violation.BeginLine,
+				StartColumn: violation.BeginColumn,
+				EndLine:     violation.EndLine,
+				EndColumn:   violation.EndColumn,
+			}
+			builder.addResult(rule, level, strings.TrimSpace(violation.Message), file.Name, region)
+		}
+	}
+	return builder.sarif(), nil
+}
+
+func pmdLevel(priority int) string {
+	switch {
+	case priority == 1 || priority == 2:
+		return "error"
+	case priority == 3:
+		return "warning"
+	default:
+		return "note"
+	}
+}
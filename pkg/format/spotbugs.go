@@ -0,0 +1,119 @@
+package format
+
+import (
+	"encoding/xml"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// spotBugsReport is the XML report written by SpotBugs, e.g. target/spotbugsXml.xml of the spotbugs-maven-plugin
+type spotBugsReport struct {
+	XMLName      xml.Name             `xml:"BugCollection"`
+	Version      string               `xml:"version,attr"`
+	SourceDirs   []string             `xml:"Project>SrcDir"`
+	BugInstances []spotBugsBug        `xml:"BugInstance"`
+	BugPatterns  []spotBugsBugPattern `xml:"BugPattern"`
+}
+
+type spotBugsBug struct {
+	Type         string               `xml:"type,attr"`
+	Priority     int                  `xml:"priority,attr"`
+	Category     string               `xml:"category,attr"`
+	ShortMessage string               `xml:"ShortMessage"`
+	LongMessage  string               `xml:"LongMessage"`
+	Class        spotBugsClass        `xml:"Class"`
+	SourceLines  []spotBugsSourceLine `xml:"SourceLine"`
+}
+
+type spotBugsClass struct {
+	ClassName  string             `xml:"classname,attr"`
+	SourceLine spotBugsSourceLine `xml:"SourceLine"`
+}
+
+type spotBugsSourceLine struct {
+	Start      int    `xml:"start,attr"`
+	End        int    `xml:"end,attr"`
+	SourcePath string `xml:"sourcepath,attr"`
+	Primary    bool   `xml:"primary,attr"`
+}
+
+type spotBugsBugPattern struct {
+	Type             string `xml:"type,attr"`
+	ShortDescription string `xml:"ShortDescription"`
+	Details          string `xml:"Details"`
+}
+
+// SpotBugsToSarif converts a SpotBugs XML report into SARIF.
+// The primary source line of a bug is used as location, the source path is resolved against the first source directory of the project.
+// SpotBugs priorities 1 (high) and 2 (normal) are reported as error and warning, all others as note.
+func SpotBugsToSarif(content []byte) (*SARIF, error) {
+	report := spotBugsReport{}
+	if err := xml.Unmarshal(content, &report); err != nil {
+		return nil, errors.Wrap(err, "failed to parse SpotBugs report")
+	}
+
+	patterns := map[string]spotBugsBugPattern{}
+	for _, pattern := range report.BugPatterns {
+		patterns[pattern.Type] = pattern
+	}
+	sourceDir := ""
+	if len(report.SourceDirs) > 0 {
+		sourceDir = strings.TrimSpace(report.SourceDirs[0])
+	}
+
+	builder := newSarifBuilder("SpotBugs", report.Version, "https://spotbugs.github.io")
+	for _, bug := range report.BugInstances {
+		level := spotBugsLevel(bug.Priority)
+		rule := SarifRule{ID: bug.Type, Name: bug.Type, DefaultConfiguration: &DefaultConfiguration{Level: level}}
+		if pattern, exists := patterns[bug.Type]; exists {
+			rule.ShortDescription = &Message{Text: strings.TrimSpace(pattern.ShortDescription)}
+			rule.Help = &Help{Text: strings.TrimSpace(pattern.Details)}
+		}
+		if len(bug.Category) > 0 {
+			rule.Properties = &SarifRuleProperties{Tags: []string{bug.Category}}
+		}
+
+		message := strings.TrimSpace(bug.LongMessage)
+		if len(message) == 0 {
+			message = strings.TrimSpace(bug.ShortMessage)
+		}
+		if len(message) == 0 {
+			message = bug.Type + " in " + bug.Class.ClassName
+		}
+
+		sourceLine := bug.primarySourceLine()
+		uri := sourceLine.SourcePath
+		if len(sourceDir) > 0 && len(uri) > 0 {
+			uri = path.Join(filepath.ToSlash(sourceDir), uri)
+		}
+		builder.addResult(rule, level, message, uri, Region{StartLine: sourceLine.Start, EndLine: sourceLine.End})
+	}
+	return builder.sarif(), nil
+}
+
+// primarySourceLine returns the source line SpotBugs marked as primary, the first one or the source lines of the class
+func (b spotBugsBug) primarySourceLine() spotBugsSourceLine {
+	for _, sourceLine := range b.SourceLines {
+		if sourceLine.Primary {
+			return sourceLine
+		}
+	}
+	if len(b.SourceLines) > 0 {
+		return b.SourceLines[0]
+	}
+	return b.Class.SourceLine
+}
+
+func spotBugsLevel(priority int) string {
+	switch priority {
+	case 1:
+		return "error"
+	case 2:
+		return "warning"
+	default:
+		return "note"
+	}
+}
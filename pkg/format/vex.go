@@ -0,0 +1,73 @@
+package format
+
+import (
+	"bytes"
+	"path/filepath"
+	"time"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// VEXStatement defines the assessment of a vulnerability affecting a package
+type VEXStatement struct {
+	Vulnerability string
+	Purl          string
+	Assessment    Assessment
+	// Detail optionally provides further information on the analysis, e.g. the comment of a triage within the tool
+	Detail string
+}
+
+// CreateVEX creates a CycloneDX VEX document (https://cyclonedx.org/capabilities/vex/) in JSON format containing the assessed vulnerabilities.
+// The affected packages are referenced by their package URL.
+func CreateVEX(toolName string, statements []VEXStatement) ([]byte, error) {
+	vulnerabilities := []cdx.Vulnerability{}
+	for _, statement := range statements {
+		analysis := statement.Assessment.ToVulnerabilityAnalysis()
+		analysis.Detail = statement.Detail
+		vulnerabilities = append(vulnerabilities, cdx.Vulnerability{
+			BOMRef:   statement.Vulnerability + "+" + statement.Purl,
+			ID:       statement.Vulnerability,
+			Analysis: analysis,
+			Affects:  &[]cdx.Affects{{Ref: statement.Purl}},
+		})
+	}
+
+	bom := cdx.NewBOM()
+	bom.Metadata = &cdx.Metadata{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Tools:     &[]cdx.Tool{{Vendor: "SAP", Name: toolName}},
+	}
+	bom.Vulnerabilities = &vulnerabilities
+
+	buffer := bytes.Buffer{}
+	encoder := cdx.NewBOMEncoder(&buffer, cdx.BOMFileFormatJSON)
+	encoder.SetPretty(true)
+	if err := encoder.Encode(bom); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// WriteVEX writes the VEX document for the assessed vulnerabilities of a tool into the given directory, next to the tool's other reports
+func WriteVEX(directory, toolName string, statements []VEXStatement, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	paths := []piperutils.Path{}
+	if len(statements) == 0 {
+		return paths, nil
+	}
+	vex, err := CreateVEX(toolName, statements)
+	if err != nil {
+		return paths, errors.Wrap(err, "failed to create VEX document")
+	}
+	if err := utils.MkdirAll(directory, 0777); err != nil {
+		return paths, errors.Wrap(err, "failed to create report directory")
+	}
+	vexPath := filepath.Join(directory, "piper_vex.json")
+	if err := utils.FileWrite(vexPath, vex, 0666); err != nil {
+		return paths, errors.Wrap(err, "failed to write VEX document")
+	}
+	paths = append(paths, piperutils.Path{Name: toolName + " VEX file", Target: vexPath})
+	return paths, nil
+}
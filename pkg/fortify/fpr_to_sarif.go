@@ -563,6 +563,20 @@ func Parse(sys System, projectVersion *models.ProjectVersion, data []byte, filte
 		log.Entry().Debug("request failed: remaining retries ", maxretries)
 	}
 
+	integrateAudit := func(prop *format.SarifProperties, issueInstanceID string) {
+		if err := integrateAuditData(prop, issueInstanceID, sys, projectVersion, auditData, filterSet, oneRequestPerIssueMode, maxretries); err != nil {
+			log.Entry().Debug(err)
+			maxretries = maxretries - 1
+			if maxretries >= 0 {
+				log.Entry().Debug("request failed: remaining retries ", maxretries)
+			}
+		}
+	}
+	return convertFVDL(fvdl, start, integrateAudit)
+}
+
+// convertFVDL converts the FVDL document into SARIF format, integrateAudit adds the audit data to the properties of a result
+func convertFVDL(fvdl FVDL, start time.Time, integrateAudit func(prop *format.SarifProperties, issueInstanceID string)) (format.SARIF, format.SARIF, error) {
 	//Now, we handle the sarif
 	var sarif format.SARIF
 	sarif.Schema = "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json"
@@ -769,13 +783,7 @@ func Parse(sys System, projectVersion *models.ProjectVersion, data []byte, filte
 		prop.InstanceID = fvdl.Vulnerabilities.Vulnerability[i].InstanceInfo.InstanceID
 		prop.RuleGUID = fvdl.Vulnerabilities.Vulnerability[i].ClassInfo.ClassID
 		//Get the audit data
-		if err := integrateAuditData(prop, fvdl.Vulnerabilities.Vulnerability[i].InstanceInfo.InstanceID, sys, projectVersion, auditData, filterSet, oneRequestPerIssueMode, maxretries); err != nil {
-			log.Entry().Debug(err)
-			maxretries = maxretries - 1
-			if maxretries >= 0 {
-				log.Entry().Debug("request failed: remaining retries ", maxretries)
-			}
-		}
+		integrateAudit(prop, fvdl.Vulnerabilities.Vulnerability[i].InstanceInfo.InstanceID)
 		result.Properties = prop
 
 		sarif.Runs[0].Results = append(sarif.Runs[0].Results, result)
@@ -0,0 +1,370 @@
+package fortify
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// analysisTagID is the GUID of the Fortify "Analysis" custom tag which holds the audit decision of an issue
+const analysisTagID = "87f2364f-dcd4-49e6-861d-f8d3f351686b"
+
+// LocalAudit contains the audit decisions of an audit XML file like the audit.xml of an FPR or an export of Audit Workbench
+type LocalAudit struct {
+	Issues map[string]LocalAuditIssue
+}
+
+// LocalAuditIssue is the audit decision on a single issue instance
+type LocalAuditIssue struct {
+	InstanceID string
+	Suppressed bool
+	Analysis   string
+	Comment    string
+}
+
+type auditXML struct {
+	XMLName xml.Name        `xml:"Audit"`
+	Issues  []auditXMLIssue `xml:"IssueList>Issue"`
+}
+
+type auditXMLIssue struct {
+	InstanceID string            `xml:"instanceId,attr"`
+	Suppressed bool              `xml:"suppressed,attr"`
+	Tags       []auditXMLTag     `xml:"Tag"`
+	Comments   []auditXMLComment `xml:"ThreadedComments>Comment"`
+}
+
+type auditXMLTag struct {
+	ID    string `xml:"id,attr"`
+	Value string `xml:"Value"`
+}
+
+type auditXMLComment struct {
+	Content   string `xml:"Content"`
+	Timestamp string `xml:"Timestamp"`
+}
+
+// ParseLocalAudit parses the contents of an audit XML file
+func ParseLocalAudit(data []byte) (*LocalAudit, error) {
+	var audit auditXML
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&audit); err != nil {
+		return nil, errors.Wrap(err, "failed to parse audit XML")
+	}
+	localAudit := &LocalAudit{Issues: map[string]LocalAuditIssue{}}
+	for _, issue := range audit.Issues {
+		auditIssue := LocalAuditIssue{InstanceID: issue.InstanceID, Suppressed: issue.Suppressed}
+		for _, tag := range issue.Tags {
+			if tag.ID == analysisTagID {
+				auditIssue.Analysis = tag.Value
+			}
+		}
+		// comments are ordered chronologically, the latest one reflects the current audit decision
+		if len(issue.Comments) > 0 {
+			auditIssue.Comment = issue.Comments[len(issue.Comments)-1].Content
+		}
+		localAudit.Issues[issue.InstanceID] = auditIssue
+	}
+	return localAudit, nil
+}
+
+// Merge adds the audit decisions of another audit, which take precedence over the existing ones
+func (a *LocalAudit) Merge(other *LocalAudit) {
+	if other == nil {
+		return
+	}
+	if a.Issues == nil {
+		a.Issues = map[string]LocalAuditIssue{}
+	}
+	for id, issue := range other.Issues {
+		a.Issues[id] = issue
+	}
+}
+
+// LocalIssue is an issue of a local scan with its priority and audit decision
+type LocalIssue struct {
+	InstanceID string
+	Category   string
+	Priority   string
+	Analysis   string
+	Suppressed bool
+}
+
+// Audited returns whether an audit decision has been taken on the issue
+func (i LocalIssue) Audited() bool {
+	return i.Suppressed || len(i.Analysis) > 0
+}
+
+// LocalScanResult contains the results of a scan which are taken from the FPR file only, without any data of Fortify SSC
+type LocalScanResult struct {
+	Sarif           format.SARIF
+	SarifSimplified format.SARIF
+	Issues          []LocalIssue
+}
+
+// Violations returns the issues which are not suppressed and either not audited or audited as exploitable or suspicious
+// and which have one of the given priorities or belong to one of the given categories
+func (r *LocalScanResult) Violations(priorities, categories []string) []LocalIssue {
+	violations := []LocalIssue{}
+	for _, issue := range r.Issues {
+		if issue.Suppressed || (len(issue.Analysis) > 0 && issue.Analysis != "Exploitable" && issue.Analysis != "Suspicious") {
+			continue
+		}
+		if piperutils.ContainsString(priorities, issue.Priority) || (len(issue.Category) > 0 && piperutils.ContainsString(categories, issue.Category)) {
+			violations = append(violations, issue)
+		}
+	}
+	return violations
+}
+
+// ConvertLocalFprToSarif converts the FPR file contents into SARIF format without querying Fortify SSC.
+// The audit decisions contained in the FPR are merged with the ones of the given audit, if any.
+func ConvertLocalFprToSarif(resultFilePath string, audit *LocalAudit) (*LocalScanResult, error) {
+	log.Entry().Debug("Extracting FPR.")
+	tmpFolder, err := os.MkdirTemp(".", "temp-")
+	defer os.RemoveAll(tmpFolder)
+	if err != nil {
+		log.Entry().WithError(err).WithField("path", tmpFolder).Debug("Creating temp directory failed")
+		return nil, err
+	}
+
+	_, err = piperutils.Unzip(resultFilePath, tmpFolder)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Entry().Debug("Reading audit file.")
+	data, err := os.ReadFile(filepath.Join(tmpFolder, "audit.fvdl"))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("cannot read audit file")
+	}
+
+	fprAudit := &LocalAudit{Issues: map[string]LocalAuditIssue{}}
+	if auditData, err := os.ReadFile(filepath.Join(tmpFolder, "audit.xml")); err == nil {
+		log.Entry().Debug("Reading audit decisions contained in the FPR.")
+		if fprAudit, err = ParseLocalAudit(auditData); err != nil {
+			return nil, err
+		}
+	}
+	fprAudit.Merge(audit)
+
+	return ParseLocal(data, fprAudit)
+}
+
+// ParseLocal parses the FVDL of an FPR file, the priority of the issues is calculated from the rule metadata
+func ParseLocal(data []byte, audit *LocalAudit) (*LocalScanResult, error) {
+	start := time.Now()
+
+	var fvdl FVDL
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&fvdl); err != nil {
+		return nil, err
+	}
+	if audit == nil {
+		audit = &LocalAudit{}
+	}
+
+	result := LocalScanResult{}
+	priorities := map[string]string{}
+	for _, vulnerability := range fvdl.Vulnerabilities.Vulnerability {
+		issue := LocalIssue{
+			InstanceID: vulnerability.InstanceInfo.InstanceID,
+			Category:   vulnerability.ClassInfo.Type,
+			Priority:   localPriority(fvdl, vulnerability),
+		}
+		if auditIssue, ok := audit.Issues[issue.InstanceID]; ok {
+			issue.Analysis = auditIssue.Analysis
+			issue.Suppressed = auditIssue.Suppressed
+		}
+		priorities[issue.InstanceID] = issue.Priority
+		result.Issues = append(result.Issues, issue)
+	}
+
+	integrateAudit := func(prop *format.SarifProperties, issueInstanceID string) {
+		integrateLocalAuditData(prop, priorities[issueInstanceID], audit.Issues[issueInstanceID])
+	}
+	sarif, sarifSimplified, err := convertFVDL(fvdl, start, integrateAudit)
+	if err != nil {
+		return nil, err
+	}
+	result.Sarif = sarif
+	result.SarifSimplified = sarifSimplified
+	return &result, nil
+}
+
+// localPriority calculates the Fortify priority of an issue from the impact and the likelihood of its rule,
+// as done by Fortify SSC and Audit Workbench for the default "Fortify Priority Order" folders
+func localPriority(fvdl FVDL, vulnerability Vulnerability) string {
+	impact, accuracy, probability := 0.0, 0.0, 0.0
+	for _, rule := range fvdl.EngineData.RuleInfo {
+		if rule.RuleID != vulnerability.ClassInfo.ClassID {
+			continue
+		}
+		for _, group := range rule.MetaInfoGroup {
+			value, _ := strconv.ParseFloat(group.Data, 64)
+			switch group.Name {
+			case "Impact":
+				impact = value
+			case "Accuracy":
+				accuracy = value
+			case "Probability":
+				probability = value
+			}
+		}
+		break
+	}
+	confidence, _ := strconv.ParseFloat(vulnerability.InstanceInfo.Confidence, 64)
+	likelihood := (accuracy * confidence * probability) / 25
+
+	switch {
+	case impact >= 2.5 && likelihood >= 2.5:
+		return "Critical"
+	case impact >= 2.5:
+		return "High"
+	case likelihood >= 2.5:
+		return "Medium"
+	default:
+		return "Low"
+	}
+}
+
+func integrateLocalAuditData(ruleProp *format.SarifProperties, priority string, auditIssue LocalAuditIssue) {
+	ruleProp.ToolSeverity = priority
+	switch priority {
+	case "Critical":
+		ruleProp.ToolSeverityIndex = 5
+	case "High":
+		ruleProp.ToolSeverityIndex = 3
+	case "Medium":
+		ruleProp.ToolSeverityIndex = 2
+	case "Low":
+		ruleProp.ToolSeverityIndex = 1
+	}
+	// without a filter set the issues are grouped into the folders of the priorities
+	ruleProp.FortifyCategory = priority
+	ruleProp.Audited = auditIssue.Suppressed || len(auditIssue.Analysis) > 0
+	ruleProp.ToolAuditMessage = auditIssue.Comment
+	ruleProp.ToolState = "Unreviewed"
+	if len(auditIssue.Analysis) > 0 {
+		ruleProp.ToolState = auditIssue.Analysis
+	} else if auditIssue.Suppressed {
+		ruleProp.ToolState = "Suppressed"
+	}
+	switch ruleProp.ToolState {
+	case "Exploitable":
+		ruleProp.ToolStateIndex = 5
+	case "Suspicious":
+		ruleProp.ToolStateIndex = 4
+	case "Bad Practice":
+		ruleProp.ToolStateIndex = 3
+	case "Reliability Issue":
+		ruleProp.ToolStateIndex = 2
+	case "Not an Issue":
+		ruleProp.ToolStateIndex = 1
+	}
+}
+
+// CreateLocalReportData creates the report data of a local scan
+func CreateLocalReportData(projectName, projectVersion string, result *LocalScanResult, violations int) FortifyReportData {
+	data := FortifyReportData{
+		ToolName:       "fortify",
+		ProjectName:    projectName,
+		ProjectVersion: projectVersion,
+		Violations:     violations,
+	}
+	for _, issue := range result.Issues {
+		switch {
+		case issue.Suppressed:
+			data.Suppressed++
+		case issue.Analysis == "Suspicious":
+			data.Suspicious++
+		case issue.Analysis == "Exploitable":
+			data.Exploitable++
+		}
+	}
+	return data
+}
+
+// CreateLocalCustomReport creates a ScanReport of a local scan with the issues grouped by priority and category
+func CreateLocalCustomReport(data FortifyReportData, result *LocalScanResult) reporting.ScanReport {
+	scanReport := reporting.ScanReport{
+		ReportTitle: "Fortify SAST Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Fortify project name", Details: data.ProjectName},
+			{Description: "Fortify project version", Details: data.ProjectVersion},
+			{Description: "Fortify SSC", Details: "not used, results are taken from the local FPR file"},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Number of compliance violations", Details: fmt.Sprint(data.Violations)},
+			{Description: "Number of issues suppressed", Details: fmt.Sprint(data.Suppressed)},
+			{Description: "Number of suspicious issues", Details: fmt.Sprint(data.Suspicious)},
+			{Description: "Number of exploitable issues", Details: fmt.Sprint(data.Exploitable)},
+		},
+		ReportTime: time.Now(),
+	}
+
+	type issueGroup struct {
+		priority, category string
+		total, audited     int
+	}
+	groups := map[string]*issueGroup{}
+	for _, issue := range result.Issues {
+		key := issue.Priority + "|" + issue.Category
+		if groups[key] == nil {
+			groups[key] = &issueGroup{priority: issue.Priority, category: issue.Category}
+		}
+		groups[key].total++
+		if issue.Audited() {
+			groups[key].audited++
+		}
+	}
+	priorityOrder := map[string]int{"Critical": 0, "High": 1, "Medium": 2, "Low": 3}
+	sortedGroups := []*issueGroup{}
+	for _, group := range groups {
+		sortedGroups = append(sortedGroups, group)
+	}
+	sort.Slice(sortedGroups, func(i, j int) bool {
+		if sortedGroups[i].priority != sortedGroups[j].priority {
+			return priorityOrder[sortedGroups[i].priority] < priorityOrder[sortedGroups[j].priority]
+		}
+		return sortedGroups[i].category < sortedGroups[j].category
+	})
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No findings detected",
+		Headers: []string{
+			"Priority",
+			"Category",
+			"Total count",
+			"Audited count",
+		},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+	for _, group := range sortedGroups {
+		row := reporting.ScanRow{}
+		row.AddColumn(group.priority, 0)
+		row.AddColumn(group.category, 0)
+		row.AddColumn(fmt.Sprint(group.total), 0)
+		row.AddColumn(fmt.Sprint(group.audited), 0)
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+
+	scanReport.DetailTable = detailTable
+	scanReport.SuccessfulScan = data.Violations == 0
+
+	return scanReport
+}
@@ -0,0 +1,208 @@
+//go:build unit
+// +build unit
+
+package fortify
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const localTestFvdl = `<?xml version="1.0" encoding="UTF-8"?>
+<FVDL xmlns="xmlns://www.fortifysoftware.com/schema/fvdl" version="1.12">
+<Build>
+  <SourceBasePath>/home/user/project</SourceBasePath>
+</Build>
+<Vulnerabilities>
+<Vulnerability>
+  <ClassInfo>
+    <ClassID>RULE-SQL</ClassID>
+    <Type>SQL Injection</Type>
+  </ClassInfo>
+  <InstanceInfo>
+    <InstanceID>INSTANCE-1</InstanceID>
+    <InstanceSeverity>5.0</InstanceSeverity>
+    <Confidence>5.0</Confidence>
+  </InstanceInfo>
+  <AnalysisInfo><Unified><Trace><Primary><Entry>
+    <Node isDefault="true"><SourceLocation path="src/db.java" line="10" colStart="0" colEnd="0"/></Node>
+  </Entry></Primary></Trace></Unified></AnalysisInfo>
+</Vulnerability>
+<Vulnerability>
+  <ClassInfo>
+    <ClassID>RULE-SQL</ClassID>
+    <Type>SQL Injection</Type>
+  </ClassInfo>
+  <InstanceInfo>
+    <InstanceID>INSTANCE-2</InstanceID>
+    <InstanceSeverity>5.0</InstanceSeverity>
+    <Confidence>5.0</Confidence>
+  </InstanceInfo>
+  <AnalysisInfo><Unified><Trace><Primary><Entry>
+    <Node isDefault="true"><SourceLocation path="src/db.java" line="20" colStart="0" colEnd="0"/></Node>
+  </Entry></Primary></Trace></Unified></AnalysisInfo>
+</Vulnerability>
+<Vulnerability>
+  <ClassInfo>
+    <ClassID>RULE-LOG</ClassID>
+    <Type>Log Forging</Type>
+  </ClassInfo>
+  <InstanceInfo>
+    <InstanceID>INSTANCE-3</InstanceID>
+    <InstanceSeverity>2.0</InstanceSeverity>
+    <Confidence>2.0</Confidence>
+  </InstanceInfo>
+  <AnalysisInfo><Unified><Trace><Primary><Entry>
+    <Node isDefault="true"><SourceLocation path="src/log.java" line="3" colStart="0" colEnd="0"/></Node>
+  </Entry></Primary></Trace></Unified></AnalysisInfo>
+</Vulnerability>
+</Vulnerabilities>
+<EngineData>
+  <RuleInfo>
+    <Rule id="RULE-SQL">
+      <MetaInfo>
+        <Group name="Accuracy">5</Group>
+        <Group name="Impact">5</Group>
+        <Group name="Probability">4</Group>
+      </MetaInfo>
+    </Rule>
+    <Rule id="RULE-LOG">
+      <MetaInfo>
+        <Group name="Accuracy">4</Group>
+        <Group name="Impact">1</Group>
+        <Group name="Probability">2</Group>
+      </MetaInfo>
+    </Rule>
+  </RuleInfo>
+</EngineData>
+</FVDL>`
+
+const localTestAudit = `<?xml version="1.0" encoding="UTF-8"?>
+<Audit xmlns="xmlns://www.fortify.com/schema/audit" version="4.3">
+  <IssueList>
+    <Issue instanceId="INSTANCE-1" suppressed="false">
+      <Tag id="87f2364f-dcd4-49e6-861d-f8d3f351686b"><Value>Not an Issue</Value></Tag>
+      <ThreadedComments>
+        <Comment><Content>first look</Content></Comment>
+        <Comment><Content>input is validated</Content></Comment>
+      </ThreadedComments>
+    </Issue>
+    <Issue instanceId="INSTANCE-3" suppressed="true"/>
+  </IssueList>
+</Audit>`
+
+func TestParseLocal(t *testing.T) {
+	t.Run("without audit", func(t *testing.T) {
+		result, err := ParseLocal([]byte(localTestFvdl), nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, []LocalIssue{
+			{InstanceID: "INSTANCE-1", Category: "SQL Injection", Priority: "Critical"},
+			{InstanceID: "INSTANCE-2", Category: "SQL Injection", Priority: "Critical"},
+			{InstanceID: "INSTANCE-3", Category: "Log Forging", Priority: "Low"},
+		}, result.Issues)
+		if assert.Len(t, result.Sarif.Runs[0].Results, 3) {
+			assert.Equal(t, "Critical", result.Sarif.Runs[0].Results[0].Properties.ToolSeverity)
+			assert.Equal(t, 5, result.Sarif.Runs[0].Results[0].Properties.ToolSeverityIndex)
+			assert.Equal(t, "Unreviewed", result.Sarif.Runs[0].Results[0].Properties.ToolState)
+			assert.False(t, result.Sarif.Runs[0].Results[0].Properties.Audited)
+		}
+		assert.Len(t, result.SarifSimplified.Runs[0].Results, 3)
+		assert.Len(t, result.Violations([]string{"Critical", "High"}, []string{}), 2)
+	})
+
+	t.Run("with audit", func(t *testing.T) {
+		audit, err := ParseLocalAudit([]byte(localTestAudit))
+		require.NoError(t, err)
+
+		result, err := ParseLocal([]byte(localTestFvdl), audit)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Not an Issue", result.Issues[0].Analysis)
+		assert.True(t, result.Issues[2].Suppressed)
+		assert.Equal(t, "Not an Issue", result.Sarif.Runs[0].Results[0].Properties.ToolState)
+		assert.Equal(t, "input is validated", result.Sarif.Runs[0].Results[0].Properties.ToolAuditMessage)
+		assert.True(t, result.Sarif.Runs[0].Results[0].Properties.Audited)
+		assert.Equal(t, "Suppressed", result.Sarif.Runs[0].Results[2].Properties.ToolState)
+
+		violations := result.Violations([]string{"Critical", "High"}, []string{"Log Forging"})
+		if assert.Len(t, violations, 1) {
+			assert.Equal(t, "INSTANCE-2", violations[0].InstanceID)
+		}
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		_, err := ParseLocal([]byte{}, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestLocalAudit(t *testing.T) {
+	t.Run("parse", func(t *testing.T) {
+		audit, err := ParseLocalAudit([]byte(localTestAudit))
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]LocalAuditIssue{
+			"INSTANCE-1": {InstanceID: "INSTANCE-1", Analysis: "Not an Issue", Comment: "input is validated"},
+			"INSTANCE-3": {InstanceID: "INSTANCE-3", Suppressed: true},
+		}, audit.Issues)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		audit := &LocalAudit{Issues: map[string]LocalAuditIssue{
+			"INSTANCE-1": {InstanceID: "INSTANCE-1", Analysis: "Suspicious"},
+			"INSTANCE-2": {InstanceID: "INSTANCE-2", Analysis: "Exploitable"},
+		}}
+
+		audit.Merge(&LocalAudit{Issues: map[string]LocalAuditIssue{"INSTANCE-1": {InstanceID: "INSTANCE-1", Analysis: "Not an Issue"}}})
+		audit.Merge(nil)
+
+		assert.Equal(t, "Not an Issue", audit.Issues["INSTANCE-1"].Analysis)
+		assert.Equal(t, "Exploitable", audit.Issues["INSTANCE-2"].Analysis)
+	})
+
+	t.Run("invalid XML", func(t *testing.T) {
+		_, err := ParseLocalAudit([]byte("<Audit>"))
+		assert.EqualError(t, err, "failed to parse audit XML: XML syntax error on line 1: unexpected EOF")
+	})
+}
+
+func TestConvertLocalFprToSarif(t *testing.T) {
+	dir := t.TempDir()
+	fprPath := filepath.Join(dir, "result.fpr")
+	fpr, err := os.Create(fprPath)
+	require.NoError(t, err)
+	archive := zip.NewWriter(fpr)
+	for name, content := range map[string]string{"audit.fvdl": localTestFvdl, "audit.xml": localTestAudit} {
+		writer, err := archive.Create(name)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+	require.NoError(t, fpr.Close())
+
+	// the audit provided externally takes precedence over the one contained in the FPR
+	audit := &LocalAudit{Issues: map[string]LocalAuditIssue{"INSTANCE-1": {InstanceID: "INSTANCE-1", Analysis: "Exploitable"}}}
+	result, err := ConvertLocalFprToSarif(fprPath, audit)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Exploitable", result.Issues[0].Analysis)
+	assert.True(t, result.Issues[2].Suppressed)
+
+	data := CreateLocalReportData("project", "1", result, len(result.Violations([]string{"Critical"}, []string{})))
+	assert.Equal(t, FortifyReportData{ToolName: "fortify", ProjectName: "project", ProjectVersion: "1", Violations: 2, Suppressed: 1, Exploitable: 1}, data)
+
+	report := CreateLocalCustomReport(data, result)
+	assert.False(t, report.SuccessfulScan)
+	if assert.Len(t, report.DetailTable.Rows, 2) {
+		assert.Equal(t, "Critical", report.DetailTable.Rows[0].Columns[0].Content)
+		assert.Equal(t, "2", report.DetailTable.Rows[0].Columns[2].Content)
+		assert.Equal(t, "1", report.DetailTable.Rows[0].Columns[3].Content)
+	}
+}
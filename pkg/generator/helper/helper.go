@@ -180,6 +180,7 @@ func {{.CobraCmdFuncName}}() *cobra.Command {
 					{{if $.ExportPrefix}}{{ $.ExportPrefix }}.{{end}}GeneralConfig.EnvRootPath, {{ index $oRes "name" | quote }}{{- end -}}
 				){{- end }}
 				config.RemoveVaultSecretFiles()
+				{{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}RunStepPostHooks(STEP_NAME, stepConfig, metadata, &stepTelemetryData)
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
 				stepTelemetryData.PiperCommitHash = {{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GitCommit
@@ -205,7 +206,14 @@ func {{.CobraCmdFuncName}}() *cobra.Command {
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize({{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.NoTelemetry, STEP_NAME)
-			{{.StepName}}(stepConfig, &stepTelemetryData{{ range $notused, $oRes := .OutputResources}}{{ if ne (index $oRes "type") "reports" }}, &{{ index $oRes "name" }}{{ end }}{{ end }})
+			{{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}RunStepPreHooks(STEP_NAME, stepConfig, metadata)
+			{{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}RunStepWithRetry(STEP_NAME, &stepTelemetryData, func() {
+				{{- range $notused, $oRes := .OutputResources }}{{ if ne (index $oRes "type") "reports" }}
+				{{ index $oRes "name" }} = {{ index $oRes "objectname" }}{}
+				{{- end }}{{ end }}
+			}, func() {
+				{{.StepName}}(stepConfig, &stepTelemetryData{{ range $notused, $oRes := .OutputResources}}{{ if ne (index $oRes "type") "reports" }}, &{{ index $oRes "name" }}{{ end }}{{ end }})
+			})
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
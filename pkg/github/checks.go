@@ -0,0 +1,123 @@
+package github
+
+import (
+	"context"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/google/go-github/v45/github"
+	"github.com/pkg/errors"
+)
+
+// MaxAnnotationsPerRequest is the maximum number of annotations GitHub accepts per check run create/update request
+const MaxAnnotationsPerRequest = 50
+
+type githubChecksService interface {
+	CreateCheckRun(ctx context.Context, owner, repo string, opts github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error)
+	UpdateCheckRun(ctx context.Context, owner, repo string, checkRunID int64, opts github.UpdateCheckRunOptions) (*github.CheckRun, *github.Response, error)
+}
+
+// CheckRunOptions to configure the check run
+type CheckRunOptions struct {
+	Owner       string
+	Repository  string
+	Name        string
+	HeadSHA     string
+	DetailsURL  string
+	Title       string
+	Summary     string
+	Text        string
+	Conclusion  string
+	Annotations []*github.CheckRunAnnotation
+}
+
+// PublishCheckRun creates a completed check run for a commit.
+// Since GitHub only accepts a limited number of annotations per request, the annotations are
+// sent in batches: the check run is created with the first batch and updated with the remaining ones.
+func PublishCheckRun(ctx context.Context, checksService githubChecksService, options *CheckRunOptions) (*github.CheckRun, error) {
+	batches := annotationBatches(options.Annotations)
+
+	status := "in_progress"
+	var conclusion *string
+	var completedAt *github.Timestamp
+	if len(batches) <= 1 {
+		status = "completed"
+		conclusion = &options.Conclusion
+		completedAt = &github.Timestamp{Time: time.Now()}
+	}
+
+	createOptions := github.CreateCheckRunOptions{
+		Name:        options.Name,
+		HeadSHA:     options.HeadSHA,
+		Status:      &status,
+		Conclusion:  conclusion,
+		StartedAt:   &github.Timestamp{Time: time.Now()},
+		CompletedAt: completedAt,
+		Output:      checkRunOutput(options, firstBatch(batches)),
+	}
+	if len(options.DetailsURL) > 0 {
+		createOptions.DetailsURL = &options.DetailsURL
+	}
+
+	checkRun, resp, err := checksService.CreateCheckRun(ctx, options.Owner, options.Repository, createOptions)
+	if err != nil {
+		if resp != nil {
+			log.Entry().Errorf("GitHub create check run returned response code %v", resp.Status)
+		}
+		return nil, errors.Wrapf(err, "failed to create check run '%v' for commit '%v'", options.Name, options.HeadSHA)
+	}
+	log.Entry().Debugf("Check run created: %v", checkRun.GetHTMLURL())
+
+	for i := 1; i < len(batches); i++ {
+		updateOptions := github.UpdateCheckRunOptions{
+			Name:   options.Name,
+			Output: checkRunOutput(options, batches[i]),
+		}
+		if i == len(batches)-1 {
+			completed := "completed"
+			updateOptions.Status = &completed
+			updateOptions.Conclusion = &options.Conclusion
+			updateOptions.CompletedAt = &github.Timestamp{Time: time.Now()}
+		}
+		checkRun, resp, err = checksService.UpdateCheckRun(ctx, options.Owner, options.Repository, checkRun.GetID(), updateOptions)
+		if err != nil {
+			if resp != nil {
+				log.Entry().Errorf("GitHub update check run returned response code %v", resp.Status)
+			}
+			return nil, errors.Wrapf(err, "failed to add annotations to check run '%v'", options.Name)
+		}
+	}
+
+	return checkRun, nil
+}
+
+func checkRunOutput(options *CheckRunOptions, annotations []*github.CheckRunAnnotation) *github.CheckRunOutput {
+	output := github.CheckRunOutput{
+		Title:       &options.Title,
+		Summary:     &options.Summary,
+		Annotations: annotations,
+	}
+	if len(options.Text) > 0 {
+		output.Text = &options.Text
+	}
+	return &output
+}
+
+func annotationBatches(annotations []*github.CheckRunAnnotation) [][]*github.CheckRunAnnotation {
+	batches := [][]*github.CheckRunAnnotation{}
+	for start := 0; start < len(annotations); start += MaxAnnotationsPerRequest {
+		end := start + MaxAnnotationsPerRequest
+		if end > len(annotations) {
+			end = len(annotations)
+		}
+		batches = append(batches, annotations[start:end])
+	}
+	return batches
+}
+
+func firstBatch(batches [][]*github.CheckRunAnnotation) []*github.CheckRunAnnotation {
+	if len(batches) == 0 {
+		return nil
+	}
+	return batches[0]
+}
@@ -0,0 +1,133 @@
+//go:build unit
+// +build unit
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/stretchr/testify/assert"
+)
+
+type ghChecksMock struct {
+	createOptions []github.CreateCheckRunOptions
+	updateOptions []github.UpdateCheckRunOptions
+	checkRunID    int64
+	createError   error
+	updateError   error
+}
+
+func (g *ghChecksMock) CreateCheckRun(ctx context.Context, owner, repo string, opts github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error) {
+	g.createOptions = append(g.createOptions, opts)
+	if g.createError != nil {
+		return nil, &github.Response{Response: &http.Response{Status: "422"}}, g.createError
+	}
+	return &github.CheckRun{ID: &g.checkRunID, Name: &opts.Name}, &github.Response{Response: &http.Response{Status: "201"}}, nil
+}
+
+func (g *ghChecksMock) UpdateCheckRun(ctx context.Context, owner, repo string, checkRunID int64, opts github.UpdateCheckRunOptions) (*github.CheckRun, *github.Response, error) {
+	g.updateOptions = append(g.updateOptions, opts)
+	if g.updateError != nil {
+		return nil, &github.Response{Response: &http.Response{Status: "422"}}, g.updateError
+	}
+	return &github.CheckRun{ID: &checkRunID, Name: &opts.Name}, &github.Response{Response: &http.Response{Status: "200"}}, nil
+}
+
+func annotations(count int) []*github.CheckRunAnnotation {
+	result := []*github.CheckRunAnnotation{}
+	for i := 0; i < count; i++ {
+		path := fmt.Sprintf("file%v.go", i)
+		line := i + 1
+		result = append(result, &github.CheckRunAnnotation{Path: &path, StartLine: &line, EndLine: &line})
+	}
+	return result
+}
+
+func TestPublishCheckRun(t *testing.T) {
+	ctx := context.Background()
+	options := CheckRunOptions{
+		Owner:      "TEST",
+		Repository: "test",
+		Name:       "Static code checks",
+		HeadSHA:    "abcd1234",
+		Title:      "2 findings",
+		Summary:    "summary",
+		Conclusion: "failure",
+	}
+
+	t.Run("single request", func(t *testing.T) {
+		mock := ghChecksMock{checkRunID: 1}
+		opts := options
+		opts.Annotations = annotations(2)
+
+		checkRun, err := PublishCheckRun(ctx, &mock, &opts)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), checkRun.GetID())
+		assert.Len(t, mock.createOptions, 1)
+		assert.Len(t, mock.updateOptions, 0)
+		assert.Equal(t, "completed", *mock.createOptions[0].Status)
+		assert.Equal(t, "failure", *mock.createOptions[0].Conclusion)
+		assert.NotNil(t, mock.createOptions[0].CompletedAt)
+		assert.Nil(t, mock.createOptions[0].DetailsURL)
+		assert.Len(t, mock.createOptions[0].Output.Annotations, 2)
+	})
+
+	t.Run("without annotations", func(t *testing.T) {
+		mock := ghChecksMock{checkRunID: 1}
+		opts := options
+		opts.Conclusion = "success"
+		opts.DetailsURL = "https://jenkins/job/1"
+
+		_, err := PublishCheckRun(ctx, &mock, &opts)
+
+		assert.NoError(t, err)
+		assert.Len(t, mock.createOptions, 1)
+		assert.Equal(t, "success", *mock.createOptions[0].Conclusion)
+		assert.Equal(t, "https://jenkins/job/1", *mock.createOptions[0].DetailsURL)
+		assert.Len(t, mock.createOptions[0].Output.Annotations, 0)
+	})
+
+	t.Run("batched annotations", func(t *testing.T) {
+		mock := ghChecksMock{checkRunID: 42}
+		opts := options
+		opts.Annotations = annotations(120)
+
+		_, err := PublishCheckRun(ctx, &mock, &opts)
+
+		assert.NoError(t, err)
+		assert.Len(t, mock.createOptions, 1)
+		assert.Equal(t, "in_progress", *mock.createOptions[0].Status)
+		assert.Nil(t, mock.createOptions[0].Conclusion)
+		assert.Len(t, mock.createOptions[0].Output.Annotations, 50)
+		assert.Len(t, mock.updateOptions, 2)
+		assert.Nil(t, mock.updateOptions[0].Status)
+		assert.Len(t, mock.updateOptions[0].Output.Annotations, 50)
+		assert.Equal(t, "completed", *mock.updateOptions[1].Status)
+		assert.Equal(t, "failure", *mock.updateOptions[1].Conclusion)
+		assert.Len(t, mock.updateOptions[1].Output.Annotations, 20)
+		assert.Equal(t, "file119.go", *mock.updateOptions[1].Output.Annotations[19].Path)
+	})
+
+	t.Run("error on create", func(t *testing.T) {
+		mock := ghChecksMock{createError: fmt.Errorf("create error")}
+
+		_, err := PublishCheckRun(ctx, &mock, &options)
+
+		assert.EqualError(t, err, "failed to create check run 'Static code checks' for commit 'abcd1234': create error")
+	})
+
+	t.Run("error on update", func(t *testing.T) {
+		mock := ghChecksMock{checkRunID: 42, updateError: fmt.Errorf("update error")}
+		opts := options
+		opts.Annotations = annotations(51)
+
+		_, err := PublishCheckRun(ctx, &mock, &opts)
+
+		assert.EqualError(t, err, "failed to add annotations to check run 'Static code checks': update error")
+	})
+}
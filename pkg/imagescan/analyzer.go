@@ -0,0 +1,154 @@
+package imagescan
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// parser extracts the packages from the content of a file
+type parser func(name string, content []byte) ([]Package, error)
+
+var elfMagic = []byte("\x7fELF")
+
+// rpmDatabaseDirectories are the locations of the rpm database, newer distributions moved it to /usr/lib/sysimage/rpm
+var rpmDatabaseDirectories = []string{"var/lib/rpm", "usr/lib/sysimage/rpm"}
+
+type analyzer struct {
+	osRelease map[string][]byte
+	packages  []Package
+}
+
+func newAnalyzer() *analyzer {
+	return &analyzer{osRelease: map[string][]byte{}}
+}
+
+// relevant checks whether the file needs to be analyzed
+func (a *analyzer) relevant(name string, mode fs.FileMode, size int64) bool {
+	return a.parser(name, mode, size) != nil
+}
+
+// analyze reads the file if it is relevant and records the packages contained in it.
+// Files which cannot be parsed are skipped with a warning since a single broken manifest must not prevent the scan of the image.
+func (a *analyzer) analyze(name string, mode fs.FileMode, size int64, reader io.Reader) error {
+	parse := a.parser(name, mode, size)
+	if parse == nil {
+		return nil
+	}
+	buffered := bufio.NewReader(reader)
+	if isExecutable(mode) && !isArchive(name) {
+		// only ELF binaries are inspected, avoid reading scripts and other executables
+		if magic, err := buffered.Peek(len(elfMagic)); err != nil || !bytes.Equal(magic, elfMagic) {
+			return nil
+		}
+	}
+	content, err := io.ReadAll(buffered)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read '%v'", name)
+	}
+	packages, err := parse(name, content)
+	if err != nil {
+		log.Entry().WithError(err).Warnf("failed to analyze '%v'", name)
+		return nil
+	}
+	a.packages = append(a.packages, packages...)
+	return nil
+}
+
+func (a *analyzer) parser(name string, mode fs.FileMode, size int64) parser {
+	if size <= 0 || size > maxFileSize {
+		return nil
+	}
+	dir, base := path.Split(name)
+	dir = strings.TrimSuffix(dir, "/")
+	switch {
+	case name == "etc/os-release" || name == "usr/lib/os-release":
+		return a.readOSRelease
+	case name == "var/lib/dpkg/status" || (dir == "var/lib/dpkg/status.d" && !strings.HasSuffix(base, ".md5sums")):
+		return parseDpkgStatus
+	case name == "lib/apk/db/installed":
+		return parseApkInstalled
+	case piperutils.ContainsString(rpmDatabaseDirectories, dir) && base == "Packages":
+		return parseRpmBerkeleyDB
+	case piperutils.ContainsString(rpmDatabaseDirectories, dir) && base == "rpmdb.sqlite":
+		return parseRpmSqlite
+	case base == "package.json" && isNodeModule(dir):
+		return parsePackageJSON
+	case base == "METADATA" && strings.HasSuffix(dir, ".dist-info"), base == "PKG-INFO" && strings.HasSuffix(dir, ".egg-info"):
+		return parsePythonMetadata
+	case isArchive(name):
+		return parseJavaArchive
+	case isExecutable(mode):
+		return parseGoBinary
+	}
+	return nil
+}
+
+func (a *analyzer) readOSRelease(name string, content []byte) ([]Package, error) {
+	a.osRelease[name] = content
+	return nil, nil
+}
+
+// inventory completes the package URLs of the packages of the Linux distribution with the distribution detected
+func (a *analyzer) inventory() *Inventory {
+	inv := &Inventory{Packages: []Package{}}
+	for _, name := range []string{"etc/os-release", "usr/lib/os-release"} {
+		if content, ok := a.osRelease[name]; ok {
+			inv.Distribution = parseOSRelease(content)
+			break
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, pkg := range a.packages {
+		if pkg.IsOSPackage() && inv.Distribution != nil {
+			pkg.Namespace = inv.Distribution.ID
+			if len(inv.Distribution.VersionID) > 0 {
+				pkg.Qualifiers["distro"] = inv.Distribution.ID + "-" + inv.Distribution.VersionID
+			}
+		}
+		purl := pkg.Purl()
+		key := pkg.Location + "#" + purl.ToString()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		inv.Packages = append(inv.Packages, pkg)
+	}
+	sort.SliceStable(inv.Packages, func(i, j int) bool {
+		if inv.Packages[i].Location != inv.Packages[j].Location {
+			return inv.Packages[i].Location < inv.Packages[j].Location
+		}
+		return inv.Packages[i].Name < inv.Packages[j].Name
+	})
+	return inv
+}
+
+func isExecutable(mode fs.FileMode) bool {
+	return mode.IsRegular() && mode.Perm()&0111 != 0
+}
+
+func isArchive(name string) bool {
+	switch path.Ext(name) {
+	case ".jar", ".war", ".ear":
+		return true
+	}
+	return false
+}
+
+func isNodeModule(dir string) bool {
+	parent := path.Dir(dir)
+	if strings.HasPrefix(path.Base(parent), "@") {
+		parent = path.Dir(parent)
+	}
+	return path.Base(parent) == "node_modules"
+}
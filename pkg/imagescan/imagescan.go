@@ -0,0 +1,240 @@
+package imagescan
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/uuid"
+	"github.com/package-url/packageurl-go"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// maxFileSize limits the size of files which are read into memory for the analysis, e.g. archives, binaries and package databases
+const maxFileSize = 512 * 1024 * 1024
+
+// Package describes a package installed in a container image or filesystem
+type Package struct {
+	Type       string
+	Namespace  string
+	Name       string
+	Version    string
+	Qualifiers map[string]string
+	Licenses   []string
+	// Location is the path of the file the package was detected in, relative to the root of the filesystem
+	Location string
+}
+
+// Purl returns the package URL of the package
+func (p Package) Purl() packageurl.PackageURL {
+	keys := make([]string, 0, len(p.Qualifiers))
+	for key, value := range p.Qualifiers {
+		if len(value) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	qualifiers := packageurl.Qualifiers{}
+	for _, key := range keys {
+		qualifiers = append(qualifiers, packageurl.Qualifier{Key: key, Value: p.Qualifiers[key]})
+	}
+	return *packageurl.NewPackageURL(p.Type, p.Namespace, p.Name, p.Version, qualifiers, "")
+}
+
+// IsOSPackage checks whether the package is managed by the package manager of the Linux distribution
+func (p Package) IsOSPackage() bool {
+	return p.Type == "deb" || p.Type == "apk" || p.Type == "rpm"
+}
+
+// Distribution identifies the Linux distribution as described in /etc/os-release
+type Distribution struct {
+	ID         string
+	VersionID  string
+	PrettyName string
+}
+
+// Inventory contains the packages detected in a container image or filesystem
+type Inventory struct {
+	Distribution *Distribution
+	Packages     []Package
+}
+
+// Scan analyzes a container image or a filesystem and returns the installed packages.
+// The path can point to an image tarball (as created by "docker save" or "crane pull"), an OCI image layout or a directory containing a root filesystem.
+func Scan(path string) (*Inventory, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to access '%v'", path)
+	}
+	if !info.IsDir() {
+		img, err := tarball.ImageFromPath(path, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read image tarball '%v'", path)
+		}
+		return ScanImage(img)
+	}
+	if _, err := os.Stat(filepath.Join(path, "index.json")); err == nil {
+		img, err := imageFromLayout(path)
+		if err != nil {
+			return nil, err
+		}
+		return ScanImage(img)
+	}
+	return ScanFilesystem(path)
+}
+
+// ScanImage analyzes the flattened filesystem of the image
+func ScanImage(img v1.Image) (*Inventory, error) {
+	content := mutate.Extract(img)
+	defer content.Close()
+
+	a := newAnalyzer()
+	reader := tar.NewReader(content)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read image filesystem")
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := a.analyze(cleanPath(header.Name), header.FileInfo().Mode(), header.Size, reader); err != nil {
+			return nil, err
+		}
+	}
+	return a.inventory(), nil
+}
+
+// ScanFilesystem analyzes the files below the root directory, symbolic links are not followed
+func ScanFilesystem(root string) (*Inventory, error) {
+	a := newAnalyzer()
+	err := filepath.WalkDir(root, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			log.Entry().Debugf("skipping '%v': %v", file, err)
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		relative, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+		if !a.relevant(filepath.ToSlash(relative), info.Mode(), info.Size()) {
+			return nil
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			log.Entry().Debugf("skipping '%v': %v", file, err)
+			return nil
+		}
+		defer f.Close()
+		return a.analyze(filepath.ToSlash(relative), info.Mode(), info.Size(), f)
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to scan filesystem '%v'", root)
+	}
+	return a.inventory(), nil
+}
+
+// ToBOM creates a CycloneDX SBOM of the packages describing the scanned image or filesystem as component
+func (inv *Inventory) ToBOM(name, version string) *cdx.BOM {
+	bom := cdx.NewBOM()
+	bom.SerialNumber = "urn:uuid:" + uuid.New().String()
+	bom.Metadata = &cdx.Metadata{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Tools:     &[]cdx.Tool{{Vendor: "SAP", Name: "Piper container vulnerability scan"}},
+		Component: &cdx.Component{BOMRef: name, Type: cdx.ComponentTypeContainer, Name: name, Version: version},
+	}
+
+	components := []cdx.Component{}
+	refs := []string{}
+	for _, pkg := range inv.Packages {
+		packageURL := pkg.Purl()
+		purl := packageURL.ToString()
+		if piperutils.ContainsString(refs, purl) {
+			continue
+		}
+		refs = append(refs, purl)
+		component := cdx.Component{
+			BOMRef:     purl,
+			Type:       cdx.ComponentTypeLibrary,
+			Name:       pkg.Name,
+			Version:    pkg.Version,
+			PackageURL: purl,
+			Properties: &[]cdx.Property{{Name: "sap:piper:location", Value: pkg.Location}},
+		}
+		if len(pkg.Namespace) > 0 && !pkg.IsOSPackage() {
+			component.Group = pkg.Namespace
+		}
+		if len(pkg.Licenses) > 0 {
+			licenses := cdx.Licenses{}
+			for _, license := range pkg.Licenses {
+				licenses = append(licenses, cdx.LicenseChoice{License: &cdx.License{Name: license}})
+			}
+			component.Licenses = &licenses
+		}
+		components = append(components, component)
+	}
+	if inv.Distribution != nil {
+		components = append(components, cdx.Component{
+			BOMRef:      "os:" + inv.Distribution.ID,
+			Type:        cdx.ComponentTypeOS,
+			Name:        inv.Distribution.ID,
+			Version:     inv.Distribution.VersionID,
+			Description: inv.Distribution.PrettyName,
+		})
+	}
+	bom.Components = &components
+
+	dependencies := []cdx.Dependency{{Ref: name, Dependencies: &[]cdx.Dependency{}}}
+	for _, ref := range refs {
+		*dependencies[0].Dependencies = append(*dependencies[0].Dependencies, cdx.Dependency{Ref: ref})
+	}
+	bom.Dependencies = &dependencies
+	return bom
+}
+
+func imageFromLayout(path string) (v1.Image, error) {
+	index, err := layout.ImageIndexFromPath(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read OCI image layout '%v'", path)
+	}
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read index of OCI image layout '%v'", path)
+	}
+	for _, descriptor := range manifest.Manifests {
+		if descriptor.MediaType.IsImage() {
+			return index.Image(descriptor.Digest)
+		}
+	}
+	return nil, fmt.Errorf("no image found in OCI image layout '%v'", path)
+}
+
+// cleanPath returns the slash separated path relative to the root of the filesystem
+func cleanPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
@@ -0,0 +1,333 @@
+//go:build unit
+// +build unit
+
+package imagescan
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dpkgStatus = `Package: libssl3
+Status: install ok installed
+Architecture: amd64
+Source: openssl
+Version: 3.0.11-1~deb12u1
+Description: Secure Sockets Layer toolkit - shared libraries
+ This package is part of the OpenSSL project's implementation.
+
+Package: libc6
+Status: install ok installed
+Architecture: amd64
+Source: glibc (2.36-9+deb12u3)
+Version: 2.36-9+deb12u3
+
+Package: removed
+Status: deinstall ok config-files
+Architecture: amd64
+Version: 1.0
+`
+
+const apkInstalled = `C:Q1abc=
+P:musl
+V:1.2.4-r2
+A:x86_64
+L:MIT
+o:musl
+
+P:libcrypto3
+V:3.1.4-r0
+A:x86_64
+L:Apache-2.0
+o:openssl
+`
+
+type testFile struct {
+	name    string
+	content []byte
+	mode    int64
+}
+
+func layerFromFiles(t *testing.T, files []testFile) *bytes.Buffer {
+	buffer := &bytes.Buffer{}
+	writer := tar.NewWriter(buffer)
+	for _, file := range files {
+		mode := file.mode
+		if mode == 0 {
+			mode = 0644
+		}
+		require.NoError(t, writer.WriteHeader(&tar.Header{Name: file.name, Mode: mode, Size: int64(len(file.content)), Typeflag: tar.TypeReg}))
+		_, err := writer.Write(file.content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buffer
+}
+
+func javaArchive(t *testing.T, files map[string][]byte) []byte {
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+	for name, content := range files {
+		w, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func purls(inv *Inventory) []string {
+	result := []string{}
+	for _, pkg := range inv.Packages {
+		purl := pkg.Purl()
+		result = append(result, purl.ToString())
+	}
+	return result
+}
+
+func TestScanImage(t *testing.T) {
+	t.Parallel()
+
+	nestedJar := javaArchive(t, map[string][]byte{"META-INF/maven/org.yaml/snakeyaml/pom.properties": []byte("groupId=org.yaml\nartifactId=snakeyaml\nversion=1.33\n")})
+	appJar := javaArchive(t, map[string][]byte{
+		"META-INF/maven/com.sap/app/pom.properties": []byte("#Generated by Maven\ngroupId=com.sap\nartifactId=app\nversion=1.0.0\n"),
+		"BOOT-INF/lib/snakeyaml-1.33.jar":           nestedJar,
+	})
+
+	base := layerFromFiles(t, []testFile{
+		{name: "usr/lib/os-release", content: []byte("PRETTY_NAME=\"Debian GNU/Linux 12 (bookworm)\"\nNAME=\"Debian GNU/Linux\"\nVERSION_ID=\"12\"\nID=debian\n")},
+		{name: "var/lib/dpkg/status", content: []byte(dpkgStatus)},
+		{name: "usr/lib/python3/dist-packages/PyYAML-6.0.dist-info/METADATA", content: []byte("Metadata-Version: 2.1\nName: PyYAML\nVersion: 6.0\nLicense: MIT\n\nYAML parser and emitter for Python\nName: ignored\n")},
+		{name: "app/node_modules/@sap/cds/package.json", content: []byte(`{"name": "@sap/cds", "version": "7.3.0", "license": "SEE LICENSE IN LICENSE"}`)},
+		{name: "app/node_modules/lodash/package.json", content: []byte(`{"name": "lodash", "version": "4.17.20", "license": {"type": "MIT"}}`)},
+		{name: "app/node_modules/lodash/fp/package.json", content: []byte(`{"main": "../fp.js"}`)},
+		{name: "app/package.json", content: []byte(`{"name": "app", "version": "1.0.0"}`)},
+		{name: "app/start.sh", content: []byte("#!/bin/sh\nexec node index.js\n"), mode: 0755},
+	})
+	app := layerFromFiles(t, []testFile{
+		{name: "app/node_modules/lodash/.wh.package.json"},
+		{name: "app/app.jar", content: appJar},
+	})
+
+	img := empty.Image
+	for _, layer := range []*bytes.Buffer{base, app} {
+		content := layer.Bytes()
+		l, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(content)), nil })
+		require.NoError(t, err)
+		img, err = mutate.AppendLayers(img, l)
+		require.NoError(t, err)
+	}
+	path := filepath.Join(t.TempDir(), "image.tar")
+	tag, _ := name.NewTag("app:latest")
+	require.NoError(t, tarball.WriteToFile(path, tag, img))
+
+	inv, err := Scan(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, &Distribution{ID: "debian", VersionID: "12", PrettyName: "Debian GNU/Linux 12 (bookworm)"}, inv.Distribution)
+	assert.Equal(t, []string{
+		"pkg:maven/com.sap/app@1.0.0",
+		"pkg:maven/org.yaml/snakeyaml@1.33",
+		"pkg:npm/%40sap/cds@7.3.0",
+		"pkg:pypi/pyyaml@6.0",
+		"pkg:deb/debian/libc6@2.36-9+deb12u3?arch=amd64&distro=debian-12&upstream=glibc@2.36-9+deb12u3",
+		"pkg:deb/debian/libssl3@3.0.11-1~deb12u1?arch=amd64&distro=debian-12&upstream=openssl",
+	}, purls(inv))
+	assert.Equal(t, "app/app.jar!/BOOT-INF/lib/snakeyaml-1.33.jar", inv.Packages[1].Location)
+	assert.Equal(t, []string{"MIT"}, inv.Packages[3].Licenses)
+
+	t.Run("SBOM", func(t *testing.T) {
+		bom := inv.ToBOM("app", "latest")
+		assert.Equal(t, cdx.ComponentTypeContainer, bom.Metadata.Component.Type)
+		require.Len(t, *bom.Components, 7)
+		assert.Equal(t, "pkg:maven/com.sap/app@1.0.0", (*bom.Components)[0].PackageURL)
+		assert.Equal(t, "com.sap", (*bom.Components)[0].Group)
+		assert.Equal(t, cdx.ComponentTypeOS, (*bom.Components)[6].Type)
+		assert.Len(t, *(*bom.Dependencies)[0].Dependencies, 6)
+	})
+}
+
+func TestScanFilesystem(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	write := func(name string, content []byte, mode os.FileMode) {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, content, mode))
+	}
+	write("etc/os-release", []byte("NAME=\"Alpine Linux\"\nID=alpine\nVERSION_ID=3.18.4\n"), 0644)
+	write("lib/apk/db/installed", []byte(apkInstalled), 0644)
+	// the test binary is a Go binary with build information
+	executable, err := os.Executable()
+	require.NoError(t, err)
+	binary, err := os.ReadFile(executable)
+	require.NoError(t, err)
+	write("usr/local/bin/app", binary, 0755)
+
+	inv, err := Scan(root)
+
+	require.NoError(t, err)
+	assert.Equal(t, "alpine", inv.Distribution.ID)
+	result := purls(inv)
+	assert.Contains(t, result, "pkg:apk/alpine/musl@1.2.4-r2?arch=x86_64&distro=alpine-3.18.4")
+	assert.Contains(t, result, "pkg:apk/alpine/libcrypto3@3.1.4-r0?arch=x86_64&distro=alpine-3.18.4&upstream=openssl")
+
+	goPackages := map[string]Package{}
+	for _, pkg := range inv.Packages {
+		if pkg.Type == "golang" {
+			assert.Equal(t, "usr/local/bin/app", pkg.Location)
+			goPackages[pkg.Namespace+"/"+pkg.Name] = pkg
+		}
+	}
+	assert.Contains(t, goPackages, "/stdlib")
+	assert.Contains(t, goPackages, "github.com/stretchr/testify")
+}
+
+func TestParseRpmSqlite(t *testing.T) {
+	t.Parallel()
+	content, err := os.ReadFile("testdata/rpmdb.sqlite")
+	require.NoError(t, err)
+
+	packages, err := parseRpmSqlite("var/lib/rpm/rpmdb.sqlite", content)
+
+	require.NoError(t, err)
+	// gpg-pubkey is no package
+	require.Len(t, packages, 152)
+	assert.Equal(t, Package{
+		Type:       "rpm",
+		Name:       "openssl-libs",
+		Version:    "3.0.7-24.el9",
+		Qualifiers: map[string]string{"arch": "x86_64", "epoch": "1", "upstream": "openssl"},
+		Licenses:   []string{"Apache-2.0"},
+		Location:   "var/lib/rpm/rpmdb.sqlite",
+	}, packages[0])
+	// the header of bash exceeds the page size and is stored on overflow pages
+	assert.Equal(t, "bash", packages[1].Name)
+	assert.Equal(t, "5.1.8-6.el9", packages[1].Version)
+	assert.Equal(t, "filler-149", packages[151].Name)
+
+	t.Run("no database", func(t *testing.T) {
+		_, err := parseRpmSqlite("rpmdb.sqlite", []byte("no database"))
+		assert.EqualError(t, err, "not a SQLite database")
+	})
+
+	t.Run("cell count exceeds page", func(t *testing.T) {
+		content, err := os.ReadFile("testdata/rpmdb-malformed.sqlite")
+		require.NoError(t, err)
+		_, err = parseRpmSqlite("rpmdb.sqlite", content)
+		assert.ErrorContains(t, err, "cell count 65535 exceeds page 2")
+	})
+
+	t.Run("payload size exceeds database", func(t *testing.T) {
+		malformed := append([]byte{}, content...)
+		// first cell of the schema page: payload size varint of 9 bytes followed by the rowid
+		cell := int(binary.BigEndian.Uint16(malformed[sqliteHeaderSize+8:]))
+		copy(malformed[cell:], []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01})
+		_, err := parseRpmSqlite("rpmdb.sqlite", malformed)
+		assert.ErrorContains(t, err, "invalid payload size")
+	})
+}
+
+// rpmHeaderBlob creates a header blob with the string tags
+func rpmHeaderBlob(tags map[int32]string) []byte {
+	entries := &bytes.Buffer{}
+	data := &bytes.Buffer{}
+	for tag := int32(rpmTagName); tag <= rpmTagSourceRPM; tag++ {
+		value, ok := tags[tag]
+		if !ok {
+			continue
+		}
+		binary.Write(entries, binary.BigEndian, []int32{tag, rpmTypeString, int32(data.Len()), 1})
+		data.WriteString(value + "\x00")
+	}
+	blob := &bytes.Buffer{}
+	binary.Write(blob, binary.BigEndian, []int32{int32(entries.Len() / 16), int32(data.Len())})
+	blob.Write(entries.Bytes())
+	blob.Write(data.Bytes())
+	return blob.Bytes()
+}
+
+// berkeleyDB creates a hash database with one hash page referencing the values stored on overflow pages
+func berkeleyDB(values [][]byte) []byte {
+	const pageSize = 512
+	pages := [][]byte{make([]byte, pageSize), make([]byte, pageSize)}
+	meta, hash := pages[0], pages[1]
+	binary.LittleEndian.PutUint32(meta[12:], bdbHashMagic)
+	binary.LittleEndian.PutUint32(meta[20:], pageSize)
+	hash[25] = bdbPageTypeHash
+	binary.LittleEndian.PutUint16(hash[20:], uint16(2*len(values)))
+
+	itemOffset := pageSize
+	for i, value := range values {
+		// key
+		itemOffset -= 5
+		hash[itemOffset] = 1
+		binary.LittleEndian.PutUint32(hash[itemOffset+1:], uint32(i))
+		binary.LittleEndian.PutUint16(hash[bdbPageHeaderSize+4*i:], uint16(itemOffset))
+
+		// value on overflow pages
+		itemOffset -= bdbOffPageItemSize
+		hash[itemOffset] = bdbItemTypeOffPage
+		binary.LittleEndian.PutUint32(hash[itemOffset+4:], uint32(len(pages)))
+		binary.LittleEndian.PutUint32(hash[itemOffset+8:], uint32(len(value)))
+		binary.LittleEndian.PutUint16(hash[bdbPageHeaderSize+4*i+2:], uint16(itemOffset))
+		for len(value) > 0 {
+			overflow := make([]byte, pageSize)
+			overflow[25] = bdbPageTypeOverflow
+			size := copy(overflow[bdbPageHeaderSize:], value)
+			value = value[size:]
+			binary.LittleEndian.PutUint16(overflow[22:], uint16(size))
+			if len(value) > 0 {
+				binary.LittleEndian.PutUint32(overflow[16:], uint32(len(pages)+1))
+			}
+			pages = append(pages, overflow)
+		}
+	}
+	binary.LittleEndian.PutUint32(meta[32:], uint32(len(pages)-1))
+	return bytes.Join(pages, nil)
+}
+
+func TestParseRpmBerkeleyDB(t *testing.T) {
+	t.Parallel()
+	license := ""
+	for len(license) < 1000 {
+		license += "GPLv2+ and LGPLv2+ and "
+	}
+	content := berkeleyDB([][]byte{
+		rpmHeaderBlob(map[int32]string{rpmTagName: "glibc", rpmTagVersion: "2.28", rpmTagRelease: "225.el8", rpmTagArch: "x86_64", rpmTagSourceRPM: "glibc-2.28-225.el8.src.rpm", rpmTagLicense: license + "BSD"}),
+		rpmHeaderBlob(map[int32]string{rpmTagName: "gpg-pubkey", rpmTagVersion: "fd431d51", rpmTagRelease: "4ae0493b"}),
+		rpmHeaderBlob(map[int32]string{rpmTagName: "openssl-libs", rpmTagVersion: "1.1.1k", rpmTagRelease: "9.el8_7", rpmTagArch: "x86_64", rpmTagSourceRPM: "openssl-1.1.1k-9.el8_7.src.rpm"}),
+	})
+
+	packages, err := parseRpmBerkeleyDB("var/lib/rpm/Packages", content)
+
+	require.NoError(t, err)
+	require.Len(t, packages, 2)
+	assert.Equal(t, "glibc", packages[0].Name)
+	assert.Equal(t, "2.28-225.el8", packages[0].Version)
+	assert.Equal(t, map[string]string{"arch": "x86_64"}, packages[0].Qualifiers)
+	assert.Equal(t, "BSD", packages[0].Licenses[len(packages[0].Licenses)-1])
+	assert.Equal(t, "openssl-libs", packages[1].Name)
+	assert.Equal(t, "openssl", packages[1].Qualifiers["upstream"])
+
+	t.Run("no database", func(t *testing.T) {
+		_, err := parseRpmBerkeleyDB("Packages", make([]byte, 512))
+		assert.EqualError(t, err, "not a Berkeley DB hash database")
+	})
+}
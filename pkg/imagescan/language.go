@@ -0,0 +1,176 @@
+package imagescan
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"debug/buildinfo"
+	"encoding/json"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// parsePackageJSON reads an npm package installed into node_modules
+func parsePackageJSON(name string, content []byte) ([]Package, error) {
+	manifest := struct {
+		Name    string      `json:"name"`
+		Version string      `json:"version"`
+		License interface{} `json:"license"`
+	}{}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, errors.Wrap(err, "failed to parse package.json")
+	}
+	if len(manifest.Name) == 0 || len(manifest.Version) == 0 {
+		return nil, nil
+	}
+	pkg := Package{Type: "npm", Name: manifest.Name, Version: manifest.Version, Location: name}
+	if scope, packageName, found := strings.Cut(manifest.Name, "/"); found {
+		pkg.Namespace, pkg.Name = scope, packageName
+	}
+	// the license is either an SPDX expression or a deprecated object with type and url
+	switch license := manifest.License.(type) {
+	case string:
+		pkg.Licenses = splitLicenses(license)
+	case map[string]interface{}:
+		if licenseType, ok := license["type"].(string); ok {
+			pkg.Licenses = []string{licenseType}
+		}
+	}
+	return []Package{pkg}, nil
+}
+
+// parsePythonMetadata reads the core metadata of an installed Python distribution (*.dist-info/METADATA or *.egg-info/PKG-INFO)
+func parsePythonMetadata(name string, content []byte) ([]Package, error) {
+	pkg := Package{Type: "pypi", Location: name}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		// the headers end with the first empty line, the description follows
+		if len(line) == 0 {
+			break
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Name":
+			pkg.Name = strings.ToLower(strings.NewReplacer("_", "-", ".", "-").Replace(value))
+		case "Version":
+			pkg.Version = value
+		case "License":
+			// some distributions put the full license text into the field
+			if len(value) > 0 && value != "UNKNOWN" && len(value) < 100 {
+				pkg.Licenses = []string{value}
+			}
+		}
+	}
+	if len(pkg.Name) == 0 || len(pkg.Version) == 0 {
+		return nil, nil
+	}
+	return []Package{pkg}, nil
+}
+
+// parseJavaArchive reads the Maven coordinates of a jar, war or ear file including the archives nested in e.g. BOOT-INF/lib or WEB-INF/lib
+func parseJavaArchive(name string, content []byte) ([]Package, error) {
+	return javaArchivePackages(name, content, true)
+}
+
+func javaArchivePackages(name string, content []byte, nested bool) ([]Package, error) {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open java archive")
+	}
+	packages := []Package{}
+	for _, file := range archive.File {
+		switch {
+		case path.Base(file.Name) == "pom.properties" && strings.HasPrefix(file.Name, "META-INF/maven/"):
+			properties, err := readZipFile(file)
+			if err != nil {
+				return nil, err
+			}
+			if pkg, ok := mavenPackage(name, properties); ok {
+				packages = append(packages, pkg)
+			}
+		case nested && isArchive(file.Name) && file.UncompressedSize64 <= maxFileSize:
+			nestedContent, err := readZipFile(file)
+			if err != nil {
+				return nil, err
+			}
+			nestedPackages, err := javaArchivePackages(name+"!/"+file.Name, nestedContent, false)
+			if err != nil {
+				// a broken nested archive must not prevent the analysis of the other dependencies
+				continue
+			}
+			packages = append(packages, nestedPackages...)
+		}
+	}
+	return packages, nil
+}
+
+func mavenPackage(name string, properties []byte) (Package, bool) {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(properties))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, value, found := strings.Cut(line, "="); found {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	if len(values["groupId"]) == 0 || len(values["artifactId"]) == 0 || len(values["version"]) == 0 {
+		return Package{}, false
+	}
+	return Package{Type: "maven", Namespace: values["groupId"], Name: values["artifactId"], Version: values["version"], Location: name}, true
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open '%v'", file.Name)
+	}
+	defer reader.Close()
+	content, err := io.ReadAll(io.LimitReader(reader, maxFileSize))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read '%v'", file.Name)
+	}
+	return content, nil
+}
+
+// parseGoBinary reads the modules compiled into a Go binary from its build information
+func parseGoBinary(name string, content []byte) ([]Package, error) {
+	info, err := buildinfo.Read(bytes.NewReader(content))
+	if err != nil {
+		// binaries not built with Go or without build information
+		return nil, nil
+	}
+	packages := []Package{}
+	// the Go version may carry additional settings, e.g. go1.21.0 X:boringcrypto
+	goVersion, _, _ := strings.Cut(info.GoVersion, " ")
+	if version := strings.TrimPrefix(goVersion, "go"); len(version) > 0 {
+		packages = append(packages, goPackage(name, "stdlib", version))
+	}
+	if len(info.Main.Path) > 0 && len(info.Main.Version) > 0 && info.Main.Version != "(devel)" {
+		packages = append(packages, goPackage(name, info.Main.Path, info.Main.Version))
+	}
+	for _, dep := range info.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		if len(dep.Version) > 0 && !strings.HasPrefix(dep.Path, ".") && !strings.HasPrefix(dep.Path, "/") {
+			packages = append(packages, goPackage(name, dep.Path, dep.Version))
+		}
+	}
+	return packages, nil
+}
+
+func goPackage(name, module, version string) Package {
+	namespace, moduleName := path.Split(module)
+	return Package{Type: "golang", Namespace: strings.TrimSuffix(namespace, "/"), Name: moduleName, Version: version, Location: name}
+}
@@ -0,0 +1,150 @@
+package imagescan
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// parseOSRelease reads the identification of the distribution, see https://www.freedesktop.org/software/systemd/man/os-release.html
+func parseOSRelease(content []byte) *Distribution {
+	distribution := &Distribution{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !found || strings.HasPrefix(key, "#") {
+			continue
+		}
+		value = strings.Trim(value, `"'`)
+		switch key {
+		case "ID":
+			distribution.ID = strings.ToLower(value)
+		case "VERSION_ID":
+			distribution.VersionID = value
+		case "PRETTY_NAME":
+			distribution.PrettyName = value
+		}
+	}
+	if len(distribution.ID) == 0 {
+		return nil
+	}
+	return distribution
+}
+
+// parseDpkgStatus reads the installed packages from the dpkg database /var/lib/dpkg/status.
+// Distroless images provide one file per package in /var/lib/dpkg/status.d with the same format.
+func parseDpkgStatus(name string, content []byte) ([]Package, error) {
+	packages := []Package{}
+	for _, paragraph := range controlParagraphs(content) {
+		if len(paragraph["Package"]) == 0 || len(paragraph["Version"]) == 0 {
+			continue
+		}
+		if status, ok := paragraph["Status"]; ok && !strings.HasSuffix(status, " installed") {
+			continue
+		}
+		pkg := Package{
+			Type:       "deb",
+			Namespace:  "debian",
+			Name:       paragraph["Package"],
+			Version:    paragraph["Version"],
+			Qualifiers: map[string]string{"arch": paragraph["Architecture"]},
+			Location:   name,
+		}
+		// the source package is given as "name" or "name (version)" if its version differs from the binary package
+		if source := paragraph["Source"]; len(source) > 0 {
+			sourceName, sourceVersion, found := strings.Cut(source, " ")
+			upstream := sourceName
+			if found {
+				upstream += "@" + strings.Trim(strings.TrimSpace(sourceVersion), "()")
+			}
+			pkg.Qualifiers["upstream"] = upstream
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+// parseApkInstalled reads the installed packages from the apk database /lib/apk/db/installed
+func parseApkInstalled(name string, content []byte) ([]Package, error) {
+	packages := []Package{}
+	var pkg *Package
+	add := func() {
+		if pkg != nil && len(pkg.Name) > 0 && len(pkg.Version) > 0 {
+			packages = append(packages, *pkg)
+		}
+		pkg = nil
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) == 0 {
+			add()
+			continue
+		}
+		if len(line) < 2 || line[1] != ':' {
+			continue
+		}
+		if pkg == nil {
+			pkg = &Package{Type: "apk", Namespace: "alpine", Qualifiers: map[string]string{}, Location: name}
+		}
+		value := line[2:]
+		switch line[0] {
+		case 'P':
+			pkg.Name = value
+		case 'V':
+			pkg.Version = value
+		case 'A':
+			pkg.Qualifiers["arch"] = value
+		case 'o':
+			pkg.Qualifiers["upstream"] = value
+		case 'L':
+			pkg.Licenses = splitLicenses(value)
+		}
+	}
+	add()
+	for i := range packages {
+		if packages[i].Qualifiers["upstream"] == packages[i].Name {
+			delete(packages[i].Qualifiers, "upstream")
+		}
+	}
+	return packages, scanner.Err()
+}
+
+// controlParagraphs parses the Debian control file format, continuation lines of multi-line fields are ignored
+func controlParagraphs(content []byte) []map[string]string {
+	paragraphs := []map[string]string{}
+	paragraph := map[string]string{}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if len(strings.TrimSpace(line)) == 0 {
+			if len(paragraph) > 0 {
+				paragraphs = append(paragraphs, paragraph)
+				paragraph = map[string]string{}
+			}
+			continue
+		}
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		if key, value, found := strings.Cut(line, ":"); found {
+			paragraph[key] = strings.TrimSpace(value)
+		}
+	}
+	if len(paragraph) > 0 {
+		paragraphs = append(paragraphs, paragraph)
+	}
+	return paragraphs
+}
+
+// splitLicenses splits simple license expressions like "MIT AND BSD-3-Clause" into single licenses
+func splitLicenses(expression string) []string {
+	licenses := []string{}
+	for _, field := range strings.Fields(strings.NewReplacer("(", " ", ")", " ").Replace(expression)) {
+		switch strings.ToUpper(field) {
+		case "AND", "OR", "WITH":
+			continue
+		}
+		licenses = append(licenses, field)
+	}
+	return licenses
+}
@@ -0,0 +1,257 @@
+package imagescan
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// rpm header tags, see https://github.com/rpm-software-management/rpm/blob/master/include/rpm/rpmtag.h
+const (
+	rpmTagName      = 1000
+	rpmTagVersion   = 1001
+	rpmTagRelease   = 1002
+	rpmTagEpoch     = 1003
+	rpmTagLicense   = 1014
+	rpmTagArch      = 1022
+	rpmTagSourceRPM = 1044
+)
+
+// rpm header data types
+const (
+	rpmTypeInt32       = 4
+	rpmTypeString      = 6
+	rpmTypeStringArray = 8
+	rpmTypeI18NString  = 9
+)
+
+// parseRpmBerkeleyDB reads the installed packages from the Berkeley DB hash database /var/lib/rpm/Packages used up to RHEL 8
+func parseRpmBerkeleyDB(name string, content []byte) ([]Package, error) {
+	blobs, err := berkeleyDBValues(content)
+	if err != nil {
+		return nil, err
+	}
+	return rpmPackages(name, blobs), nil
+}
+
+// parseRpmSqlite reads the installed packages from the SQLite database rpmdb.sqlite used since RHEL 9 and Fedora 33
+func parseRpmSqlite(name string, content []byte) ([]Package, error) {
+	db, err := newSqliteReader(content)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.tableRows("Packages")
+	if err != nil {
+		return nil, err
+	}
+	blobs := [][]byte{}
+	for _, row := range rows {
+		// table Packages(hnum INTEGER PRIMARY KEY, blob BLOB NOT NULL)
+		if len(row) > 1 {
+			if blob, ok := row[1].([]byte); ok {
+				blobs = append(blobs, blob)
+			}
+		}
+	}
+	return rpmPackages(name, blobs), nil
+}
+
+func rpmPackages(name string, blobs [][]byte) []Package {
+	packages := []Package{}
+	for _, blob := range blobs {
+		header, err := parseRpmHeader(blob)
+		if err != nil {
+			log.Entry().WithError(err).Debugf("ignoring invalid rpm header in '%v'", name)
+			continue
+		}
+		pkgName := header.text(rpmTagName)
+		// public keys imported into the rpm database are listed as pseudo packages
+		if len(pkgName) == 0 || pkgName == "gpg-pubkey" {
+			continue
+		}
+		version := header.text(rpmTagVersion)
+		if release := header.text(rpmTagRelease); len(release) > 0 {
+			version += "-" + release
+		}
+		pkg := Package{
+			Type:       "rpm",
+			Name:       pkgName,
+			Version:    version,
+			Qualifiers: map[string]string{"arch": header.text(rpmTagArch)},
+			Location:   name,
+		}
+		if epoch, ok := header.integer(rpmTagEpoch); ok && epoch > 0 {
+			pkg.Qualifiers["epoch"] = fmt.Sprint(epoch)
+		}
+		if source := sourcePackageName(header.text(rpmTagSourceRPM)); len(source) > 0 && source != pkgName {
+			pkg.Qualifiers["upstream"] = source
+		}
+		if license := header.text(rpmTagLicense); len(license) > 0 {
+			pkg.Licenses = splitLicenses(license)
+		}
+		packages = append(packages, pkg)
+	}
+	return packages
+}
+
+// sourcePackageName returns the name of the source rpm, e.g. openssl for openssl-3.0.7-24.el9.src.rpm
+func sourcePackageName(sourceRPM string) string {
+	name := strings.TrimSuffix(sourceRPM, ".src.rpm")
+	for i := 0; i < 2; i++ {
+		index := strings.LastIndex(name, "-")
+		if index < 0 {
+			return ""
+		}
+		name = name[:index]
+	}
+	return name
+}
+
+type rpmHeader struct {
+	entries map[int32]rpmHeaderEntry
+	data    []byte
+}
+
+type rpmHeaderEntry struct {
+	dataType int32
+	offset   int32
+	count    int32
+}
+
+// parseRpmHeader parses a header blob as stored in the rpm database: index count, data length, index entries and data
+func parseRpmHeader(blob []byte) (*rpmHeader, error) {
+	if len(blob) < 8 {
+		return nil, errors.New("header too short")
+	}
+	indexCount := int(binary.BigEndian.Uint32(blob[0:4]))
+	dataLength := int(binary.BigEndian.Uint32(blob[4:8]))
+	dataStart := 8 + 16*indexCount
+	if indexCount <= 0 || dataLength < 0 || dataStart+dataLength > len(blob) {
+		return nil, fmt.Errorf("invalid header with %v entries and %v bytes data", indexCount, dataLength)
+	}
+	header := &rpmHeader{entries: map[int32]rpmHeaderEntry{}, data: blob[dataStart : dataStart+dataLength]}
+	for i := 0; i < indexCount; i++ {
+		entry := blob[8+16*i : 8+16*(i+1)]
+		header.entries[int32(binary.BigEndian.Uint32(entry[0:4]))] = rpmHeaderEntry{
+			dataType: int32(binary.BigEndian.Uint32(entry[4:8])),
+			offset:   int32(binary.BigEndian.Uint32(entry[8:12])),
+			count:    int32(binary.BigEndian.Uint32(entry[12:16])),
+		}
+	}
+	return header, nil
+}
+
+// text returns the first string of a string tag
+func (h *rpmHeader) text(tag int32) string {
+	entry, ok := h.entries[tag]
+	if !ok || entry.offset < 0 || int(entry.offset) >= len(h.data) {
+		return ""
+	}
+	switch entry.dataType {
+	case rpmTypeString, rpmTypeStringArray, rpmTypeI18NString:
+		value := h.data[entry.offset:]
+		if end := bytes.IndexByte(value, 0); end >= 0 {
+			value = value[:end]
+		}
+		return string(value)
+	}
+	return ""
+}
+
+func (h *rpmHeader) integer(tag int32) (int32, bool) {
+	entry, ok := h.entries[tag]
+	if !ok || entry.dataType != rpmTypeInt32 || entry.offset < 0 || int(entry.offset)+4 > len(h.data) {
+		return 0, false
+	}
+	return int32(binary.BigEndian.Uint32(h.data[entry.offset:])), true
+}
+
+// Berkeley DB hash database layout, see https://github.com/berkeleydb/libdb/blob/master/src/dbinc/db_page.h
+const (
+	bdbHashMagic        = 0x061561
+	bdbPageHeaderSize   = 26
+	bdbPageTypeHash     = 13
+	bdbPageTypeHashUns  = 2
+	bdbItemTypeOffPage  = 3
+	bdbOffPageItemSize  = 12
+	bdbPageTypeOverflow = 7
+)
+
+// berkeleyDBValues returns the values of all key/value pairs of a Berkeley DB hash database.
+// rpm stores the headers as values which exceed the page size and are therefore kept on overflow pages.
+func berkeleyDBValues(content []byte) ([][]byte, error) {
+	if len(content) < 72 {
+		return nil, errors.New("not a Berkeley DB database")
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if binary.LittleEndian.Uint32(content[12:16]) != bdbHashMagic {
+		if binary.BigEndian.Uint32(content[12:16]) != bdbHashMagic {
+			return nil, errors.New("not a Berkeley DB hash database")
+		}
+		order = binary.BigEndian
+	}
+	pageSize := int(order.Uint32(content[20:24]))
+	lastPage := int(order.Uint32(content[32:36]))
+	if pageSize < 512 || len(content) < (lastPage+1)*pageSize {
+		return nil, fmt.Errorf("invalid Berkeley DB database with page size %v and %v pages", pageSize, lastPage+1)
+	}
+	page := func(number int) []byte {
+		return content[number*pageSize : (number+1)*pageSize]
+	}
+
+	values := [][]byte{}
+	for number := 1; number <= lastPage; number++ {
+		p := page(number)
+		pageType := p[25]
+		if pageType != bdbPageTypeHash && pageType != bdbPageTypeHashUns {
+			continue
+		}
+		entries := int(order.Uint16(p[20:22]))
+		// entries are stored as key/value pairs, the values have odd indices
+		for i := 1; i < entries; i += 2 {
+			indexOffset := bdbPageHeaderSize + 2*i
+			if indexOffset+2 > len(p) {
+				break
+			}
+			offset := int(order.Uint16(p[indexOffset:]))
+			if offset+bdbOffPageItemSize > len(p) || p[offset] != bdbItemTypeOffPage {
+				continue
+			}
+			value, err := overflowValue(page, lastPage, int(order.Uint32(p[offset+4:])), int(order.Uint32(p[offset+8:])), order)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+// overflowValue collects the data of the chain of overflow pages, the page header field hf_offset contains the length of the data on the page
+func overflowValue(page func(int) []byte, lastPage, number, length int, order binary.ByteOrder) ([]byte, error) {
+	value := make([]byte, 0, length)
+	for visited := 0; number != 0 && len(value) < length; visited++ {
+		if number > lastPage || visited > lastPage {
+			return nil, fmt.Errorf("invalid overflow page %v", number)
+		}
+		p := page(number)
+		if p[25] != bdbPageTypeOverflow {
+			return nil, fmt.Errorf("page %v is no overflow page", number)
+		}
+		size := int(order.Uint16(p[22:24]))
+		if bdbPageHeaderSize+size > len(p) {
+			return nil, fmt.Errorf("invalid data length on overflow page %v", number)
+		}
+		value = append(value, p[bdbPageHeaderSize:bdbPageHeaderSize+size]...)
+		number = int(order.Uint32(p[16:20]))
+	}
+	if len(value) < length {
+		return nil, fmt.Errorf("overflow value truncated, expected %v bytes but found %v", length, len(value))
+	}
+	return value[:length], nil
+}
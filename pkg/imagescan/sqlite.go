@@ -0,0 +1,286 @@
+package imagescan
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SQLite file format, see https://www.sqlite.org/fileformat2.html
+// Only reading the rows of tables is supported which is sufficient to read the rpm database without a SQLite driver.
+const (
+	sqliteHeaderSize        = 100
+	sqlitePageInteriorTable = 0x05
+	sqlitePageLeafTable     = 0x0d
+)
+
+var sqliteMagic = []byte("SQLite format 3\x00")
+
+type sqliteReader struct {
+	content    []byte
+	pageSize   int
+	usableSize int
+}
+
+func newSqliteReader(content []byte) (*sqliteReader, error) {
+	if len(content) < sqliteHeaderSize || !bytes.Equal(content[:len(sqliteMagic)], sqliteMagic) {
+		return nil, errors.New("not a SQLite database")
+	}
+	pageSize := int(binary.BigEndian.Uint16(content[16:18]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+	if pageSize < 512 {
+		return nil, fmt.Errorf("invalid SQLite page size %v", pageSize)
+	}
+	return &sqliteReader{content: content, pageSize: pageSize, usableSize: pageSize - int(content[20])}, nil
+}
+
+// tableRows returns the records of the table, the values are either nil, int64, float64, string or []byte
+func (db *sqliteReader) tableRows(table string) ([][]interface{}, error) {
+	schema, err := db.rows(1)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read SQLite schema")
+	}
+	for _, row := range schema {
+		// sqlite_schema(type, name, tbl_name, rootpage, sql)
+		if len(row) < 4 || row[0] != "table" {
+			continue
+		}
+		if name, ok := row[1].(string); !ok || !strings.EqualFold(name, table) {
+			continue
+		}
+		rootPage, ok := row[3].(int64)
+		if !ok {
+			return nil, fmt.Errorf("invalid root page of SQLite table %v", table)
+		}
+		return db.rows(int(rootPage))
+	}
+	return nil, fmt.Errorf("SQLite table %v not found", table)
+}
+
+// rows traverses the table b-tree starting with the root page
+func (db *sqliteReader) rows(rootPage int) ([][]interface{}, error) {
+	rows := [][]interface{}{}
+	pages := []int{rootPage}
+	visited := map[int]bool{}
+	for len(pages) > 0 {
+		number := pages[0]
+		pages = pages[1:]
+		if visited[number] {
+			return nil, fmt.Errorf("cycle in b-tree at page %v", number)
+		}
+		visited[number] = true
+
+		page, headerOffset, err := db.page(number)
+		if err != nil {
+			return nil, err
+		}
+		if headerOffset+8 > len(page) {
+			return nil, fmt.Errorf("invalid b-tree page %v", number)
+		}
+		pageType := page[headerOffset]
+		cellCount := int(binary.BigEndian.Uint16(page[headerOffset+3:]))
+		switch pageType {
+		case sqlitePageInteriorTable:
+			pointers := headerOffset + 12
+			if pointers+2*cellCount > len(page) {
+				return nil, fmt.Errorf("cell count %v exceeds page %v", cellCount, number)
+			}
+			for i := 0; i < cellCount; i++ {
+				cell := int(binary.BigEndian.Uint16(page[pointers+2*i:]))
+				if cell+4 > len(page) {
+					return nil, fmt.Errorf("invalid cell on page %v", number)
+				}
+				pages = append(pages, int(binary.BigEndian.Uint32(page[cell:])))
+			}
+			pages = append(pages, int(binary.BigEndian.Uint32(page[headerOffset+8:])))
+		case sqlitePageLeafTable:
+			pointers := headerOffset + 8
+			if pointers+2*cellCount > len(page) {
+				return nil, fmt.Errorf("cell count %v exceeds page %v", cellCount, number)
+			}
+			for i := 0; i < cellCount; i++ {
+				cell := int(binary.BigEndian.Uint16(page[pointers+2*i:]))
+				payload, err := db.leafPayload(page, cell)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid cell on page %v", number)
+				}
+				row, err := parseRecord(payload)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid record on page %v", number)
+				}
+				rows = append(rows, row)
+			}
+		default:
+			return nil, fmt.Errorf("page %v is no table b-tree page", number)
+		}
+	}
+	return rows, nil
+}
+
+// page returns the content of the page and the offset of the b-tree header which follows the database header on the first page
+func (db *sqliteReader) page(number int) ([]byte, int, error) {
+	start := (number - 1) * db.pageSize
+	if number < 1 || start+db.pageSize > len(db.content) {
+		return nil, 0, fmt.Errorf("page %v out of range", number)
+	}
+	page := db.content[start : start+db.usableSize]
+	if number == 1 {
+		return page, sqliteHeaderSize, nil
+	}
+	return page, 0, nil
+}
+
+// leafPayload reads the payload of a table leaf cell including the content stored on overflow pages
+func (db *sqliteReader) leafPayload(page []byte, cell int) ([]byte, error) {
+	if cell >= len(page) {
+		return nil, errors.New("cell out of range")
+	}
+	payloadSize, n := varint(page[cell:])
+	cell += n
+	_, n = varint(page[cell:]) // rowid
+	cell += n
+
+	// the payload cannot exceed the database, a larger size would only allocate memory
+	if payloadSize > uint64(len(db.content)) {
+		return nil, fmt.Errorf("invalid payload size %v", payloadSize)
+	}
+	total := int(payloadSize)
+	local := db.localPayloadSize(total)
+	if cell+local > len(page) {
+		return nil, errors.New("payload out of range")
+	}
+	payload := make([]byte, 0, total)
+	payload = append(payload, page[cell:cell+local]...)
+	if local == total {
+		return payload, nil
+	}
+
+	if cell+local+4 > len(page) {
+		return nil, errors.New("overflow pointer out of range")
+	}
+	overflow := int(binary.BigEndian.Uint32(page[cell+local:]))
+	for visited := 0; overflow != 0 && len(payload) < total; visited++ {
+		start := (overflow - 1) * db.pageSize
+		if overflow < 1 || start+db.usableSize > len(db.content) || visited > len(db.content)/db.pageSize {
+			return nil, fmt.Errorf("invalid overflow page %v", overflow)
+		}
+		content := db.content[start : start+db.usableSize]
+		size := total - len(payload)
+		if size > db.usableSize-4 {
+			size = db.usableSize - 4
+		}
+		payload = append(payload, content[4:4+size]...)
+		overflow = int(binary.BigEndian.Uint32(content))
+	}
+	if len(payload) < total {
+		return nil, errors.New("payload truncated")
+	}
+	return payload, nil
+}
+
+// localPayloadSize calculates the part of the payload stored on the leaf page itself
+func (db *sqliteReader) localPayloadSize(payloadSize int) int {
+	maxLocal := db.usableSize - 35
+	if payloadSize <= maxLocal {
+		return payloadSize
+	}
+	minLocal := ((db.usableSize-12)*32)/255 - 23
+	local := minLocal + (payloadSize-minLocal)%(db.usableSize-4)
+	if local > maxLocal {
+		return minLocal
+	}
+	return local
+}
+
+// parseRecord decodes a record consisting of a header with the serial types of the columns followed by their values
+func parseRecord(payload []byte) ([]interface{}, error) {
+	headerSize, n := varint(payload)
+	if n == 0 || int(headerSize) > len(payload) {
+		return nil, errors.New("invalid record header")
+	}
+	types := []int64{}
+	for offset := n; offset < int(headerSize); {
+		serialType, n := varint(payload[offset:headerSize])
+		if n == 0 {
+			return nil, errors.New("invalid serial type")
+		}
+		types = append(types, int64(serialType))
+		offset += n
+	}
+
+	values := []interface{}{}
+	data := payload[headerSize:]
+	for _, serialType := range types {
+		size := serialTypeSize(serialType)
+		if size > len(data) {
+			return nil, errors.New("record truncated")
+		}
+		value := data[:size]
+		data = data[size:]
+		switch {
+		case serialType == 0:
+			values = append(values, nil)
+		case serialType >= 1 && serialType <= 6:
+			values = append(values, signedInteger(value))
+		case serialType == 7:
+			values = append(values, math.Float64frombits(binary.BigEndian.Uint64(value)))
+		case serialType == 8:
+			values = append(values, int64(0))
+		case serialType == 9:
+			values = append(values, int64(1))
+		case serialType >= 12 && serialType%2 == 0:
+			values = append(values, value)
+		case serialType >= 13:
+			values = append(values, string(value))
+		default:
+			return nil, fmt.Errorf("unsupported serial type %v", serialType)
+		}
+	}
+	return values, nil
+}
+
+func serialTypeSize(serialType int64) int {
+	switch serialType {
+	case 1, 2, 3, 4:
+		return int(serialType)
+	case 5:
+		return 6
+	case 6, 7:
+		return 8
+	}
+	if serialType >= 12 {
+		return int((serialType - 12) / 2)
+	}
+	return 0
+}
+
+func signedInteger(value []byte) int64 {
+	var result int64
+	for _, b := range value {
+		result = result<<8 | int64(b)
+	}
+	// sign extension of the big-endian two's complement value
+	shift := uint(64 - 8*len(value))
+	return result << shift >> shift
+}
+
+// varint decodes a SQLite variable-length integer and returns the value and the number of bytes consumed, 0 if the data is incomplete
+func varint(data []byte) (uint64, int) {
+	var value uint64
+	for i := 0; i < 9 && i < len(data); i++ {
+		if i == 8 {
+			return value<<8 | uint64(data[i]), 9
+		}
+		value = value<<7 | uint64(data[i]&0x7f)
+		if data[i]&0x80 == 0 {
+			return value, i + 1
+		}
+	}
+	return 0, 0
+}
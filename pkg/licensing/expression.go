@@ -0,0 +1,195 @@
+package licensing
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Expression defines a parsed SPDX license expression (https://spdx.github.io/spdx-spec/v2.3/SPDX-license-expressions/).
+// An expression is either a license, optionally with an exception, or a conjunction (AND) or disjunction (OR) of expressions.
+type Expression struct {
+	License   string
+	Exception string
+	Operator  string
+	Operands  []*Expression
+}
+
+const (
+	operatorAnd  = "AND"
+	operatorOr   = "OR"
+	operatorWith = "WITH"
+)
+
+// String returns the normalized textual representation of the expression
+func (e *Expression) String() string {
+	if len(e.Operator) == 0 {
+		if len(e.Exception) > 0 {
+			return e.License + " " + operatorWith + " " + e.Exception
+		}
+		return e.License
+	}
+	operands := []string{}
+	for _, operand := range e.Operands {
+		text := operand.String()
+		// AND binds stronger than OR, thus only OR operands within AND need to be enclosed
+		if operand.Operator == operatorOr && e.Operator == operatorAnd {
+			text = "(" + text + ")"
+		}
+		operands = append(operands, text)
+	}
+	return strings.Join(operands, " "+e.Operator+" ")
+}
+
+// Licenses returns all licenses (including exceptions) referenced by the expression
+func (e *Expression) Licenses() []string {
+	if len(e.Operator) == 0 {
+		return []string{e.String()}
+	}
+	licenses := []string{}
+	for _, operand := range e.Operands {
+		for _, license := range operand.Licenses() {
+			if !containsFold(licenses, license) {
+				licenses = append(licenses, license)
+			}
+		}
+	}
+	return licenses
+}
+
+// ParseExpression parses an SPDX license expression.
+// The operators AND, OR and WITH are case insensitive, WITH binds stronger than AND and AND binds stronger than OR.
+func ParseExpression(expression string) (*Expression, error) {
+	p := parser{tokens: tokenize(expression)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty license expression")
+	}
+	result, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid license expression '%v': %w", expression, err)
+	}
+	if p.position < len(p.tokens) {
+		return nil, fmt.Errorf("invalid license expression '%v': unexpected token '%v'", expression, p.tokens[p.position])
+	}
+	return result, nil
+}
+
+func tokenize(expression string) []string {
+	expression = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression)
+	return strings.Fields(expression)
+}
+
+type parser struct {
+	tokens   []string
+	position int
+}
+
+func (p *parser) next() string {
+	if p.position >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.position]
+}
+
+func (p *parser) isOperator(operator string) bool {
+	return strings.EqualFold(p.next(), operator)
+}
+
+func (p *parser) parseOr() (*Expression, error) {
+	return p.parseBinary(operatorOr, p.parseAnd)
+}
+
+func (p *parser) parseAnd() (*Expression, error) {
+	return p.parseBinary(operatorAnd, p.parseWith)
+}
+
+func (p *parser) parseBinary(operator string, parseOperand func() (*Expression, error)) (*Expression, error) {
+	first, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	operands := []*Expression{first}
+	for p.isOperator(operator) {
+		p.position++
+		operand, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		// flatten nested expressions with the same operator, e.g. (A AND B) AND C
+		if operand.Operator == operator {
+			operands = append(operands, operand.Operands...)
+		} else {
+			operands = append(operands, operand)
+		}
+	}
+	if len(operands) == 1 {
+		return first, nil
+	}
+	if first.Operator == operator {
+		operands = append(first.Operands, operands[1:]...)
+	}
+	return &Expression{Operator: operator, Operands: operands}, nil
+}
+
+func (p *parser) parseWith() (*Expression, error) {
+	expression, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOperator(operatorWith) {
+		if len(expression.Operator) > 0 {
+			return nil, fmt.Errorf("exception is only allowed for a single license")
+		}
+		p.position++
+		exception := p.next()
+		if !isIdentifier(exception) {
+			return nil, fmt.Errorf("missing exception after %v", operatorWith)
+		}
+		p.position++
+		expression.Exception = exception
+	}
+	return expression, nil
+}
+
+func (p *parser) parsePrimary() (*Expression, error) {
+	token := p.next()
+	switch {
+	case token == "(":
+		p.position++
+		expression, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.position++
+		return expression, nil
+	case isIdentifier(token):
+		p.position++
+		return &Expression{License: token}, nil
+	case len(token) == 0:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected token '%v'", token)
+}
+
+func isIdentifier(token string) bool {
+	if len(token) == 0 || token == "(" || token == ")" {
+		return false
+	}
+	for _, operator := range []string{operatorAnd, operatorOr, operatorWith} {
+		if strings.EqualFold(token, operator) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
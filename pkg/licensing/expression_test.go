@@ -0,0 +1,55 @@
+//go:build unit
+// +build unit
+
+package licensing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExpression(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		expression string
+		expected   string
+		licenses   []string
+		err        string
+	}{
+		{expression: "MIT", expected: "MIT", licenses: []string{"MIT"}},
+		{expression: "MIT OR Apache-2.0", expected: "MIT OR Apache-2.0", licenses: []string{"MIT", "Apache-2.0"}},
+		{expression: "mit and (apache-2.0 or bsd-3-clause)", expected: "mit AND (apache-2.0 OR bsd-3-clause)", licenses: []string{"mit", "apache-2.0", "bsd-3-clause"}},
+		{expression: "MIT AND Apache-2.0 OR BSD-3-Clause", expected: "MIT AND Apache-2.0 OR BSD-3-Clause", licenses: []string{"MIT", "Apache-2.0", "BSD-3-Clause"}},
+		{expression: "(MIT AND Apache-2.0) AND ISC", expected: "MIT AND Apache-2.0 AND ISC", licenses: []string{"MIT", "Apache-2.0", "ISC"}},
+		{expression: "GPL-2.0-only WITH Classpath-exception-2.0 OR MIT", expected: "GPL-2.0-only WITH Classpath-exception-2.0 OR MIT", licenses: []string{"GPL-2.0-only WITH Classpath-exception-2.0", "MIT"}},
+		{expression: "GPL-2.0+", expected: "GPL-2.0+", licenses: []string{"GPL-2.0+"}},
+		{expression: "", err: "empty license expression"},
+		{expression: "MIT AND", err: "invalid license expression 'MIT AND': unexpected end of expression"},
+		{expression: "(MIT OR ISC", err: "invalid license expression '(MIT OR ISC': missing closing parenthesis"},
+		{expression: "MIT ISC", err: "invalid license expression 'MIT ISC': unexpected token 'ISC'"},
+		{expression: "(MIT OR ISC) WITH Classpath-exception-2.0", err: "invalid license expression '(MIT OR ISC) WITH Classpath-exception-2.0': exception is only allowed for a single license"},
+	}
+
+	for _, test := range tt {
+		test := test
+		t.Run(test.expression, func(t *testing.T) {
+			expression, err := ParseExpression(test.expression)
+			if len(test.err) > 0 {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, expression.String())
+			assert.Equal(t, test.licenses, expression.Licenses())
+		})
+	}
+
+	t.Run("precedence", func(t *testing.T) {
+		expression, err := ParseExpression("MIT AND Apache-2.0 OR BSD-3-Clause")
+		assert.NoError(t, err)
+		assert.Equal(t, operatorOr, expression.Operator)
+		assert.Equal(t, operatorAnd, expression.Operands[0].Operator)
+	})
+}
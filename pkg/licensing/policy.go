@@ -0,0 +1,254 @@
+package licensing
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/bmatcuk/doublestar"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/sbom"
+)
+
+// Decision defines the result of evaluating a license against the policy
+type Decision int
+
+const (
+	// Allowed licenses can be used without further action
+	Allowed Decision = iota
+	// Review licenses need to be checked, e.g. by a legal expert
+	Review
+	// Denied licenses must not be used
+	Denied
+)
+
+// String returns the textual representation of the decision
+func (d Decision) String() string {
+	switch d {
+	case Allowed:
+		return "allowed"
+	case Review:
+		return "review"
+	}
+	return "denied"
+}
+
+func parseDecision(decision string) (Decision, error) {
+	switch strings.ToLower(decision) {
+	case "allow", "allowed":
+		return Allowed, nil
+	case "", "review":
+		return Review, nil
+	case "deny", "denied":
+		return Denied, nil
+	}
+	return Review, fmt.Errorf("invalid decision '%v', supported values are allow, review and deny", decision)
+}
+
+// Policy defines which licenses are allowed, need to be reviewed or are denied.
+// License entries are case insensitive and may contain wildcards (e.g. GPL-*), an entry can also cover a license with exception (e.g. GPL-2.0-only WITH Classpath-exception-2.0).
+type Policy struct {
+	Allow  []string `yaml:"allow"`
+	Review []string `yaml:"review"`
+	Deny   []string `yaml:"deny"`
+	// Unknown defines the decision for licenses which are not covered by the policy as well as for components without license information
+	Unknown    string      `yaml:"unknown"`
+	Exceptions []Exception `yaml:"exceptions"`
+
+	unknownDecision Decision
+}
+
+// Exception allows licenses for a specific component
+type Exception struct {
+	// Component is a pattern matching the package URL of the component, e.g. pkg:npm/lodash@* or pkg:maven/com.sap/**
+	Component string `yaml:"component"`
+	// Licenses which are allowed for the component, all licenses are allowed if the list is empty
+	Licenses []string `yaml:"licenses"`
+	Reason   string   `yaml:"reason"`
+}
+
+// LoadPolicy reads the policy from a YAML file
+func LoadPolicy(path string, utils piperutils.FileUtils) (*Policy, error) {
+	content, err := utils.FileRead(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read license policy '%v'", path)
+	}
+	policy := Policy{}
+	if err := yaml.Unmarshal(content, &policy); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse license policy '%v'", path)
+	}
+	if policy.unknownDecision, err = parseDecision(policy.Unknown); err != nil {
+		return nil, errors.Wrapf(err, "invalid license policy '%v'", path)
+	}
+	for _, exception := range policy.Exceptions {
+		if len(exception.Component) == 0 {
+			return nil, fmt.Errorf("invalid license policy '%v': exception without component", path)
+		}
+	}
+	return &policy, nil
+}
+
+// Result contains the evaluation of the licenses of a component
+type Result struct {
+	Name       string
+	Group      string
+	Version    string
+	Purl       string
+	Copyright  string
+	Expression string
+	Licenses   []string
+	Decision   Decision
+	// Violations contains the licenses which cause a decision other than allowed
+	Violations []string
+	// Reason explains the decision, e.g. the licenses violating the policy or the reason of an exception
+	Reason string
+}
+
+// Evaluate evaluates the licenses of a component against the policy.
+// For a disjunction (OR) the most permissive license is chosen, for a conjunction (AND) all licenses need to be permitted.
+func (p *Policy) Evaluate(component cdx.Component) Result {
+	result := Result{
+		Name:       component.Name,
+		Group:      component.Group,
+		Version:    component.Version,
+		Purl:       component.PackageURL,
+		Copyright:  component.Copyright,
+		Licenses:   []string{},
+		Violations: []string{},
+	}
+
+	expressions := []string{}
+	for _, license := range sbom.ComponentLicenses(component) {
+		if strings.ContainsAny(license, " ()") && !isExpression(license) {
+			// license names (e.g. "Apache License 2.0") are no valid identifiers
+			license = "LicenseRef-" + strings.Join(strings.Fields(license), "-")
+		}
+		expressions = append(expressions, license)
+	}
+	if len(expressions) == 0 {
+		result.Decision = p.unknownDecision
+		result.Reason = "no license information available"
+		return p.applyExceptions(result)
+	}
+	if len(expressions) == 1 {
+		result.Expression = expressions[0]
+	} else {
+		result.Expression = "(" + strings.Join(expressions, ") AND (") + ")"
+	}
+
+	expression, err := ParseExpression(result.Expression)
+	if err != nil {
+		result.Licenses = expressions
+		result.Violations = expressions
+		result.Decision = p.unknownDecision
+		result.Reason = err.Error()
+		return p.applyExceptions(result)
+	}
+	result.Expression = expression.String()
+	result.Licenses = expression.Licenses()
+
+	result.Decision, result.Violations = p.evaluate(expression)
+	if result.Decision != Allowed {
+		result.Reason = fmt.Sprintf("%v: %v", result.Decision, strings.Join(result.Violations, ", "))
+	}
+	return p.applyExceptions(result)
+}
+
+// evaluate returns the decision for the expression as well as the licenses which cause the decision
+func (p *Policy) evaluate(expression *Expression) (Decision, []string) {
+	if len(expression.Operator) == 0 {
+		decision := p.decide(expression)
+		if decision == Allowed {
+			return decision, []string{}
+		}
+		return decision, []string{expression.String()}
+	}
+
+	decision, licenses := p.evaluate(expression.Operands[0])
+	for _, operand := range expression.Operands[1:] {
+		operandDecision, operandLicenses := p.evaluate(operand)
+		switch {
+		case operandDecision == decision:
+			licenses = append(licenses, operandLicenses...)
+		case expression.Operator == operatorAnd && operandDecision > decision,
+			expression.Operator == operatorOr && operandDecision < decision:
+			decision, licenses = operandDecision, operandLicenses
+		}
+	}
+	return decision, licenses
+}
+
+// decide returns the decision for a single license, deny entries take precedence over review and allow entries.
+// A license with exception is matched as a whole first, then the license without exception is matched.
+func (p *Policy) decide(license *Expression) Decision {
+	for _, term := range []string{license.String(), license.License} {
+		switch {
+		case matchesLicense(p.Deny, term):
+			return Denied
+		case matchesLicense(p.Review, term):
+			return Review
+		case matchesLicense(p.Allow, term):
+			return Allowed
+		}
+	}
+	return p.unknownDecision
+}
+
+// applyExceptions allows the component if an exception of the component covers all licenses violating the policy
+func (p *Policy) applyExceptions(result Result) Result {
+	if result.Decision == Allowed {
+		return result
+	}
+	for _, exception := range p.Exceptions {
+		if !matchesComponent(exception.Component, result) {
+			continue
+		}
+		if len(exception.Licenses) > 0 {
+			covered := len(result.Violations) > 0
+			for _, license := range result.Violations {
+				if !matchesLicense(exception.Licenses, license) {
+					covered = false
+					break
+				}
+			}
+			if !covered {
+				continue
+			}
+		}
+		result.Decision = Allowed
+		result.Violations = []string{}
+		result.Reason = "exception: " + exception.Reason
+		return result
+	}
+	return result
+}
+
+func matchesComponent(pattern string, result Result) bool {
+	for _, value := range []string{result.Purl, result.Name} {
+		if len(value) == 0 {
+			continue
+		}
+		if matched, _ := doublestar.Match(pattern, value); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesLicense(patterns []string, license string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToUpper(pattern), strings.ToUpper(license)); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func isExpression(license string) bool {
+	upper := " " + strings.ToUpper(license) + " "
+	return strings.Contains(upper, " AND ") || strings.Contains(upper, " OR ") || strings.Contains(upper, " WITH ")
+}
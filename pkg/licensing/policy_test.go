@@ -0,0 +1,136 @@
+//go:build unit
+// +build unit
+
+package licensing
+
+import (
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+const testPolicy = `allow:
+  - MIT
+  - Apache-2.0
+  - BSD-*
+review:
+  - LGPL-*
+deny:
+  - GPL-*
+  - AGPL-*
+unknown: review
+exceptions:
+  - component: "pkg:maven/org.openjdk/**"
+    licenses:
+      - GPL-2.0-only WITH Classpath-exception-2.0
+    reason: approved by legal
+  - component: internal-lib
+    reason: owned by us
+`
+
+func testLicenseComponent(name, purl string, licenses ...cdx.LicenseChoice) cdx.Component {
+	component := cdx.Component{Name: name, Version: "1.0.0", PackageURL: purl}
+	if len(licenses) > 0 {
+		choices := cdx.Licenses(licenses)
+		component.Licenses = &choices
+	}
+	return component
+}
+
+func TestPolicyEvaluate(t *testing.T) {
+	t.Parallel()
+	utils := &mock.FilesMock{}
+	utils.AddFile("license-policy.yaml", []byte(testPolicy))
+	policy, err := LoadPolicy("license-policy.yaml", utils)
+	require.NoError(t, err)
+
+	tt := []struct {
+		name       string
+		component  cdx.Component
+		decision   Decision
+		violations []string
+		reason     string
+	}{
+		{name: "allowed license", component: testLicenseComponent("a", "pkg:npm/a@1.0.0", cdx.LicenseChoice{License: &cdx.License{ID: "MIT"}}), decision: Allowed, violations: []string{}},
+		{name: "wildcard", component: testLicenseComponent("a", "pkg:npm/a@1.0.0", cdx.LicenseChoice{License: &cdx.License{ID: "bsd-3-clause"}}), decision: Allowed, violations: []string{}},
+		{name: "denied license", component: testLicenseComponent("a", "pkg:npm/a@1.0.0", cdx.LicenseChoice{License: &cdx.License{ID: "GPL-3.0-only"}}), decision: Denied, violations: []string{"GPL-3.0-only"}, reason: "denied: GPL-3.0-only"},
+		{name: "OR chooses permissive license", component: testLicenseComponent("a", "pkg:npm/a@1.0.0", cdx.LicenseChoice{Expression: "GPL-3.0-only OR MIT"}), decision: Allowed, violations: []string{}},
+		{name: "AND requires all licenses", component: testLicenseComponent("a", "pkg:npm/a@1.0.0", cdx.LicenseChoice{Expression: "MIT AND LGPL-2.1-only"}), decision: Review, violations: []string{"LGPL-2.1-only"}},
+		{name: "multiple licenses are combined", component: testLicenseComponent("a", "pkg:npm/a@1.0.0", cdx.LicenseChoice{License: &cdx.License{ID: "MIT"}}, cdx.LicenseChoice{License: &cdx.License{ID: "AGPL-3.0-only"}}), decision: Denied, violations: []string{"AGPL-3.0-only"}},
+		{name: "unknown license", component: testLicenseComponent("a", "pkg:npm/a@1.0.0", cdx.LicenseChoice{License: &cdx.License{Name: "Some License"}}), decision: Review, violations: []string{"LicenseRef-Some-License"}},
+		{name: "missing license", component: testLicenseComponent("a", "pkg:npm/a@1.0.0"), decision: Review, violations: []string{}, reason: "no license information available"},
+		{name: "invalid expression", component: testLicenseComponent("a", "pkg:npm/a@1.0.0", cdx.LicenseChoice{Expression: "MIT AND"}), decision: Review, violations: []string{"MIT AND"}},
+		{name: "exception with license", component: testLicenseComponent("jdk", "pkg:maven/org.openjdk/jdk@17", cdx.LicenseChoice{Expression: "GPL-2.0-only WITH Classpath-exception-2.0"}), decision: Allowed, violations: []string{}, reason: "exception: approved by legal"},
+		{name: "exception not covering license", component: testLicenseComponent("jdk", "pkg:maven/org.openjdk/jdk@17", cdx.LicenseChoice{License: &cdx.License{ID: "GPL-3.0-only"}}), decision: Denied, violations: []string{"GPL-3.0-only"}},
+		{name: "exception by name", component: testLicenseComponent("internal-lib", ""), decision: Allowed, violations: []string{}, reason: "exception: owned by us"},
+	}
+
+	for _, test := range tt {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			result := policy.Evaluate(test.component)
+			assert.Equal(t, test.decision, result.Decision)
+			assert.Equal(t, test.violations, result.Violations)
+			if len(test.reason) > 0 {
+				assert.Equal(t, test.reason, result.Reason)
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid decision", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("license-policy.yaml", []byte("unknown: maybe"))
+		_, err := LoadPolicy("license-policy.yaml", utils)
+		assert.EqualError(t, err, "invalid license policy 'license-policy.yaml': invalid decision 'maybe', supported values are allow, review and deny")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadPolicy("license-policy.yaml", &mock.FilesMock{})
+		assert.Contains(t, err.Error(), "failed to read license policy 'license-policy.yaml'")
+	})
+}
+
+func TestCreateAttribution(t *testing.T) {
+	t.Parallel()
+	results := []Result{
+		{Name: "react", Version: "18.2.0", Expression: "MIT", Copyright: "Copyright (c) Meta Platforms, Inc."},
+		{Name: "express", Version: "4.18.2", Expression: "MIT"},
+		{Name: "unknown", Version: "1.0.0"},
+	}
+
+	notice := CreateAttribution("shop", results)
+
+	assert.Equal(t, `# Third-Party Software Notices
+
+shop includes the following third-party software components.
+
+## MIT
+
+* express 4.18.2
+* react 18.2.0 - Copyright (c) Meta Platforms, Inc.
+
+## NOASSERTION
+
+* unknown 1.0.0
+`, string(notice))
+}
+
+func TestViolation(t *testing.T) {
+	t.Parallel()
+	violation := Violation{Result: Result{Name: "gpl-lib", Version: "1.0.0", Purl: "pkg:npm/gpl-lib@1.0.0", Expression: "GPL-3.0-only", Decision: Denied, Reason: "denied: GPL-3.0-only"}}
+
+	assert.Equal(t, "License policy violation - pkg:npm/gpl-lib@1.0.0", violation.Title())
+	markdown, err := violation.ToMarkdown()
+	assert.NoError(t, err)
+	assert.Contains(t, string(markdown), "# Policy Violation - pkg:npm/gpl-lib@1.0.0")
+	assert.Contains(t, string(markdown), "License expression: GPL-3.0-only")
+	assert.Contains(t, violation.ToTxt(), "Description: The license of the component is denied by the license policy (denied: GPL-3.0-only).")
+}
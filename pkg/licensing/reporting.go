@@ -0,0 +1,260 @@
+package licensing
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// ReportsDirectory defines the subfolder for the reports which are generated
+const ReportsDirectory = "licenseCompliance"
+
+// Violation describes a component violating the license policy, it can be used to create GitHub issues
+type Violation struct {
+	Result
+}
+
+// Title returns the title of the violation
+func (v Violation) Title() string {
+	return fmt.Sprintf("License policy violation - %v", v.identifier())
+}
+
+// ToMarkdown returns the markdown representation of the violation
+func (v Violation) ToMarkdown() ([]byte, error) {
+	policyReport := reporting.PolicyViolationReport{
+		ArtifactID:  v.Name,
+		Description: v.description(),
+		Group:       v.Group,
+		Version:     v.Version,
+		PackageURL:  v.identifier(),
+	}
+	return policyReport.ToMarkdown()
+}
+
+// ToTxt returns the textual representation of the violation
+func (v Violation) ToTxt() string {
+	return fmt.Sprintf(`License policy violation %v
+Package: %v
+Installed Version: %v
+Package URL: %v
+Description: %v`,
+		v.identifier(),
+		v.Name,
+		v.Version,
+		v.Purl,
+		v.description(),
+	)
+}
+
+func (v Violation) identifier() string {
+	if len(v.Purl) > 0 {
+		return v.Purl
+	}
+	return fmt.Sprintf("%v@%v", v.Name, v.Version)
+}
+
+func (v Violation) description() string {
+	expression := v.Expression
+	if len(expression) == 0 {
+		expression = "none"
+	}
+	return fmt.Sprintf("The license of the component is %v by the license policy (%v).\n\nLicense expression: %v", v.Decision, v.Reason, expression)
+}
+
+// Violations returns the results with a decision other than allowed
+func Violations(results []Result, includeReview bool) []Violation {
+	violations := []Violation{}
+	for _, result := range results {
+		if result.Decision == Denied || (includeReview && result.Decision == Review) {
+			violations = append(violations, Violation{Result: result})
+		}
+	}
+	return violations
+}
+
+// CountDecisions counts the results per decision
+func CountDecisions(results []Result) map[Decision]int {
+	counts := map[Decision]int{Allowed: 0, Review: 0, Denied: 0}
+	for _, result := range results {
+		counts[result.Decision]++
+	}
+	return counts
+}
+
+// CreateScanReport creates a ScanReport listing the license decisions of all components
+func CreateScanReport(policyFile string, bomFiles []string, results []Result, failOnReview bool) reporting.ScanReport {
+	counts := CountDecisions(results)
+
+	// sort according to decision
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Decision > results[j].Decision
+	})
+
+	scanReport := reporting.ScanReport{
+		ReportTitle: "License Compliance Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "License policy", Details: policyFile},
+			{Description: "Evaluated SBOMs", Details: strings.Join(bomFiles, ", ")},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Total number of components", Details: fmt.Sprint(len(results))},
+			{Description: "Components with denied licenses", Details: fmt.Sprint(counts[Denied])},
+			{Description: "Components with licenses to be reviewed", Details: fmt.Sprint(counts[Review])},
+		},
+		SuccessfulScan: counts[Denied] == 0 && (!failOnReview || counts[Review] == 0),
+		ReportTime:     time.Now(),
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No components found",
+		Headers: []string{
+			"Decision",
+			"Component",
+			"Version",
+			"License expression",
+			"Reason",
+		},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+	for _, result := range results {
+		row := reporting.ScanRow{}
+		row.AddColumn(result.Decision.String(), decisionStyle(result.Decision))
+		row.AddColumn(componentName(result), 0)
+		row.AddColumn(result.Version, 0)
+		row.AddColumn(result.Expression, 0)
+		row.AddColumn(result.Reason, 0)
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+	scanReport.DetailTable = detailTable
+
+	return scanReport
+}
+
+func decisionStyle(decision Decision) reporting.ColumnStyle {
+	switch decision {
+	case Denied:
+		return reporting.Red
+	case Review:
+		return reporting.Yellow
+	}
+	return reporting.Green
+}
+
+func componentName(result Result) string {
+	if len(result.Group) > 0 {
+		return result.Group + "/" + result.Name
+	}
+	return result.Name
+}
+
+// WriteCustomReports creates an HTML and a JSON format file based on the license decisions as well as a markdown file listing the violations
+func WriteCustomReports(scanReport reporting.ScanReport, violations []Violation, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+
+	// ignore templating errors since template is in our hands and issues will be detected with the automated tests
+	htmlReport, _ := scanReport.ToHTML()
+	if err := utils.MkdirAll(ReportsDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrapf(err, "failed to create report directory")
+	}
+	htmlReportPath := filepath.Join(ReportsDirectory, "piper_license_compliance_report.html")
+	if err := utils.FileWrite(htmlReportPath, htmlReport, 0666); err != nil {
+		return reportPaths, errors.Wrapf(err, "failed to write html report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "License Compliance Report", Target: htmlReportPath})
+
+	if len(violations) > 0 {
+		var markdown bytes.Buffer
+		for _, violation := range violations {
+			content, err := violation.ToMarkdown()
+			if err != nil {
+				return reportPaths, errors.Wrap(err, "failed to create policy violation report")
+			}
+			markdown.Write(content)
+			markdown.WriteString("\n")
+		}
+		violationReportPath := filepath.Join(ReportsDirectory, "piper_license_policy_violations.md")
+		if err := utils.FileWrite(violationReportPath, markdown.Bytes(), 0666); err != nil {
+			return reportPaths, errors.Wrapf(err, "failed to write policy violation report")
+		}
+		reportPaths = append(reportPaths, piperutils.Path{Name: "License Policy Violations", Target: violationReportPath})
+	}
+
+	// JSON reports are used by step pipelineCreateSummary in order to e.g. prepare an issue creation in GitHub
+	// ignore JSON errors since structure is in our hands
+	jsonReport, _ := scanReport.ToJSON()
+	if exists, _ := utils.DirExists(reporting.StepReportDirectory); !exists {
+		if err := utils.MkdirAll(reporting.StepReportDirectory, 0777); err != nil {
+			return reportPaths, errors.Wrap(err, "failed to create step reporting directory")
+		}
+	}
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, "licenseComplianceCheck_licenses.json"), jsonReport, 0666); err != nil {
+		return reportPaths, errors.Wrapf(err, "failed to write json report")
+	}
+
+	return reportPaths, nil
+}
+
+// CreateAttribution creates a markdown NOTICE file listing the components grouped by license
+func CreateAttribution(applicationName string, results []Result) []byte {
+	byLicense := map[string][]Result{}
+	for _, result := range results {
+		expression := result.Expression
+		if len(expression) == 0 {
+			expression = "NOASSERTION"
+		}
+		byLicense[expression] = append(byLicense[expression], result)
+	}
+	expressions := []string{}
+	for expression := range byLicense {
+		expressions = append(expressions, expression)
+	}
+	sort.Strings(expressions)
+
+	var notice bytes.Buffer
+	notice.WriteString("# Third-Party Software Notices\n\n")
+	if len(applicationName) > 0 {
+		notice.WriteString(fmt.Sprintf("%v includes the following third-party software components.\n", applicationName))
+	} else {
+		notice.WriteString("This software includes the following third-party software components.\n")
+	}
+	for _, expression := range expressions {
+		components := byLicense[expression]
+		sort.SliceStable(components, func(i, j int) bool {
+			return componentName(components[i])+components[i].Version < componentName(components[j])+components[j].Version
+		})
+		notice.WriteString(fmt.Sprintf("\n## %v\n\n", expression))
+		for _, component := range components {
+			line := fmt.Sprintf("* %v", componentName(component))
+			if len(component.Version) > 0 {
+				line += " " + component.Version
+			}
+			if len(component.Copyright) > 0 {
+				line += " - " + strings.Join(strings.Fields(component.Copyright), " ")
+			}
+			notice.WriteString(line + "\n")
+		}
+	}
+	return notice.Bytes()
+}
+
+// WriteAttribution writes the NOTICE file
+func WriteAttribution(path, applicationName string, results []Result, utils piperutils.FileUtils) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := utils.MkdirAll(dir, 0777); err != nil {
+			return errors.Wrapf(err, "failed to create directory '%v'", dir)
+		}
+	}
+	if err := utils.FileWrite(path, CreateAttribution(applicationName, results), 0666); err != nil {
+		return errors.Wrapf(err, "failed to write attribution file '%v'", path)
+	}
+	return nil
+}
@@ -66,7 +66,7 @@ func TestFatalHookFire(t *testing.T) {
 	})
 
 	t.Run("file exists", func(t *testing.T) {
-		hook := FatalHook{}
+		hook := FatalHook{Path: workspace}
 		entry := logrus.Entry{
 			Message: "the new error message",
 		}
@@ -103,8 +103,13 @@ func SetStepName(stepName string) {
 }
 
 // DeferExitHandler registers a logrus exit handler to allow cleanup activities.
+// Exit handlers are not executed for fatal errors within RunRecoverable.
 func DeferExitHandler(handler func()) {
-	logrus.DeferExitHandler(handler)
+	logrus.DeferExitHandler(func() {
+		if !recoverFatal {
+			handler()
+		}
+	})
 }
 
 // RegisterHook registers a logrus hook
@@ -0,0 +1,102 @@
+package log
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// recoverFatal indicates that a fatal error does not exit the process but is returned by RunRecoverable
+var recoverFatal bool
+
+type fatalExit struct {
+	code int
+}
+
+// RecoveredFatalError is returned by RunRecoverable for a fatal error logged during the execution.
+// It keeps the original log entry in order to report it via ExitWithRecoveredFatal.
+type RecoveredFatalError struct {
+	entry *logrus.Entry
+	code  int
+}
+
+func (e *RecoveredFatalError) Error() string {
+	if e.entry == nil {
+		return fmt.Sprintf("fatal error with exit code %v", e.code)
+	}
+	if err, ok := e.entry.Data[logrus.ErrorKey].(error); ok {
+		return fmt.Sprintf("%v: %v", e.entry.Message, err)
+	}
+	return e.entry.Message
+}
+
+// fatalEntryCollector keeps the fatal entry instead of passing it to the fatal hooks
+type fatalEntryCollector struct {
+	entry *logrus.Entry
+}
+
+func (c *fatalEntryCollector) Levels() []logrus.Level {
+	return []logrus.Level{logrus.FatalLevel}
+}
+
+func (c *fatalEntryCollector) Fire(entry *logrus.Entry) error {
+	c.entry = entry
+	return nil
+}
+
+// RunRecoverable executes the given function and returns an error instead of exiting the process
+// in case a fatal error is logged during its execution.
+// Neither the hooks for fatal errors, e.g. the error details file or Sentry, nor the registered exit handlers are executed in that case.
+func RunRecoverable(run func()) (err error) {
+	logger := Entry().Logger
+	exitFunc := logger.ExitFunc
+	collector := &fatalEntryCollector{}
+	hooks := logger.ReplaceHooks(recoverableHooks(logger.Hooks, collector))
+	recoverFatal = true
+	logger.ExitFunc = func(code int) {
+		panic(fatalExit{code: code})
+	}
+
+	defer func() {
+		logger.ExitFunc = exitFunc
+		logger.ReplaceHooks(hooks)
+		recoverFatal = false
+		if r := recover(); r != nil {
+			exit, ok := r.(fatalExit)
+			if !ok {
+				panic(r)
+			}
+			err = &RecoveredFatalError{entry: collector.entry, code: exit.code}
+		}
+	}()
+
+	run()
+	return nil
+}
+
+// recoverableHooks returns the hooks without the ones for fatal errors, which are replaced by the collector
+func recoverableHooks(hooks logrus.LevelHooks, collector logrus.Hook) logrus.LevelHooks {
+	recoverable := logrus.LevelHooks{}
+	for level, levelHooks := range hooks {
+		if level != logrus.FatalLevel {
+			recoverable[level] = levelHooks
+		}
+	}
+	recoverable.Add(collector)
+	return recoverable
+}
+
+// ExitWithRecoveredFatal reports the fatal error returned by RunRecoverable and exits the process.
+// The original entry is passed to the hooks for fatal errors without logging it again, thus the original error details are reported.
+func ExitWithRecoveredFatal(err error) {
+	recovered, ok := err.(*RecoveredFatalError)
+	if !ok || recovered.entry == nil {
+		Entry().WithError(err).Fatal("step execution failed")
+		return
+	}
+	logger := Entry().Logger
+	if fireErr := logger.Hooks.Fire(logrus.FatalLevel, recovered.entry); fireErr != nil {
+		Entry().WithError(fireErr).Warning("failed to fire hooks for fatal error")
+	}
+	logger.Exit(recovered.code)
+}
@@ -0,0 +1,87 @@
+//go:build unit
+// +build unit
+
+package log
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunRecoverable(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		executed := false
+
+		err := RunRecoverable(func() { executed = true })
+
+		assert.NoError(t, err)
+		assert.True(t, executed)
+	})
+
+	t.Run("fatal error is returned", func(t *testing.T) {
+		handlerExecuted := false
+		DeferExitHandler(func() { handlerExecuted = true })
+		afterFatal := false
+
+		err := RunRecoverable(func() {
+			Entry().Fatal("step failed")
+			afterFatal = true
+		})
+
+		assert.EqualError(t, err, "step failed")
+		assert.False(t, afterFatal)
+		assert.False(t, handlerExecuted, "exit handlers must not be executed")
+		assert.False(t, recoverFatal)
+	})
+
+	t.Run("fatal hooks are not fired", func(t *testing.T) {
+		hooks := Entry().Logger.ReplaceHooks(logrus.LevelHooks{})
+		defer Entry().Logger.ReplaceHooks(hooks)
+		dir := t.TempDir()
+		RegisterHook(&FatalHook{Path: dir})
+
+		err := RunRecoverable(func() {
+			Entry().WithError(errors.New("connection refused")).Fatal("step failed")
+		})
+
+		assert.EqualError(t, err, "step failed: connection refused")
+		assert.NoFileExists(t, filepath.Join(dir, "errorDetails.json"))
+		assert.Len(t, Entry().Logger.Hooks[logrus.FatalLevel], 1, "fatal hooks must be restored")
+	})
+
+	t.Run("other panics are not recovered", func(t *testing.T) {
+		assert.PanicsWithValue(t, "unexpected", func() {
+			RunRecoverable(func() { panic("unexpected") })
+		})
+	})
+}
+
+func TestExitWithRecoveredFatal(t *testing.T) {
+	logger := Entry().Logger
+	hooks := logger.ReplaceHooks(logrus.LevelHooks{})
+	defer logger.ReplaceHooks(hooks)
+	exitFunc := logger.ExitFunc
+	defer func() { logger.ExitFunc = exitFunc }()
+	exitCode := 0
+	logger.ExitFunc = func(code int) { exitCode = code }
+	dir := t.TempDir()
+	RegisterHook(&FatalHook{Path: dir})
+
+	err := RunRecoverable(func() {
+		Entry().WithError(errors.New("connection refused")).Fatal("step failed")
+	})
+	ExitWithRecoveredFatal(err)
+
+	assert.Equal(t, 1, exitCode)
+	content, readErr := os.ReadFile(filepath.Join(dir, "errorDetails.json"))
+	if assert.NoError(t, readErr) {
+		assert.Contains(t, string(content), `"message":"step failed"`)
+		assert.Contains(t, string(content), `"error":"connection refused"`)
+	}
+}
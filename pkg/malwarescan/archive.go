@@ -0,0 +1,194 @@
+package malwarescan
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+)
+
+// ArchiveEntryResult : Result of the scan of a single file contained in an archive
+type ArchiveEntryResult struct {
+	// Path of the entry, entries of nested archives are separated by '!', e.g. image.tar!layer.tar!usr/bin/tool
+	Path string `json:"path"`
+	ScanResult
+}
+
+type archiveFormat int
+
+const (
+	formatNone archiveFormat = iota
+	formatTar
+	formatGzip
+	formatZip
+)
+
+// IsArchive : Returns true if the content starts like a tar, gzip compressed or zip archive
+func IsArchive(candidate io.Reader) bool {
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(candidate, head)
+	return detectArchiveFormat(head[:n]) != formatNone
+}
+
+// ScanArchive : Scans every regular file contained in the given tar, gzip compressed tar or zip archive separately.
+// Nested tar archives like the layers of an image tarball are scanned recursively. Nested zip archives (e.g. jar files)
+// are scanned as a whole since the scan engines unpack them on their own.
+func ScanArchive(client Client, name string, archive io.Reader) ([]ArchiveEntryResult, error) {
+	results := []ArchiveEntryResult{}
+	buffered := bufio.NewReaderSize(archive, 512)
+	head, _ := buffered.Peek(512)
+	switch detectArchiveFormat(head) {
+	case formatTar:
+		return results, scanTar(client, name, buffered, &results, true)
+	case formatGzip:
+		return results, scanGzip(client, name, buffered, &results, true)
+	case formatZip:
+		if readerAt, size, ok := asReaderAt(archive); ok {
+			return results, scanZip(client, name, readerAt, size, &results)
+		}
+		return results, scanSpooledZip(client, name, buffered, &results)
+	}
+	return nil, fmt.Errorf("'%v' is not a supported archive", name)
+}
+
+func scanTar(client Client, name string, archive io.Reader, results *[]ArchiveEntryResult, topLevel bool) error {
+	reader := tar.NewReader(archive)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to read tar archive '%v'", name)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := scanEntry(client, entryPath(name, header.Name, topLevel), reader, results); err != nil {
+			return err
+		}
+	}
+}
+
+func scanGzip(client Client, name string, archive io.Reader, results *[]ArchiveEntryResult, topLevel bool) error {
+	reader, err := gzip.NewReader(archive)
+	if err != nil {
+		return errors.Wrapf(err, "failed to decompress '%v'", name)
+	}
+	defer reader.Close()
+
+	buffered := bufio.NewReaderSize(reader, 512)
+	head, _ := buffered.Peek(512)
+	if detectArchiveFormat(head) == formatTar {
+		return scanTar(client, name, buffered, results, topLevel)
+	}
+	return scanContent(client, name, buffered, results)
+}
+
+// asReaderAt returns the random access to an archive, e.g. to an opened file, which is required to read zip archives
+func asReaderAt(archive io.Reader) (io.ReaderAt, int64, bool) {
+	switch r := archive.(type) {
+	case interface {
+		io.ReaderAt
+		Size() int64
+	}:
+		return r, r.Size(), true
+	case interface {
+		io.ReaderAt
+		Stat() (os.FileInfo, error)
+	}:
+		info, err := r.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return nil, 0, false
+		}
+		return r, info.Size(), true
+	}
+	return nil, 0, false
+}
+
+// scanSpooledZip copies a zip archive without random access into a temporary file instead of keeping it in memory
+func scanSpooledZip(client Client, name string, archive io.Reader, results *[]ArchiveEntryResult) error {
+	spool, err := os.CreateTemp("", "malwarescan-*.zip")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create temporary file for zip archive '%v'", name)
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	size, err := io.Copy(spool, archive)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read zip archive '%v'", name)
+	}
+	return scanZip(client, name, spool, size, results)
+}
+
+func scanZip(client Client, name string, archive io.ReaderAt, size int64, results *[]ArchiveEntryResult) error {
+	reader, err := zip.NewReader(archive, size)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read zip archive '%v'", name)
+	}
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		entry, err := file.Open()
+		if err != nil {
+			return errors.Wrapf(err, "failed to read '%v' in zip archive '%v'", file.Name, name)
+		}
+		err = scanContent(client, file.Name, entry, results)
+		entry.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanEntry scans an entry of a tar archive and descends into nested tar archives
+func scanEntry(client Client, name string, entry io.Reader, results *[]ArchiveEntryResult) error {
+	buffered := bufio.NewReaderSize(entry, 512)
+	head, _ := buffered.Peek(512)
+	switch detectArchiveFormat(head) {
+	case formatTar:
+		return scanTar(client, name, buffered, results, false)
+	case formatGzip:
+		return scanGzip(client, name, buffered, results, false)
+	}
+	return scanContent(client, name, buffered, results)
+}
+
+func scanContent(client Client, name string, content io.Reader, results *[]ArchiveEntryResult) error {
+	result, err := client.Scan(content)
+	if err != nil {
+		return errors.Wrapf(err, "failed to scan '%v'", name)
+	}
+	*results = append(*results, ArchiveEntryResult{Path: name, ScanResult: *result})
+	return nil
+}
+
+func entryPath(archive, entry string, topLevel bool) string {
+	entry = path.Clean(entry)
+	if topLevel {
+		return entry
+	}
+	return archive + "!" + entry
+}
+
+func detectArchiveFormat(head []byte) archiveFormat {
+	switch {
+	case len(head) >= 2 && head[0] == 0x1f && head[1] == 0x8b:
+		return formatGzip
+	case len(head) >= 4 && bytes.Equal(head[:4], []byte("PK\x03\x04")):
+		return formatZip
+	case len(head) >= 262 && bytes.Equal(head[257:262], []byte("ustar")):
+		return formatTar
+	}
+	return formatNone
+}
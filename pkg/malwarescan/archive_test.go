@@ -0,0 +1,141 @@
+//go:build unit
+// +build unit
+
+package malwarescan
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scanClientMock flags every content containing EICAR as malware
+type scanClientMock struct{}
+
+func (c *scanClientMock) Scan(candidate io.Reader) (*ScanResult, error) {
+	content, err := io.ReadAll(candidate)
+	if err != nil {
+		return nil, err
+	}
+	result := ScanResult{ScanSize: len(content)}
+	if strings.Contains(string(content), "EICAR") {
+		result.MalwareDetected = true
+		result.Finding = "Eicar-Test-Signature"
+	}
+	return &result, nil
+}
+
+func (c *scanClientMock) Info() (*Info, error) {
+	return &Info{}, nil
+}
+
+func createTar(t *testing.T, files map[string][]byte, names ...string) []byte {
+	buffer := &bytes.Buffer{}
+	writer := tar.NewWriter(buffer)
+	require.NoError(t, writer.WriteHeader(&tar.Header{Name: "dir/", Typeflag: tar.TypeDir, Mode: 0755}))
+	for _, name := range names {
+		require.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(files[name]))}))
+		_, err := writer.Write(files[name])
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func createGzip(t *testing.T, content []byte) []byte {
+	buffer := &bytes.Buffer{}
+	writer := gzip.NewWriter(buffer)
+	_, err := writer.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func TestScanArchive(t *testing.T) {
+	layer := createTar(t, map[string][]byte{"usr/bin/tool": []byte("EICAR"), "etc/config": []byte("key=value")}, "usr/bin/tool", "etc/config")
+	image := createTar(t, map[string][]byte{
+		"manifest.json":   []byte("[]"),
+		"layer1.tar":      layer,
+		"layer2.tar.gz":   createGzip(t, layer),
+		"dir/archive.zip": []byte("PK\x03\x04"),
+	}, "manifest.json", "layer1.tar", "layer2.tar.gz", "dir/archive.zip")
+
+	t.Run("tar with nested layers", func(t *testing.T) {
+		results, err := ScanArchive(&scanClientMock{}, "image.tar", bytes.NewReader(image))
+
+		require.NoError(t, err)
+		assert.Equal(t, []ArchiveEntryResult{
+			{Path: "manifest.json", ScanResult: ScanResult{ScanSize: 2}},
+			{Path: "layer1.tar!usr/bin/tool", ScanResult: ScanResult{ScanSize: 5, MalwareDetected: true, Finding: "Eicar-Test-Signature"}},
+			{Path: "layer1.tar!etc/config", ScanResult: ScanResult{ScanSize: 9}},
+			{Path: "layer2.tar.gz!usr/bin/tool", ScanResult: ScanResult{ScanSize: 5, MalwareDetected: true, Finding: "Eicar-Test-Signature"}},
+			{Path: "layer2.tar.gz!etc/config", ScanResult: ScanResult{ScanSize: 9}},
+			{Path: "dir/archive.zip", ScanResult: ScanResult{ScanSize: 4}},
+		}, results)
+	})
+
+	t.Run("gzip compressed tar", func(t *testing.T) {
+		results, err := ScanArchive(&scanClientMock{}, "layer.tar.gz", bytes.NewReader(createGzip(t, layer)))
+
+		require.NoError(t, err)
+		assert.Equal(t, []ArchiveEntryResult{
+			{Path: "usr/bin/tool", ScanResult: ScanResult{ScanSize: 5, MalwareDetected: true, Finding: "Eicar-Test-Signature"}},
+			{Path: "etc/config", ScanResult: ScanResult{ScanSize: 9}},
+		}, results)
+	})
+
+	t.Run("zip", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		writer := zip.NewWriter(buffer)
+		_, err := writer.Create("META-INF/")
+		require.NoError(t, err)
+		entry, err := writer.Create("META-INF/MANIFEST.MF")
+		require.NoError(t, err)
+		_, err = entry.Write([]byte("Manifest-Version: 1.0"))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		expected := []ArchiveEntryResult{
+			{Path: "META-INF/MANIFEST.MF", ScanResult: ScanResult{ScanSize: 21}},
+		}
+		jar := buffer.Bytes()
+
+		// random access
+		results, err := ScanArchive(&scanClientMock{}, "app.jar", bytes.NewReader(jar))
+		require.NoError(t, err)
+		assert.Equal(t, expected, results)
+
+		// random access to an opened file
+		jarFile := filepath.Join(t.TempDir(), "app.jar")
+		require.NoError(t, os.WriteFile(jarFile, jar, 0o644))
+		file, err := os.Open(jarFile)
+		require.NoError(t, err)
+		defer file.Close()
+		results, err = ScanArchive(&scanClientMock{}, "app.jar", file)
+		require.NoError(t, err)
+		assert.Equal(t, expected, results)
+
+		// sequential access only
+		results, err = ScanArchive(&scanClientMock{}, "app.jar", bytes.NewBuffer(jar))
+		require.NoError(t, err)
+		assert.Equal(t, expected, results)
+	})
+
+	t.Run("no archive", func(t *testing.T) {
+		assert.False(t, IsArchive(strings.NewReader("HELLO")))
+		assert.True(t, IsArchive(bytes.NewReader(image)))
+
+		_, err := ScanArchive(&scanClientMock{}, "file.txt", strings.NewReader("HELLO"))
+
+		assert.EqualError(t, err, "'file.txt' is not a supported archive")
+	})
+}
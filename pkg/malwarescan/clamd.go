@@ -0,0 +1,208 @@
+package malwarescan
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// clamdDefaultChunkSize is the size of the chunks streamed to clamd, it needs to be below the StreamMaxLength of clamd
+	clamdDefaultChunkSize = 64 * 1024
+	clamdDefaultTimeout   = 10 * time.Minute
+)
+
+// ClamdClient : Client implementation for a local ClamAV daemon (clamd) using the INSTREAM command
+// (see https://docs.clamav.net/manual/Usage/Scanning.html#clamd)
+type ClamdClient struct {
+	// Network is either tcp or unix
+	Network string
+	// Address is host:port for tcp and the socket path for unix
+	Address   string
+	Timeout   time.Duration
+	ChunkSize int
+}
+
+// NewClamdClient : Creates a client for clamd listening on the given address.
+// The address is either a URL like tcp://localhost:3310 or unix:///var/run/clamav/clamd.ctl, or a plain host:port.
+func NewClamdClient(address string, timeout time.Duration) (*ClamdClient, error) {
+	client := ClamdClient{Network: "tcp", Address: address, Timeout: timeout, ChunkSize: clamdDefaultChunkSize}
+	switch {
+	case strings.HasPrefix(address, "tcp://"):
+		client.Address = strings.TrimPrefix(address, "tcp://")
+	case strings.HasPrefix(address, "unix://"):
+		client.Network = "unix"
+		client.Address = strings.TrimPrefix(address, "unix://")
+	case strings.HasPrefix(address, "/"):
+		client.Network = "unix"
+	}
+	if len(client.Address) == 0 {
+		return nil, fmt.Errorf("invalid clamd address '%v'", address)
+	}
+	return &client, nil
+}
+
+// Scan : Streams the given content to clamd and maps the verdict into a ScanResult.
+// Size and SHA256 of the content are determined while streaming.
+func (c *ClamdClient) Scan(candidate io.Reader) (*ScanResult, error) {
+	conn, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, errors.Wrap(err, "failed to send INSTREAM command to clamd")
+	}
+
+	hash := sha256.New()
+	head := &bytes.Buffer{}
+	size, err := c.stream(conn, io.TeeReader(candidate, io.MultiWriter(hash, &limitedWriter{w: head, n: 512})))
+	if err != nil {
+		// clamd closes the connection e.g. if the size limit is exceeded, the reason is contained in the response
+		if response, readErr := readClamdResponse(conn); readErr == nil && len(response) > 0 {
+			return nil, fmt.Errorf("clamd returned an error: %v", response)
+		}
+		return nil, err
+	}
+
+	response, err := readClamdResponse(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ScanResult{
+		ScanSize: size,
+		SHA256:   hex.EncodeToString(hash.Sum(nil)),
+		MimeType: http.DetectContentType(head.Bytes()),
+	}
+	// responses look like "stream: OK", "stream: Eicar-Test-Signature FOUND" or "INSTREAM size limit exceeded. ERROR"
+	verdict := strings.TrimSpace(strings.TrimPrefix(response, "stream:"))
+	switch {
+	case verdict == "OK":
+	case strings.HasSuffix(verdict, " FOUND"):
+		result.Finding = strings.TrimSuffix(verdict, " FOUND")
+		// with AlertEncrypted enabled clamd reports encrypted content via heuristic signatures
+		if strings.HasPrefix(result.Finding, "Heuristics.Encrypted") {
+			result.EncryptedContentDetected = true
+		} else {
+			result.MalwareDetected = true
+		}
+	default:
+		return nil, fmt.Errorf("clamd returned an error: %v", response)
+	}
+	return &result, nil
+}
+
+// Info : Returns engine version and signature timestamp as reported by the VERSION command of clamd.
+func (c *ClamdClient) Info() (*Info, error) {
+	conn, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("zVERSION\x00")); err != nil {
+		return nil, errors.Wrap(err, "failed to send VERSION command to clamd")
+	}
+	response, err := readClamdResponse(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	// the version looks like "ClamAV 1.0.1/26812/Mon Feb 20 08:23:40 2023" containing engine, signature and signature timestamp
+	parts := strings.SplitN(response, "/", 3)
+	info := Info{EngineVersion: parts[0]}
+	if len(parts) == 3 {
+		info.EngineVersion = fmt.Sprintf("%v (signatures %v)", parts[0], parts[1])
+		info.SignatureTimestamp = parts[2]
+	}
+	return &info, nil
+}
+
+func (c *ClamdClient) dial() (net.Conn, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = clamdDefaultTimeout
+	}
+	conn, err := net.DialTimeout(c.Network, c.Address, timeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to clamd at '%v'", c.Address)
+	}
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "failed to set deadline for clamd connection")
+	}
+	return conn, nil
+}
+
+// stream sends the content in chunks, each prefixed with its length as 4 byte unsigned integer in network byte order.
+// A chunk of length zero terminates the stream.
+func (c *ClamdClient) stream(conn io.Writer, content io.Reader) (int, error) {
+	chunkSize := c.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = clamdDefaultChunkSize
+	}
+	// the first four bytes of the buffer hold the length of the chunk
+	buffer := make([]byte, 4+chunkSize)
+	size := 0
+	for {
+		n, err := io.ReadFull(content, buffer[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buffer[:4], uint32(n))
+			if _, werr := conn.Write(buffer[:4+n]); werr != nil {
+				return size, errors.Wrap(werr, "failed to stream content to clamd")
+			}
+			size += n
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return size, errors.Wrap(err, "failed to read content to be scanned")
+		}
+	}
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return size, errors.Wrap(err, "failed to terminate stream to clamd")
+	}
+	return size, nil
+}
+
+// readClamdResponse reads the response terminated by a null character as requested via the z prefix of the commands
+func readClamdResponse(conn io.Reader) (string, error) {
+	response, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !(err == io.EOF && len(response) > 0) {
+		return "", errors.Wrap(err, "failed to read response from clamd")
+	}
+	return strings.TrimSpace(strings.TrimRight(response, "\x00")), nil
+}
+
+// limitedWriter keeps the first n bytes written to it and discards the rest
+type limitedWriter struct {
+	w io.Writer
+	n int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.n > 0 {
+		keep := p
+		if len(keep) > l.n {
+			keep = keep[:l.n]
+		}
+		l.n -= len(keep)
+		if _, err := l.w.Write(keep); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
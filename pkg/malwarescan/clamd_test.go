@@ -0,0 +1,162 @@
+//go:build unit
+// +build unit
+
+package malwarescan
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clamdMock serves a single connection like clamd and records the streamed content
+type clamdMock struct {
+	listener net.Listener
+	command  string
+	content  bytes.Buffer
+	done     chan struct{}
+}
+
+func newClamdMock(t *testing.T, response string) *clamdMock {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	mock := &clamdMock{listener: listener, done: make(chan struct{})}
+	go func() {
+		defer close(mock.done)
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		command, _ := reader.ReadString(0)
+		mock.command = strings.TrimRight(command, "\x00")
+		if mock.command == "zINSTREAM" {
+			for {
+				length := make([]byte, 4)
+				if _, err := io.ReadFull(reader, length); err != nil {
+					return
+				}
+				size := binary.BigEndian.Uint32(length)
+				if size == 0 {
+					break
+				}
+				if _, err := io.CopyN(&mock.content, reader, int64(size)); err != nil {
+					return
+				}
+			}
+		}
+		conn.Write([]byte(response + "\x00"))
+	}()
+	t.Cleanup(func() { listener.Close() })
+	return mock
+}
+
+func (m *clamdMock) client() *ClamdClient {
+	return &ClamdClient{Network: "tcp", Address: m.listener.Addr().String(), Timeout: 5 * time.Second, ChunkSize: 4}
+}
+
+func TestClamdScan(t *testing.T) {
+	t.Run("Scan without finding", func(t *testing.T) {
+		clamd := newClamdMock(t, "stream: OK")
+
+		scanResult, err := clamd.client().Scan(strings.NewReader("HELLO WORLD"))
+		<-clamd.done
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "zINSTREAM", clamd.command)
+			assert.Equal(t, "HELLO WORLD", clamd.content.String())
+			assert.False(t, scanResult.MalwareDetected)
+			assert.False(t, scanResult.EncryptedContentDetected)
+			assert.Equal(t, 11, scanResult.ScanSize)
+			assert.Equal(t, "text/plain; charset=utf-8", scanResult.MimeType)
+			assert.Equal(t, "787ec76dcafd20c1908eb0936a12f91edd105ab5cd7ecc2b1ae2032648345dff", scanResult.SHA256)
+		}
+	})
+
+	t.Run("Scan with malware", func(t *testing.T) {
+		clamd := newClamdMock(t, "stream: Eicar-Test-Signature FOUND")
+
+		scanResult, err := clamd.client().Scan(strings.NewReader("EICAR"))
+
+		if assert.NoError(t, err) {
+			assert.True(t, scanResult.MalwareDetected)
+			assert.False(t, scanResult.EncryptedContentDetected)
+			assert.Equal(t, "Eicar-Test-Signature", scanResult.Finding)
+		}
+	})
+
+	t.Run("Scan with encrypted content", func(t *testing.T) {
+		clamd := newClamdMock(t, "stream: Heuristics.Encrypted.Zip FOUND")
+
+		scanResult, err := clamd.client().Scan(strings.NewReader("PK"))
+
+		if assert.NoError(t, err) {
+			assert.False(t, scanResult.MalwareDetected)
+			assert.True(t, scanResult.EncryptedContentDetected)
+			assert.Equal(t, "Heuristics.Encrypted.Zip", scanResult.Finding)
+		}
+	})
+
+	t.Run("Scan with error", func(t *testing.T) {
+		clamd := newClamdMock(t, "INSTREAM size limit exceeded. ERROR")
+
+		_, err := clamd.client().Scan(strings.NewReader("HELLO"))
+
+		assert.EqualError(t, err, "clamd returned an error: INSTREAM size limit exceeded. ERROR")
+	})
+
+	t.Run("Connection refused", func(t *testing.T) {
+		clamd := newClamdMock(t, "")
+		client := clamd.client()
+		clamd.listener.Close()
+
+		_, err := client.Scan(strings.NewReader("HELLO"))
+
+		assert.Contains(t, err.Error(), "failed to connect to clamd at")
+	})
+}
+
+func TestClamdInfo(t *testing.T) {
+	clamd := newClamdMock(t, "ClamAV 1.0.1/26812/Mon Feb 20 08:23:40 2023")
+
+	info, err := clamd.client().Info()
+	<-clamd.done
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "zVERSION", clamd.command)
+		assert.Equal(t, "ClamAV 1.0.1 (signatures 26812)", info.EngineVersion)
+		assert.Equal(t, "Mon Feb 20 08:23:40 2023", info.SignatureTimestamp)
+	}
+}
+
+func TestNewClamdClient(t *testing.T) {
+	tt := []struct {
+		address         string
+		expectedNetwork string
+		expectedAddress string
+	}{
+		{address: "tcp://localhost:3310", expectedNetwork: "tcp", expectedAddress: "localhost:3310"},
+		{address: "clamav:3310", expectedNetwork: "tcp", expectedAddress: "clamav:3310"},
+		{address: "unix:///var/run/clamav/clamd.ctl", expectedNetwork: "unix", expectedAddress: "/var/run/clamav/clamd.ctl"},
+		{address: "/tmp/clamd.sock", expectedNetwork: "unix", expectedAddress: "/tmp/clamd.sock"},
+	}
+	for _, test := range tt {
+		client, err := NewClamdClient(test.address, time.Minute)
+		if assert.NoError(t, err, test.address) {
+			assert.Equal(t, test.expectedNetwork, client.Network, test.address)
+			assert.Equal(t, test.expectedAddress, client.Address, test.address)
+		}
+	}
+
+	_, err := NewClamdClient("unix://", time.Minute)
+	assert.EqualError(t, err, "invalid clamd address 'unix://'")
+}
@@ -0,0 +1,84 @@
+package osv
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+var cvss3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// CVSS3BaseScore calculates the base score of a CVSS v3.0 or v3.1 vector, e.g. CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H
+func CVSS3BaseScore(vector string) (float64, error) {
+	parts := strings.Split(vector, "/")
+	if len(parts) < 2 || !strings.HasPrefix(parts[0], "CVSS:3") {
+		return 0, fmt.Errorf("invalid CVSS v3 vector '%v'", vector)
+	}
+	metrics := map[string]string{}
+	for _, part := range parts[1:] {
+		kv := strings.SplitN(part, ":", 2)
+		if len(kv) != 2 {
+			return 0, fmt.Errorf("invalid metric '%v' in CVSS v3 vector '%v'", part, vector)
+		}
+		metrics[kv[0]] = kv[1]
+	}
+
+	values := map[string]float64{}
+	for metric, weights := range cvss3Weights {
+		value, ok := weights[metrics[metric]]
+		if !ok {
+			return 0, fmt.Errorf("missing or invalid metric '%v' in CVSS v3 vector '%v'", metric, vector)
+		}
+		values[metric] = value
+	}
+	scopeChanged := metrics["S"] == "C"
+	if !scopeChanged && metrics["S"] != "U" {
+		return 0, fmt.Errorf("missing or invalid metric 'S' in CVSS v3 vector '%v'", vector)
+	}
+	switch metrics["PR"] {
+	case "N":
+		values["PR"] = 0.85
+	case "L":
+		values["PR"] = 0.62
+		if scopeChanged {
+			values["PR"] = 0.68
+		}
+	case "H":
+		values["PR"] = 0.27
+		if scopeChanged {
+			values["PR"] = 0.5
+		}
+	default:
+		return 0, fmt.Errorf("missing or invalid metric 'PR' in CVSS v3 vector '%v'", vector)
+	}
+
+	iss := 1 - (1-values["C"])*(1-values["I"])*(1-values["A"])
+	impact := 6.42 * iss
+	if scopeChanged {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, nil
+	}
+	exploitability := 8.22 * values["AV"] * values["AC"] * values["PR"] * values["UI"]
+	if scopeChanged {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), nil
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), nil
+}
+
+// roundUp implements the rounding defined in CVSS v3.1 which avoids floating point inaccuracies
+func roundUp(value float64) float64 {
+	intInput := int64(math.Round(value * 100000))
+	if intInput%10000 == 0 {
+		return float64(intInput) / 100000
+	}
+	return (math.Floor(float64(intInput)/10000) + 1) / 10
+}
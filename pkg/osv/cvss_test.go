@@ -0,0 +1,40 @@
+//go:build unit
+// +build unit
+
+package osv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCVSS3BaseScore(t *testing.T) {
+	tt := []struct {
+		vector   string
+		expected float64
+	}{
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", expected: 9.8},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", expected: 10.0},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:H/UI:N/S:U/C:H/I:H/A:H", expected: 7.2},
+		{vector: "CVSS:3.0/AV:N/AC:L/PR:L/UI:R/S:C/C:L/I:L/A:N", expected: 5.4},
+		{vector: "CVSS:3.1/AV:L/AC:H/PR:H/UI:R/S:U/C:N/I:N/A:N", expected: 0.0},
+	}
+	for _, test := range tt {
+		t.Run(test.vector, func(t *testing.T) {
+			score, err := CVSS3BaseScore(test.vector)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, score)
+		})
+	}
+
+	t.Run("error case - invalid vector", func(t *testing.T) {
+		_, err := CVSS3BaseScore("AV:N/AC:L")
+		assert.EqualError(t, err, "invalid CVSS v3 vector 'AV:N/AC:L'")
+	})
+
+	t.Run("error case - missing metric", func(t *testing.T) {
+		_, err := CVSS3BaseScore("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H")
+		assert.EqualError(t, err, "missing or invalid metric 'A' in CVSS v3 vector 'CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H'")
+	})
+}
@@ -0,0 +1,491 @@
+package osv
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/package-url/packageurl-go"
+	"github.com/pkg/errors"
+)
+
+// Vulnerability defines a record of the OSV schema (https://ossf.github.io/osv-schema/), also used for GHSA exports
+type Vulnerability struct {
+	ID               string                 `json:"id"`
+	Summary          string                 `json:"summary,omitempty"`
+	Details          string                 `json:"details,omitempty"`
+	Aliases          []string               `json:"aliases,omitempty"`
+	Published        string                 `json:"published,omitempty"`
+	Modified         string                 `json:"modified,omitempty"`
+	Withdrawn        string                 `json:"withdrawn,omitempty"`
+	Severity         []Severity             `json:"severity,omitempty"`
+	Affected         []Affected             `json:"affected,omitempty"`
+	References       []Reference            `json:"references,omitempty"`
+	DatabaseSpecific map[string]interface{} `json:"database_specific,omitempty"`
+}
+
+// Severity defines a severity vector, e.g. of type CVSS_V3
+type Severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// Affected defines the affected versions of a package
+type Affected struct {
+	Package          Package                `json:"package"`
+	Ranges           []Range                `json:"ranges,omitempty"`
+	Versions         []string               `json:"versions,omitempty"`
+	DatabaseSpecific map[string]interface{} `json:"database_specific,omitempty"`
+}
+
+// Package identifies a package within an ecosystem
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Purl      string `json:"purl,omitempty"`
+}
+
+// Range defines a list of version events of type SEMVER, ECOSYSTEM or GIT
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+// Event defines a single version event of a range
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// Reference links additional information
+type Reference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// Database contains the vulnerabilities of a local OSV database export indexed by ecosystem and package name
+type Database struct {
+	packages map[string][]*Vulnerability
+	count    int
+}
+
+// LoadDatabase reads an OSV database export.
+// The path can either point to a directory containing the JSON records (e.g. an extracted export of osv.dev or the GitHub advisory database)
+// or to a zip archive as provided by osv.dev per ecosystem (e.g. npm/all.zip).
+func LoadDatabase(path string, utils piperutils.FileUtils) (*Database, error) {
+	db := &Database{packages: map[string][]*Vulnerability{}}
+
+	isDir, err := utils.DirExists(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check vulnerability database '%v'", path)
+	}
+	if isDir {
+		files, err := utils.Glob(filepath.Join(path, "**", "*.json"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list vulnerability database '%v'", path)
+		}
+		for _, file := range files {
+			content, err := utils.FileRead(file)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read vulnerability record '%v'", file)
+			}
+			if err := db.add(file, content); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		if err := db.loadArchive(path, utils); err != nil {
+			return nil, err
+		}
+	}
+	log.Entry().Infof("Loaded %v vulnerability records from %v", db.count, path)
+	return db, nil
+}
+
+func (db *Database) loadArchive(path string, utils piperutils.FileUtils) error {
+	content, err := utils.FileRead(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read vulnerability database '%v'", path)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return errors.Wrapf(err, "vulnerability database '%v' is neither a directory nor a zip archive", path)
+	}
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !strings.HasSuffix(file.Name, ".json") {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return errors.Wrapf(err, "failed to open '%v' in '%v'", file.Name, path)
+		}
+		record, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return errors.Wrapf(err, "failed to read '%v' in '%v'", file.Name, path)
+		}
+		if err := db.add(file.Name, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *Database) add(name string, content []byte) error {
+	vuln := Vulnerability{}
+	if err := json.Unmarshal(content, &vuln); err != nil {
+		return errors.Wrapf(err, "failed to parse vulnerability record '%v'", name)
+	}
+	if len(vuln.ID) == 0 || len(vuln.Withdrawn) > 0 {
+		return nil
+	}
+	db.Add(&vuln)
+	return nil
+}
+
+// Add adds a vulnerability to the database
+func (db *Database) Add(vuln *Vulnerability) {
+	keys := []string{}
+	for _, affected := range vuln.Affected {
+		key := packageKey(affected.Package.Ecosystem, affected.Package.Name)
+		if !contains(keys, key) {
+			keys = append(keys, key)
+			db.packages[key] = append(db.packages[key], vuln)
+		}
+	}
+	db.count++
+}
+
+// Count returns the number of vulnerabilities in the database
+func (db *Database) Count() int {
+	return db.count
+}
+
+// Match defines a vulnerability affecting a package version
+type Match struct {
+	Vulnerability *Vulnerability
+	FixedVersions []string
+}
+
+// Query returns the vulnerabilities affecting the package identified by the package URL.
+// Packages of Linux distributions are additionally matched by their source package provided with the qualifier "upstream" (e.g. upstream=openssl or upstream=openssl@3.0.11-1)
+// and only against records of the same distribution release if the qualifier "distro" is available (e.g. distro=debian-12).
+func (db *Database) Query(purl packageurl.PackageURL) []Match {
+	ecosystem, name := Ecosystem(purl)
+	if len(ecosystem) == 0 || len(purl.Version) == 0 {
+		return nil
+	}
+	qualifiers := purl.Qualifiers.Map()
+	version := purl.Version
+	if epoch := qualifiers["epoch"]; purl.Type == "rpm" && len(epoch) > 0 && epoch != "0" && !strings.Contains(version, ":") {
+		version = epoch + ":" + version
+	}
+	candidates := []packageVersion{{name: name, version: version}}
+	if upstream := qualifiers["upstream"]; len(upstream) > 0 {
+		candidate := packageVersion{name: upstream, version: version}
+		if i := strings.Index(upstream, "@"); i >= 0 {
+			candidate = packageVersion{name: upstream[:i], version: upstream[i+1:]}
+		}
+		if candidate.name != name {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	matches := []Match{}
+	for _, candidate := range candidates {
+		for _, vuln := range db.packages[packageKey(ecosystem, candidate.name)] {
+			if containsVulnerability(matches, vuln) {
+				continue
+			}
+			match := Match{Vulnerability: vuln}
+			affected := false
+			for _, a := range vuln.Affected {
+				if packageKey(a.Package.Ecosystem, a.Package.Name) != packageKey(ecosystem, candidate.name) || !sameRelease(a.Package.Ecosystem, qualifiers["distro"]) {
+					continue
+				}
+				if a.IsAffected(candidate.version) {
+					affected = true
+					for _, fixed := range a.FixedVersions() {
+						if !contains(match.FixedVersions, fixed) {
+							match.FixedVersions = append(match.FixedVersions, fixed)
+						}
+					}
+				}
+			}
+			if affected {
+				matches = append(matches, match)
+			}
+		}
+	}
+	return matches
+}
+
+type packageVersion struct {
+	name    string
+	version string
+}
+
+func containsVulnerability(matches []Match, vuln *Vulnerability) bool {
+	for _, match := range matches {
+		if match.Vulnerability == vuln {
+			return true
+		}
+	}
+	return false
+}
+
+// sameRelease checks whether the release of an ecosystem (e.g. Debian:11 or Alpine:v3.18) matches the distribution of a package (e.g. debian-11 or alpine-3.18.4).
+// Records without release and packages without distribution always match.
+func sameRelease(ecosystem, distro string) bool {
+	parts := strings.SplitN(ecosystem, ":", 3)
+	if len(parts) < 2 || len(distro) == 0 {
+		return true
+	}
+	release := strings.TrimPrefix(parts[1], "v")
+	if len(release) == 0 || !unicode.IsDigit(rune(release[0])) {
+		return true
+	}
+	distroVersion := distro[strings.LastIndex(distro, "-")+1:]
+	return distroVersion == release || strings.HasPrefix(distroVersion, release+".")
+}
+
+// Ecosystem returns the OSV ecosystem and package name for a package URL
+func Ecosystem(purl packageurl.PackageURL) (string, string) {
+	name := purl.Name
+	switch purl.Type {
+	case packageurl.TypeNPM:
+		if len(purl.Namespace) > 0 {
+			name = purl.Namespace + "/" + purl.Name
+		}
+		return "npm", name
+	case packageurl.TypeMaven:
+		return "Maven", purl.Namespace + ":" + purl.Name
+	case packageurl.TypeGolang:
+		if len(purl.Namespace) > 0 {
+			name = purl.Namespace + "/" + purl.Name
+		}
+		return "Go", name
+	case packageurl.TypePyPi:
+		return "PyPI", strings.ReplaceAll(strings.ToLower(purl.Name), "_", "-")
+	case packageurl.TypeNuget:
+		return "NuGet", name
+	case packageurl.TypeGem:
+		return "RubyGems", name
+	case "cargo":
+		return "crates.io", name
+	case packageurl.TypeComposer:
+		return "Packagist", purl.Namespace + "/" + purl.Name
+	case "hex":
+		return "Hex", name
+	case "pub":
+		return "Pub", name
+	case "deb", packageurl.TypeDebian:
+		if strings.EqualFold(purl.Namespace, "ubuntu") {
+			return "Ubuntu", name
+		}
+		return "Debian", name
+	case "apk":
+		switch strings.ToLower(purl.Namespace) {
+		case "wolfi":
+			return "Wolfi", name
+		case "chainguard":
+			return "Chainguard", name
+		}
+		return "Alpine", name
+	case packageurl.TypeRPM:
+		switch strings.ToLower(purl.Namespace) {
+		case "redhat", "rhel":
+			return "Red Hat", name
+		case "almalinux":
+			return "AlmaLinux", name
+		case "rocky":
+			return "Rocky Linux", name
+		case "opensuse", "opensuse-leap", "opensuse-tumbleweed":
+			return "openSUSE", name
+		case "suse", "sles":
+			return "SUSE", name
+		case "mageia":
+			return "Mageia", name
+		case "openeuler":
+			return "openEuler", name
+		}
+	}
+	return "", ""
+}
+
+// IsAffected checks if a version is affected, i.e. if it is explicitly listed or contained in one of the ranges
+func (a Affected) IsAffected(version string) bool {
+	for _, v := range a.Versions {
+		if v == version {
+			return true
+		}
+	}
+	compare := versionComparator(a.Package.Ecosystem)
+	for _, r := range a.Ranges {
+		switch r.Type {
+		case "GIT":
+			continue
+		case "SEMVER":
+			if r.Contains(version) {
+				return true
+			}
+		default:
+			if r.contains(version, compare) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FixedVersions returns the versions fixing the vulnerability
+func (a Affected) FixedVersions() []string {
+	fixed := []string{}
+	for _, r := range a.Ranges {
+		if r.Type == "GIT" {
+			continue
+		}
+		for _, event := range r.Events {
+			if len(event.Fixed) > 0 {
+				fixed = append(fixed, event.Fixed)
+			}
+		}
+	}
+	return fixed
+}
+
+// Contains evaluates the events of the range in version order as defined by the OSV schema
+func (r Range) Contains(version string) bool {
+	return r.contains(version, CompareVersions)
+}
+
+func (r Range) contains(version string, compare func(a, b string) int) bool {
+	affected := false
+	for _, event := range sortedEvents(r.Events, compare) {
+		switch {
+		case len(event.Introduced) > 0:
+			if event.Introduced == "0" || compare(version, event.Introduced) >= 0 {
+				affected = true
+			}
+		case len(event.Fixed) > 0:
+			if compare(version, event.Fixed) >= 0 {
+				affected = false
+			}
+		case len(event.LastAffected) > 0:
+			if compare(version, event.LastAffected) > 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+// Score returns the highest CVSS v3 base score of the vulnerability.
+// If no CVSS v3 vector is available, the score is derived from the severity provided by the database (e.g. GHSA).
+func (v *Vulnerability) Score() float64 {
+	score := -1.0
+	for _, severity := range v.Severity {
+		if severity.Type != "CVSS_V3" {
+			continue
+		}
+		if s, err := CVSS3BaseScore(severity.Score); err == nil && s > score {
+			score = s
+		}
+	}
+	if score >= 0 {
+		return score
+	}
+	if s, ok := severityScore(v.DatabaseSpecific); ok {
+		return s
+	}
+	for _, affected := range v.Affected {
+		if s, ok := severityScore(affected.DatabaseSpecific); ok && s > score {
+			score = s
+		}
+	}
+	return score
+}
+
+// IDs returns the ID and the aliases of the vulnerability
+func (v *Vulnerability) IDs() []string {
+	return append([]string{v.ID}, v.Aliases...)
+}
+
+// CVE returns the CVE identifier of the vulnerability if available, otherwise its ID
+func (v *Vulnerability) CVE() string {
+	for _, id := range v.IDs() {
+		if strings.HasPrefix(id, "CVE-") {
+			return id
+		}
+	}
+	return v.ID
+}
+
+// URL returns the advisory URL of the vulnerability
+func (v *Vulnerability) URL() string {
+	for _, ref := range v.References {
+		if ref.Type == "ADVISORY" {
+			return ref.URL
+		}
+	}
+	return fmt.Sprintf("https://osv.dev/vulnerability/%v", v.ID)
+}
+
+// SeverityForScore maps a CVSS v3 score to its qualitative rating
+func SeverityForScore(score float64) string {
+	switch {
+	case score >= 9.0:
+		return "critical"
+	case score >= 7.0:
+		return "high"
+	case score >= 4.0:
+		return "medium"
+	case score > 0:
+		return "low"
+	case score == 0:
+		return "none"
+	}
+	return "unknown"
+}
+
+func severityScore(databaseSpecific map[string]interface{}) (float64, bool) {
+	severity, ok := databaseSpecific["severity"].(string)
+	if !ok {
+		return 0, false
+	}
+	switch strings.ToUpper(severity) {
+	case "CRITICAL":
+		return 9.0, true
+	case "HIGH":
+		return 7.0, true
+	case "MODERATE", "MEDIUM":
+		return 4.0, true
+	case "LOW":
+		return 0.1, true
+	}
+	return 0, false
+}
+
+func packageKey(ecosystem, name string) string {
+	// ecosystems may carry a release suffix, e.g. Debian:11 or Alpine:v3.18
+	ecosystem = strings.SplitN(ecosystem, ":", 2)[0]
+	return strings.ToLower(ecosystem + "/" + name)
+}
+
+func contains(list []string, value string) bool {
+	for _, elem := range list {
+		if elem == value {
+			return true
+		}
+	}
+	return false
+}
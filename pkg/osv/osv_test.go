@@ -0,0 +1,181 @@
+//go:build unit
+// +build unit
+
+package osv
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDatabase(t *testing.T) {
+	record, err := os.ReadFile("testdata/GHSA-test-lodash.json")
+	require.NoError(t, err)
+
+	t.Run("success case - directory", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("osv/npm/GHSA-35jh-r3h4-6jhm.json", record)
+		utils.AddFile("osv/npm/withdrawn.json", []byte(`{"id": "GHSA-withdrawn", "withdrawn": "2023-01-01T00:00:00Z", "affected": [{"package": {"ecosystem": "npm", "name": "lodash"}}]}`))
+
+		db, err := LoadDatabase("osv", utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, db.Count())
+	})
+
+	t.Run("success case - zip archive", func(t *testing.T) {
+		buffer := bytes.Buffer{}
+		archive := zip.NewWriter(&buffer)
+		w, _ := archive.Create("GHSA-35jh-r3h4-6jhm.json")
+		w.Write(record)
+		archive.Close()
+		utils := &mock.FilesMock{}
+		utils.AddFile("all.zip", buffer.Bytes())
+
+		db, err := LoadDatabase("all.zip", utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, db.Count())
+	})
+
+	t.Run("error case - invalid record", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("osv/invalid.json", []byte("{"))
+
+		_, err := LoadDatabase("osv", utils)
+
+		assert.EqualError(t, err, "failed to parse vulnerability record 'osv/invalid.json': unexpected end of JSON input")
+	})
+
+	t.Run("error case - no archive", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("all.zip", []byte("no zip"))
+
+		_, err := LoadDatabase("all.zip", utils)
+
+		assert.Contains(t, err.Error(), "vulnerability database 'all.zip' is neither a directory nor a zip archive")
+	})
+}
+
+func TestQuery(t *testing.T) {
+	db := &Database{packages: map[string][]*Vulnerability{}}
+	db.Add(&Vulnerability{ID: "GHSA-1", Affected: []Affected{{
+		Package: Package{Ecosystem: "Maven", Name: "org.apache.logging.log4j:log4j-core"},
+		Ranges: []Range{{Type: "ECOSYSTEM", Events: []Event{
+			{Introduced: "2.0-beta9"}, {Fixed: "2.3.1"},
+			{Introduced: "2.4"}, {Fixed: "2.12.2"},
+			{Introduced: "2.13.0"}, {Fixed: "2.15.0"},
+		}}},
+	}}})
+	db.Add(&Vulnerability{ID: "PYSEC-1", Affected: []Affected{{
+		Package:  Package{Ecosystem: "PyPI", Name: "pyyaml"},
+		Versions: []string{"5.3"},
+	}}})
+	db.Add(&Vulnerability{ID: "GO-1", Affected: []Affected{{
+		Package: Package{Ecosystem: "Go", Name: "golang.org/x/text"},
+		Ranges:  []Range{{Type: "SEMVER", Events: []Event{{Introduced: "0"}, {LastAffected: "0.3.7"}}}},
+	}}})
+
+	tt := []struct {
+		purl     string
+		expected []string
+		fixed    []string
+	}{
+		{purl: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", expected: []string{"GHSA-1"}, fixed: []string{"2.3.1", "2.12.2", "2.15.0"}},
+		{purl: "pkg:maven/org.apache.logging.log4j/log4j-core@2.12.2", expected: []string{}},
+		{purl: "pkg:maven/org.apache.logging.log4j/log4j-core@2.17.1?type=jar", expected: []string{}},
+		{purl: "pkg:pypi/PyYAML@5.3", expected: []string{"PYSEC-1"}},
+		{purl: "pkg:golang/golang.org/x/text@v0.3.7", expected: []string{"GO-1"}},
+		{purl: "pkg:golang/golang.org/x/text@v0.3.8", expected: []string{}},
+		{purl: "pkg:generic/unknown@1.0", expected: nil},
+	}
+	for _, test := range tt {
+		t.Run(test.purl, func(t *testing.T) {
+			purl, err := packageurl.FromString(test.purl)
+			require.NoError(t, err)
+
+			matches := db.Query(purl)
+
+			ids := []string{}
+			if matches == nil {
+				ids = nil
+			}
+			for _, match := range matches {
+				ids = append(ids, match.Vulnerability.ID)
+				if test.fixed != nil {
+					assert.Equal(t, test.fixed, match.FixedVersions)
+				}
+			}
+			assert.Equal(t, test.expected, ids)
+		})
+	}
+}
+
+func TestQueryDistributionPackages(t *testing.T) {
+	db := &Database{packages: map[string][]*Vulnerability{}}
+	db.Add(&Vulnerability{ID: "DSA-1", Affected: []Affected{
+		{
+			Package: Package{Ecosystem: "Debian:11", Name: "openssl"},
+			Ranges:  []Range{{Type: "ECOSYSTEM", Events: []Event{{Introduced: "0"}, {Fixed: "1.1.1n-0+deb11u5"}}}},
+		},
+		{
+			Package: Package{Ecosystem: "Debian:12", Name: "openssl"},
+			Ranges:  []Range{{Type: "ECOSYSTEM", Events: []Event{{Introduced: "0"}, {Fixed: "3.0.11-1~deb12u1"}}}},
+		},
+	}})
+	db.Add(&Vulnerability{ID: "ALSA-1", Affected: []Affected{{
+		Package: Package{Ecosystem: "AlmaLinux:9", Name: "openssl"},
+		Ranges:  []Range{{Type: "ECOSYSTEM", Events: []Event{{Introduced: "0"}, {Fixed: "1:3.0.7-25.el9_3"}}}},
+	}}})
+
+	tt := []struct {
+		purl     string
+		expected []string
+	}{
+		{purl: "pkg:deb/debian/libssl1.1@1.1.1n-0+deb11u4?distro=debian-11&upstream=openssl", expected: []string{"DSA-1"}},
+		{purl: "pkg:deb/debian/libssl1.1@1.1.1n-0+deb11u5?distro=debian-11&upstream=openssl", expected: []string{}},
+		{purl: "pkg:deb/debian/libssl3@3.0.9-1?distro=debian-12&upstream=openssl", expected: []string{"DSA-1"}},
+		{purl: "pkg:deb/debian/libssl3@3.0.11-1~deb12u2?distro=debian-12&upstream=openssl", expected: []string{}},
+		{purl: "pkg:deb/debian/openssl@3.0.11-1~deb12u2?distro=debian-12", expected: []string{}},
+		{purl: "pkg:rpm/almalinux/openssl-libs@3.0.7-24.el9?epoch=1&distro=almalinux-9.3&upstream=openssl", expected: []string{"ALSA-1"}},
+		{purl: "pkg:rpm/almalinux/openssl-libs@3.0.7-25.el9_3?epoch=1&distro=almalinux-9.3&upstream=openssl", expected: []string{}},
+		{purl: "pkg:rpm/almalinux/openssl@3.0.7-24.el9?epoch=1&distro=almalinux-8.9", expected: []string{}},
+	}
+	for _, test := range tt {
+		t.Run(test.purl, func(t *testing.T) {
+			purl, err := packageurl.FromString(test.purl)
+			require.NoError(t, err)
+
+			ids := []string{}
+			for _, match := range db.Query(purl) {
+				ids = append(ids, match.Vulnerability.ID)
+			}
+			assert.Equal(t, test.expected, ids)
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	t.Run("CVSS v3 vector", func(t *testing.T) {
+		vuln := Vulnerability{Severity: []Severity{{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}}}
+		assert.Equal(t, 9.8, vuln.Score())
+	})
+
+	t.Run("database severity", func(t *testing.T) {
+		vuln := Vulnerability{DatabaseSpecific: map[string]interface{}{"severity": "MODERATE"}}
+		assert.Equal(t, 4.0, vuln.Score())
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		vuln := Vulnerability{Severity: []Severity{{Type: "CVSS_V4", Score: "CVSS:4.0/AV:N"}}}
+		assert.Equal(t, -1.0, vuln.Score())
+		assert.Equal(t, "unknown", SeverityForScore(vuln.Score()))
+	})
+}
@@ -0,0 +1,274 @@
+package osv
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// ReportsDirectory defines the subfolder for the reports which are generated
+const ReportsDirectory = "sbomScan"
+
+// CountSecurityVulnerabilities counts the findings with a score above and below the severity limit
+func CountSecurityVulnerabilities(findings []Finding, cvssSeverityLimit float64) (int, int) {
+	severe := 0
+	for _, finding := range findings {
+		if isSevereVulnerability(finding, cvssSeverityLimit) {
+			severe++
+		}
+	}
+	return severe, len(findings) - severe
+}
+
+func isSevereVulnerability(finding Finding, cvssSeverityLimit float64) bool {
+	return cvssSeverityLimit >= 0 && finding.Score() >= cvssSeverityLimit
+}
+
+// CreateCustomVulnerabilityReport creates a vulnerability ScanReport to be used for uploading into various sinks
+func CreateCustomVulnerabilityReport(databasePath string, bomFiles []string, findings, assessedFindings []Finding, cvssSeverityLimit float64) reporting.ScanReport {
+	severe, _ := CountSecurityVulnerabilities(findings, cvssSeverityLimit)
+
+	// sort according to vulnerability severity
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Score() > findings[j].Score()
+	})
+
+	scanReport := reporting.ScanReport{
+		ReportTitle: "SBOM Security Vulnerability Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Vulnerability database", Details: databasePath},
+			{Description: "Scanned SBOMs", Details: strings.Join(bomFiles, ", ")},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Total number of vulnerabilities", Details: fmt.Sprint(len(findings))},
+			{Description: fmt.Sprintf("Total number of vulnerabilities with CVSS score >= %.1f", cvssSeverityLimit), Details: fmt.Sprint(severe)},
+			{Description: "Total number of assessed vulnerabilities", Details: fmt.Sprint(len(assessedFindings))},
+		},
+		SuccessfulScan: severe == 0,
+		ReportTime:     time.Now(),
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No publicly known vulnerabilities detected",
+		Headers: []string{
+			"Vulnerability",
+			"Aliases",
+			"CVSS Score",
+			"Severity",
+			"Package",
+			"Fixed versions",
+			"SBOM",
+			"Summary",
+		},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+
+	for _, finding := range findings {
+		var scoreStyle reporting.ColumnStyle = reporting.Yellow
+		if isSevereVulnerability(finding, cvssSeverityLimit) {
+			scoreStyle = reporting.Red
+		}
+		row := reporting.ScanRow{}
+		row.AddColumn(fmt.Sprintf(`<a href="%v">%v</a>`, finding.Vulnerability.URL(), finding.Vulnerability.ID), 0)
+		row.AddColumn(strings.Join(finding.Vulnerability.Aliases, ", "), 0)
+		row.AddColumn(scoreText(finding.Score()), scoreStyle)
+		row.AddColumn(SeverityForScore(finding.Score()), 0)
+		row.AddColumn(finding.Purl.ToString(), 0)
+		row.AddColumn(strings.Join(finding.FixedVersions, ", "), 0)
+		row.AddColumn(finding.BOMFile, 0)
+		row.AddColumn(finding.Vulnerability.Summary, 0)
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+	scanReport.DetailTable = detailTable
+
+	return scanReport
+}
+
+// WriteCustomVulnerabilityReports creates an HTML and a JSON format file based on the findings of the scan
+func WriteCustomVulnerabilityReports(scanReport reporting.ScanReport, bomFiles []string, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+
+	// ignore templating errors since template is in our hands and issues will be detected with the automated tests
+	htmlReport, _ := scanReport.ToHTML()
+	if err := utils.MkdirAll(ReportsDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrapf(err, "failed to create report directory")
+	}
+	htmlReportPath := filepath.Join(ReportsDirectory, "piper_sbom_vulnerability_report.html")
+	if err := utils.FileWrite(htmlReportPath, htmlReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrapf(err, "failed to write html report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "SBOM Vulnerability Report", Target: htmlReportPath})
+
+	// JSON reports are used by step pipelineCreateSummary in order to e.g. prepare an issue creation in GitHub
+	// ignore JSON errors since structure is in our hands
+	jsonReport, _ := scanReport.ToJSON()
+	if exists, _ := utils.DirExists(reporting.StepReportDirectory); !exists {
+		if err := utils.MkdirAll(reporting.StepReportDirectory, 0777); err != nil {
+			return reportPaths, errors.Wrap(err, "failed to create step reporting directory")
+		}
+	}
+	reportSha := fmt.Sprintf("%x", sha1.Sum([]byte(strings.Join(bomFiles, ","))))
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, fmt.Sprintf("sbomExecuteVulnerabilityScan_oss_%v.json", reportSha)), jsonReport, 0666); err != nil {
+		return reportPaths, errors.Wrapf(err, "failed to write json report")
+	}
+
+	return reportPaths, nil
+}
+
+// CreateSarifResultFile creates a SARIF result from the findings of the scan
+func CreateSarifResultFile(findings []Finding) *format.SARIF {
+	log.Entry().Debug("Creating SARIF file for data transfer")
+	sarif := format.SARIF{
+		Schema:  "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []format.Runs{{Results: []format.Results{}}},
+	}
+
+	tool := format.Tool{Driver: format.Driver{
+		Name:           "Piper SBOM vulnerability scan",
+		InformationUri: "https://osv.dev",
+	}}
+
+	collectedRules := []string{}
+	for _, finding := range findings {
+		vuln := finding.Vulnerability
+		purl := finding.Purl.ToString()
+		result := format.Results{
+			RuleID:         vuln.ID,
+			Level:          transformToLevel(finding.Score()),
+			Message:        &format.Message{Text: fmt.Sprintf("%v affects %v", vuln.ID, purl)},
+			AnalysisTarget: &format.ArtifactLocation{URI: finding.BOMFile},
+			Locations: []format.Location{{PhysicalLocation: format.PhysicalLocation{
+				ArtifactLocation: format.ArtifactLocation{URI: finding.BOMFile},
+				LogicalLocations: []format.LogicalLocation{{FullyQualifiedName: purl}},
+			}}},
+			PartialFingerprints: format.PartialFingerprints{
+				PackageURLPlusCVEHash: base64.URLEncoding.EncodeToString([]byte(fmt.Sprintf("%v+%v", purl, vuln.ID))),
+			},
+			Properties: getAuditInformation(finding),
+		}
+		sarif.Runs[0].Results = append(sarif.Runs[0].Results, result)
+
+		// only create rule on new vulnerability
+		if !piperutils.ContainsString(collectedRules, vuln.ID) {
+			collectedRules = append(collectedRules, vuln.ID)
+			description := vuln.Details
+			if len(description) == 0 {
+				description = vuln.Summary
+			}
+			rule := format.SarifRule{
+				ID:                   vuln.ID,
+				Name:                 vuln.CVE(),
+				ShortDescription:     &format.Message{Text: fmt.Sprintf("%v Package %v", vuln.ID, finding.Purl.Name)},
+				FullDescription:      &format.Message{Text: description},
+				DefaultConfiguration: &format.DefaultConfiguration{Level: transformToLevel(finding.Score())},
+				HelpURI:              vuln.URL(),
+				Help: &format.Help{
+					Text:     fmt.Sprintf("%v\n\nFixed versions: %v", description, strings.Join(finding.FixedVersions, ", ")),
+					Markdown: fmt.Sprintf("**%v** %v\n\n%v\n\n**Fixed versions:** %v", vuln.ID, vuln.Summary, vuln.Details, strings.Join(finding.FixedVersions, ", ")),
+				},
+				Properties: &format.SarifRuleProperties{
+					Tags:      append([]string{"security", purl}, vuln.Aliases...),
+					Precision: "very-high",
+				},
+			}
+			if score := finding.Score(); score >= 0 {
+				rule.Properties.SecuritySeverity = fmt.Sprint(score)
+			}
+			tool.Driver.Rules = append(tool.Driver.Rules, rule)
+		}
+	}
+	sarif.Runs[0].Tool = tool
+
+	conversion := new(format.Conversion)
+	conversion.Tool.Driver.Name = "Piper SBOM to SARIF converter"
+	conversion.Tool.Driver.InformationUri = "https://github.com/SAP/jenkins-library"
+	conversion.Invocation.ExecutionSuccessful = true
+	conversion.Invocation.Properties = &format.InvocationProperties{Platform: runtime.GOOS}
+	sarif.Runs[0].Conversion = conversion
+
+	return &sarif
+}
+
+// CreateVEXStatements returns the VEX statements for the assessed findings
+func CreateVEXStatements(assessedFindings []Finding) []format.VEXStatement {
+	statements := []format.VEXStatement{}
+	for _, finding := range assessedFindings {
+		if finding.Assessment != nil {
+			statements = append(statements, format.VEXStatement{
+				Vulnerability: finding.Assessment.Vulnerability,
+				Purl:          finding.Purl.ToString(),
+				Assessment:    *finding.Assessment,
+			})
+		}
+	}
+	return statements
+}
+
+// WriteSarifFile writes a JSON sarif format file for upload into e.g. GitHub code scanning
+func WriteSarifFile(sarif *format.SARIF, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+
+	sarifReport, err := json.Marshal(sarif)
+	if err != nil {
+		return reportPaths, errors.Wrapf(err, "failed to marshall SARIF json file")
+	}
+	if err := utils.MkdirAll(ReportsDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrapf(err, "failed to create report directory")
+	}
+	sarifReportPath := filepath.Join(ReportsDirectory, "piper_sbom_vulnerability.sarif")
+	if err := utils.FileWrite(sarifReportPath, sarifReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrapf(err, "failed to write SARIF file")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "SBOM Vulnerability SARIF file", Target: sarifReportPath})
+
+	return reportPaths, nil
+}
+
+func getAuditInformation(finding Finding) *format.SarifProperties {
+	unifiedAuditState := "new"
+	auditMessage := ""
+	if finding.Assessment != nil {
+		unifiedAuditState = string(finding.Assessment.Status)
+		auditMessage = string(finding.Assessment.Analysis)
+	}
+	return &format.SarifProperties{
+		Audited:           unifiedAuditState == string(format.Relevant) || unifiedAuditState == string(format.NotRelevant),
+		ToolSeverity:      SeverityForScore(finding.Score()),
+		ToolAuditMessage:  auditMessage,
+		UnifiedAuditState: unifiedAuditState,
+	}
+}
+
+func transformToLevel(score float64) string {
+	switch SeverityForScore(score) {
+	case "critical", "high":
+		return "error"
+	case "medium", "low":
+		return "warning"
+	}
+	return "none"
+}
+
+func scoreText(score float64) string {
+	if score < 0 {
+		return "n/a"
+	}
+	return fmt.Sprint(score)
+}
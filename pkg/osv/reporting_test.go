@@ -0,0 +1,122 @@
+//go:build unit
+// +build unit
+
+package osv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/sbom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scanTestBOM(t *testing.T) []Finding {
+	record, err := os.ReadFile("testdata/GHSA-test-lodash.json")
+	require.NoError(t, err)
+	bom, err := os.ReadFile("testdata/bom-npm.json")
+	require.NoError(t, err)
+	utils := &mock.FilesMock{}
+	utils.AddFile("osv/GHSA-35jh-r3h4-6jhm.json", record)
+	utils.AddFile("bom-npm.json", bom)
+
+	db, err := LoadDatabase("osv", utils)
+	require.NoError(t, err)
+	cdxBOM, err := sbom.ReadBOM("bom-npm.json", utils)
+	require.NoError(t, err)
+	return db.ScanBOM(cdxBOM, "bom-npm.json")
+}
+
+func TestScanBOM(t *testing.T) {
+	findings := scanTestBOM(t)
+
+	require.Len(t, findings, 1)
+	assert.Equal(t, "GHSA-35jh-r3h4-6jhm", findings[0].Vulnerability.ID)
+	assert.Equal(t, "pkg:npm/lodash@4.17.20", findings[0].Purl.ToString())
+	assert.Equal(t, []string{"4.17.21"}, findings[0].FixedVersions)
+	assert.Equal(t, 7.2, findings[0].Score())
+}
+
+func TestFindingContainedIn(t *testing.T) {
+	findings := scanTestBOM(t)
+
+	t.Run("matching alias", func(t *testing.T) {
+		assessments := []format.Assessment{{Vulnerability: "CVE-2021-23337", Status: format.NotRelevant, Analysis: format.NotUsed, Purls: []format.Purl{{Purl: "pkg:npm/lodash@4.17.20"}}}}
+		finding := findings[0]
+
+		contained, err := finding.ContainedIn(&assessments)
+
+		assert.NoError(t, err)
+		assert.True(t, contained)
+		assert.Equal(t, format.NotUsed, finding.Assessment.Analysis)
+	})
+
+	t.Run("other package version", func(t *testing.T) {
+		assessments := []format.Assessment{{Vulnerability: "GHSA-35jh-r3h4-6jhm", Purls: []format.Purl{{Purl: "pkg:npm/lodash@4.17.19"}}}}
+		finding := findings[0]
+
+		contained, err := finding.ContainedIn(&assessments)
+
+		assert.NoError(t, err)
+		assert.False(t, contained)
+	})
+}
+
+func TestCreateSarifResultFile(t *testing.T) {
+	findings := scanTestBOM(t)
+
+	sarif := CreateSarifResultFile(findings)
+
+	require.Len(t, sarif.Runs[0].Results, 1)
+	result := sarif.Runs[0].Results[0]
+	assert.Equal(t, "GHSA-35jh-r3h4-6jhm", result.RuleID)
+	assert.Equal(t, "error", result.Level)
+	assert.Equal(t, "bom-npm.json", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "new", result.Properties.UnifiedAuditState)
+	require.Len(t, sarif.Runs[0].Tool.Driver.Rules, 1)
+	rule := sarif.Runs[0].Tool.Driver.Rules[0]
+	assert.Equal(t, "CVE-2021-23337", rule.Name)
+	assert.Equal(t, "7.2", rule.Properties.SecuritySeverity)
+	assert.Equal(t, "https://nvd.nist.gov/vuln/detail/CVE-2021-23337", rule.HelpURI)
+}
+
+func TestCreateCustomVulnerabilityReport(t *testing.T) {
+	findings := scanTestBOM(t)
+
+	t.Run("severe vulnerability", func(t *testing.T) {
+		scanReport := CreateCustomVulnerabilityReport("osv", []string{"bom-npm.json"}, findings, []Finding{}, 7.0)
+
+		assert.False(t, scanReport.SuccessfulScan)
+		assert.Equal(t, "1", scanReport.Overview[1].Details)
+		require.Len(t, scanReport.DetailTable.Rows, 1)
+		assert.Equal(t, reporting.ColumnStyle(reporting.Red), scanReport.DetailTable.Rows[0].Columns[2].Style)
+	})
+
+	t.Run("below threshold", func(t *testing.T) {
+		scanReport := CreateCustomVulnerabilityReport("osv", []string{"bom-npm.json"}, findings, []Finding{}, 9.0)
+
+		assert.True(t, scanReport.SuccessfulScan)
+	})
+}
+
+func TestWriteReports(t *testing.T) {
+	findings := scanTestBOM(t)
+	utils := &mock.FilesMock{}
+
+	scanReport := CreateCustomVulnerabilityReport("osv", []string{"bom-npm.json"}, findings, []Finding{}, 7.0)
+	reportPaths, err := WriteCustomVulnerabilityReports(scanReport, []string{"bom-npm.json"}, utils)
+	assert.NoError(t, err)
+	sarifPaths, err := WriteSarifFile(CreateSarifResultFile(findings), utils)
+	assert.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(ReportsDirectory, "piper_sbom_vulnerability_report.html"), reportPaths[0].Target)
+	assert.Equal(t, filepath.Join(ReportsDirectory, "piper_sbom_vulnerability.sarif"), sarifPaths[0].Target)
+	assert.True(t, utils.HasWrittenFile(filepath.Join(ReportsDirectory, "piper_sbom_vulnerability.sarif")))
+	jsonReports, _ := utils.Glob(filepath.Join(reporting.StepReportDirectory, "sbomExecuteVulnerabilityScan_oss_*.json"))
+	assert.Len(t, jsonReports, 1)
+}
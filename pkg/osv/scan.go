@@ -0,0 +1,67 @@
+package osv
+
+import (
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/package-url/packageurl-go"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/sbom"
+)
+
+// Finding defines a vulnerability detected for a component of an SBOM
+type Finding struct {
+	Vulnerability *Vulnerability
+	Purl          packageurl.PackageURL
+	FixedVersions []string
+	BOMFile       string
+	Assessment    *format.Assessment
+}
+
+// Score returns the CVSS v3 score of the finding
+func (f *Finding) Score() float64 {
+	return f.Vulnerability.Score()
+}
+
+// ContainedIn checks whether the finding is covered by one of the assessments and attaches the matching assessment.
+// Assessments match the ID of the vulnerability or one of its aliases (e.g. the CVE) in combination with the package URL.
+func (f *Finding) ContainedIn(assessments *[]format.Assessment) (bool, error) {
+	assessment, err := format.FindAssessment(assessments, &f.Purl, f.Vulnerability.IDs()...)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		log.Entry().WithError(err).Error("assessment from file ignored")
+		return false, err
+	}
+	if assessment != nil {
+		log.Entry().Debugf("matching assessment %v on package %v detected for vulnerability %v", assessment.Vulnerability, f.Purl.ToString(), f.Vulnerability.ID)
+		f.Assessment = assessment
+		return true, nil
+	}
+	return false, nil
+}
+
+// ScanBOM matches all components of the SBOM by their package URL against the database
+func (db *Database) ScanBOM(bom *cdx.BOM, bomFile string) []Finding {
+	findings := []Finding{}
+	scanned := map[string]bool{}
+	for _, component := range sbom.Components(bom) {
+		if len(component.PackageURL) == 0 || scanned[component.PackageURL] {
+			continue
+		}
+		scanned[component.PackageURL] = true
+		purl, err := packageurl.FromString(component.PackageURL)
+		if err != nil {
+			log.Entry().Debugf("ignoring component %v with invalid package URL '%v': %v", component.Name, component.PackageURL, err)
+			continue
+		}
+		for _, match := range db.Query(purl) {
+			findings = append(findings, Finding{
+				Vulnerability: match.Vulnerability,
+				Purl:          purl,
+				FixedVersions: match.FixedVersions,
+				BOMFile:       bomFile,
+			})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,426 @@
+package osv
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CompareVersions compares two versions and returns -1, 0 or 1.
+// The comparison covers semantic versions as well as the common dotted version schemes of the supported ecosystems:
+// numeric segments are compared numerically, other segments lexically, and a pre-release (e.g. 1.0.0-rc.1) precedes its release.
+func CompareVersions(a, b string) int {
+	a, aPre := splitPreRelease(normalizeVersion(a))
+	b, bPre := splitPreRelease(normalizeVersion(b))
+
+	if c := compareSegments(strings.Split(a, "."), strings.Split(b, ".")); c != 0 {
+		return c
+	}
+	switch {
+	case len(aPre) == 0 && len(bPre) == 0:
+		return 0
+	case len(aPre) == 0:
+		return 1
+	case len(bPre) == 0:
+		return -1
+	}
+	return compareSegments(strings.Split(aPre, "."), strings.Split(bPre, "."))
+}
+
+func normalizeVersion(version string) string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	// drop build metadata and Debian epochs
+	if i := strings.Index(version, "+"); i >= 0 {
+		version = version[:i]
+	}
+	if i := strings.Index(version, ":"); i >= 0 {
+		version = version[i+1:]
+	}
+	return version
+}
+
+func splitPreRelease(version string) (string, string) {
+	if i := strings.IndexAny(version, "-~"); i >= 0 {
+		return version[:i], version[i+1:]
+	}
+	return version, ""
+}
+
+func compareSegments(a, b []string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y string
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if c := compareSegment(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func compareSegment(a, b string) int {
+	aNum, aRest := numericPrefix(a)
+	bNum, bRest := numericPrefix(b)
+	if aNum != bNum {
+		if aNum < bNum {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(aRest, bRest)
+}
+
+func numericPrefix(segment string) (int64, string) {
+	i := strings.IndexFunc(segment, func(r rune) bool { return !unicode.IsDigit(r) })
+	if i < 0 {
+		i = len(segment)
+	}
+	if i == 0 {
+		return 0, segment
+	}
+	number, err := strconv.ParseInt(segment[:i], 10, 64)
+	if err != nil {
+		return 0, segment
+	}
+	return number, segment[i:]
+}
+
+func sortedEvents(events []Event, compare func(a, b string) int) []Event {
+	sorted := make([]Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compare(eventVersion(sorted[i]), eventVersion(sorted[j])) < 0
+	})
+	return sorted
+}
+
+func eventVersion(event Event) string {
+	switch {
+	case len(event.Introduced) > 0:
+		return event.Introduced
+	case len(event.Fixed) > 0:
+		return event.Fixed
+	case len(event.LastAffected) > 0:
+		return event.LastAffected
+	}
+	return event.Limit
+}
+
+// versionComparator returns the version comparison of the ecosystem.
+// Linux distributions define their own version schemes, all other ecosystems are compared with CompareVersions.
+func versionComparator(ecosystem string) func(a, b string) int {
+	switch strings.SplitN(ecosystem, ":", 2)[0] {
+	case "Debian", "Ubuntu":
+		return CompareDebianVersions
+	case "Alpine", "Wolfi", "Chainguard":
+		return CompareAlpineVersions
+	case "Red Hat", "AlmaLinux", "Rocky Linux", "openSUSE", "SUSE", "Mageia", "openEuler":
+		return CompareRPMVersions
+	}
+	return CompareVersions
+}
+
+// CompareDebianVersions compares two Debian package versions ([epoch:]upstream_version[-debian_revision]) as done by dpkg and returns -1, 0 or 1
+func CompareDebianVersions(a, b string) int {
+	aEpoch, aVersion := splitEpoch(strings.TrimSpace(a))
+	bEpoch, bVersion := splitEpoch(strings.TrimSpace(b))
+	if aEpoch != bEpoch {
+		return compareInt(aEpoch, bEpoch)
+	}
+	aUpstream, aRevision := splitRevision(aVersion)
+	bUpstream, bRevision := splitRevision(bVersion)
+	if c := dpkgVerRevCmp(aUpstream, bUpstream); c != 0 {
+		return c
+	}
+	return dpkgVerRevCmp(aRevision, bRevision)
+}
+
+// dpkgVerRevCmp compares alternating non-digit and digit parts, a tilde sorts before anything, even the end of a part
+func dpkgVerRevCmp(a, b string) int {
+	order := func(s string, i int) int {
+		if i >= len(s) {
+			return 0
+		}
+		c := s[i]
+		switch {
+		case c == '~':
+			return -1
+		case c >= '0' && c <= '9':
+			return 0
+		case unicode.IsLetter(rune(c)):
+			return int(c)
+		}
+		return int(c) + 256
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			if c := compareInt(int64(order(a, i)), int64(order(b, j))); c != 0 {
+				return c
+			}
+			i++
+			j++
+		}
+		aNum, aNext := digits(a, i)
+		bNum, bNext := digits(b, j)
+		if c := compareNumeric(aNum, bNum); c != 0 {
+			return c
+		}
+		i, j = aNext, bNext
+	}
+	return 0
+}
+
+// CompareRPMVersions compares two RPM package versions ([epoch:]version[-release]) as done by rpm and returns -1, 0 or 1
+func CompareRPMVersions(a, b string) int {
+	aEpoch, aVersion := splitEpoch(strings.TrimSpace(a))
+	bEpoch, bVersion := splitEpoch(strings.TrimSpace(b))
+	if aEpoch != bEpoch {
+		return compareInt(aEpoch, bEpoch)
+	}
+	aVer, aRelease := splitRevision(aVersion)
+	bVer, bRelease := splitRevision(bVersion)
+	if c := rpmVerCmp(aVer, bVer); c != 0 {
+		return c
+	}
+	// a missing release matches any release, e.g. in ranges fixed in 1.2 without release
+	if len(aRelease) == 0 || len(bRelease) == 0 {
+		return 0
+	}
+	return rpmVerCmp(aRelease, bRelease)
+}
+
+// rpmVerCmp implements the segment comparison of rpmvercmp including the tilde (pre-release) and caret (post-release) handling
+func rpmVerCmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+	isSeparator := func(c byte) bool {
+		return !isDigit(c) && !unicode.IsLetter(rune(c)) && c != '~' && c != '^'
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for i < len(a) && isSeparator(a[i]) {
+			i++
+		}
+		for j < len(b) && isSeparator(b[j]) {
+			j++
+		}
+
+		aTilde, bTilde := i < len(a) && a[i] == '~', j < len(b) && b[j] == '~'
+		if aTilde || bTilde {
+			if !aTilde {
+				return 1
+			}
+			if !bTilde {
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+
+		aCaret, bCaret := i < len(a) && a[i] == '^', j < len(b) && b[j] == '^'
+		if aCaret || bCaret {
+			switch {
+			case i >= len(a):
+				return -1
+			case j >= len(b):
+				return 1
+			case !aCaret:
+				return 1
+			case !bCaret:
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+
+		if i >= len(a) || j >= len(b) {
+			break
+		}
+
+		numeric := isDigit(a[i])
+		aEnd, bEnd := i, j
+		for aEnd < len(a) && segmentChar(a[aEnd], numeric) {
+			aEnd++
+		}
+		for bEnd < len(b) && segmentChar(b[bEnd], numeric) {
+			bEnd++
+		}
+		if bEnd == j {
+			// segments of different type, numeric segments are newer
+			if numeric {
+				return 1
+			}
+			return -1
+		}
+		var c int
+		if numeric {
+			c = compareNumeric(a[i:aEnd], b[j:bEnd])
+		} else {
+			c = strings.Compare(a[i:aEnd], b[j:bEnd])
+		}
+		if c != 0 {
+			return c
+		}
+		i, j = aEnd, bEnd
+	}
+	switch {
+	case i >= len(a) && j >= len(b):
+		return 0
+	case i >= len(a):
+		return -1
+	}
+	return 1
+}
+
+func segmentChar(c byte, numeric bool) bool {
+	if numeric {
+		return isDigit(c)
+	}
+	return unicode.IsLetter(rune(c))
+}
+
+// apkSuffixOrder defines the order of the suffixes of Alpine package versions, the position of a release without suffix is "".
+var apkSuffixOrder = []string{"alpha", "beta", "pre", "rc", "", "cvs", "svn", "git", "hg", "p"}
+
+// CompareAlpineVersions compares two Alpine package versions (e.g. 1.2.3a_rc1-r2) as done by apk and returns -1, 0 or 1
+func CompareAlpineVersions(a, b string) int {
+	aVersion, aRevision := splitApkRevision(strings.TrimSpace(a))
+	bVersion, bRevision := splitApkRevision(strings.TrimSpace(b))
+
+	aVersion, aSuffixes := splitApkSuffixes(aVersion)
+	bVersion, bSuffixes := splitApkSuffixes(bVersion)
+
+	aNumbers, aLetter := splitApkLetter(aVersion)
+	bNumbers, bLetter := splitApkLetter(bVersion)
+	aParts, bParts := strings.Split(aNumbers, "."), strings.Split(bNumbers, ".")
+	for k := 0; k < len(aParts) || k < len(bParts); k++ {
+		switch {
+		case k >= len(aParts):
+			return -1
+		case k >= len(bParts):
+			return 1
+		}
+		if c := compareNumeric(aParts[k], bParts[k]); c != 0 {
+			return c
+		}
+	}
+	if c := strings.Compare(aLetter, bLetter); c != 0 {
+		return c
+	}
+	for k := 0; k < len(aSuffixes) || k < len(bSuffixes); k++ {
+		aSuffix, bSuffix := apkSuffix{}, apkSuffix{}
+		if k < len(aSuffixes) {
+			aSuffix = aSuffixes[k]
+		}
+		if k < len(bSuffixes) {
+			bSuffix = bSuffixes[k]
+		}
+		if c := compareInt(int64(apkSuffixIndex(aSuffix.name)), int64(apkSuffixIndex(bSuffix.name))); c != 0 {
+			return c
+		}
+		if c := compareNumeric(aSuffix.number, bSuffix.number); c != 0 {
+			return c
+		}
+	}
+	return compareNumeric(aRevision, bRevision)
+}
+
+type apkSuffix struct {
+	name   string
+	number string
+}
+
+func splitApkRevision(version string) (string, string) {
+	if i := strings.LastIndex(version, "-r"); i >= 0 {
+		return version[:i], version[i+2:]
+	}
+	return version, "0"
+}
+
+func splitApkSuffixes(version string) (string, []apkSuffix) {
+	parts := strings.Split(version, "_")
+	suffixes := []apkSuffix{}
+	for _, part := range parts[1:] {
+		i := strings.IndexFunc(part, unicode.IsDigit)
+		if i < 0 {
+			i = len(part)
+		}
+		suffixes = append(suffixes, apkSuffix{name: part[:i], number: part[i:]})
+	}
+	return parts[0], suffixes
+}
+
+func splitApkLetter(version string) (string, string) {
+	if len(version) > 0 && unicode.IsLetter(rune(version[len(version)-1])) {
+		return version[:len(version)-1], version[len(version)-1:]
+	}
+	return version, ""
+}
+
+func apkSuffixIndex(name string) int {
+	for i, suffix := range apkSuffixOrder {
+		if suffix == name {
+			return i
+		}
+	}
+	// unknown suffixes sort like a release
+	return 4
+}
+
+func splitEpoch(version string) (int64, string) {
+	if i := strings.Index(version, ":"); i >= 0 {
+		epoch, err := strconv.ParseInt(version[:i], 10, 64)
+		if err == nil {
+			return epoch, version[i+1:]
+		}
+	}
+	return 0, version
+}
+
+func splitRevision(version string) (string, string) {
+	if i := strings.LastIndex(version, "-"); i >= 0 {
+		return version[:i], version[i+1:]
+	}
+	return version, ""
+}
+
+func digits(s string, i int) (string, int) {
+	start := i
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[start:i], i
+}
+
+// compareNumeric compares two strings of digits of arbitrary length
+func compareNumeric(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return compareInt(int64(len(a)), int64(len(b)))
+	}
+	return strings.Compare(a, b)
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,66 @@
+//go:build unit
+// +build unit
+
+package osv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tt := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "1.0.0", b: "1.0.0", expected: 0},
+		{a: "v1.2.3", b: "1.2.3", expected: 0},
+		{a: "1.2", b: "1.2.0", expected: 0},
+		{a: "1.10.0", b: "1.9.0", expected: 1},
+		{a: "1.0.0-rc.1", b: "1.0.0", expected: -1},
+		{a: "1.0.0-alpha", b: "1.0.0-beta", expected: -1},
+		{a: "1.0.0+build.1", b: "1.0.0", expected: 0},
+		{a: "2.0-beta9", b: "2.0", expected: -1},
+		{a: "2.3.1", b: "2.0-beta9", expected: 1},
+		{a: "1:2.4", b: "2.5", expected: -1},
+	}
+	for _, test := range tt {
+		t.Run(test.a+" vs "+test.b, func(t *testing.T) {
+			assert.Equal(t, test.expected, CompareVersions(test.a, test.b))
+		})
+	}
+}
+
+func TestCompareDistributionVersions(t *testing.T) {
+	tt := []struct {
+		name     string
+		compare  func(a, b string) int
+		a, b     string
+		expected int
+	}{
+		{name: "dpkg", compare: CompareDebianVersions, a: "1.1.1n-0+deb11u4", b: "1.1.1n-0+deb11u5", expected: -1},
+		{name: "dpkg", compare: CompareDebianVersions, a: "1:1.0", b: "2.0", expected: 1},
+		{name: "dpkg", compare: CompareDebianVersions, a: "1.0~rc1-1", b: "1.0-1", expected: -1},
+		{name: "dpkg", compare: CompareDebianVersions, a: "1.0-1", b: "1.0-1ubuntu0.1", expected: -1},
+		{name: "dpkg", compare: CompareDebianVersions, a: "2.36-9+deb12u3", b: "2.36-9+deb12u3", expected: 0},
+		{name: "dpkg", compare: CompareDebianVersions, a: "1.10", b: "1.9", expected: 1},
+		{name: "rpm", compare: CompareRPMVersions, a: "3.0.7-24.el9", b: "3.0.7-25.el9", expected: -1},
+		{name: "rpm", compare: CompareRPMVersions, a: "1:3.0.7-24.el9", b: "3.0.7-25.el9", expected: 1},
+		{name: "rpm", compare: CompareRPMVersions, a: "1.0~rc1-1", b: "1.0-1", expected: -1},
+		{name: "rpm", compare: CompareRPMVersions, a: "1.0^git1-1", b: "1.0-1", expected: 1},
+		{name: "rpm", compare: CompareRPMVersions, a: "2.28-225.el9_2", b: "2.28", expected: 0},
+		{name: "rpm", compare: CompareRPMVersions, a: "1.0a", b: "1.0.1", expected: -1},
+		{name: "apk", compare: CompareAlpineVersions, a: "3.1.4-r0", b: "3.1.4-r1", expected: -1},
+		{name: "apk", compare: CompareAlpineVersions, a: "1.2.3_rc1-r0", b: "1.2.3-r0", expected: -1},
+		{name: "apk", compare: CompareAlpineVersions, a: "1.2.3_p1-r0", b: "1.2.3-r5", expected: 1},
+		{name: "apk", compare: CompareAlpineVersions, a: "1.2.3a-r0", b: "1.2.3-r0", expected: 1},
+		{name: "apk", compare: CompareAlpineVersions, a: "1.36.1-r2", b: "1.36.1-r2", expected: 0},
+	}
+	for _, test := range tt {
+		t.Run(test.name+" "+test.a+" vs "+test.b, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.compare(test.a, test.b))
+			assert.Equal(t, -test.expected, test.compare(test.b, test.a))
+		})
+	}
+}
@@ -1,6 +1,13 @@
 package protecode
 
-import "strconv"
+import (
+	"strconv"
+
+	"github.com/package-url/packageurl-go"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+)
 
 const (
 	vulnerabilitySeverityThreshold = 7.0
@@ -39,3 +46,41 @@ func isSevere(vulnerability Vulnerability) bool {
 	}
 	return false
 }
+
+// ToPackageUrl creates the package URL for the component.
+// Protecode does not provide the package type of a component, thus components are identified as pkg:generic/<lib>@<version>.
+func (c Component) ToPackageUrl() *packageurl.PackageURL {
+	return packageurl.NewPackageURL("generic", "", c.Lib, c.Version, nil, "")
+}
+
+// ApplyAssessments attaches the matching assessments to the vulnerabilities of the result.
+// Assessed vulnerabilities are handled like vulnerabilities triaged within Protecode. The VEX statements of the assessed vulnerabilities are returned.
+func ApplyAssessments(result *Result, assessments *[]format.Assessment) []format.VEXStatement {
+	statements := []format.VEXStatement{}
+	if assessments == nil || len(*assessments) == 0 {
+		return statements
+	}
+	for i := range result.Components {
+		component := &result.Components[i]
+		purl := component.ToPackageUrl()
+		for j := range component.Vulns {
+			vulnerability := &component.Vulns[j]
+			assessment, err := format.FindAssessment(assessments, purl, vulnerability.Vuln.Cve)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				log.Entry().WithError(err).Error("assessment from file ignored")
+				continue
+			}
+			if assessment != nil {
+				log.Entry().Debugf("matching assessment %v on package %v detected for vulnerability %v", assessment.Vulnerability, purl.ToString(), vulnerability.Vuln.Cve)
+				vulnerability.Assessment = assessment
+				statements = append(statements, format.VEXStatement{
+					Vulnerability: vulnerability.Vuln.Cve,
+					Purl:          purl.ToString(),
+					Assessment:    *assessment,
+				})
+			}
+		}
+	}
+	return statements
+}
@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/SAP/jenkins-library/pkg/format"
 )
 
 func TestIsSevere(t *testing.T) {
@@ -128,3 +130,36 @@ func TestHasSevereVulnerabilities(t *testing.T) {
 		assert.False(t, HasSevereVulnerabilities(data, ""))
 	})
 }
+
+func TestApplyAssessments(t *testing.T) {
+	assessments := []format.Assessment{
+		{Vulnerability: "Cve1", Status: format.NotRelevant, Analysis: format.NotPresent, Purls: []format.Purl{{Purl: "pkg:generic/log4j@2.14.0"}}},
+	}
+	severe := Vulnerability{Exact: true, Triage: []Triage{}, Vuln: Vuln{Cve: "Cve1", Cvss: "8.0", Cvss3Score: "8.0"}}
+
+	t.Run("assessed vulnerability", func(t *testing.T) {
+		// init
+		data := Result{Components: []Component{{Lib: "log4j", Version: "2.14.0", Vulns: []Vulnerability{severe}}}}
+		// test
+		statements := ApplyAssessments(&data, &assessments)
+		// assert
+		assert.Equal(t, []format.VEXStatement{{Vulnerability: "Cve1", Purl: "pkg:generic/log4j@2.14.0", Assessment: assessments[0]}}, statements)
+		assert.NotNil(t, data.Components[0].Vulns[0].Assessment)
+		assert.False(t, HasSevereVulnerabilities(data, ""))
+	})
+	t.Run("assessment for other version", func(t *testing.T) {
+		// init
+		data := Result{Components: []Component{{Lib: "log4j", Version: "2.15.0", Vulns: []Vulnerability{severe}}}}
+		// test
+		statements := ApplyAssessments(&data, &assessments)
+		// assert
+		assert.Empty(t, statements)
+		assert.True(t, HasSevereVulnerabilities(data, ""))
+	})
+	t.Run("without assessments", func(t *testing.T) {
+		// init
+		data := Result{Components: []Component{{Lib: "log4j", Version: "2.14.0", Vulns: []Vulnerability{severe}}}}
+		// test && assert
+		assert.Empty(t, ApplyAssessments(&data, nil))
+	})
+}
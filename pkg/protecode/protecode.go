@@ -13,6 +13,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperHttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 )
@@ -46,14 +47,18 @@ type Result struct {
 
 // Component the protecode component information
 type Component struct {
-	Vulns []Vulnerability `json:"vulns,omitempty"`
+	Lib     string          `json:"lib,omitempty"`
+	Version string          `json:"version,omitempty"`
+	Vendor  string          `json:"vendor,omitempty"`
+	Vulns   []Vulnerability `json:"vulns,omitempty"`
 }
 
 // Vulnerability the protecode vulnerability information
 type Vulnerability struct {
-	Exact  bool     `json:"exact,omitempty"`
-	Vuln   Vuln     `json:"vuln,omitempty"`
-	Triage []Triage `json:"triage,omitempty"`
+	Exact      bool               `json:"exact,omitempty"`
+	Vuln       Vuln               `json:"vuln,omitempty"`
+	Triage     []Triage           `json:"triage,omitempty"`
+	Assessment *format.Assessment `json:"-"`
 }
 
 // Vuln holds the information about the vulnerability
@@ -277,7 +282,7 @@ func isExcluded(vulnerability Vulnerability, excludeCVEs string) bool {
 }
 
 func isTriaged(vulnerability Vulnerability) bool {
-	return len(vulnerability.Triage) > 0
+	return len(vulnerability.Triage) > 0 || vulnerability.Assessment != nil
 }
 
 func isSevereCVSS3(vulnerability Vulnerability) bool {
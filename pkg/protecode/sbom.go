@@ -0,0 +1,85 @@
+package protecode
+
+import (
+	"strings"
+	"time"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/uuid"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// SBOMFileName is the name of the CycloneDX SBOM of the components detected by Protecode
+const SBOMFileName = "bom-protecode.xml"
+
+// ToBOM creates a CycloneDX SBOM of the components Protecode detected in the scanned binary or image, which is described as component of the SBOM.
+// The components are identified by the same generic package URL as used for assessments and VEX statements, since Protecode does not provide the package type.
+func (r Result) ToBOM(name, version string, componentType cdx.ComponentType) *cdx.BOM {
+	bom := cdx.NewBOM()
+	bom.SerialNumber = "urn:uuid:" + uuid.New().String()
+	bom.Metadata = &cdx.Metadata{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Tools:     &[]cdx.Tool{{Vendor: "SAP", Name: "Piper Protecode scan"}},
+		Component: &cdx.Component{BOMRef: name, Type: componentType, Name: name, Version: version},
+	}
+
+	components := []cdx.Component{}
+	refs := []string{}
+	for _, component := range r.Components {
+		purl := component.ToPackageUrl().ToString()
+		if piperutils.ContainsString(refs, purl) {
+			continue
+		}
+		refs = append(refs, purl)
+		components = append(components, cdx.Component{
+			BOMRef:     purl,
+			Type:       cdx.ComponentTypeLibrary,
+			Publisher:  component.Vendor,
+			Name:       component.Lib,
+			Version:    component.Version,
+			PackageURL: purl,
+		})
+	}
+	bom.Components = &components
+
+	dependencies := []cdx.Dependency{{Ref: name, Dependencies: &[]cdx.Dependency{}}}
+	for _, ref := range refs {
+		*dependencies[0].Dependencies = append(*dependencies[0].Dependencies, cdx.Dependency{Ref: ref})
+	}
+	bom.Dependencies = &dependencies
+	return bom
+}
+
+// TriageVEXStatements returns the VEX statements for the vulnerabilities triaged within Protecode.
+// Protecode triages are free text without a structured reason, thus they are reported as in_triage and the triage descriptions are kept as detail of the analysis.
+// Vulnerabilities with an assessment are skipped since their VEX statements are created by ApplyAssessments.
+func TriageVEXStatements(result Result) []format.VEXStatement {
+	statements := []format.VEXStatement{}
+	for _, component := range result.Components {
+		purl := component.ToPackageUrl().ToString()
+		for _, vulnerability := range component.Vulns {
+			if len(vulnerability.Triage) == 0 || vulnerability.Assessment != nil {
+				continue
+			}
+			descriptions := []string{}
+			for _, triage := range vulnerability.Triage {
+				if len(triage.Description) > 0 {
+					descriptions = append(descriptions, triage.Description)
+				}
+			}
+			statements = append(statements, format.VEXStatement{
+				Vulnerability: vulnerability.Vuln.Cve,
+				Purl:          purl,
+				Assessment: format.Assessment{
+					Vulnerability: vulnerability.Vuln.Cve,
+					Status:        format.InProcess,
+					Purls:         []format.Purl{{Purl: purl}},
+				},
+				Detail: strings.Join(descriptions, "\n"),
+			})
+		}
+	}
+	return statements
+}
@@ -0,0 +1,61 @@
+//go:build unit
+// +build unit
+
+package protecode
+
+import (
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+)
+
+func TestToBOM(t *testing.T) {
+	// init
+	data := Result{Components: []Component{
+		{Lib: "log4j", Version: "2.14.0", Vendor: "apache"},
+		{Lib: "zlib", Version: "1.2.11"},
+		{Lib: "log4j", Version: "2.14.0", Vendor: "apache"},
+	}}
+	// test
+	bom := data.ToBOM("my-image:1.0", "1.0", cdx.ComponentTypeContainer)
+	// assert
+	assert.Equal(t, &cdx.Component{BOMRef: "my-image:1.0", Type: cdx.ComponentTypeContainer, Name: "my-image:1.0", Version: "1.0"}, bom.Metadata.Component)
+	assert.Equal(t, []cdx.Component{
+		{BOMRef: "pkg:generic/log4j@2.14.0", Type: cdx.ComponentTypeLibrary, Publisher: "apache", Name: "log4j", Version: "2.14.0", PackageURL: "pkg:generic/log4j@2.14.0"},
+		{BOMRef: "pkg:generic/zlib@1.2.11", Type: cdx.ComponentTypeLibrary, Name: "zlib", Version: "1.2.11", PackageURL: "pkg:generic/zlib@1.2.11"},
+	}, *bom.Components)
+	assert.Equal(t, []cdx.Dependency{{Ref: "my-image:1.0", Dependencies: &[]cdx.Dependency{{Ref: "pkg:generic/log4j@2.14.0"}, {Ref: "pkg:generic/zlib@1.2.11"}}}}, *bom.Dependencies)
+}
+
+func TestTriageVEXStatements(t *testing.T) {
+	triaged := Vulnerability{Vuln: Vuln{Cve: "Cve1"}, Triage: []Triage{{Description: "Vulnerable code is not reachable"}, {Description: "confirmed"}}}
+	assessed := Vulnerability{Vuln: Vuln{Cve: "Cve2"}, Triage: []Triage{{Description: "not used"}}, Assessment: &format.Assessment{}}
+	open := Vulnerability{Vuln: Vuln{Cve: "Cve3"}}
+	unknown := Vulnerability{Vuln: Vuln{Cve: "Cve4"}, Triage: []Triage{{Description: "fix not included in release yet"}}}
+	// init
+	data := Result{Components: []Component{{Lib: "log4j", Version: "2.14.0", Vulns: []Vulnerability{triaged, assessed, open, unknown}}}}
+	// test
+	statements := TriageVEXStatements(data)
+	// assert
+	assert.Equal(t, []format.VEXStatement{{
+		Vulnerability: "Cve1",
+		Purl:          "pkg:generic/log4j@2.14.0",
+		Assessment:    format.Assessment{Vulnerability: "Cve1", Status: format.InProcess, Purls: []format.Purl{{Purl: "pkg:generic/log4j@2.14.0"}}},
+		Detail:        "Vulnerable code is not reachable\nconfirmed",
+	}, {
+		Vulnerability: "Cve4",
+		Purl:          "pkg:generic/log4j@2.14.0",
+		Assessment:    format.Assessment{Vulnerability: "Cve4", Status: format.InProcess, Purls: []format.Purl{{Purl: "pkg:generic/log4j@2.14.0"}}},
+		Detail:        "fix not included in release yet",
+	}}, statements)
+	assert.Equal(t, &cdx.VulnerabilityAnalysis{State: cdx.IASInTriage}, statements[1].Assessment.ToVulnerabilityAnalysis())
+
+	vex, err := format.CreateVEX("Protecode", statements)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(vex), `"state": "not_affected"`)
+	assert.Contains(t, string(vex), `"state": "in_triage"`)
+	assert.Contains(t, string(vex), `"detail": "Vulnerable code is not reachable\nconfirmed"`)
+}
@@ -0,0 +1,192 @@
+package provenance
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// PayloadType is the DSSE payload type of in-toto statements
+const PayloadType = "application/vnd.in-toto+json"
+
+// Envelope is a Dead Simple Signing Envelope (DSSE), see https://github.com/secure-systems-lab/dsse
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature is a signature of a DSSE envelope
+type Signature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// Signer signs DSSE envelopes with a private key
+type Signer struct {
+	key   crypto.Signer
+	keyID string
+}
+
+// PAE returns the pre-authentication encoding of the payload which is signed
+func PAE(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// NewSigner creates a signer from an unencrypted private key in PEM format, ECDSA, RSA and Ed25519 keys are supported
+func NewSigner(keyPEM []byte, keyID string) (*Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("no PEM encoded private key found")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported private key type '%v', the key must not be encrypted", block.Type)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse private key")
+	}
+
+	switch signer := key.(type) {
+	case *ecdsa.PrivateKey, *rsa.PrivateKey, ed25519.PrivateKey:
+		return &Signer{key: signer.(crypto.Signer), keyID: keyID}, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key %T", key)
+	}
+}
+
+// PublicKey returns the public key matching the private key of the signer
+func (s *Signer) PublicKey() crypto.PublicKey {
+	return s.key.Public()
+}
+
+// SignBytes signs the SHA256 digest of the message, Ed25519 keys sign the message itself
+func (s *Signer) SignBytes(message []byte) ([]byte, error) {
+	if _, ok := s.key.(ed25519.PrivateKey); ok {
+		return s.key.Sign(rand.Reader, message, crypto.Hash(0))
+	}
+	digest := sha256.Sum256(message)
+	return s.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+// Sign creates a DSSE envelope containing the payload and its signature
+func (s *Signer) Sign(payloadType string, payload []byte) (*Envelope, error) {
+	sig, err := s.SignBytes(PAE(payloadType, payload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign payload")
+	}
+
+	return &Envelope{
+		PayloadType: payloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []Signature{{KeyID: s.keyID, Sig: base64.StdEncoding.EncodeToString(sig)}},
+	}, nil
+}
+
+// SignStatement signs the in-toto statement
+func (s *Signer) SignStatement(statement Statement) (*Envelope, error) {
+	// ignore JSON errors since structure is in our hands
+	payload, _ := json.Marshal(statement)
+	return s.Sign(PayloadType, payload)
+}
+
+// ParsePublicKey parses a public key in PEM format
+func ParsePublicKey(keyPEM []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("no PEM encoded public key found")
+	}
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		return key, errors.Wrap(err, "failed to parse public key")
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		return key, errors.Wrap(err, "failed to parse public key")
+	default:
+		return nil, fmt.Errorf("unsupported public key type '%v'", block.Type)
+	}
+}
+
+// DecodePayload returns the decoded payload of the envelope
+func (e *Envelope) DecodePayload() ([]byte, error) {
+	payload, err := base64.StdEncoding.DecodeString(e.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode payload")
+	}
+	return payload, nil
+}
+
+// Verify checks that the envelope contains at least one valid signature created with the private key matching the public key
+func (e *Envelope) Verify(publicKey crypto.PublicKey) error {
+	payload, err := e.DecodePayload()
+	if err != nil {
+		return err
+	}
+	message := PAE(e.PayloadType, payload)
+
+	for _, signature := range e.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(signature.Sig)
+		if err != nil {
+			continue
+		}
+		valid, err := VerifyBytes(publicKey, message, sig)
+		if err != nil {
+			return err
+		}
+		if valid {
+			return nil
+		}
+	}
+	return errors.New("no valid signature found")
+}
+
+// VerifyBytes checks the signature of the message created by SignBytes
+func VerifyBytes(publicKey crypto.PublicKey, message, sig []byte) (bool, error) {
+	digest := sha256.Sum256(message)
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(key, digest[:], sig), nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) == nil, nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, message, sig), nil
+	default:
+		return false, fmt.Errorf("unsupported public key %T", publicKey)
+	}
+}
+
+// Statement returns the in-toto statement contained in the envelope
+func (e *Envelope) Statement() (Statement, error) {
+	statement := Statement{}
+	if e.PayloadType != PayloadType {
+		return statement, fmt.Errorf("unexpected payload type '%v'", e.PayloadType)
+	}
+	payload, err := e.DecodePayload()
+	if err != nil {
+		return statement, err
+	}
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return statement, errors.Wrap(err, "failed to parse in-toto statement")
+	}
+	return statement, nil
+}
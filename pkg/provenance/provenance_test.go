@@ -0,0 +1,218 @@
+//go:build unit
+// +build unit
+
+package provenance
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+func testKeyPEM(t *testing.T, key crypto.Signer) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func testStatement() Statement {
+	return NewStatement([]Subject{{Name: "app.jar", Digest: map[string]string{"sha256": "abc"}}}, BuildInfo{
+		BuilderID:          "https://jenkins.example.com/job/app/",
+		InvocationID:       "https://jenkins.example.com/job/app/42/",
+		RepositoryURL:      "https://github.com/org/app",
+		Branch:             "main",
+		Commit:             "0123456789abcdef",
+		StartedOn:          time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC),
+		ExternalParameters: map[string]interface{}{"buildTool": "maven"},
+	})
+}
+
+func TestNewStatement(t *testing.T) {
+	t.Parallel()
+
+	statement := testStatement()
+
+	statementJSON, err := json.Marshal(statement)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"_type": "https://in-toto.io/Statement/v1",
+		"subject": [{"name": "app.jar", "digest": {"sha256": "abc"}}],
+		"predicateType": "https://slsa.dev/provenance/v1",
+		"predicate": {
+			"buildDefinition": {
+				"buildType": "https://sap.github.io/jenkins-library/provenance/v1",
+				"externalParameters": {"buildTool": "maven"},
+				"resolvedDependencies": [{"uri": "git+https://github.com/org/app@refs/heads/main", "digest": {"gitCommit": "0123456789abcdef"}}]
+			},
+			"runDetails": {
+				"builder": {"id": "https://jenkins.example.com/job/app/"},
+				"metadata": {"invocationId": "https://jenkins.example.com/job/app/42/", "startedOn": "2023-05-01T10:00:00Z"}
+			}
+		}
+	}`, string(statementJSON))
+}
+
+func TestSubjects(t *testing.T) {
+	t.Parallel()
+
+	t.Run("file", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("target/app.jar", []byte("content"))
+		subject, err := FileSubject("app.jar", "target/app.jar", utils)
+		assert.NoError(t, err)
+		assert.Equal(t, Subject{Name: "app.jar", Digest: map[string]string{"sha256": "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73"}}, subject)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := FileSubject("app.jar", "target/app.jar", &mock.FilesMock{})
+		assert.Contains(t, fmt.Sprint(err), "failed to read artifact 'target/app.jar'")
+	})
+
+	t.Run("image", func(t *testing.T) {
+		subject, err := ImageSubject("registry.example.com/app", "sha256:abc")
+		assert.NoError(t, err)
+		assert.Equal(t, Subject{Name: "registry.example.com/app", Digest: map[string]string{"sha256": "abc"}}, subject)
+	})
+
+	t.Run("invalid image digest", func(t *testing.T) {
+		_, err := ImageSubject("registry.example.com/app", "abc")
+		assert.EqualError(t, err, "invalid digest 'abc' of image 'registry.example.com/app'")
+	})
+}
+
+func TestSigner(t *testing.T) {
+	t.Parallel()
+
+	ecdsaKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, ed25519Key, _ := ed25519.GenerateKey(rand.Reader)
+
+	for _, key := range []crypto.Signer{ecdsaKey, rsaKey, ed25519Key} {
+		key := key
+		t.Run(fmt.Sprintf("%T", key), func(t *testing.T) {
+			signer, err := NewSigner(testKeyPEM(t, key), "my-key")
+			require.NoError(t, err)
+
+			envelope, err := signer.SignStatement(testStatement())
+			require.NoError(t, err)
+			assert.Equal(t, PayloadType, envelope.PayloadType)
+			assert.Equal(t, "my-key", envelope.Signatures[0].KeyID)
+			assert.NoError(t, envelope.Verify(key.Public()))
+			statement, err := envelope.Statement()
+			assert.NoError(t, err)
+			assert.Equal(t, "app.jar", statement.Subject[0].Name)
+
+			tampered := *envelope
+			tampered.PayloadType = "application/json"
+			assert.EqualError(t, tampered.Verify(key.Public()), "no valid signature found")
+		})
+	}
+
+	t.Run("public key", func(t *testing.T) {
+		signer, err := NewSigner(testKeyPEM(t, ecdsaKey), "")
+		require.NoError(t, err)
+		der, _ := x509.MarshalPKIXPublicKey(signer.PublicKey())
+		publicKey, err := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+		assert.NoError(t, err)
+		envelope, _ := signer.Sign(PayloadType, []byte("{}"))
+		assert.NoError(t, envelope.Verify(publicKey))
+
+		otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.EqualError(t, envelope.Verify(otherKey.Public()), "no valid signature found")
+	})
+
+	t.Run("EC private key", func(t *testing.T) {
+		der, _ := x509.MarshalECPrivateKey(ecdsaKey)
+		_, err := NewSigner(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), "")
+		assert.NoError(t, err)
+	})
+
+	t.Run("encrypted key", func(t *testing.T) {
+		_, err := NewSigner(pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: []byte("key")}), "")
+		assert.EqualError(t, err, "unsupported private key type 'ENCRYPTED PRIVATE KEY', the key must not be encrypted")
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		_, err := NewSigner([]byte("no key"), "")
+		assert.EqualError(t, err, "no PEM encoded private key found")
+	})
+
+	t.Run("PAE", func(t *testing.T) {
+		assert.Equal(t, "DSSEv1 29 http://example.com/HelloWorld 11 hello world", string(PAE("http://example.com/HelloWorld", []byte("hello world"))))
+	})
+}
+
+func TestAttachToImage(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	repository, err := name.NewRepository(host + "/app")
+	require.NoError(t, err)
+	image, err := random.Image(1024, 1)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(repository.Tag("1.0.0"), image))
+	digest, _ := image.Digest()
+	imageRef := fmt.Sprintf("%v@%v", repository, digest)
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	signer, err := NewSigner(testKeyPEM(t, key), "")
+	require.NoError(t, err)
+	envelope, err := signer.SignStatement(testStatement())
+	require.NoError(t, err)
+
+	t.Run("no referrers", func(t *testing.T) {
+		envelopes, err := Referrers(imageRef)
+		assert.NoError(t, err)
+		assert.Empty(t, envelopes)
+	})
+
+	t.Run("attach", func(t *testing.T) {
+		referrer, err := AttachToImage(imageRef, envelope)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(referrer, "sha256:"))
+
+		// attaching the same envelope again does not create a duplicate
+		_, err = AttachToImage(imageRef, envelope)
+		require.NoError(t, err)
+
+		envelopes, err := Referrers(imageRef)
+		require.NoError(t, err)
+		require.Len(t, envelopes, 1)
+		assert.NoError(t, envelopes[0].Verify(key.Public()))
+
+		manifest, err := remote.Get(repository.Digest(referrer))
+		require.NoError(t, err)
+		rawManifest, _ := image.RawManifest()
+		assert.Contains(t, string(manifest.Manifest), fmt.Sprintf(`"subject":{"mediaType":"application/vnd.docker.distribution.manifest.v2+json","size":%v,"digest":"%v"}`, len(rawManifest), digest))
+	})
+
+	t.Run("reference by tag", func(t *testing.T) {
+		_, err := AttachToImage(repository.Tag("1.0.0").String(), envelope)
+		assert.Contains(t, fmt.Sprint(err), "a reference by digest is required")
+	})
+}
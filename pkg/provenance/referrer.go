@@ -0,0 +1,238 @@
+package provenance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	// EnvelopeMediaType is the media type of DSSE envelopes stored in a container registry
+	EnvelopeMediaType types.MediaType = "application/vnd.dsse.envelope.v1+json"
+	// emptyMediaType is the media type of the empty config of OCI artifacts
+	emptyMediaType types.MediaType = "application/vnd.oci.empty.v1+json"
+	// predicateTypeAnnotation is used by cosign and others to filter attestations by predicate type
+	predicateTypeAnnotation = "dev.sigstore.cosign/predicateType"
+)
+
+// descriptor extends the descriptor of go-containerregistry by the artifact type introduced with OCI image spec 1.1
+type descriptor struct {
+	v1.Descriptor
+	ArtifactType string `json:"artifactType,omitempty"`
+}
+
+type referrerManifest struct {
+	SchemaVersion int64             `json:"schemaVersion"`
+	MediaType     types.MediaType   `json:"mediaType"`
+	ArtifactType  string            `json:"artifactType"`
+	Config        v1.Descriptor     `json:"config"`
+	Layers        []v1.Descriptor   `json:"layers"`
+	Subject       *v1.Descriptor    `json:"subject"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+type referrersIndex struct {
+	SchemaVersion int64           `json:"schemaVersion"`
+	MediaType     types.MediaType `json:"mediaType"`
+	Manifests     []descriptor    `json:"manifests"`
+}
+
+// rawManifest allows to push manifests which are not supported by go-containerregistry
+type rawManifest struct {
+	manifest  []byte
+	mediaType types.MediaType
+}
+
+func (r rawManifest) RawManifest() ([]byte, error) {
+	return r.manifest, nil
+}
+
+func (r rawManifest) MediaType() (types.MediaType, error) {
+	return r.mediaType, nil
+}
+
+// AttachToImage pushes the envelope as OCI referrer of the image, the image has to be referenced by digest.
+// In addition to the subject of the manifest, the referrers tag schema (tag sha256-<hash>) is maintained for registries not supporting the referrers API.
+// The digest of the referrer manifest is returned.
+func AttachToImage(image string, envelope *Envelope, options ...remote.Option) (string, error) {
+	digest, err := name.NewDigest(image)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid image reference '%v', a reference by digest is required", image)
+	}
+	subject, err := remote.Head(digest, options...)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get image '%v'", image)
+	}
+
+	statement, err := envelope.Statement()
+	if err != nil {
+		return "", err
+	}
+	// ignore JSON errors since structure is in our hands
+	envelopeJSON, _ := json.Marshal(envelope)
+	config := static.NewLayer([]byte("{}"), emptyMediaType)
+	layer := static.NewLayer(envelopeJSON, EnvelopeMediaType)
+	for _, blob := range []v1.Layer{config, layer} {
+		if err := remote.WriteLayer(digest.Repository, blob, options...); err != nil {
+			return "", errors.Wrapf(err, "failed to upload attestation to '%v'", digest.Repository)
+		}
+	}
+	configDescriptor, err := layerDescriptor(config, emptyMediaType, nil)
+	if err != nil {
+		return "", err
+	}
+	annotations := map[string]string{predicateTypeAnnotation: statement.PredicateType}
+	envelopeDescriptor, err := layerDescriptor(layer, EnvelopeMediaType, annotations)
+	if err != nil {
+		return "", err
+	}
+
+	manifest, _ := json.Marshal(referrerManifest{
+		SchemaVersion: 2,
+		MediaType:     types.OCIManifestSchema1,
+		ArtifactType:  string(EnvelopeMediaType),
+		Config:        configDescriptor,
+		Layers:        []v1.Descriptor{envelopeDescriptor},
+		Subject:       &v1.Descriptor{MediaType: subject.MediaType, Size: subject.Size, Digest: subject.Digest},
+		Annotations:   annotations,
+	})
+	manifestDigest, manifestSize, err := v1.SHA256(bytes.NewReader(manifest))
+	if err != nil {
+		return "", err
+	}
+	if err := remote.Put(digest.Repository.Digest(manifestDigest.String()), rawManifest{manifest: manifest, mediaType: types.OCIManifestSchema1}, options...); err != nil {
+		return "", errors.Wrapf(err, "failed to push attestation manifest to '%v'", digest.Repository)
+	}
+
+	referrer := descriptor{
+		Descriptor:   v1.Descriptor{MediaType: types.OCIManifestSchema1, Size: manifestSize, Digest: manifestDigest, Annotations: annotations},
+		ArtifactType: string(EnvelopeMediaType),
+	}
+	if err := addToReferrersTag(digest, referrer, options...); err != nil {
+		return "", err
+	}
+	return manifestDigest.String(), nil
+}
+
+func layerDescriptor(layer v1.Layer, mediaType types.MediaType, annotations map[string]string) (v1.Descriptor, error) {
+	digest, err := layer.Digest()
+	if err != nil {
+		return v1.Descriptor{}, err
+	}
+	size, err := layer.Size()
+	if err != nil {
+		return v1.Descriptor{}, err
+	}
+	return v1.Descriptor{MediaType: mediaType, Size: size, Digest: digest, Annotations: annotations}, nil
+}
+
+// addToReferrersTag adds the referrer to the index tagged with the digest of the subject, see https://github.com/opencontainers/distribution-spec/blob/v1.1.0/spec.md#referrers-tag-schema
+func addToReferrersTag(subject name.Digest, referrer descriptor, options ...remote.Option) error {
+	tag := subject.Repository.Tag(strings.Replace(subject.DigestStr(), ":", "-", 1))
+
+	index := referrersIndex{SchemaVersion: 2, MediaType: types.OCIImageIndex, Manifests: []descriptor{}}
+	existing, err := remote.Get(tag, options...)
+	if err != nil && !isNotFound(err) {
+		return errors.Wrapf(err, "failed to get referrers index '%v'", tag)
+	}
+	if err == nil {
+		if existing.MediaType != types.OCIImageIndex {
+			return fmt.Errorf("tag '%v' is not a referrers index", tag)
+		}
+		if err := json.Unmarshal(existing.Manifest, &index); err != nil {
+			return errors.Wrapf(err, "failed to parse referrers index '%v'", tag)
+		}
+	}
+	for _, manifest := range index.Manifests {
+		if manifest.Digest == referrer.Digest {
+			return nil
+		}
+	}
+	index.Manifests = append(index.Manifests, referrer)
+
+	// ignore JSON errors since structure is in our hands
+	indexJSON, _ := json.Marshal(index)
+	if err := remote.Put(tag, rawManifest{manifest: indexJSON, mediaType: types.OCIImageIndex}, options...); err != nil {
+		return errors.Wrapf(err, "failed to update referrers index '%v'", tag)
+	}
+	return nil
+}
+
+// Referrers returns the DSSE envelopes attached to the image using the referrers tag schema, the image has to be referenced by digest
+func Referrers(image string, options ...remote.Option) ([]Envelope, error) {
+	digest, err := name.NewDigest(image)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid image reference '%v', a reference by digest is required", image)
+	}
+	tag := digest.Repository.Tag(strings.Replace(digest.DigestStr(), ":", "-", 1))
+
+	envelopes := []Envelope{}
+	existing, err := remote.Get(tag, options...)
+	if err != nil {
+		if isNotFound(err) {
+			return envelopes, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get referrers of image '%v'", image)
+	}
+	index := referrersIndex{}
+	if err := json.Unmarshal(existing.Manifest, &index); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse referrers index '%v'", tag)
+	}
+
+	for _, referrer := range index.Manifests {
+		if referrer.ArtifactType != string(EnvelopeMediaType) {
+			continue
+		}
+		descriptor, err := remote.Get(digest.Repository.Digest(referrer.Digest.String()), options...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get referrer '%v'", referrer.Digest)
+		}
+		manifest := referrerManifest{}
+		if err := json.Unmarshal(descriptor.Manifest, &manifest); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse referrer '%v'", referrer.Digest)
+		}
+		for _, layer := range manifest.Layers {
+			if layer.MediaType != EnvelopeMediaType {
+				continue
+			}
+			envelope, err := readEnvelope(digest.Repository.Digest(layer.Digest.String()), options...)
+			if err != nil {
+				return nil, err
+			}
+			envelopes = append(envelopes, envelope)
+		}
+	}
+	return envelopes, nil
+}
+
+func readEnvelope(ref name.Digest, options ...remote.Option) (Envelope, error) {
+	envelope := Envelope{}
+	layer, err := remote.Layer(ref, options...)
+	if err != nil {
+		return envelope, errors.Wrapf(err, "failed to get attestation '%v'", ref)
+	}
+	content, err := layer.Compressed()
+	if err != nil {
+		return envelope, errors.Wrapf(err, "failed to download attestation '%v'", ref)
+	}
+	defer content.Close()
+	if err := json.NewDecoder(content).Decode(&envelope); err != nil {
+		return envelope, errors.Wrapf(err, "failed to parse attestation '%v'", ref)
+	}
+	return envelope, nil
+}
+
+func isNotFound(err error) bool {
+	var transportErr *transport.Error
+	return errors.As(err, &transportErr) && transportErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,171 @@
+package provenance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+const (
+	// StatementType is the type of an in-toto statement in version 1
+	StatementType = "https://in-toto.io/Statement/v1"
+	// PredicateTypeSLSAProvenance is the predicate type of SLSA provenance in version 1
+	PredicateTypeSLSAProvenance = "https://slsa.dev/provenance/v1"
+	// BuildType describes the build performed by a project "Piper" pipeline
+	BuildType = "https://sap.github.io/jenkins-library/provenance/v1"
+)
+
+// Statement is an in-toto statement with SLSA provenance as predicate, see https://slsa.dev/spec/v1.0/provenance
+type Statement struct {
+	Type          string     `json:"_type"`
+	Subject       []Subject  `json:"subject"`
+	PredicateType string     `json:"predicateType"`
+	Predicate     Provenance `json:"predicate"`
+}
+
+// Subject is an artifact described by a statement
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Provenance is the SLSA provenance predicate
+type Provenance struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+// BuildDefinition describes the inputs of the build
+type BuildDefinition struct {
+	BuildType            string                 `json:"buildType"`
+	ExternalParameters   map[string]interface{} `json:"externalParameters"`
+	InternalParameters   map[string]interface{} `json:"internalParameters,omitempty"`
+	ResolvedDependencies []ResourceDescriptor   `json:"resolvedDependencies,omitempty"`
+}
+
+// ResourceDescriptor describes a resource like the source repository used by the build
+type ResourceDescriptor struct {
+	URI    string            `json:"uri,omitempty"`
+	Digest map[string]string `json:"digest,omitempty"`
+	Name   string            `json:"name,omitempty"`
+}
+
+// RunDetails describes the build platform and the build invocation
+type RunDetails struct {
+	Builder  Builder       `json:"builder"`
+	Metadata BuildMetadata `json:"metadata"`
+}
+
+// Builder identifies the build platform
+type Builder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version,omitempty"`
+}
+
+// BuildMetadata describes the build invocation
+type BuildMetadata struct {
+	InvocationID string     `json:"invocationId,omitempty"`
+	StartedOn    *time.Time `json:"startedOn,omitempty"`
+	FinishedOn   *time.Time `json:"finishedOn,omitempty"`
+}
+
+// BuildInfo contains the information about the build which is recorded in the provenance
+type BuildInfo struct {
+	BuilderID          string
+	BuilderVersion     map[string]string
+	InvocationID       string
+	RepositoryURL      string
+	Commit             string
+	Branch             string
+	StartedOn          time.Time
+	FinishedOn         time.Time
+	ExternalParameters map[string]interface{}
+	InternalParameters map[string]interface{}
+}
+
+// NewStatement creates an in-toto statement containing the SLSA provenance of the subjects
+func NewStatement(subjects []Subject, info BuildInfo) Statement {
+	externalParameters := info.ExternalParameters
+	if externalParameters == nil {
+		externalParameters = map[string]interface{}{}
+	}
+
+	provenance := Provenance{
+		BuildDefinition: BuildDefinition{
+			BuildType:          BuildType,
+			ExternalParameters: externalParameters,
+			InternalParameters: info.InternalParameters,
+		},
+		RunDetails: RunDetails{
+			Builder: Builder{
+				ID:      info.BuilderID,
+				Version: info.BuilderVersion,
+			},
+			Metadata: BuildMetadata{
+				InvocationID: info.InvocationID,
+			},
+		},
+	}
+	if len(info.RepositoryURL) > 0 {
+		source := ResourceDescriptor{URI: sourceURI(info.RepositoryURL, info.Branch)}
+		if len(info.Commit) > 0 {
+			source.Digest = map[string]string{"gitCommit": info.Commit}
+		}
+		provenance.BuildDefinition.ResolvedDependencies = []ResourceDescriptor{source}
+	}
+	if !info.StartedOn.IsZero() {
+		startedOn := info.StartedOn.UTC()
+		provenance.RunDetails.Metadata.StartedOn = &startedOn
+	}
+	if !info.FinishedOn.IsZero() {
+		finishedOn := info.FinishedOn.UTC()
+		provenance.RunDetails.Metadata.FinishedOn = &finishedOn
+	}
+
+	return Statement{
+		Type:          StatementType,
+		Subject:       subjects,
+		PredicateType: PredicateTypeSLSAProvenance,
+		Predicate:     provenance,
+	}
+}
+
+// sourceURI returns the URI of the source repository in SPDX download location format, e.g. git+https://github.com/org/repo@refs/heads/main
+func sourceURI(repositoryURL, branch string) string {
+	uri := repositoryURL
+	if !strings.HasPrefix(uri, "git+") {
+		uri = "git+" + uri
+	}
+	if len(branch) > 0 {
+		if !strings.HasPrefix(branch, "refs/") {
+			branch = "refs/heads/" + branch
+		}
+		uri = fmt.Sprintf("%v@%v", uri, branch)
+	}
+	return uri
+}
+
+// FileSubject creates the subject of a file using its SHA256 digest
+func FileSubject(name, path string, utils piperutils.FileUtils) (Subject, error) {
+	content, err := utils.FileRead(path)
+	if err != nil {
+		return Subject{}, errors.Wrapf(err, "failed to read artifact '%v'", path)
+	}
+	digest := sha256.Sum256(content)
+	return Subject{Name: name, Digest: map[string]string{"sha256": hex.EncodeToString(digest[:])}}, nil
+}
+
+// ImageSubject creates the subject of a container image, the digest is expected in the format <algorithm>:<hash>
+func ImageSubject(name, digest string) (Subject, error) {
+	parts := strings.SplitN(digest, ":", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return Subject{}, fmt.Errorf("invalid digest '%v' of image '%v'", digest, name)
+	}
+	return Subject{Name: name, Digest: map[string]string{parts[0]: parts[1]}}, nil
+}
@@ -0,0 +1,348 @@
+package remediation
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/SAP/jenkins-library/pkg/osv"
+	"github.com/package-url/packageurl-go"
+	"github.com/pkg/errors"
+	"golang.org/x/mod/modfile"
+)
+
+// Change defines an upgrade applied to a build descriptor
+type Change struct {
+	File        string
+	Upgrade     Upgrade
+	FromVersion string
+	ToVersion   string
+}
+
+// Skipped defines an upgrade which has not been applied together with the reason
+type Skipped struct {
+	Upgrade Upgrade
+	Reason  string
+}
+
+// Result contains the updated build descriptors as well as the applied and skipped upgrades
+type Result struct {
+	Files   map[string][]byte
+	Changes []Change
+	Skipped []Skipped
+}
+
+// skipError marks an upgrade which must not be applied to a build descriptor, the reason is reported for the skipped upgrade
+type skipError struct {
+	reason string
+}
+
+func (e *skipError) Error() string {
+	return e.reason
+}
+
+// Options define how upgrades are applied
+type Options struct {
+	// AllowMajorUpgrades enables upgrades to a fixed version with a different major version.
+	// Major upgrades of Go modules are never applied since they require a different module path (e.g. /v2) and changes of the imports.
+	AllowMajorUpgrades bool
+}
+
+// Apply applies the upgrades to the direct dependencies declared in the given build descriptors (package.json, pom.xml and go.mod).
+// Build descriptors are provided as map of file path and content, only the changed ones are contained in the result.
+// Lock files like package-lock.json or go.sum are not updated.
+func Apply(descriptors map[string][]byte, upgrades []Upgrade, options Options) (*Result, error) {
+	result := &Result{Files: map[string][]byte{}}
+	applied := map[string]bool{}
+
+	paths := sortedKeys(descriptors)
+	for _, upgrade := range upgrades {
+		if upgrade.MajorUpgrade() && upgrade.PackageType == packageurl.TypeGolang {
+			result.Skipped = append(result.Skipped, Skipped{Upgrade: upgrade, Reason: "major version upgrade of Go module requires new module path"})
+			continue
+		}
+		if upgrade.MajorUpgrade() && !options.AllowMajorUpgrades {
+			result.Skipped = append(result.Skipped, Skipped{Upgrade: upgrade, Reason: "major version upgrade"})
+			continue
+		}
+		reason := "no outdated direct dependency declaration found"
+		for _, path := range paths {
+			if descriptorType(path) != upgrade.PackageType {
+				continue
+			}
+			content := descriptors[path]
+			if updated, ok := result.Files[path]; ok {
+				content = updated
+			}
+			updated, from, err := applyUpgrade(path, content, upgrade)
+			if skip, ok := err.(*skipError); ok {
+				reason = fmt.Sprintf("%v in '%v'", skip.reason, path)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if len(from) == 0 {
+				continue
+			}
+			result.Files[path] = updated
+			result.Changes = append(result.Changes, Change{File: path, Upgrade: upgrade, FromVersion: from, ToVersion: upgrade.FixedVersion})
+			applied[upgrade.key()] = true
+		}
+		if !applied[upgrade.key()] {
+			result.Skipped = append(result.Skipped, Skipped{Upgrade: upgrade, Reason: reason})
+		}
+	}
+	return result, nil
+}
+
+func descriptorType(path string) string {
+	switch {
+	case strings.HasSuffix(path, "package.json"):
+		return packageurl.TypeNPM
+	case strings.HasSuffix(path, "pom.xml"):
+		return packageurl.TypeMaven
+	case strings.HasSuffix(path, "go.mod"):
+		return packageurl.TypeGolang
+	}
+	return ""
+}
+
+func applyUpgrade(path string, content []byte, upgrade Upgrade) ([]byte, string, error) {
+	switch descriptorType(path) {
+	case packageurl.TypeNPM:
+		return applyNpmUpgrade(path, content, upgrade)
+	case packageurl.TypeMaven:
+		return applyMavenUpgrade(path, content, upgrade)
+	case packageurl.TypeGolang:
+		return applyGoUpgrade(path, content, upgrade)
+	}
+	return content, "", nil
+}
+
+var npmVersionSpec = regexp.MustCompile(`^(\^|~|>=|=)?v?(\d+(\.[0-9A-Za-z-]+)*)$`)
+
+// applyNpmUpgrade updates the version of a direct dependency while keeping the range operator, e.g. ^1.2.3 becomes ^1.2.4.
+// Version specifications which do not refer to a single version (e.g. git URLs or complex ranges) are not touched.
+func applyNpmUpgrade(path string, content []byte, upgrade Upgrade) ([]byte, string, error) {
+	descriptor := map[string]json.RawMessage{}
+	if err := json.Unmarshal(content, &descriptor); err != nil {
+		return nil, "", errors.Wrapf(err, "failed to parse '%v'", path)
+	}
+
+	for _, section := range []string{"dependencies", "devDependencies", "optionalDependencies"} {
+		dependencies := map[string]string{}
+		if raw, ok := descriptor[section]; !ok || json.Unmarshal(raw, &dependencies) != nil {
+			continue
+		}
+		spec, ok := dependencies[upgrade.Name]
+		if !ok {
+			continue
+		}
+		match := npmVersionSpec.FindStringSubmatch(spec)
+		if match == nil || osv.CompareVersions(match[2], upgrade.FixedVersion) >= 0 {
+			continue
+		}
+		declaration := regexp.MustCompile(fmt.Sprintf(`("%v"\s*:\s*")%v(")`, regexp.QuoteMeta(upgrade.Name), regexp.QuoteMeta(spec)))
+		newSpec := match[1] + strings.TrimPrefix(upgrade.FixedVersion, "v")
+		return declaration.ReplaceAll(content, []byte("${1}"+newSpec+"${2}")), spec, nil
+	}
+	return content, "", nil
+}
+
+var mavenProperty = regexp.MustCompile(`^\$\{([^}]+)\}$`)
+
+// mavenValue is the text of an element within a pom.xml together with its position
+type mavenValue struct {
+	text       string
+	start, end int64
+}
+
+// mavenDependency is a dependency declaration with the values of its direct child elements, values of e.g. exclusions are not contained
+type mavenDependency struct {
+	groupID, artifactID string
+	version             *mavenValue
+}
+
+type mavenEdit struct {
+	value mavenValue
+	text  string
+}
+
+// applyMavenUpgrade updates the version of a dependency declared in the dependencies or the dependency management of the project.
+// If the version is defined via a property, the property is updated unless it is used elsewhere, e.g. for other artifacts of the same group,
+// since those would be upgraded silently.
+func applyMavenUpgrade(path string, content []byte, upgrade Upgrade) ([]byte, string, error) {
+	parts := strings.SplitN(upgrade.Name, ":", 2)
+	if len(parts) != 2 {
+		return content, "", nil
+	}
+	dependencies, properties, err := parseMavenDescriptor(content)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to parse '%v'", path)
+	}
+
+	from := ""
+	edits := []mavenEdit{}
+	for _, dependency := range dependencies {
+		if dependency.groupID != parts[0] || dependency.artifactID != parts[1] || dependency.version == nil {
+			continue
+		}
+		version := *dependency.version
+		if property := mavenProperty.FindStringSubmatch(version.text); property != nil {
+			value, ok := properties[property[1]]
+			if !ok {
+				continue
+			}
+			if bytes.Count(content, []byte(version.text)) > countPropertyUsages(dependencies, parts[0], parts[1], version.text) {
+				return content, "", &skipError{reason: fmt.Sprintf("version property '%v' is used by other declarations", property[1])}
+			}
+			version = value
+		}
+		if osv.CompareVersions(version.text, upgrade.FixedVersion) >= 0 || containsEdit(edits, version) {
+			continue
+		}
+		from = version.text
+		edits = append(edits, mavenEdit{value: version, text: upgrade.FixedVersion})
+	}
+	return applyMavenEdits(content, edits), from, nil
+}
+
+// countPropertyUsages returns the number of declarations of the artifact whose version refers to the property
+func countPropertyUsages(dependencies []mavenDependency, groupID, artifactID, reference string) int {
+	count := 0
+	for _, dependency := range dependencies {
+		if dependency.groupID == groupID && dependency.artifactID == artifactID && dependency.version != nil && dependency.version.text == reference {
+			count++
+		}
+	}
+	return count
+}
+
+// parseMavenDescriptor returns the dependency declarations of the project and its dependency management as well as the properties of a pom.xml.
+// Dependencies declared elsewhere, e.g. of plugins or profiles, are not contained.
+// The positions of the values are byte offsets within the content, which allows to update them without reformatting the file.
+func parseMavenDescriptor(content []byte) ([]mavenDependency, map[string]mavenValue, error) {
+	dependencies := []mavenDependency{}
+	properties := map[string]mavenValue{}
+
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	elements := []string{}
+	var dependency *mavenDependency
+	dependencyDepth := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			elements = append(elements, t.Name.Local)
+			if t.Name.Local == "dependency" && dependency == nil && isProjectDependency(elements) {
+				dependency = &mavenDependency{}
+				dependencyDepth = len(elements)
+			}
+		case xml.EndElement:
+			if dependency != nil && len(elements) == dependencyDepth {
+				dependencies = append(dependencies, *dependency)
+				dependency = nil
+			}
+			elements = elements[:len(elements)-1]
+		case xml.CharData:
+			value := trimmedValue(t, decoder.InputOffset())
+			switch {
+			case dependency != nil && len(elements) == dependencyDepth+1:
+				switch elements[len(elements)-1] {
+				case "groupId":
+					dependency.groupID = value.text
+				case "artifactId":
+					dependency.artifactID = value.text
+				case "version":
+					dependency.version = &value
+				}
+			case len(elements) == 3 && elements[0] == "project" && elements[1] == "properties":
+				properties[elements[2]] = value
+			}
+		}
+	}
+	return dependencies, properties, nil
+}
+
+// isProjectDependency returns true for the path of a dependency within project/dependencies or project/dependencyManagement/dependencies
+func isProjectDependency(elements []string) bool {
+	path := strings.Join(elements, "/")
+	return path == "project/dependencies/dependency" || path == "project/dependencyManagement/dependencies/dependency"
+}
+
+// trimmedValue returns the text of the character data without surrounding whitespace, end is the offset after the character data
+func trimmedValue(data xml.CharData, end int64) mavenValue {
+	text := string(data)
+	start := end - int64(len(text))
+	leading := len(text) - len(strings.TrimLeftFunc(text, unicode.IsSpace))
+	trailing := len(text) - len(strings.TrimRightFunc(text, unicode.IsSpace))
+	return mavenValue{text: strings.TrimSpace(text), start: start + int64(leading), end: end - int64(trailing)}
+}
+
+func containsEdit(edits []mavenEdit, value mavenValue) bool {
+	for _, edit := range edits {
+		if edit.value.start == value.start {
+			return true
+		}
+	}
+	return false
+}
+
+// applyMavenEdits replaces the values starting with the last one in order to keep the offsets of the other values valid
+func applyMavenEdits(content []byte, edits []mavenEdit) []byte {
+	sort.Slice(edits, func(i, j int) bool { return edits[i].value.start > edits[j].value.start })
+	updated := append([]byte{}, content...)
+	for _, edit := range edits {
+		updated = append(updated[:edit.value.start], append([]byte(edit.text), updated[edit.value.end:]...)...)
+	}
+	return updated
+}
+
+// applyGoUpgrade updates the version of a module which is required directly
+func applyGoUpgrade(path string, content []byte, upgrade Upgrade) ([]byte, string, error) {
+	file, err := modfile.Parse(path, content, nil)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to parse '%v'", path)
+	}
+	fixedVersion := "v" + strings.TrimPrefix(upgrade.FixedVersion, "v")
+	for _, require := range file.Require {
+		if require.Mod.Path != upgrade.Name || require.Indirect {
+			continue
+		}
+		if osv.CompareVersions(require.Mod.Version, fixedVersion) >= 0 {
+			return content, "", nil
+		}
+		from := require.Mod.Version
+		if err := file.AddRequire(upgrade.Name, fixedVersion); err != nil {
+			return nil, "", errors.Wrapf(err, "failed to update '%v' in '%v'", upgrade.Name, path)
+		}
+		updated, err := file.Format()
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "failed to format '%v'", path)
+		}
+		return updated, from, nil
+	}
+	return content, "", nil
+}
+
+func sortedKeys(descriptors map[string][]byte) []string {
+	keys := make([]string, 0, len(descriptors))
+	for key := range descriptors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,240 @@
+package remediation
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/blackduck"
+	"github.com/SAP/jenkins-library/pkg/osv"
+	"github.com/SAP/jenkins-library/pkg/whitesource"
+	"github.com/package-url/packageurl-go"
+)
+
+// Upgrade defines the upgrade of a dependency to a version which fixes known vulnerabilities
+type Upgrade struct {
+	// PackageType is the package URL type of the dependency, e.g. npm, maven or golang
+	PackageType string
+	// Name identifies the dependency within its ecosystem, e.g. @scope/name for npm, groupId:artifactId for Maven and the module path for Go
+	Name            string
+	Version         string
+	FixedVersion    string
+	Vulnerabilities []string
+}
+
+// MajorUpgrade returns true if the fixed version has a different major version than the current version
+func (u Upgrade) MajorUpgrade() bool {
+	return majorVersion(u.Version) != majorVersion(u.FixedVersion)
+}
+
+func (u Upgrade) key() string {
+	return fmt.Sprintf("%v:%v@%v", u.PackageType, u.Name, u.Version)
+}
+
+// Merge combines the upgrades of the same dependency version into one upgrade.
+// The highest fixed version is used and the addressed vulnerabilities are combined.
+func Merge(upgrades []Upgrade) []Upgrade {
+	merged := map[string]*Upgrade{}
+	keys := []string{}
+	for _, upgrade := range upgrades {
+		if len(upgrade.Name) == 0 || len(upgrade.FixedVersion) == 0 {
+			continue
+		}
+		existing, ok := merged[upgrade.key()]
+		if !ok {
+			u := upgrade
+			u.Vulnerabilities = append([]string{}, upgrade.Vulnerabilities...)
+			merged[upgrade.key()] = &u
+			keys = append(keys, upgrade.key())
+			continue
+		}
+		if osv.CompareVersions(upgrade.FixedVersion, existing.FixedVersion) > 0 {
+			existing.FixedVersion = upgrade.FixedVersion
+		}
+		existing.Vulnerabilities = append(existing.Vulnerabilities, upgrade.Vulnerabilities...)
+	}
+
+	sort.Strings(keys)
+	result := []Upgrade{}
+	for _, key := range keys {
+		upgrade := merged[key]
+		upgrade.Vulnerabilities = unique(upgrade.Vulnerabilities)
+		result = append(result, *upgrade)
+	}
+	return result
+}
+
+// FromBlackDuck creates the upgrades for the given vulnerabilities.
+// The remediating versions are provided per component version link as returned by the BlackDuck API.
+func FromBlackDuck(vulnerabilities []blackduck.Vulnerability, remediatingVersions map[string]*blackduck.RemediatingVersions) []Upgrade {
+	upgrades := []Upgrade{}
+	for _, vulnerability := range vulnerabilities {
+		fixedVersion := blackDuckFixedVersion(remediatingVersions[vulnerability.ComponentVersion])
+		if len(fixedVersion) == 0 {
+			continue
+		}
+		component := vulnerability.Component
+		if component == nil {
+			component = &blackduck.Component{Name: vulnerability.Name, Version: vulnerability.Version}
+		}
+		packageType, name := blackDuckCoordinates(component)
+		upgrades = append(upgrades, Upgrade{
+			PackageType:     packageType,
+			Name:            name,
+			Version:         vulnerability.Version,
+			FixedVersion:    fixedVersion,
+			Vulnerabilities: []string{blackDuckVulnerabilityID(vulnerability)},
+		})
+	}
+	return upgrades
+}
+
+func blackDuckFixedVersion(versions *blackduck.RemediatingVersions) string {
+	if versions == nil {
+		return ""
+	}
+	// prefer the closest version fixing the vulnerabilities of the current version
+	if versions.FixesPreviousVulnerabilities != nil && len(versions.FixesPreviousVulnerabilities.Name) > 0 {
+		return versions.FixesPreviousVulnerabilities.Name
+	}
+	if versions.NoVulnerabilities != nil {
+		return versions.NoVulnerabilities.Name
+	}
+	return ""
+}
+
+// blackDuckCoordinates determines package type and name from the component origin.
+// BlackDuck provides the external ID as groupId:artifactId:version for Maven, as name/version for npm and as module:version for Go.
+func blackDuckCoordinates(component *blackduck.Component) (string, string) {
+	if len(component.Origins) == 0 {
+		return packageurl.TypeGeneric, component.Name
+	}
+	externalID := component.Origins[0].ExternalID
+	switch strings.ToLower(component.Origins[0].ExternalNamespace) {
+	case "maven":
+		parts := strings.Split(externalID, ":")
+		if len(parts) >= 2 {
+			return packageurl.TypeMaven, parts[0] + ":" + parts[1]
+		}
+		return packageurl.TypeMaven, component.Name
+	case "npmjs", "node":
+		if i := strings.LastIndex(externalID, "/"); i > 0 {
+			return packageurl.TypeNPM, externalID[:i]
+		}
+		return packageurl.TypeNPM, component.Name
+	case "golang":
+		if i := strings.LastIndex(externalID, ":"); i > 0 {
+			return packageurl.TypeGolang, externalID[:i]
+		}
+		return packageurl.TypeGolang, component.Name
+	}
+	return strings.ToLower(component.Origins[0].ExternalNamespace), component.Name
+}
+
+func blackDuckVulnerabilityID(vulnerability blackduck.Vulnerability) string {
+	// the related vulnerability links the CVE of a BlackDuck advisory, e.g. https://my.blackduck.system/api/vulnerabilities/CVE-2021-44228
+	if !strings.HasPrefix(vulnerability.VulnerabilityName, "CVE-") && len(vulnerability.RelatedVulnerability) > 0 {
+		return path.Base(vulnerability.RelatedVulnerability)
+	}
+	return vulnerability.VulnerabilityName
+}
+
+// FromWhiteSource creates the upgrades for the given security vulnerability alerts based on their top fix
+func FromWhiteSource(alerts []whitesource.Alert) []Upgrade {
+	upgrades := []Upgrade{}
+	for _, alert := range alerts {
+		if alert.Type != "SECURITY_VULNERABILITY" {
+			continue
+		}
+		packageType, name := whiteSourceCoordinates(alert.Library)
+		fixedVersion := whiteSourceFixedVersion(alert.Vulnerability.TopFix, name)
+		if len(fixedVersion) == 0 {
+			continue
+		}
+		upgrades = append(upgrades, Upgrade{
+			PackageType:     packageType,
+			Name:            name,
+			Version:         alert.Library.Version,
+			FixedVersion:    fixedVersion,
+			Vulnerabilities: []string{alert.Vulnerability.Name},
+		})
+	}
+	return upgrades
+}
+
+func whiteSourceCoordinates(library whitesource.Library) (string, string) {
+	packageType := library.ToPackageUrl().Type
+	switch packageType {
+	case packageurl.TypeMaven:
+		return packageType, library.GroupID + ":" + library.ArtifactID
+	case packageurl.TypeNPM:
+		// npm libraries are reported with the archive name as artifact ID, e.g. lodash-4.17.11.tgz
+		return packageType, strings.TrimSuffix(library.ArtifactID, "-"+library.Version+".tgz")
+	case packageurl.TypeGolang:
+		if len(library.GroupID) > 0 && !strings.HasSuffix(library.GroupID, "/"+library.ArtifactID) {
+			return packageType, library.GroupID + "/" + library.ArtifactID
+		}
+		if len(library.GroupID) > 0 {
+			return packageType, library.GroupID
+		}
+	}
+	return packageType, library.ArtifactID
+}
+
+var versionPattern = regexp.MustCompile(`^v?\d+(\.[0-9A-Za-z-]+)*$`)
+
+// whiteSourceFixedVersion extracts the fixed version from the fix resolution of WhiteSource.
+// It is provided as text in different flavors, e.g. 'Upgrade to version 4.17.21', 'Upgrade to version lodash - 4.17.21,lodash-es - 4.17.21'
+// or 'Upgrade to version org.apache.logging.log4j:log4j-core:2.17.1'.
+func whiteSourceFixedVersion(fix whitesource.Fix, name string) string {
+	if len(fix.Type) > 0 && fix.Type != "UPGRADE_VERSION" {
+		return ""
+	}
+	resolution := strings.TrimSpace(fix.FixResolution)
+	if !strings.HasPrefix(strings.ToLower(resolution), "upgrade to version") {
+		return ""
+	}
+	resolution = strings.TrimSpace(resolution[len("upgrade to version"):])
+
+	for _, candidate := range strings.Split(resolution, ",") {
+		candidate = strings.TrimSpace(candidate)
+		candidateName := ""
+		if i := strings.LastIndex(candidate, " - "); i >= 0 {
+			candidateName, candidate = strings.TrimSpace(candidate[:i]), strings.TrimSpace(candidate[i+3:])
+		} else if i := strings.LastIndex(candidate, ":"); i >= 0 {
+			candidateName, candidate = candidate[:i], candidate[i+1:]
+		}
+		if !versionPattern.MatchString(candidate) {
+			continue
+		}
+		// fixes of other libraries are listed as well, e.g. the ones of a package which shares the vulnerable code
+		if len(candidateName) == 0 || candidateName == name || strings.HasSuffix(name, ":"+candidateName) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func majorVersion(version string) string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, ".-+"); i >= 0 {
+		return version[:i]
+	}
+	return version
+}
+
+func unique(values []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, value := range values {
+		if len(value) == 0 || seen[value] {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+	sort.Strings(result)
+	return result
+}
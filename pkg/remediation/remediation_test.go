@@ -0,0 +1,318 @@
+//go:build unit
+// +build unit
+
+package remediation
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/blackduck"
+	"github.com/SAP/jenkins-library/pkg/whitesource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromBlackDuck(t *testing.T) {
+	vulnerabilities := []blackduck.Vulnerability{
+		{
+			Name:             "lodash",
+			Version:          "4.17.11",
+			ComponentVersion: "https://my.blackduck.system/api/components/c1/versions/v1",
+			Component:        &blackduck.Component{Name: "lodash", Version: "4.17.11", Origins: []blackduck.ComponentOrigin{{ExternalNamespace: "npmjs", ExternalID: "lodash/4.17.11"}}},
+			VulnerabilityWithRemediation: blackduck.VulnerabilityWithRemediation{
+				VulnerabilityName:    "BDSA-2019-1234",
+				RelatedVulnerability: "https://my.blackduck.system/api/vulnerabilities/CVE-2019-10744",
+			},
+		},
+		{
+			Name:                         "log4j-core",
+			Version:                      "2.14.1",
+			ComponentVersion:             "https://my.blackduck.system/api/components/c2/versions/v1",
+			Component:                    &blackduck.Component{Origins: []blackduck.ComponentOrigin{{ExternalNamespace: "maven", ExternalID: "org.apache.logging.log4j:log4j-core:2.14.1"}}},
+			VulnerabilityWithRemediation: blackduck.VulnerabilityWithRemediation{VulnerabilityName: "CVE-2021-44228"},
+		},
+		{
+			Name:                         "errors",
+			Version:                      "v0.8.0",
+			ComponentVersion:             "https://my.blackduck.system/api/components/c3/versions/v1",
+			Component:                    &blackduck.Component{Origins: []blackduck.ComponentOrigin{{ExternalNamespace: "golang", ExternalID: "github.com/pkg/errors:v0.8.0"}}},
+			VulnerabilityWithRemediation: blackduck.VulnerabilityWithRemediation{VulnerabilityName: "CVE-2000-0001"},
+		},
+		{
+			Name:                         "no-fix",
+			Version:                      "1.0.0",
+			ComponentVersion:             "https://my.blackduck.system/api/components/c4/versions/v1",
+			VulnerabilityWithRemediation: blackduck.VulnerabilityWithRemediation{VulnerabilityName: "CVE-2000-0002"},
+		},
+	}
+	remediatingVersions := map[string]*blackduck.RemediatingVersions{
+		"https://my.blackduck.system/api/components/c1/versions/v1": {FixesPreviousVulnerabilities: &blackduck.RemediatingVersion{Name: "4.17.12"}},
+		"https://my.blackduck.system/api/components/c2/versions/v1": {NoVulnerabilities: &blackduck.RemediatingVersion{Name: "2.17.1"}},
+		"https://my.blackduck.system/api/components/c3/versions/v1": {FixesPreviousVulnerabilities: &blackduck.RemediatingVersion{Name: "v0.9.1"}},
+	}
+
+	upgrades := FromBlackDuck(vulnerabilities, remediatingVersions)
+
+	assert.Equal(t, []Upgrade{
+		{PackageType: "npm", Name: "lodash", Version: "4.17.11", FixedVersion: "4.17.12", Vulnerabilities: []string{"CVE-2019-10744"}},
+		{PackageType: "maven", Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", FixedVersion: "2.17.1", Vulnerabilities: []string{"CVE-2021-44228"}},
+		{PackageType: "golang", Name: "github.com/pkg/errors", Version: "v0.8.0", FixedVersion: "v0.9.1", Vulnerabilities: []string{"CVE-2000-0001"}},
+	}, upgrades)
+}
+
+func TestFromWhiteSource(t *testing.T) {
+	alerts := []whitesource.Alert{
+		{
+			Type:          "SECURITY_VULNERABILITY",
+			Library:       whitesource.Library{GroupID: "lodash", ArtifactID: "lodash-4.17.11.tgz", Version: "4.17.11", LibType: "javascript/Node.js"},
+			Vulnerability: whitesource.Vulnerability{Name: "CVE-2019-10744", TopFix: whitesource.Fix{Type: "UPGRADE_VERSION", FixResolution: "Upgrade to version lodash - 4.17.12,lodash-es - 4.17.14"}},
+		},
+		{
+			Type:          "SECURITY_VULNERABILITY",
+			Library:       whitesource.Library{GroupID: "org.apache.logging.log4j", ArtifactID: "log4j-core", Version: "2.14.1", LibType: "Java"},
+			Vulnerability: whitesource.Vulnerability{Name: "CVE-2021-44228", TopFix: whitesource.Fix{Type: "UPGRADE_VERSION", FixResolution: "Upgrade to version org.apache.logging.log4j:log4j-core:2.15.0"}},
+		},
+		{
+			Type:          "SECURITY_VULNERABILITY",
+			Library:       whitesource.Library{GroupID: "org.apache.logging.log4j", ArtifactID: "log4j-core", Version: "2.14.1", LibType: "Java"},
+			Vulnerability: whitesource.Vulnerability{Name: "CVE-2021-45046", TopFix: whitesource.Fix{FixResolution: "Upgrade to version 2.16.0"}},
+		},
+		{
+			Type:          "SECURITY_VULNERABILITY",
+			Library:       whitesource.Library{GroupID: "org.example", ArtifactID: "patched", Version: "1.0.0", LibType: "Java"},
+			Vulnerability: whitesource.Vulnerability{Name: "CVE-2000-0001", TopFix: whitesource.Fix{Type: "CHANGE_FILES", FixResolution: "Replace or update the following file: A.java"}},
+		},
+		{
+			Type:    "REJECTED_BY_POLICY_RESOURCE",
+			Library: whitesource.Library{GroupID: "org.example", ArtifactID: "policy", Version: "1.0.0", LibType: "Java"},
+		},
+	}
+
+	upgrades := Merge(FromWhiteSource(alerts))
+
+	assert.Equal(t, []Upgrade{
+		{PackageType: "maven", Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", FixedVersion: "2.16.0", Vulnerabilities: []string{"CVE-2021-44228", "CVE-2021-45046"}},
+		{PackageType: "npm", Name: "lodash", Version: "4.17.11", FixedVersion: "4.17.12", Vulnerabilities: []string{"CVE-2019-10744"}},
+	}, upgrades)
+}
+
+func TestMajorUpgrade(t *testing.T) {
+	assert.False(t, Upgrade{Version: "1.2.3", FixedVersion: "1.4.0"}.MajorUpgrade())
+	assert.False(t, Upgrade{Version: "v0.8.0", FixedVersion: "0.9.1"}.MajorUpgrade())
+	assert.True(t, Upgrade{Version: "1.2.3", FixedVersion: "2.0.0"}.MajorUpgrade())
+}
+
+const testPackageJSON = `{
+  "name": "my-app",
+  "dependencies": {
+    "lodash": "^4.17.11",
+    "@scope/lib": "git+https://github.com/scope/lib.git"
+  },
+  "devDependencies": {
+    "mocha": "~6.0.0"
+  }
+}
+`
+
+const testPom = `<project>
+  <properties>
+    <log4j.version>2.14.1</log4j.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>org.apache.logging.log4j</groupId>
+      <artifactId>log4j-core</artifactId>
+      <version>${log4j.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+      <version>2.9.8</version>
+    </dependency>
+  </dependencies>
+</project>
+`
+
+const testGoMod = `module example.com/app
+
+go 1.19
+
+require (
+	github.com/pkg/errors v0.8.0
+	golang.org/x/text v0.3.0 // indirect
+)
+`
+
+func TestApply(t *testing.T) {
+	descriptors := map[string][]byte{
+		"package.json": []byte(testPackageJSON),
+		"pom.xml":      []byte(testPom),
+		"go.mod":       []byte(testGoMod),
+	}
+	upgrades := []Upgrade{
+		{PackageType: "npm", Name: "lodash", Version: "4.17.11", FixedVersion: "4.17.21", Vulnerabilities: []string{"CVE-2019-10744"}},
+		{PackageType: "npm", Name: "mocha", Version: "6.0.0", FixedVersion: "10.0.0", Vulnerabilities: []string{"CVE-2000-0001"}},
+		{PackageType: "npm", Name: "minimist", Version: "1.2.0", FixedVersion: "1.2.6", Vulnerabilities: []string{"CVE-2021-44906"}},
+		{PackageType: "maven", Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", FixedVersion: "2.17.1", Vulnerabilities: []string{"CVE-2021-44228"}},
+		{PackageType: "maven", Name: "com.fasterxml.jackson.core:jackson-databind", Version: "2.9.8", FixedVersion: "2.9.10.8", Vulnerabilities: []string{"CVE-2020-36518"}},
+		{PackageType: "golang", Name: "github.com/pkg/errors", Version: "v0.8.0", FixedVersion: "0.9.1", Vulnerabilities: []string{"CVE-2000-0002"}},
+		{PackageType: "golang", Name: "golang.org/x/text", Version: "v0.3.0", FixedVersion: "v0.3.8", Vulnerabilities: []string{"CVE-2022-32149"}},
+	}
+
+	t.Run("without major upgrades", func(t *testing.T) {
+		result, err := Apply(descriptors, upgrades, Options{})
+
+		require.NoError(t, err)
+		assert.Contains(t, string(result.Files["package.json"]), `"lodash": "^4.17.21"`)
+		assert.Contains(t, string(result.Files["package.json"]), `"mocha": "~6.0.0"`)
+		assert.Contains(t, string(result.Files["pom.xml"]), "<log4j.version>2.17.1</log4j.version>")
+		assert.Contains(t, string(result.Files["pom.xml"]), "<version>${log4j.version}</version>")
+		assert.Contains(t, string(result.Files["pom.xml"]), "<version>2.9.10.8</version>")
+		assert.Contains(t, string(result.Files["go.mod"]), "github.com/pkg/errors v0.9.1\n")
+		assert.Contains(t, string(result.Files["go.mod"]), "golang.org/x/text v0.3.0 // indirect")
+
+		assert.Equal(t, []Change{
+			{File: "package.json", Upgrade: upgrades[0], FromVersion: "^4.17.11", ToVersion: "4.17.21"},
+			{File: "pom.xml", Upgrade: upgrades[3], FromVersion: "2.14.1", ToVersion: "2.17.1"},
+			{File: "pom.xml", Upgrade: upgrades[4], FromVersion: "2.9.8", ToVersion: "2.9.10.8"},
+			{File: "go.mod", Upgrade: upgrades[5], FromVersion: "v0.8.0", ToVersion: "0.9.1"},
+		}, result.Changes)
+		assert.Equal(t, []Skipped{
+			{Upgrade: upgrades[1], Reason: "major version upgrade"},
+			{Upgrade: upgrades[2], Reason: "no outdated direct dependency declaration found"},
+			{Upgrade: upgrades[6], Reason: "no outdated direct dependency declaration found"},
+		}, result.Skipped)
+		assert.Equal(t, []string{"CVE-2000-0002", "CVE-2019-10744", "CVE-2020-36518", "CVE-2021-44228"}, result.Vulnerabilities())
+
+		body := result.PullRequestBody()
+		assert.Contains(t, body, "| lodash | package.json | ^4.17.11 | 4.17.21 | CVE-2019-10744 |")
+		assert.Contains(t, body, "Addressed vulnerabilities: CVE-2000-0002, CVE-2019-10744, CVE-2020-36518, CVE-2021-44228")
+		assert.Contains(t, body, "| mocha | 6.0.0 | 10.0.0 | major version upgrade | CVE-2000-0001 |")
+	})
+
+	t.Run("with major upgrades", func(t *testing.T) {
+		goMajorUpgrade := Upgrade{PackageType: "golang", Name: "github.com/pkg/errors", Version: "v0.8.0", FixedVersion: "v2.0.0", Vulnerabilities: []string{"CVE-2000-0003"}}
+		result, err := Apply(descriptors, []Upgrade{upgrades[1], goMajorUpgrade}, Options{AllowMajorUpgrades: true})
+
+		require.NoError(t, err)
+		assert.Contains(t, string(result.Files["package.json"]), `"mocha": "~10.0.0"`)
+		assert.NotContains(t, result.Files, "go.mod")
+		assert.Equal(t, []Skipped{{Upgrade: goMajorUpgrade, Reason: "major version upgrade of Go module requires new module path"}}, result.Skipped)
+	})
+
+	t.Run("dependency excluded by another dependency", func(t *testing.T) {
+		pom := `<project>
+  <dependencies>
+    <dependency>
+      <groupId>org.springframework.boot</groupId>
+      <artifactId>spring-boot-starter-log4j2</artifactId>
+      <version>2.5.0</version>
+      <exclusions>
+        <exclusion>
+          <groupId>org.apache.logging.log4j</groupId>
+          <artifactId>log4j-core</artifactId>
+        </exclusion>
+      </exclusions>
+    </dependency>
+  </dependencies>
+</project>
+`
+		result, err := Apply(map[string][]byte{"pom.xml": []byte(pom)}, upgrades[3:4], Options{})
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Files)
+		assert.Equal(t, []Skipped{{Upgrade: upgrades[3], Reason: "no outdated direct dependency declaration found"}}, result.Skipped)
+	})
+
+	t.Run("maven dependency and property in the same file", func(t *testing.T) {
+		pom := `<project>
+  <properties>
+    <jackson.version>2.9.8</jackson.version>
+    <other.version>2.9.8</other.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.fasterxml.jackson.core</groupId>
+        <artifactId>jackson-databind</artifactId>
+        <version>${jackson.version}</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+      <version>
+        2.9.8
+      </version>
+    </dependency>
+  </dependencies>
+</project>
+`
+		result, err := Apply(map[string][]byte{"pom.xml": []byte(pom)}, upgrades[4:5], Options{})
+
+		require.NoError(t, err)
+		updated := string(result.Files["pom.xml"])
+		assert.Contains(t, updated, "<jackson.version>2.9.10.8</jackson.version>")
+		assert.Contains(t, updated, "<other.version>2.9.8</other.version>")
+		assert.Contains(t, updated, "<version>\n        2.9.10.8\n      </version>")
+	})
+
+	t.Run("maven property shared with other artifacts", func(t *testing.T) {
+		pom := `<project>
+  <properties>
+    <log4j.version>2.14.1</log4j.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>org.apache.logging.log4j</groupId>
+      <artifactId>log4j-core</artifactId>
+      <version>${log4j.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>org.apache.logging.log4j</groupId>
+      <artifactId>log4j-api</artifactId>
+      <version>${log4j.version}</version>
+    </dependency>
+  </dependencies>
+</project>
+`
+		result, err := Apply(map[string][]byte{"pom.xml": []byte(pom)}, upgrades[3:4], Options{})
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Files)
+		assert.Equal(t, []Skipped{{Upgrade: upgrades[3], Reason: "version property 'log4j.version' is used by other declarations in 'pom.xml'"}}, result.Skipped)
+	})
+
+	t.Run("maven dependency of a plugin", func(t *testing.T) {
+		pom := `<project>
+  <build>
+    <plugins>
+      <plugin>
+        <groupId>org.apache.maven.plugins</groupId>
+        <artifactId>maven-site-plugin</artifactId>
+        <dependencies>
+          <dependency>
+            <groupId>com.fasterxml.jackson.core</groupId>
+            <artifactId>jackson-databind</artifactId>
+            <version>2.9.8</version>
+          </dependency>
+        </dependencies>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`
+		result, err := Apply(map[string][]byte{"pom.xml": []byte(pom)}, upgrades[4:5], Options{})
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Files)
+		assert.Equal(t, []Skipped{{Upgrade: upgrades[4], Reason: "no outdated direct dependency declaration found"}}, result.Skipped)
+	})
+
+	t.Run("invalid descriptor", func(t *testing.T) {
+		_, err := Apply(map[string][]byte{"package.json": []byte("{")}, upgrades[:1], Options{})
+		assert.EqualError(t, err, "failed to parse 'package.json': unexpected end of JSON input")
+	})
+}
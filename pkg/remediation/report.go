@@ -0,0 +1,43 @@
+package remediation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PullRequestBody creates the markdown description of a pull request containing the applied upgrades
+func (r *Result) PullRequestBody() string {
+	var body strings.Builder
+	body.WriteString("This pull request upgrades dependencies with known vulnerabilities to versions which fix them.\n\n")
+
+	body.WriteString("| Dependency | File | From | To | Vulnerabilities |\n")
+	body.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, change := range r.Changes {
+		body.WriteString(fmt.Sprintf("| %v | %v | %v | %v | %v |\n", change.Upgrade.Name, change.File, change.FromVersion, change.ToVersion, strings.Join(change.Upgrade.Vulnerabilities, ", ")))
+	}
+
+	if vulnerabilities := r.Vulnerabilities(); len(vulnerabilities) > 0 {
+		body.WriteString(fmt.Sprintf("\nAddressed vulnerabilities: %v\n", strings.Join(vulnerabilities, ", ")))
+	}
+
+	if len(r.Skipped) > 0 {
+		body.WriteString("\n### Skipped upgrades\n\n")
+		body.WriteString("| Dependency | Version | Fixed version | Reason | Vulnerabilities |\n")
+		body.WriteString("| --- | --- | --- | --- | --- |\n")
+		for _, skipped := range r.Skipped {
+			body.WriteString(fmt.Sprintf("| %v | %v | %v | %v | %v |\n", skipped.Upgrade.Name, skipped.Upgrade.Version, skipped.Upgrade.FixedVersion, skipped.Reason, strings.Join(skipped.Upgrade.Vulnerabilities, ", ")))
+		}
+	}
+
+	body.WriteString("\nLock files (e.g. package-lock.json, go.sum) are not updated and need to be refreshed before merging.\n")
+	return body.String()
+}
+
+// Vulnerabilities returns the vulnerabilities addressed by the applied upgrades
+func (r *Result) Vulnerabilities() []string {
+	vulnerabilities := []string{}
+	for _, change := range r.Changes {
+		vulnerabilities = append(vulnerabilities, change.Upgrade.Vulnerabilities...)
+	}
+	return unique(vulnerabilities)
+}
@@ -0,0 +1,142 @@
+package sbom
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/package-url/packageurl-go"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// ReportsDirectory defines the subfolder for the SBOMs and reports which are generated
+const ReportsDirectory = "sbom"
+
+// ReadBOM reads a CycloneDX SBOM in XML or JSON format
+func ReadBOM(path string, utils piperutils.FileUtils) (*cdx.BOM, error) {
+	content, err := utils.FileRead(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read SBOM '%v'", path)
+	}
+	bom := cdx.BOM{}
+	if err := cdx.NewBOMDecoder(bytes.NewReader(content), bomFileFormat(path)).Decode(&bom); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse SBOM '%v'", path)
+	}
+	return &bom, nil
+}
+
+// WriteBOM writes a CycloneDX SBOM, the format (XML or JSON) is determined by the file extension
+func WriteBOM(bom *cdx.BOM, path string, utils piperutils.FileUtils) error {
+	var buffer bytes.Buffer
+	encoder := cdx.NewBOMEncoder(&buffer, bomFileFormat(path))
+	encoder.SetPretty(true)
+	if err := encoder.Encode(bom); err != nil {
+		return errors.Wrapf(err, "failed to encode SBOM '%v'", path)
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := utils.MkdirAll(dir, 0777); err != nil {
+			return errors.Wrapf(err, "failed to create directory '%v'", dir)
+		}
+	}
+	if err := utils.FileWrite(path, buffer.Bytes(), 0666); err != nil {
+		return errors.Wrapf(err, "failed to write SBOM '%v'", path)
+	}
+	return nil
+}
+
+func bomFileFormat(path string) cdx.BOMFileFormat {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return cdx.BOMFileFormatJSON
+	}
+	return cdx.BOMFileFormatXML
+}
+
+// Components returns the component described by the SBOM as well as all (nested) components contained in the SBOM
+func Components(bom *cdx.BOM) []cdx.Component {
+	components := []cdx.Component{}
+	if bom.Metadata != nil && bom.Metadata.Component != nil {
+		components = append(components, *bom.Metadata.Component)
+	}
+	if bom.Components != nil {
+		components = append(components, flattenComponents(*bom.Components)...)
+	}
+	return components
+}
+
+// ContainedComponents returns all (nested) components contained in the SBOM without the component described by the SBOM
+func ContainedComponents(bom *cdx.BOM) []cdx.Component {
+	if bom.Components == nil {
+		return []cdx.Component{}
+	}
+	return flattenComponents(*bom.Components)
+}
+
+func flattenComponents(components []cdx.Component) []cdx.Component {
+	flat := []cdx.Component{}
+	for _, component := range components {
+		flat = append(flat, component)
+		if component.Components != nil {
+			flat = append(flat, flattenComponents(*component.Components)...)
+		}
+	}
+	return flat
+}
+
+// ComponentKey identifies a component by its package URL, components without package URL are identified by group, name and version
+func ComponentKey(component cdx.Component) string {
+	if len(component.PackageURL) > 0 {
+		if purl, err := packageurl.FromString(component.PackageURL); err == nil {
+			return purl.ToString()
+		}
+		return component.PackageURL
+	}
+	return fmt.Sprintf("%v:%v@%v", component.Group, component.Name, component.Version)
+}
+
+// componentIdentity identifies a component independent of its version
+func componentIdentity(component cdx.Component) string {
+	if len(component.PackageURL) > 0 {
+		if purl, err := packageurl.FromString(component.PackageURL); err == nil {
+			purl.Version = ""
+			purl.Qualifiers = nil
+			purl.Subpath = ""
+			return purl.ToString()
+		}
+	}
+	return fmt.Sprintf("%v:%v", component.Group, component.Name)
+}
+
+// componentName returns the name of the component including its group or namespace
+func componentName(component cdx.Component) string {
+	if len(component.Group) > 0 {
+		return component.Group + "/" + component.Name
+	}
+	return component.Name
+}
+
+// ComponentLicenses returns the sorted license IDs, names and expressions of a component
+func ComponentLicenses(component cdx.Component) []string {
+	licenses := []string{}
+	if component.Licenses == nil {
+		return licenses
+	}
+	for _, choice := range *component.Licenses {
+		license := choice.Expression
+		if choice.License != nil {
+			license = choice.License.ID
+			if len(license) == 0 {
+				license = choice.License.Name
+			}
+		}
+		if len(license) > 0 && !piperutils.ContainsString(licenses, license) {
+			licenses = append(licenses, license)
+		}
+	}
+	sort.Strings(licenses)
+	return licenses
+}
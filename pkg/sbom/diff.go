@@ -0,0 +1,164 @@
+package sbom
+
+import (
+	"sort"
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+)
+
+// ComponentInfo contains the information of a component which is relevant for comparing SBOMs
+type ComponentInfo struct {
+	Name     string   `json:"name"`
+	Version  string   `json:"version,omitempty"`
+	Purl     string   `json:"purl,omitempty"`
+	Licenses []string `json:"licenses,omitempty"`
+}
+
+// ComponentChange describes a component which is contained in both SBOMs with a different version or different licenses
+type ComponentChange struct {
+	Name             string   `json:"name"`
+	Purl             string   `json:"purl,omitempty"`
+	PreviousVersion  string   `json:"previousVersion,omitempty"`
+	Version          string   `json:"version,omitempty"`
+	PreviousLicenses []string `json:"previousLicenses,omitempty"`
+	Licenses         []string `json:"licenses,omitempty"`
+}
+
+// LicenseChanged returns true if the licenses of the component changed
+func (c ComponentChange) LicenseChanged() bool {
+	return strings.Join(c.PreviousLicenses, ",") != strings.Join(c.Licenses, ",")
+}
+
+// BOMDiff describes the differences between two versions of an SBOM
+type BOMDiff struct {
+	Added          []ComponentInfo   `json:"added"`
+	Removed        []ComponentInfo   `json:"removed"`
+	Upgraded       []ComponentChange `json:"upgraded"`
+	Downgraded     []ComponentChange `json:"downgraded"`
+	LicenseChanges []ComponentChange `json:"licenseChanges"`
+}
+
+// HasChanges returns true if the SBOMs differ
+func (d BOMDiff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Upgraded) > 0 || len(d.Downgraded) > 0 || len(d.LicenseChanges) > 0
+}
+
+// Diff compares two versions of an SBOM.
+// Components are matched by their package URL without version, a component contained in both SBOMs with different versions is considered to be upgraded or downgraded.
+// Versions are ordered via compareVersions, which returns -1, 0 or 1 like osv.CompareVersions.
+// License changes are reported for unchanged, upgraded and downgraded components.
+func Diff(previous, current *cdx.BOM, compareVersions func(a, b string) int) BOMDiff {
+	previousComponents := componentsByIdentity(previous)
+	currentComponents := componentsByIdentity(current)
+
+	diff := BOMDiff{
+		Added:          []ComponentInfo{},
+		Removed:        []ComponentInfo{},
+		Upgraded:       []ComponentChange{},
+		Downgraded:     []ComponentChange{},
+		LicenseChanges: []ComponentChange{},
+	}
+	for _, identity := range sortedIdentities(previousComponents, currentComponents) {
+		previousVersions := previousComponents[identity]
+		currentVersions := currentComponents[identity]
+
+		removed := []ComponentInfo{}
+		for version, info := range previousVersions {
+			if currentInfo, ok := currentVersions[version]; ok {
+				change := newComponentChange(info, currentInfo)
+				if change.LicenseChanged() {
+					diff.LicenseChanges = append(diff.LicenseChanges, change)
+				}
+				continue
+			}
+			removed = append(removed, info)
+		}
+		added := []ComponentInfo{}
+		for version, info := range currentVersions {
+			if _, ok := previousVersions[version]; !ok {
+				added = append(added, info)
+			}
+		}
+		sortByVersion(removed, compareVersions)
+		sortByVersion(added, compareVersions)
+
+		// versions which are replaced by another version of the same component are considered to be upgrades or downgrades
+		for len(removed) > 0 && len(added) > 0 {
+			change := newComponentChange(removed[0], added[0])
+			if compareVersions(added[0].Version, removed[0].Version) < 0 {
+				diff.Downgraded = append(diff.Downgraded, change)
+			} else {
+				diff.Upgraded = append(diff.Upgraded, change)
+			}
+			if change.LicenseChanged() {
+				diff.LicenseChanges = append(diff.LicenseChanges, change)
+			}
+			removed = removed[1:]
+			added = added[1:]
+		}
+		diff.Removed = append(diff.Removed, removed...)
+		diff.Added = append(diff.Added, added...)
+	}
+	sort.SliceStable(diff.LicenseChanges, func(i, j int) bool {
+		return diff.LicenseChanges[i].Purl+diff.LicenseChanges[i].Version < diff.LicenseChanges[j].Purl+diff.LicenseChanges[j].Version
+	})
+	return diff
+}
+
+func newComponentChange(previous, current ComponentInfo) ComponentChange {
+	return ComponentChange{
+		Name:             current.Name,
+		Purl:             current.Purl,
+		PreviousVersion:  previous.Version,
+		Version:          current.Version,
+		PreviousLicenses: previous.Licenses,
+		Licenses:         current.Licenses,
+	}
+}
+
+// componentsByIdentity maps the components of the SBOM by their identity and version.
+// The component described by the SBOM is not considered since its version changes with every release.
+func componentsByIdentity(bom *cdx.BOM) map[string]map[string]ComponentInfo {
+	components := map[string]map[string]ComponentInfo{}
+	if bom.Components == nil {
+		return components
+	}
+	for _, component := range flattenComponents(*bom.Components) {
+		identity := componentIdentity(component)
+		if _, ok := components[identity]; !ok {
+			components[identity] = map[string]ComponentInfo{}
+		}
+		components[identity][component.Version] = ComponentInfo{
+			Name:     componentName(component),
+			Version:  component.Version,
+			Purl:     component.PackageURL,
+			Licenses: ComponentLicenses(component),
+		}
+	}
+	return components
+}
+
+func sortedIdentities(maps ...map[string]map[string]ComponentInfo) []string {
+	identities := []string{}
+	for _, m := range maps {
+		for identity := range m {
+			identities = append(identities, identity)
+		}
+	}
+	sort.Strings(identities)
+	unique := []string{}
+	for i, identity := range identities {
+		if i == 0 || identities[i-1] != identity {
+			unique = append(unique, identity)
+		}
+	}
+	return unique
+}
+
+// sortByVersion sorts the components by version in order to pair removed and added versions deterministically
+func sortByVersion(components []ComponentInfo, compareVersions func(a, b string) int) {
+	sort.SliceStable(components, func(i, j int) bool {
+		return compareVersions(components[i].Version, components[j].Version) < 0
+	})
+}
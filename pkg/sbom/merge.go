@@ -0,0 +1,196 @@
+package sbom
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/uuid"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// Merge combines multiple SBOMs into one SBOM describing the application.
+// Components are de-duplicated by their package URL, the dependencies of duplicates are attached to the remaining component.
+// The components described by the merged SBOMs (e.g. the modules of the application) become dependencies of the application.
+func Merge(boms []*cdx.BOM, application *cdx.Component) *cdx.BOM {
+	m := merger{
+		refs:         map[string]string{},
+		indices:      map[string]int{},
+		usedRefs:     map[string]bool{},
+		dependencies: map[string][]string{},
+	}
+
+	applicationRef := ""
+	if application != nil {
+		app := *application
+		if len(app.BOMRef) == 0 {
+			app.BOMRef = ComponentKey(app)
+		}
+		applicationRef = app.BOMRef
+		m.usedRefs[applicationRef] = true
+		application = &app
+	}
+
+	tools := []cdx.Tool{}
+	for _, bom := range boms {
+		bomRefs := map[string]string{}
+		if bom.Metadata != nil {
+			if bom.Metadata.Tools != nil {
+				tools = appendTools(tools, *bom.Metadata.Tools)
+			}
+			if bom.Metadata.Component != nil {
+				ref := m.add(*bom.Metadata.Component, bomRefs)
+				if len(applicationRef) > 0 {
+					m.addDependency(applicationRef, ref)
+				}
+			}
+		}
+		if bom.Components != nil {
+			for _, component := range flattenComponents(*bom.Components) {
+				m.add(component, bomRefs)
+			}
+		}
+		if bom.Dependencies != nil {
+			for _, dependency := range *bom.Dependencies {
+				ref := mappedRef(bomRefs, dependency.Ref)
+				m.addDependency(ref)
+				if dependency.Dependencies != nil {
+					for _, dependsOn := range *dependency.Dependencies {
+						m.addDependency(ref, mappedRef(bomRefs, dependsOn.Ref))
+					}
+				}
+			}
+		}
+	}
+
+	merged := cdx.NewBOM()
+	merged.SerialNumber = "urn:uuid:" + uuid.New().String()
+	merged.Metadata = &cdx.Metadata{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Tools:     &tools,
+		Component: application,
+	}
+	merged.Components = &m.components
+	dependencies := m.sortedDependencies()
+	merged.Dependencies = &dependencies
+	return merged
+}
+
+type merger struct {
+	components []cdx.Component
+	// refs maps the key of a component to the bom-ref used in the merged SBOM
+	refs map[string]string
+	// indices maps the key of a component to its index in the merged components
+	indices      map[string]int
+	usedRefs     map[string]bool
+	dependencies map[string][]string
+}
+
+// add adds the component if it is not yet contained and returns the bom-ref of the component within the merged SBOM
+func (m *merger) add(component cdx.Component, bomRefs map[string]string) string {
+	key := ComponentKey(component)
+	if ref, ok := m.refs[key]; ok {
+		m.complete(m.indices[key], component)
+		if len(component.BOMRef) > 0 {
+			bomRefs[component.BOMRef] = ref
+		}
+		return ref
+	}
+
+	ref := component.BOMRef
+	if len(ref) == 0 || m.usedRefs[ref] {
+		// bom-refs are only unique within one SBOM, e.g. syft uses generated IDs
+		ref = key
+	}
+	for i := 1; m.usedRefs[ref]; i++ {
+		ref = fmt.Sprintf("%v#%v", key, i)
+	}
+	if len(component.BOMRef) > 0 {
+		bomRefs[component.BOMRef] = ref
+	}
+	m.usedRefs[ref] = true
+	m.refs[key] = ref
+	m.indices[key] = len(m.components)
+
+	component.BOMRef = ref
+	component.Components = nil
+	m.components = append(m.components, component)
+	return ref
+}
+
+// complete adds information which is missing in the already contained component
+func (m *merger) complete(index int, component cdx.Component) {
+	existing := &m.components[index]
+	if (existing.Licenses == nil || len(*existing.Licenses) == 0) && component.Licenses != nil {
+		existing.Licenses = component.Licenses
+	}
+	if (existing.Hashes == nil || len(*existing.Hashes) == 0) && component.Hashes != nil {
+		existing.Hashes = component.Hashes
+	}
+	if len(existing.Scope) == 0 || component.Scope == cdx.ScopeRequired {
+		existing.Scope = component.Scope
+	}
+}
+
+func (m *merger) addDependency(ref string, dependsOn ...string) {
+	if _, ok := m.dependencies[ref]; !ok {
+		m.dependencies[ref] = []string{}
+	}
+	for _, dependency := range dependsOn {
+		if dependency == ref {
+			continue
+		}
+		if !piperutils.ContainsString(m.dependencies[ref], dependency) {
+			m.dependencies[ref] = append(m.dependencies[ref], dependency)
+		}
+	}
+}
+
+func (m *merger) sortedDependencies() []cdx.Dependency {
+	refs := []string{}
+	for ref := range m.dependencies {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	dependencies := []cdx.Dependency{}
+	for _, ref := range refs {
+		dependsOn := m.dependencies[ref]
+		sort.Strings(dependsOn)
+		dependency := cdx.Dependency{Ref: ref}
+		if len(dependsOn) > 0 {
+			nested := []cdx.Dependency{}
+			for _, dependencyRef := range dependsOn {
+				nested = append(nested, cdx.Dependency{Ref: dependencyRef})
+			}
+			dependency.Dependencies = &nested
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies
+}
+
+func mappedRef(bomRefs map[string]string, ref string) string {
+	if mapped, ok := bomRefs[ref]; ok {
+		return mapped
+	}
+	return ref
+}
+
+func appendTools(tools, additionalTools []cdx.Tool) []cdx.Tool {
+	for _, tool := range additionalTools {
+		contained := false
+		for _, t := range tools {
+			if t.Vendor == tool.Vendor && t.Name == tool.Name && t.Version == tool.Version {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
@@ -0,0 +1,114 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// CreateDiffReport creates a ScanReport listing the differences between two versions of an SBOM
+func CreateDiffReport(previousBOMFile, bomFile string, diff BOMDiff) reporting.ScanReport {
+	scanReport := reporting.ScanReport{
+		ReportTitle: "SBOM Difference Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Previous SBOM", Details: previousBOMFile},
+			{Description: "Current SBOM", Details: bomFile},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Added components", Details: fmt.Sprint(len(diff.Added))},
+			{Description: "Removed components", Details: fmt.Sprint(len(diff.Removed))},
+			{Description: "Upgraded components", Details: fmt.Sprint(len(diff.Upgraded))},
+			{Description: "Downgraded components", Details: fmt.Sprint(len(diff.Downgraded))},
+			{Description: "Components with changed licenses", Details: fmt.Sprint(len(diff.LicenseChanges))},
+		},
+		SuccessfulScan: true,
+		ReportTime:     time.Now(),
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No differences detected",
+		Headers: []string{
+			"Change",
+			"Component",
+			"Previous version",
+			"Version",
+			"Previous licenses",
+			"Licenses",
+		},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+	for _, component := range diff.Added {
+		detailTable.Rows = append(detailTable.Rows, diffRow("added", reporting.Green, ComponentChange{Name: component.Name, Version: component.Version, Licenses: component.Licenses}))
+	}
+	for _, component := range diff.Removed {
+		detailTable.Rows = append(detailTable.Rows, diffRow("removed", reporting.Grey, ComponentChange{Name: component.Name, PreviousVersion: component.Version, PreviousLicenses: component.Licenses}))
+	}
+	for _, change := range diff.Upgraded {
+		detailTable.Rows = append(detailTable.Rows, diffRow("upgraded", reporting.Black, change))
+	}
+	for _, change := range diff.Downgraded {
+		detailTable.Rows = append(detailTable.Rows, diffRow("downgraded", reporting.Red, change))
+	}
+	for _, change := range diff.LicenseChanges {
+		detailTable.Rows = append(detailTable.Rows, diffRow("license changed", reporting.Yellow, change))
+	}
+	scanReport.DetailTable = detailTable
+
+	return scanReport
+}
+
+func diffRow(change string, style reporting.ColumnStyle, component ComponentChange) reporting.ScanRow {
+	row := reporting.ScanRow{}
+	row.AddColumn(change, style)
+	row.AddColumn(component.Name, 0)
+	row.AddColumn(component.PreviousVersion, 0)
+	row.AddColumn(component.Version, 0)
+	row.AddColumn(strings.Join(component.PreviousLicenses, ", "), 0)
+	row.AddColumn(strings.Join(component.Licenses, ", "), 0)
+	return row
+}
+
+// WriteDiffReports writes the difference report in HTML format as well as the differences in JSON format
+func WriteDiffReports(scanReport reporting.ScanReport, diff BOMDiff, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+
+	// ignore templating errors since template is in our hands and issues will be detected with the automated tests
+	htmlReport, _ := scanReport.ToHTML()
+	if err := utils.MkdirAll(ReportsDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrapf(err, "failed to create report directory")
+	}
+	htmlReportPath := filepath.Join(ReportsDirectory, "piper_sbom_diff_report.html")
+	if err := utils.FileWrite(htmlReportPath, htmlReport, 0666); err != nil {
+		return reportPaths, errors.Wrapf(err, "failed to write html report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "SBOM Difference Report", Target: htmlReportPath})
+
+	// ignore JSON errors since structure is in our hands
+	diffJSON, _ := json.MarshalIndent(diff, "", "  ")
+	diffPath := filepath.Join(ReportsDirectory, "sbom_diff.json")
+	if err := utils.FileWrite(diffPath, diffJSON, 0666); err != nil {
+		return reportPaths, errors.Wrapf(err, "failed to write SBOM differences")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Target: diffPath})
+
+	// JSON reports are used by step pipelineCreateSummary in order to e.g. prepare an issue creation in GitHub
+	jsonReport, _ := scanReport.ToJSON()
+	if exists, _ := utils.DirExists(reporting.StepReportDirectory); !exists {
+		if err := utils.MkdirAll(reporting.StepReportDirectory, 0777); err != nil {
+			return reportPaths, errors.Wrap(err, "failed to create step reporting directory")
+		}
+	}
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, "sbomProcess_diff.json"), jsonReport, 0666); err != nil {
+		return reportPaths, errors.Wrapf(err, "failed to write json report")
+	}
+
+	return reportPaths, nil
+}
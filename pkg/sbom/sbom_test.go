@@ -0,0 +1,203 @@
+//go:build unit
+// +build unit
+
+package sbom
+
+import (
+	"strings"
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+func testComponent(ref, name, version, license string) cdx.Component {
+	component := cdx.Component{
+		BOMRef:     ref,
+		Type:       cdx.ComponentTypeLibrary,
+		Name:       name,
+		Version:    version,
+		PackageURL: "pkg:npm/" + name + "@" + version,
+	}
+	if len(license) > 0 {
+		component.Licenses = &cdx.Licenses{{License: &cdx.License{ID: license}}}
+	}
+	return component
+}
+
+func testBOM(module cdx.Component, components []cdx.Component, dependencies map[string][]string) *cdx.BOM {
+	bom := cdx.NewBOM()
+	bom.Metadata = &cdx.Metadata{Component: &module, Tools: &[]cdx.Tool{{Name: "cyclonedx-npm", Version: "1.0.0"}}}
+	bom.Components = &components
+	deps := []cdx.Dependency{}
+	for ref, dependsOn := range dependencies {
+		nested := []cdx.Dependency{}
+		for _, dependency := range dependsOn {
+			nested = append(nested, cdx.Dependency{Ref: dependency})
+		}
+		deps = append(deps, cdx.Dependency{Ref: ref, Dependencies: &nested})
+	}
+	bom.Dependencies = &deps
+	return bom
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+	frontend := testBOM(
+		testComponent("frontend", "frontend", "1.0.0", ""),
+		[]cdx.Component{testComponent("1", "lodash", "4.17.21", ""), testComponent("2", "react", "18.2.0", "MIT")},
+		map[string][]string{"frontend": {"1", "2"}, "2": {"1"}},
+	)
+	backend := testBOM(
+		testComponent("backend", "backend", "1.0.0", ""),
+		[]cdx.Component{testComponent("1", "express", "4.18.2", "MIT"), testComponent("2", "lodash", "4.17.21", "MIT")},
+		map[string][]string{"backend": {"1", "2"}},
+	)
+	application := cdx.Component{Type: cdx.ComponentTypeApplication, Name: "shop", Version: "2.0.0"}
+
+	merged := Merge([]*cdx.BOM{frontend, backend}, &application)
+
+	assert.Equal(t, "shop", merged.Metadata.Component.Name)
+	assert.Equal(t, ":shop@2.0.0", merged.Metadata.Component.BOMRef)
+	assert.Len(t, *merged.Metadata.Tools, 1)
+	components := *merged.Components
+	require.Len(t, components, 5)
+	refs := map[string]cdx.Component{}
+	for _, component := range components {
+		refs[component.Name] = component
+	}
+	assert.Equal(t, "1", refs["lodash"].BOMRef)
+	assert.Equal(t, []string{"MIT"}, ComponentLicenses(refs["lodash"]), "license of duplicate is taken over")
+	assert.Equal(t, "pkg:npm/express@4.18.2", refs["express"].BOMRef, "conflicting bom-ref is replaced")
+
+	dependencies := map[string][]string{}
+	for _, dependency := range *merged.Dependencies {
+		dependsOn := []string{}
+		if dependency.Dependencies != nil {
+			for _, d := range *dependency.Dependencies {
+				dependsOn = append(dependsOn, d.Ref)
+			}
+		}
+		dependencies[dependency.Ref] = dependsOn
+	}
+	assert.Equal(t, []string{"backend", "frontend"}, dependencies[":shop@2.0.0"])
+	assert.Equal(t, []string{"1", "2"}, dependencies["frontend"])
+	assert.Equal(t, []string{"1", "pkg:npm/express@4.18.2"}, dependencies["backend"])
+	assert.Equal(t, []string{"1"}, dependencies["2"])
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+	previous := testBOM(
+		testComponent("app", "app", "1.0.0", ""),
+		[]cdx.Component{
+			testComponent("a", "lodash", "4.17.20", "MIT"),
+			testComponent("b", "left-pad", "1.3.0", "WTFPL"),
+			testComponent("c", "react", "18.2.0", "MIT"),
+		},
+		nil,
+	)
+	current := testBOM(
+		testComponent("app", "app", "1.1.0", ""),
+		[]cdx.Component{
+			testComponent("a", "lodash", "4.17.21", "MIT"),
+			testComponent("c", "react", "18.2.0", "Apache-2.0"),
+			testComponent("d", "express", "4.18.2", "MIT"),
+		},
+		nil,
+	)
+
+	diff := Diff(previous, current, strings.Compare)
+
+	assert.True(t, diff.HasChanges())
+	assert.Equal(t, []ComponentInfo{{Name: "express", Version: "4.18.2", Purl: "pkg:npm/express@4.18.2", Licenses: []string{"MIT"}}}, diff.Added)
+	assert.Equal(t, []ComponentInfo{{Name: "left-pad", Version: "1.3.0", Purl: "pkg:npm/left-pad@1.3.0", Licenses: []string{"WTFPL"}}}, diff.Removed)
+	if assert.Len(t, diff.Upgraded, 1) {
+		assert.Equal(t, "lodash", diff.Upgraded[0].Name)
+		assert.Equal(t, "4.17.20", diff.Upgraded[0].PreviousVersion)
+		assert.Equal(t, "4.17.21", diff.Upgraded[0].Version)
+	}
+	if assert.Len(t, diff.LicenseChanges, 1) {
+		assert.Equal(t, "react", diff.LicenseChanges[0].Name)
+		assert.Equal(t, []string{"MIT"}, diff.LicenseChanges[0].PreviousLicenses)
+		assert.Equal(t, []string{"Apache-2.0"}, diff.LicenseChanges[0].Licenses)
+	}
+
+	assert.Empty(t, diff.Downgraded)
+	assert.False(t, Diff(current, current, strings.Compare).HasChanges())
+
+	downgrade := Diff(current, previous, strings.Compare)
+	assert.Empty(t, downgrade.Upgraded)
+	if assert.Len(t, downgrade.Downgraded, 1) {
+		assert.Equal(t, "4.17.21", downgrade.Downgraded[0].PreviousVersion)
+		assert.Equal(t, "4.17.20", downgrade.Downgraded[0].Version)
+	}
+}
+
+func TestSPDXConversion(t *testing.T) {
+	t.Parallel()
+	express := testComponent("express", "express", "4.18.2", "MIT")
+	express.Hashes = &[]cdx.Hash{{Algorithm: cdx.HashAlgoSHA1, Value: "abc"}}
+	custom := testComponent("custom", "custom", "1.0.0", "")
+	custom.Licenses = &cdx.Licenses{{License: &cdx.License{Name: "Custom License"}}}
+	bom := testBOM(
+		cdx.Component{BOMRef: "app", Type: cdx.ComponentTypeApplication, Name: "app", Version: "1.0.0"},
+		[]cdx.Component{express, custom},
+		map[string][]string{"app": {"express", "custom"}},
+	)
+	bom.SerialNumber = "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79"
+
+	document := ToSPDX(bom, "Project Piper")
+
+	assert.Equal(t, "SPDX-2.3", document.SPDXVersion)
+	assert.Equal(t, "app-1.0.0", document.Name)
+	assert.Equal(t, "https://spdx.org/spdxdocs/app-1.0.0-3e671687-395b-41f5-a30f-a58921a69b79", document.DocumentNamespace)
+	assert.Equal(t, []string{"SPDXRef-Package-app"}, document.DocumentDescribes)
+	require.Len(t, document.Packages, 3)
+	assert.Equal(t, "APPLICATION", document.Packages[0].PrimaryPackagePurpose)
+	assert.Equal(t, "MIT", document.Packages[1].LicenseDeclared)
+	assert.Equal(t, []SPDXChecksum{{Algorithm: "SHA1", ChecksumValue: "abc"}}, document.Packages[1].Checksums)
+	assert.Equal(t, []SPDXExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:npm/express@4.18.2"}}, document.Packages[1].ExternalRefs)
+	assert.Equal(t, "LicenseRef-Custom-License", document.Packages[2].LicenseDeclared)
+	assert.Equal(t, []SPDXExtractedLicenseInfo{{LicenseID: "LicenseRef-Custom-License", Name: "Custom License", ExtractedText: "Custom License"}}, document.HasExtractedLicensingInfos)
+	assert.Equal(t, []SPDXRelationship{
+		{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: "SPDXRef-Package-app"},
+		{SPDXElementID: "SPDXRef-Package-app", RelationshipType: "DEPENDS_ON", RelatedSPDXElement: "SPDXRef-Package-express"},
+		{SPDXElementID: "SPDXRef-Package-app", RelationshipType: "DEPENDS_ON", RelatedSPDXElement: "SPDXRef-Package-custom"},
+	}, document.Relationships)
+
+	converted := FromSPDX(document)
+
+	require.NotNil(t, converted.Metadata.Component)
+	assert.Equal(t, "app", converted.Metadata.Component.Name)
+	require.Len(t, *converted.Components, 2)
+	assert.Equal(t, "pkg:npm/express@4.18.2", (*converted.Components)[0].PackageURL)
+	assert.Equal(t, []string{"MIT"}, ComponentLicenses((*converted.Components)[0]))
+	assert.Equal(t, []cdx.Hash{{Algorithm: cdx.HashAlgoSHA1, Value: "abc"}}, *(*converted.Components)[0].Hashes)
+	assert.False(t, Diff(bom, converted, strings.Compare).HasChanges(), "conversion must not change components")
+	require.Len(t, *converted.Dependencies, 1)
+	assert.Len(t, *(*converted.Dependencies)[0].Dependencies, 2)
+}
+
+func TestReadWrite(t *testing.T) {
+	t.Parallel()
+	bom := testBOM(testComponent("app", "app", "1.0.0", ""), []cdx.Component{testComponent("a", "lodash", "4.17.21", "MIT")}, nil)
+
+	for _, path := range []string{"sbom/bom.xml", "sbom/bom.json"} {
+		utils := &mock.FilesMock{}
+		require.NoError(t, WriteBOM(bom, path, utils))
+		read, err := ReadBOM(path, utils)
+		require.NoError(t, err)
+		assert.Equal(t, "lodash", (*read.Components)[0].Name)
+	}
+
+	t.Run("invalid SPDX version", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("old.spdx.json", []byte(`{"spdxVersion": "SPDX-1.2"}`))
+		_, err := ReadSPDX("old.spdx.json", utils)
+		assert.EqualError(t, err, "unsupported SPDX version 'SPDX-1.2' of document 'old.spdx.json'")
+	})
+}
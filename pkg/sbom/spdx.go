@@ -0,0 +1,450 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+const (
+	spdxVersion     = "SPDX-2.3"
+	spdxDocumentID  = "SPDXRef-DOCUMENT"
+	spdxNoAssertion = "NOASSERTION"
+	spdxNone        = "NONE"
+)
+
+// SPDXDocument defines an SPDX 2.3 document in JSON format (https://spdx.github.io/spdx-spec/v2.3/)
+type SPDXDocument struct {
+	SPDXVersion                string                     `json:"spdxVersion"`
+	DataLicense                string                     `json:"dataLicense"`
+	SPDXID                     string                     `json:"SPDXID"`
+	Name                       string                     `json:"name"`
+	DocumentNamespace          string                     `json:"documentNamespace"`
+	CreationInfo               SPDXCreationInfo           `json:"creationInfo"`
+	DocumentDescribes          []string                   `json:"documentDescribes,omitempty"`
+	Packages                   []SPDXPackage              `json:"packages"`
+	Relationships              []SPDXRelationship         `json:"relationships,omitempty"`
+	HasExtractedLicensingInfos []SPDXExtractedLicenseInfo `json:"hasExtractedLicensingInfos,omitempty"`
+}
+
+// SPDXCreationInfo defines the creation information of an SPDX document
+type SPDXCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+// SPDXPackage defines a package of an SPDX document
+type SPDXPackage struct {
+	SPDXID                string            `json:"SPDXID"`
+	Name                  string            `json:"name"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	Supplier              string            `json:"supplier,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	Checksums             []SPDXChecksum    `json:"checksums,omitempty"`
+	LicenseConcluded      string            `json:"licenseConcluded"`
+	LicenseDeclared       string            `json:"licenseDeclared"`
+	CopyrightText         string            `json:"copyrightText"`
+	Description           string            `json:"description,omitempty"`
+	ExternalRefs          []SPDXExternalRef `json:"externalRefs,omitempty"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose,omitempty"`
+}
+
+// SPDXChecksum defines a checksum of an SPDX package
+type SPDXChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+// SPDXExternalRef defines an external reference of an SPDX package, e.g. the package URL
+type SPDXExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+// SPDXRelationship defines a relationship between two SPDX elements
+type SPDXRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// SPDXExtractedLicenseInfo defines a license which is not contained in the SPDX license list
+type SPDXExtractedLicenseInfo struct {
+	LicenseID     string `json:"licenseId"`
+	Name          string `json:"name,omitempty"`
+	ExtractedText string `json:"extractedText"`
+}
+
+var (
+	spdxIDCharacters   = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+	spdxHashAlgorithms = map[cdx.HashAlgorithm]string{
+		cdx.HashAlgoMD5:         "MD5",
+		cdx.HashAlgoSHA1:        "SHA1",
+		cdx.HashAlgoSHA256:      "SHA256",
+		cdx.HashAlgoSHA384:      "SHA384",
+		cdx.HashAlgoSHA512:      "SHA512",
+		cdx.HashAlgoSHA3_256:    "SHA3-256",
+		cdx.HashAlgoSHA3_512:    "SHA3-512",
+		cdx.HashAlgoBlake2b_256: "BLAKE2b-256",
+		cdx.HashAlgoBlake2b_384: "BLAKE2b-384",
+		cdx.HashAlgoBlake2b_512: "BLAKE2b-512",
+		cdx.HashAlgoBlake3:      "BLAKE3",
+	}
+)
+
+// ToSPDX converts a CycloneDX SBOM into an SPDX document.
+// The component described by the SBOM becomes the package described by the document, dependencies are converted into DEPENDS_ON relationships.
+func ToSPDX(bom *cdx.BOM, creator string) *SPDXDocument {
+	document := SPDXDocument{
+		SPDXVersion: spdxVersion,
+		DataLicense: "CC0-1.0",
+		SPDXID:      spdxDocumentID,
+		CreationInfo: SPDXCreationInfo{
+			Created:  time.Now().UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: " + creator},
+		},
+		Packages: []SPDXPackage{},
+	}
+
+	converter := spdxConverter{ids: map[string]string{}, usedIDs: map[string]bool{}, extractedLicenses: map[string]bool{}}
+	if bom.Metadata != nil {
+		if len(bom.Metadata.Timestamp) > 0 {
+			document.CreationInfo.Created = bom.Metadata.Timestamp
+		}
+		if bom.Metadata.Tools != nil {
+			for _, tool := range *bom.Metadata.Tools {
+				document.CreationInfo.Creators = append(document.CreationInfo.Creators, strings.TrimSpace(fmt.Sprintf("Tool: %v-%v", tool.Name, tool.Version)))
+			}
+		}
+		if bom.Metadata.Component != nil {
+			root := converter.addPackage(&document, *bom.Metadata.Component)
+			document.Name = componentName(*bom.Metadata.Component)
+			if len(bom.Metadata.Component.Version) > 0 {
+				document.Name += "-" + bom.Metadata.Component.Version
+			}
+			document.DocumentDescribes = []string{root}
+		}
+	}
+	if bom.Components != nil {
+		for _, component := range flattenComponents(*bom.Components) {
+			id := converter.addPackage(&document, component)
+			if len(document.DocumentDescribes) == 0 {
+				document.Relationships = append(document.Relationships, SPDXRelationship{SPDXElementID: spdxDocumentID, RelationshipType: "DESCRIBES", RelatedSPDXElement: id})
+			}
+		}
+	}
+	for _, id := range document.DocumentDescribes {
+		document.Relationships = append([]SPDXRelationship{{SPDXElementID: spdxDocumentID, RelationshipType: "DESCRIBES", RelatedSPDXElement: id}}, document.Relationships...)
+	}
+	if bom.Dependencies != nil {
+		for _, dependency := range *bom.Dependencies {
+			id, ok := converter.ids[dependency.Ref]
+			if !ok || dependency.Dependencies == nil {
+				continue
+			}
+			for _, dependsOn := range *dependency.Dependencies {
+				if relatedID, ok := converter.ids[dependsOn.Ref]; ok {
+					document.Relationships = append(document.Relationships, SPDXRelationship{SPDXElementID: id, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: relatedID})
+				}
+			}
+		}
+	}
+
+	if len(document.Name) == 0 {
+		document.Name = "sbom"
+	}
+	serial := strings.TrimPrefix(bom.SerialNumber, "urn:uuid:")
+	if len(serial) == 0 {
+		serial = uuid.New().String()
+	}
+	document.DocumentNamespace = fmt.Sprintf("https://spdx.org/spdxdocs/%v-%v", spdxIDCharacters.ReplaceAllString(document.Name, "-"), serial)
+	document.HasExtractedLicensingInfos = converter.extractedLicenseInfos
+	return &document
+}
+
+type spdxConverter struct {
+	// ids maps the bom-ref of a component to the SPDX ID of the package
+	ids                   map[string]string
+	usedIDs               map[string]bool
+	extractedLicenses     map[string]bool
+	extractedLicenseInfos []SPDXExtractedLicenseInfo
+}
+
+func (c *spdxConverter) addPackage(document *SPDXDocument, component cdx.Component) string {
+	id := c.spdxID(component)
+	if len(component.BOMRef) > 0 {
+		c.ids[component.BOMRef] = id
+	}
+	license := c.licenseExpression(component)
+	pkg := SPDXPackage{
+		SPDXID:                id,
+		Name:                  componentName(component),
+		VersionInfo:           component.Version,
+		DownloadLocation:      spdxNoAssertion,
+		LicenseConcluded:      spdxNoAssertion,
+		LicenseDeclared:       license,
+		CopyrightText:         spdxNoAssertion,
+		Description:           component.Description,
+		PrimaryPackagePurpose: spdxPackagePurpose(component.Type),
+	}
+	if len(component.Copyright) > 0 {
+		pkg.CopyrightText = component.Copyright
+	}
+	if component.Supplier != nil && len(component.Supplier.Name) > 0 {
+		pkg.Supplier = "Organization: " + component.Supplier.Name
+	}
+	if component.Hashes != nil {
+		for _, hash := range *component.Hashes {
+			if algorithm, ok := spdxHashAlgorithms[hash.Algorithm]; ok {
+				pkg.Checksums = append(pkg.Checksums, SPDXChecksum{Algorithm: algorithm, ChecksumValue: hash.Value})
+			}
+		}
+	}
+	if len(component.PackageURL) > 0 {
+		pkg.ExternalRefs = append(pkg.ExternalRefs, SPDXExternalRef{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: component.PackageURL})
+	}
+	if len(component.CPE) > 0 {
+		pkg.ExternalRefs = append(pkg.ExternalRefs, SPDXExternalRef{ReferenceCategory: "SECURITY", ReferenceType: "cpe23Type", ReferenceLocator: component.CPE})
+	}
+	document.Packages = append(document.Packages, pkg)
+	return id
+}
+
+func (c *spdxConverter) spdxID(component cdx.Component) string {
+	base := component.BOMRef
+	if len(base) == 0 {
+		base = ComponentKey(component)
+	}
+	base = "SPDXRef-Package-" + strings.Trim(spdxIDCharacters.ReplaceAllString(base, "-"), "-")
+	id := base
+	for i := 1; c.usedIDs[id]; i++ {
+		id = fmt.Sprintf("%v-%v", base, i)
+	}
+	c.usedIDs[id] = true
+	return id
+}
+
+// licenseExpression combines the licenses of the component into one SPDX license expression.
+// Licenses which are not identified by an SPDX license ID are added as extracted licensing information.
+func (c *spdxConverter) licenseExpression(component cdx.Component) string {
+	if component.Licenses == nil {
+		return spdxNoAssertion
+	}
+	expressions := []string{}
+	for _, choice := range *component.Licenses {
+		expression := choice.Expression
+		if choice.License != nil {
+			expression = choice.License.ID
+			if len(expression) == 0 && len(choice.License.Name) > 0 {
+				expression = "LicenseRef-" + strings.Trim(spdxIDCharacters.ReplaceAllString(choice.License.Name, "-"), "-")
+				if !c.extractedLicenses[expression] {
+					c.extractedLicenses[expression] = true
+					c.extractedLicenseInfos = append(c.extractedLicenseInfos, SPDXExtractedLicenseInfo{LicenseID: expression, Name: choice.License.Name, ExtractedText: choice.License.Name})
+				}
+			}
+		}
+		if len(expression) == 0 {
+			continue
+		}
+		if strings.Contains(expression, " ") && len(*component.Licenses) > 1 {
+			expression = "(" + expression + ")"
+		}
+		expressions = append(expressions, expression)
+	}
+	if len(expressions) == 0 {
+		return spdxNoAssertion
+	}
+	return strings.Join(expressions, " AND ")
+}
+
+func spdxPackagePurpose(componentType cdx.ComponentType) string {
+	switch componentType {
+	case cdx.ComponentTypeApplication:
+		return "APPLICATION"
+	case cdx.ComponentTypeFramework:
+		return "FRAMEWORK"
+	case cdx.ComponentTypeLibrary:
+		return "LIBRARY"
+	case cdx.ComponentTypeContainer:
+		return "CONTAINER"
+	case cdx.ComponentTypeOS:
+		return "OPERATING-SYSTEM"
+	case cdx.ComponentTypeDevice:
+		return "DEVICE"
+	case cdx.ComponentTypeFirmware:
+		return "FIRMWARE"
+	case cdx.ComponentTypeFile:
+		return "FILE"
+	}
+	return ""
+}
+
+// FromSPDX converts an SPDX document into a CycloneDX SBOM.
+// The package described by the document becomes the component described by the SBOM, DEPENDS_ON and DEPENDENCY_OF relationships are converted into dependencies.
+func FromSPDX(document *SPDXDocument) *cdx.BOM {
+	describes := append([]string{}, document.DocumentDescribes...)
+	for _, relationship := range document.Relationships {
+		if relationship.SPDXElementID == spdxDocumentID && relationship.RelationshipType == "DESCRIBES" && !piperutils.ContainsString(describes, relationship.RelatedSPDXElement) {
+			describes = append(describes, relationship.RelatedSPDXElement)
+		}
+	}
+
+	bom := cdx.NewBOM()
+	bom.Metadata = &cdx.Metadata{Timestamp: document.CreationInfo.Created}
+	tools := []cdx.Tool{}
+	for _, creator := range document.CreationInfo.Creators {
+		if tool := strings.TrimPrefix(creator, "Tool: "); tool != creator {
+			tools = append(tools, cdx.Tool{Name: strings.TrimSpace(tool)})
+		}
+	}
+	if len(tools) > 0 {
+		bom.Metadata.Tools = &tools
+	}
+
+	extractedLicenses := map[string]string{}
+	for _, license := range document.HasExtractedLicensingInfos {
+		extractedLicenses[license.LicenseID] = license.Name
+	}
+	components := []cdx.Component{}
+	for _, pkg := range document.Packages {
+		component := componentFromSPDXPackage(pkg, extractedLicenses)
+		// only a single described package can become the component described by the SBOM
+		if len(describes) == 1 && describes[0] == pkg.SPDXID {
+			component.Type = cdx.ComponentTypeApplication
+			bom.Metadata.Component = &component
+			continue
+		}
+		components = append(components, component)
+	}
+	bom.Components = &components
+
+	dependsOn := map[string][]string{}
+	refs := []string{}
+	addDependency := func(ref, dependency string) {
+		if _, ok := dependsOn[ref]; !ok {
+			refs = append(refs, ref)
+		}
+		if !piperutils.ContainsString(dependsOn[ref], dependency) {
+			dependsOn[ref] = append(dependsOn[ref], dependency)
+		}
+	}
+	for _, relationship := range document.Relationships {
+		switch relationship.RelationshipType {
+		case "DEPENDS_ON":
+			addDependency(relationship.SPDXElementID, relationship.RelatedSPDXElement)
+		case "DEPENDENCY_OF":
+			addDependency(relationship.RelatedSPDXElement, relationship.SPDXElementID)
+		}
+	}
+	if len(refs) > 0 {
+		dependencies := []cdx.Dependency{}
+		for _, ref := range refs {
+			nested := []cdx.Dependency{}
+			for _, dependency := range dependsOn[ref] {
+				nested = append(nested, cdx.Dependency{Ref: dependency})
+			}
+			dependencies = append(dependencies, cdx.Dependency{Ref: ref, Dependencies: &nested})
+		}
+		bom.Dependencies = &dependencies
+	}
+	return bom
+}
+
+func componentFromSPDXPackage(pkg SPDXPackage, extractedLicenses map[string]string) cdx.Component {
+	component := cdx.Component{
+		BOMRef:      pkg.SPDXID,
+		Type:        cdx.ComponentTypeLibrary,
+		Name:        pkg.Name,
+		Version:     pkg.VersionInfo,
+		Description: pkg.Description,
+	}
+	if pkg.CopyrightText != spdxNoAssertion && pkg.CopyrightText != spdxNone {
+		component.Copyright = pkg.CopyrightText
+	}
+	if supplier := strings.TrimPrefix(strings.TrimPrefix(pkg.Supplier, "Organization: "), "Person: "); len(supplier) > 0 && supplier != spdxNoAssertion {
+		component.Supplier = &cdx.OrganizationalEntity{Name: supplier}
+	}
+	for _, ref := range pkg.ExternalRefs {
+		switch ref.ReferenceType {
+		case "purl":
+			component.PackageURL = ref.ReferenceLocator
+		case "cpe23Type", "cpe22Type":
+			component.CPE = ref.ReferenceLocator
+		}
+	}
+	hashes := []cdx.Hash{}
+	for _, checksum := range pkg.Checksums {
+		for algorithm, spdxAlgorithm := range spdxHashAlgorithms {
+			if spdxAlgorithm == checksum.Algorithm {
+				hashes = append(hashes, cdx.Hash{Algorithm: algorithm, Value: checksum.ChecksumValue})
+			}
+		}
+	}
+	if len(hashes) > 0 {
+		component.Hashes = &hashes
+	}
+
+	license := pkg.LicenseConcluded
+	if license == spdxNoAssertion || license == spdxNone || len(license) == 0 {
+		license = pkg.LicenseDeclared
+	}
+	if license != spdxNoAssertion && license != spdxNone && len(license) > 0 {
+		choice := cdx.LicenseChoice{Expression: license}
+		if name, ok := extractedLicenses[license]; ok && len(name) > 0 {
+			choice = cdx.LicenseChoice{License: &cdx.License{Name: name}}
+		} else if !strings.ContainsAny(license, " ()") {
+			choice = cdx.LicenseChoice{License: &cdx.License{ID: license}}
+		}
+		component.Licenses = &cdx.Licenses{choice}
+	}
+	return component
+}
+
+// ReadSPDX reads an SPDX document in JSON format
+func ReadSPDX(path string, utils piperutils.FileUtils) (*SPDXDocument, error) {
+	content, err := utils.FileRead(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read SPDX document '%v'", path)
+	}
+	document := SPDXDocument{}
+	if err := json.Unmarshal(content, &document); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse SPDX document '%v'", path)
+	}
+	if !strings.HasPrefix(document.SPDXVersion, "SPDX-2.") {
+		return nil, fmt.Errorf("unsupported SPDX version '%v' of document '%v'", document.SPDXVersion, path)
+	}
+	return &document, nil
+}
+
+// WriteSPDX writes an SPDX document in JSON format
+func WriteSPDX(document *SPDXDocument, path string, utils piperutils.FileUtils) error {
+	content, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal SPDX document")
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := utils.MkdirAll(dir, 0777); err != nil {
+			return errors.Wrapf(err, "failed to create directory '%v'", dir)
+		}
+	}
+	if err := utils.FileWrite(path, content, 0666); err != nil {
+		return errors.Wrapf(err, "failed to write SPDX document '%v'", path)
+	}
+	return nil
+}
+
+// IsSPDXFile returns true if the file name indicates an SPDX document in JSON format
+func IsSPDXFile(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".spdx.json")
+}
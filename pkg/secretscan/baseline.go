@@ -0,0 +1,107 @@
+package secretscan
+
+import (
+	"regexp"
+
+	"github.com/bmatcuk/doublestar"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// Baseline contains the known findings as well as an allowlist of paths and secrets which are not reported at all
+type Baseline struct {
+	// Fingerprints of the known findings
+	Fingerprints []string  `json:"fingerprints,omitempty"`
+	Allowlist    Allowlist `json:"allowlist,omitempty"`
+
+	compiled []*regexp.Regexp
+}
+
+// Allowlist defines paths and secrets to be ignored
+type Allowlist struct {
+	// Paths are glob patterns of files which are not scanned
+	Paths []string `json:"paths,omitempty"`
+	// Regexes matching secrets which are ignored, e.g. example values
+	Regexes []string `json:"regexes,omitempty"`
+}
+
+// LoadBaseline reads the baseline file, an empty baseline is returned if the file does not exist
+func LoadBaseline(path string, utils piperutils.FileUtils) (*Baseline, error) {
+	baseline := Baseline{}
+	if exists, _ := utils.FileExists(path); !exists {
+		return &baseline, nil
+	}
+	content, err := utils.FileRead(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read baseline file '%v'", path)
+	}
+	if err := yaml.Unmarshal(content, &baseline); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse baseline file '%v'", path)
+	}
+	return &baseline, nil
+}
+
+// Apply marks the findings contained in the baseline
+func (b *Baseline) Apply(findings []Finding) []Finding {
+	for i := range findings {
+		findings[i].InBaseline = piperutils.ContainsString(b.Fingerprints, findings[i].Fingerprint)
+	}
+	return findings
+}
+
+// NewFindings returns the findings which are not contained in the baseline
+func NewFindings(findings []Finding) []Finding {
+	newFindings := []Finding{}
+	for _, finding := range findings {
+		if !finding.InBaseline {
+			newFindings = append(newFindings, finding)
+		}
+	}
+	return newFindings
+}
+
+// CreateBaseline creates a baseline containing all findings while keeping the allowlist of the previous baseline
+func CreateBaseline(previous *Baseline, findings []Finding) ([]byte, error) {
+	baseline := Baseline{Fingerprints: []string{}}
+	if previous != nil {
+		baseline.Allowlist = previous.Allowlist
+	}
+	for _, finding := range findings {
+		if !piperutils.ContainsString(baseline.Fingerprints, finding.Fingerprint) {
+			baseline.Fingerprints = append(baseline.Fingerprints, finding.Fingerprint)
+		}
+	}
+	return yaml.Marshal(baseline)
+}
+
+func (b *Baseline) compile() error {
+	b.compiled = []*regexp.Regexp{}
+	for _, expression := range b.Allowlist.Regexes {
+		compiled, err := regexp.Compile(expression)
+		if err != nil {
+			return errors.Wrapf(err, "invalid allowlist regex '%v'", expression)
+		}
+		b.compiled = append(b.compiled, compiled)
+	}
+	return nil
+}
+
+func (b *Baseline) allowsPath(path string) bool {
+	for _, pattern := range b.Allowlist.Paths {
+		if matched, _ := doublestar.PathMatch(pattern, path); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Baseline) allowsSecret(secret string) bool {
+	for _, expression := range b.compiled {
+		if expression.MatchString(secret) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package secretscan
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// ReportsDirectory defines the subfolder for the reports which are generated
+const ReportsDirectory = "secretScan"
+
+// CreateCustomReport creates a ScanReport of the findings to be used for uploading into various sinks
+func CreateCustomReport(scanTarget string, findings []Finding) reporting.ScanReport {
+	newFindings := NewFindings(findings)
+	scanReport := reporting.ScanReport{
+		ReportTitle: "Secret Scan Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Scanned", Details: scanTarget},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Total number of detected secrets", Details: fmt.Sprint(len(findings))},
+			{Description: "Number of new secrets", Details: fmt.Sprint(len(newFindings))},
+			{Description: "Number of secrets contained in the baseline", Details: fmt.Sprint(len(findings) - len(newFindings))},
+		},
+		SuccessfulScan: len(newFindings) == 0,
+		ReportTime:     time.Now(),
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No secrets detected",
+		Headers:       []string{"Rule", "File", "Line", "Commit", "Secret (redacted)", "Status", "Fingerprint"},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+	for _, finding := range findings {
+		status, style := "new", reporting.ColumnStyle(reporting.Red)
+		if finding.InBaseline {
+			status, style = "baseline", reporting.Yellow
+		}
+		row := reporting.ScanRow{}
+		row.AddColumn(finding.RuleID, 0)
+		row.AddColumn(finding.File, 0)
+		row.AddColumn(finding.Line, 0)
+		row.AddColumn(finding.Commit, 0)
+		row.AddColumn(finding.Redacted(), 0)
+		row.AddColumn(status, style)
+		row.AddColumn(finding.Fingerprint, 0)
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+	scanReport.DetailTable = detailTable
+
+	return scanReport
+}
+
+// WriteCustomReports creates an HTML and a JSON format file based on the findings of the scan
+func WriteCustomReports(scanReport reporting.ScanReport, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+
+	// ignore templating errors since template is in our hands and issues will be detected with the automated tests
+	htmlReport, _ := scanReport.ToHTML()
+	if err := utils.MkdirAll(ReportsDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to create report directory")
+	}
+	htmlReportPath := filepath.Join(ReportsDirectory, "piper_secret_scan_report.html")
+	if err := utils.FileWrite(htmlReportPath, htmlReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrap(err, "failed to write html report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Secret Scan Report", Target: htmlReportPath})
+
+	// JSON reports are used by step pipelineCreateSummary in order to e.g. prepare an issue creation in GitHub
+	// ignore JSON errors since structure is in our hands
+	jsonReport, _ := scanReport.ToJSON()
+	if exists, _ := utils.DirExists(reporting.StepReportDirectory); !exists {
+		if err := utils.MkdirAll(reporting.StepReportDirectory, 0777); err != nil {
+			return reportPaths, errors.Wrap(err, "failed to create step reporting directory")
+		}
+	}
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, "secretExecuteScan_secrets.json"), jsonReport, 0666); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to write json report")
+	}
+
+	return reportPaths, nil
+}
+
+// WriteBaseline writes a baseline containing all findings, which can be used to accept the current findings
+func WriteBaseline(previous *Baseline, findings []Finding, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+	content, err := CreateBaseline(previous, findings)
+	if err != nil {
+		return reportPaths, errors.Wrap(err, "failed to create baseline")
+	}
+	if err := utils.MkdirAll(ReportsDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to create report directory")
+	}
+	baselinePath := filepath.Join(ReportsDirectory, "piper_secret_baseline.yaml")
+	if err := utils.FileWrite(baselinePath, content, 0666); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to write baseline")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Secret Scan Baseline", Target: baselinePath})
+	return reportPaths, nil
+}
+
+// CreateSarifResultFile creates a SARIF result from the findings of the scan, secrets are redacted
+func CreateSarifResultFile(findings []Finding, rules []Rule) *format.SARIF {
+	log.Entry().Debug("Creating SARIF file for data transfer")
+	sarif := format.SARIF{
+		Schema:  "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []format.Runs{{Results: []format.Results{}}},
+	}
+
+	tool := format.Tool{Driver: format.Driver{
+		Name:           "Piper secret scan",
+		InformationUri: "https://www.project-piper.io/steps/secretExecuteScan/",
+	}}
+	descriptions := map[string]string{}
+	for _, rule := range rules {
+		descriptions[rule.ID] = rule.Description
+	}
+
+	collectedRules := []string{}
+	for _, finding := range findings {
+		unifiedAuditState := "new"
+		if finding.InBaseline {
+			unifiedAuditState = "baseline"
+		}
+		result := format.Results{
+			RuleID:  finding.RuleID,
+			Level:   "error",
+			Message: &format.Message{Text: fmt.Sprintf("%v detected: %v", finding.Description, finding.Redacted())},
+			Locations: []format.Location{{PhysicalLocation: format.PhysicalLocation{
+				ArtifactLocation: format.ArtifactLocation{URI: finding.File},
+				Region:           format.Region{StartLine: finding.Line},
+			}}},
+			PartialFingerprints: format.PartialFingerprints{PrimaryLocationLineHash: finding.Fingerprint},
+			Properties: &format.SarifProperties{
+				Audited:           finding.InBaseline,
+				ToolSeverity:      "high",
+				ToolState:         unifiedAuditState,
+				UnifiedAuditState: unifiedAuditState,
+			},
+		}
+		sarif.Runs[0].Results = append(sarif.Runs[0].Results, result)
+
+		// only create rule on new rule id
+		if !piperutils.ContainsString(collectedRules, finding.RuleID) {
+			collectedRules = append(collectedRules, finding.RuleID)
+			description := descriptions[finding.RuleID]
+			tool.Driver.Rules = append(tool.Driver.Rules, format.SarifRule{
+				ID:                   finding.RuleID,
+				Name:                 finding.RuleID,
+				ShortDescription:     &format.Message{Text: description},
+				FullDescription:      &format.Message{Text: fmt.Sprintf("%v hard-coded in the sources. Remove it and revoke it, since it is part of the repository history.", description)},
+				DefaultConfiguration: &format.DefaultConfiguration{Level: "error"},
+				Properties: &format.SarifRuleProperties{
+					Tags:      []string{"security", "secret"},
+					Precision: "high",
+				},
+			})
+		}
+	}
+	sarif.Runs[0].Tool = tool
+
+	conversion := new(format.Conversion)
+	conversion.Tool.Driver.Name = "Piper secret scan to SARIF converter"
+	conversion.Tool.Driver.InformationUri = "https://github.com/SAP/jenkins-library"
+	conversion.Invocation.ExecutionSuccessful = true
+	conversion.Invocation.Properties = &format.InvocationProperties{Platform: runtime.GOOS}
+	sarif.Runs[0].Conversion = conversion
+
+	return &sarif
+}
+
+// WriteSarifFile writes a JSON sarif format file for upload into e.g. GitHub code scanning
+func WriteSarifFile(sarif *format.SARIF, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+
+	sarifReport, err := json.Marshal(sarif)
+	if err != nil {
+		return reportPaths, errors.Wrap(err, "failed to marshall SARIF json file")
+	}
+	if err := utils.MkdirAll(ReportsDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to create report directory")
+	}
+	sarifReportPath := filepath.Join(ReportsDirectory, "piper_secret_scan.sarif")
+	if err := utils.FileWrite(sarifReportPath, sarifReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrap(err, "failed to write SARIF file")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Secret Scan SARIF file", Target: sarifReportPath})
+
+	return reportPaths, nil
+}
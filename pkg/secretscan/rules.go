@@ -0,0 +1,104 @@
+package secretscan
+
+import (
+	"math"
+	"regexp"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// Rule defines how a secret is detected
+type Rule struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	// Regex matching the secret, if the regex contains a capturing group the first group is considered to be the secret
+	Regex string `json:"regex"`
+	// Entropy is the minimal Shannon entropy of the secret, used to reduce false positives of generic rules
+	Entropy float64 `json:"entropy,omitempty"`
+
+	compiled *regexp.Regexp
+}
+
+// RuleSet contains the rules loaded from a rules file
+type RuleSet struct {
+	Rules []Rule `json:"rules"`
+}
+
+// DefaultRules returns the built-in rules
+func DefaultRules() []Rule {
+	return []Rule{
+		{ID: "aws-access-key-id", Description: "AWS access key ID", Regex: `\b((?:AKIA|ASIA|ABIA|ACCA|A3T[A-Z0-9])[A-Z0-9]{16})\b`},
+		{ID: "aws-secret-access-key", Description: "AWS secret access key", Regex: `(?i)aws.{0,20}(?:secret|key).{0,20}?['"]([0-9a-zA-Z/+]{40})['"]`, Entropy: 4.0},
+		{ID: "github-token", Description: "GitHub token", Regex: `\b(gh[pousr]_[0-9a-zA-Z]{36,251})\b`},
+		{ID: "github-fine-grained-token", Description: "GitHub fine-grained personal access token", Regex: `\b(github_pat_[0-9a-zA-Z_]{82})\b`},
+		{ID: "slack-token", Description: "Slack token", Regex: `\b(xox[abposr]-[0-9a-zA-Z-]{10,72})\b`},
+		{ID: "slack-webhook", Description: "Slack webhook URL", Regex: `(https://hooks\.slack\.com/services/T[0-9a-zA-Z_]{8,12}/B[0-9a-zA-Z_]{8,12}/[0-9a-zA-Z_]{24})`},
+		{ID: "private-key", Description: "Private key", Regex: `(-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----)`},
+		{ID: "generic-secret", Description: "Generic secret assignment", Regex: `(?i)(?:api[_-]?key|secret|token|passw(?:or)?d)["']?\s*[:=]\s*["']([0-9a-zA-Z\-_=+/.]{16,128})["']`, Entropy: 3.5},
+	}
+}
+
+// LoadRules reads additional rules from a YAML file
+func LoadRules(path string, utils piperutils.FileUtils) ([]Rule, error) {
+	content, err := utils.FileRead(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read rules file '%v'", path)
+	}
+	ruleSet := RuleSet{}
+	if err := yaml.Unmarshal(content, &ruleSet); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse rules file '%v'", path)
+	}
+	return ruleSet.Rules, nil
+}
+
+func (r *Rule) compile() error {
+	if r.compiled != nil {
+		return nil
+	}
+	if len(r.ID) == 0 {
+		return errors.New("rule without id")
+	}
+	compiled, err := regexp.Compile(r.Regex)
+	if err != nil {
+		return errors.Wrapf(err, "invalid regex of rule '%v'", r.ID)
+	}
+	r.compiled = compiled
+	return nil
+}
+
+// match returns the secrets detected by the rule within a line
+func (r *Rule) match(line string) []string {
+	secrets := []string{}
+	for _, submatches := range r.compiled.FindAllStringSubmatch(line, -1) {
+		secret := submatches[0]
+		if len(submatches) > 1 && len(submatches[1]) > 0 {
+			secret = submatches[1]
+		}
+		if r.Entropy > 0 && ShannonEntropy(secret) < r.Entropy {
+			continue
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets
+}
+
+// ShannonEntropy calculates the Shannon entropy in bits per character
+func ShannonEntropy(value string) float64 {
+	if len(value) == 0 {
+		return 0
+	}
+	frequencies := map[rune]float64{}
+	for _, char := range value {
+		frequencies[char]++
+	}
+	entropy := 0.0
+	length := float64(len([]rune(value)))
+	for _, count := range frequencies {
+		probability := count / length
+		entropy -= probability * math.Log2(probability)
+	}
+	return entropy
+}
@@ -0,0 +1,204 @@
+package secretscan
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// maxFileSize defines the size limit of files to be scanned, bigger files are skipped
+const maxFileSize = 1024 * 1024
+
+// Finding defines a secret detected by a rule
+type Finding struct {
+	RuleID      string `json:"ruleId"`
+	Description string `json:"description"`
+	File        string `json:"file"`
+	Line        int    `json:"line"`
+	Commit      string `json:"commit,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+	// InBaseline indicates that the finding is contained in the baseline and thus known
+	InBaseline bool `json:"inBaseline"`
+	secret     string
+}
+
+// Redacted returns the secret with most of its characters masked
+func (f Finding) Redacted() string {
+	if len(f.secret) <= 8 {
+		return "****"
+	}
+	return f.secret[:4] + "****"
+}
+
+// Scanner detects secrets based on a set of rules
+type Scanner struct {
+	rules     []Rule
+	allowlist *Baseline
+}
+
+// NewScanner creates a scanner using the provided rules, findings matching the allowlist of the baseline are ignored
+func NewScanner(rules []Rule, baseline *Baseline) (*Scanner, error) {
+	compiled := make([]Rule, len(rules))
+	for i := range rules {
+		compiled[i] = rules[i]
+		if err := compiled[i].compile(); err != nil {
+			return nil, err
+		}
+	}
+	if baseline == nil {
+		baseline = &Baseline{}
+	}
+	if err := baseline.compile(); err != nil {
+		return nil, err
+	}
+	return &Scanner{rules: compiled, allowlist: baseline}, nil
+}
+
+// ScanContent scans the content of a file line by line
+func (s *Scanner) ScanContent(file string, content []byte) []Finding {
+	findings := []Finding{}
+	if s.allowlist.allowsPath(file) || isBinary(content) {
+		return findings
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), maxFileSize)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		findings = append(findings, s.scanLine(file, lineNumber, scanner.Text())...)
+	}
+	return findings
+}
+
+func (s *Scanner) scanLine(file string, lineNumber int, line string) []Finding {
+	findings := []Finding{}
+	for i := range s.rules {
+		rule := &s.rules[i]
+		for _, secret := range rule.match(line) {
+			if s.allowlist.allowsSecret(secret) {
+				continue
+			}
+			// make sure the secret never shows up in the log
+			log.RegisterSecret(secret)
+			findings = append(findings, Finding{
+				RuleID:      rule.ID,
+				Description: rule.Description,
+				File:        file,
+				Line:        lineNumber,
+				Fingerprint: fingerprint(rule.ID, file, secret),
+				secret:      secret,
+			})
+		}
+	}
+	return findings
+}
+
+// ScanCommits scans the lines added by the commits. Secrets already detected in a newer commit are only reported once.
+func (s *Scanner) ScanCommits(commits object.CommitIter) ([]Finding, error) {
+	findings := []Finding{}
+	known := map[string]bool{}
+	err := commits.ForEach(func(commit *object.Commit) error {
+		commitFindings, err := s.scanCommit(commit)
+		if err != nil {
+			return errors.Wrapf(err, "failed to scan commit %v", commit.Hash.String())
+		}
+		for _, finding := range commitFindings {
+			if known[finding.Fingerprint] {
+				continue
+			}
+			known[finding.Fingerprint] = true
+			findings = append(findings, finding)
+		}
+		return nil
+	})
+	return findings, err
+}
+
+func (s *Scanner) scanCommit(commit *object.Commit) ([]Finding, error) {
+	findings := []Finding{}
+	if commit.NumParents() == 0 {
+		// initial commit: all files have been added
+		files, err := commit.Files()
+		if err != nil {
+			return nil, err
+		}
+		err = files.ForEach(func(file *object.File) error {
+			if file.Size > maxFileSize {
+				return nil
+			}
+			reader, err := file.Reader()
+			if err != nil {
+				return err
+			}
+			defer reader.Close()
+			content, err := io.ReadAll(reader)
+			if err != nil {
+				return err
+			}
+			findings = append(findings, withCommit(s.ScanContent(file.Name, content), commit)...)
+			return nil
+		})
+		return findings, err
+	}
+
+	parent, err := commit.Parent(0)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := parent.Patch(commit)
+	if err != nil {
+		return nil, err
+	}
+	for _, filePatch := range patch.FilePatches() {
+		_, to := filePatch.Files()
+		if to == nil || filePatch.IsBinary() || s.allowlist.allowsPath(to.Path()) {
+			continue
+		}
+		lineNumber := 0
+		for _, chunk := range filePatch.Chunks() {
+			lines := strings.SplitAfter(chunk.Content(), "\n")
+			for _, line := range lines {
+				if len(line) == 0 {
+					continue
+				}
+				switch chunk.Type() {
+				case diff.Equal:
+					lineNumber++
+				case diff.Add:
+					lineNumber++
+					findings = append(findings, withCommit(s.scanLine(to.Path(), lineNumber, strings.TrimSuffix(line, "\n")), commit)...)
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+func withCommit(findings []Finding, commit *object.Commit) []Finding {
+	for i := range findings {
+		findings[i].Commit = commit.Hash.String()
+	}
+	return findings
+}
+
+// fingerprint identifies a secret independent of its line, so that moving code does not create new findings
+func fingerprint(ruleID, file, secret string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(ruleID+":"+file+":"+secret)))
+}
+
+func isBinary(content []byte) bool {
+	sample := content
+	if len(sample) > 8000 {
+		sample = sample[:8000]
+	}
+	return bytes.IndexByte(sample, 0) != -1
+}
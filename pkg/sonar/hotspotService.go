@@ -0,0 +1,94 @@
+package sonar
+
+import (
+	"net/http"
+	"strconv"
+
+	sonargo "github.com/magicsong/sonargo/sonar"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// EndpointHotspotsSearch API endpoint for https://sonarcloud.io/web_api/api/hotspots/search
+const EndpointHotspotsSearch = "hotspots/search"
+
+const hotspotStatusToReview = "TO_REVIEW"
+
+// HotspotService ...
+type HotspotService struct {
+	Project     string
+	Branch      string
+	PullRequest string
+	apiClient   *Requester
+}
+
+// SearchHotspots ...
+func (service *HotspotService) SearchHotspots(options *HotspotsSearchOption) (*HotspotsSearchObject, *http.Response, error) {
+	request, err := service.apiClient.create("GET", EndpointHotspotsSearch, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	// use custom HTTP client to send request
+	response, err := service.apiClient.send(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	// reuse response verrification from sonargo
+	err = sonargo.CheckResponse(response)
+	if err != nil {
+		return nil, response, err
+	}
+	// decode JSON response
+	result := new(HotspotsSearchObject)
+	err = service.apiClient.decode(response, result)
+	if err != nil {
+		return nil, response, err
+	}
+	return result, response, nil
+}
+
+// GetHotspotsToReview returns all security hotspots of the project which have not been reviewed yet together with the components they belong to.
+// The hotspots are fetched page by page, SonarQube returns at most 10000 hotspots.
+func (service *HotspotService) GetHotspotsToReview() ([]*Hotspot, []*sonargo.Component, error) {
+	hotspots := []*Hotspot{}
+	components := []*sonargo.Component{}
+	for page := 1; ; page++ {
+		options := &HotspotsSearchOption{
+			ProjectKey: service.Project,
+			Status:     hotspotStatusToReview,
+			P:          strconv.Itoa(page),
+			Ps:         strconv.Itoa(searchPageSize),
+		}
+		// if PR, ignore branch name and consider PR branch name. If not PR, consider branch name
+		if len(service.PullRequest) > 0 {
+			options.PullRequest = service.PullRequest
+		} else if len(service.Branch) > 0 {
+			options.Branch = service.Branch
+		}
+		result, _, err := service.SearchHotspots(options)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to fetch page %v of the security hotspots", page)
+		}
+		hotspots = append(hotspots, result.Hotspots...)
+		components = append(components, result.Components...)
+		if result.Paging == nil || len(result.Hotspots) == 0 || len(hotspots) >= result.Paging.Total {
+			break
+		}
+		if page*searchPageSize >= maxSearchResults {
+			log.Entry().Warnf("only the first %v of %v security hotspots can be fetched from SonarQube", len(hotspots), result.Paging.Total)
+			break
+		}
+	}
+	return hotspots, components, nil
+}
+
+// NewHotspotService returns a new instance of a service for the hotspots API endpoint.
+func NewHotspotService(host, token, project, branch, pullRequest string, client Sender) *HotspotService {
+	return &HotspotService{
+		Project:     project,
+		Branch:      branch,
+		PullRequest: pullRequest,
+		apiClient:   NewAPIClient(host, token, client),
+	}
+}
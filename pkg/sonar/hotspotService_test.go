@@ -0,0 +1,83 @@
+//go:build unit
+// +build unit
+
+package sonar
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+)
+
+func TestHotspotService(t *testing.T) {
+	testURL := "https://example.org"
+	t.Run("success", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+		// add response handler
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointHotspotsSearch, func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "my_project", req.URL.Query().Get("projectKey"))
+			assert.Equal(t, "main", req.URL.Query().Get("branch"))
+			assert.Equal(t, "TO_REVIEW", req.URL.Query().Get("status"))
+			assert.Equal(t, "500", req.URL.Query().Get("ps"))
+			return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(responseHotspotsSearch, req.URL.Query().Get("p"))), nil
+		})
+		// create service instance
+		serviceUnderTest := NewHotspotService(testURL, mock.Anything, "my_project", "main", "", sender)
+		// test
+		hotspots, components, err := serviceUnderTest.GetHotspotsToReview()
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, hotspots, 1)
+		assert.Equal(t, "java:S2068", hotspots[0].RuleKey)
+		assert.Equal(t, "HIGH", hotspots[0].VulnerabilityProbability)
+		assert.Equal(t, 12, hotspots[0].TextRange.StartLine)
+		assert.Len(t, components, 1)
+		assert.Equal(t, 1, httpmock.GetTotalCallCount(), "unexpected number of requests")
+	})
+	t.Run("error", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+		// add response handler
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointHotspotsSearch, httpmock.NewStringResponder(http.StatusForbidden, `{"errors":[{"msg":"Insufficient privileges"}]}`))
+		// create service instance
+		serviceUnderTest := NewHotspotService(testURL, mock.Anything, "my_project", "", "", sender)
+		// test
+		_, _, err := serviceUnderTest.GetHotspotsToReview()
+		// assert
+		assert.Contains(t, err.Error(), "failed to fetch page 1 of the security hotspots")
+	})
+}
+
+const responseHotspotsSearch = `{
+  "paging": {"pageIndex": %v, "pageSize": 500, "total": 1},
+  "hotspots": [
+    {
+      "key": "AXhotspot1",
+      "component": "my_project:src/main/java/Config.java",
+      "project": "my_project",
+      "securityCategory": "auth",
+      "vulnerabilityProbability": "HIGH",
+      "status": "TO_REVIEW",
+      "line": 12,
+      "message": "Make sure this is not a hard-coded password.",
+      "ruleKey": "java:S2068",
+      "textRange": {"startLine": 12, "endLine": 12, "startOffset": 8, "endOffset": 24}
+    }
+  ],
+  "components": [
+    {"key": "my_project:src/main/java/Config.java", "qualifier": "FIL", "path": "src/main/java/Config.java"}
+  ]
+}`
@@ -0,0 +1,288 @@
+package sonar
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	sonargo "github.com/magicsong/sonargo/sonar"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// ReportsDirectory defines the subfolder for the issue reports which are generated
+const ReportsDirectory = "sonar"
+
+const securityHotspot = "SECURITY_HOTSPOT"
+
+// Findings contains the open issues and the security hotspots to review of a project
+type Findings struct {
+	Issues     []*sonargo.Issue
+	Hotspots   []*Hotspot
+	Components []*sonargo.Component
+}
+
+// componentPath returns the path of the file a component key refers to, e.g. "my_project:src/main.go" refers to "src/main.go"
+func (f *Findings) componentPath(key string) string {
+	for _, component := range f.Components {
+		if component.Key == key && len(component.Path) > 0 {
+			return component.Path
+		}
+	}
+	if index := strings.Index(key, ":"); index >= 0 {
+		return key[index+1:]
+	}
+	return key
+}
+
+// issueLevel maps the severity of an issue to a SARIF level
+func issueLevel(severity string) string {
+	switch severity {
+	case "BLOCKER", "CRITICAL":
+		return "error"
+	case "MAJOR":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// hotspotLevel maps the vulnerability probability of a security hotspot to a SARIF level
+func hotspotLevel(probability string) string {
+	switch probability {
+	case "HIGH":
+		return "error"
+	case "MEDIUM":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+func region(line int, textRange *sonargo.TextRange) format.Region {
+	if textRange == nil {
+		return format.Region{StartLine: line}
+	}
+	// SonarQube offsets are 0-based, SARIF columns are 1-based
+	return format.Region{
+		StartLine:   textRange.StartLine,
+		StartColumn: textRange.StartOffset + 1,
+		EndLine:     textRange.EndLine,
+		EndColumn:   textRange.EndOffset + 1,
+	}
+}
+
+// CreateSarifResultFile creates a SARIF result from the open issues and security hotspots of a project
+func CreateSarifResultFile(findings *Findings) *format.SARIF {
+	log.Entry().Debug("Creating SARIF file for data transfer")
+	sarif := format.SARIF{
+		Schema:  "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []format.Runs{{Results: []format.Results{}}},
+	}
+
+	tool := format.Tool{Driver: format.Driver{
+		Name:           "SonarQube",
+		InformationUri: "https://www.sonarsource.com/products/sonarqube/",
+	}}
+	collectedRules := []string{}
+	addRule := func(ruleID, level string, tags []string) {
+		// only create rule on new rule id
+		if piperutils.ContainsString(collectedRules, ruleID) {
+			return
+		}
+		collectedRules = append(collectedRules, ruleID)
+		tool.Driver.Rules = append(tool.Driver.Rules, format.SarifRule{
+			ID:                   ruleID,
+			Name:                 ruleID,
+			DefaultConfiguration: &format.DefaultConfiguration{Level: level},
+			Properties:           &format.SarifRuleProperties{Tags: tags},
+		})
+	}
+
+	for _, issue := range findings.Issues {
+		level := issueLevel(issue.Severity)
+		result := format.Results{
+			RuleID:  issue.Rule,
+			Level:   level,
+			Message: &format.Message{Text: issue.Message},
+			Locations: []format.Location{{PhysicalLocation: format.PhysicalLocation{
+				ArtifactLocation: format.ArtifactLocation{URI: findings.componentPath(issue.Component)},
+				Region:           region(issue.Line, issue.TextRange),
+			}}},
+			PartialFingerprints: format.PartialFingerprints{PrimaryLocationLineHash: issue.Hash},
+			Properties: &format.SarifProperties{
+				InstanceID:        issue.Key,
+				ToolSeverity:      issue.Severity,
+				ToolState:         issue.Status,
+				UnifiedAuditState: "new",
+			},
+		}
+		sarif.Runs[0].Results = append(sarif.Runs[0].Results, result)
+		addRule(issue.Rule, level, append([]string{strings.ToLower(issue.Type)}, issue.Tags...))
+	}
+
+	for _, hotspot := range findings.Hotspots {
+		level := hotspotLevel(hotspot.VulnerabilityProbability)
+		result := format.Results{
+			RuleID:  hotspot.RuleKey,
+			Level:   level,
+			Message: &format.Message{Text: hotspot.Message},
+			Locations: []format.Location{{PhysicalLocation: format.PhysicalLocation{
+				ArtifactLocation: format.ArtifactLocation{URI: findings.componentPath(hotspot.Component)},
+				Region:           region(hotspot.Line, hotspot.TextRange),
+			}}},
+			Properties: &format.SarifProperties{
+				InstanceID:        hotspot.Key,
+				ToolSeverity:      hotspot.VulnerabilityProbability,
+				ToolState:         hotspot.Status,
+				UnifiedAuditState: "new",
+			},
+		}
+		sarif.Runs[0].Results = append(sarif.Runs[0].Results, result)
+		addRule(hotspot.RuleKey, level, []string{"security", strings.ToLower(securityHotspot), hotspot.SecurityCategory})
+	}
+	sarif.Runs[0].Tool = tool
+
+	conversion := new(format.Conversion)
+	conversion.Tool.Driver.Name = "Piper SonarQube to SARIF converter"
+	conversion.Tool.Driver.InformationUri = "https://github.com/SAP/jenkins-library"
+	conversion.Invocation.ExecutionSuccessful = true
+	conversion.Invocation.Properties = &format.InvocationProperties{Platform: runtime.GOOS}
+	sarif.Runs[0].Conversion = conversion
+
+	return &sarif
+}
+
+// CreateCustomReport creates a ScanReport of the quality gate, the open issues and the security hotspots to be used for uploading into various sinks
+func CreateCustomReport(projectKey string, findings *Findings, qualityGate *QualityGate) reporting.ScanReport {
+	scanReport := reporting.ScanReport{
+		ReportTitle: "SonarQube Issue Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Project", Details: projectKey},
+		},
+		SuccessfulScan: qualityGate == nil || qualityGate.Status != QualityGateStatusError,
+		ReportTime:     time.Now(),
+	}
+	if qualityGate != nil {
+		scanReport.Overview = append(scanReport.Overview, reporting.OverviewRow{Description: "Quality gate", Details: qualityGate.Status})
+		for _, condition := range qualityGate.FailedConditions() {
+			scanReport.Overview = append(scanReport.Overview, reporting.OverviewRow{
+				Description: fmt.Sprintf("Failed condition %v", condition.Metric),
+				Details:     fmt.Sprintf("%v (threshold %v %v)", condition.ActualValue, condition.Comparator, condition.ErrorThreshold),
+				Style:       reporting.Red,
+			})
+		}
+	}
+	severities := map[string]int{}
+	for _, issue := range findings.Issues {
+		severities[issue.Severity]++
+	}
+	for _, severity := range []issueSeverity{blocker, critical, major, minor, info} {
+		scanReport.Overview = append(scanReport.Overview, reporting.OverviewRow{
+			Description: fmt.Sprintf("%v issues", severity.ToString()),
+			Details:     fmt.Sprint(severities[severity.ToString()]),
+		})
+	}
+	scanReport.Overview = append(scanReport.Overview, reporting.OverviewRow{Description: "Security hotspots to review", Details: fmt.Sprint(len(findings.Hotspots))})
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No open issues or security hotspots",
+		Headers:       []string{"Type", "Severity", "Rule", "Location", "Message"},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+	for _, issue := range findings.Issues {
+		row := reporting.ScanRow{}
+		row.AddColumn(issue.Type, 0)
+		row.AddColumn(issue.Severity, severityStyle(issueLevel(issue.Severity)))
+		row.AddColumn(issue.Rule, 0)
+		row.AddColumn(fmt.Sprintf("%v:%v", findings.componentPath(issue.Component), issue.Line), 0)
+		row.AddColumn(issue.Message, 0)
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+	for _, hotspot := range findings.Hotspots {
+		row := reporting.ScanRow{}
+		row.AddColumn(securityHotspot, 0)
+		row.AddColumn(hotspot.VulnerabilityProbability, severityStyle(hotspotLevel(hotspot.VulnerabilityProbability)))
+		row.AddColumn(hotspot.RuleKey, 0)
+		row.AddColumn(fmt.Sprintf("%v:%v", findings.componentPath(hotspot.Component), hotspot.Line), 0)
+		row.AddColumn(hotspot.Message, 0)
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+	scanReport.DetailTable = detailTable
+
+	return scanReport
+}
+
+func severityStyle(level string) reporting.ColumnStyle {
+	switch level {
+	case "error":
+		return reporting.Red
+	case "warning":
+		return reporting.Yellow
+	default:
+		return 0
+	}
+}
+
+// WriteSarifFile writes a JSON sarif format file for upload into e.g. GitHub code scanning
+func WriteSarifFile(sarif *format.SARIF, reportPath string, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+
+	sarifReport, err := json.Marshal(sarif)
+	if err != nil {
+		return reportPaths, errors.Wrap(err, "failed to marshall SARIF json file")
+	}
+	reportsDirectory := filepath.Join(reportPath, ReportsDirectory)
+	if err := utils.MkdirAll(reportsDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to create report directory")
+	}
+	sarifReportPath := filepath.Join(reportsDirectory, "piper_sonar_issues.sarif")
+	if err := utils.FileWrite(sarifReportPath, sarifReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrap(err, "failed to write SARIF file")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "SonarQube SARIF file", Target: sarifReportPath})
+
+	return reportPaths, nil
+}
+
+// WriteCustomReports creates an HTML and a JSON format file based on the issues of the project
+func WriteCustomReports(scanReport reporting.ScanReport, reportPath string, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+
+	// ignore templating errors since template is in our hands and issues will be detected with the automated tests
+	htmlReport, _ := scanReport.ToHTML()
+	reportsDirectory := filepath.Join(reportPath, ReportsDirectory)
+	if err := utils.MkdirAll(reportsDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to create report directory")
+	}
+	htmlReportPath := filepath.Join(reportsDirectory, "piper_sonar_report.html")
+	if err := utils.FileWrite(htmlReportPath, htmlReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrap(err, "failed to write html report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "SonarQube Issue Report", Target: htmlReportPath})
+
+	// JSON reports are used by step pipelineCreateSummary in order to e.g. prepare an issue creation in GitHub
+	// ignore JSON errors since structure is in our hands
+	jsonReport, _ := scanReport.ToJSON()
+	if exists, _ := utils.DirExists(reporting.StepReportDirectory); !exists {
+		if err := utils.MkdirAll(reporting.StepReportDirectory, 0777); err != nil {
+			return reportPaths, errors.Wrap(err, "failed to create step reporting directory")
+		}
+	}
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, "sonarExecuteScan_issues.json"), jsonReport, 0666); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to write json report")
+	}
+
+	return reportPaths, nil
+}
@@ -0,0 +1,87 @@
+//go:build unit
+// +build unit
+
+package sonar
+
+import (
+	"path/filepath"
+	"testing"
+
+	sonargo "github.com/magicsong/sonargo/sonar"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+func testFindings() *Findings {
+	return &Findings{
+		Issues: []*sonargo.Issue{
+			{Key: "issue1", Rule: "go:S1192", Severity: "CRITICAL", Type: "CODE_SMELL", Status: "OPEN", Component: "my_project:pkg/main.go", Line: 7, Message: "Define a constant", Hash: "abc123", TextRange: &sonargo.TextRange{StartLine: 7, EndLine: 7, StartOffset: 2, EndOffset: 10}},
+			{Key: "issue2", Rule: "go:S1192", Severity: "MINOR", Type: "CODE_SMELL", Status: "OPEN", Component: "my_project:cmd/run.go", Line: 3, Message: "Define a constant"},
+		},
+		Hotspots: []*Hotspot{
+			{Key: "hotspot1", RuleKey: "go:S2068", VulnerabilityProbability: "MEDIUM", SecurityCategory: "auth", Status: "TO_REVIEW", Component: "my_project:config.go", Line: 12, Message: "Hard-coded password"},
+		},
+		Components: []*sonargo.Component{
+			{Key: "my_project:pkg/main.go", Path: "pkg/main.go"},
+		},
+	}
+}
+
+func TestCreateSarifResultFile(t *testing.T) {
+	sarif := CreateSarifResultFile(testFindings())
+
+	assert.Equal(t, "2.1.0", sarif.Version)
+	assert.Equal(t, "SonarQube", sarif.Runs[0].Tool.Driver.Name)
+	assert.Len(t, sarif.Runs[0].Tool.Driver.Rules, 2)
+	results := sarif.Runs[0].Results
+	if assert.Len(t, results, 3) {
+		assert.Equal(t, "error", results[0].Level)
+		assert.Equal(t, "pkg/main.go", results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Equal(t, 3, results[0].Locations[0].PhysicalLocation.Region.StartColumn)
+		assert.Equal(t, "abc123", results[0].PartialFingerprints.PrimaryLocationLineHash)
+		assert.Equal(t, "note", results[1].Level)
+		assert.Equal(t, "cmd/run.go", results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Equal(t, 3, results[1].Locations[0].PhysicalLocation.Region.StartLine)
+		assert.Equal(t, "warning", results[2].Level)
+		assert.Equal(t, "go:S2068", results[2].RuleID)
+		assert.Equal(t, "config.go", results[2].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	}
+}
+
+func TestCreateCustomReport(t *testing.T) {
+	t.Run("failed quality gate", func(t *testing.T) {
+		gate := &QualityGate{Status: "ERROR", Conditions: []QualityGateCondition{{Metric: "new_coverage", Status: "ERROR", Comparator: "LT", ErrorThreshold: "80", ActualValue: "52.3"}}}
+
+		report := CreateCustomReport("my_project", testFindings(), gate)
+
+		assert.False(t, report.SuccessfulScan)
+		assert.Contains(t, report.Overview, reporting.OverviewRow{Description: "Quality gate", Details: "ERROR"})
+		assert.Contains(t, report.Overview, reporting.OverviewRow{Description: "Failed condition new_coverage", Details: "52.3 (threshold LT 80)", Style: reporting.Red})
+		assert.Contains(t, report.Overview, reporting.OverviewRow{Description: "CRITICAL issues", Details: "1"})
+		assert.Contains(t, report.Overview, reporting.OverviewRow{Description: "Security hotspots to review", Details: "1"})
+		assert.Len(t, report.DetailTable.Rows, 3)
+	})
+	t.Run("without quality gate", func(t *testing.T) {
+		report := CreateCustomReport("my_project", &Findings{}, nil)
+
+		assert.True(t, report.SuccessfulScan)
+		assert.Len(t, report.DetailTable.Rows, 0)
+	})
+}
+
+func TestWriteIssueReports(t *testing.T) {
+	utils := &mock.FilesMock{}
+	findings := testFindings()
+
+	sarifPaths, err := WriteSarifFile(CreateSarifResultFile(findings), "workspace", utils)
+	assert.NoError(t, err)
+	reportPaths, err := WriteCustomReports(CreateCustomReport("my_project", findings, nil), "workspace", utils)
+	assert.NoError(t, err)
+
+	assert.Equal(t, filepath.Join("workspace", "sonar", "piper_sonar_issues.sarif"), sarifPaths[0].Target)
+	assert.Equal(t, filepath.Join("workspace", "sonar", "piper_sonar_report.html"), reportPaths[0].Target)
+	assert.True(t, utils.HasWrittenFile(filepath.Join("workspace", "sonar", "piper_sonar_issues.sarif")))
+	assert.True(t, utils.HasWrittenFile(filepath.Join(reporting.StepReportDirectory, "sonarExecuteScan_issues.json")))
+}
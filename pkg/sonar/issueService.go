@@ -2,14 +2,24 @@ package sonar
 
 import (
 	"net/http"
+	"strconv"
 
 	sonargo "github.com/magicsong/sonargo/sonar"
 	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/log"
 )
 
 // EndpointIssuesSearch API endpoint for https://sonarcloud.io/web_api/api/issues/search
 const EndpointIssuesSearch = "issues/search"
 
+const (
+	// searchPageSize is the maximum page size accepted by the search endpoints
+	searchPageSize = 500
+	// maxSearchResults is the maximum number of results the search endpoints return, later pages are rejected
+	maxSearchResults = 10000
+)
+
 // IssueService ...
 type IssueService struct {
 	Organization string
@@ -44,12 +54,10 @@ func (service *IssueService) SearchIssues(options *IssuesSearchOption) (*sonargo
 	return result, response, nil
 }
 
-func (service *IssueService) getIssueCount(severity issueSeverity) (int, error) {
+func (service *IssueService) searchOptions() *IssuesSearchOption {
 	options := &IssuesSearchOption{
 		ComponentKeys: service.Project,
-		Severities:    severity.ToString(),
 		Resolved:      "false",
-		Ps:            "1",
 	}
 	if len(service.Organization) > 0 {
 		options.Organization = service.Organization
@@ -60,6 +68,13 @@ func (service *IssueService) getIssueCount(severity issueSeverity) (int, error)
 	} else if len(service.Branch) > 0 {
 		options.Branch = service.Branch
 	}
+	return options
+}
+
+func (service *IssueService) getIssueCount(severity issueSeverity) (int, error) {
+	options := service.searchOptions()
+	options.Severities = severity.ToString()
+	options.Ps = "1"
 	result, _, err := service.SearchIssues(options)
 	if err != nil {
 		return -1, errors.Wrapf(err, "failed to fetch the numer of '%s' issues", severity)
@@ -92,6 +107,36 @@ func (service *IssueService) GetNumberOfInfoIssues() (int, error) {
 	return service.getIssueCount(info)
 }
 
+// GetAllIssues returns all open issues of the project together with the components they belong to.
+// The issues are fetched page by page, SonarQube returns at most 10000 issues.
+func (service *IssueService) GetAllIssues() ([]*sonargo.Issue, []*sonargo.Component, error) {
+	issues := []*sonargo.Issue{}
+	components := []*sonargo.Component{}
+	for page := 1; ; page++ {
+		options := service.searchOptions()
+		options.P = strconv.Itoa(page)
+		options.Ps = strconv.Itoa(searchPageSize)
+		result, _, err := service.SearchIssues(options)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to fetch page %v of the issues", page)
+		}
+		issues = append(issues, result.Issues...)
+		components = append(components, result.Components...)
+		total := result.Total
+		if result.Paging != nil {
+			total = result.Paging.Total
+		}
+		if len(result.Issues) == 0 || len(issues) >= total {
+			break
+		}
+		if page*searchPageSize >= maxSearchResults {
+			log.Entry().Warnf("only the first %v of %v issues can be fetched from SonarQube", len(issues), total)
+			break
+		}
+	}
+	return issues, components, nil
+}
+
 // NewIssuesService returns a new instance of a service for the issues API endpoint.
 func NewIssuesService(host, token, project, organization, branch, pullRequest string, client Sender) *IssueService {
 	return &IssueService{
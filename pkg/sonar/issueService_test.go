@@ -4,7 +4,10 @@
 package sonar
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/jarcoal/httpmock"
@@ -73,6 +76,57 @@ func TestIssueService(t *testing.T) {
 	})
 }
 
+func TestGetAllIssues(t *testing.T) {
+	testURL := "https://example.org"
+	t.Run("paginated", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+		// add response handler serving 501 issues on two pages
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointIssuesSearch, func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "false", req.URL.Query().Get("resolved"))
+			assert.Equal(t, "500", req.URL.Query().Get("ps"))
+			page, _ := strconv.Atoi(req.URL.Query().Get("p"))
+			count := 500
+			if page == 2 {
+				count = 1
+			}
+			issues := []string{}
+			for i := 0; i < count; i++ {
+				issues = append(issues, fmt.Sprintf(`{"key": "issue-%v-%v", "rule": "go:S1234", "severity": "MAJOR", "component": "my_project:main.go"}`, page, i))
+			}
+			return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(`{"total": 501, "paging": {"pageIndex": %v, "pageSize": 500, "total": 501}, "issues": [%v], "components": [{"key": "my_project:main.go", "path": "main.go"}]}`, page, strings.Join(issues, ","))), nil
+		})
+		// create service instance
+		serviceUnderTest := NewIssuesService(testURL, mock.Anything, "my_project", mock.Anything, mock.Anything, mock.Anything, sender)
+		// test
+		issues, components, err := serviceUnderTest.GetAllIssues()
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, issues, 501)
+		assert.Equal(t, "issue-2-0", issues[500].Key)
+		assert.Len(t, components, 2)
+		assert.Equal(t, 2, httpmock.GetTotalCallCount(), "unexpected number of requests")
+	})
+	t.Run("error", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+		// add response handler
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointIssuesSearch, httpmock.NewStringResponder(http.StatusNotFound, responseIssueSearchError))
+		// create service instance
+		serviceUnderTest := NewIssuesService(testURL, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, sender)
+		// test
+		_, _, err := serviceUnderTest.GetAllIssues()
+		// assert
+		assert.Contains(t, fmt.Sprint(err), "failed to fetch page 1 of the issues")
+	})
+}
+
 const responseIssueSearchError = `{
   "errors": [
     {
@@ -0,0 +1,120 @@
+package sonar
+
+import (
+	"net/http"
+
+	sonargo "github.com/magicsong/sonargo/sonar"
+	"github.com/pkg/errors"
+)
+
+// EndpointQualityGatesProjectStatus API endpoint for https://sonarcloud.io/web_api/api/qualitygates/project_status
+const EndpointQualityGatesProjectStatus = "qualitygates/project_status"
+
+// QualityGateStatusError is the status of a failed quality gate
+const QualityGateStatusError = "ERROR"
+
+// QualityGate contains the status of the quality gate of a project
+type QualityGate struct {
+	Status     string                 `json:"status"`
+	Conditions []QualityGateCondition `json:"conditions,omitempty"`
+}
+
+// QualityGateCondition contains the status of a single condition of a quality gate
+type QualityGateCondition struct {
+	Metric         string `json:"metric"`
+	Status         string `json:"status"`
+	Comparator     string `json:"comparator,omitempty"`
+	ErrorThreshold string `json:"errorThreshold,omitempty"`
+	ActualValue    string `json:"actualValue,omitempty"`
+}
+
+// FailedConditions returns the conditions with status ERROR
+func (gate *QualityGate) FailedConditions() []QualityGateCondition {
+	failed := []QualityGateCondition{}
+	for _, condition := range gate.Conditions {
+		if condition.Status == QualityGateStatusError {
+			failed = append(failed, condition)
+		}
+	}
+	return failed
+}
+
+// QualityGateService ...
+type QualityGateService struct {
+	Project     string
+	Branch      string
+	PullRequest string
+	// AnalysisID optionally defines the analysis whose quality gate status is returned instead of the one of the latest analysis
+	AnalysisID string
+	apiClient  *Requester
+}
+
+// ProjectStatus ...
+func (service *QualityGateService) ProjectStatus(options *QualityGatesProjectStatusOption) (*sonargo.QualitygatesProjectStatusObject, *http.Response, error) {
+	request, err := service.apiClient.create("GET", EndpointQualityGatesProjectStatus, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	// use custom HTTP client to send request
+	response, err := service.apiClient.send(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	// reuse response verrification from sonargo
+	err = sonargo.CheckResponse(response)
+	if err != nil {
+		return nil, response, err
+	}
+	// decode JSON response
+	result := new(sonargo.QualitygatesProjectStatusObject)
+	err = service.apiClient.decode(response, result)
+	if err != nil {
+		return nil, response, err
+	}
+	return result, response, nil
+}
+
+// GetQualityGate returns the quality gate status of the analysis or, if no analysis is defined, of the latest analysis of the project.
+// The analysis ID prevents that the status of a concurrent analysis of the same branch is returned.
+func (service *QualityGateService) GetQualityGate() (*QualityGate, error) {
+	options := &QualityGatesProjectStatusOption{
+		ProjectKey: service.Project,
+	}
+	// the analysis identifies project and branch, the API does not allow to combine it with other parameters
+	if len(service.AnalysisID) > 0 {
+		options = &QualityGatesProjectStatusOption{AnalysisId: service.AnalysisID}
+	} else if len(service.PullRequest) > 0 {
+		// if PR, ignore branch name and consider PR branch name. If not PR, consider branch name
+		options.PullRequest = service.PullRequest
+	} else if len(service.Branch) > 0 {
+		options.Branch = service.Branch
+	}
+	result, _, err := service.ProjectStatus(options)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch the quality gate status")
+	}
+	if result.ProjectStatus == nil {
+		return nil, errors.New("failed to fetch the quality gate status: empty response")
+	}
+	gate := &QualityGate{Status: result.ProjectStatus.Status}
+	for _, condition := range result.ProjectStatus.Conditions {
+		gate.Conditions = append(gate.Conditions, QualityGateCondition{
+			Metric:         condition.MetricKey,
+			Status:         condition.Status,
+			Comparator:     condition.Comparator,
+			ErrorThreshold: condition.ErrorThreshold,
+			ActualValue:    condition.ActualValue,
+		})
+	}
+	return gate, nil
+}
+
+// NewQualityGateService returns a new instance of a service for the quality gate API endpoint.
+func NewQualityGateService(host, token, project, branch, pullRequest string, client Sender) *QualityGateService {
+	return &QualityGateService{
+		Project:     project,
+		Branch:      branch,
+		PullRequest: pullRequest,
+		apiClient:   NewAPIClient(host, token, client),
+	}
+}
@@ -0,0 +1,105 @@
+//go:build unit
+// +build unit
+
+package sonar
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+)
+
+func TestQualityGateService(t *testing.T) {
+	testURL := "https://example.org"
+	t.Run("success", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+		// add response handler
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointQualityGatesProjectStatus, func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "my_project", req.URL.Query().Get("projectKey"))
+			assert.Equal(t, "42", req.URL.Query().Get("pullRequest"))
+			assert.Empty(t, req.URL.Query().Get("branch"))
+			return httpmock.NewStringResponse(http.StatusOK, responseQualityGateError), nil
+		})
+		// create service instance
+		serviceUnderTest := NewQualityGateService(testURL, mock.Anything, "my_project", "feature", "42", sender)
+		// test
+		gate, err := serviceUnderTest.GetQualityGate()
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, QualityGateStatusError, gate.Status)
+		assert.Len(t, gate.Conditions, 2)
+		assert.Equal(t, []QualityGateCondition{{Metric: "new_coverage", Status: "ERROR", Comparator: "LT", ErrorThreshold: "80", ActualValue: "52.3"}}, gate.FailedConditions())
+		assert.Equal(t, 1, httpmock.GetTotalCallCount(), "unexpected number of requests")
+	})
+	t.Run("success with analysis", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+		// add response handler
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointQualityGatesProjectStatus, func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "AXe5y_mgcqEbAZBpFc0V", req.URL.Query().Get("analysisId"))
+			assert.Empty(t, req.URL.Query().Get("projectKey"))
+			assert.Empty(t, req.URL.Query().Get("pullRequest"))
+			return httpmock.NewStringResponse(http.StatusOK, responseQualityGateError), nil
+		})
+		// create service instance
+		serviceUnderTest := NewQualityGateService(testURL, mock.Anything, "my_project", "", "42", sender)
+		serviceUnderTest.AnalysisID = "AXe5y_mgcqEbAZBpFc0V"
+		// test
+		gate, err := serviceUnderTest.GetQualityGate()
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, QualityGateStatusError, gate.Status)
+	})
+	t.Run("error", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+		// add response handler
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointQualityGatesProjectStatus, httpmock.NewStringResponder(http.StatusNotFound, `{"errors":[{"msg":"Project 'my_project' not found"}]}`))
+		// create service instance
+		serviceUnderTest := NewQualityGateService(testURL, mock.Anything, "my_project", "", "", sender)
+		// test
+		gate, err := serviceUnderTest.GetQualityGate()
+		// assert
+		assert.Error(t, err)
+		assert.Nil(t, gate)
+		assert.Contains(t, err.Error(), "failed to fetch the quality gate status")
+	})
+}
+
+const responseQualityGateError = `{
+  "projectStatus": {
+    "status": "ERROR",
+    "conditions": [
+      {
+        "status": "ERROR",
+        "metricKey": "new_coverage",
+        "comparator": "LT",
+        "errorThreshold": "80",
+        "actualValue": "52.3"
+      },
+      {
+        "status": "OK",
+        "metricKey": "new_duplicated_lines_density",
+        "comparator": "GT",
+        "errorThreshold": "3",
+        "actualValue": "0.0"
+      }
+    ],
+    "ignoredConditions": false
+  }
+}`
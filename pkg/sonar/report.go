@@ -19,6 +19,7 @@ type ReportData struct {
 	NumberOfIssues Issues            `json:"numberOfIssues"`
 	Coverage       *SonarCoverage    `json:"coverage,omitempty"`
 	LinesOfCode    *SonarLinesOfCode `json:"linesOfCode,omitempty"`
+	QualityGate    *QualityGate      `json:"qualityGate,omitempty"`
 }
 
 // Issues ...
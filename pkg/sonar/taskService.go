@@ -22,7 +22,9 @@ const (
 
 // TaskService ...
 type TaskService struct {
-	TaskID       string
+	TaskID string
+	// AnalysisID is the ID of the analysis created by the task, available once the task has finished
+	AnalysisID   string
 	PollInterval time.Duration
 	apiClient    *Requester
 }
@@ -65,6 +67,7 @@ func (service *TaskService) HasFinished() (bool, error) {
 	if result.Task.Status == taskStatusPending || result.Task.Status == taskStatusProcessing {
 		return false, nil
 	}
+	service.AnalysisID = result.Task.AnalysisID
 	// for _, warning := range result.Task.Warnings {
 	// 	log.Entry().Warnf("Warnings during analysis: %s", warning)
 	// }
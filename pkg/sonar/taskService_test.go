@@ -101,6 +101,7 @@ func TestWaitForTask(t *testing.T) {
 		err := serviceUnderTest.WaitForTask()
 		// assert
 		assert.NoError(t, err)
+		assert.Equal(t, "AXe5y_mgcqEbAZBpFc0V", serviceUnderTest.AnalysisID)
 		assert.Equal(t, 3, httpmock.GetTotalCallCount(), "unexpected number of requests")
 	})
 	t.Run("failure", func(t *testing.T) {
@@ -1,5 +1,9 @@
 package sonar
 
+import (
+	sonargo "github.com/magicsong/sonargo/sonar"
+)
+
 // IssuesSearchOption is a copy from magicsong/sonargo plus the "internal" fields organization, branch and pullrequest.
 type IssuesSearchOption struct {
 	Branch       string `url:"branch,omitempty"`       // Description:"Branch key"
@@ -46,6 +50,47 @@ type MeasuresComponentOption struct {
 	MetricKeys       string `url:"metricKeys,omitempty"`       // Description:"Comma-separated list of metric keys",ExampleValue:"ncloc,complexity,violations"
 }
 
+// QualityGatesProjectStatusOption is a copy from magicsong/sonargo plus the "internal" fields branch and pullrequest.
+type QualityGatesProjectStatusOption struct {
+	Branch      string `url:"branch,omitempty"`      // Description:"Branch key"
+	PullRequest string `url:"pullRequest,omitempty"` // Description:"Pull request id"
+	// copied from https://github.com/magicsong/sonargo/blob/103eda7abc20bd192a064b6eb94ba26329e339f1/sonar/qualitygates_service.go#L276
+	AnalysisId string `url:"analysisId,omitempty"` // Description:"Analysis id",ExampleValue:"AU-TpxcA-iU5OvuD2FL1"
+	ProjectId  string `url:"projectId,omitempty"`  // Description:"Project id",ExampleValue:"AU-Tpxb--iU5OvuD2FLy"
+	ProjectKey string `url:"projectKey,omitempty"` // Description:"Project key",ExampleValue:"my_project"
+}
+
+// HotspotsSearchOption contains the parameters of https://sonarcloud.io/web_api/api/hotspots/search which is not covered by magicsong/sonargo.
+type HotspotsSearchOption struct {
+	Branch      string `url:"branch,omitempty"`      // Description:"Branch key"
+	PullRequest string `url:"pullRequest,omitempty"` // Description:"Pull request id"
+	ProjectKey  string `url:"projectKey,omitempty"`  // Description:"Key of the project",ExampleValue:"my_project"
+	Status      string `url:"status,omitempty"`      // Description:"If 'projectKey' is provided, only Security Hotspots with the specified status are returned.",ExampleValue:"TO_REVIEW"
+	P           string `url:"p,omitempty"`           // Description:"1-based page number",ExampleValue:"42"
+	Ps          string `url:"ps,omitempty"`          // Description:"Page size. Must be greater than 0.",ExampleValue:"20"
+}
+
+// HotspotsSearchObject is the response of https://sonarcloud.io/web_api/api/hotspots/search
+type HotspotsSearchObject struct {
+	Paging     *sonargo.Paging      `json:"paging,omitempty"`
+	Hotspots   []*Hotspot           `json:"hotspots,omitempty"`
+	Components []*sonargo.Component `json:"components,omitempty"`
+}
+
+// Hotspot is a security hotspot as returned by https://sonarcloud.io/web_api/api/hotspots/search
+type Hotspot struct {
+	Key                      string             `json:"key,omitempty"`
+	Component                string             `json:"component,omitempty"`
+	Project                  string             `json:"project,omitempty"`
+	SecurityCategory         string             `json:"securityCategory,omitempty"`
+	VulnerabilityProbability string             `json:"vulnerabilityProbability,omitempty"`
+	Status                   string             `json:"status,omitempty"`
+	Line                     int                `json:"line,omitempty"`
+	Message                  string             `json:"message,omitempty"`
+	RuleKey                  string             `json:"ruleKey,omitempty"`
+	TextRange                *sonargo.TextRange `json:"textRange,omitempty"`
+}
+
 type issueSeverity string
 
 func (s issueSeverity) ToString() string {
@@ -33,6 +33,7 @@ type BaseMetaData struct {
 	ErrorCategoryLabel   string `json:"custom13,omitempty"`
 	OrchestratorLabel    string `json:"custom14,omitempty"`
 	PiperCommitHashLabel string `json:"custom15,omitempty"`
+	AttemptsLabel        string `json:"custom16,omitempty"`
 }
 
 // baseMetaData object containing the labels for the base data
@@ -46,6 +47,7 @@ var baseMetaData BaseMetaData = BaseMetaData{
 	ErrorCategoryLabel:   "errorCategory",
 	OrchestratorLabel:    "orchestrator",
 	PiperCommitHashLabel: "piperCommitHash",
+	AttemptsLabel:        "attempts",
 }
 
 // CustomData object definition containing the data that can be set by a step, and it's mapping information
@@ -57,6 +59,7 @@ type CustomData struct {
 	ErrorCode       string `json:"e_12,omitempty"`
 	ErrorCategory   string `json:"e_13,omitempty"`
 	PiperCommitHash string `json:"e_15,omitempty"`
+	Attempts        string `json:"e_16,omitempty"`
 	Custom1Label    string `json:"custom26,omitempty"`
 	Custom2Label    string `json:"custom27,omitempty"`
 	Custom3Label    string `json:"custom28,omitempty"`
@@ -217,6 +217,7 @@ func TestSetData(t *testing.T) {
 					ErrorCategoryLabel:   "errorCategory",
 					OrchestratorLabel:    "orchestrator",
 					PiperCommitHashLabel: "piperCommitHash",
+					AttemptsLabel:        "attempts",
 				},
 				CustomData: CustomData{
 					Duration:        "100",
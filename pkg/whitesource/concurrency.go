@@ -0,0 +1,50 @@
+package whitesource
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// runConcurrently calls task for every index from 0 to count-1 with at most limit tasks running at the same time.
+// In contrast to a plain errgroup all tasks are executed, even if some of them fail, and the errors are aggregated.
+func runConcurrently(count, limit int, task func(index int) error) error {
+	if limit < 1 {
+		limit = 1
+	}
+	errs := make([]error, count)
+	group := errgroup.Group{}
+	group.SetLimit(limit)
+	for i := 0; i < count; i++ {
+		i := i // https://golang.org/doc/faq#closures_and_goroutines
+		group.Go(func() error {
+			// each task writes its own slot, therefore no synchronization is required
+			errs[i] = task(i)
+			return nil
+		})
+	}
+	_ = group.Wait()
+	return aggregateErrors(errs)
+}
+
+// aggregateErrors combines the given errors in their order, a single error is returned unchanged
+func aggregateErrors(errs []error) error {
+	failed := []error{}
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	switch len(failed) {
+	case 0:
+		return nil
+	case 1:
+		return failed[0]
+	}
+	messages := []string{}
+	for _, err := range failed {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Errorf("%v errors occurred: %v", len(failed), strings.Join(messages, "; "))
+}
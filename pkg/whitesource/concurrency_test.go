@@ -0,0 +1,47 @@
+//go:build unit
+// +build unit
+
+package whitesource
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunConcurrently(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var executed int32
+		err := runConcurrently(5, 2, func(index int) error {
+			atomic.AddInt32(&executed, 1)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int32(5), executed)
+	})
+
+	t.Run("single error", func(t *testing.T) {
+		err := runConcurrently(3, 0, func(index int) error {
+			if index == 1 {
+				return fmt.Errorf("task failed")
+			}
+			return nil
+		})
+		assert.EqualError(t, err, "task failed")
+	})
+
+	t.Run("multiple errors", func(t *testing.T) {
+		var executed int32
+		err := runConcurrently(4, 4, func(index int) error {
+			atomic.AddInt32(&executed, 1)
+			if index%2 == 0 {
+				return fmt.Errorf("task %v failed", index)
+			}
+			return nil
+		})
+		assert.EqualError(t, err, "2 errors occurred: task 0 failed; task 2 failed")
+		assert.Equal(t, int32(4), executed)
+	})
+}
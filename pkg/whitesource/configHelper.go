@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
@@ -13,6 +14,8 @@ import (
 	"github.com/pkg/errors"
 )
 
+var configFileNameRegex = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
 // ConfigOption defines a dedicated WhiteSource config which can be enforced if required
 type ConfigOption struct {
 	Name          string
@@ -47,6 +50,10 @@ func (s *ScanOptions) RewriteUAConfigurationFile(utils Utils, projectName string
 
 	now := time.Now().Format("20060102150405")
 	newConfigFilePath := fmt.Sprintf("%v.%v", s.ConfigFilePath, now)
+	if len(projectName) > 0 {
+		// modules of an MTA may be scanned at the same time, each one requires its own configuration file
+		newConfigFilePath = fmt.Sprintf("%v.%v.%v", s.ConfigFilePath, configFileNameRegex.ReplaceAllString(projectName, "_"), now)
+	}
 
 	var configContent bytes.Buffer
 	_, err = newConfig.Write(&configContent, properties.UTF8)
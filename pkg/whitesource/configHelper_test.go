@@ -5,6 +5,7 @@ package whitesource
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -44,6 +45,18 @@ func TestRewriteUAConfigurationFile(t *testing.T) {
 		assert.Contains(t, string(newUAConfig), "failErrorLevel = ALL")
 	})
 
+	t.Run("project specific file", func(t *testing.T) {
+		config := ScanOptions{
+			BuildTool:      "npm",
+			ConfigFilePath: "ua.props",
+		}
+		utilsMock := NewScanUtilsMock()
+
+		path, err := config.RewriteUAConfigurationFile(utilsMock, "@scope/my module")
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(path, "ua.props._scope_my_module."))
+	})
+
 	t.Run("error - write file", func(t *testing.T) {
 		config := ScanOptions{
 			BuildTool:      "npm",
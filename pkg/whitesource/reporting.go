@@ -546,6 +546,21 @@ func transformAlertsToVulnerabilities(scan *Scan, alerts *[]Alert) []cdx.Vulnera
 	return vulnerabilities
 }
 
+// CreateVEXStatements returns the VEX statements for the assessed alerts
+func CreateVEXStatements(assessedAlerts []Alert) []format.VEXStatement {
+	statements := []format.VEXStatement{}
+	for _, alert := range assessedAlerts {
+		if alert.Assessment != nil {
+			statements = append(statements, format.VEXStatement{
+				Vulnerability: alert.Vulnerability.Name,
+				Purl:          alert.Library.ToPackageUrl().ToString(),
+				Assessment:    *alert.Assessment,
+			})
+		}
+	}
+	return statements
+}
+
 func WriteCycloneSBOM(sbom []byte, utils piperutils.FileUtils) ([]piperutils.Path, error) {
 	paths := []piperutils.Path{}
 	if err := utils.MkdirAll(ReportsDirectory, 0777); err != nil {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -25,6 +26,12 @@ type Scan struct {
 	AgentName       string
 	AgentVersion    string
 	Coordinates     versioning.Coordinates
+	// RequestConcurrency limits the number of projects which are polled or whose reports are downloaded at the same time.
+	RequestConcurrency int
+	// mutex guards scannedProjects and scanTimes which are updated by concurrent module scans
+	mutex sync.Mutex
+	// configMutex serializes writing the Unified Agent configuration files of concurrent module scans
+	configMutex sync.Mutex
 }
 
 func (s *Scan) init() {
@@ -61,6 +68,8 @@ func (s *Scan) AppendScannedProjectVersion(projectName string) error {
 	if len(projectName) == len(s.versionSuffix()) {
 		return fmt.Errorf("projectName consists only of the product version")
 	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	s.init()
 	_, exists := s.scannedProjects[projectName]
 	if exists {
@@ -75,12 +84,16 @@ func (s *Scan) AppendScannedProjectVersion(projectName string) error {
 
 // ProjectByName returns a WhiteSource Project previously established via AppendScannedProject().
 func (s *Scan) ProjectByName(projectName string) (Project, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	project, exists := s.scannedProjects[projectName]
 	return project, exists
 }
 
 // ScannedProjects returns the WhiteSource projects that have been added via AppendScannedProject() as a slice.
 func (s *Scan) ScannedProjects() []Project {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	var projects []Project
 	for _, project := range s.scannedProjects {
 		projects = append(projects, project)
@@ -117,6 +130,8 @@ func (s *Scan) ScannedProjectTokens() []string {
 // ScanTime returns the time at which the respective WhiteSource Project was scanned, or the the
 // zero value of time.Time, if AppendScannedProject() was not called with that name.
 func (s *Scan) ScanTime(projectName string) time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	if s.scanTimes == nil {
 		return time.Time{}
 	}
@@ -132,11 +147,13 @@ type whitesource interface {
 // UpdateProjects pulls the current backend metadata for all WhiteSource projects in the product with
 // the given productToken, and updates all scanned projects with the obtained information.
 func (s *Scan) UpdateProjects(productToken string, sys whitesource) error {
-	s.init()
 	projects, err := sys.GetProjectsMetaInfo(productToken)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve WhiteSource projects meta info: %w", err)
 	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.init()
 
 	var projectsToUpdate []string
 	for projectName := range s.scannedProjects {
@@ -46,5 +46,8 @@ type ScanOptions struct {
 
 	InstallCommand string
 
+	// ScanConcurrency defines how many modules of an MTA are scanned with the Unified Agent at the same time.
+	ScanConcurrency int
+
 	Verbose bool
 }
@@ -12,13 +12,12 @@ type whitesourcePoller interface {
 
 // BlockUntilReportsAreReady polls the WhiteSource system for all projects known to the Scan and blocks
 // until their LastUpdateDate time stamp is from within the last 20 seconds.
+// Up to RequestConcurrency projects are polled at the same time.
 func (s *Scan) BlockUntilReportsAreReady(sys whitesourcePoller) error {
-	for _, project := range s.ScannedProjects() {
-		if err := pollProjectStatus(project.Token, s.ScanTime(project.Name), sys); err != nil {
-			return err
-		}
-	}
-	return nil
+	projects := s.ScannedProjects()
+	return runConcurrently(len(projects), s.RequestConcurrency, func(i int) error {
+		return pollProjectStatus(projects[i].Token, s.ScanTime(projects[i].Name), sys)
+	})
 }
 
 type pollOptions struct {
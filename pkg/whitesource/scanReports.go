@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
@@ -24,23 +26,54 @@ type scanUtils interface {
 	FileWrite(path string, content []byte, perm os.FileMode) error
 }
 
-// DownloadReports downloads a Project's risk and vulnerability reports
+// synchronizedScanUtils serializes the file operations of reports which are downloaded concurrently
+type synchronizedScanUtils struct {
+	utils scanUtils
+	mutex sync.Mutex
+}
+
+func (u *synchronizedScanUtils) MkdirAll(path string, perm os.FileMode) error {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	return u.utils.MkdirAll(path, perm)
+}
+
+func (u *synchronizedScanUtils) FileWrite(path string, content []byte, perm os.FileMode) error {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	return u.utils.FileWrite(path, content, perm)
+}
+
+// DownloadReports downloads a Project's risk and vulnerability reports.
+// The reports of up to RequestConcurrency projects are downloaded at the same time.
 func (s *Scan) DownloadReports(options ReportOptions, utils scanUtils, sys whitesource) ([]piperutils.Path, error) {
 	if err := utils.MkdirAll(options.ReportDirectory, os.ModePerm); err != nil {
 		return nil, err
 	}
 
-	var paths []piperutils.Path
-	for _, project := range s.scannedProjects {
-		vulnPath, err := downloadVulnerabilityReport(options, project, utils, sys)
+	projects := s.ScannedProjects()
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	syncUtils := &synchronizedScanUtils{utils: utils}
+	projectPaths := make([][]piperutils.Path, len(projects))
+	err := runConcurrently(len(projects), s.RequestConcurrency, func(i int) error {
+		vulnPath, err := downloadVulnerabilityReport(options, projects[i], syncUtils, sys)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		riskPath, err := downloadRiskReport(options, project, utils, sys)
+		riskPath, err := downloadRiskReport(options, projects[i], syncUtils, sys)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		paths = append(paths, *vulnPath, *riskPath)
+		projectPaths[i] = []piperutils.Path{*vulnPath, *riskPath}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []piperutils.Path
+	for _, p := range projectPaths {
+		paths = append(paths, p...)
 	}
 	return paths, nil
 }
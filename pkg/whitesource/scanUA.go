@@ -20,6 +20,14 @@ const jvmTarGz = "jvm.tar.gz"
 const jvmDir = "./jvm"
 const projectRegEx = `Project name: ([^,]*), URL: (.*)`
 
+// uaModule is a part of an MTA which is scanned with a dedicated Unified Agent execution
+type uaModule struct {
+	config       *ScanOptions
+	projectName  string
+	scanPath     string
+	errorMessage string
+}
+
 // ExecuteUAScan executes a scan with the Whitesource Unified Agent.
 func (s *Scan) ExecuteUAScan(config *ScanOptions, utils Utils) error {
 	s.AgentName = "WhiteSource Unified Agent"
@@ -28,13 +36,17 @@ func (s *Scan) ExecuteUAScan(config *ScanOptions, utils Utils) error {
 	}
 
 	log.Entry().Infof("Executing WhiteSource UA scan for MTA project")
+	modules := []uaModule{}
 	pomExists, _ := utils.FileExists("pom.xml")
 	if pomExists {
 		mavenConfig := *config
 		mavenConfig.BuildTool = "maven"
-		if err := s.ExecuteUAScanInPath(&mavenConfig, utils, config.ScanPath); err != nil {
-			return errors.Wrap(err, "failed to run scan for maven modules of mta")
-		}
+		modules = append(modules, uaModule{
+			config:       &mavenConfig,
+			projectName:  s.AggregateProjectName,
+			scanPath:     config.ScanPath,
+			errorMessage: "failed to run scan for maven modules of mta",
+		})
 	} else {
 		if pomFiles, _ := utils.Glob("**/pom.xml"); len(pomFiles) > 0 {
 			log.SetErrorCategory(log.ErrorCustom)
@@ -46,32 +58,87 @@ func (s *Scan) ExecuteUAScan(config *ScanOptions, utils Utils) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to find package.json files")
 	}
-	if len(packageJSONFiles) > 0 {
+	for _, packageJSONFile := range packageJSONFiles {
+		// we only need the path here
+		modulePath, _ := filepath.Split(packageJSONFile)
+		projectName, err := getProjectNameFromPackageJSON(packageJSONFile, utils)
+		if err != nil {
+			return errors.Wrapf(err, "failed retrieve project name")
+		}
 		npmConfig := *config
 		npmConfig.BuildTool = "npm"
-		for _, packageJSONFile := range packageJSONFiles {
-			// we only need the path here
-			modulePath, _ := filepath.Split(packageJSONFile)
-			projectName, err := getProjectNameFromPackageJSON(packageJSONFile, utils)
-			if err != nil {
-				return errors.Wrapf(err, "failed retrieve project name")
-			}
-			npmConfig.ProjectName = projectName
-			// ToDo: likely needs to be refactored, AggregateProjectName should only be available if we want to force aggregation?
-			s.AggregateProjectName = projectName
-			if err := s.ExecuteUAScanInPath(&npmConfig, utils, modulePath); err != nil {
-				return errors.Wrapf(err, "failed to run scan for npm module %v", modulePath)
+		npmConfig.ProjectName = projectName
+		modules = append(modules, uaModule{
+			config:       &npmConfig,
+			projectName:  projectName,
+			scanPath:     modulePath,
+			errorMessage: fmt.Sprintf("failed to run scan for npm module %v", modulePath),
+		})
+	}
+
+	if config.ScanConcurrency > 1 && len(modules) > 1 {
+		err = s.executeUAScansConcurrently(config, utils, modules)
+	} else {
+		for _, module := range modules {
+			if err = s.executeUAScanInPath(module.config, utils, module.scanPath, module.projectName); err != nil {
+				err = errors.Wrap(err, module.errorMessage)
+				break
 			}
 		}
 	}
+	if len(packageJSONFiles) > 0 {
+		// ToDo: likely needs to be refactored, AggregateProjectName should only be available if we want to force aggregation?
+		s.AggregateProjectName = modules[len(modules)-1].projectName
+	}
+	if err != nil {
+		return err
+	}
 
 	_ = removeJre(filepath.Join(jvmDir, "bin", "java"), utils)
 
 	return nil
 }
 
+// executeUAScansConcurrently scans the modules with up to ScanConcurrency Unified Agent executions at the same time.
+// The agent and the JRE are provided once upfront since all executions share them.
+func (s *Scan) executeUAScansConcurrently(config *ScanOptions, utils Utils, modules []uaModule) error {
+	if err := downloadAgent(config, utils); err != nil {
+		return err
+	}
+	javaPath, err := downloadJre(config, utils)
+	if err != nil {
+		return err
+	}
+	if err := s.readAgentVersion(config, utils, javaPath); err != nil {
+		return err
+	}
+
+	// every execution requires its own command runner in order to capture its output separately
+	moduleUtils := make([]Utils, len(modules))
+	for i := range modules {
+		moduleUtils[i] = utils.Clone()
+	}
+
+	log.Entry().Infof("Scanning %v modules with up to %v concurrent Unified Agent executions", len(modules), config.ScanConcurrency)
+	err = runConcurrently(len(modules), config.ScanConcurrency, func(i int) error {
+		if err := s.runUA(modules[i].config, moduleUtils[i], javaPath, modules[i].scanPath, modules[i].projectName); err != nil {
+			return errors.Wrap(err, modules[i].errorMessage)
+		}
+		return nil
+	})
+
+	if err := removeJre(javaPath, utils); err != nil {
+		log.Entry().Warning(err)
+	}
+	return err
+}
+
 // ExecuteUAScanInPath executes a scan with the Whitesource Unified Agent in a dedicated scanPath.
 func (s *Scan) ExecuteUAScanInPath(config *ScanOptions, utils Utils, scanPath string) error {
+	return s.executeUAScanInPath(config, utils, scanPath, s.AggregateProjectName)
+}
+
+func (s *Scan) executeUAScanInPath(config *ScanOptions, utils Utils, scanPath, projectName string) error {
 	// Download the unified agent jar file if one does not exist
 	err := downloadAgent(config, utils)
 	if err != nil {
@@ -84,25 +151,45 @@ func (s *Scan) ExecuteUAScanInPath(config *ScanOptions, utils Utils, scanPath st
 		return err
 	}
 
-	// Fetch version of UA
+	if err := s.readAgentVersion(config, utils, javaPath); err != nil {
+		return err
+	}
+
+	// ToDo: Check if Download of Docker/container image should be done here instead of in cmd/whitesourceExecuteScan.go
+
+	err = s.runUA(config, utils, javaPath, scanPath, projectName)
+
+	if err := removeJre(javaPath, utils); err != nil {
+		log.Entry().Warning(err)
+	}
+	return err
+}
+
+// readAgentVersion fetches the version of the Unified Agent
+func (s *Scan) readAgentVersion(config *ScanOptions, utils Utils, javaPath string) error {
 	versionBuffer := bytes.Buffer{}
 	utils.Stdout(&versionBuffer)
-	err = utils.RunExecutable(javaPath, "-jar", config.AgentFileName, "-v")
+	err := utils.RunExecutable(javaPath, "-jar", config.AgentFileName, "-v")
 	if err != nil {
 		return errors.Wrap(err, "Failed to determine UA version")
 	}
 	s.AgentVersion = strings.TrimSpace(versionBuffer.String())
 	log.Entry().Debugf("Read UA version %v from Stdout", s.AgentVersion)
 	utils.Stdout(log.Writer())
+	return nil
+}
 
-	// ToDo: Check if Download of Docker/container image should be done here instead of in cmd/whitesourceExecuteScan.go
-
+// runUA executes the Unified Agent for the given project in the scanPath
+func (s *Scan) runUA(config *ScanOptions, utils Utils, javaPath, scanPath, projectName string) error {
 	// ToDo: check if this is required
-	if err := s.AppendScannedProject(s.AggregateProjectName); err != nil {
+	if err := s.AppendScannedProject(projectName); err != nil {
 		return err
 	}
 
-	configPath, err := config.RewriteUAConfigurationFile(utils, s.AggregateProjectName)
+	// the configuration files of concurrent executions are written one after the other
+	s.configMutex.Lock()
+	configPath, err := config.RewriteUAConfigurationFile(utils, projectName)
+	s.configMutex.Unlock()
 	if err != nil {
 		return err
 	}
@@ -121,7 +208,7 @@ func (s *Scan) ExecuteUAScanInPath(config *ScanOptions, utils Utils, scanPath st
 
 	prErr, stdErr := io.Pipe()
 	trErr := io.TeeReader(prErr, os.Stderr)
-	utils.Stdout(stdErr)
+	utils.Stderr(stdErr)
 
 	var wg sync.WaitGroup
 	wg.Add(2)
@@ -137,14 +224,14 @@ func (s *Scan) ExecuteUAScanInPath(config *ScanOptions, utils Utils, scanPath st
 	}()
 	err = utils.RunExecutable(javaPath, "-jar", config.AgentFileName, "-d", scanPath, "-c", configPath, "-wss.url", config.AgentURL)
 
-	if err := removeJre(javaPath, utils); err != nil {
-		log.Entry().Warning(err)
-	}
+	// make sure the complete output has been parsed before the projects are evaluated
+	_ = stdOut.Close()
+	_ = stdErr.Close()
+	wg.Wait()
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
 
 	if err != nil {
-		if err := removeJre(javaPath, utils); err != nil {
-			log.Entry().Warning(err)
-		}
 		exitCode := utils.GetExitCode()
 		log.Entry().Infof("WhiteSource scan failed with exit code %v", exitCode)
 		evaluateExitCode(exitCode)
@@ -299,7 +386,12 @@ func parseForProjects(logLine string, scan *Scan) {
 	compile := regexp.MustCompile(projectRegEx)
 	values := compile.FindStringSubmatch(logLine)
 
-	if len(values) > 0 && scan.scannedProjects != nil && len(scan.scannedProjects[values[1]].Name) == 0 {
+	if len(values) == 0 {
+		return
+	}
+	scan.mutex.Lock()
+	defer scan.mutex.Unlock()
+	if scan.scannedProjects != nil && len(scan.scannedProjects[values[1]].Name) == 0 {
 		scan.scannedProjects[values[1]] = Project{Name: values[1]}
 	}
 
@@ -50,6 +50,66 @@ func TestExecuteUAScan(t *testing.T) {
 		assert.Contains(t, utilsMock.Calls[2].Params, ".")
 	})
 
+	t.Run("success - mta concurrently", func(t *testing.T) {
+		config := ScanOptions{
+			BuildTool:       "mta",
+			ProjectName:     "test-project",
+			ProductName:     "test-product",
+			ProductVersion:  "1",
+			AgentFileName:   "unified-agent.jar",
+			ScanConcurrency: 2,
+		}
+		utilsMock := NewScanUtilsMock()
+		utilsMock.AddFile("pom.xml", []byte("dummy"))
+		utilsMock.AddFile("app/package.json", []byte(`{"name":"app"}`))
+		utilsMock.AddFile("ui/package.json", []byte(`{"name":"ui"}`))
+		utilsMock.StdoutReturn = map[string]string{"-d ui/": "Project name: ui-detected - 1, URL: https://ws.service.url/ui\n"}
+		scan := newTestScan(&config)
+
+		err := scan.ExecuteUAScan(&config, utilsMock)
+
+		assert.NoError(t, err)
+		// agent version is determined once for all executions
+		if assert.Len(t, utilsMock.Calls, 2) {
+			assert.Contains(t, utilsMock.Calls[1].Params, "-v")
+		}
+		scanPaths := []string{}
+		if assert.Len(t, utilsMock.Clones, 3) {
+			for _, clone := range utilsMock.Clones {
+				if assert.Len(t, clone.Calls, 1) {
+					scanPaths = append(scanPaths, clone.Calls[0].Params[3])
+				}
+			}
+		}
+		assert.ElementsMatch(t, []string{".", "app/", "ui/"}, scanPaths)
+		assert.Equal(t, []string{"app - 1", "test-project - 1", "ui - 1", "ui-detected - 1"}, scan.ScannedProjectNames())
+		assert.Equal(t, "ui", scan.AggregateProjectName)
+	})
+
+	t.Run("error - mta concurrently", func(t *testing.T) {
+		config := ScanOptions{
+			BuildTool:       "mta",
+			ProjectName:     "test-project",
+			ProductName:     "test-product",
+			ProductVersion:  "1",
+			AgentFileName:   "unified-agent.jar",
+			ScanConcurrency: 3,
+		}
+		utilsMock := NewScanUtilsMock()
+		utilsMock.AddFile("app/package.json", []byte(`{"name":"app"}`))
+		utilsMock.AddFile("ui/package.json", []byte(`{"name":"ui"}`))
+		utilsMock.AddFile("web/package.json", []byte(`{"name":"web"}`))
+		utilsMock.ShouldFailOnCommand = map[string]error{"-d (app|web)/": fmt.Errorf("scan failed")}
+		scan := newTestScan(&config)
+
+		err := scan.ExecuteUAScan(&config, utilsMock)
+
+		assert.EqualError(t, err, "2 errors occurred: failed to run scan for npm module app/: failed to execute WhiteSource scan with exit code 0: scan failed; failed to run scan for npm module web/: failed to execute WhiteSource scan with exit code 0: scan failed")
+		// all modules are scanned despite of the failures
+		assert.Len(t, utilsMock.Clones, 3)
+		assert.Equal(t, []string{"app - 1", "ui - 1", "web - 1"}, scan.ScannedProjectNames())
+	})
+
 	t.Run("error - maven", func(t *testing.T) {
 		config := ScanOptions{
 			AgentDownloadURL: "https://download.ua.org/agent.jar",
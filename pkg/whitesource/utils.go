@@ -30,4 +30,7 @@ type Utils interface {
 
 	FindPackageJSONFiles(config *ScanOptions) ([]string, error)
 	InstallAllNPMDependencies(config *ScanOptions, packageJSONFiles []string) error
+
+	// Clone returns Utils with an own command runner, to be used for concurrent executions
+	Clone() Utils
 }
@@ -40,6 +40,7 @@ type ScanUtilsMock struct {
 	DownloadError       map[string]error
 	RemoveAllDirs       []string
 	RemoveAllError      map[string]error
+	Clones              []*ScanUtilsMock
 }
 
 // RemoveAll mimics os.RemoveAll().
@@ -85,6 +86,21 @@ func (m *ScanUtilsMock) DownloadFile(url, filename string, _ http.Header, _ []*h
 	return nil
 }
 
+// Clone returns a ScanUtilsMock with an own ExecMockRunner which shares the files and the configured command failures.
+// The clones are recorded in order to verify their calls.
+func (m *ScanUtilsMock) Clone() Utils {
+	clone := &ScanUtilsMock{
+		FilesMock: m.FilesMock,
+		ExecMockRunner: &mock.ExecMockRunner{
+			StdoutReturn:        m.StdoutReturn,
+			ShouldFailOnCommand: m.ShouldFailOnCommand,
+			ExitCode:            m.ExitCode,
+		},
+	}
+	m.Clones = append(m.Clones, clone)
+	return clone
+}
+
 // FileOpen mimics os.FileOpen() based on FilesMock OpenFile().
 func (m *ScanUtilsMock) FileOpen(name string, flag int, perm os.FileMode) (File, error) {
 	return m.OpenFile(name, flag, perm)
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/SAP/jenkins-library/pkg/format"
@@ -80,24 +81,16 @@ func (a Alert) Title() string {
 }
 
 func (a *Alert) ContainedIn(assessments *[]format.Assessment) (bool, error) {
-	localPurl := a.Library.ToPackageUrl().ToString()
-	for _, assessment := range *assessments {
-		if assessment.Vulnerability == a.Vulnerability.Name {
-			for _, purl := range assessment.Purls {
-				assessmentPurl, err := purl.ToPackageUrl()
-				assessmentPurlStr := assessmentPurl.ToString()
-				if err != nil {
-					log.SetErrorCategory(log.ErrorConfiguration)
-					log.Entry().WithError(err).Errorf("assessment from file ignored due to invalid packageUrl '%s'", purl)
-					return false, err
-				}
-				if assessmentPurlStr == localPurl {
-					log.Entry().Debugf("matching assessment %v on package %v detected for alert %v", assessment.Vulnerability, assessmentPurlStr, a.Vulnerability.Name)
-					a.Assessment = &assessment
-					return true, nil
-				}
-			}
-		}
+	assessment, err := format.FindAssessment(assessments, a.Library.ToPackageUrl(), a.Vulnerability.Name)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		log.Entry().WithError(err).Error("assessment from file ignored")
+		return false, err
+	}
+	if assessment != nil {
+		log.Entry().Debugf("matching assessment %v on package %v detected for alert %v", assessment.Vulnerability, a.Library.ToPackageUrl().ToString(), a.Vulnerability.Name)
+		a.Assessment = assessment
+		return true, nil
 	}
 	return false, nil
 }
@@ -331,6 +324,9 @@ type System struct {
 	userToken     string
 	maxRetries    int
 	retryInterval time.Duration
+	// backoffMutex guards backoffUntil which pauses all requests, also the ones sent concurrently, once the backend asks to back off
+	backoffMutex sync.Mutex
+	backoffUntil time.Time
 }
 
 // DateTimeLayout is the layout of the time format used by the WhiteSource API.
@@ -739,8 +735,7 @@ func (s *System) sendRequestAndDecodeJSONRecursive(req Request, result interface
 			if *count == 0 {
 				initial = true
 			}
-			log.Entry().Warnf("backend returned error 3000, retrying in %v", s.retryInterval)
-			time.Sleep(s.retryInterval)
+			log.Entry().Warnf("backend returned error 3000, retrying in %v", s.startBackoff())
 			*count = *count + 1
 			err = s.sendRequestAndDecodeJSONRecursive(req, result, count)
 			if err != nil {
@@ -749,6 +744,7 @@ func (s *System) sendRequestAndDecodeJSONRecursive(req Request, result interface
 				}
 				return err
 			}
+			return nil
 		}
 		return fmt.Errorf("invalid request, error code %v, message '%s'", errorResponse.ErrorCode, errorResponse.ErrorMessage)
 	}
@@ -762,7 +758,28 @@ func (s *System) sendRequestAndDecodeJSONRecursive(req Request, result interface
 	return nil
 }
 
+// startBackoff pauses all requests for the retry interval and returns the time until requests are sent again
+func (s *System) startBackoff() time.Duration {
+	s.backoffMutex.Lock()
+	defer s.backoffMutex.Unlock()
+	if until := time.Now().Add(s.retryInterval); until.After(s.backoffUntil) {
+		s.backoffUntil = until
+	}
+	return time.Until(s.backoffUntil)
+}
+
+// waitForBackoff blocks as long as requests are paused due to a back off requested by the backend
+func (s *System) waitForBackoff() {
+	s.backoffMutex.Lock()
+	wait := time.Until(s.backoffUntil)
+	s.backoffMutex.Unlock()
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
 func (s *System) sendRequest(req Request) ([]byte, error) {
+	s.waitForBackoff()
 	var responseBody []byte
 	if req.UserKey == "" {
 		req.UserKey = s.userToken
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"testing"
 	"time"
 
@@ -68,6 +69,60 @@ func TestGetProductsMetaInfo(t *testing.T) {
 	assert.Equal(t, []Product{{Name: "Test Product", Token: "test_product_token", CreationDate: "2020-01-01 00:00:00", LastUpdateDate: "2020-01-01 01:00:00"}}, products)
 }
 
+type whitesourceSequenceMockClient struct {
+	mutex         sync.Mutex
+	responseBodys []string
+	calls         int
+}
+
+func (c *whitesourceSequenceMockClient) SetOptions(opts piperhttp.ClientOptions) {
+	//noop
+}
+
+func (c *whitesourceSequenceMockClient) SendRequest(method, url string, body io.Reader, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	responseBody := c.responseBodys[len(c.responseBodys)-1]
+	if c.calls < len(c.responseBodys) {
+		responseBody = c.responseBodys[c.calls]
+	}
+	c.calls++
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader([]byte(responseBody)))}, nil
+}
+
+func TestSendRequestBackoff(t *testing.T) {
+	t.Parallel()
+	t.Run("success after retry", func(t *testing.T) {
+		myTestClient := whitesourceSequenceMockClient{responseBodys: []string{
+			`{"errorCode":3000,"errorMessage":"WhiteSource backend has a hickup"}`,
+			`{"productToken":"test_product_token"}`,
+		}}
+		sys := System{serverURL: "https://my.test.server", httpClient: &myTestClient, orgToken: "test_org_token", userToken: "test_user_token"}
+		sys.maxRetries = 3
+		sys.retryInterval = 1 * time.Microsecond
+
+		productToken, err := sys.CreateProduct("test_product_name")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "test_product_token", productToken)
+		assert.Equal(t, 2, myTestClient.calls)
+	})
+
+	t.Run("back off is shared", func(t *testing.T) {
+		myTestClient := whitesourceSequenceMockClient{responseBodys: []string{`{"productToken":"test_product_token"}`}}
+		sys := System{serverURL: "https://my.test.server", httpClient: &myTestClient, orgToken: "test_org_token", userToken: "test_user_token"}
+		sys.retryInterval = 50 * time.Millisecond
+
+		sys.startBackoff()
+		start := time.Now()
+		_, err := sys.CreateProduct("test_product_name")
+
+		assert.NoError(t, err)
+		// a request sent by another caller waits until the back off is over
+		assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	})
+}
+
 func TestCreateProduct(t *testing.T) {
 	t.Parallel()
 	t.Run("retryable error", func(t *testing.T) {